@@ -1,7 +1,10 @@
 package benchmark
 
 import (
+	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -10,13 +13,20 @@ import (
 )
 
 func Command(settings *conf.Settings) *cobra.Command {
-	return &cobra.Command{
+	var sources, batchSize int
+	cmd := &cobra.Command{
 		Use:   "benchmark",
 		Short: "Run BirdNET inference benchmark",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if sources > 0 {
+				return runBatchBenchmark(settings, sources, batchSize)
+			}
 			return runBenchmark(settings)
 		},
 	}
+	cmd.Flags().IntVar(&sources, "sources", 0, "Simulate this many concurrent audio sources and compare serial with batched inference")
+	cmd.Flags().IntVar(&batchSize, "batch", 8, "Batch size for the batched run of --sources")
+	return cmd
 }
 
 func runBenchmark(settings *conf.Settings) error {
@@ -130,6 +140,103 @@ func runInferenceBenchmark(settings *conf.Settings, results *benchmarkResults) e
 	return nil
 }
 
+// runBatchBenchmark measures the throughput of several sources analyzed one
+// chunk at a time against batched inference.
+func runBatchBenchmark(settings *conf.Settings, sources, batchSize int) error {
+	if batchSize < 2 || batchSize > conf.MaxBirdNETBatchSize {
+		return fmt.Errorf("batch size must be between 2 and %d", conf.MaxBirdNETBatchSize)
+	}
+
+	fmt.Printf("📡 Simulating %d sources submitting chunks back to back\n", sources)
+	fmt.Println("\n🐌 Serial inference (batch size 1):")
+	serial, err := runSourcesBenchmark(settings, sources, 1)
+	if err != nil {
+		return fmt.Errorf("❌ serial benchmark failed: %w", err)
+	}
+	fmt.Printf("\n🚀 Batched inference (batch size %d):\n", batchSize)
+	batched, err := runSourcesBenchmark(settings, sources, batchSize)
+	if err != nil {
+		return fmt.Errorf("❌ batched benchmark failed: %w", err)
+	}
+
+	fmt.Printf("Results:\n")
+	fmt.Printf("Method         Throughput              Avg batch  Avg queue wait\n")
+	fmt.Printf("─────────────  ──────────────────────  ─────────  ──────────────\n")
+	fmt.Printf("Serial         %6.2f chunks/sec        %5.1f      %9s\n", serial.chunksPerSecond, 1.0, "-")
+	fmt.Printf("Batched        %6.2f chunks/sec        %5.1f      %6d ms\n",
+		batched.chunksPerSecond, batched.stats.AvgBatchSize, batched.stats.AvgQueueWait.Milliseconds())
+	fmt.Printf("─────────────  ──────────────────────  ─────────  ──────────────\n")
+
+	if serial.chunksPerSecond > 0 {
+		gain := (batched.chunksPerSecond - serial.chunksPerSecond) / serial.chunksPerSecond * 100
+		fmt.Printf("\n🚀 Throughput gain with batching: %.1f%%\n", gain)
+	}
+
+	// Every source needs one chunk per 3 seconds without overlap
+	realtimeSources := batched.chunksPerSecond * 3
+	fmt.Printf("Sources analyzable in real time: about %.0f without overlap, %.0f with 1.5 s overlap\n",
+		realtimeSources, realtimeSources/2)
+	return nil
+}
+
+// sourcesBenchmarkResults holds the outcome of a multi-source run
+type sourcesBenchmarkResults struct {
+	chunksPerSecond float64
+	stats           birdnet.BatchStats
+}
+
+// runSourcesBenchmark runs sources goroutines predicting for 30 seconds, through
+// the batch scheduler when batchSize is above one. The batch size is set on a
+// copy so the shared settings keep their configured value.
+func runSourcesBenchmark(settings *conf.Settings, sources, batchSize int) (sourcesBenchmarkResults, error) {
+	var results sourcesBenchmarkResults
+	runSettings := *settings
+	runSettings.BirdNET.BatchSize = batchSize
+	bn, err := birdnet.NewBirdNET(&runSettings)
+	if err != nil {
+		return results, fmt.Errorf("failed to initialize BirdNET: %w", err)
+	}
+	defer bn.Delete()
+
+	duration := 30 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	defer cancel()
+
+	fmt.Println("⏳ Running benchmark for 30 seconds...")
+	var chunks atomic.Int64
+	var firstErr error
+	var errOnce sync.Once
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sourceID := fmt.Sprintf("source_%d", i)
+			chunk := [][]float32{make([]float32, 48000*3)}
+			for ctx.Err() == nil {
+				if _, err := bn.PredictSource(context.Background(), sourceID, chunk); err != nil {
+					errOnce.Do(func() { firstErr = err })
+					cancel()
+					return
+				}
+				if n := chunks.Add(1); n%10 == 0 {
+					fmt.Printf("\r🔄 Chunks: \033[1;36m%d\033[0m", n)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	fmt.Println()
+	if firstErr != nil {
+		return results, firstErr
+	}
+
+	results.chunksPerSecond = float64(chunks.Load()) / time.Since(start).Seconds()
+	results.stats = bn.BatchStats()
+	return results, nil
+}
+
 func getPerformanceRating(inferenceTime float64) (rating, description string) {
 	switch {
 	case inferenceTime > 3000:
@@ -14,6 +14,7 @@ import (
 	"github.com/tphakala/birdnet-go/cmd/rangefilter"
 	"github.com/tphakala/birdnet-go/cmd/realtime"
 	"github.com/tphakala/birdnet-go/cmd/support"
+	"github.com/tphakala/birdnet-go/cmd/worker"
 	"github.com/tphakala/birdnet-go/internal/conf"
 )
 
@@ -39,6 +40,7 @@ func RootCommand(settings *conf.Settings) *cobra.Command {
 	supportCmd := support.Command(settings)
 	benchmarkCmd := benchmark.Command(settings)
 	notifyCmd := notify.Command(settings)
+	workerCmd := worker.Command(settings)
 
 	subcommands := []*cobra.Command{
 		realtimeCmd,
@@ -48,6 +50,7 @@ func RootCommand(settings *conf.Settings) *cobra.Command {
 		supportCmd,
 		benchmarkCmd,
 		notifyCmd,
+		workerCmd,
 	}
 
 	rootCmd.AddCommand(subcommands...)
@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Command creates a command serving BirdNET inference to realtime mode on other machines.
+func Command(settings *conf.Settings) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "worker",
+		Short: "Serve BirdNET inference to remote capture sites",
+		Long: "Load the BirdNET model and analyze audio chunks sent by realtime mode on other machines " +
+			"over an authenticated HTTP API. Point birdnet.remote.workers of the capture sites at this host.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runWorker(settings)
+		},
+	}
+
+	cmd.Flags().StringVar(&settings.BirdNET.Worker.Listen, "listen", viper.GetString("birdnet.worker.listen"), "Listen address and port of the inference API")
+	cmd.Flags().StringVar(&settings.BirdNET.Worker.Token, "token", viper.GetString("birdnet.worker.token"), "Bearer token clients authenticate with")
+
+	return cmd
+}
+
+func runWorker(settings *conf.Settings) error {
+	if err := settings.BirdNET.Worker.Validate(); err != nil {
+		return err
+	}
+
+	bn, err := birdnet.NewBirdNET(settings)
+	if err != nil {
+		return fmt.Errorf("failed to initialize BirdNET: %w", err)
+	}
+	defer bn.Delete()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	return birdnet.ServeWorker(ctx, bn, &settings.BirdNET.Worker)
+}
@@ -10,6 +10,12 @@ export interface AlertCondition {
   value: string;
   duration_sec: number;
   sort_order: number;
+  /** Set on group rows: 'and' | 'or' | 'not' */
+  logic?: string;
+  /** Key of a group row, referenced by its members' parent_key */
+  node_key?: number;
+  /** Key of the enclosing group, 0 or absent for top-level conditions */
+  parent_key?: number;
 }
 
 export interface AlertAction {
@@ -32,6 +38,12 @@ export interface AlertRule {
   event_name: string;
   metric_name: string;
   cooldown_sec: number;
+  window_sec?: number;
+  active_from?: string;
+  active_until?: string;
+  /** Minutes an alert may stay unacknowledged before escalation, 0 disables */
+  escalate_after_min?: number;
+  escalate_target?: string;
   created_at: string;
   updated_at: string;
   conditions: AlertCondition[];
@@ -44,6 +56,11 @@ export interface AlertHistory {
   fired_at: string;
   event_data: string;
   actions: string;
+  /** 'firing' | 'acknowledged' | 'resolved', empty for one-off alerts */
+  status?: string;
+  acknowledged_at?: string;
+  escalated_at?: string;
+  resolved_at?: string;
   created_at: string;
   rule?: AlertRule;
 }
@@ -81,11 +98,13 @@ export interface ObjectTypeSchema {
   label: string;
   events?: EventSchema[];
   metrics?: MetricSchema[];
+  absence?: EventSchema[];
 }
 
 export interface AlertSchema {
   objectTypes: ObjectTypeSchema[];
   operators: OperatorSchema[];
+  groups?: OperatorSchema[];
 }
 
 // ---------- API response types ----------
@@ -102,6 +121,11 @@ interface ListHistoryResponse {
   offset: number;
 }
 
+interface ListOpenAlertsResponse {
+  alerts: AlertHistory[];
+  total: number;
+}
+
 interface ExportResponse {
   rules: AlertRule[];
   version: number;
@@ -201,6 +225,22 @@ export async function clearAlertHistory(): Promise<{ deleted: number }> {
   return api.delete<{ deleted: number }>(`${BASE}/history`);
 }
 
+/** Fetch alerts that are firing or acknowledged but not resolved. */
+export async function fetchOpenAlerts(): Promise<AlertHistory[]> {
+  const resp = await api.get<ListOpenAlertsResponse>(`${BASE}/open`);
+  return resp.alerts;
+}
+
+/** Acknowledge a firing alert, stopping its escalation. */
+export async function acknowledgeAlert(id: number): Promise<void> {
+  await api.post(`${BASE}/open/${id}/acknowledge`);
+}
+
+/** Manually resolve an open alert. */
+export async function resolveAlert(id: number): Promise<void> {
+  await api.post(`${BASE}/open/${id}/resolve`);
+}
+
 /** Fetch the alerting schema for the UI. */
 export async function fetchAlertSchema(): Promise<AlertSchema> {
   return api.get<AlertSchema>(`${BASE}/schema`);
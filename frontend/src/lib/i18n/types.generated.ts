@@ -1574,8 +1574,6 @@ export type TranslationKey =
   | 'settings.filters.errors.speciesLoadFailed'
   | 'settings.integration.birdweather.title'
   | 'settings.integration.birdweather.description'
-  | 'settings.integration.birdweather.ffmpegWarning.title'
-  | 'settings.integration.birdweather.ffmpegWarning.message'
   | 'settings.integration.birdweather.enable'
   | 'settings.integration.birdweather.token.label'
   | 'settings.integration.birdweather.token.helpText'
@@ -1769,6 +1767,14 @@ export type TranslationKey =
   | 'settings.audio.streams.diagnostics.restartCount'
   | 'settings.audio.streams.diagnostics.connection'
   | 'settings.audio.streams.diagnostics.stateErrorHistory'
+  | 'settings.audio.streams.diagnostics.inputHealth'
+  | 'settings.audio.streams.micHealth.healthy'
+  | 'settings.audio.streams.micHealth.issues.silence'
+  | 'settings.audio.streams.micHealth.issues.clipping'
+  | 'settings.audio.streams.micHealth.issues.dc_offset'
+  | 'settings.audio.streams.micHealth.issues.hum'
+  | 'settings.audio.streams.micHealth.issues.rolloff'
+  | 'settings.audio.streams.micHealth.issues.channel_imbalance'
   | 'settings.audio.streams.connectionStatus.stable'
   | 'settings.audio.streams.connectionStatus.degraded'
   | 'settings.audio.streams.connectionStatus.failed'
@@ -1800,6 +1806,17 @@ export type TranslationKey =
   | 'settings.audio.audioFilters.graph.flatResponse'
   | 'settings.audio.audioFilters.graph.frequency'
   | 'settings.audio.audioFilters.graph.gain'
+  | 'settings.audio.noiseReduction.title'
+  | 'settings.audio.noiseReduction.description'
+  | 'settings.audio.noiseReduction.enable'
+  | 'settings.audio.noiseReduction.enableHelp'
+  | 'settings.audio.noiseReduction.disabled'
+  | 'settings.audio.noiseReduction.strengthLabel'
+  | 'settings.audio.noiseReduction.strengthHelp'
+  | 'settings.audio.noiseReduction.thresholdLabel'
+  | 'settings.audio.noiseReduction.thresholdHelp'
+  | 'settings.audio.noiseReduction.clips'
+  | 'settings.audio.noiseReduction.clipsHelp'
   | 'settings.audio.soundLevelMonitoring.title'
   | 'settings.audio.soundLevelMonitoring.description'
   | 'settings.audio.soundLevelMonitoring.enable'
@@ -2244,12 +2261,19 @@ export type TranslationKey =
   | 'settings.alerts.actionLabels.disable'
   | 'settings.alerts.actionLabels.test'
   | 'settings.alerts.actionLabels.delete'
+  | 'settings.alerts.actionLabels.acknowledge'
+  | 'settings.alerts.actionLabels.resolve'
+  | 'settings.alerts.alertStatus.firing'
+  | 'settings.alerts.alertStatus.acknowledged'
+  | 'settings.alerts.alertStatus.resolved'
   | 'settings.alerts.status.enabled'
   | 'settings.alerts.status.disabled'
   | 'settings.alerts.status.deleted'
   | 'settings.alerts.status.testFired'
   | 'settings.alerts.status.defaultsReset'
   | 'settings.alerts.status.historyCleared'
+  | 'settings.alerts.status.acknowledged'
+  | 'settings.alerts.status.resolved'
   | 'settings.alerts.status.created'
   | 'settings.alerts.status.updated'
   | 'settings.alerts.status.exported'
@@ -2261,6 +2285,7 @@ export type TranslationKey =
   | 'settings.alerts.errors.testFailed'
   | 'settings.alerts.errors.resetFailed'
   | 'settings.alerts.errors.clearHistoryFailed'
+  | 'settings.alerts.errors.alertUpdateFailed'
   | 'settings.alerts.errors.saveFailed'
   | 'settings.alerts.errors.exportFailed'
   | 'settings.alerts.errors.importFailed'
@@ -2277,21 +2302,36 @@ export type TranslationKey =
   | 'settings.alerts.editor.triggerType'
   | 'settings.alerts.editor.triggerEvent'
   | 'settings.alerts.editor.triggerMetric'
+  | 'settings.alerts.editor.triggerAbsence'
   | 'settings.alerts.editor.event'
   | 'settings.alerts.editor.metric'
+  | 'settings.alerts.editor.absenceEvent'
+  | 'settings.alerts.editor.absenceWindowMinutes'
+  | 'settings.alerts.editor.activeFrom'
+  | 'settings.alerts.editor.activeUntil'
+  | 'settings.alerts.editor.activeHoursPlaceholder'
   | 'settings.alerts.editor.conditionsSection'
   | 'settings.alerts.editor.property'
   | 'settings.alerts.editor.operator'
   | 'settings.alerts.editor.value'
   | 'settings.alerts.editor.valuePlaceholder'
+  | 'settings.alerts.editor.valueListPlaceholder'
+  | 'settings.alerts.editor.valueRegexPlaceholder'
+  | 'settings.alerts.editor.valueTimeRangePlaceholder'
   | 'settings.alerts.editor.duration'
   | 'settings.alerts.editor.addCondition'
   | 'settings.alerts.editor.removeCondition'
+  | 'settings.alerts.editor.addGroup'
+  | 'settings.alerts.editor.removeGroup'
+  | 'settings.alerts.editor.groupLogic'
   | 'settings.alerts.editor.actionsSection'
   | 'settings.alerts.editor.actionBell'
   | 'settings.alerts.editor.actionPush'
   | 'settings.alerts.editor.optionsSection'
   | 'settings.alerts.editor.cooldownMinutes'
+  | 'settings.alerts.editor.escalateAfterMinutes'
+  | 'settings.alerts.editor.escalateTarget'
+  | 'settings.alerts.editor.escalateTargetPlaceholder'
   | 'settings.alerts.editor.templateTitle'
   | 'settings.alerts.editor.templateTitlePlaceholder'
   | 'settings.alerts.editor.templateMessage'
@@ -59,6 +59,30 @@ export interface MainSettings {
   };
 }
 
+export interface AdaptiveOverlapSettings {
+  enabled: boolean; // lower the overlap of a source while inference cannot keep up
+  minOverlap: number; // lowest overlap in seconds to fall back to
+}
+
+// RemoteWorker matches backend RemoteWorker - an inference worker realtime mode sends chunks to
+export interface RemoteWorker {
+  url: string; // base URL of the worker, e.g. http://server:8090
+  token: string; // bearer token the worker requires
+}
+
+export interface RemoteInferenceSettings {
+  workers: RemoteWorker[]; // empty to analyze locally
+  timeout: string; // Duration string, e.g. "5s"
+  fallback: boolean; // analyze locally when no worker answers
+}
+
+export interface InferenceWorkerSettings {
+  listen: string; // address the worker command listens on
+  token: string; // bearer token clients authenticate with
+  certFile: string; // TLS certificate, empty for plain HTTP
+  keyFile: string; // TLS private key
+}
+
 export interface BirdNetSettings {
   modelPath: string | null;
   labelPath: string | null;
@@ -67,6 +91,10 @@ export interface BirdNetSettings {
   overlap: number; // 0.0-2.9
   locale: string;
   threads: number;
+  batchSize?: number; // chunks of different sources per model invocation, 1 disables batching
+  adaptiveOverlap?: AdaptiveOverlapSettings;
+  remote?: RemoteInferenceSettings;
+  worker?: InferenceWorkerSettings;
   latitude: number;
   longitude: number;
   rangeFilter: RangeFilterSettings;
@@ -116,8 +144,12 @@ export interface AudioSettings {
   streamTransport?: string;
   export: ExportSettings;
   soundLevel: SoundLevelSettings;
+  noiseReduction?: NoiseReductionSettings;
+  archive?: ArchiveSettings;
   useAudioCore?: boolean;
   equalizer: EqualizerSettings;
+  sourceEqualizer?: EqualizerSettings; // Sound card equalizer override, omitted to inherit the global equalizer
+  channels?: ChannelSettings; // Sound card channel layout, omitted to mix to mono
 }
 
 export interface SoundLevelSettings {
@@ -125,6 +157,32 @@ export interface SoundLevelSettings {
   interval: number;
 }
 
+// NoiseReductionSettings matches backend NoiseReductionSettings
+export interface NoiseReductionSettings {
+  enabled: boolean; // reduce stationary noise before analysis
+  strength: number; // share of gated noise removed, 0.0-1.0
+  threshold: number; // level in dB above the noise profile that passes the gate
+  clips: boolean; // also reduce noise in exported clips
+}
+
+// ArchiveSettings matches backend ArchiveSettings
+export interface ArchiveSettings {
+  enabled: boolean; // record all captured audio into rolling FLAC segments
+  path: string; // archive directory, separate from the clip export path
+  segmentLength: number; // segment length in seconds
+  maxAge: string; // e.g. "7d", empty for no age limit
+  maxSize: string; // e.g. "20GB", empty for no size limit
+  checkInterval: number; // retention check interval in minutes
+}
+
+// ChannelSettings matches backend ChannelSettings
+export interface ChannelSettings {
+  mode?: 'mix' | 'split' | 'select'; // mix to mono, analyze every channel, or analyze one channel
+  count?: number; // number of captured channels for split and select modes
+  channel?: number; // 1-based channel analyzed in select mode
+  names?: string[]; // optional channel names used for split sub-sources
+}
+
 // Stream type constants
 export const StreamTypes = {
   RTSP: 'rtsp',
@@ -142,6 +200,9 @@ export interface StreamConfig {
   url: string; // Required: stream URL
   type: StreamType; // Stream type: rtsp, http, hls, rtmp, udp
   transport?: 'tcp' | 'udp'; // Transport protocol (for RTSP/RTMP only)
+  backupUrls?: string[]; // Alternative URLs tried in order when the primary URL fails
+  equalizer?: EqualizerSettings; // Per-stream equalizer override, omitted to inherit the global equalizer
+  channels?: ChannelSettings; // Per-stream channel layout, omitted to mix to mono
 }
 
 // RTSPHealthSettings matches backend RTSPHealthSettings
@@ -157,6 +218,20 @@ export interface RTSPSettings {
   ffmpegParameters?: string[]; // optional custom FFmpeg parameters
 }
 
+// IngestDevice matches backend IngestDevice - a remote recorder pushing audio
+export interface IngestDevice {
+  id: string; // device ID used in the ingest URL
+  name: string; // display name of the source
+  token: string; // bearer token the device authenticates with
+}
+
+// IngestSettings matches backend IngestSettings
+export interface IngestSettings {
+  enabled: boolean;
+  latency: number; // seconds pushed audio is held for late chunks (default: 10)
+  devices: IngestDevice[];
+}
+
 export interface AudioQuality {
   sampleRate: number;
   bitRate: number;
@@ -423,6 +498,7 @@ export interface RealtimeSettings {
   privacyFilter?: PrivacyFilterSettings;
   dogBarkFilter?: DogBarkFilterSettings;
   rtsp?: RTSPSettings;
+  ingest?: IngestSettings;
   mqtt?: MQTTSettings;
   telemetry?: TelemetrySettings;
   monitoring?: MonitoringSettings;
@@ -436,6 +512,7 @@ export interface WebServerSettings {
   port?: string;
   log?: LogConfig;
   liveStream?: LiveStreamSettings;
+  icecast?: IcecastSettings;
   enableTerminal?: boolean; // Enable browser terminal (security risk - disabled by default)
 }
 
@@ -522,6 +599,18 @@ export interface LiveStreamSettings {
   enabled?: boolean;
 }
 
+// IcecastSettings matches backend IcecastSettings
+export interface IcecastSettings {
+  enabled: boolean; // serve sources at /stream/<source id or name>
+  format: 'mp3' | 'opus';
+  bitRate: number; // kbps
+  maxListeners: number; // concurrent listeners per source
+  noiseReduction: boolean; // apply noise reduction to the streamed audio
+  sources: string[]; // source IDs or names to expose, empty for all
+  username: string; // HTTP basic auth for media players, empty to require a web login
+  password: string;
+}
+
 // File output settings (legacy interface)
 export interface FileOutputSettings {
   file?: {
@@ -538,6 +627,29 @@ export interface BackupSettings {
   path?: string;
 }
 
+// HubStation matches backend HubStation - a station allowed to push detections to this hub
+export interface HubStation {
+  name: string; // station name, detections are stored under it
+  token: string; // bearer token the station authenticates with
+  url?: string; // web address of the station for clips that were not uploaded
+}
+
+export interface HubUpstreamSettings {
+  enabled: boolean;
+  url: string; // base URL of the hub
+  station: string; // name of this station on the hub
+  token: string; // station token configured on the hub
+  interval: string; // Duration string, e.g. "30s"
+  batchSize: number; // detections per push (1-1000)
+  uploadClips: boolean; // upload audio clips with detections
+}
+
+export interface HubSettings {
+  enabled: boolean; // accept detections from stations
+  stations: HubStation[];
+  upstream: HubUpstreamSettings;
+}
+
 // Sentry settings (was SupportSettings)
 export interface SentrySettings {
   enabled: boolean;
@@ -629,6 +741,7 @@ export interface SettingsFormData {
   output?: OutputSettings;
   backup?: BackupSettings;
   notification?: NotificationSettings;
+  hub?: HubSettings;
 }
 
 // Global settings state interface
@@ -0,0 +1,206 @@
+package alerting
+
+import (
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
+)
+
+// absenceState tracks when each absence rule last saw a matching event and
+// whether the current silence has already been reported.
+type absenceState struct {
+	lastSeen time.Time
+	alerted  bool
+}
+
+// ValidateAbsenceRule checks the absence-specific fields of a rule. Rules with
+// other trigger types are accepted unchanged.
+func ValidateAbsenceRule(rule *entities.AlertRule) error {
+	if rule.TriggerType != TriggerTypeAbsence {
+		return nil
+	}
+	if rule.EventName == "" {
+		return errors.Newf("absence rule requires an event to watch").
+			Component("alerting").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "alert-absence-event").
+			Build()
+	}
+	if rule.WindowSec <= 0 {
+		return errors.Newf("absence rule requires a positive window").
+			Component("alerting").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "alert-absence-window").
+			Build()
+	}
+	if (rule.ActiveFrom == "") != (rule.ActiveUntil == "") {
+		return errors.Newf("absence rule active hours need both a start and an end").
+			Component("alerting").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "alert-absence-active-hours").
+			Build()
+	}
+	if rule.ActiveFrom != "" {
+		if _, err := conf.ParseQuietHoursTime(rule.ActiveFrom); err != nil {
+			return err
+		}
+		if _, err := conf.ParseQuietHoursTime(rule.ActiveUntil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetSunCalc sets the sun calculator used for sun-relative active hours.
+func (e *Engine) SetSunCalc(sun *suncalc.SunCalc) {
+	e.absenceMu.Lock()
+	defer e.absenceMu.Unlock()
+	e.sun = sun
+}
+
+// recordPresence marks absence rules watching this event as satisfied.
+func (e *Engine) recordPresence(rules []entities.AlertRule, event *AlertEvent) {
+	e.absenceMu.Lock()
+	defer e.absenceMu.Unlock()
+	for i := range rules {
+		rule := &rules[i]
+		if rule.TriggerType != TriggerTypeAbsence ||
+			rule.ObjectType != event.ObjectType ||
+			rule.EventName != event.EventName ||
+			!EvaluateConditions(rule.Conditions, event.Properties) {
+			continue
+		}
+		e.absence[rule.ID] = &absenceState{lastSeen: event.Timestamp}
+	}
+}
+
+// trackAbsenceRules starts tracking newly loaded absence rules from now, so a
+// freshly created rule or a restart does not report silence immediately.
+func (e *Engine) trackAbsenceRules(rules []entities.AlertRule, now time.Time) {
+	e.absenceMu.Lock()
+	defer e.absenceMu.Unlock()
+	for i := range rules {
+		if rules[i].TriggerType != TriggerTypeAbsence {
+			continue
+		}
+		if _, ok := e.absence[rules[i].ID]; !ok {
+			e.absence[rules[i].ID] = &absenceState{lastSeen: now}
+		}
+	}
+}
+
+// EvaluateAbsence fires absence rules whose watched event has not been seen
+// for the rule's window. Each silence is reported once; the rule rearms when a
+// matching event arrives. Outside the rule's active hours nothing fires, and
+// silence is counted from the start of the active period so that a quiet night
+// does not raise an alert at sunrise.
+func (e *Engine) EvaluateAbsence(now time.Time) {
+	e.rulesMu.RLock()
+	rules := make([]entities.AlertRule, len(e.rules))
+	copy(rules, e.rules)
+	e.rulesMu.RUnlock()
+
+	for i := range rules {
+		rule := &rules[i]
+		if rule.TriggerType != TriggerTypeAbsence || rule.WindowSec <= 0 {
+			continue
+		}
+		silentSince, ok := e.silentSince(rule, now)
+		if !ok || now.Sub(silentSince) < time.Duration(rule.WindowSec)*time.Second {
+			continue
+		}
+		if e.isInCooldown(rule.ID, rule.CooldownSec) || e.isOpen(rule.ID, "") {
+			continue
+		}
+
+		e.absenceMu.Lock()
+		state := e.absence[rule.ID]
+		lastSeen := state.lastSeen
+		state.alerted = true
+		e.absenceMu.Unlock()
+
+		e.fireRule(rule, &AlertEvent{
+			ObjectType: rule.ObjectType,
+			EventName:  rule.EventName,
+			Properties: map[string]any{
+				PropertyLastSeen:      lastSeen.Format(time.RFC3339),
+				PropertySilentMinutes: int(now.Sub(lastSeen).Minutes()),
+			},
+			Timestamp: now,
+		})
+	}
+}
+
+// silentSince returns when the silence being measured for rule began, or false
+// when the rule should not be evaluated now (outside active hours, or already
+// reported).
+func (e *Engine) silentSince(rule *entities.AlertRule, now time.Time) (time.Time, bool) {
+	e.absenceMu.Lock()
+	state, ok := e.absence[rule.ID]
+	if !ok {
+		state = &absenceState{lastSeen: now}
+		e.absence[rule.ID] = state
+	}
+	since, alerted, sun := state.lastSeen, state.alerted, e.sun
+	e.absenceMu.Unlock()
+
+	if alerted {
+		return time.Time{}, false
+	}
+	periodStart, active := activePeriodStart(rule, sun, now)
+	if !active {
+		return time.Time{}, false
+	}
+	if periodStart.After(since) {
+		since = periodStart
+	}
+	return since, true
+}
+
+// activePeriodStart reports whether now is within the rule's active hours and
+// when the current active period began. Rules without active hours are always
+// active. A period whose end is not after its start spans midnight.
+func activePeriodStart(rule *entities.AlertRule, sun *suncalc.SunCalc, now time.Time) (time.Time, bool) {
+	if rule.ActiveFrom == "" || rule.ActiveUntil == "" {
+		return time.Time{}, true
+	}
+	from, err := conf.ParseQuietHoursTime(rule.ActiveFrom)
+	if err != nil {
+		return time.Time{}, false
+	}
+	until, err := conf.ParseQuietHoursTime(rule.ActiveUntil)
+	if err != nil {
+		return time.Time{}, false
+	}
+	if (from.IsSunRelative() || until.IsSunRelative()) && sun == nil {
+		return time.Time{}, false
+	}
+
+	start, err := sun.ResolveTime(from, now)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := sun.ResolveTime(until, now)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	switch {
+	case start.Before(end):
+		return start, !now.Before(start) && now.Before(end)
+	case !now.Before(start):
+		return start, true
+	case now.Before(end):
+		// Early morning part of a period that started yesterday
+		prevStart, err := sun.ResolveTime(from, now.AddDate(0, 0, -1))
+		if err != nil {
+			return time.Time{}, false
+		}
+		return prevStart, true
+	default:
+		return time.Time{}, false
+	}
+}
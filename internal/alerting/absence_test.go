@@ -0,0 +1,149 @@
+package alerting
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
+)
+
+func absenceRule(id uint, windowSec int, conditions ...entities.AlertCondition) entities.AlertRule {
+	return entities.AlertRule{
+		ID:          id,
+		Enabled:     true,
+		ObjectType:  ObjectTypeDetection,
+		TriggerType: TriggerTypeAbsence,
+		EventName:   EventDetectionOccurred,
+		WindowSec:   windowSec,
+		Conditions:  conditions,
+	}
+}
+
+// newAbsenceEngine creates an engine with rules loaded and returns a counter of fired rule IDs.
+func newAbsenceEngine(t *testing.T, rules ...entities.AlertRule) (*Engine, func() []uint) {
+	t.Helper()
+	var mu sync.Mutex
+	var fired []uint
+	engine := NewEngine(newMockRepo(rules...), func(rule *entities.AlertRule, _ *AlertEvent) {
+		mu.Lock()
+		defer mu.Unlock()
+		fired = append(fired, rule.ID)
+	}, testLogger())
+	require.NoError(t, engine.RefreshRules(t.Context()))
+	return engine, func() []uint {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]uint(nil), fired...)
+	}
+}
+
+func TestEngine_AbsenceFiresOncePerSilence(t *testing.T) {
+	engine, fired := newAbsenceEngine(t, absenceRule(1, 3600))
+	now := time.Now()
+
+	engine.EvaluateAbsence(now.Add(30 * time.Minute))
+	assert.Empty(t, fired(), "window not yet elapsed")
+
+	engine.EvaluateAbsence(now.Add(61 * time.Minute))
+	assert.Equal(t, []uint{1}, fired())
+
+	engine.EvaluateAbsence(now.Add(3 * time.Hour))
+	assert.Len(t, fired(), 1, "same silence is reported once")
+
+	// A detection rearms the rule
+	engine.HandleEvent(&AlertEvent{ObjectType: ObjectTypeDetection, EventName: EventDetectionOccurred,
+		Properties: map[string]any{}, Timestamp: now.Add(3 * time.Hour)})
+	engine.EvaluateAbsence(now.Add(4*time.Hour + time.Minute))
+	assert.Len(t, fired(), 2)
+}
+
+func TestEngine_AbsencePresenceResetsWindow(t *testing.T) {
+	engine, fired := newAbsenceEngine(t, absenceRule(1, 3600))
+	now := time.Now()
+
+	engine.HandleEvent(&AlertEvent{ObjectType: ObjectTypeDetection, EventName: EventDetectionOccurred,
+		Properties: map[string]any{}, Timestamp: now.Add(50 * time.Minute)})
+	engine.EvaluateAbsence(now.Add(70 * time.Minute))
+	assert.Empty(t, fired())
+}
+
+func TestEngine_AbsenceConditionsFilterPresence(t *testing.T) {
+	rule := absenceRule(1, 3600, entities.AlertCondition{
+		Property: PropertyLocation, Operator: OperatorIs, Value: "pond",
+	})
+	engine, fired := newAbsenceEngine(t, rule)
+	now := time.Now()
+
+	// Detections from another source do not count as presence
+	engine.HandleEvent(&AlertEvent{ObjectType: ObjectTypeDetection, EventName: EventDetectionOccurred,
+		Properties: map[string]any{PropertyLocation: "garden"}, Timestamp: now.Add(50 * time.Minute)})
+	engine.EvaluateAbsence(now.Add(61 * time.Minute))
+	assert.Equal(t, []uint{1}, fired())
+}
+
+func TestEngine_AbsenceActiveHours(t *testing.T) {
+	rule := absenceRule(1, 3600)
+	rule.ActiveFrom = "08:00"
+	rule.ActiveUntil = "20:00"
+	engine, fired := newAbsenceEngine(t, rule)
+
+	day := time.Now().AddDate(0, 0, 1)
+	at := func(hour, minute int) time.Time {
+		return time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, time.Local)
+	}
+
+	engine.EvaluateAbsence(at(3, 0))
+	assert.Empty(t, fired(), "outside active hours")
+
+	engine.EvaluateAbsence(at(8, 30))
+	assert.Empty(t, fired(), "silence counts from the start of the active period")
+
+	engine.EvaluateAbsence(at(9, 1))
+	assert.Equal(t, []uint{1}, fired())
+}
+
+func TestActivePeriodStart(t *testing.T) {
+	t.Parallel()
+	sun := suncalc.NewSunCalc(60.17, 24.94)
+	at := func(hour int) time.Time { return time.Date(2026, 6, 10, hour, 0, 0, 0, time.Local) }
+
+	overnight := &entities.AlertRule{ActiveFrom: "22:00", ActiveUntil: "06:00"}
+	start, active := activePeriodStart(overnight, sun, at(3))
+	assert.True(t, active)
+	assert.Equal(t, time.Date(2026, 6, 9, 22, 0, 0, 0, time.Local), start, "period began the previous evening")
+	_, active = activePeriodStart(overnight, sun, at(12))
+	assert.False(t, active)
+
+	daylight := &entities.AlertRule{ActiveFrom: "sunrise+1h", ActiveUntil: "sunset-1h"}
+	_, active = activePeriodStart(daylight, sun, at(13))
+	assert.True(t, active)
+	_, active = activePeriodStart(daylight, nil, at(13))
+	assert.False(t, active, "sun-relative hours need a sun calculator")
+
+	_, active = activePeriodStart(&entities.AlertRule{}, nil, at(2))
+	assert.True(t, active, "no active hours means always active")
+}
+
+func TestValidateAbsenceRule(t *testing.T) {
+	t.Parallel()
+	valid := absenceRule(1, 600)
+	require.NoError(t, ValidateAbsenceRule(&valid))
+
+	noWindow := absenceRule(1, 0)
+	require.Error(t, ValidateAbsenceRule(&noWindow))
+
+	halfHours := absenceRule(1, 600)
+	halfHours.ActiveFrom = "sunrise"
+	require.Error(t, ValidateAbsenceRule(&halfHours))
+
+	badHours := absenceRule(1, 600)
+	badHours.ActiveFrom, badHours.ActiveUntil = "noon", "sunset"
+	require.Error(t, ValidateAbsenceRule(&badHours))
+
+	event := entities.AlertRule{TriggerType: TriggerTypeEvent}
+	require.NoError(t, ValidateAbsenceRule(&event), "other trigger types are not checked")
+}
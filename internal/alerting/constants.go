@@ -13,8 +13,9 @@ const (
 
 // Trigger types define how a rule is activated.
 const (
-	TriggerTypeEvent  = "event"
-	TriggerTypeMetric = "metric"
+	TriggerTypeEvent   = "event"
+	TriggerTypeMetric  = "metric"
+	TriggerTypeAbsence = "absence" // Fires when a matching event has not occurred for WindowSec
 )
 
 // Event names identify specific alertable events.
@@ -36,6 +37,11 @@ const (
 	EventDeviceStarted = "device.started"
 	EventDeviceStopped = "device.stopped"
 	EventDeviceError   = "device.error"
+
+	EventDeviceSoundLevel = "device.sound_level" // Sound level measurement interval completed
+
+	EventDeviceHealthIssue     = "device.health_issue"     // Microphone input problem persisted
+	EventDeviceHealthRecovered = "device.health_recovered" // Microphone input problem cleared
 )
 
 // Metric names identify threshold-based metrics.
@@ -55,6 +61,18 @@ const (
 	OperatorLessThan       = "less_than"
 	OperatorGreaterOrEqual = "greater_or_equal"
 	OperatorLessOrEqual    = "less_or_equal"
+	OperatorIn             = "in"           // Value is a comma-separated list
+	OperatorNotIn          = "not_in"       // Value is a comma-separated list
+	OperatorMatches        = "matches"      // Value is a regular expression
+	OperatorTimeBetween    = "time_between" // Value is "HH:MM-HH:MM", may wrap midnight
+	OperatorInGroup        = "in_group"     // Value lists genera, families or orders
+)
+
+// Condition group logic combines the conditions nested in a group.
+const (
+	LogicAnd = "and"
+	LogicOr  = "or"
+	LogicNot = "not" // Matches when the group's members do not all match
 )
 
 // Condition properties identify event fields available for condition evaluation.
@@ -70,9 +88,17 @@ const (
 	PropertyError          = "error"
 	PropertyPath           = "path"
 	PropertyBroker         = "broker"
+	PropertySource         = "source"
+	PropertyTimeOfDay      = "time_of_day" // Local "HH:MM" of the event, added by the engine
+	PropertyIssue          = "issue"       // Microphone health issue, e.g. "clipping" or "hum"
+
+	// Properties of events produced by absence triggers
+	PropertyLastSeen      = "last_seen"
+	PropertySilentMinutes = "silent_minutes"
 )
 
 // Action targets identify where notifications are sent.
 const (
 	TargetBell = "bell"
+	TargetPush = "push" // All push providers; other targets name a single provider
 )
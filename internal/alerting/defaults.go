@@ -60,6 +60,19 @@ func DefaultRules() []entities.AlertRule {
 				{Target: TargetBell, SortOrder: 0},
 			},
 		},
+		{
+			Name:        "Microphone health issue",
+			Description: "Notifies when an audio input is silent, clipping, offset, humming or degraded",
+			Enabled:     true,
+			BuiltIn:     true,
+			ObjectType:  ObjectTypeDevice,
+			TriggerType: TriggerTypeEvent,
+			EventName:   EventDeviceHealthIssue,
+			CooldownSec: 3600,
+			Actions: []entities.AlertAction{
+				{Target: TargetBell, SortOrder: 0},
+			},
+		},
 		{
 			Name:        "High CPU usage",
 			Description: "Notifies when CPU usage exceeds 90% for 5 minutes",
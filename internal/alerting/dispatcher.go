@@ -13,6 +13,13 @@ type NotificationCreator interface {
 	CreateAndBroadcast(title, message string) error
 }
 
+// TargetedNotificationCreator is implemented by notification creators that can
+// deliver to a push target: TargetPush for every push provider, or the name of
+// a single provider.
+type TargetedNotificationCreator interface {
+	CreateAndBroadcastTo(title, message, target string) error
+}
+
 // ActionDispatcher routes alert rule actions to the notification bell
 // and/or external targets.
 type ActionDispatcher struct {
@@ -34,15 +41,56 @@ func (d *ActionDispatcher) Dispatch(rule *entities.AlertRule, event *AlertEvent)
 		action := &rule.Actions[i]
 		title := renderTemplate(action.TemplateTitle, rule, event)
 		message := renderTemplate(action.TemplateMessage, rule, event)
+		d.dispatchTo(action.Target, title, message, rule)
+	}
+}
 
-		switch action.Target {
-		case TargetBell:
-			d.dispatchBell(title, message, rule)
-		default:
-			d.log.Warn("unknown alert action target",
-				logger.String("target", action.Target),
-				logger.Uint64("rule_id", uint64(rule.ID)))
-		}
+// DispatchResolved notifies the rule's action targets that its alert resolved.
+func (d *ActionDispatcher) DispatchResolved(rule *entities.AlertRule, event *AlertEvent) {
+	title := fmt.Sprintf("Resolved: %s", rule.Name)
+	message := fmt.Sprintf("Alert %q is no longer active", rule.Name)
+	if trigger := eventTrigger(event); trigger != "" {
+		message = fmt.Sprintf("Alert %q resolved (%s)", rule.Name, trigger)
+	}
+	for i := range rule.Actions {
+		d.dispatchTo(rule.Actions[i].Target, title, message, rule)
+	}
+}
+
+// DispatchEscalation re-sends an unacknowledged alert to the rule's escalation target.
+func (d *ActionDispatcher) DispatchEscalation(rule *entities.AlertRule, event *AlertEvent) {
+	if rule.EscalateTarget == "" {
+		return
+	}
+	var title, message string
+	if len(rule.Actions) > 0 {
+		title = renderTemplate(rule.Actions[0].TemplateTitle, rule, event)
+		message = renderTemplate(rule.Actions[0].TemplateMessage, rule, event)
+	} else {
+		title = defaultTemplate(rule, event)
+		message = title
+	}
+	title = "Unacknowledged: " + title
+	d.dispatchTo(rule.EscalateTarget, title, message, rule)
+}
+
+func (d *ActionDispatcher) dispatchTo(target, title, message string, rule *entities.AlertRule) {
+	if target == TargetBell {
+		d.dispatchBell(title, message, rule)
+		return
+	}
+	targeted, ok := d.notifCreator.(TargetedNotificationCreator)
+	if !ok {
+		d.log.Warn("unknown alert action target",
+			logger.String("target", target),
+			logger.Uint64("rule_id", uint64(rule.ID)))
+		return
+	}
+	if err := targeted.CreateAndBroadcastTo(title, message, target); err != nil {
+		d.log.Error("failed to dispatch alert to push target",
+			logger.String("target", target),
+			logger.Uint64("rule_id", uint64(rule.ID)),
+			logger.Error(err))
 	}
 }
 
@@ -57,6 +105,17 @@ func (d *ActionDispatcher) dispatchBell(title, message string, rule *entities.Al
 	}
 }
 
+// eventTrigger returns the event or metric name that produced an event.
+func eventTrigger(event *AlertEvent) string {
+	if event == nil {
+		return ""
+	}
+	if event.EventName != "" {
+		return event.EventName
+	}
+	return event.MetricName
+}
+
 // renderTemplate substitutes template variables in the title/message strings.
 // Falls back to defaults if the template is empty.
 func renderTemplate(tmpl string, rule *entities.AlertRule, event *AlertEvent) string {
@@ -20,6 +20,17 @@ func (m *mockNotifCreator) CreateAndBroadcast(title, message string) error {
 	return nil
 }
 
+// mockTargetedCreator also records notifications routed to push targets.
+type mockTargetedCreator struct {
+	mockNotifCreator
+	targeted []struct{ title, target string }
+}
+
+func (m *mockTargetedCreator) CreateAndBroadcastTo(title, _, target string) error {
+	m.targeted = append(m.targeted, struct{ title, target string }{title, target})
+	return nil
+}
+
 func dispatchTestLogger() logger.Logger {
 	return logger.NewSlogLogger(io.Discard, logger.LogLevelError, nil)
 }
@@ -117,6 +128,39 @@ func TestDispatcher_UnknownTargetSkipped(t *testing.T) {
 	assert.Empty(t, mock.calls, "unknown target should not produce a notification")
 }
 
+func TestDispatcher_PushTargets(t *testing.T) {
+	mock := &mockTargetedCreator{}
+	dispatcher := NewActionDispatcher(mock, dispatchTestLogger())
+
+	rule := &entities.AlertRule{
+		ID:   1,
+		Name: "Stream Down",
+		Actions: []entities.AlertAction{
+			{Target: TargetBell, TemplateTitle: "Stream lost"},
+			{Target: "telegram", TemplateTitle: "Stream lost"},
+		},
+		EscalateTarget: "pager",
+	}
+	event := &AlertEvent{ObjectType: ObjectTypeStream, EventName: EventStreamDisconnected}
+
+	dispatcher.Dispatch(rule, event)
+	require.Len(t, mock.calls, 1)
+	require.Len(t, mock.targeted, 1)
+	assert.Equal(t, "telegram", mock.targeted[0].target)
+
+	dispatcher.DispatchEscalation(rule, event)
+	require.Len(t, mock.targeted, 2)
+	assert.Equal(t, "pager", mock.targeted[1].target)
+	assert.Equal(t, "Unacknowledged: Stream lost", mock.targeted[1].title)
+
+	dispatcher.DispatchResolved(rule, &AlertEvent{ObjectType: ObjectTypeStream, EventName: EventStreamConnected})
+	require.Len(t, mock.calls, 2)
+	assert.Equal(t, "Resolved: Stream Down", mock.calls[1].title)
+	assert.Contains(t, mock.calls[1].message, EventStreamConnected)
+	require.Len(t, mock.targeted, 3)
+	assert.Equal(t, "telegram", mock.targeted[2].target)
+}
+
 func TestDispatcher_NilNotifCreator(t *testing.T) {
 	dispatcher := NewActionDispatcher(nil, dispatchTestLogger())
 
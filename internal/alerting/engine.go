@@ -3,12 +3,14 @@ package alerting
 import (
 	"context"
 	"encoding/json"
+	"maps"
 	"sync"
 	"time"
 
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
 )
 
 const (
@@ -40,6 +42,20 @@ type Engine struct {
 
 	// History cleanup
 	cleanupStop chan struct{}
+
+	// Absence tracking (in-memory, restarts the silence window on restart)
+	absence     map[uint]*absenceState // rule ID → last matching event
+	absenceMu   sync.Mutex
+	sun         *suncalc.SunCalc // resolves sun-relative active hours; nil disables them
+
+	// Open stateful alerts, restored from history on startup
+	open         map[openAlertKey]*openAlert
+	openMu       sync.Mutex
+	resolveFunc  ActionFunc
+	escalateFunc ActionFunc
+
+	// Scheduled absence and escalation checks
+	monitorStop chan struct{}
 }
 
 // NewEngine creates a new alerting rules engine.
@@ -50,6 +66,8 @@ func NewEngine(repo repository.AlertRuleRepository, actionFunc ActionFunc, log l
 		actionFunc:    actionFunc,
 		log:           log,
 		cooldowns:     make(map[uint]time.Time),
+		absence:       make(map[uint]*absenceState),
+		open:          make(map[openAlertKey]*openAlert),
 	}
 }
 
@@ -60,12 +78,36 @@ func (e *Engine) RefreshRules(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	e.compilePatterns(rules)
 	e.rulesMu.Lock()
 	e.rules = rules
 	e.rulesMu.Unlock()
+	e.trackAbsenceRules(rules, time.Now())
+	e.syncOpenAlerts(rules)
 	return nil
 }
 
+// compilePatterns compiles the patterns of the "matches" conditions of loaded
+// rules, so events are not matched against patterns compiled anew. A pattern
+// that does not compile is left unset and never matches.
+func (e *Engine) compilePatterns(rules []entities.AlertRule) {
+	for i := range rules {
+		for j := range rules[i].Conditions {
+			cond := &rules[i].Conditions[j]
+			if cond.Operator != OperatorMatches || cond.Logic != "" {
+				continue
+			}
+			re, err := compileConditionRegex(cond.Value)
+			if err != nil {
+				e.log.Warn("alert rule pattern does not compile and never matches",
+					logger.Uint64("rule_id", uint64(rules[i].ID)),
+					logger.Error(err))
+			}
+			cond.Pattern = re
+		}
+	}
+}
+
 // HandleEvent evaluates an event against all enabled rules.
 func (e *Engine) HandleEvent(event *AlertEvent) {
 	// Record metric sample once before rule iteration to avoid duplicates
@@ -83,14 +125,37 @@ func (e *Engine) HandleEvent(event *AlertEvent) {
 	copy(rules, e.rules)
 	e.rulesMu.RUnlock()
 
+	event = withTimeOfDay(event)
+	e.recordPresence(rules, event)
+	e.resolveRecovered(event)
+
 	for i := range rules {
 		rule := &rules[i]
-		if e.ruleMatches(rule, event) && !e.isInCooldown(rule.ID, rule.CooldownSec) {
+		if e.ruleMatches(rule, event) && !e.isInCooldown(rule.ID, rule.CooldownSec) && !e.isOpen(rule.ID, subjectKey(event.Properties)) {
 			e.fireRule(rule, event)
 		}
 	}
 }
 
+// withTimeOfDay returns the event with PropertyTimeOfDay set from its
+// timestamp, copying the properties so the publisher's map is not modified.
+func withTimeOfDay(event *AlertEvent) *AlertEvent {
+	if _, ok := event.Properties[PropertyTimeOfDay]; ok {
+		return event
+	}
+	ts := event.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	props := make(map[string]any, len(event.Properties)+1)
+	maps.Copy(props, event.Properties)
+	props[PropertyTimeOfDay] = ts.Local().Format("15:04")
+
+	withTime := *event
+	withTime.Properties = props
+	return &withTime
+}
+
 func (e *Engine) ruleMatches(rule *entities.AlertRule, event *AlertEvent) bool {
 	// Match object type
 	if rule.ObjectType != event.ObjectType {
@@ -151,7 +216,13 @@ func (e *Engine) isInCooldown(ruleID uint, cooldownSec int) bool {
 	return time.Since(lastFired) < time.Duration(cooldownSec)*time.Second
 }
 
+// fireRule records and dispatches a firing. Firings of stateful rules stay
+// open until resolved.
 func (e *Engine) fireRule(rule *entities.AlertRule, event *AlertEvent) {
+	e.fire(rule, event, isStateful(rule))
+}
+
+func (e *Engine) fire(rule *entities.AlertRule, event *AlertEvent, track bool) {
 	// Record cooldown
 	e.cooldownsMu.Lock()
 	e.cooldowns[rule.ID] = time.Now()
@@ -174,6 +245,9 @@ func (e *Engine) fireRule(rule *entities.AlertRule, event *AlertEvent) {
 		EventData: string(eventJSON),
 		Actions:   string(actionsJSON),
 	}
+	if track {
+		history.Status = repository.AlertStatusFiring
+	}
 	saveCtx, saveCancel := context.WithTimeout(context.Background(), saveHistoryTimeout)
 	defer saveCancel()
 	if err := e.repo.SaveHistory(saveCtx, history); err != nil {
@@ -181,6 +255,9 @@ func (e *Engine) fireRule(rule *entities.AlertRule, event *AlertEvent) {
 			logger.Uint64("rule_id", uint64(rule.ID)),
 			logger.Error(err))
 	}
+	if track {
+		e.trackOpenAlert(rule, event, history)
+	}
 
 	// Dispatch actions
 	if e.actionFunc != nil {
@@ -198,7 +275,7 @@ func (e *Engine) TestFireRule(rule *entities.AlertRule) {
 		Properties: map[string]any{"test": true},
 		Timestamp:  time.Now(),
 	}
-	e.fireRule(rule, event)
+	e.fire(rule, event, false)
 }
 
 // StartHistoryCleanup starts a background goroutine that periodically deletes
@@ -250,7 +327,8 @@ func (e *Engine) stopCleanup() {
 	}
 }
 
-// Stop shuts down background goroutines (history cleanup).
+// Stop shuts down background goroutines (history cleanup and alert monitor).
 func (e *Engine) Stop() {
 	e.stopCleanup()
+	e.stopMonitor()
 }
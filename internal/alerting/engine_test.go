@@ -41,6 +41,7 @@ func (m *mockAlertRuleRepo) SaveHistory(_ context.Context, h *entities.AlertHist
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.history = append(m.history, h)
+	h.ID = uint(len(m.history))
 	return nil
 }
 
@@ -63,6 +64,50 @@ func (m *mockAlertRuleRepo) DeleteHistory(_ context.Context) (int64, error)
 func (m *mockAlertRuleRepo) DeleteHistoryBefore(_ context.Context, _ time.Time) (int64, error) {
 	return 0, nil
 }
+func (m *mockAlertRuleRepo) ListOpenAlerts(_ context.Context) ([]entities.AlertHistory, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []entities.AlertHistory
+	for i := len(m.history) - 1; i >= 0; i-- {
+		if h := m.history[i]; h.Status == repository.AlertStatusFiring || h.Status == repository.AlertStatusAcknowledged {
+			out = append(out, *h)
+		}
+	}
+	return out, nil
+}
+func (m *mockAlertRuleRepo) AcknowledgeAlert(_ context.Context, id uint, at time.Time) error {
+	return m.updateOpen(id, func(h *entities.AlertHistory) bool {
+		if h.Status != repository.AlertStatusFiring {
+			return false
+		}
+		h.Status, h.AcknowledgedAt = repository.AlertStatusAcknowledged, &at
+		return true
+	})
+}
+func (m *mockAlertRuleRepo) MarkAlertEscalated(_ context.Context, id uint, at time.Time) error {
+	return m.updateOpen(id, func(h *entities.AlertHistory) bool {
+		h.EscalatedAt = &at
+		return true
+	})
+}
+func (m *mockAlertRuleRepo) ResolveAlert(_ context.Context, id uint, at time.Time) error {
+	return m.updateOpen(id, func(h *entities.AlertHistory) bool {
+		h.Status, h.ResolvedAt = repository.AlertStatusResolved, &at
+		return true
+	})
+}
+
+// updateOpen applies update to the open history entry with the given ID.
+func (m *mockAlertRuleRepo) updateOpen(id uint, update func(h *entities.AlertHistory) bool) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, h := range m.history {
+		if h.ID == id && (h.Status == repository.AlertStatusFiring || h.Status == repository.AlertStatusAcknowledged) && update(h) {
+			return nil
+		}
+	}
+	return repository.ErrOpenAlertNotFound
+}
 func (m *mockAlertRuleRepo) CountRulesByName(_ context.Context, _ string) (int64, error) {
 	return 0, nil
 }
@@ -128,6 +173,43 @@ func TestEngine_EventMatchesRuleWithPassingConditions(t *testing.T) {
 	assert.True(t, fired)
 }
 
+func TestEngine_RefreshRulesCompilesPatterns(t *testing.T) {
+	rule := entities.AlertRule{
+		ID:          1,
+		Enabled:     true,
+		ObjectType:  ObjectTypeDetection,
+		TriggerType: TriggerTypeEvent,
+		EventName:   EventDetectionOccurred,
+		Conditions: []entities.AlertCondition{
+			{Property: PropertySpeciesName, Operator: OperatorMatches, Value: "(?i) owl$"},
+		},
+	}
+	invalid := rule
+	invalid.ID = 2
+	invalid.Conditions = []entities.AlertCondition{{Property: PropertySpeciesName, Operator: OperatorMatches, Value: "(["}}
+	repo := newMockRepo(rule, invalid)
+
+	var fired []uint
+	engine := NewEngine(repo, func(r *entities.AlertRule, _ *AlertEvent) {
+		fired = append(fired, r.ID)
+	}, testLogger())
+	require.NoError(t, engine.RefreshRules(t.Context()))
+
+	engine.rulesMu.RLock()
+	require.Len(t, engine.rules, 2)
+	assert.NotNil(t, engine.rules[0].Conditions[0].Pattern, "the pattern is compiled when the rule is loaded")
+	assert.Nil(t, engine.rules[1].Conditions[0].Pattern)
+	engine.rulesMu.RUnlock()
+
+	engine.HandleEvent(&AlertEvent{
+		ObjectType: ObjectTypeDetection,
+		EventName:  EventDetectionOccurred,
+		Properties: map[string]any{PropertySpeciesName: "Great Horned Owl"},
+		Timestamp:  time.Now(),
+	})
+	assert.Equal(t, []uint{1}, fired, "an invalid pattern never matches")
+}
+
 func TestEngine_EventMatchesRuleWithFailingConditions(t *testing.T) {
 	rule := entities.AlertRule{
 		ID:          1,
@@ -249,7 +331,7 @@ func TestEngine_CooldownExpires(t *testing.T) {
 		Enabled:     true,
 		ObjectType:  ObjectTypeStream,
 		TriggerType: TriggerTypeEvent,
-		EventName:   EventStreamDisconnected,
+		EventName:   EventStreamConnected, // not stateful, so only cooldown limits refiring
 		CooldownSec: 1, // 1 second for test speed
 	}
 	repo := newMockRepo(rule)
@@ -263,7 +345,7 @@ func TestEngine_CooldownExpires(t *testing.T) {
 
 	event := &AlertEvent{
 		ObjectType: ObjectTypeStream,
-		EventName:  EventStreamDisconnected,
+		EventName:  EventStreamConnected,
 		Properties: map[string]any{},
 		Timestamp:  time.Now(),
 	}
@@ -389,7 +471,7 @@ func TestEngine_NoCooldownMeansAlwaysFires(t *testing.T) {
 		Enabled:     true,
 		ObjectType:  ObjectTypeStream,
 		TriggerType: TriggerTypeEvent,
-		EventName:   EventStreamDisconnected,
+		EventName:   EventStreamConnected, // not stateful, so only cooldown limits refiring
 		CooldownSec: 0,
 	}
 	repo := newMockRepo(rule)
@@ -403,7 +485,7 @@ func TestEngine_NoCooldownMeansAlwaysFires(t *testing.T) {
 
 	event := &AlertEvent{
 		ObjectType: ObjectTypeStream,
-		EventName:  EventStreamDisconnected,
+		EventName:  EventStreamConnected,
 		Properties: map[string]any{},
 		Timestamp:  time.Now(),
 	}
@@ -456,3 +538,29 @@ func TestEngine_MultipleRulesSameMetricNoDuplicateRecording(t *testing.T) {
 	engine.metricTracker.mu.RUnlock()
 	assert.Len(t, samples, 1, "metric should be recorded once per event, not once per rule")
 }
+
+func TestEngine_TimeOfDayCondition(t *testing.T) {
+	rule := entities.AlertRule{
+		ID: 1, Enabled: true, ObjectType: ObjectTypeDetection, TriggerType: TriggerTypeEvent,
+		EventName: EventDetectionOccurred,
+		Conditions: []entities.AlertCondition{
+			{Property: PropertyTimeOfDay, Operator: OperatorTimeBetween, Value: "22:00-05:00"},
+		},
+	}
+	var fired []uint
+	engine := NewEngine(newMockRepo(rule), func(r *entities.AlertRule, _ *AlertEvent) {
+		fired = append(fired, r.ID)
+	}, testLogger())
+	require.NoError(t, engine.RefreshRules(t.Context()))
+
+	props := map[string]any{PropertySpeciesName: "Tawny Owl"}
+	day := time.Date(2026, 6, 10, 0, 0, 0, 0, time.Local)
+	engine.HandleEvent(&AlertEvent{ObjectType: ObjectTypeDetection, EventName: EventDetectionOccurred,
+		Properties: props, Timestamp: day.Add(13 * time.Hour)})
+	assert.Empty(t, fired)
+
+	engine.HandleEvent(&AlertEvent{ObjectType: ObjectTypeDetection, EventName: EventDetectionOccurred,
+		Properties: props, Timestamp: day.Add(23 * time.Hour)})
+	assert.Equal(t, []uint{1}, fired)
+	assert.NotContains(t, props, PropertyTimeOfDay, "publisher's properties are not modified")
+}
@@ -2,24 +2,170 @@ package alerting
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
 )
 
-// EvaluateConditions checks if all conditions match against event properties.
-// Returns true if ALL conditions are satisfied (AND logic).
+// maxConditionDepth limits how deeply condition groups may be nested.
+const maxConditionDepth = 8
+
+// EvaluateConditions checks if conditions match against event properties.
+// Top-level conditions are ANDed; group conditions (Logic "and", "or", "not")
+// combine the conditions whose ParentKey references their NodeKey.
 // Empty conditions list returns true (no conditions = always match).
+// A malformed group structure never matches.
 func EvaluateConditions(conditions []entities.AlertCondition, properties map[string]any) bool {
+	if !hasConditionGroups(conditions) {
+		for i := range conditions {
+			if !evaluateCondition(&conditions[i], properties) {
+				return false
+			}
+		}
+		return true
+	}
+
+	root, err := buildConditionTree(conditions)
+	if err != nil {
+		return false
+	}
+	return root.evaluate(properties)
+}
+
+// hasConditionGroups reports whether any condition uses grouping.
+func hasConditionGroups(conditions []entities.AlertCondition) bool {
 	for i := range conditions {
-		if !evaluateCondition(&conditions[i], properties) {
+		if conditions[i].Logic != "" || conditions[i].ParentKey != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// conditionNode is a condition in the evaluation tree. The root node has no
+// condition and ANDs its children.
+type conditionNode struct {
+	cond     *entities.AlertCondition
+	children []*conditionNode
+}
+
+// buildConditionTree links conditions to their groups and verifies that every
+// condition is reachable from the top level within maxConditionDepth.
+func buildConditionTree(conditions []entities.AlertCondition) (*conditionNode, error) {
+	root := &conditionNode{}
+	nodes := make([]*conditionNode, len(conditions))
+	groups := make(map[int]*conditionNode)
+
+	for i := range conditions {
+		cond := &conditions[i]
+		nodes[i] = &conditionNode{cond: cond}
+		if cond.Logic == "" {
+			continue
+		}
+		switch cond.Logic {
+		case LogicAnd, LogicOr, LogicNot:
+		default:
+			return nil, conditionGroupError("unknown condition group logic %q", cond.Logic)
+		}
+		if cond.NodeKey <= 0 {
+			return nil, conditionGroupError("condition group requires a positive node key")
+		}
+		if _, exists := groups[cond.NodeKey]; exists {
+			return nil, conditionGroupError("duplicate condition group key %d", cond.NodeKey)
+		}
+		groups[cond.NodeKey] = nodes[i]
+	}
+
+	for i := range conditions {
+		parent := root
+		if key := conditions[i].ParentKey; key != 0 {
+			group, ok := groups[key]
+			if !ok {
+				return nil, conditionGroupError("condition references unknown group %d", key)
+			}
+			parent = group
+		}
+		parent.children = append(parent.children, nodes[i])
+	}
+
+	// Every node has a single parent, so nodes caught in a cycle are not
+	// reachable from the root.
+	reached, depth := root.size(0)
+	if depth > maxConditionDepth {
+		return nil, conditionGroupError("condition groups nested deeper than %d levels", maxConditionDepth)
+	}
+	if reached != len(conditions) {
+		return nil, conditionGroupError("condition groups form a cycle")
+	}
+	return root, nil
+}
+
+// size returns the number of descendants of n and the depth of its deepest
+// descendant. Traversal stops below maxConditionDepth.
+func (n *conditionNode) size(depth int) (count, maxDepth int) {
+	maxDepth = depth
+	if depth > maxConditionDepth {
+		return 0, depth
+	}
+	for _, child := range n.children {
+		c, d := child.size(depth + 1)
+		count += 1 + c
+		maxDepth = max(maxDepth, d)
+	}
+	return count, maxDepth
+}
+
+func (n *conditionNode) evaluate(properties map[string]any) bool {
+	logic := LogicAnd
+	if n.cond != nil {
+		if n.cond.Logic == "" {
+			return evaluateCondition(n.cond, properties)
+		}
+		logic = n.cond.Logic
+	}
+
+	// Empty groups place no constraint
+	if len(n.children) == 0 {
+		return true
+	}
+
+	switch logic {
+	case LogicOr:
+		for _, child := range n.children {
+			if child.evaluate(properties) {
+				return true
+			}
+		}
+		return false
+	case LogicNot:
+		return !allMatch(n.children, properties)
+	default:
+		return allMatch(n.children, properties)
+	}
+}
+
+func allMatch(nodes []*conditionNode, properties map[string]any) bool {
+	for _, node := range nodes {
+		if !node.evaluate(properties) {
 			return false
 		}
 	}
 	return true
 }
 
+func conditionGroupError(format string, args ...any) error {
+	return errors.Newf(format, args...).
+		Component("alerting").
+		Category(errors.CategoryValidation).
+		Context("validation_type", "alert-condition-groups").
+		Build()
+}
+
 func evaluateCondition(cond *entities.AlertCondition, properties map[string]any) bool {
 	propVal, exists := properties[cond.Property]
 	if !exists {
@@ -40,11 +186,154 @@ func evaluateCondition(cond *entities.AlertCondition, properties map[string]any)
 		return !strings.Contains(strings.ToLower(propStr), strings.ToLower(condVal))
 	case OperatorGreaterThan, OperatorLessThan, OperatorGreaterOrEqual, OperatorLessOrEqual:
 		return evaluateNumeric(cond.Operator, propVal, condVal)
+	case OperatorIn:
+		return inList(propStr, condVal)
+	case OperatorNotIn:
+		return !inList(propStr, condVal)
+	case OperatorMatches:
+		return matchesPattern(cond, propStr)
+	case OperatorTimeBetween:
+		return evaluateTimeBetween(propVal, condVal)
+	case OperatorInGroup:
+		return inSpeciesGroup(propStr, condVal)
 	default:
 		return false
 	}
 }
 
+// splitList splits a comma-separated condition value into trimmed, non-empty items.
+func splitList(value string) []string {
+	parts := strings.Split(value, ",")
+	items := parts[:0]
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			items = append(items, part)
+		}
+	}
+	return items
+}
+
+// inList reports whether value equals any item of a comma-separated list, ignoring case.
+func inList(value, list string) bool {
+	value = strings.TrimSpace(value)
+	for _, item := range splitList(list) {
+		if strings.EqualFold(value, item) {
+			return true
+		}
+	}
+	return false
+}
+
+// compileConditionRegex compiles the pattern of a "matches" condition.
+func compileConditionRegex(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("alerting").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "alert-condition-regex").
+			Build()
+	}
+	return re, nil
+}
+
+// matchesPattern reports whether a value matches the pattern of a "matches"
+// condition, compiling it for conditions of rules not loaded by the engine.
+func matchesPattern(cond *entities.AlertCondition, value string) bool {
+	re := cond.Pattern
+	if re == nil {
+		var err error
+		if re, err = compileConditionRegex(cond.Value); err != nil {
+			return false
+		}
+	}
+	return re.MatchString(value)
+}
+
+// parseTimeRange parses a "HH:MM-HH:MM" time of day range into minutes after midnight.
+func parseTimeRange(value string) (start, end int, err error) {
+	from, until, ok := strings.Cut(value, "-")
+	if ok {
+		if start, err = parseClockMinutes(from); err == nil {
+			end, err = parseClockMinutes(until)
+		}
+	}
+	if !ok || err != nil {
+		return 0, 0, errors.Newf("invalid time range %q, expected HH:MM-HH:MM", value).
+			Component("alerting").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "alert-condition-time-range").
+			Build()
+	}
+	return start, end, nil
+}
+
+func parseClockMinutes(value string) (int, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// evaluateTimeBetween checks whether a time of day falls within a range.
+// The range includes its start and excludes its end; a range whose end is not
+// after its start wraps past midnight.
+func evaluateTimeBetween(propVal any, condVal string) bool {
+	start, end, err := parseTimeRange(condVal)
+	if err != nil {
+		return false
+	}
+
+	var minutes int
+	switch v := propVal.(type) {
+	case time.Time:
+		minutes = v.Hour()*60 + v.Minute()
+	default:
+		if minutes, err = parseClockMinutes(fmt.Sprintf("%v", v)); err != nil {
+			return false
+		}
+	}
+
+	if start < end {
+		return minutes >= start && minutes < end
+	}
+	return minutes >= start || minutes < end
+}
+
+// SpeciesGroupResolver returns the taxonomic groups (genus, family, order and
+// their common names) that a species, given by scientific name, belongs to.
+type SpeciesGroupResolver func(scientificName string) []string
+
+var speciesGroupResolver atomic.Pointer[SpeciesGroupResolver]
+
+// SetSpeciesGroupResolver sets the resolver used by the in_group operator.
+// Without a resolver in_group conditions never match.
+func SetSpeciesGroupResolver(resolver SpeciesGroupResolver) {
+	if resolver == nil {
+		speciesGroupResolver.Store(nil)
+		return
+	}
+	speciesGroupResolver.Store(&resolver)
+}
+
+// inSpeciesGroup reports whether the species belongs to any of the listed groups.
+func inSpeciesGroup(scientificName, groups string) bool {
+	resolver := speciesGroupResolver.Load()
+	if resolver == nil {
+		return false
+	}
+	memberOf := (*resolver)(scientificName)
+	for _, group := range splitList(groups) {
+		for _, name := range memberOf {
+			if strings.EqualFold(group, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 func evaluateNumeric(operator string, propVal any, condVal string) bool {
 	propFloat, err := toFloat64(propVal)
 	if err != nil {
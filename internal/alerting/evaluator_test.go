@@ -2,6 +2,7 @@ package alerting
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
@@ -31,6 +32,13 @@ func TestEvaluateConditions_StringOperators(t *testing.T) {
 		{"contains no match", OperatorContains, "species_name", "Eagle", "Great Horned Owl", false},
 		{"not_contains match", OperatorNotContains, "species_name", "Eagle", "Great Horned Owl", true},
 		{"not_contains no match", OperatorNotContains, "species_name", "Owl", "Great Horned Owl", false},
+		{"in match", OperatorIn, "species_name", "Robin, great horned owl", "Great Horned Owl", true},
+		{"in no match", OperatorIn, "species_name", "Robin, Blackbird", "Great Horned Owl", false},
+		{"not_in match", OperatorNotIn, "species_name", "Robin, Blackbird", "Great Horned Owl", true},
+		{"not_in no match", OperatorNotIn, "species_name", "Robin,Blackbird", "Robin", false},
+		{"matches", OperatorMatches, "species_name", "(?i)^great .* owl$", "Great Horned Owl", true},
+		{"matches no match", OperatorMatches, "species_name", "^Owl", "Great Horned Owl", false},
+		{"matches invalid regex", OperatorMatches, "species_name", "([", "Great Horned Owl", false},
 	}
 
 	for _, tt := range tests {
@@ -163,3 +171,111 @@ func TestEvaluateConditions_AdditionalIntTypes(t *testing.T) {
 		})
 	}
 }
+
+func TestEvaluateConditions_TimeBetween(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		propVal any
+		want    bool
+	}{
+		{"inside range", "06:00-09:30", "07:15", true},
+		{"start inclusive", "06:00-09:30", "06:00", true},
+		{"end exclusive", "06:00-09:30", "09:30", false},
+		{"outside range", "06:00-09:30", "12:00", false},
+		{"wraps midnight late", "22:00-05:00", "23:10", true},
+		{"wraps midnight early", "22:00-05:00", "04:59", true},
+		{"wraps midnight outside", "22:00-05:00", "12:00", false},
+		{"time value", "22:00-05:00", time.Date(2026, 1, 1, 1, 30, 0, 0, time.Local), true},
+		{"invalid range", "late", "23:00", false},
+		{"invalid property", "22:00-05:00", "night", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conds := []entities.AlertCondition{
+				{Property: PropertyTimeOfDay, Operator: OperatorTimeBetween, Value: tt.value},
+			}
+			assert.Equal(t, tt.want, EvaluateConditions(conds, map[string]any{PropertyTimeOfDay: tt.propVal}))
+		})
+	}
+}
+
+func TestEvaluateConditions_InGroup(t *testing.T) {
+	cond := []entities.AlertCondition{
+		{Property: PropertyScientificName, Operator: OperatorInGroup, Value: "Strigiformes, Caprimulgidae"},
+	}
+	owl := map[string]any{PropertyScientificName: "Bubo bubo"}
+
+	assert.False(t, EvaluateConditions(cond, owl), "no resolver means no match")
+
+	SetSpeciesGroupResolver(func(scientificName string) []string {
+		if scientificName == "Bubo bubo" {
+			return []string{"bubo", "Strigidae", "Owls", "Strigiformes"}
+		}
+		return nil
+	})
+	t.Cleanup(func() { SetSpeciesGroupResolver(nil) })
+
+	assert.True(t, EvaluateConditions(cond, owl))
+	assert.False(t, EvaluateConditions(cond, map[string]any{PropertyScientificName: "Turdus merula"}))
+}
+
+func TestEvaluateConditions_Groups(t *testing.T) {
+	// (owl OR nightjar) AND confidence > 0.8 AND location is pond
+	conds := []entities.AlertCondition{
+		{Logic: LogicOr, NodeKey: 1},
+		{Property: PropertySpeciesName, Operator: OperatorContains, Value: "owl", ParentKey: 1},
+		{Property: PropertySpeciesName, Operator: OperatorContains, Value: "nightjar", ParentKey: 1},
+		{Property: PropertyConfidence, Operator: OperatorGreaterThan, Value: "0.8"},
+		{Property: PropertyLocation, Operator: OperatorIs, Value: "pond"},
+	}
+	props := func(species string, confidence float64, location string) map[string]any {
+		return map[string]any{PropertySpeciesName: species, PropertyConfidence: confidence, PropertyLocation: location}
+	}
+
+	assert.True(t, EvaluateConditions(conds, props("Tawny Owl", 0.9, "pond")))
+	assert.True(t, EvaluateConditions(conds, props("European Nightjar", 0.9, "pond")))
+	assert.False(t, EvaluateConditions(conds, props("Robin", 0.9, "pond")))
+	assert.False(t, EvaluateConditions(conds, props("Tawny Owl", 0.7, "pond")))
+	assert.False(t, EvaluateConditions(conds, props("Tawny Owl", 0.9, "garden")))
+}
+
+func TestEvaluateConditions_NestedNot(t *testing.T) {
+	// NOT (species is Robin OR species is Blackbird)
+	conds := []entities.AlertCondition{
+		{Logic: LogicNot, NodeKey: 1},
+		{Logic: LogicOr, NodeKey: 2, ParentKey: 1},
+		{Property: PropertySpeciesName, Operator: OperatorIs, Value: "Robin", ParentKey: 2},
+		{Property: PropertySpeciesName, Operator: OperatorIs, Value: "Blackbird", ParentKey: 2},
+	}
+	assert.False(t, EvaluateConditions(conds, map[string]any{PropertySpeciesName: "Robin"}))
+	assert.True(t, EvaluateConditions(conds, map[string]any{PropertySpeciesName: "Wren"}))
+}
+
+func TestEvaluateConditions_MalformedGroupsNeverMatch(t *testing.T) {
+	tests := []struct {
+		name  string
+		conds []entities.AlertCondition
+	}{
+		{"unknown parent", []entities.AlertCondition{
+			{Property: PropertySpeciesName, Operator: OperatorIs, Value: "Robin", ParentKey: 7},
+		}},
+		{"cycle", []entities.AlertCondition{
+			{Logic: LogicOr, NodeKey: 1, ParentKey: 2},
+			{Logic: LogicOr, NodeKey: 2, ParentKey: 1},
+		}},
+		{"unknown logic", []entities.AlertCondition{{Logic: "xor", NodeKey: 1}}},
+		{"duplicate key", []entities.AlertCondition{
+			{Logic: LogicOr, NodeKey: 1},
+			{Logic: LogicAnd, NodeKey: 1},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.False(t, EvaluateConditions(tt.conds, map[string]any{PropertySpeciesName: "Robin"}))
+			assert.Error(t, ValidateConditions(tt.conds))
+		})
+	}
+}
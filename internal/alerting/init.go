@@ -8,6 +8,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/events"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/notification"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
 )
 
 // notificationAdapter lazily resolves the notification service to implement
@@ -24,6 +25,18 @@ func (a *notificationAdapter) CreateAndBroadcast(title, message string) error {
 	return err
 }
 
+func (a *notificationAdapter) CreateAndBroadcastTo(title, message, target string) error {
+	svc := notification.GetService()
+	if svc == nil {
+		return nil // notification service not yet initialized
+	}
+	notif := notification.NewNotification(notification.TypeSystem, notification.PriorityHigh, title, message)
+	if target != TargetPush {
+		notif.WithMetadata(notification.MetadataKeyPushTarget, target)
+	}
+	return svc.CreateWithMetadata(notif)
+}
+
 // Initialize creates and starts the alerting engine.
 // It seeds default rules if none exist, creates the engine with the
 // action dispatcher, subscribes to the event bus, and loads rules.
@@ -43,11 +56,18 @@ func Initialize(
 	dispatcher := NewActionDispatcher(&notificationAdapter{}, log)
 	engine := NewEngine(repo, dispatcher.Dispatch, log)
 
+	engine.SetLifecycleActions(dispatcher.DispatchResolved, dispatcher.DispatchEscalation)
+
 	// Load rules from database
 	if err := engine.RefreshRules(ctx); err != nil {
 		return nil, err
 	}
 
+	// Restore alerts that were still open at shutdown
+	if err := engine.LoadOpenAlerts(ctx); err != nil {
+		log.Warn("failed to restore open alerts", logger.Error(err))
+	}
+
 	// Subscribe engine to the event bus and set global singleton
 	eventBus.Subscribe(engine.HandleEvent)
 	SetGlobalBus(eventBus)
@@ -68,8 +88,12 @@ func Initialize(
 	// Start periodic history cleanup based on configured retention
 	if settings := conf.GetSettings(); settings != nil {
 		engine.StartHistoryCleanup(settings.Alerting.HistoryRetentionDays)
+		engine.SetSunCalc(suncalc.NewSunCalc(settings.BirdNET.Latitude, settings.BirdNET.Longitude))
 	}
 
+	// Evaluate absence rules ("no detections for N hours") and escalations on a schedule
+	engine.StartMonitor()
+
 	log.Info("alerting engine initialized",
 		logger.Int("rules_loaded", len(engine.rules)))
 
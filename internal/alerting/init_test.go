@@ -59,6 +59,12 @@ func (m *initMockRepo) ListHistory(_ context.Context, _ repository.AlertHistoryF
 }
 func (m *initMockRepo) DeleteHistory(_ context.Context) (int64, error)                         { return 0, nil }
 func (m *initMockRepo) DeleteHistoryBefore(_ context.Context, _ time.Time) (int64, error)      { return 0, nil }
+func (m *initMockRepo) ListOpenAlerts(_ context.Context) ([]entities.AlertHistory, error) {
+	return nil, nil
+}
+func (m *initMockRepo) AcknowledgeAlert(_ context.Context, _ uint, _ time.Time) error   { return nil }
+func (m *initMockRepo) MarkAlertEscalated(_ context.Context, _ uint, _ time.Time) error { return nil }
+func (m *initMockRepo) ResolveAlert(_ context.Context, _ uint, _ time.Time) error       { return nil }
 func (m *initMockRepo) CountRulesByName(_ context.Context, _ string) (int64, error)            { return 0, nil }
 
 func initTestLogger() logger.Logger {
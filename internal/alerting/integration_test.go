@@ -95,6 +95,12 @@ func (r *integrationRepo) ListHistory(_ context.Context, _ repository.AlertHisto
 
 func (r *integrationRepo) DeleteHistory(_ context.Context) (int64, error)                         { return 0, nil }
 func (r *integrationRepo) DeleteHistoryBefore(_ context.Context, _ time.Time) (int64, error)      { return 0, nil }
+func (r *integrationRepo) ListOpenAlerts(_ context.Context) ([]entities.AlertHistory, error) {
+	return nil, nil
+}
+func (r *integrationRepo) AcknowledgeAlert(_ context.Context, _ uint, _ time.Time) error   { return nil }
+func (r *integrationRepo) MarkAlertEscalated(_ context.Context, _ uint, _ time.Time) error { return nil }
+func (r *integrationRepo) ResolveAlert(_ context.Context, _ uint, _ time.Time) error       { return nil }
 func (r *integrationRepo) CountRulesByName(_ context.Context, _ string) (int64, error)            { return 0, nil }
 
 func (r *integrationRepo) historyCount() int {
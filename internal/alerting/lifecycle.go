@@ -0,0 +1,370 @@
+package alerting
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// monitorInterval is how often absence rules and escalations are evaluated.
+const monitorInterval = 1 * time.Minute
+
+// recoveryEvents maps failure events to the events that resolve them.
+var recoveryEvents = map[string][]string{
+	EventStreamDisconnected: {EventStreamConnected},
+	EventStreamError:        {EventStreamConnected},
+	EventMQTTDisconnected:   {EventMQTTConnected},
+	EventDeviceStopped:      {EventDeviceStarted},
+	EventDeviceError:        {EventDeviceStarted},
+	EventDeviceHealthIssue:  {EventDeviceHealthRecovered},
+}
+
+// subjectProperties identify what an event is about. A recovery event only
+// resolves an alert raised for the same stream, device, broker or health
+// issue, and one rule can have an open alert per subject.
+var subjectProperties = []string{PropertyStreamURL, PropertyStreamName, PropertyDeviceName, PropertyBroker, PropertySource, PropertyIssue}
+
+// openAlertKey identifies an open alert by rule and subject.
+type openAlertKey struct {
+	ruleID  uint
+	subject string
+}
+
+// openAlert is an alert that fired and has not been resolved yet.
+type openAlert struct {
+	historyID    uint
+	rule         entities.AlertRule
+	event        *AlertEvent
+	firedAt      time.Time
+	acknowledged bool
+	escalated    bool
+}
+
+// isStateful reports whether firings of rule are tracked until resolved.
+// Rules with a recovery signal resolve automatically; rules with escalation
+// stay open until acknowledged and resolved.
+func isStateful(rule *entities.AlertRule) bool {
+	if rule.EscalateAfterMin > 0 {
+		return true
+	}
+	switch rule.TriggerType {
+	case TriggerTypeMetric, TriggerTypeAbsence:
+		return true
+	case TriggerTypeEvent:
+		_, ok := recoveryEvents[rule.EventName]
+		return ok
+	default:
+		return false
+	}
+}
+
+// subjectKey returns the subject of an event built from its identifying properties.
+func subjectKey(properties map[string]any) string {
+	var parts []string
+	for _, name := range subjectProperties {
+		if v, ok := properties[name]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%v", name, v))
+		}
+	}
+	return strings.Join(parts, ";")
+}
+
+// sameSubject reports whether two events concern the same subject. Only
+// identifying properties present on both events are compared.
+func sameSubject(fired, recovered map[string]any) bool {
+	for _, name := range subjectProperties {
+		a, okA := fired[name]
+		b, okB := recovered[name]
+		if okA && okB && fmt.Sprintf("%v", a) != fmt.Sprintf("%v", b) {
+			return false
+		}
+	}
+	return true
+}
+
+// recovers reports whether event resolves an alert that rule raised for fired.
+func recovers(rule *entities.AlertRule, fired, event *AlertEvent) bool {
+	if event.ObjectType != rule.ObjectType {
+		return false
+	}
+	switch rule.TriggerType {
+	case TriggerTypeMetric:
+		return event.MetricName == rule.MetricName && !metricConditionsHold(rule.Conditions, event.Properties)
+	case TriggerTypeAbsence:
+		return event.EventName == rule.EventName && EvaluateConditions(rule.Conditions, event.Properties)
+	case TriggerTypeEvent:
+		return slices.Contains(recoveryEvents[rule.EventName], event.EventName) &&
+			sameSubject(fired.Properties, event.Properties)
+	default:
+		return false
+	}
+}
+
+// metricConditionsHold checks metric conditions against the current sample,
+// ignoring their sustain durations.
+func metricConditionsHold(conditions []entities.AlertCondition, properties map[string]any) bool {
+	for i := range conditions {
+		if !evaluateCondition(&conditions[i], properties) {
+			return false
+		}
+	}
+	return true
+}
+
+// SetLifecycleActions sets the callbacks used when an alert resolves and when
+// an unacknowledged alert is escalated.
+func (e *Engine) SetLifecycleActions(resolved, escalated ActionFunc) {
+	e.openMu.Lock()
+	defer e.openMu.Unlock()
+	e.resolveFunc = resolved
+	e.escalateFunc = escalated
+}
+
+// isOpen reports whether a rule has an open alert for subject.
+func (e *Engine) isOpen(ruleID uint, subject string) bool {
+	e.openMu.Lock()
+	defer e.openMu.Unlock()
+	_, ok := e.open[openAlertKey{ruleID: ruleID, subject: subject}]
+	return ok
+}
+
+// trackOpenAlert records a stateful firing.
+func (e *Engine) trackOpenAlert(rule *entities.AlertRule, event *AlertEvent, history *entities.AlertHistory) {
+	e.openMu.Lock()
+	defer e.openMu.Unlock()
+	e.open[openAlertKey{ruleID: rule.ID, subject: subjectKey(event.Properties)}] = &openAlert{
+		historyID: history.ID,
+		rule:      *rule,
+		event:     event,
+		firedAt:   history.FiredAt,
+	}
+}
+
+// LoadOpenAlerts restores alerts left open by a previous run. Call after
+// RefreshRules. Alerts of rules that are no longer enabled are resolved.
+func (e *Engine) LoadOpenAlerts(ctx context.Context) error {
+	items, err := e.repo.ListOpenAlerts(ctx)
+	if err != nil {
+		return err
+	}
+
+	e.rulesMu.RLock()
+	rules := make(map[uint]entities.AlertRule, len(e.rules))
+	for i := range e.rules {
+		rules[e.rules[i].ID] = e.rules[i]
+	}
+	e.rulesMu.RUnlock()
+
+	now := time.Now()
+	for i := range items {
+		item := &items[i]
+		rule, ok := rules[item.RuleID]
+		var props map[string]any
+		if ok {
+			if err := json.Unmarshal([]byte(item.EventData), &props); err != nil {
+				props = map[string]any{}
+			}
+		}
+
+		e.openMu.Lock()
+		key := openAlertKey{ruleID: item.RuleID, subject: subjectKey(props)}
+		_, duplicate := e.open[key]
+		if ok && !duplicate {
+			e.open[key] = &openAlert{
+				historyID: item.ID,
+				rule:      rule,
+				event: &AlertEvent{
+					ObjectType: rule.ObjectType,
+					EventName:  rule.EventName,
+					MetricName: rule.MetricName,
+					Properties: props,
+					Timestamp:  item.FiredAt,
+				},
+				firedAt:      item.FiredAt,
+				acknowledged: item.Status == repository.AlertStatusAcknowledged,
+				escalated:    item.EscalatedAt != nil,
+			}
+		}
+		e.openMu.Unlock()
+
+		// Items are newest first, so older duplicates are superseded
+		if !ok || duplicate {
+			if err := e.repo.ResolveAlert(ctx, item.ID, now); err != nil {
+				e.log.Warn("failed to resolve stale alert",
+					logger.Uint64("alert_id", uint64(item.ID)), logger.Error(err))
+			}
+		}
+	}
+	return nil
+}
+
+// syncOpenAlerts updates open alerts with reloaded rules and resolves, without
+// notification, alerts whose rule was disabled or deleted.
+func (e *Engine) syncOpenAlerts(rules []entities.AlertRule) {
+	byID := make(map[uint]*entities.AlertRule, len(rules))
+	for i := range rules {
+		byID[rules[i].ID] = &rules[i]
+	}
+
+	var stale []*openAlert
+	e.openMu.Lock()
+	for key, alert := range e.open {
+		if rule, ok := byID[key.ruleID]; ok {
+			alert.rule = *rule
+			continue
+		}
+		delete(e.open, key)
+		stale = append(stale, alert)
+	}
+	e.openMu.Unlock()
+
+	for _, alert := range stale {
+		e.persistResolved(alert)
+	}
+}
+
+// resolveRecovered resolves open alerts that event shows have recovered.
+func (e *Engine) resolveRecovered(event *AlertEvent) {
+	var resolved []*openAlert
+	e.openMu.Lock()
+	for key, alert := range e.open {
+		if recovers(&alert.rule, alert.event, event) {
+			delete(e.open, key)
+			resolved = append(resolved, alert)
+		}
+	}
+	resolveFunc := e.resolveFunc
+	e.openMu.Unlock()
+
+	for _, alert := range resolved {
+		e.persistResolved(alert)
+		e.log.Info("alert resolved",
+			logger.String("rule", alert.rule.Name),
+			logger.Uint64("alert_id", uint64(alert.historyID)))
+		if resolveFunc != nil {
+			resolveFunc(&alert.rule, event)
+		}
+	}
+}
+
+func (e *Engine) persistResolved(alert *openAlert) {
+	if alert.historyID == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), saveHistoryTimeout)
+	defer cancel()
+	if err := e.repo.ResolveAlert(ctx, alert.historyID, time.Now()); err != nil {
+		e.log.Error("failed to resolve alert",
+			logger.Uint64("alert_id", uint64(alert.historyID)), logger.Error(err))
+	}
+}
+
+// Acknowledge marks an open alert as acknowledged, which stops escalation.
+// It returns repository.ErrOpenAlertNotFound when the alert is not firing.
+func (e *Engine) Acknowledge(ctx context.Context, alertID uint) error {
+	if err := e.repo.AcknowledgeAlert(ctx, alertID, time.Now()); err != nil {
+		return err
+	}
+	e.openMu.Lock()
+	defer e.openMu.Unlock()
+	for _, alert := range e.open {
+		if alert.historyID == alertID {
+			alert.acknowledged = true
+		}
+	}
+	return nil
+}
+
+// Resolve manually resolves an open alert without sending a notification.
+// It returns repository.ErrOpenAlertNotFound when the alert is not open.
+func (e *Engine) Resolve(ctx context.Context, alertID uint) error {
+	if err := e.repo.ResolveAlert(ctx, alertID, time.Now()); err != nil {
+		return err
+	}
+	e.openMu.Lock()
+	defer e.openMu.Unlock()
+	for key, alert := range e.open {
+		if alert.historyID == alertID {
+			delete(e.open, key)
+		}
+	}
+	return nil
+}
+
+// EvaluateEscalations re-notifies the escalation target of alerts that have
+// stayed unacknowledged for their rule's EscalateAfterMin. Each alert is
+// escalated once.
+func (e *Engine) EvaluateEscalations(now time.Time) {
+	var due []*openAlert
+	e.openMu.Lock()
+	for _, alert := range e.open {
+		after := time.Duration(alert.rule.EscalateAfterMin) * time.Minute
+		if after <= 0 || alert.acknowledged || alert.escalated || now.Sub(alert.firedAt) < after {
+			continue
+		}
+		alert.escalated = true
+		due = append(due, alert)
+	}
+	escalateFunc := e.escalateFunc
+	e.openMu.Unlock()
+
+	for _, alert := range due {
+		if alert.historyID != 0 {
+			ctx, cancel := context.WithTimeout(context.Background(), saveHistoryTimeout)
+			if err := e.repo.MarkAlertEscalated(ctx, alert.historyID, now); err != nil {
+				e.log.Error("failed to record alert escalation",
+					logger.Uint64("alert_id", uint64(alert.historyID)), logger.Error(err))
+			}
+			cancel()
+		}
+		e.log.Info("escalating unacknowledged alert",
+			logger.String("rule", alert.rule.Name),
+			logger.String("target", alert.rule.EscalateTarget))
+		if escalateFunc != nil {
+			escalateFunc(&alert.rule, alert.event)
+		}
+	}
+}
+
+// StartMonitor starts a background goroutine that evaluates absence rules
+// and escalations every minute until Stop is called.
+func (e *Engine) StartMonitor() {
+	e.stopMonitor()
+	e.rulesMu.Lock()
+	e.monitorStop = make(chan struct{})
+	stopCh := e.monitorStop
+	e.rulesMu.Unlock()
+
+	go func() {
+		ticker := time.NewTicker(monitorInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case now := <-ticker.C:
+				e.EvaluateAbsence(now)
+				e.EvaluateEscalations(now)
+			case <-stopCh:
+				return
+			}
+		}
+	}()
+	e.log.Debug("alert monitor started", logger.Duration("interval", monitorInterval))
+}
+
+// stopMonitor signals the monitor goroutine to exit.
+func (e *Engine) stopMonitor() {
+	e.rulesMu.Lock()
+	ch := e.monitorStop
+	e.monitorStop = nil
+	e.rulesMu.Unlock()
+	if ch != nil {
+		close(ch)
+	}
+}
@@ -0,0 +1,196 @@
+package alerting
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+)
+
+// lifecycleRecorder records fired, resolved and escalated rule IDs.
+type lifecycleRecorder struct {
+	mu                         sync.Mutex
+	fired, resolved, escalated []uint
+}
+
+func (r *lifecycleRecorder) record(list *[]uint) ActionFunc {
+	return func(rule *entities.AlertRule, _ *AlertEvent) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		*list = append(*list, rule.ID)
+	}
+}
+
+func (r *lifecycleRecorder) counts() (fired, resolved, escalated int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.fired), len(r.resolved), len(r.escalated)
+}
+
+func newLifecycleEngine(t *testing.T, rules ...entities.AlertRule) (*Engine, *mockAlertRuleRepo, *lifecycleRecorder) {
+	t.Helper()
+	repo := newMockRepo(rules...)
+	rec := &lifecycleRecorder{}
+	engine := NewEngine(repo, rec.record(&rec.fired), testLogger())
+	engine.SetLifecycleActions(rec.record(&rec.resolved), rec.record(&rec.escalated))
+	require.NoError(t, engine.RefreshRules(t.Context()))
+	return engine, repo, rec
+}
+
+func streamEvent(name, url string) *AlertEvent {
+	return &AlertEvent{
+		ObjectType: ObjectTypeStream,
+		EventName:  name,
+		Properties: map[string]any{PropertyStreamURL: url},
+		Timestamp:  time.Now(),
+	}
+}
+
+func healthEvent(name, issue string) *AlertEvent {
+	return &AlertEvent{
+		ObjectType: ObjectTypeDevice,
+		EventName:  name,
+		Properties: map[string]any{PropertySource: "audio_card_001", PropertyIssue: issue, PropertyValue: 0.8},
+		Timestamp:  time.Now(),
+	}
+}
+
+func TestLifecycle_MicHealthRecoveryMatchesIssue(t *testing.T) {
+	rule := entities.AlertRule{
+		ID: 1, Name: "Mic health", Enabled: true, ObjectType: ObjectTypeDevice,
+		TriggerType: TriggerTypeEvent, EventName: EventDeviceHealthIssue,
+	}
+	engine, _, rec := newLifecycleEngine(t, rule)
+
+	engine.HandleEvent(healthEvent(EventDeviceHealthIssue, "hum"))
+	engine.HandleEvent(healthEvent(EventDeviceHealthIssue, "clipping"))
+	fired, _, _ := rec.counts()
+	assert.Equal(t, 2, fired, "each issue has its own alert")
+
+	engine.HandleEvent(healthEvent(EventDeviceHealthRecovered, "hum"))
+	_, resolved, _ := rec.counts()
+	assert.Equal(t, 1, resolved, "recovery resolves only the matching issue")
+	assert.True(t, engine.isOpen(1, "source=audio_card_001;issue=clipping"))
+}
+
+func TestLifecycle_StreamReconnectResolves(t *testing.T) {
+	rule := entities.AlertRule{
+		ID: 1, Name: "Stream down", Enabled: true, ObjectType: ObjectTypeStream,
+		TriggerType: TriggerTypeEvent, EventName: EventStreamDisconnected,
+	}
+	engine, repo, rec := newLifecycleEngine(t, rule)
+
+	engine.HandleEvent(streamEvent(EventStreamDisconnected, "rtsp://a"))
+	engine.HandleEvent(streamEvent(EventStreamDisconnected, "rtsp://a"))
+	fired, _, _ := rec.counts()
+	assert.Equal(t, 1, fired, "open alert is not re-notified")
+	assert.Equal(t, repository.AlertStatusFiring, repo.history[0].Status)
+
+	// Another stream has its own alert
+	engine.HandleEvent(streamEvent(EventStreamDisconnected, "rtsp://b"))
+	fired, _, _ = rec.counts()
+	assert.Equal(t, 2, fired)
+
+	// Reconnect of stream a resolves only its alert
+	engine.HandleEvent(streamEvent(EventStreamConnected, "rtsp://a"))
+	_, resolved, _ := rec.counts()
+	assert.Equal(t, 1, resolved)
+	assert.Equal(t, repository.AlertStatusResolved, repo.history[0].Status)
+	assert.Equal(t, repository.AlertStatusFiring, repo.history[1].Status)
+
+	// After resolution the rule can fire again
+	engine.HandleEvent(streamEvent(EventStreamDisconnected, "rtsp://a"))
+	fired, _, _ = rec.counts()
+	assert.Equal(t, 3, fired)
+}
+
+func TestLifecycle_MetricRecoveryResolves(t *testing.T) {
+	rule := entities.AlertRule{
+		ID: 1, Name: "CPU", Enabled: true, ObjectType: ObjectTypeSystem,
+		TriggerType: TriggerTypeMetric, MetricName: MetricCPUUsage,
+		Conditions: []entities.AlertCondition{{Property: PropertyValue, Operator: OperatorGreaterThan, Value: "90"}},
+	}
+	engine, _, rec := newLifecycleEngine(t, rule)
+	sample := func(v float64) *AlertEvent {
+		return &AlertEvent{ObjectType: ObjectTypeSystem, MetricName: MetricCPUUsage,
+			Properties: map[string]any{PropertyValue: v}, Timestamp: time.Now()}
+	}
+
+	engine.HandleEvent(sample(95))
+	engine.HandleEvent(sample(97))
+	fired, resolved, _ := rec.counts()
+	assert.Equal(t, 1, fired)
+	assert.Zero(t, resolved)
+
+	engine.HandleEvent(sample(40))
+	_, resolved, _ = rec.counts()
+	assert.Equal(t, 1, resolved)
+}
+
+func TestLifecycle_EscalationUnlessAcknowledged(t *testing.T) {
+	rule := entities.AlertRule{
+		ID: 1, Name: "Stream down", Enabled: true, ObjectType: ObjectTypeStream,
+		TriggerType: TriggerTypeEvent, EventName: EventStreamDisconnected,
+		EscalateAfterMin: 15, EscalateTarget: "pager",
+	}
+	engine, repo, rec := newLifecycleEngine(t, rule)
+
+	engine.HandleEvent(streamEvent(EventStreamDisconnected, "rtsp://a"))
+	engine.HandleEvent(streamEvent(EventStreamDisconnected, "rtsp://b"))
+	now := time.Now()
+
+	engine.EvaluateEscalations(now.Add(10 * time.Minute))
+	_, _, escalated := rec.counts()
+	assert.Zero(t, escalated, "not yet due")
+
+	require.NoError(t, engine.Acknowledge(t.Context(), repo.history[0].ID))
+	require.ErrorIs(t, engine.Acknowledge(t.Context(), repo.history[0].ID), repository.ErrOpenAlertNotFound)
+
+	engine.EvaluateEscalations(now.Add(16 * time.Minute))
+	engine.EvaluateEscalations(now.Add(30 * time.Minute))
+	_, _, escalated = rec.counts()
+	assert.Equal(t, 1, escalated, "only the unacknowledged alert escalates, once")
+	assert.NotNil(t, repo.history[1].EscalatedAt)
+}
+
+func TestLifecycle_ManualResolveAndRestore(t *testing.T) {
+	rule := entities.AlertRule{
+		ID: 1, Name: "Stream down", Enabled: true, ObjectType: ObjectTypeStream,
+		TriggerType: TriggerTypeEvent, EventName: EventStreamDisconnected,
+	}
+	engine, repo, rec := newLifecycleEngine(t, rule)
+	engine.HandleEvent(streamEvent(EventStreamDisconnected, "rtsp://a"))
+
+	// A restarted engine restores the open alert and keeps suppressing it
+	restarted := NewEngine(repo, rec.record(&rec.fired), testLogger())
+	require.NoError(t, restarted.RefreshRules(t.Context()))
+	require.NoError(t, restarted.LoadOpenAlerts(t.Context()))
+	restarted.HandleEvent(streamEvent(EventStreamDisconnected, "rtsp://a"))
+	fired, _, _ := rec.counts()
+	assert.Equal(t, 1, fired)
+
+	require.NoError(t, restarted.Resolve(t.Context(), repo.history[0].ID))
+	restarted.HandleEvent(streamEvent(EventStreamDisconnected, "rtsp://a"))
+	fired, _, _ = rec.counts()
+	assert.Equal(t, 2, fired)
+}
+
+func TestLifecycle_NonStatefulRulesStayStateless(t *testing.T) {
+	rule := entities.AlertRule{
+		ID: 1, Enabled: true, ObjectType: ObjectTypeDetection,
+		TriggerType: TriggerTypeEvent, EventName: EventDetectionOccurred,
+	}
+	engine, repo, rec := newLifecycleEngine(t, rule)
+	event := &AlertEvent{ObjectType: ObjectTypeDetection, EventName: EventDetectionOccurred,
+		Properties: map[string]any{}, Timestamp: time.Now()}
+
+	engine.HandleEvent(event)
+	engine.HandleEvent(event)
+	fired, _, _ := rec.counts()
+	assert.Equal(t, 2, fired)
+	assert.Empty(t, repo.history[0].Status)
+}
@@ -1,9 +1,13 @@
 package alerting
 
+import "slices"
+
 // Schema describes the full catalog of alertable object types, events, and metrics.
 type Schema struct {
 	ObjectTypes []ObjectTypeSchema `json:"objectTypes"`
 	Operators   []OperatorSchema   `json:"operators"`
+	// Groups lists the logic available for nesting conditions.
+	Groups []OperatorSchema `json:"groups"`
 }
 
 // ObjectTypeSchema describes an object type and its available triggers.
@@ -12,6 +16,8 @@ type ObjectTypeSchema struct {
 	Label   string         `json:"label"`
 	Events  []EventSchema  `json:"events,omitempty"`
 	Metrics []MetricSchema `json:"metrics,omitempty"`
+	// Absence lists events that absence triggers can watch for silence.
+	Absence []EventSchema `json:"absence,omitempty"`
 }
 
 // EventSchema describes an event trigger and its available properties.
@@ -33,7 +39,7 @@ type MetricSchema struct {
 type PropertySchema struct {
 	Name      string   `json:"name"`
 	Label     string   `json:"label"`
-	Type      string   `json:"type"` // "string" or "number"
+	Type      string   `json:"type"` // "string", "number", or "time"
 	Operators []string `json:"operators"`
 }
 
@@ -41,18 +47,27 @@ type PropertySchema struct {
 type OperatorSchema struct {
 	Name  string `json:"name"`
 	Label string `json:"label"`
-	Type  string `json:"type"` // "string", "number", or "all"
+	Type  string `json:"type"` // "string", "number", "time", or "all"
 }
 
 // stringOperators are operators valid for string properties.
-var stringOperators = []string{OperatorIs, OperatorIsNot, OperatorContains, OperatorNotContains}
+var stringOperators = []string{
+	OperatorIs, OperatorIsNot, OperatorContains, OperatorNotContains,
+	OperatorIn, OperatorNotIn, OperatorMatches,
+}
+
+// scientificNameOperators add taxonomic group membership to the string operators.
+var scientificNameOperators = append(slices.Clone(stringOperators), OperatorInGroup)
+
+// timeOperators are operators valid for time of day properties.
+var timeOperators = []string{OperatorTimeBetween}
 
 // numericOperators are operators valid for numeric properties.
 var numericOperators = []string{OperatorGreaterThan, OperatorLessThan, OperatorGreaterOrEqual, OperatorLessOrEqual}
 
 // GetSchema returns the full alerting schema for the UI.
 func GetSchema() Schema {
-	return Schema{
+	schema := Schema{
 		ObjectTypes: []ObjectTypeSchema{
 			{
 				Name:  ObjectTypeStream,
@@ -70,6 +85,9 @@ func GetSchema() Schema {
 					{Name: EventDetectionNewSpecies, Label: "New Species Detected", Properties: detectionProperties()},
 					{Name: EventDetectionOccurred, Label: "Detection Occurred", Properties: detectionProperties()},
 				},
+				Absence: []EventSchema{
+					{Name: EventDetectionOccurred, Label: "No Detections", Properties: detectionProperties()},
+				},
 			},
 			{
 				Name:  ObjectTypeApplication,
@@ -95,6 +113,11 @@ func GetSchema() Schema {
 					{Name: EventDeviceStarted, Label: "Device Started", Properties: deviceProperties()},
 					{Name: EventDeviceStopped, Label: "Device Stopped", Properties: deviceProperties()},
 					{Name: EventDeviceError, Label: "Device Error", Properties: deviceErrorProperties()},
+					{Name: EventDeviceHealthIssue, Label: "Microphone Health Issue", Properties: deviceHealthProperties()},
+					{Name: EventDeviceHealthRecovered, Label: "Microphone Health Recovered", Properties: deviceHealthProperties()},
+				},
+				Absence: []EventSchema{
+					{Name: EventDeviceSoundLevel, Label: "No Sound Level Updates", Properties: soundLevelProperties()},
 				},
 			},
 			{
@@ -116,8 +139,27 @@ func GetSchema() Schema {
 			{Name: OperatorLessThan, Label: "less than", Type: "number"},
 			{Name: OperatorGreaterOrEqual, Label: "greater or equal", Type: "number"},
 			{Name: OperatorLessOrEqual, Label: "less or equal", Type: "number"},
+			{Name: OperatorIn, Label: "is one of", Type: "string"},
+			{Name: OperatorNotIn, Label: "is not one of", Type: "string"},
+			{Name: OperatorMatches, Label: "matches regex", Type: "string"},
+			{Name: OperatorInGroup, Label: "in taxonomic group", Type: "string"},
+			{Name: OperatorTimeBetween, Label: "between", Type: "time"},
+		},
+		Groups: []OperatorSchema{
+			{Name: LogicAnd, Label: "all of", Type: "all"},
+			{Name: LogicOr, Label: "any of", Type: "all"},
+			{Name: LogicNot, Label: "not all of", Type: "all"},
 		},
 	}
+
+	// Every event carries the time of day it occurred at
+	for i := range schema.ObjectTypes {
+		events := schema.ObjectTypes[i].Events
+		for j := range events {
+			events[j].Properties = append(events[j].Properties, timeOfDayProperty())
+		}
+	}
+	return schema
 }
 
 func streamProperties() []PropertySchema {
@@ -136,7 +178,7 @@ func streamErrorProperties() []PropertySchema {
 func detectionProperties() []PropertySchema {
 	return []PropertySchema{
 		{Name: PropertySpeciesName, Label: "Species Name", Type: "string", Operators: stringOperators},
-		{Name: PropertyScientificName, Label: "Scientific Name", Type: "string", Operators: stringOperators},
+		{Name: PropertyScientificName, Label: "Scientific Name", Type: "string", Operators: scientificNameOperators},
 		{Name: PropertyConfidence, Label: "Confidence", Type: "number", Operators: numericOperators},
 		{Name: PropertyLocation, Label: "Location", Type: "string", Operators: stringOperators},
 	}
@@ -160,6 +202,19 @@ func deviceProperties() []PropertySchema {
 	}
 }
 
+func soundLevelProperties() []PropertySchema {
+	return append(deviceProperties(),
+		PropertySchema{Name: PropertySource, Label: "Source ID", Type: "string", Operators: stringOperators},
+	)
+}
+
+func deviceHealthProperties() []PropertySchema {
+	return append(soundLevelProperties(),
+		PropertySchema{Name: PropertyIssue, Label: "Issue", Type: "string", Operators: stringOperators},
+		PropertySchema{Name: PropertyValue, Label: "Measured Value", Type: "number", Operators: numericOperators},
+	)
+}
+
 func deviceErrorProperties() []PropertySchema {
 	return append(deviceProperties(),
 		PropertySchema{Name: PropertyError, Label: "Error Message", Type: "string", Operators: stringOperators},
@@ -171,3 +226,7 @@ func numericValueProperties() []PropertySchema {
 		{Name: PropertyValue, Label: "Value", Type: "number", Operators: numericOperators},
 	}
 }
+
+func timeOfDayProperty() PropertySchema {
+	return PropertySchema{Name: PropertyTimeOfDay, Label: "Time of Day", Type: "time", Operators: timeOperators}
+}
@@ -1,6 +1,7 @@
 package alerting
 
 import (
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -33,10 +34,22 @@ func TestGetSchema_AllEventsPresent(t *testing.T) {
 		EventApplicationStarted, EventApplicationStopped,
 		EventBirdWeatherFailed, EventMQTTConnected, EventMQTTDisconnected,
 		EventDeviceStarted, EventDeviceStopped, EventDeviceError,
+		EventDeviceHealthIssue, EventDeviceHealthRecovered,
 	}
 	assert.ElementsMatch(t, expectedEvents, allEvents)
 }
 
+func TestGetSchema_AbsenceSignals(t *testing.T) {
+	schema := GetSchema()
+	var signals []string
+	for _, ot := range schema.ObjectTypes {
+		for _, ev := range ot.Absence {
+			signals = append(signals, ev.Name)
+		}
+	}
+	assert.ElementsMatch(t, []string{EventDetectionOccurred, EventDeviceSoundLevel}, signals)
+}
+
 func TestGetSchema_AllMetricsPresent(t *testing.T) {
 	schema := GetSchema()
 	var allMetrics []string
@@ -57,14 +70,33 @@ func TestGetSchema_AllOperatorsPresent(t *testing.T) {
 	assert.ElementsMatch(t, []string{
 		OperatorIs, OperatorIsNot, OperatorContains, OperatorNotContains,
 		OperatorGreaterThan, OperatorLessThan, OperatorGreaterOrEqual, OperatorLessOrEqual,
+		OperatorIn, OperatorNotIn, OperatorMatches, OperatorInGroup, OperatorTimeBetween,
 	}, names)
 }
 
+func TestGetSchema_GroupsAndTimeOfDay(t *testing.T) {
+	schema := GetSchema()
+	groups := make([]string, len(schema.Groups))
+	for i, g := range schema.Groups {
+		groups[i] = g.Name
+	}
+	assert.ElementsMatch(t, []string{LogicAnd, LogicOr, LogicNot}, groups)
+
+	for _, ot := range schema.ObjectTypes {
+		for _, ev := range ot.Events {
+			idx := slices.IndexFunc(ev.Properties, func(p PropertySchema) bool { return p.Name == PropertyTimeOfDay })
+			require.GreaterOrEqual(t, idx, 0, "event %s lacks time of day", ev.Name)
+			assert.Equal(t, []string{OperatorTimeBetween}, ev.Properties[idx].Operators)
+		}
+	}
+}
+
 func TestGetSchema_PropertiesHaveValidOperators(t *testing.T) {
 	schema := GetSchema()
 	validOps := map[string]bool{
 		OperatorIs: true, OperatorIsNot: true, OperatorContains: true, OperatorNotContains: true,
 		OperatorGreaterThan: true, OperatorLessThan: true, OperatorGreaterOrEqual: true, OperatorLessOrEqual: true,
+		OperatorIn: true, OperatorNotIn: true, OperatorMatches: true, OperatorInGroup: true, OperatorTimeBetween: true,
 	}
 	for _, ot := range schema.ObjectTypes {
 		for _, ev := range ot.Events {
@@ -0,0 +1,85 @@
+package alerting
+
+import (
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// ValidateRule checks a rule's trigger-specific fields and conditions before it
+// is stored.
+func ValidateRule(rule *entities.AlertRule) error {
+	if err := ValidateAbsenceRule(rule); err != nil {
+		return err
+	}
+	if rule.EscalateAfterMin < 0 || (rule.EscalateAfterMin > 0 && rule.EscalateTarget == "") {
+		return errors.Newf("escalation requires a positive delay and a target").
+			Component("alerting").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "alert-escalation").
+			Build()
+	}
+	if rule.TriggerType == TriggerTypeMetric && hasConditionGroups(rule.Conditions) {
+		return conditionGroupError("condition groups are not supported for metric triggers")
+	}
+	return ValidateConditions(rule.Conditions)
+}
+
+// ValidateConditions checks the group structure and operator values of a
+// condition list.
+func ValidateConditions(conditions []entities.AlertCondition) error {
+	if hasConditionGroups(conditions) {
+		root, err := buildConditionTree(conditions)
+		if err != nil {
+			return err
+		}
+		if err := validateGroupsNotEmpty(root); err != nil {
+			return err
+		}
+	}
+
+	for i := range conditions {
+		cond := &conditions[i]
+		if cond.Logic != "" {
+			continue
+		}
+		if err := validateConditionValue(cond); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateGroupsNotEmpty(node *conditionNode) error {
+	for _, child := range node.children {
+		if child.cond.Logic == "" {
+			continue
+		}
+		if len(child.children) == 0 {
+			return conditionGroupError("condition group %d has no conditions", child.cond.NodeKey)
+		}
+		if err := validateGroupsNotEmpty(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateConditionValue(cond *entities.AlertCondition) error {
+	switch cond.Operator {
+	case OperatorMatches:
+		_, err := compileConditionRegex(cond.Value)
+		return err
+	case OperatorTimeBetween:
+		_, _, err := parseTimeRange(cond.Value)
+		return err
+	case OperatorIn, OperatorNotIn, OperatorInGroup:
+		if len(splitList(cond.Value)) == 0 {
+			return errors.Newf("operator %s requires at least one value", cond.Operator).
+				Component("alerting").
+				Category(errors.CategoryValidation).
+				Context("validation_type", "alert-condition-list").
+				Build()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,59 @@
+package alerting
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+func TestValidateRule(t *testing.T) {
+	t.Parallel()
+
+	grouped := []entities.AlertCondition{
+		{Logic: LogicOr, NodeKey: 1},
+		{Property: PropertySpeciesName, Operator: OperatorIs, Value: "Robin", ParentKey: 1},
+	}
+
+	tests := []struct {
+		name    string
+		rule    entities.AlertRule
+		wantErr bool
+	}{
+		{"flat conditions", entities.AlertRule{TriggerType: TriggerTypeEvent, Conditions: []entities.AlertCondition{
+			{Property: PropertySpeciesName, Operator: OperatorIs, Value: "Robin"},
+		}}, false},
+		{"grouped event rule", entities.AlertRule{TriggerType: TriggerTypeEvent, Conditions: grouped}, false},
+		{"grouped metric rule", entities.AlertRule{TriggerType: TriggerTypeMetric, Conditions: grouped}, true},
+		{"empty group", entities.AlertRule{TriggerType: TriggerTypeEvent, Conditions: []entities.AlertCondition{
+			{Logic: LogicNot, NodeKey: 1},
+		}}, true},
+		{"invalid regex", entities.AlertRule{TriggerType: TriggerTypeEvent, Conditions: []entities.AlertCondition{
+			{Property: PropertySpeciesName, Operator: OperatorMatches, Value: "(["},
+		}}, true},
+		{"invalid time range", entities.AlertRule{TriggerType: TriggerTypeEvent, Conditions: []entities.AlertCondition{
+			{Property: PropertyTimeOfDay, Operator: OperatorTimeBetween, Value: "25:00-06:00"},
+		}}, true},
+		{"empty list", entities.AlertRule{TriggerType: TriggerTypeEvent, Conditions: []entities.AlertCondition{
+			{Property: PropertySpeciesName, Operator: OperatorIn, Value: " , "},
+		}}, true},
+		{"escalation without target", entities.AlertRule{TriggerType: TriggerTypeEvent, EscalateAfterMin: 10}, true},
+		{"escalation", entities.AlertRule{TriggerType: TriggerTypeEvent, EscalateAfterMin: 10, EscalateTarget: TargetPush}, false},
+		{"absence rule without window", entities.AlertRule{
+			TriggerType: TriggerTypeAbsence, EventName: EventDetectionOccurred,
+		}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := ValidateRule(&tt.rule)
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -179,12 +179,16 @@ func (cm *ControlMonitor) handleControlSignal(signal string) {
 		cm.handleReconfigureMQTT()
 	case "reconfigure_rtsp_sources":
 		cm.handleReconfigureStreams()
+	case "update_live_sources":
+		cm.handleUpdateLiveSources()
 	case "reconfigure_birdweather":
 		cm.handleReconfigureBirdWeather()
 	case "update_detection_intervals":
 		cm.handleUpdateDetectionIntervals()
 	case "reconfigure_sound_level":
 		cm.handleReconfigureSoundLevel()
+	case "reconfigure_audio_archive":
+		cm.handleReconfigureAudioArchive()
 	case "reconfigure_telemetry":
 		cm.handleReconfigureTelemetry()
 	case "reconfigure_species_tracking":
@@ -284,19 +288,17 @@ func (cm *ControlMonitor) handleReconfigureMQTT() {
 	}
 }
 
-// handleReconfigureStreams reconfigures audio streams
-func (cm *ControlMonitor) handleReconfigureStreams() {
-	GetLogger().Info("Reconfiguring audio streams")
-	settings := conf.Setting()
-
-	// Prepare the list of active sources (using source IDs, not raw URLs)
+// activeSourceIDs returns the IDs of all sources that need an analysis buffer
+// monitor: configured streams, the audio device, push ingest devices and
+// running live sessions
+func activeSourceIDs(settings *conf.Settings) []string {
 	var sources []string
 	if len(settings.Realtime.RTSP.Streams) > 0 {
 		registry := myaudio.GetRegistry()
 		if registry != nil {
 			for _, stream := range settings.Realtime.RTSP.Streams {
 				if streamSource := registry.GetOrCreateSource(stream.URL, myaudio.StreamTypeToSourceType(stream.Type)); streamSource != nil {
-					sources = append(sources, streamSource.ID)
+					sources = append(sources, channelSourceIDs(streamSource, stream.Channels)...)
 				} else {
 					GetLogger().Warn("Failed to get stream source ID from registry during reconfiguration",
 						logger.String("stream_name", stream.Name))
@@ -310,7 +312,7 @@ func (cm *ControlMonitor) handleReconfigureStreams() {
 		// Get the audio source from registry instead of hardcoded "malgo"
 		if registry := myaudio.GetRegistry(); registry != nil {
 			if audioSource := registry.GetOrCreateSource(settings.Realtime.Audio.Source, myaudio.SourceTypeAudioCard); audioSource != nil {
-				sources = append(sources, audioSource.ID)
+				sources = append(sources, channelSourceIDs(audioSource, settings.Realtime.Audio.Channels)...)
 			} else {
 				GetLogger().Warn("Failed to get audio source from registry during stream reconfiguration")
 			}
@@ -319,6 +321,25 @@ func (cm *ControlMonitor) handleReconfigureStreams() {
 		}
 	}
 
+	sources = append(sources, ingestSourceIDs(settings)...)
+	sources = append(sources, myaudio.LiveSessionSourceIDs()...)
+	return sources
+}
+
+// handleUpdateLiveSources starts and stops analysis of browser live sessions
+func (cm *ControlMonitor) handleUpdateLiveSources() {
+	if err := cm.bufferManager.UpdateMonitors(activeSourceIDs(conf.Setting())); err != nil {
+		GetLogger().Warn("Buffer monitor update for live sessions completed with errors", logger.Error(err))
+	}
+}
+
+// handleReconfigureStreams reconfigures audio streams
+func (cm *ControlMonitor) handleReconfigureStreams() {
+	GetLogger().Info("Reconfiguring audio streams")
+	settings := conf.Setting()
+
+	sources := activeSourceIDs(settings)
+
 	// Update the analysis buffer monitors
 	if err := cm.bufferManager.UpdateMonitors(sources); err != nil {
 		GetLogger().Warn("Buffer monitor update completed with errors", logger.Error(err))
@@ -478,6 +499,24 @@ func (cm *ControlMonitor) notifyError(message string, err error) {
 	GetLogger().Error(message, logger.Error(err))
 }
 
+// handleReconfigureAudioArchive restarts the continuous audio archive with the current settings
+func (cm *ControlMonitor) handleReconfigureAudioArchive() {
+	GetLogger().Info("Reconfiguring audio archive")
+	settings := conf.Setting()
+
+	if err := myaudio.StartAudioArchive(settings); err != nil {
+		GetLogger().Error("Failed to reconfigure audio archive", logger.Error(err))
+		cm.notifyError("Failed to reconfigure audio archive", err)
+		return
+	}
+
+	if settings.Realtime.Audio.Archive.Enabled {
+		cm.notifySuccess("Audio archive reconfigured")
+	} else {
+		cm.notifySuccess("Audio archive disabled")
+	}
+}
+
 // handleReconfigureSoundLevel reconfigures sound level monitoring
 func (cm *ControlMonitor) handleReconfigureSoundLevel() {
 	GetLogger().Info("Reconfiguring sound level monitoring")
@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/detection"
 	"github.com/tphakala/birdnet-go/internal/errors"
@@ -176,19 +177,33 @@ func (a *DatabaseAction) ExecuteContext(ctx context.Context, _ any) error {
 			}
 		}
 
-		// export audio clip from capture buffer
-		pcmData, err := myaudio.ReadSegmentFromCaptureBuffer(a.Result.AudioSource.ID, a.Result.BeginTime, captureLength)
+		// export audio clip from capture buffer, keeping all channels of multichannel sources
+		pcmData, channels, err := myaudio.ReadClipFromCaptureBuffer(a.Result.AudioSource.ID, a.Result.BeginTime, captureLength)
 		if err != nil {
 			handleAudioExportError(err,
 				logger.String("source", a.Result.AudioSource.SafeString),
 				logger.Time("begin_time", a.Result.BeginTime),
 				logger.Int("duration_seconds", captureLength))
 		} else {
+			// Reduce stationary noise in the clip when enabled for exports
+			if noiseReduction := &a.Settings.Realtime.Audio.NoiseReduction; noiseReduction.Clips {
+				if err := myaudio.ReduceInterleavedClipNoise(a.Result.AudioSource.ID, pcmData, channels, noiseReduction); err != nil {
+					GetLogger().Warn("Failed to reduce clip noise, saving unprocessed clip",
+						logger.String("component", "analysis.processor.actions"),
+						logger.String("detection_id", a.CorrelationID),
+						logger.Error(err),
+						logger.String("operation", "reduce_clip_noise"))
+				}
+			}
+
 			// Create a SaveAudioAction and execute it
 			saveAudioAction := &SaveAudioAction{
 				Settings:      a.Settings,
 				ClipName:      a.Result.ClipName,
 				pcmData:       pcmData,
+				channels:      channels,
+				sourceID:      a.Result.AudioSource.ID,
+				metadata:      clipMetadata(&a.Result, a.Settings),
 				NoteID:        a.Result.ID,
 				PreRenderer:   a.PreRenderer,
 				CorrelationID: a.CorrelationID,
@@ -297,6 +312,11 @@ func (a *DatabaseAction) populateEventMetadata(detectionEvent events.DetectionEv
 	metadata["longitude"] = a.Result.Longitude
 	metadata["begin_time"] = a.Result.BeginTime
 
+	// Clip name lets push providers attach the audio clip and spectrogram once exported
+	if a.Settings != nil && a.Settings.Realtime.Audio.Export.Enabled && a.Result.ClipName != "" {
+		metadata["clip_name"] = a.Result.ClipName
+	}
+
 	if a.processor != nil && a.processor.BirdImageCache != nil {
 		if birdImage, err := a.processor.BirdImageCache.Get(a.Result.Species.ScientificName); err == nil && birdImage.URL != "" {
 			metadata["image_url"] = birdImage.URL
@@ -351,6 +371,28 @@ func (a *DatabaseAction) publishNewSpeciesDetectionEvent(isNewSpecies bool, days
 	}
 }
 
+// clipMetadata returns the detection metadata embedded in an exported clip.
+// The clip starts at BeginTime, reported in the time zone of the detection.
+func clipMetadata(result *detection.Result, settings *conf.Settings) *myaudio.ClipMetadata {
+	meta := &myaudio.ClipMetadata{
+		CommonName:     result.Species.CommonName,
+		ScientificName: result.Species.ScientificName,
+		SpeciesCode:    result.Species.Code,
+		Confidence:     result.Confidence,
+		Timestamp:      result.Timestamp,
+		Latitude:       result.Latitude,
+		Longitude:      result.Longitude,
+		SourceName:     result.AudioSource.DisplayName,
+		ModelName:      result.Model.Name,
+		ModelVersion:   result.Model.Version,
+		AppVersion:     settings.Version,
+	}
+	if !result.BeginTime.IsZero() {
+		meta.RecordedAt = result.BeginTime.In(result.Timestamp.Location())
+	}
+	return meta
+}
+
 // Execute saves the audio clip to a file
 func (a *SaveAudioAction) Execute(_ context.Context, _ any) error {
 	// Get the full path by joining the export path with the relative clip name
@@ -362,12 +404,19 @@ func (a *SaveAudioAction) Execute(_ context.Context, _ any) error {
 		return err
 	}
 
-	if a.Settings.Realtime.Audio.Export.Type == "wav" {
-		if err := myaudio.SavePCMDataToWAV(outputPath, a.pcmData); err != nil {
+	channels := max(a.channels, 1)
+	switch a.Settings.Realtime.Audio.Export.Type {
+	case "wav":
+		if err := myaudio.SaveInterleavedPCMToWAV(outputPath, a.pcmData, channels, a.metadata); err != nil {
 			return err
 		}
-	} else {
-		if err := myaudio.ExportAudioWithFFmpeg(a.pcmData, outputPath, &a.Settings.Realtime.Audio); err != nil {
+	case "flac":
+		// FLAC is encoded in process, ffmpeg is only needed for lossy formats
+		if err := myaudio.ExportInterleavedAudioToFLAC(a.pcmData, outputPath, &a.Settings.Realtime.Audio, channels, a.metadata); err != nil {
+			return err
+		}
+	default:
+		if err := myaudio.ExportInterleavedAudioWithFFmpeg(a.pcmData, outputPath, &a.Settings.Realtime.Audio, channels, a.metadata); err != nil {
 			return err
 		}
 	}
@@ -400,8 +449,13 @@ func (a *SaveAudioAction) Execute(_ context.Context, _ any) error {
 	// Submit for pre-rendering if enabled
 	if a.Settings.Realtime.Dashboard.Spectrogram.Enabled && a.PreRenderer != nil {
 		// Create pre-render job using local DTO (avoids direct spectrogram dependency)
+		// Spectrograms are rendered from the channel the detection was made on
+		pcmData := a.pcmData
+		if channels > 1 {
+			pcmData = myaudio.SourceChannelPCM(a.sourceID, a.pcmData, channels)
+		}
 		job := PreRenderJob{
-			PCMData:   a.pcmData,
+			PCMData:   pcmData,
 			ClipPath:  outputPath, // Use full path to audio file
 			NoteID:    a.NoteID,
 			Timestamp: time.Now(),
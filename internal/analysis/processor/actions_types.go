@@ -18,6 +18,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/detection"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/mqtt"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
 )
 
 // Timeout and interval constants
@@ -103,8 +104,11 @@ type SaveAudioAction struct {
 	Settings      *conf.Settings
 	ClipName      string
 	pcmData       []byte
-	NoteID        uint              // Note ID for correlation logging with pre-renderer
-	PreRenderer   PreRendererSubmit // Injected from processor
+	channels      int                   // Channels interleaved in pcmData, mono when zero
+	sourceID      string                // Analysis source of the clip, selects the spectrogram channel
+	metadata      *myaudio.ClipMetadata // Detection metadata embedded in the clip, none when nil
+	NoteID        uint                  // Note ID for correlation logging with pre-renderer
+	PreRenderer   PreRendererSubmit     // Injected from processor
 	EventTracker  *EventTracker
 	Description   string
 	CorrelationID string // Detection correlation ID for log tracking
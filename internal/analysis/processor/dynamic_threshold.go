@@ -51,7 +51,7 @@ func (p *Processor) addSpeciesToDynamicThresholds(speciesLowercase, scientificNa
 		p.DynamicThresholds[speciesLowercase] = &DynamicThreshold{
 			Level:          0,
 			CurrentValue:   float64(baseThreshold),
-			Timer:          time.Now(),
+			Timer:          p.currentTime(),
 			HighConfCount:  0,
 			ValidHours:     p.Settings.Realtime.DynamicThreshold.ValidHours,
 			ScientificName: scientificName,
@@ -62,6 +62,15 @@ func (p *Processor) addSpeciesToDynamicThresholds(speciesLowercase, scientificNa
 	}
 }
 
+// currentTime returns the time of the processor clock. Simulations replay
+// stored detections on a clock set to their detection time.
+func (p *Processor) currentTime() time.Time {
+	if p.now != nil {
+		return p.now()
+	}
+	return time.Now()
+}
+
 // getAdjustedConfidenceThreshold returns the current dynamic threshold for a species.
 // This function does NOT trigger learning from detections - learning happens separately
 // in LearnFromApprovedDetection() when a detection is approved.
@@ -85,7 +94,7 @@ func (p *Processor) getAdjustedConfidenceThreshold(speciesLowercase string, base
 		return baseThreshold
 	}
 
-	now := time.Now()
+	now := p.currentTime()
 
 	// Check for expired thresholds and reset if needed
 	// Guard ensures we only reset if not already at base state (prevents redundant work)
@@ -187,7 +196,7 @@ func (p *Processor) LearnFromApprovedDetection(speciesLowercase, scientificName
 		return
 	}
 
-	now := time.Now()
+	now := p.currentTime()
 	previousLevel := dt.Level
 	previousValue := dt.CurrentValue
 
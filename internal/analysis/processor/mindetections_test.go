@@ -914,3 +914,23 @@ func TestValidateAndLogFilterConfig_InvalidLevel(t *testing.T) {
 		})
 	}
 }
+
+// TestMinDetectionsForOverlap verifies that the requirement for a lowered
+// overlap matches the settings based calculation with that overlap.
+func TestMinDetectionsForOverlap(t *testing.T) {
+	for level := range 6 {
+		for _, overlap := range []float64{0.0, 0.5, 1.0, 1.5, 2.0, 2.5} {
+			settings := &conf.Settings{
+				Realtime: conf.RealtimeSettings{
+					FalsePositiveFilter: conf.FalsePositiveFilterSettings{Level: level},
+				},
+				BirdNET: conf.BirdNETConfig{Overlap: overlap},
+			}
+			assert.Equal(t, calculateMinDetectionsFromSettings(settings), minDetectionsForOverlap(level, overlap),
+				"level %d, overlap %.1f", level, overlap)
+		}
+	}
+
+	// Fewer chunks per vocalization require fewer detections
+	assert.Less(t, minDetectionsForOverlap(5, 1.0), minDetectionsForOverlap(5, 2.5))
+}
@@ -68,7 +68,8 @@ type Processor struct {
 	LastHumanDetection  map[string]time.Time    // keep track of human vocal per audio source
 	Metrics             *observability.Metrics
 	DynamicThresholds   map[string]*DynamicThreshold
-	thresholdsMutex     sync.RWMutex // Mutex to protect access to DynamicThresholds
+	thresholdsMutex     sync.RWMutex     // Mutex to protect access to DynamicThresholds
+	now                 func() time.Time // Clock of the dynamic thresholds, nil for time.Now
 	pendingDetections   map[string]PendingDetection
 	pendingMutex        sync.Mutex // Mutex to protect access to pendingDetections
 	lastDogDetectionLog map[string]time.Time
@@ -1051,14 +1052,6 @@ func (p *Processor) processApprovedDetection(item *PendingDetection, speciesName
 func calculateMinDetectionsFromSettings(settings *conf.Settings) int {
 	// BirdNET uses 3-second chunks for analysis
 	const chunkDurationSeconds = 3.0
-	// Bird vocalization reference window - typical duration of a bird call
-	// Used to calculate how many detections are possible within a single vocalization
-	const referenceWindowSeconds = 6.0
-	// Minimum segment length to prevent division by near-zero values
-	const minSegmentLength = 0.1
-	// Small epsilon to prevent floating-point rounding errors in ceil()
-	// Without this, values like 5.0000000003 would ceil to 6 instead of 5
-	const epsilon = 1e-9
 
 	// Get filtering level from settings
 	level := settings.Realtime.FalsePositiveFilter.Level
@@ -1090,6 +1083,28 @@ func calculateMinDetectionsFromSettings(settings *conf.Settings) int {
 		// Continue with calculation - system will work but may not achieve target filtering
 	}
 
+	return minDetectionsForOverlap(level, overlap)
+}
+
+// minDetectionsForOverlap computes the minimum detections of a filtering level
+// for chunks analyzed with the given overlap.
+func minDetectionsForOverlap(level int, overlap float64) int {
+	// BirdNET uses 3-second chunks for analysis
+	const chunkDurationSeconds = 3.0
+	// Bird vocalization reference window - typical duration of a bird call
+	// Used to calculate how many detections are possible within a single vocalization
+	const referenceWindowSeconds = 6.0
+	// Minimum segment length to prevent division by near-zero values
+	const minSegmentLength = 0.1
+	// Small epsilon to prevent floating-point rounding errors in ceil()
+	// Without this, values like 5.0000000003 would ceil to 6 instead of 5
+	const epsilon = 1e-9
+
+	// Level 0: no filtering
+	if level == 0 {
+		return 1
+	}
+
 	// Calculate segment length (how often we analyze)
 	segmentLength := math.Max(minSegmentLength, chunkDurationSeconds-overlap)
 
@@ -1116,6 +1131,17 @@ func (p *Processor) calculateMinDetections() int {
 	return calculateMinDetectionsFromSettings(p.Settings)
 }
 
+// minDetectionsForSource returns the detections required of a source. While
+// adaptive overlap has lowered the overlap of the source fewer detections are
+// possible per vocalization, so the requirement follows the lowered overlap.
+func (p *Processor) minDetectionsForSource(sourceID string, minDetections int) int {
+	overlap, adapted := myaudio.AdaptedOverlap(sourceID)
+	if !adapted {
+		return minDetections
+	}
+	return minDetectionsForOverlap(p.Settings.Realtime.FalsePositiveFilter.Level, overlap)
+}
+
 // flushPendingDetections processes one flush cycle, flushing eligible detections.
 // Returns the count of pending and flushed detections for logging.
 func (p *Processor) flushPendingDetections(minDetections int) (pendingCount, flushedCount int) {
@@ -1132,7 +1158,8 @@ func (p *Processor) flushPendingDetections(minDetections int) (pendingCount, flu
 			continue
 		}
 
-		if shouldDiscard, reason := p.shouldDiscardDetection(&item, minDetections); shouldDiscard {
+		required := p.minDetectionsForSource(item.Source, minDetections)
+		if shouldDiscard, reason := p.shouldDiscardDetection(&item, required); shouldDiscard {
 			GetLogger().Info("discarding detection",
 				logger.String("species", species),
 				logger.String("source", p.getDisplayNameForSource(item.Source)),
@@ -1148,7 +1175,7 @@ func (p *Processor) flushPendingDetections(minDetections int) (pendingCount, flu
 			logger.String("source", p.getDisplayNameForSource(item.Source)),
 			logger.Bool("deadline_reached", true),
 			logger.Int("count", item.Count),
-			logger.Int("required", minDetections),
+			logger.Int("required", required),
 			logger.String("operation", "flush_detection"))
 
 		p.processApprovedDetection(&item, species)
@@ -0,0 +1,452 @@
+// simulator.go replays stored detections through the detection filters to
+// compare the current settings with candidate settings before applying them.
+package processor
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	// simulationPageSize is the number of stored detections read per query
+	simulationPageSize = 500
+	// DefaultSimulationMaxDetections caps the detections a simulation replays
+	DefaultSimulationMaxDetections = 20000
+	// maxSimulationExamples caps the gained and lost detections listed in a report
+	maxSimulationExamples = 200
+)
+
+// Reasons a replay filters a prediction
+const (
+	simulationReasonPrivacy    = "privacy"      // human detection privacy filter
+	simulationReasonConfidence = "confidence"   // at or below the (dynamic) threshold
+	simulationReasonSpecies    = "species_list" // range filter or species include/exclude
+)
+
+// SimulationStore is the part of the datastore a simulation reads.
+type SimulationStore interface {
+	SearchNotesAdvanced(filters *datastore.AdvancedSearchFilters) ([]datastore.Note, int64, error)
+	GetNoteResults(noteID string) ([]datastore.Results, error)
+}
+
+// SimulationOptions selects the stored detections a simulation replays.
+type SimulationOptions struct {
+	Start         time.Time // first day
+	End           time.Time // last day
+	Species       string    // empty for all species
+	Source        string    // empty for all sources
+	MaxDetections int       // 0 for DefaultSimulationMaxDetections
+}
+
+// ReviewBreakdown counts detections by their human review.
+type ReviewBreakdown struct {
+	Total         int `json:"total"`
+	Correct       int `json:"correct"`
+	FalsePositive int `json:"false_positive"`
+	Unreviewed    int `json:"unreviewed"`
+}
+
+func (b *ReviewBreakdown) add(review string) {
+	b.Total++
+	switch review {
+	case "correct":
+		b.Correct++
+	case "false_positive":
+		b.FalsePositive++
+	default:
+		b.Unreviewed++
+	}
+}
+
+// SimulationMetrics describes what one set of settings keeps of the replayed
+// detections. Precision is the share of reviewed kept detections reviewed as
+// correct, recall the share of all detections reviewed as correct that are
+// kept; both are nil without reviews to estimate them from.
+type SimulationMetrics struct {
+	Kept          ReviewBreakdown `json:"kept"`
+	Precision     *float64        `json:"precision"`
+	Recall        *float64        `json:"recall"`
+	MinDetections int             `json:"min_detections"` // confirmations the false positive filter requires
+}
+
+// SimulatedDetection is a prediction one set of settings keeps and the other filters.
+type SimulatedDetection struct {
+	DetectionID    uint      `json:"detection_id"` // stored detection the prediction belongs to
+	Secondary      bool      `json:"secondary"`    // a secondary prediction rather than the stored species
+	ScientificName string    `json:"scientific_name"`
+	CommonName     string    `json:"common_name"`
+	Source         string    `json:"source"`
+	Time           time.Time `json:"time"`
+	Confidence     float32   `json:"confidence"` // with the candidate sensitivity
+	Threshold      float32   `json:"threshold"`  // of the settings filtering the prediction
+	Reason         string    `json:"reason"`     // why those settings filter it
+	Review         string    `json:"review,omitempty"`
+}
+
+// SimulationReport compares the current settings with candidate settings over
+// stored detections.
+type SimulationReport struct {
+	Replayed      int                  `json:"replayed"`    // stored detections replayed
+	Predictions   int                  `json:"predictions"` // predictions replayed, secondary ones included
+	Truncated     bool                 `json:"truncated"`   // more detections matched than were replayed
+	Baseline      SimulationMetrics    `json:"baseline"`
+	Candidate     SimulationMetrics    `json:"candidate"`
+	GainedReviews ReviewBreakdown      `json:"gained_reviews"`
+	LostReviews   ReviewBreakdown      `json:"lost_reviews"`
+	Gained        []SimulatedDetection `json:"gained"`
+	Lost          []SimulatedDetection `json:"lost"`
+	Notes         []string             `json:"notes,omitempty"`
+}
+
+// simulationReplay is one set of settings replaying the stored detections. Its
+// processor has no datastore, so threshold changes are not recorded.
+type simulationReplay struct {
+	proc        *Processor
+	sensitivity float64
+	window      time.Duration
+	clock       time.Time
+	rangeLists  map[string][]string  // range filter species by date
+	lastKept    map[string]time.Time // last kept prediction by species
+	metrics     SimulationMetrics
+}
+
+// simulationOutcome is the verdict of a replay on one prediction.
+type simulationOutcome struct {
+	kept       bool
+	confidence float32
+	threshold  float32
+	reason     string
+}
+
+// simulationPrediction is a prediction of a stored detection being replayed.
+type simulationPrediction struct {
+	result         datastore.Results
+	scientificName string
+	commonName     string
+	secondary      bool
+}
+
+// Simulate replays the stored detections selected by opts through the
+// detection filters with the current settings and with candidate, in time
+// order, and reports the detections candidate would gain or lose. Each replay
+// evaluates the threshold, the dynamic threshold learned from the detections it
+// keeps, the range filter on the date of each detection and the species lists.
+// Dynamic thresholds start from their base values. The false positive filter
+// is not replayed, as the confirmations of a detection are not stored.
+func (p *Processor) Simulate(ctx context.Context, store SimulationStore, candidate *conf.Settings, opts SimulationOptions) (*SimulationReport, error) {
+	if p.Bn == nil {
+		return nil, errors.Newf("BirdNET is not initialized").
+			Component("analysis.processor").
+			Category(errors.CategoryValidation).
+			Context("operation", "simulate_settings").
+			Build()
+	}
+	limit := opts.MaxDetections
+	if limit <= 0 {
+		limit = DefaultSimulationMaxDetections
+	}
+
+	baseline := p.newSimulationReplay(p.Settings)
+	alternative := p.newSimulationReplay(candidate)
+	report := &SimulationReport{Gained: []SimulatedDetection{}, Lost: []SimulatedDetection{}}
+	labels := labelsByScientificName(p.Bn.Settings.BirdNET.Labels)
+	var reviewedCorrect int
+
+	filters := &datastore.AdvancedSearchFilters{
+		DateRange: &datastore.DateRange{Start: opts.Start, End: opts.End},
+		SortBy:    "date_asc",
+		Limit:     simulationPageSize,
+	}
+	if opts.Species != "" {
+		filters.Species = []string{opts.Species}
+	}
+	if opts.Source != "" {
+		filters.Location = []string{opts.Source}
+	}
+
+	for report.Replayed < limit {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		filters.Limit = min(simulationPageSize, limit-report.Replayed)
+		notes, total, err := store.SearchNotesAdvanced(filters)
+		if err != nil {
+			return nil, errors.New(err).
+				Component("analysis.processor").
+				Category(errors.CategoryDatabase).
+				Context("operation", "simulate_settings").
+				Build()
+		}
+		report.Truncated = total > int64(limit)
+
+		for i := range notes {
+			note := &notes[i]
+			predictions, err := simulationPredictions(store, note, labels)
+			if err != nil {
+				return nil, err
+			}
+			at := noteTime(note)
+			baseline.clock, alternative.clock = at, at
+			if note.Verified == "correct" {
+				reviewedCorrect++
+			}
+
+			for _, pred := range predictions {
+				report.Predictions++
+				before := baseline.evaluate(pred, note, at)
+				after := alternative.evaluate(pred, note, at)
+				review := ""
+				if !pred.secondary {
+					review = note.Verified
+				}
+				if before.kept {
+					baseline.metrics.Kept.add(review)
+				}
+				if after.kept {
+					alternative.metrics.Kept.add(review)
+				}
+
+				switch {
+				case before.kept && !after.kept:
+					report.LostReviews.add(review)
+					if len(report.Lost) < maxSimulationExamples {
+						report.Lost = append(report.Lost, simulatedDetection(pred, note, at, after, review))
+					}
+				case !before.kept && after.kept:
+					report.GainedReviews.add(review)
+					if len(report.Gained) < maxSimulationExamples {
+						gained := simulatedDetection(pred, note, at, after, review)
+						gained.Threshold, gained.Reason = before.threshold, before.reason
+						report.Gained = append(report.Gained, gained)
+					}
+				}
+			}
+		}
+		report.Replayed += len(notes)
+		filters.Offset += len(notes)
+		if len(notes) < filters.Limit {
+			break
+		}
+	}
+
+	baseline.metrics.estimate(reviewedCorrect)
+	alternative.metrics.estimate(reviewedCorrect)
+	report.Baseline, report.Candidate = baseline.metrics, alternative.metrics
+	report.Notes = simulationNotes(report)
+	return report, nil
+}
+
+// newSimulationReplay creates a replay of settings on a fresh processor whose
+// clock follows the replayed detections.
+func (p *Processor) newSimulationReplay(settings *conf.Settings) *simulationReplay {
+	simSettings := *settings
+	simSettings.Debug = false
+	simSettings.Realtime.DynamicThreshold.Debug = false
+
+	captureLength := time.Duration(simSettings.Realtime.Audio.Export.Length) * time.Second
+	preCaptureLength := time.Duration(simSettings.Realtime.Audio.Export.PreCapture) * time.Second
+
+	r := &simulationReplay{
+		sensitivity: simSettings.BirdNET.Sensitivity,
+		window:      max(time.Duration(0), captureLength-preCaptureLength),
+		rangeLists:  make(map[string][]string),
+		lastKept:    make(map[string]time.Time),
+		metrics: SimulationMetrics{
+			MinDetections: minDetectionsForOverlap(simSettings.Realtime.FalsePositiveFilter.Level, simSettings.BirdNET.Overlap),
+		},
+	}
+	r.proc = &Processor{
+		Settings:          &simSettings,
+		Bn:                p.Bn,
+		DynamicThresholds: make(map[string]*DynamicThreshold),
+		now:               func() time.Time { return r.clock },
+	}
+	return r
+}
+
+// evaluate runs a prediction through the filters of the replay and learns the
+// dynamic threshold from it when it is kept. A kept secondary prediction within
+// the detection window of a kept detection of the same species merges into it,
+// as pending detections do, and does not count again.
+func (r *simulationReplay) evaluate(pred simulationPrediction, note *datastore.Note, at time.Time) simulationOutcome {
+	settings := r.proc.Settings
+	settings.BirdNET.RangeFilter.Species = r.rangeFilter(at)
+
+	result := pred.result
+	fromSensitivity := note.Sensitivity
+	if fromSensitivity <= 0 {
+		fromSensitivity = r.proc.Bn.Settings.BirdNET.Sensitivity
+	}
+	result.Confidence = birdnet.RescaleConfidence(result.Confidence, fromSensitivity, r.sensitivity)
+
+	speciesLowercase := strings.ToLower(pred.commonName)
+	if speciesLowercase == "" {
+		speciesLowercase = strings.ToLower(pred.scientificName)
+	}
+	baseThreshold := r.proc.getBaseConfidenceThreshold(pred.commonName, pred.scientificName)
+	filtered, threshold := r.proc.shouldFilterDetection(result, pred.commonName, pred.scientificName, speciesLowercase, baseThreshold, note.SourceNode)
+
+	outcome := simulationOutcome{confidence: result.Confidence, threshold: threshold}
+	switch {
+	case filtered && threshold == 0:
+		outcome.reason = simulationReasonPrivacy
+	case filtered && result.Confidence <= threshold:
+		outcome.reason = simulationReasonConfidence
+	case filtered:
+		outcome.reason = simulationReasonSpecies
+	default:
+		last, seen := r.lastKept[speciesLowercase]
+		r.lastKept[speciesLowercase] = at
+		if pred.secondary && seen && at.Sub(last) < r.window {
+			return outcome
+		}
+		outcome.kept = true
+		r.proc.LearnFromApprovedDetection(speciesLowercase, pred.scientificName, result.Confidence)
+	}
+	return outcome
+}
+
+// rangeFilter returns the range filter species of the replay settings on the
+// date of at, falling back to every label when the range filter fails.
+func (r *simulationReplay) rangeFilter(at time.Time) []string {
+	date := at.Format(time.DateOnly)
+	if species, ok := r.rangeLists[date]; ok {
+		return species
+	}
+	var species []string
+	scores, err := r.proc.Bn.GetProbableSpeciesWith(r.proc.Settings, at, 0)
+	if err != nil {
+		GetLogger().Warn("Range filter failed during settings simulation, including all species",
+			logger.String("date", date),
+			logger.Error(err),
+			logger.String("operation", "simulate_settings"))
+		species = r.proc.Bn.Settings.BirdNET.Labels
+	} else {
+		species = make([]string, 0, len(scores))
+		for _, score := range scores {
+			species = append(species, score.Label)
+		}
+	}
+	r.rangeLists[date] = species
+	return species
+}
+
+// estimate sets the precision and recall of the kept detections, given the
+// number of replayed detections reviewed as correct.
+func (m *SimulationMetrics) estimate(reviewedCorrect int) {
+	if reviewed := m.Kept.Correct + m.Kept.FalsePositive; reviewed > 0 {
+		precision := float64(m.Kept.Correct) / float64(reviewed)
+		m.Precision = &precision
+	}
+	if reviewedCorrect > 0 {
+		recall := float64(m.Kept.Correct) / float64(reviewedCorrect)
+		m.Recall = &recall
+	}
+}
+
+// simulationPredictions returns the stored species of a detection followed by
+// its secondary predictions, with their model labels.
+func simulationPredictions(store SimulationStore, note *datastore.Note, labels map[string]string) ([]simulationPrediction, error) {
+	results, err := store.GetNoteResults(strconv.FormatUint(uint64(note.ID), 10))
+	if err != nil {
+		return nil, errors.New(err).
+			Component("analysis.processor").
+			Category(errors.CategoryDatabase).
+			Context("operation", "simulate_settings").
+			Context("detection_id", note.ID).
+			Build()
+	}
+
+	primaryLabel := labels[note.ScientificName]
+	if primaryLabel == "" {
+		primaryLabel = note.ScientificName + "_" + note.CommonName
+	}
+	predictions := []simulationPrediction{{
+		result:         datastore.Results{Species: primaryLabel, Confidence: float32(note.Confidence)},
+		scientificName: note.ScientificName,
+		commonName:     note.CommonName,
+	}}
+
+	slices.SortStableFunc(results, func(a, b datastore.Results) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+	for _, result := range results {
+		label := result.Species
+		if !strings.Contains(label, "_") {
+			label = labels[label]
+		}
+		scientificName, commonName := birdnet.SplitSpeciesName(label)
+		if scientificName == "" || commonName == "" || scientificName == note.ScientificName {
+			continue
+		}
+		result.Species = label
+		predictions = append(predictions, simulationPrediction{
+			result:         result,
+			scientificName: scientificName,
+			commonName:     commonName,
+			secondary:      true,
+		})
+	}
+	return predictions, nil
+}
+
+// labelsByScientificName maps the scientific names of model labels to the labels.
+func labelsByScientificName(labels []string) map[string]string {
+	byName := make(map[string]string, len(labels))
+	for _, label := range labels {
+		scientificName, _ := birdnet.SplitSpeciesName(label)
+		byName[scientificName] = label
+	}
+	return byName
+}
+
+// noteTime returns the detection time of a stored detection.
+func noteTime(note *datastore.Note) time.Time {
+	if !note.BeginTime.IsZero() {
+		return note.BeginTime
+	}
+	at, err := time.ParseInLocation(time.DateTime, note.Date+" "+note.Time, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return at
+}
+
+func simulatedDetection(pred simulationPrediction, note *datastore.Note, at time.Time, outcome simulationOutcome, review string) SimulatedDetection {
+	return SimulatedDetection{
+		DetectionID:    note.ID,
+		Secondary:      pred.secondary,
+		ScientificName: pred.scientificName,
+		CommonName:     pred.commonName,
+		Source:         note.SourceNode,
+		Time:           at,
+		Confidence:     outcome.confidence,
+		Threshold:      outcome.threshold,
+		Reason:         outcome.reason,
+		Review:         review,
+	}
+}
+
+// simulationNotes explains what a report cannot show.
+func simulationNotes(report *SimulationReport) []string {
+	var notes []string
+	if report.Truncated {
+		notes = append(notes, "Only the oldest "+strconv.Itoa(report.Replayed)+" matching detections were replayed; narrow the date range to cover the rest.")
+	}
+	if report.Candidate.MinDetections > report.Baseline.MinDetections {
+		notes = append(notes, "The candidate false positive filter requires more confirmations. Confirmations are not stored, so kept detections may still be filtered by it.")
+	} else if report.Candidate.MinDetections < report.Baseline.MinDetections {
+		notes = append(notes, "The candidate false positive filter requires fewer confirmations. Detections it would no longer filter were never stored and cannot be replayed.")
+	}
+	return notes
+}
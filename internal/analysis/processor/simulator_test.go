@@ -0,0 +1,205 @@
+// simulator_test.go: Unit tests for the settings simulator
+package processor
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// simulationTestStore serves notes in the order given and their secondary predictions.
+type simulationTestStore struct {
+	notes   []datastore.Note
+	results map[string][]datastore.Results // by note ID
+}
+
+func (s *simulationTestStore) SearchNotesAdvanced(filters *datastore.AdvancedSearchFilters) ([]datastore.Note, int64, error) {
+	total := int64(len(s.notes))
+	if filters.Offset >= len(s.notes) {
+		return nil, total, nil
+	}
+	page := s.notes[filters.Offset:]
+	if len(page) > filters.Limit {
+		page = page[:filters.Limit]
+	}
+	return page, total, nil
+}
+
+func (s *simulationTestStore) GetNoteResults(noteID string) ([]datastore.Results, error) {
+	return s.results[noteID], nil
+}
+
+var simulationStart = time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+
+func simulationNote(id uint, scientificName, commonName string, confidence float64, offset time.Duration, review string) datastore.Note {
+	return datastore.Note{
+		ID:             id,
+		ScientificName: scientificName,
+		CommonName:     commonName,
+		Confidence:     confidence,
+		BeginTime:      simulationStart.Add(offset),
+		Verified:       review,
+	}
+}
+
+// newSimulationProcessor creates a processor without a range filter model, so
+// every label is in range.
+func newSimulationProcessor() *Processor {
+	p := newTestProcessor()
+	p.Settings.BirdNET.Sensitivity = 1
+	p.Settings.BirdNET.Labels = []string{
+		"Turdus merula_Eurasian Blackbird",
+		"Parus major_Great Tit",
+		"Homo sapiens_Human",
+	}
+	p.Settings.Realtime.DynamicThreshold.Enabled = false
+	p.Bn = &birdnet.BirdNET{Settings: p.Settings}
+	return p
+}
+
+func TestSimulate_RaisedThresholdLosesDetections(t *testing.T) {
+	t.Parallel()
+
+	p := newSimulationProcessor()
+	store := &simulationTestStore{notes: []datastore.Note{
+		simulationNote(1, "Turdus merula", "Eurasian Blackbird", 0.85, 0, "correct"),
+		simulationNote(2, "Turdus merula", "Eurasian Blackbird", 0.95, time.Hour, "correct"),
+		simulationNote(3, "Parus major", "Great Tit", 0.86, 2*time.Hour, "false_positive"),
+		simulationNote(4, "Parus major", "Great Tit", 0.88, 3*time.Hour, ""),
+	}}
+
+	candidate := *p.Settings
+	candidate.BirdNET.Threshold = 0.9
+
+	report, err := p.Simulate(context.Background(), store, &candidate, SimulationOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, report.Replayed)
+	assert.Equal(t, ReviewBreakdown{Total: 4, Correct: 2, FalsePositive: 1, Unreviewed: 1}, report.Baseline.Kept)
+	assert.Equal(t, ReviewBreakdown{Total: 1, Correct: 1}, report.Candidate.Kept)
+	assert.Equal(t, ReviewBreakdown{Total: 3, Correct: 1, FalsePositive: 1, Unreviewed: 1}, report.LostReviews)
+	assert.Empty(t, report.Gained)
+
+	require.NotNil(t, report.Baseline.Precision)
+	assert.InDelta(t, 2.0/3.0, *report.Baseline.Precision, 0.0001)
+	require.NotNil(t, report.Candidate.Recall)
+	assert.InDelta(t, 0.5, *report.Candidate.Recall, 0.0001)
+
+	require.Len(t, report.Lost, 3)
+	assert.Equal(t, uint(1), report.Lost[0].DetectionID)
+	assert.Equal(t, simulationReasonConfidence, report.Lost[0].Reason)
+	assert.InDelta(t, 0.9, report.Lost[0].Threshold, 0.0001)
+	assert.Equal(t, "correct", report.Lost[0].Review)
+
+	// The settings are simulated, not applied
+	assert.InDelta(t, 0.8, p.Settings.BirdNET.Threshold, 0.0001)
+}
+
+func TestSimulate_LoweredThresholdGainsSecondaryPredictions(t *testing.T) {
+	t.Parallel()
+
+	p := newSimulationProcessor()
+	store := &simulationTestStore{
+		notes: []datastore.Note{
+			simulationNote(1, "Turdus merula", "Eurasian Blackbird", 0.9, 0, "correct"),
+			simulationNote(2, "Turdus merula", "Eurasian Blackbird", 0.9, 5*time.Second, ""),
+			simulationNote(3, "Turdus merula", "Eurasian Blackbird", 0.9, time.Hour, ""),
+		},
+		results: map[string][]datastore.Results{
+			"1": {{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.9}, {Species: "Parus major_Great Tit", Confidence: 0.7}},
+			"2": {{Species: "Parus major_Great Tit", Confidence: 0.75}},
+			// The enhanced database stores predictions by scientific name
+			"3": {{Species: "Parus major", Confidence: 0.65}, {Species: "Homo sapiens", Confidence: 0.95}},
+		},
+	}
+
+	candidate := *p.Settings
+	candidate.BirdNET.Threshold = 0.6
+
+	report, err := p.Simulate(context.Background(), store, &candidate, SimulationOptions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, report.Replayed)
+	assert.Equal(t, ReviewBreakdown{Total: 2, Unreviewed: 2}, report.GainedReviews,
+		"a secondary prediction within the detection window of a gained one merges into it")
+	assert.Empty(t, report.Lost)
+
+	require.Len(t, report.Gained, 2)
+	for i, id := range []uint{1, 3} {
+		gained := report.Gained[i]
+		assert.Equal(t, id, gained.DetectionID)
+		assert.True(t, gained.Secondary)
+		assert.Equal(t, "Parus major", gained.ScientificName)
+		assert.Equal(t, "Great Tit", gained.CommonName)
+		assert.Empty(t, gained.Review, "a review of the stored species says nothing about secondary predictions")
+		assert.Equal(t, simulationReasonConfidence, gained.Reason)
+		assert.InDelta(t, 0.8, gained.Threshold, 0.0001, "the threshold filtering it before")
+	}
+}
+
+func TestSimulate_DynamicThresholdFollowsDetectionTime(t *testing.T) {
+	t.Parallel()
+
+	p := newSimulationProcessor()
+	store := &simulationTestStore{notes: []datastore.Note{
+		simulationNote(1, "Turdus merula", "Eurasian Blackbird", 0.95, 0, "correct"),
+		simulationNote(2, "Turdus merula", "Eurasian Blackbird", 0.7, 10*time.Minute, "correct"),
+		simulationNote(3, "Turdus merula", "Eurasian Blackbird", 0.7, 3*time.Hour, "false_positive"),
+	}}
+
+	candidate := *p.Settings
+	candidate.Realtime.DynamicThreshold = conf.DynamicThresholdSettings{
+		Enabled:    true,
+		Trigger:    0.9,
+		Min:        0.2,
+		ValidHours: 1,
+	}
+
+	report, err := p.Simulate(context.Background(), store, &candidate, SimulationOptions{})
+	require.NoError(t, err)
+
+	require.Len(t, report.Gained, 1, "the threshold learned from the first detection expires after an hour of detection time")
+	assert.Equal(t, uint(2), report.Gained[0].DetectionID)
+	assert.InDelta(t, 0.8, report.Gained[0].Threshold, 0.0001)
+	assert.Equal(t, ReviewBreakdown{Total: 1, Correct: 1}, report.GainedReviews)
+	assert.Empty(t, p.DynamicThresholds, "the live dynamic thresholds are untouched")
+}
+
+func TestSimulate_LimitsReplayedDetections(t *testing.T) {
+	t.Parallel()
+
+	p := newSimulationProcessor()
+	store := &simulationTestStore{}
+	for i := range simulationPageSize + 10 {
+		store.notes = append(store.notes, simulationNote(uint(i+1), "Turdus merula", "Eurasian Blackbird", 0.9, time.Duration(i)*time.Minute, ""))
+	}
+
+	report, err := p.Simulate(context.Background(), store, p.Settings, SimulationOptions{MaxDetections: simulationPageSize + 5})
+	require.NoError(t, err)
+	assert.Equal(t, simulationPageSize+5, report.Replayed)
+	assert.True(t, report.Truncated)
+	assert.Contains(t, report.Notes[0], strconv.Itoa(simulationPageSize+5))
+	assert.Nil(t, report.Baseline.Precision, "no reviews to estimate precision from")
+}
+
+func TestSimulate_ReportsFalsePositiveFilterChange(t *testing.T) {
+	t.Parallel()
+
+	p := newSimulationProcessor()
+	p.Settings.BirdNET.Overlap = 2.0
+	candidate := *p.Settings
+	candidate.Realtime.FalsePositiveFilter.Level = 2
+
+	report, err := p.Simulate(context.Background(), &simulationTestStore{}, &candidate, SimulationOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Baseline.MinDetections)
+	assert.Greater(t, report.Candidate.MinDetections, 1)
+	assert.Len(t, report.Notes, 1)
+}
@@ -29,11 +29,13 @@ import (
 	"github.com/tphakala/birdnet-go/internal/detection"
 	"github.com/tphakala/birdnet-go/internal/diskmanager"
 	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/hub"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/monitor"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
 	"github.com/tphakala/birdnet-go/internal/notification"
+	"github.com/tphakala/birdnet-go/internal/notification/digest"
 	"github.com/tphakala/birdnet-go/internal/observability"
 	"github.com/tphakala/birdnet-go/internal/privacy"
 	"github.com/tphakala/birdnet-go/internal/security"
@@ -354,6 +356,9 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 	// Ensure v2 database is closed on shutdown (handles nil case gracefully)
 	defer closeV2Database()
 
+	// Keep notification history in the v2 database when it is available
+	initPersistentNotificationStore()
+
 	// Initialize and start the HTTP server
 	GetLogger().Info("starting HTTP server")
 	oauth2Server := security.NewOAuth2Server()
@@ -382,7 +387,7 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 	bufferManager := MustNewBufferManager(bn, quitChan, &wg)
 
 	// Start buffer monitors for each audio source only if we have active sources
-	if len(settings.Realtime.RTSP.Streams) > 0 || settings.Realtime.Audio.Source != "" {
+	if len(settings.Realtime.RTSP.Streams) > 0 || settings.Realtime.Audio.Source != "" || settings.HasIngestDevices() {
 		if err := bufferManager.UpdateMonitors(sources); err != nil {
 			// Use structured logging to improve error visibility and triage
 			// Extract error details from the enhanced error if available
@@ -427,11 +432,31 @@ func RealtimeAnalysis(settings *conf.Settings) error {
 		startClipCleanupMonitor(&wg, quitChan, dataStore)
 	}
 
+	// start continuous audio archive, the monitor also handles archives enabled later
+	if settings.Realtime.Audio.Archive.Enabled {
+		if err := myaudio.StartAudioArchive(settings); err != nil {
+			GetLogger().Error("failed to start audio archive",
+				logger.Error(err),
+				logger.String("operation", "audio_archive_start"))
+		}
+	}
+	startArchiveCleanupMonitor(&wg, quitChan)
+
 	// start weather polling
 	if settings.Realtime.Weather.Provider != "none" {
 		startWeatherPolling(&wg, settings, dataStore, metrics, quitChan)
 	}
 
+	// start scheduled digest notifications
+	if settings.Notification.Digest.Daily.Enabled || settings.Notification.Digest.Weekly.Enabled {
+		startDigestScheduler(&wg, settings, dataStore, quitChan)
+	}
+
+	// start pushing detections to the upstream hub
+	if settings.Hub.Upstream.Enabled {
+		startHubPusher(&wg, settings, dataStore, quitChan)
+	}
+
 	// Telemetry endpoint initialization is handled by control monitor for hot reload support.
 	// Unlike other services that start directly here, telemetry is managed by the control monitor
 	// to allow users to dynamically enable/disable metrics and change the listen address without
@@ -726,6 +751,13 @@ func startClipCleanupMonitor(wg *sync.WaitGroup, quitChan chan struct{}, dataSto
 	})
 }
 
+// startArchiveCleanupMonitor initializes and starts the audio archive retention routine in a new goroutine.
+func startArchiveCleanupMonitor(wg *sync.WaitGroup, quitChan chan struct{}) {
+	wg.Go(func() {
+		archiveCleanupMonitor(quitChan)
+	})
+}
+
 // startWeatherPolling initializes and starts the weather polling routine in a new goroutine.
 func startWeatherPolling(wg *sync.WaitGroup, settings *conf.Settings, dataStore datastore.Interface, metrics *observability.Metrics, quitChan chan struct{}) {
 	// Create new weather service
@@ -742,6 +774,49 @@ func startWeatherPolling(wg *sync.WaitGroup, settings *conf.Settings, dataStore
 	})
 }
 
+// initPersistentNotificationStore switches the notification service from its
+// startup in-memory store to the v2 database so bell history survives restarts.
+// Without the v2 database the in-memory store stays in place.
+func initPersistentNotificationStore() {
+	v2Manager := GetV2DatabaseManager()
+	if v2Manager == nil || !notification.IsInitialized() {
+		return
+	}
+
+	repo := repository.NewNotificationRepository(v2Manager.DB())
+	store := repository.NewNotificationStore(repo, notification.DefaultMaxNotifications)
+	if err := notification.GetService().SetStore(store); err != nil {
+		GetLogger().Error("failed to enable persistent notification store",
+			logger.Error(err),
+			logger.String("operation", "initialize_notification_store"))
+		return
+	}
+	GetLogger().Info("notification history persisted in database",
+		logger.String("operation", "initialize_notification_store"))
+}
+
+// startDigestScheduler initializes and starts the digest notification scheduler in a new goroutine.
+func startDigestScheduler(wg *sync.WaitGroup, settings *conf.Settings, dataStore datastore.Interface, quitChan chan struct{}) {
+	if !notification.IsInitialized() {
+		GetLogger().Warn("notification service not initialized, digest notifications disabled",
+			logger.String("operation", "initialize_digest_scheduler"))
+		return
+	}
+
+	scheduler := digest.NewScheduler(settings, dataStore, notification.GetService())
+	wg.Go(func() {
+		scheduler.Start(quitChan)
+	})
+}
+
+// startHubPusher starts pushing the detections of this station to the upstream hub in a new goroutine.
+func startHubPusher(wg *sync.WaitGroup, settings *conf.Settings, dataStore datastore.Interface, quitChan chan struct{}) {
+	pusher := hub.NewPusher(settings, dataStore)
+	wg.Go(func() {
+		pusher.Start(quitChan)
+	})
+}
+
 // monitorShutdownSignals listens for shutdown signals (SIGINT, SIGTERM) and triggers the application shutdown process.
 func monitorShutdownSignals(quitChan chan struct{}) {
 	go func() {
@@ -960,6 +1035,43 @@ func clipCleanupMonitor(quitChan chan struct{}, dataStore datastore.Interface) {
 	}
 }
 
+// archiveCleanupMonitor enforces the audio archive retention limits and stops
+// the archive on shutdown so open segments are completed.
+func archiveCleanupMonitor(quitChan chan struct{}) {
+	checkInterval := conf.Setting().Realtime.Audio.Archive.CheckInterval
+	if checkInterval <= 0 {
+		checkInterval = conf.DefaultCleanupCheckInterval
+	}
+
+	ticker := time.NewTicker(time.Duration(checkInterval) * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-quitChan:
+			myaudio.StopAudioArchive()
+			return
+
+		case <-ticker.C:
+			archive := conf.Setting().Realtime.Audio.Archive
+			if !archive.Enabled {
+				continue
+			}
+			result := diskmanager.ArchiveCleanup(quitChan, &archive)
+			if result.Err != nil {
+				GetLogger().Error("audio archive cleanup failed",
+					logger.Error(result.Err),
+					logger.String("operation", "archive_cleanup"))
+				continue
+			}
+			GetLogger().Info("audio archive cleanup completed",
+				logger.Int("segments_removed", result.ClipsRemoved),
+				logger.Int("disk_utilization_percent", result.DiskUtilization),
+				logger.String("operation", "archive_cleanup"))
+		}
+	}
+}
+
 // setupImageProviderRegistry initializes or retrieves the global image provider registry
 // and registers the default providers (Wikimedia, AviCommons).
 // Uses atomic GetOrRegister to eliminate race conditions between concurrent calls.
@@ -1582,7 +1694,7 @@ func initializeBirdImageCacheIfNeeded(settings *conf.Settings, dataStore datasto
 func initializeAudioSources(settings *conf.Settings) ([]string, error) {
 	log := GetLogger()
 	var sources []string
-	if len(settings.Realtime.RTSP.Streams) > 0 || settings.Realtime.Audio.Source != "" {
+	if len(settings.Realtime.RTSP.Streams) > 0 || settings.Realtime.Audio.Source != "" || settings.HasIngestDevices() {
 		if len(settings.Realtime.RTSP.Streams) > 0 {
 			// Register RTSP sources in the registry and get their source IDs
 			registry := myaudio.GetRegistry()
@@ -1614,7 +1726,7 @@ func initializeAudioSources(settings *conf.Settings) ([]string, error) {
 					continue
 				}
 
-				sources = append(sources, source.ID)
+				sources = append(sources, channelSourceIDs(source, stream.Channels)...)
 			}
 
 			// If some sources failed to register, log a summary
@@ -1637,9 +1749,10 @@ func initializeAudioSources(settings *conf.Settings) ([]string, error) {
 					logger.String("source", settings.Realtime.Audio.Source),
 					logger.Error(err))
 			} else {
-				sources = append(sources, source.ID)
+				sources = append(sources, channelSourceIDs(source, settings.Realtime.Audio.Channels)...)
 			}
 		}
+		sources = append(sources, ingestSourceIDs(settings)...)
 
 		// Initialize buffers for all audio sources
 		if err := initializeBuffers(sources); err != nil {
@@ -1654,6 +1767,48 @@ func initializeAudioSources(settings *conf.Settings) ([]string, error) {
 	return sources, nil
 }
 
+// channelSourceIDs applies the channel settings of a capture source and returns
+// the IDs of the sources analyzed for it. Invalid settings fall back to
+// analyzing the source as mono.
+func channelSourceIDs(source *myaudio.AudioSource, channels conf.ChannelSettings) []string {
+	ids, err := myaudio.ConfigureSourceChannels(source, channels)
+	if err != nil {
+		GetLogger().Warn("invalid channel settings, analyzing source as mono",
+			logger.String("source_id", source.ID),
+			logger.Error(err))
+		return []string{source.ID}
+	}
+	return ids
+}
+
+// ingestSourceIDs registers the push ingest devices in the source registry and
+// returns their source IDs.
+func ingestSourceIDs(settings *conf.Settings) []string {
+	if !settings.HasIngestDevices() {
+		return nil
+	}
+	registry := myaudio.GetRegistry()
+	if registry == nil {
+		return nil
+	}
+
+	var ids []string
+	for _, device := range settings.Realtime.Ingest.Devices {
+		source, err := registry.RegisterSource(myaudio.IngestConnectionString(device.ID), myaudio.SourceConfig{
+			DisplayName: device.Name,
+			Type:        myaudio.SourceTypePush,
+		})
+		if err != nil {
+			GetLogger().Warn("failed to register ingest device source",
+				logger.String("device_id", device.ID),
+				logger.Error(err))
+			continue
+		}
+		ids = append(ids, source.ID)
+	}
+	return ids
+}
+
 // printSystemDetails prints system information and analyzer configuration
 func printSystemDetails(settings *conf.Settings) {
 	log := GetLogger()
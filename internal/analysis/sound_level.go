@@ -646,6 +646,7 @@ func registerSoundLevelProcessorsForActiveSources(settings *conf.Settings) error
 		} else {
 			successCount++
 			LogSoundLevelProcessorRegistered(audioSource.DisplayName, "audio_device", "analysis.soundlevel")
+			registerChannelSoundLevelProcessors(audioSource)
 		}
 	}
 
@@ -696,6 +697,7 @@ func registerSoundLevelProcessorsForActiveSources(settings *conf.Settings) error
 			LogSoundLevelProcessorRegistrationFailed(audioSource.DisplayName, string(audioSource.Type)+"_stream", "analysis.soundlevel", err)
 		} else {
 			successCount++
+			registerChannelSoundLevelProcessors(audioSource)
 			if _, isActive := activeStreams[stream.URL]; isActive {
 				LogSoundLevelProcessorRegistered(audioSource.DisplayName, string(audioSource.Type)+"_active", "analysis.soundlevel")
 			} else {
@@ -722,6 +724,14 @@ func registerSoundLevelProcessorsForActiveSources(settings *conf.Settings) error
 	return nil
 }
 
+// registerChannelSoundLevelProcessors registers sound level processors for the
+// channel sub-sources of a source split into channels.
+func registerChannelSoundLevelProcessors(audioSource *myaudio.AudioSource) {
+	if err := myaudio.RegisterChannelSoundLevelProcessors(audioSource.ID); err != nil {
+		LogSoundLevelProcessorRegistrationFailed(audioSource.DisplayName, "channel", "analysis.soundlevel", err)
+	}
+}
+
 // unregisterAllSoundLevelProcessors unregisters all sound level processors
 func unregisterAllSoundLevelProcessors(settings *conf.Settings) {
 	// Unregister audio source
@@ -731,6 +741,7 @@ func unregisterAllSoundLevelProcessors(settings *conf.Settings) {
 		if registry != nil {
 			if audioSource, exists := registry.GetSourceByConnection(settings.Realtime.Audio.Source); exists {
 				myaudio.UnregisterSoundLevelProcessor(audioSource.ID)
+				myaudio.UnregisterChannelSoundLevelProcessors(audioSource.ID)
 				LogSoundLevelProcessorUnregistered(audioSource.DisplayName, "audio_device", "analysis.soundlevel")
 			}
 			// If source doesn't exist, nothing to unregister - this is expected during teardown
@@ -746,6 +757,7 @@ func unregisterAllSoundLevelProcessors(settings *conf.Settings) {
 		if registry != nil {
 			if audioSource, exists := registry.GetSourceByConnection(stream.URL); exists {
 				myaudio.UnregisterSoundLevelProcessor(audioSource.ID)
+				myaudio.UnregisterChannelSoundLevelProcessors(audioSource.ID)
 				LogSoundLevelProcessorUnregistered(stream.Name, "stream", "analysis.soundlevel")
 			}
 			// If source doesn't exist, nothing to unregister - this is expected during teardown
@@ -10,6 +10,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/markbates/goth/gothic"
+	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/security"
 )
@@ -127,6 +128,8 @@ func AuthMethodFromString(s string) AuthMethod {
 		return AuthMethodAPIKey
 	case AuthMethodLocalSubnet.String():
 		return AuthMethodLocalSubnet
+	case AuthMethodTrustedProxy.String():
+		return AuthMethodTrustedProxy
 	case AuthMethodBasicAuth.String():
 		return AuthMethodBasicAuth
 	case AuthMethodToken.String():
@@ -294,10 +297,22 @@ func (a *SecurityAdapter) EstablishSession(c echo.Context, accessToken string) e
 	return nil
 }
 
+// AuthenticateProxy resolves the identity asserted by a trusted reverse proxy.
+// The peer address is taken from the raw connection rather than c.RealIP(),
+// which honours client-controlled forwarding headers.
+func (a *SecurityAdapter) AuthenticateProxy(c echo.Context) (*security.ProxyIdentity, error) {
+	return a.OAuth2Server.AuthenticateProxyRequest(c.Request())
+}
+
 // IsAuthenticated checks if a request is authenticated via any supported method.
 // Returns true if auth is bypassed (not required) or if token/session auth succeeds.
 // This centralizes authentication checking logic to avoid duplication across handlers.
 func (a *SecurityAdapter) IsAuthenticated(c echo.Context) bool {
+	// A trusted proxy identity (or a strict-mode rejection) takes precedence
+	if _, err := a.AuthenticateProxy(c); !errors.Is(err, security.ErrProxyAuthNotApplicable) {
+		return err == nil
+	}
+
 	// Check if auth is required for this client (e.g., local subnet bypass)
 	if !a.IsAuthRequired(c) {
 		return true // Bypassed auth is treated as authenticated for data access
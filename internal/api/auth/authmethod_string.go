@@ -16,11 +16,12 @@ func _() {
 	_ = x[AuthMethodBrowserSession-5]
 	_ = x[AuthMethodAPIKey-6]
 	_ = x[AuthMethodLocalSubnet-7]
+	_ = x[AuthMethodTrustedProxy-8]
 }
 
-const _AuthMethod_name = "UnknownNoneBasicAuthTokenOAuth2BrowserSessionAPIKeyLocalSubnet"
+const _AuthMethod_name = "UnknownNoneBasicAuthTokenOAuth2BrowserSessionAPIKeyLocalSubnetTrustedProxy"
 
-var _AuthMethod_index = [...]uint8{0, 7, 11, 20, 25, 31, 45, 51, 62}
+var _AuthMethod_index = [...]uint8{0, 7, 11, 20, 25, 31, 45, 51, 62, 74}
 
 func (i AuthMethod) String() string {
 	if i < 0 || i >= AuthMethod(len(_AuthMethod_index)-1) {
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/security"
 )
@@ -24,6 +25,8 @@ const (
 	CtxKeyAuthMethod = "auth:authMethod"
 	// CtxKeyUsername contains the authenticated user's username (if available).
 	CtxKeyUsername = "auth:username"
+	// CtxKeyReadOnly indicates the authenticated user may only use safe (read-only) methods.
+	CtxKeyReadOnly = "auth:readOnly"
 )
 
 // Middleware provides authentication middleware with the Service
@@ -45,13 +48,22 @@ func (m *Middleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 			return err
 		}
 
+		// Trusted proxy identity is evaluated first so that strict mode
+		// cannot be sidestepped by subnet bypass or stale sessions
+		if result := m.tryProxyAuth(c); result.handled {
+			if result.rejected {
+				return result.err
+			}
+			return next(c)
+		}
+
 		if m.shouldBypassAuth(c) {
 			return next(c)
 		}
 
 		// Try token auth first (from Authorization header)
 		if result := m.tryTokenAuth(c); result.handled {
-			if result.err != nil {
+			if result.rejected {
 				return result.err
 			}
 			return next(c)
@@ -69,8 +81,9 @@ func (m *Middleware) Authenticate(next echo.HandlerFunc) echo.HandlerFunc {
 
 // authResult represents the result of an authentication attempt.
 type authResult struct {
-	handled bool  // Whether the auth attempt was processed (header present or valid session)
-	err     error // Error to return to client (nil if successful)
+	handled  bool  // Whether the auth attempt was processed (header present or valid session)
+	rejected bool  // Whether the request was refused and the error response already sent
+	err      error // Result of sending the error response, usually nil
 }
 
 // validateAuthService checks if AuthService is configured and returns an error response if not.
@@ -101,6 +114,93 @@ func (m *Middleware) shouldBypassAuth(c echo.Context) bool {
 	return false
 }
 
+// tryProxyAuth attempts to authenticate using an identity asserted by a trusted reverse proxy.
+// Requests that carry no proxy identity fall through to the other methods unless
+// the configuration requires every request to arrive via a trusted proxy.
+func (m *Middleware) tryProxyAuth(c echo.Context) authResult {
+	identity, err := m.AuthService.AuthenticateProxy(c)
+	if errors.Is(err, security.ErrProxyAuthNotApplicable) {
+		return authResult{handled: false}
+	}
+
+	path := c.Request().URL.Path
+	ip := c.RealIP()
+	log := m.log()
+
+	if err != nil {
+		log.Warn("Trusted proxy authentication rejected request",
+			logger.String("path", path),
+			logger.String("ip", ip),
+			logger.Error(err))
+		return authResult{
+			handled:  true,
+			rejected: true,
+			err: c.JSON(http.StatusForbidden, map[string]string{
+				"error": "Access denied",
+			}),
+		}
+	}
+
+	readOnly := identity.Access == security.ProxyAccessReadOnly
+	if readOnly && !isSafeMethod(c.Request().Method) {
+		log.Warn("Read-only proxy user attempted a modifying request",
+			logger.String("path", path),
+			logger.String("ip", ip),
+			logger.String("method", c.Request().Method),
+			logger.Username(identity.Username))
+		return authResult{
+			handled:  true,
+			rejected: true,
+			err: c.JSON(http.StatusForbidden, map[string]string{
+				"error": "Read-only access",
+			}),
+		}
+	}
+
+	log.Debug("Trusted proxy authentication successful",
+		logger.String("path", path),
+		logger.String("ip", ip),
+		logger.Username(identity.Username))
+	c.Set(CtxKeyIsAuthenticated, true)
+	c.Set(CtxKeyUsername, identity.Username)
+	c.Set(CtxKeyAuthMethod, AuthMethodTrustedProxy)
+	c.Set(CtxKeyReadOnly, readOnly)
+	return authResult{handled: true, err: nil}
+}
+
+// RequireWrite refuses requests of read-only identities. It guards routes that
+// change state or expose secrets through safe methods, such as WebSocket
+// upgrades, and must run after Authenticate.
+func RequireWrite(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if IsReadOnly(c) {
+			GetLogger().Warn("Read-only user attempted a write-only route",
+				logger.String("path", c.Request().URL.Path),
+				logger.String("ip", c.RealIP()))
+			return c.JSON(http.StatusForbidden, map[string]string{
+				"error": "Read-only access",
+			})
+		}
+		return next(c)
+	}
+}
+
+// IsReadOnly reports whether the authenticated identity may only read.
+func IsReadOnly(c echo.Context) bool {
+	readOnly, _ := c.Get(CtxKeyReadOnly).(bool)
+	return readOnly
+}
+
+// isSafeMethod reports whether the HTTP method is read-only as defined by RFC 9110.
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
 // tryTokenAuth attempts to authenticate using a Bearer token from the Authorization header.
 // Note: Token-based authentication does not currently provide username information.
 // The AccessToken structure only contains the token and expiry, not user identity.
@@ -148,7 +248,8 @@ func (m *Middleware) handleMalformedAuthHeader(c echo.Context, path, ip string)
 		logger.String("ip", ip))
 	c.Response().Header().Set("WWW-Authenticate", `Bearer realm="api"`)
 	return authResult{
-		handled: true,
+		handled:  true,
+		rejected: true,
 		err: c.JSON(http.StatusUnauthorized, map[string]string{
 			"error": "Invalid Authorization header",
 		}),
@@ -163,7 +264,8 @@ func (m *Middleware) handleInvalidToken(c echo.Context, path, ip string) authRes
 	c.Response().Header().Set("WWW-Authenticate",
 		`Bearer realm="api", error="invalid_token", error_description="Invalid or expired token"`)
 	return authResult{
-		handled: true,
+		handled:  true,
+		rejected: true,
 		err: c.JSON(http.StatusUnauthorized, map[string]string{
 			"error": "Invalid or expired token",
 		}),
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/security"
+)
+
+// newProxyAuthTestEcho serves a write route, a read route and a WebSocket
+// route behind the middleware with trusted proxy auth for 172.18.0.0/16.
+// handled records the paths whose handler ran.
+func newProxyAuthTestEcho(requireProxy bool, handled *[]string) *echo.Echo {
+	settings := &conf.Settings{}
+	settings.Security.TrustedProxyAuth = conf.TrustedProxyAuth{
+		Enabled:        true,
+		TrustedProxies: "172.18.0.0/16",
+		UserHeader:     "Remote-User",
+		GroupsHeader:   "Remote-Groups",
+		AdminGroups:    "admins",
+		ViewerGroups:   "viewers",
+		RequireProxy:   requireProxy,
+	}
+	settings.Security.BasicAuth.Enabled = true
+	mw := NewMiddleware(NewSecurityAdapter(security.NewOAuth2ServerForTesting(settings)))
+
+	handler := func(c echo.Context) error {
+		*handled = append(*handled, c.Request().URL.Path)
+		return c.NoContent(http.StatusOK)
+	}
+	e := echo.New()
+	e.GET("/api/v2/detections", handler, mw.Authenticate)
+	e.POST("/api/v2/settings", handler, mw.Authenticate)
+	e.GET("/api/v2/terminal/ws", handler, mw.Authenticate, RequireWrite)
+	return e
+}
+
+func TestAuthenticate_TrustedProxy(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		requireProxy bool
+		method       string
+		path         string
+		remoteAddr   string
+		groups       string
+		status       int
+		handled      bool
+	}{
+		{name: "admin from trusted proxy writes", method: http.MethodPost, path: "/api/v2/settings",
+			remoteAddr: "172.18.0.5:4321", groups: "admins", status: http.StatusOK, handled: true},
+		{name: "viewer from trusted proxy reads", method: http.MethodGet, path: "/api/v2/detections",
+			remoteAddr: "172.18.0.5:4321", groups: "viewers", status: http.StatusOK, handled: true},
+		{name: "viewer cannot write", method: http.MethodPost, path: "/api/v2/settings",
+			remoteAddr: "172.18.0.5:4321", groups: "viewers", status: http.StatusForbidden},
+		{name: "viewer cannot open a WebSocket route", method: http.MethodGet, path: "/api/v2/terminal/ws",
+			remoteAddr: "172.18.0.5:4321", groups: "viewers", status: http.StatusForbidden},
+		{name: "user without a mapped group is refused", method: http.MethodGet, path: "/api/v2/detections",
+			remoteAddr: "172.18.0.5:4321", groups: "guests", status: http.StatusForbidden},
+		{name: "identity header from untrusted peer is ignored", method: http.MethodPost, path: "/api/v2/settings",
+			remoteAddr: "192.168.1.10:4321", groups: "admins", status: http.StatusUnauthorized},
+		{name: "untrusted peer refused when the proxy is required", requireProxy: true, method: http.MethodGet, path: "/api/v2/detections",
+			remoteAddr: "192.168.1.10:4321", groups: "admins", status: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var handled []string
+			e := newProxyAuthTestEcho(tt.requireProxy, &handled)
+
+			req := httptest.NewRequest(tt.method, tt.path, http.NoBody)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("Remote-User", "alice")
+			req.Header.Set("Remote-Groups", tt.groups)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			if tt.handled {
+				assert.Equal(t, []string{tt.path}, handled)
+			} else {
+				assert.Empty(t, handled, "a refused request must not reach the handler")
+			}
+		})
+	}
+}
+
+func TestAuthenticate_InvalidTokenDoesNotReachHandler(t *testing.T) {
+	t.Parallel()
+
+	var handled []string
+	e := newProxyAuthTestEcho(false, &handled)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/settings", http.NoBody)
+	req.RemoteAddr = "192.168.1.10:4321"
+	req.Header.Set("Authorization", "Bearer not-a-token")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, handled)
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/security"
 )
 
 // GetLogger returns the auth package logger.
@@ -39,6 +40,7 @@ const (
 	AuthMethodBrowserSession // Added for explicit browser session identification
 	AuthMethodAPIKey         // Added for API key authentication
 	AuthMethodLocalSubnet    // Added for local subnet bypass authentication
+	AuthMethodTrustedProxy   // Identity asserted by a trusted reverse proxy (forward auth)
 	// NOTE: Remember to run `go generate` in this directory after adding new methods.
 )
 
@@ -77,6 +79,11 @@ type Service interface {
 	// Handles session fixation mitigation by clearing old session first.
 	EstablishSession(c echo.Context, accessToken string) error
 
+	// AuthenticateProxy resolves the identity asserted by a trusted reverse proxy.
+	// Returns security.ErrProxyAuthNotApplicable when other methods should be tried,
+	// or another security.ErrProxy*/ErrUntrustedProxy error when the request must be rejected.
+	AuthenticateProxy(c echo.Context) (*security.ProxyIdentity, error)
+
 	// IsAuthenticated checks if a request is authenticated via any supported method.
 	// Returns true if auth is bypassed (not required) or if token/session auth succeeds.
 	IsAuthenticated(c echo.Context) bool
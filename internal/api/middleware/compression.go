@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"strings"
+
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
 )
@@ -12,17 +14,19 @@ const (
 )
 
 // NewGzip creates a gzip compression middleware with the default compression level.
-// It automatically skips compression for SSE endpoints.
+// It automatically skips compression for SSE endpoints and audio streams.
 func NewGzip() echo.MiddlewareFunc {
 	return NewGzipWithLevel(DefaultGzipLevel)
 }
 
 // NewGzipWithLevel creates a gzip compression middleware with a custom compression level.
-// It automatically skips compression for SSE endpoints.
+// It automatically skips compression for SSE endpoints and audio streams.
 func NewGzipWithLevel(level int) echo.MiddlewareFunc {
 	return middleware.GzipWithConfig(middleware.GzipConfig{
-		Level:   level,
-		Skipper: SSESkipper,
+		Level: level,
+		Skipper: func(c echo.Context) bool {
+			return SSESkipper(c) || AudioStreamSkipper(c)
+		},
 	})
 }
 
@@ -34,6 +38,12 @@ func NewGzipWithSkipper(level int, skipper middleware.Skipper) echo.MiddlewareFu
 	})
 }
 
+// AudioStreamSkipper skips compression for the Icecast audio streams, whose
+// players expect the encoded audio as is.
+func AudioStreamSkipper(c echo.Context) bool {
+	return strings.HasPrefix(c.Request().URL.Path, "/stream/")
+}
+
 // SSESkipper is a skipper function that skips compression for Server-Sent Events endpoints.
 func SSESkipper(c echo.Context) bool {
 	return c.Request().Header.Get("Accept") == "text/event-stream"
@@ -89,6 +89,16 @@ func DefaultCSRFSkipper(c echo.Context) bool {
 		return true
 	}
 
+	// Skip for push ingest, remote recorders authenticate with a device token
+	if strings.HasPrefix(path, "/api/v2/ingest/") {
+		return true
+	}
+
+	// Skip for hub sync, stations authenticate with a station token
+	if strings.HasPrefix(path, "/api/v2/hub/stations/") {
+		return true
+	}
+
 	// Skip for auth endpoints (login handles auth, logout is low-risk)
 	if path == "/api/v2/auth/login" ||
 		path == "/api/v2/auth/logout" ||
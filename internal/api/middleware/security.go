@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -98,6 +99,35 @@ func NewSecureHeaders(config SecurityConfig) echo.MiddlewareFunc {
 }
 
 // NewBodyLimit creates a middleware that limits the request body size.
+// Audio pushed by remote recorders may be streamed in one long request, so
+// uploads to the ingest endpoint are exempt; ingest bounds its own buffering.
+// Clips uploaded by hub stations are exempt too, the hub bounds their size.
+// It is used both server-wide and on the /api/v2 group.
 func NewBodyLimit(limit string) echo.MiddlewareFunc {
-	return middleware.BodyLimit(limit)
+	return middleware.BodyLimitWithConfig(middleware.BodyLimitConfig{
+		Limit:   limit,
+		Skipper: skipBodyLimit,
+	})
+}
+
+// skipBodyLimit reports whether a request streams a body the body limit must not cut off.
+func skipBodyLimit(c echo.Context) bool {
+	req := c.Request()
+	switch req.Method {
+	case http.MethodPost:
+		return strings.HasPrefix(req.URL.Path, "/api/v2/ingest/")
+	case http.MethodPut:
+		return isHubClipUpload(req.URL.Path)
+	}
+	return false
+}
+
+// isHubClipUpload reports whether path is /api/v2/hub/stations/:station/clips/*.
+func isHubClipUpload(path string) bool {
+	rest, ok := strings.CutPrefix(path, "/api/v2/hub/stations/")
+	if !ok {
+		return false
+	}
+	station, clip, ok := strings.Cut(rest, "/")
+	return ok && station != "" && strings.HasPrefix(clip, "clips/")
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/tphakala/birdnet-go/internal/alerting"
@@ -26,6 +27,11 @@ func (c *Controller) initAlertRoutes() {
 	// Initialize repository lazily from V2Manager
 	c.alertRuleRepo = repository.NewAlertRuleRepository(c.V2Manager.DB())
 
+	// Resolve taxonomic groups for the in_group condition operator
+	if c.TaxonomyDB != nil {
+		alerting.SetSpeciesGroupResolver(c.speciesTaxonomyGroups)
+	}
+
 	// Initialize the alerting engine — seeds default rules and starts event processing
 	eventBus := alerting.NewAlertEventBus()
 	engine, err := alerting.Initialize(c.alertRuleRepo, eventBus, GetLogger())
@@ -44,6 +50,7 @@ func (c *Controller) initAlertRoutes() {
 	alerts.GET("/rules", c.ListAlertRules)
 	alerts.GET("/rules/:id", c.GetAlertRule)
 	alerts.GET("/history", c.ListAlertHistory)
+	alerts.GET("/open", c.ListOpenAlerts)
 
 	// Protected endpoints
 	protected := alerts.Group("", c.authMiddleware)
@@ -56,6 +63,18 @@ func (c *Controller) initAlertRoutes() {
 	protected.POST("/rules/reset-defaults", c.ResetDefaultAlertRules)
 	protected.POST("/rules/import", c.ImportAlertRules)
 	protected.DELETE("/history", c.ClearAlertHistory)
+	protected.POST("/open/:id/acknowledge", c.AcknowledgeAlert)
+	protected.POST("/open/:id/resolve", c.ResolveAlert)
+}
+
+// speciesTaxonomyGroups returns the genus, family and order of a species from
+// the local taxonomy database.
+func (c *Controller) speciesTaxonomyGroups(scientificName string) []string {
+	genus, meta, err := c.TaxonomyDB.GetGenusByScientificName(scientificName)
+	if err != nil {
+		return nil
+	}
+	return []string{genus, meta.Family, meta.FamilyCommon, meta.Order}
 }
 
 // requireV2 checks that the enhanced database is available and returns an error response if not.
@@ -142,6 +161,9 @@ func (c *Controller) CreateAlertRule(ctx echo.Context) error {
 	if rule.ObjectType == "" || rule.TriggerType == "" {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Object type and trigger type are required"})
 	}
+	if err := alerting.ValidateRule(&rule); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
 
 	// Prevent duplicate names
 	count, err := c.alertRuleRepo.CountRulesByName(ctx.Request().Context(), rule.Name)
@@ -199,6 +221,9 @@ func (c *Controller) UpdateAlertRule(ctx echo.Context) error {
 	if rule.ObjectType == "" || rule.TriggerType == "" {
 		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Object type and trigger type are required"})
 	}
+	if err := alerting.ValidateRule(&rule); err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": err.Error()})
+	}
 
 	rule.ID = existing.ID
 	rule.CreatedAt = existing.CreatedAt
@@ -385,6 +410,67 @@ func (c *Controller) ClearAlertHistory(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, map[string]any{"deleted": deleted})
 }
 
+// ListOpenAlerts returns alerts that are firing or acknowledged but not yet resolved.
+func (c *Controller) ListOpenAlerts(ctx echo.Context) error {
+	if !datastoreV2.IsEnhancedDatabase() {
+		return c.requireV2(ctx)
+	}
+
+	items, err := c.alertRuleRepo.ListOpenAlerts(ctx.Request().Context())
+	if err != nil {
+		c.logErrorIfEnabled("failed to list open alerts", logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to list open alerts", http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"alerts": items,
+		"total":  len(items),
+	})
+}
+
+// AcknowledgeAlert acknowledges a firing alert, stopping its escalation.
+func (c *Controller) AcknowledgeAlert(ctx echo.Context) error {
+	return c.updateOpenAlert(ctx, "acknowledge", func(engine *alerting.Engine, id uint) error {
+		if engine != nil {
+			return engine.Acknowledge(ctx.Request().Context(), id)
+		}
+		return c.alertRuleRepo.AcknowledgeAlert(ctx.Request().Context(), id, time.Now())
+	})
+}
+
+// ResolveAlert manually resolves an open alert.
+func (c *Controller) ResolveAlert(ctx echo.Context) error {
+	return c.updateOpenAlert(ctx, "resolve", func(engine *alerting.Engine, id uint) error {
+		if engine != nil {
+			return engine.Resolve(ctx.Request().Context(), id)
+		}
+		return c.alertRuleRepo.ResolveAlert(ctx.Request().Context(), id, time.Now())
+	})
+}
+
+// updateOpenAlert parses the alert ID and applies a lifecycle change to it.
+func (c *Controller) updateOpenAlert(ctx echo.Context, operation string, update func(*alerting.Engine, uint) error) error {
+	if !datastoreV2.IsEnhancedDatabase() {
+		return c.requireV2(ctx)
+	}
+
+	id, err := parseUintParam(ctx, "id")
+	if err != nil {
+		return ctx.JSON(http.StatusBadRequest, map[string]string{"error": "Invalid alert ID"})
+	}
+
+	if err := update(c.alertEngine, id); err != nil {
+		if errors.Is(err, repository.ErrOpenAlertNotFound) {
+			return ctx.JSON(http.StatusNotFound, map[string]string{"error": "Open alert not found"})
+		}
+		c.logErrorIfEnabled("failed to update alert",
+			logger.String("operation", operation), logger.Uint64("id", uint64(id)), logger.Error(err))
+		return c.HandleError(ctx, err, "Failed to "+operation+" alert", http.StatusInternalServerError)
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{"id": id, "operation": operation})
+}
+
 // ExportAlertRules exports all rules as JSON.
 func (c *Controller) ExportAlertRules(ctx echo.Context) error {
 	if !datastoreV2.IsEnhancedDatabase() {
@@ -422,6 +508,11 @@ func (c *Controller) ImportAlertRules(ctx echo.Context) error {
 	var imported int
 	for i := range payload.Rules {
 		rule := &payload.Rules[i]
+		if err := alerting.ValidateRule(rule); err != nil {
+			c.logErrorIfEnabled("skipping invalid imported rule",
+				logger.String("name", rule.Name), logger.Error(err))
+			continue
+		}
 		// Reset IDs for import
 		rule.ID = 0
 		for j := range rule.Conditions {
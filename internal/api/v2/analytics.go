@@ -600,7 +600,7 @@ func (c *Controller) GetSpeciesSummary(ctx echo.Context) error {
 // fetchSpeciesSummaryData fetches species summary data with timing
 func (c *Controller) fetchSpeciesSummaryData(ctx echo.Context, startDate, endDate string) ([]datastore.SpeciesSummaryData, time.Duration, error) {
 	dbStart := time.Now()
-	summaryData, err := c.DS.GetSpeciesSummaryData(ctx.Request().Context(), startDate, endDate)
+	summaryData, err := c.DS.GetSpeciesSummaryData(stationContext(ctx), startDate, endDate)
 	return summaryData, time.Since(dbStart), err
 }
 
@@ -732,7 +732,7 @@ func (c *Controller) GetHourlyAnalytics(ctx echo.Context) error {
 	)
 
 	// Add timeout to prevent resource exhaustion
-	ctxWithTimeout, cancel := context.WithTimeout(ctx.Request().Context(), analyticsQueryTimeout)
+	ctxWithTimeout, cancel := context.WithTimeout(stationContext(ctx), analyticsQueryTimeout)
 	defer cancel()
 
 	// Get hourly analytics data from the datastore
@@ -834,7 +834,7 @@ func (c *Controller) GetDailyAnalytics(ctx echo.Context) error {
 	)
 
 	// Add timeout to prevent resource exhaustion
-	ctxWithTimeout, cancel := context.WithTimeout(ctx.Request().Context(), analyticsQueryTimeout)
+	ctxWithTimeout, cancel := context.WithTimeout(stationContext(ctx), analyticsQueryTimeout)
 	defer cancel()
 
 	// Get daily analytics data from the datastore
@@ -947,7 +947,7 @@ func (c *Controller) GetSpeciesDiversity(ctx echo.Context) error {
 	)
 
 	// Add timeout to prevent resource exhaustion
-	ctxWithTimeout, cancel := context.WithTimeout(ctx.Request().Context(), analyticsQueryTimeout)
+	ctxWithTimeout, cancel := context.WithTimeout(stationContext(ctx), analyticsQueryTimeout)
 	defer cancel()
 
 	diversityData, err := c.DS.GetSpeciesDiversityData(ctxWithTimeout, startDate, endDate)
@@ -1038,7 +1038,7 @@ func (c *Controller) GetTimeOfDayDistribution(ctx echo.Context) error {
 	}
 
 	// Get hourly distribution data from the datastore
-	hourlyData, err := c.DS.GetHourlyDistribution(ctx.Request().Context(), startDate, endDate, speciesParam)
+	hourlyData, err := c.DS.GetHourlyDistribution(stationContext(ctx), startDate, endDate, speciesParam)
 	if err != nil {
 		return c.HandleError(ctx, err, "Failed to get hourly distribution data", http.StatusInternalServerError)
 	}
@@ -1358,7 +1358,7 @@ func (c *Controller) processHourlyBatchSpecies(ctx echo.Context, speciesParams [
 		}
 		seen[speciesItem] = true
 
-		hourlyData, err := c.DS.GetHourlyAnalyticsData(ctx.Request().Context(), date, speciesItem)
+		hourlyData, err := c.DS.GetHourlyAnalyticsData(stationContext(ctx), date, speciesItem)
 		if err != nil {
 			processingErrors = append(processingErrors, fmt.Sprintf("Failed to get hourly data for species %s: %v", speciesItem, err))
 			c.logErrorIfEnabled("Error getting hourly data for species in batch request",
@@ -1463,7 +1463,7 @@ func (c *Controller) processDailyBatchSpecies(ctx echo.Context, uniqueSpecies []
 	processingErrors = make([]string, 0)
 
 	for _, speciesItem := range uniqueSpecies {
-		dailyData, err := c.DS.GetDailyAnalyticsData(ctx.Request().Context(), startDate, endDate, speciesItem)
+		dailyData, err := c.DS.GetDailyAnalyticsData(stationContext(ctx), startDate, endDate, speciesItem)
 		if err != nil {
 			processingErrors = append(processingErrors, fmt.Sprintf("Failed to get daily data for species %s: %v", speciesItem, err))
 			c.logErrorIfEnabled("Error getting daily data for species in batch request",
@@ -1534,3 +1534,9 @@ func (c *Controller) handleBatchDailyResults(ctx echo.Context, results map[strin
 	)
 	return ctx.JSON(http.StatusOK, results)
 }
+
+// stationContext returns the request context limited to the detections of the
+// station named by the "station" query parameter, if any.
+func stationContext(ctx echo.Context) context.Context {
+	return datastore.WithStation(ctx.Request().Context(), ctx.QueryParam("station"))
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/alerting"
 	"github.com/tphakala/birdnet-go/internal/analysis/processor"
 	"github.com/tphakala/birdnet-go/internal/api/auth"
+	mw "github.com/tphakala/birdnet-go/internal/api/middleware"
 	"github.com/tphakala/birdnet-go/internal/birdnet"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/datastore"
@@ -26,10 +27,12 @@ import (
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
 	"github.com/tphakala/birdnet-go/internal/ebird"
 	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/hub"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/myaudio"
 	"github.com/tphakala/birdnet-go/internal/observability"
+	"github.com/tphakala/birdnet-go/internal/reanalysis"
 	"github.com/tphakala/birdnet-go/internal/securefs"
 	"github.com/tphakala/birdnet-go/internal/spectrogram"
 	"github.com/tphakala/birdnet-go/internal/suncalc"
@@ -96,6 +99,14 @@ type Controller struct {
 	alertRuleRepo repository.AlertRuleRepository
 	alertEngine   *alerting.Engine
 
+	// Multi-station hub fields (initialized in initHubRoutes)
+	stationRepo repository.StationRepository
+	hubReceiver *hub.Receiver
+
+	// Re-analysis job fields (initialized in initReanalysisRoutes)
+	reanalysisRepo   repository.ReanalysisRepository
+	reanalysisRunner *reanalysis.Runner
+
 	// Legacy cleanup state tracker
 	cleanupStatus *CleanupStatus
 
@@ -368,8 +379,8 @@ func NewWithOptions(e *echo.Echo, ds datastore.Interface, settings *conf.Setting
 	// c.Group.Use(middleware.Logger())        // Removed: Use custom LoggingMiddleware below for structured logging
 	// NOTE: CORS middleware is configured at the global Echo level in server.go
 	// Removing duplicate CORS here to avoid conflicts with global CORS configuration
-	c.Group.Use(middleware.BodyLimit("1M")) // Limit request body to 1MB to prevent DoS attacks
-	c.Group.Use(c.LoggingMiddleware())      // Use custom structured logging middleware
+	c.Group.Use(mw.NewBodyLimit("1M")) // Limit request body to 1MB to prevent DoS attacks, streaming ingest is exempt
+	c.Group.Use(c.LoggingMiddleware()) // Use custom structured logging middleware
 
 	// NOTE: CSRF token is provided by the /app/config endpoint using middleware.EnsureCSRFToken()
 	// which handles Echo v4.15.0's Sec-Fetch-Site optimization that may skip token generation
@@ -504,6 +515,12 @@ func (c *Controller) initRoutes() {
 		{"species routes", c.initSpeciesRoutes},
 		{"dynamic threshold routes", c.initDynamicThresholdRoutes},
 		{"alert routes", c.initAlertRoutes},
+		{"archive routes", c.initArchiveRoutes},
+		{"ingest routes", c.initIngestRoutes},
+		{"icecast routes", c.initIcecastRoutes},
+		{"hub routes", c.initHubRoutes},
+		{"reanalysis routes", c.initReanalysisRoutes},
+		{"simulator routes", c.initSimulatorRoutes},
 	}
 
 	for _, initializer := range routeInitializers {
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// archiveEncodeTimeout bounds WAV encoding of an archive range
+const archiveEncodeTimeout = 30 * time.Second
+
+// initArchiveRoutes registers the continuous audio archive endpoints
+func (c *Controller) initArchiveRoutes() {
+	archive := c.Group.Group("/archive", c.authMiddleware)
+	archive.GET("/sources", c.GetArchiveSources)
+	archive.GET("/segments", c.GetArchiveSegments)
+	archive.GET("/audio", c.GetArchiveAudio)
+}
+
+// GetArchiveSources handles GET /api/v2/archive/sources
+// Lists the archived sources with their segment counts and time spans
+func (c *Controller) GetArchiveSources(ctx echo.Context) error {
+	sources, err := myaudio.ListArchiveSources(c.Settings.Realtime.Audio.Archive.Path)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list archived sources", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, sources)
+}
+
+// GetArchiveSegments handles GET /api/v2/archive/segments?source=&start=&end=
+// Lists the archived segments of a source overlapping a time range
+func (c *Controller) GetArchiveSegments(ctx echo.Context) error {
+	source, start, end, err := parseArchiveRangeParams(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+	segments, err := myaudio.ListArchiveSegments(c.Settings.Realtime.Audio.Archive.Path, source, start, end)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list archived segments", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, segments)
+}
+
+// GetArchiveAudio handles GET /api/v2/archive/audio?source=&start=&end=
+// Returns the archived audio of a source between start and end as a WAV file,
+// stitched across segment boundaries
+func (c *Controller) GetArchiveAudio(ctx echo.Context) error {
+	source, start, end, err := parseArchiveRangeParams(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	pcm, channels, err := myaudio.ReadArchiveRange(c.Settings.Realtime.Audio.Archive.Path, source, start, end)
+	switch {
+	case errors.Is(err, myaudio.ErrArchiveNoAudio):
+		return c.HandleError(ctx, err, "No archived audio in the requested range", http.StatusNotFound)
+	case err != nil:
+		var enhanced *errors.EnhancedError
+		if errors.As(err, &enhanced) && enhanced.Category == errors.CategoryValidation {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+		return c.HandleError(ctx, err, "Failed to read archived audio", http.StatusInternalServerError)
+	}
+
+	encodeCtx, cancel := context.WithTimeout(ctx.Request().Context(), archiveEncodeTimeout)
+	defer cancel()
+	wav, err := myaudio.EncodeInterleavedPCMtoWAVWithContext(encodeCtx, pcm, channels)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to encode archived audio", http.StatusInternalServerError)
+	}
+
+	filename := fmt.Sprintf("%s_%s.wav", source, start.UTC().Format("20060102T150405Z"))
+	ctx.Response().Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	return ctx.Stream(http.StatusOK, "audio/wav", wav)
+}
+
+// parseArchiveRangeParams reads the source and RFC3339 time range query parameters.
+// The source is an archive source name or the ID of a running audio source.
+func parseArchiveRangeParams(ctx echo.Context) (source string, start, end time.Time, err error) {
+	source = ctx.QueryParam("source")
+	if source == "" {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("source parameter is required")
+	}
+	if key, registered := myaudio.ArchiveKeyOfSource(source); registered {
+		source = key
+	} else {
+		source = myaudio.ArchiveSourceKey(source)
+	}
+
+	start, err = time.Parse(time.RFC3339, ctx.QueryParam("start"))
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("start must be an RFC3339 timestamp")
+	}
+	end, err = time.Parse(time.RFC3339, ctx.QueryParam("end"))
+	if err != nil {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("end must be an RFC3339 timestamp")
+	}
+	if !end.After(start) {
+		return "", time.Time{}, time.Time{}, fmt.Errorf("end must be after start")
+	}
+	return source, start, end, nil
+}
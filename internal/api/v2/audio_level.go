@@ -478,6 +478,18 @@ func (c *Controller) getAnonymizedSourceName(source *myaudio.AudioSource) string
 		return fmt.Sprintf("camera-%s", idPrefix)
 	case myaudio.SourceTypeFile:
 		return "file-source"
+	case myaudio.SourceTypePush:
+		idPrefix := source.ID
+		if len(source.ID) > anonymizedIDPrefixLen {
+			idPrefix = source.ID[:anonymizedIDPrefixLen]
+		}
+		return fmt.Sprintf("recorder-%s", idPrefix)
+	case myaudio.SourceTypeBrowser:
+		idPrefix := source.ID
+		if len(source.ID) > anonymizedIDPrefixLen {
+			idPrefix = source.ID[:anonymizedIDPrefixLen]
+		}
+		return fmt.Sprintf("session-%s", idPrefix)
 	default:
 		return "unknown-source"
 	}
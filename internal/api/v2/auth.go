@@ -59,6 +59,7 @@ type AuthStatus struct {
 	Authenticated bool   `json:"authenticated"`
 	Username      string `json:"username,omitempty"`
 	Method        string `json:"auth_method,omitempty"`
+	ReadOnly      bool   `json:"read_only,omitempty"`
 }
 
 // initAuthRoutes registers all authentication-related API endpoints
@@ -318,6 +319,7 @@ func (c *Controller) GetAuthStatus(ctx echo.Context) error {
 		Authenticated: isAuthenticated,
 		Username:      username,
 		Method:        authMethod,
+		ReadOnly:      boolFromCtx(ctx, auth.CtxKeyReadOnly, false),
 	}
 
 	c.logInfoIfEnabled("Auth status check",
@@ -18,6 +18,7 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/shirou/gopsutil/v3/disk"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"gorm.io/gorm"
@@ -340,7 +341,7 @@ func (c *Controller) initBackupRoutes() {
 	protectedGroup.POST("", c.StartBackupJob)
 	protectedGroup.GET("", c.ListBackupJobs)
 	protectedGroup.GET("/:id", c.GetBackupJobStatus)
-	protectedGroup.GET("/:id/download", c.DownloadBackupFile)
+	protectedGroup.GET("/:id/download", c.DownloadBackupFile, auth.RequireWrite)
 	protectedGroup.DELETE("/:id", c.CancelBackupJob)
 }
 
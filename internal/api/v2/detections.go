@@ -2,6 +2,7 @@
 package api
 
 import (
+	"cmp"
 	"fmt"
 	"maps"
 	"net/http"
@@ -252,8 +253,9 @@ func (c *Controller) parseDetectionQueryParams(ctx echo.Context) (*detectionQuer
 		TimeOfDay:  ctx.QueryParam("timeOfDay"),
 		HourRange:  ctx.QueryParam("hourRange"),
 		Verified:   ctx.QueryParam("verified"),
-		Location:   ctx.QueryParam("location"),
-		Locked:     ctx.QueryParam("locked"),
+		// station is the hub name for the node a detection came from
+		Location: cmp.Or(ctx.QueryParam("location"), ctx.QueryParam("station")),
+		Locked:   ctx.QueryParam("locked"),
 		// Sorting
 		SortBy: ctx.QueryParam("sortBy"),
 		// Include weather data
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
 
@@ -42,7 +43,7 @@ func (c *Controller) initFileSystemRoutes() {
 	fsGroup := c.Group.Group("/filesystem", c.authMiddleware)
 
 	// GET /api/v2/filesystem/browse - Browse files and directories
-	fsGroup.GET("/browse", c.BrowseFileSystem)
+	fsGroup.GET("/browse", c.BrowseFileSystem, auth.RequireWrite)
 
 	c.logInfoIfEnabled("Filesystem routes initialized")
 }
@@ -0,0 +1,192 @@
+package api
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/hub"
+)
+
+// HubStationStatus describes a station known to the hub
+type HubStationStatus struct {
+	Name         string     `json:"name"`
+	URL          string     `json:"url,omitempty"`
+	Configured   bool       `json:"configured"`        // the station may push, it has a token in the hub settings
+	Detections   int64      `json:"detections"`        // detections the station pushed
+	LastRemoteID uint       `json:"last_remote_id"`    // sync cursor of the station
+	LastSyncAt   *time.Time `json:"last_sync_at"`      // time of the last push
+	Version      string     `json:"version,omitempty"` // BirdNET-Go version the station reported
+}
+
+// initHubRoutes registers the multi-station hub endpoints. Stations
+// authenticate with their station token instead of a user session.
+func (c *Controller) initHubRoutes() {
+	if c.V2Manager == nil {
+		return
+	}
+
+	c.stationRepo = repository.NewStationRepository(c.V2Manager.DB())
+	c.hubReceiver = hub.NewReceiver(c.DS, c.stationRepo, c.Settings.Realtime.Audio.Export.Path)
+
+	c.Group.GET("/hub/stations/:station/cursor", c.GetHubCursor)
+	c.Group.POST("/hub/stations/:station/detections", c.PushHubDetections)
+	c.Group.PUT("/hub/stations/:station/clips/*", c.PushHubClip)
+	c.Group.GET("/hub/stations", c.GetHubStations, c.authMiddleware)
+	c.Group.GET("/hub/detections/:id/origin", c.GetHubDetectionOrigin, c.authMiddleware)
+}
+
+// authenticateStation checks the station token of a push. It returns the
+// station name, or an empty name once the error response is sent; the
+// returned error is the result of sending it and is usually nil.
+func (c *Controller) authenticateStation(ctx echo.Context) (string, error) {
+	if !c.Settings.Hub.Enabled {
+		return "", c.HandleError(ctx, errors.NewStd("hub mode is disabled"), "Hub mode is disabled", http.StatusNotFound)
+	}
+
+	name := ctx.Param("station")
+	station, ok := c.Settings.Hub.StationByName(name)
+	token, hasToken := strings.CutPrefix(ctx.Request().Header.Get("Authorization"), "Bearer ")
+	// Compare against a token even for unknown stations so both fail alike
+	if !hasToken || subtle.ConstantTimeCompare([]byte(token), []byte(station.Token)) != 1 || !ok {
+		return "", c.HandleError(ctx, errors.NewStd("invalid station credentials"), "Invalid station or token", http.StatusUnauthorized)
+	}
+	return name, nil
+}
+
+// GetHubCursor handles GET /api/v2/hub/stations/:station/cursor
+// Returns the highest detection ID of the station the hub stored; the station
+// pushes the detections after it.
+func (c *Controller) GetHubCursor(ctx echo.Context) error {
+	station, err := c.authenticateStation(ctx)
+	if station == "" {
+		return err
+	}
+	cursor, err := c.hubReceiver.Cursor(ctx.Request().Context(), station)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to read station cursor", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, hub.Cursor{Station: station, Cursor: cursor})
+}
+
+// PushHubDetections handles POST /api/v2/hub/stations/:station/detections
+// Stores a batch of station detections. Detections stored before are skipped,
+// so a station can safely resend a batch.
+func (c *Controller) PushHubDetections(ctx echo.Context) error {
+	station, err := c.authenticateStation(ctx)
+	if station == "" {
+		return err
+	}
+
+	var batch hub.Batch
+	if err := ctx.Bind(&batch); err != nil {
+		return c.HandleError(ctx, err, "Invalid detection batch", http.StatusBadRequest)
+	}
+
+	result, err := c.hubReceiver.Receive(ctx.Request().Context(), station, &batch)
+	if err != nil {
+		var enhanced *errors.EnhancedError
+		if errors.As(err, &enhanced) && enhanced.Category == errors.CategoryValidation {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+		return c.HandleError(ctx, err, "Failed to store detections", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, result)
+}
+
+// PushHubClip handles PUT /api/v2/hub/stations/:station/clips/*
+// Stores the audio clip of a station detection under its path on the station.
+// Clips are uploaded before the detections referencing them.
+func (c *Controller) PushHubClip(ctx echo.Context) error {
+	station, err := c.authenticateStation(ctx)
+	if station == "" {
+		return err
+	}
+
+	clipName, err := url.PathUnescape(ctx.Param("*"))
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid clip name", http.StatusBadRequest)
+	}
+	if err := c.hubReceiver.SaveClip(station, clipName, ctx.Request().Body); err != nil {
+		if errors.Is(err, hub.ErrClipTooLarge) {
+			return c.HandleError(ctx, err, err.Error(), http.StatusRequestEntityTooLarge)
+		}
+		var enhanced *errors.EnhancedError
+		if errors.As(err, &enhanced) && enhanced.Category == errors.CategoryValidation {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+		return c.HandleError(ctx, err, "Failed to store clip", http.StatusInternalServerError)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// GetHubStations handles GET /api/v2/hub/stations
+// Lists the configured stations and every station that pushed detections,
+// with its sync state.
+func (c *Controller) GetHubStations(ctx echo.Context) error {
+	stored, err := c.stationRepo.ListStations(ctx.Request().Context())
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list stations", http.StatusInternalServerError)
+	}
+
+	statuses := make([]HubStationStatus, 0, len(c.Settings.Hub.Stations)+len(stored))
+	index := make(map[string]int, len(c.Settings.Hub.Stations))
+	for _, s := range c.Settings.Hub.Stations {
+		index[s.Name] = len(statuses)
+		statuses = append(statuses, HubStationStatus{Name: s.Name, URL: s.URL, Configured: true})
+	}
+	for i := range stored {
+		s := &stored[i]
+		pos, ok := index[s.Name]
+		if !ok {
+			pos = len(statuses)
+			statuses = append(statuses, HubStationStatus{Name: s.Name})
+		}
+		statuses[pos].Detections = s.Detections
+		statuses[pos].LastRemoteID = s.LastRemoteID
+		statuses[pos].LastSyncAt = s.LastSyncAt
+		statuses[pos].Version = s.Version
+	}
+
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"enabled":  c.Settings.Hub.Enabled,
+		"stations": statuses,
+	})
+}
+
+// GetHubDetectionOrigin handles GET /api/v2/hub/detections/:id/origin
+// Returns the station a detection came from and, when the station's web
+// address is configured, where its clip and spectrogram are served there.
+func (c *Controller) GetHubDetectionOrigin(ctx echo.Context) error {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		return c.HandleError(ctx, err, "Invalid detection ID", http.StatusBadRequest)
+	}
+
+	sd, err := c.stationRepo.GetStationDetection(ctx.Request().Context(), uint(id))
+	if errors.Is(err, repository.ErrStationNotFound) {
+		return c.HandleError(ctx, err, "Detection was not pushed by a station", http.StatusNotFound)
+	}
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to read detection origin", http.StatusInternalServerError)
+	}
+
+	origin := hub.Origin{RemoteID: sd.RemoteID}
+	if sd.Station != nil {
+		origin.Station = sd.Station.Name
+	}
+	if station, ok := c.Settings.Hub.StationByName(origin.Station); ok && station.URL != "" {
+		base := strings.TrimRight(station.URL, "/")
+		origin.StationURL = base
+		origin.AudioURL = fmt.Sprintf("%s/api/v2/audio/%d", base, sd.RemoteID)
+		origin.SpectrogramURL = fmt.Sprintf("%s/api/v2/spectrogram/%d", base, sd.RemoteID)
+	}
+	return ctx.JSON(http.StatusOK, origin)
+}
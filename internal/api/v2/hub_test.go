@@ -0,0 +1,118 @@
+package api
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	datastoreV2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
+	"github.com/tphakala/birdnet-go/internal/hub"
+)
+
+const testStationToken = "0123456789abcdef"
+
+// setupHubTestEnvironment creates a controller accepting pushes of the station
+// "meadow", with its hub routes behind the full /api/v2 middleware stack.
+func setupHubTestEnvironment(t *testing.T) (*echo.Echo, *Controller) {
+	t.Helper()
+	e, _, controller := setupTestEnvironment(t)
+
+	mgr, err := datastoreV2.NewSQLiteManager(datastoreV2.Config{DirectPath: filepath.Join(t.TempDir(), "hub.db")})
+	require.NoError(t, err)
+	require.NoError(t, mgr.Initialize())
+	t.Cleanup(func() { _ = mgr.Close() })
+
+	controller.V2Manager = mgr
+	controller.Settings.Hub = conf.HubSettings{
+		Enabled:  true,
+		Stations: []conf.HubStation{{Name: "meadow", Token: testStationToken}},
+	}
+	controller.initHubRoutes()
+	return e, controller
+}
+
+func TestPushHubClip_LargerThanGroupBodyLimit(t *testing.T) {
+	e, controller := setupHubTestEnvironment(t)
+
+	// A default length WAV clip is about 1.44 MB
+	clip := make([]byte, 1440044)
+	req := httptest.NewRequest(http.MethodPut, "/api/v2/hub/stations/meadow/clips/2026/05/turdus_merula.wav", bytes.NewReader(clip))
+	req.Header.Set("Authorization", "Bearer "+testStationToken)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusNoContent, rec.Code, rec.Body.String())
+	info, err := os.Stat(filepath.Join(controller.Settings.Realtime.Audio.Export.Path, "stations", "meadow", "2026", "05", "turdus_merula.wav"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(clip)), info.Size())
+
+	req = httptest.NewRequest(http.MethodPut, "/api/v2/hub/stations/meadow/clips/2026/05/huge.wav",
+		io.LimitReader(zeroReader{}, hub.MaxClipSize+1))
+	req.Header.Set("Authorization", "Bearer "+testStationToken)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+}
+
+// zeroReader streams zeros without a known length.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestHubStationRoutes_RejectBadCredentials(t *testing.T) {
+	e, controller := setupHubTestEnvironment(t)
+
+	batch := `{"version":"v1","detections":[{"id":1,"date":"2026-05-01","time":"06:30:00","scientific_name":"Turdus merula","confidence":0.9}]}`
+	requests := []struct {
+		method string
+		path   string
+		body   string
+	}{
+		{http.MethodGet, "/cursor", ""},
+		{http.MethodPost, "/detections", batch},
+		{http.MethodPut, "/clips/2026/05/turdus_merula.wav", "RIFF"},
+	}
+	credentials := []struct {
+		name    string
+		station string
+		auth    string
+	}{
+		{"wrong token", "meadow", "Bearer fedcba9876543210"},
+		{"missing token", "meadow", ""},
+		{"unknown station", "forest", "Bearer " + testStationToken},
+	}
+
+	for _, cred := range credentials {
+		for _, r := range requests {
+			t.Run(cred.name+" "+r.method+" "+r.path, func(t *testing.T) {
+				req := httptest.NewRequest(r.method, "/api/v2/hub/stations/"+cred.station+r.path, strings.NewReader(r.body))
+				req.Header.Set("Content-Type", "application/json")
+				if cred.auth != "" {
+					req.Header.Set("Authorization", cred.auth)
+				}
+				rec := httptest.NewRecorder()
+				e.ServeHTTP(rec, req)
+				assert.Equal(t, http.StatusUnauthorized, rec.Code)
+			})
+		}
+	}
+
+	// The datastore mock fails the test on any save; nothing else was written either
+	stations, err := controller.stationRepo.ListStations(t.Context())
+	require.NoError(t, err)
+	assert.Empty(t, stations, "no station was registered")
+	_, err = os.Stat(filepath.Join(controller.Settings.Realtime.Audio.Export.Path, "stations"))
+	assert.True(t, os.IsNotExist(err), "no clip was stored")
+}
@@ -0,0 +1,130 @@
+package api
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// icecastWriteTimeout is the longest a listener may stall a single write
+const icecastWriteTimeout = 30 * time.Second
+
+// initIcecastRoutes registers the Icecast-compatible stream output. It lives
+// outside /api/v2 so media players get a short, stable URL.
+func (c *Controller) initIcecastRoutes() {
+	c.Echo.GET("/stream/:source", c.ServeIcecastStream, c.icecastAuth)
+}
+
+// ServeIcecastStream handles GET /stream/:source
+// Streams the live audio of a source, looked up by ID or display name, as MP3
+// or Ogg/Opus with Icecast response headers so players like VLC and smart
+// speakers can tune in. The audio is what the analysis hears, after the
+// equalizer of the source, optionally with noise reduction.
+func (c *Controller) ServeIcecastStream(ctx echo.Context) error {
+	settings := c.Settings.WebServer.Icecast
+	if !settings.Enabled {
+		return c.HandleError(ctx, errors.NewStd("icecast streaming is disabled"), "Stream output is disabled", http.StatusNotFound)
+	}
+
+	source, ok := resolveIcecastSource(ctx.Param("source"), settings.Sources)
+	if !ok {
+		return c.HandleError(ctx, errors.NewStd("stream not found"), "Stream not found", http.StatusNotFound)
+	}
+
+	listener, err := myaudio.AddIcecastListener(source.ID, &settings)
+	switch {
+	case errors.Is(err, myaudio.ErrTooManyIcecastListeners):
+		return c.HandleError(ctx, err, err.Error(), http.StatusServiceUnavailable)
+	case err != nil:
+		return c.HandleError(ctx, err, "Failed to start stream", http.StatusInternalServerError)
+	}
+	defer listener.Close()
+
+	c.logInfoIfEnabled("Icecast listener connected",
+		logger.String("source_id", source.ID),
+		logger.String("ip", ctx.RealIP()))
+
+	header := ctx.Response().Header()
+	header.Set(echo.HeaderContentType, myaudio.IcecastContentType(settings.Format))
+	header.Set(echo.HeaderCacheControl, "no-cache, no-store")
+	header.Set("icy-name", source.DisplayName)
+	header.Set("icy-description", "BirdNET-Go live audio")
+	header.Set("icy-br", strconv.Itoa(settings.BitRate))
+	header.Set("icy-sr", strconv.Itoa(conf.SampleRate))
+	header.Set("icy-pub", "0")
+	ctx.Response().WriteHeader(http.StatusOK)
+
+	// The stream outlives the server write timeout, deadlines move per write
+	rc := http.NewResponseController(ctx.Response())
+	_ = rc.Flush()
+	for {
+		select {
+		case chunk, ok := <-listener.Chunks():
+			if !ok {
+				return nil
+			}
+			_ = rc.SetWriteDeadline(time.Now().Add(icecastWriteTimeout))
+			if _, err := ctx.Response().Write(chunk); err != nil {
+				return nil
+			}
+			if err := rc.Flush(); err != nil {
+				return nil
+			}
+		case <-ctx.Request().Context().Done():
+			return nil
+		case <-c.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// icecastAuth lets players in with the stream credentials over HTTP basic auth
+// and otherwise requires the usual login. Without a login the response
+// challenges for basic auth so players prompt for the stream credentials.
+func (c *Controller) icecastAuth(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(ctx echo.Context) error {
+		settings := c.Settings.WebServer.Icecast
+		if settings.Username != "" {
+			if user, pass, ok := ctx.Request().BasicAuth(); ok {
+				userOK := subtle.ConstantTimeCompare([]byte(user), []byte(settings.Username)) == 1
+				passOK := subtle.ConstantTimeCompare([]byte(pass), []byte(settings.Password)) == 1
+				if userOK && passOK {
+					return next(ctx)
+				}
+			}
+			ctx.Response().Header().Set(echo.HeaderWWWAuthenticate, `Basic realm="BirdNET-Go stream"`)
+		}
+		if c.authMiddleware == nil {
+			return next(ctx)
+		}
+		return c.authMiddleware(next)(ctx)
+	}
+}
+
+// resolveIcecastSource finds a source by ID or case-insensitive display name.
+// When exposed is not empty only the sources it names, by ID or display name,
+// can be streamed.
+func resolveIcecastSource(key string, exposed []string) (*myaudio.AudioSource, bool) {
+	matches := func(source *myaudio.AudioSource, name string) bool {
+		return source.ID == name || strings.EqualFold(source.DisplayName, name)
+	}
+	for _, source := range myaudio.GetRegistry().ListSources() {
+		if !matches(source, key) {
+			continue
+		}
+		if len(exposed) > 0 && !slices.ContainsFunc(exposed, func(name string) bool { return matches(source, name) }) {
+			return nil, false
+		}
+		return source, true
+	}
+	return nil, false
+}
@@ -0,0 +1,109 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// registerIcecastTestSource registers a source and removes it after the test
+func registerIcecastTestSource(t *testing.T, name string) *myaudio.AudioSource {
+	t.Helper()
+	source, err := myaudio.GetRegistry().RegisterSource("rtsp://icecast-test.example.com/"+name, myaudio.SourceConfig{
+		DisplayName: name,
+		Type:        myaudio.SourceTypeRTSP,
+	})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = myaudio.GetRegistry().RemoveSource(source.ID) })
+	return source
+}
+
+func TestResolveIcecastSource(t *testing.T) {
+	pond := registerIcecastTestSource(t, "Pond")
+	registerIcecastTestSource(t, "Meadow")
+
+	source, ok := resolveIcecastSource("pond", nil)
+	require.True(t, ok)
+	assert.Equal(t, pond.ID, source.ID)
+
+	source, ok = resolveIcecastSource(pond.ID, []string{"Pond"})
+	require.True(t, ok)
+	assert.Equal(t, pond.ID, source.ID)
+
+	_, ok = resolveIcecastSource("Meadow", []string{"Pond"})
+	assert.False(t, ok, "sources not listed are not exposed")
+	_, ok = resolveIcecastSource("Forest", nil)
+	assert.False(t, ok)
+}
+
+func TestServeIcecastStream_NotFound(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	registerIcecastTestSource(t, "Pond")
+
+	tests := []struct {
+		name     string
+		settings conf.IcecastSettings
+		source   string
+	}{
+		{"disabled", conf.IcecastSettings{}, "Pond"},
+		{"unknown source", conf.IcecastSettings{Enabled: true, MaxListeners: 1}, "Forest"},
+		{"source not exposed", conf.IcecastSettings{Enabled: true, MaxListeners: 1, Sources: []string{"Meadow"}}, "Pond"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			controller.Settings.WebServer.Icecast = tt.settings
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(httptest.NewRequest(http.MethodGet, "/stream/"+tt.source, http.NoBody), rec)
+			ctx.SetParamNames("source")
+			ctx.SetParamValues(tt.source)
+
+			require.NoError(t, controller.ServeIcecastStream(ctx))
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+		})
+	}
+}
+
+func TestIcecastAuth(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.Settings.WebServer.Icecast = conf.IcecastSettings{Enabled: true, Username: "player", Password: "secret"}
+	controller.authMiddleware = func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(ctx echo.Context) error {
+			return ctx.NoContent(http.StatusUnauthorized)
+		}
+	}
+	handler := controller.icecastAuth(func(ctx echo.Context) error {
+		return ctx.NoContent(http.StatusOK)
+	})
+
+	tests := []struct {
+		name     string
+		user     string
+		password string
+		want     int
+	}{
+		{"stream credentials", "player", "secret", http.StatusOK},
+		{"wrong password", "player", "guess", http.StatusUnauthorized},
+		{"no credentials", "", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/stream/Pond", http.NoBody)
+			if tt.user != "" {
+				req.SetBasicAuth(tt.user, tt.password)
+			}
+			rec := httptest.NewRecorder()
+
+			require.NoError(t, handler(e.NewContext(req, rec)))
+			assert.Equal(t, tt.want, rec.Code)
+			if tt.want == http.StatusUnauthorized {
+				assert.Contains(t, rec.Header().Get(echo.HeaderWWWAuthenticate), "Basic")
+			}
+		})
+	}
+}
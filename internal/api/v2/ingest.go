@@ -0,0 +1,116 @@
+package api
+
+import (
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+// ingestReadTimeout is the longest a streamed push may stall between reads
+const ingestReadTimeout = 30 * time.Second
+
+// initIngestRoutes registers the push ingest and browser live session
+// endpoints. Pushes authenticate with the token of the device instead of a
+// user session.
+func (c *Controller) initIngestRoutes() {
+	c.Group.POST("/ingest/:device", c.PushIngestAudio)
+	c.Group.GET("/ingest/devices", c.GetIngestDevices, c.authMiddleware)
+	c.Group.GET("/ingest/live", c.HandleLiveSessionWS, c.authMiddleware, auth.RequireWrite)
+	c.Group.GET("/ingest/sessions", c.GetLiveSessions, c.authMiddleware)
+}
+
+// PushIngestAudio handles POST /api/v2/ingest/:device?format=&rate=&channels=&ts=
+// Accepts audio from a remote recorder authenticated with "Authorization: Bearer <token>".
+// format is pcm (signed 16-bit little-endian, rate required, channels default 1), flac
+// or opus. ts is the device capture time of the first sample in Unix
+// milliseconds; without it the audio is taken as live. The body may be a
+// complete chunk or a stream read until the device closes it.
+func (c *Controller) PushIngestAudio(ctx echo.Context) error {
+	settings := c.Settings
+	if !settings.Realtime.Ingest.Enabled {
+		return c.HandleError(ctx, errors.NewStd("push ingest is disabled"), "Push ingest is disabled", http.StatusNotFound)
+	}
+
+	deviceID := ctx.Param("device")
+	device, ok := settings.IngestDeviceByID(deviceID)
+	token, hasToken := strings.CutPrefix(ctx.Request().Header.Get("Authorization"), "Bearer ")
+	// Compare against a token even for unknown devices so both fail alike
+	if !hasToken || subtle.ConstantTimeCompare([]byte(token), []byte(device.Token)) != 1 || !ok {
+		return c.HandleError(ctx, errors.NewStd("invalid ingest credentials"), "Invalid device or token", http.StatusUnauthorized)
+	}
+
+	params, err := parseIngestParams(ctx)
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	body := &deadlineReader{r: ctx.Request().Body, rc: http.NewResponseController(ctx.Response())}
+	result, err := myaudio.IngestAudio(ctx.Request().Context(), deviceID, params, body)
+	switch {
+	case errors.Is(err, myaudio.ErrIngestDeviceNotFound):
+		return c.HandleError(ctx, err, "Device is not active", http.StatusServiceUnavailable)
+	case err != nil:
+		var enhanced *errors.EnhancedError
+		if errors.As(err, &enhanced) && enhanced.Category == errors.CategoryValidation {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+		return c.HandleError(ctx, err, "Failed to ingest audio", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusAccepted, result)
+}
+
+// GetIngestDevices handles GET /api/v2/ingest/devices
+// Reports buffering, gaps and clock offset of every push ingest device
+func (c *Controller) GetIngestDevices(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, myaudio.GetIngestStatus())
+}
+
+// parseIngestParams reads the audio format of a push from its query parameters.
+func parseIngestParams(ctx echo.Context) (myaudio.IngestParams, error) {
+	params := myaudio.IngestParams{Format: strings.ToLower(ctx.QueryParam("format"))}
+	if params.Format == "" {
+		params.Format = myaudio.IngestFormatPCM
+	}
+	if params.Format == myaudio.IngestFormatPCM {
+		rate, err := strconv.Atoi(ctx.QueryParam("rate"))
+		if err != nil {
+			return params, errors.NewStd("rate must be the sample rate of the PCM audio")
+		}
+		channels := 1
+		if v := ctx.QueryParam("channels"); v != "" {
+			if channels, err = strconv.Atoi(v); err != nil {
+				return params, errors.NewStd("channels must be a number")
+			}
+		}
+		params.SampleRate, params.Channels = rate, channels
+	}
+	if v := ctx.QueryParam("ts"); v != "" {
+		ms, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || ms <= 0 {
+			return params, errors.NewStd("ts must be the capture time in Unix milliseconds")
+		}
+		params.Start = time.UnixMilli(ms)
+	}
+	return params, nil
+}
+
+// deadlineReader extends the read deadline of the connection on every read so
+// a streamed push is bounded by stalls rather than the server read timeout.
+type deadlineReader struct {
+	r  io.Reader
+	rc *http.ResponseController
+}
+
+func (d *deadlineReader) Read(p []byte) (int, error) {
+	// Not every connection supports deadlines, the server timeout applies then
+	_ = d.rc.SetReadDeadline(time.Now().Add(ingestReadTimeout))
+	return d.r.Read(p)
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+func TestPushIngestAudio_Authentication(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.Settings.Realtime.Ingest = conf.IngestSettings{
+		Enabled: true,
+		Latency: 10,
+		Devices: []conf.IngestDevice{{ID: "meadow", Token: "0123456789abcdef"}},
+	}
+
+	tests := []struct {
+		name   string
+		device string
+		auth   string
+		query  string
+		want   int
+	}{
+		{"missing token", "meadow", "", "rate=16000", http.StatusUnauthorized},
+		{"wrong token", "meadow", "Bearer fedcba9876543210", "rate=16000", http.StatusUnauthorized},
+		{"unknown device", "pond", "Bearer 0123456789abcdef", "rate=16000", http.StatusUnauthorized},
+		{"missing rate", "meadow", "Bearer 0123456789abcdef", "", http.StatusBadRequest},
+		{"bad timestamp", "meadow", "Bearer 0123456789abcdef", "rate=16000&ts=yesterday", http.StatusBadRequest},
+		{"device not active", "meadow", "Bearer 0123456789abcdef", "rate=16000", http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/ingest/"+tt.device+"?"+tt.query, strings.NewReader("\x00\x00"))
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			ctx := e.NewContext(req, rec)
+			ctx.SetParamNames("device")
+			ctx.SetParamValues(tt.device)
+
+			require.NoError(t, controller.PushIngestAudio(ctx))
+			assert.Equal(t, tt.want, rec.Code)
+		})
+	}
+}
+
+func TestPushIngestAudio_Disabled(t *testing.T) {
+	e, _, controller := setupTestEnvironment(t)
+	controller.Settings.Realtime.Ingest.Enabled = false
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v2/ingest/meadow", http.NoBody)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("device")
+	ctx.SetParamValues("meadow")
+
+	require.NoError(t, controller.PushIngestAudio(ctx))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+// TestPushIngestAudio_ExemptFromGroupBodyLimit pushes more audio than the 1MB
+// /api/v2 body limit through the full middleware stack.
+func TestPushIngestAudio_ExemptFromGroupBodyLimit(t *testing.T) {
+	// Not parallel: activates a device in the global ingest service
+	previous := conf.GetSettings()
+	conf.SetTestSettings(conf.GetTestSettings())
+	t.Cleanup(func() { conf.SetTestSettings(previous) })
+
+	e, _, controller := setupTestEnvironment(t)
+	controller.Settings.Realtime.Ingest = conf.IngestSettings{
+		Enabled: true,
+		Latency: 10,
+		Devices: []conf.IngestDevice{{ID: "meadow", Token: "0123456789abcdef"}},
+	}
+	controller.initIngestRoutes()
+
+	quit := make(chan struct{})
+	require.NoError(t, myaudio.SyncIngestDevices(controller.Settings, quit, nil))
+	t.Cleanup(func() {
+		myaudio.StopIngest()
+		close(quit)
+	})
+
+	pcm := make([]byte, 2<<20) // 2MB of silence at the analysis sample rate
+	tests := []struct {
+		name string
+		body func() io.Reader
+	}{
+		{"with content length", func() io.Reader { return bytes.NewReader(pcm) }},
+		{"streamed", func() io.Reader { return io.MultiReader(bytes.NewReader(pcm)) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v2/ingest/meadow?format=pcm&rate=48000", tt.body())
+			req.Header.Set("Authorization", "Bearer 0123456789abcdef")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			require.Equal(t, http.StatusAccepted, rec.Code, rec.Body.String())
+			var result myaudio.IngestResult
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &result))
+			assert.InDelta(t, float64(len(pcm)/2)/conf.SampleRate, result.Seconds, 0.001, "the whole body was ingested")
+		})
+	}
+}
@@ -428,7 +428,7 @@ func (c *Controller) TestBirdWeatherConnection(ctx echo.Context) error {
 			Longitude: c.Settings.BirdNET.Longitude,
 		},
 		Realtime: conf.RealtimeSettings{
-			Audio: c.Settings.Realtime.Audio, // Required for FFmpeg path (FLAC encoding)
+			Audio: c.Settings.Realtime.Audio,
 			Birdweather: conf.BirdweatherSettings{
 				Enabled:          request.Enabled,
 				ID:               request.ID,
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	liveSessionMaxMsgSize    = 256 * 1024 // about 2.7 s of 48 kHz mono PCM per message
+	liveSessionPongWait      = 60 * time.Second
+	liveSessionPingPeriod    = (liveSessionPongWait * 9) / 10
+	liveSessionWriteWait     = 10 * time.Second
+	liveSessionSignalTimeout = 5 * time.Second
+)
+
+// liveSessionMessage is sent to the browser as text once the session is ready
+type liveSessionMessage struct {
+	Type    string                  `json:"type"`
+	Session myaudio.LiveSessionInfo `json:"session"`
+}
+
+// HandleLiveSessionWS handles GET /api/v2/ingest/live?rate=&name=
+// Streams the microphone of the logged-in user's browser or phone as a
+// temporary audio source. After the upgrade the server sends a "session" text
+// message with the source ID detections are tagged with, the client then sends
+// binary messages of mono signed 16-bit little-endian PCM at rate. The session
+// ends when the WebSocket closes.
+func (c *Controller) HandleLiveSessionWS(ctx echo.Context) error {
+	rate, err := strconv.Atoi(ctx.QueryParam("rate"))
+	if err != nil {
+		return c.HandleError(ctx, err, "rate must be the sample rate of the microphone audio", http.StatusBadRequest)
+	}
+
+	session, err := myaudio.StartLiveSession(ctx.QueryParam("name"), stringFromCtx(ctx, auth.CtxKeyUsername, ""), rate)
+	switch {
+	case errors.Is(err, myaudio.ErrTooManyLiveSessions):
+		return c.HandleError(ctx, err, err.Error(), http.StatusTooManyRequests)
+	case err != nil:
+		var enhanced *errors.EnhancedError
+		if errors.As(err, &enhanced) && enhanced.Category == errors.CategoryValidation {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+		return c.HandleError(ctx, err, "Failed to start live session", http.StatusInternalServerError)
+	}
+	defer func() {
+		session.Close()
+		c.signalLiveSources()
+	}()
+
+	// Same origin check as the terminal: browsers must connect from this host
+	conn, err := terminalUpgrader.Upgrade(ctx.Response(), ctx.Request(), nil)
+	if err != nil {
+		c.logErrorIfEnabled("Failed to upgrade live session WebSocket", logger.Error(err))
+		return err
+	}
+	defer func() { _ = conn.Close() }()
+	conn.SetReadLimit(liveSessionMaxMsgSize)
+
+	c.signalLiveSources()
+	_ = conn.SetWriteDeadline(time.Now().Add(liveSessionWriteWait))
+	if err := conn.WriteJSON(liveSessionMessage{Type: "session", Session: session.Info()}); err != nil {
+		return nil
+	}
+
+	// Pings keep the read deadline moving while the browser streams
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		ticker := time.NewTicker(liveSessionPingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// WriteControl is safe alongside other writers
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(liveSessionWriteWait)); err != nil {
+					return
+				}
+			case <-done:
+				return
+			case <-c.ctx.Done():
+				_ = conn.Close()
+				return
+			}
+		}
+	}()
+
+	_ = conn.SetReadDeadline(time.Now().Add(liveSessionPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(liveSessionPongWait))
+	})
+	for {
+		msgType, msg, err := conn.ReadMessage()
+		if err != nil {
+			// The connection is hijacked, Echo must not write an HTTP error
+			return nil
+		}
+		if msgType != websocket.BinaryMessage {
+			continue
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(liveSessionPongWait))
+		if err := session.Write(msg); err != nil {
+			return nil
+		}
+	}
+}
+
+// GetLiveSessions handles GET /api/v2/ingest/sessions
+// Lists the browser live sessions currently streaming
+func (c *Controller) GetLiveSessions(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, myaudio.ListLiveSessions())
+}
+
+// signalLiveSources asks the analysis to start or stop monitoring live sessions.
+func (c *Controller) signalLiveSources() {
+	if c.controlChan == nil {
+		return
+	}
+	select {
+	case c.controlChan <- "update_live_sources":
+	case <-time.After(liveSessionSignalTimeout):
+		c.logErrorIfEnabled("Timed out signalling live session change to analysis")
+	}
+}
@@ -0,0 +1,178 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/reanalysis"
+)
+
+const (
+	defaultReanalysisJobLimit  = 50
+	maxReanalysisFlagLimit     = 500
+	defaultReanalysisFlagLimit = 100
+)
+
+// ReanalysisJobRequest is the body of a new re-analysis job. Empty filters
+// match every detection, a zero sensitivity or threshold uses the configured one.
+type ReanalysisJobRequest struct {
+	ModelPath   string  `json:"model_path"`  // custom model file, empty for the configured model
+	LabelPath   string  `json:"label_path"`  // labels of a custom model
+	Sensitivity float64 `json:"sensitivity"` // sigmoid sensitivity of the model
+	Threshold   float64 `json:"threshold"`   // confidence the new top prediction needs to count
+	StartDate   string  `json:"start_date"`  // YYYY-MM-DD
+	EndDate     string  `json:"end_date"`    // YYYY-MM-DD
+	Species     string  `json:"species"`     // scientific name or species code
+	Source      string  `json:"source"`      // node name
+}
+
+// initReanalysisRoutes registers the re-analysis job endpoints and starts the
+// job runner. Jobs need the enhanced (v2) database.
+func (c *Controller) initReanalysisRoutes() {
+	if c.V2Manager == nil {
+		return
+	}
+	store, ok := c.DS.(reanalysis.Store)
+	if !ok {
+		return
+	}
+
+	c.reanalysisRepo = repository.NewReanalysisRepository(c.V2Manager.DB())
+	c.reanalysisRunner = reanalysis.NewRunner(c.Settings, store, c.reanalysisRepo, reanalysis.BirdNETAnalyzer(c.Settings))
+	c.wg.Go(func() {
+		c.reanalysisRunner.Run(c.ctx)
+	})
+
+	jobs := c.Group.Group("/reanalysis/jobs", c.authMiddleware)
+	jobs.GET("", c.ListReanalysisJobs)
+	jobs.POST("", c.CreateReanalysisJob)
+	jobs.GET("/:id", c.GetReanalysisJob)
+	jobs.POST("/:id/cancel", c.CancelReanalysisJob)
+	jobs.GET("/:id/flags", c.ListReanalysisFlags)
+}
+
+// ListReanalysisJobs handles GET /api/v2/reanalysis/jobs
+// Returns the most recent jobs with their progress, newest first.
+func (c *Controller) ListReanalysisJobs(ctx echo.Context) error {
+	limit := defaultReanalysisJobLimit
+	if v, err := strconv.Atoi(ctx.QueryParam("limit")); err == nil && v > 0 {
+		limit = min(v, maxHistoryLimit)
+	}
+
+	jobs, err := c.reanalysisRepo.ListJobs(ctx.Request().Context(), limit)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list reanalysis jobs", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{"jobs": jobs})
+}
+
+// CreateReanalysisJob handles POST /api/v2/reanalysis/jobs
+// Queues a job re-running inference over the stored clips of the matching
+// detections. Jobs run one at a time in the background.
+func (c *Controller) CreateReanalysisJob(ctx echo.Context) error {
+	var req ReanalysisJobRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid reanalysis job", http.StatusBadRequest)
+	}
+
+	job := &entities.ReanalysisJob{
+		ModelPath:   req.ModelPath,
+		LabelPath:   req.LabelPath,
+		Sensitivity: req.Sensitivity,
+		Threshold:   req.Threshold,
+		StartDate:   req.StartDate,
+		EndDate:     req.EndDate,
+		Species:     req.Species,
+		Source:      req.Source,
+	}
+	if err := c.reanalysisRunner.Submit(ctx.Request().Context(), job); err != nil {
+		var enhanced *errors.EnhancedError
+		if errors.As(err, &enhanced) && enhanced.Category == errors.CategoryValidation {
+			return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+		}
+		return c.HandleError(ctx, err, "Failed to create reanalysis job", http.StatusInternalServerError)
+	}
+
+	c.logInfoIfEnabled("reanalysis job queued",
+		logger.Uint64("job_id", uint64(job.ID)),
+		logger.String("ip", ctx.RealIP()))
+	return ctx.JSON(http.StatusAccepted, job)
+}
+
+// GetReanalysisJob handles GET /api/v2/reanalysis/jobs/:id
+// Returns a job with its progress.
+func (c *Controller) GetReanalysisJob(ctx echo.Context) error {
+	job, err := c.reanalysisJobParam(ctx)
+	if job == nil {
+		return err
+	}
+	return ctx.JSON(http.StatusOK, job)
+}
+
+// CancelReanalysisJob handles POST /api/v2/reanalysis/jobs/:id/cancel
+// Stops a pending or running job. Predictions and flags it stored are kept.
+func (c *Controller) CancelReanalysisJob(ctx echo.Context) error {
+	job, err := c.reanalysisJobParam(ctx)
+	if job == nil {
+		return err
+	}
+
+	cancelled, err := c.reanalysisRunner.Cancel(ctx.Request().Context(), job.ID)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to cancel reanalysis job", http.StatusInternalServerError)
+	}
+	if !cancelled {
+		return c.HandleError(ctx, errors.NewStd("reanalysis job already ended"), "Reanalysis job already ended", http.StatusConflict)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// ListReanalysisFlags handles GET /api/v2/reanalysis/jobs/:id/flags
+// Returns the detections a job disagrees with, for review.
+func (c *Controller) ListReanalysisFlags(ctx echo.Context) error {
+	job, err := c.reanalysisJobParam(ctx)
+	if job == nil {
+		return err
+	}
+
+	limit, offset := defaultReanalysisFlagLimit, 0
+	if v, err := strconv.Atoi(ctx.QueryParam("limit")); err == nil && v > 0 {
+		limit = min(v, maxReanalysisFlagLimit)
+	}
+	if v, err := strconv.Atoi(ctx.QueryParam("offset")); err == nil && v >= 0 {
+		offset = v
+	}
+
+	flags, total, err := c.reanalysisRepo.ListFlags(ctx.Request().Context(), job.ID, limit, offset)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to list reanalysis flags", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, map[string]any{
+		"flags":  flags,
+		"total":  total,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// reanalysisJobParam loads the job of the :id parameter. It returns a nil job
+// once the error response is sent.
+func (c *Controller) reanalysisJobParam(ctx echo.Context) (*entities.ReanalysisJob, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		return nil, c.HandleError(ctx, err, "Invalid job ID", http.StatusBadRequest)
+	}
+	job, err := c.reanalysisRepo.GetJob(ctx.Request().Context(), uint(id))
+	if errors.Is(err, repository.ErrReanalysisJobNotFound) {
+		return nil, c.HandleError(ctx, err, "Reanalysis job not found", http.StatusNotFound)
+	}
+	if err != nil {
+		return nil, c.HandleError(ctx, err, "Failed to read reanalysis job", http.StatusInternalServerError)
+	}
+	return job, nil
+}
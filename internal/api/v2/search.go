@@ -36,6 +36,7 @@ type SearchRequest struct {
 	VerifiedStatus string  `json:"verifiedStatus"`
 	LockedStatus   string  `json:"lockedStatus"`
 	DeviceFilter   string  `json:"deviceFilter"`
+	Station        string  `json:"station"`
 	TimeOfDay      string  `json:"timeOfDay"`
 	Page           int     `json:"page"`
 	SortBy         string  `json:"sortBy"`
@@ -134,6 +135,7 @@ func (c *Controller) buildSearchFilters(req *SearchRequest, ctxTimeout context.C
 		LockedOnly:     req.LockedStatus == "locked",
 		UnlockedOnly:   req.LockedStatus == "unlocked",
 		Device:         req.DeviceFilter,
+		Station:        req.Station,
 		TimeOfDay:      req.TimeOfDay,
 		Page:           req.Page,
 		PerPage:        defaultPerPage,
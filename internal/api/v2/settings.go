@@ -16,6 +16,7 @@ import (
 	"unicode/utf8"
 
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/imageprovider"
 	"github.com/tphakala/birdnet-go/internal/logger"
@@ -95,6 +96,15 @@ func (c *Controller) GetAllSettings(ctx echo.Context) error {
 		logger.String("ip", ctx.RealIP()),
 	)
 
+	// Read-only users see the settings without their credentials
+	if auth.IsReadOnly(ctx) {
+		masked, err := maskSettingsSecrets(settings, "")
+		if err != nil {
+			return c.HandleError(ctx, err, "Failed to get settings", http.StatusInternalServerError)
+		}
+		return ctx.JSON(http.StatusOK, masked)
+	}
+
 	// Return a copy of the settings
 	return ctx.JSON(http.StatusOK, settings)
 }
@@ -132,6 +142,14 @@ func (c *Controller) GetSectionSettings(ctx echo.Context) error {
 
 	c.logAPIRequest(ctx, logger.LogLevelInfo, "Retrieved settings section successfully", logger.String("section", section))
 
+	if auth.IsReadOnly(ctx) {
+		masked, err := maskSettingsSecrets(sectionValue, section)
+		if err != nil {
+			return c.HandleError(ctx, err, "Failed to get settings section", http.StatusInternalServerError)
+		}
+		return ctx.JSON(http.StatusOK, masked)
+	}
+
 	return ctx.JSON(http.StatusOK, sectionValue)
 }
 
@@ -736,6 +754,8 @@ func getSettingsSectionValue(settings *conf.Settings, section string) (any, erro
 		return &settings.Realtime.Species, nil
 	case "rtsp":
 		return &settings.Realtime.RTSP, nil
+	case "ingest":
+		return &settings.Realtime.Ingest, nil
 	case "privacyfilter":
 		return &settings.Realtime.PrivacyFilter, nil
 	case "dogbarkfilter":
@@ -803,6 +823,7 @@ func getSectionValidators() map[string]sectionValidator {
 	return map[string]sectionValidator{
 		"mqtt":                   validateMQTTSection,
 		"rtsp":                   validateStreamsSection,
+		"ingest":                 validateIngestSection,
 		"security":               validateSecuritySection,
 		"main":                   validateMainSection,
 		SettingsSectionBirdnet:   validateBirdNETSection,
@@ -839,6 +860,15 @@ func validateStreamsSection(data json.RawMessage) error {
 	return rtspSettings.ValidateStreams()
 }
 
+// validateIngestSection validates push ingest settings
+func validateIngestSection(data json.RawMessage) error {
+	var ingestSettings conf.IngestSettings
+	if err := json.Unmarshal(data, &ingestSettings); err != nil {
+		return err
+	}
+	return ingestSettings.Validate()
+}
+
 // securitySectionAllowedFields defines which fields in the security section can be updated via API
 var securitySectionAllowedFields = map[string]bool{
 	"host":              true, // Server hostname for TLS
@@ -1586,7 +1616,7 @@ func streamsSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		return true
 	}
 
-	// Check for changes in individual streams (name, URL, type, or transport)
+	// Check for changes in individual streams (name, URL, type, transport, backup URLs or channels)
 	for i, oldStream := range oldRTSP.Streams {
 		if i >= len(newRTSP.Streams) {
 			return true
@@ -1595,12 +1625,15 @@ func streamsSettingsChanged(oldSettings, currentSettings *conf.Settings) bool {
 		if oldStream.Name != newStream.Name ||
 			oldStream.URL != newStream.URL ||
 			oldStream.Type != newStream.Type ||
-			oldStream.Transport != newStream.Transport {
+			oldStream.Transport != newStream.Transport ||
+			!slices.Equal(oldStream.BackupURLs, newStream.BackupURLs) ||
+			!reflect.DeepEqual(oldStream.Channels, newStream.Channels) {
 			return true
 		}
 	}
 
-	return false
+	// Push ingest devices are sources managed alongside the streams
+	return !reflect.DeepEqual(oldSettings.Realtime.Ingest, currentSettings.Realtime.Ingest)
 }
 
 // speciesIntervalSettingsChanged checks if any species-specific interval settings have changed
@@ -10,9 +10,10 @@ import (
 	"github.com/tphakala/birdnet-go/internal/notification"
 )
 
-// audioDeviceSettingChanged checks if audio device settings have changed
+// audioDeviceSettingChanged checks if audio device settings, including its channel layout, have changed
 func audioDeviceSettingChanged(oldSettings, currentSettings *conf.Settings) bool {
-	return oldSettings.Realtime.Audio.Source != currentSettings.Realtime.Audio.Source
+	return oldSettings.Realtime.Audio.Source != currentSettings.Realtime.Audio.Source ||
+		!reflect.DeepEqual(oldSettings.Realtime.Audio.Channels, currentSettings.Realtime.Audio.Channels)
 }
 
 // soundLevelSettingsChanged checks if sound level monitoring settings have changed
@@ -36,6 +37,25 @@ func equalizerSettingsChanged(oldSettings, newSettings conf.EqualizerSettings) b
 	return !reflect.DeepEqual(oldSettings, newSettings)
 }
 
+// sourceEqualizersChanged checks if the sound card or any stream equalizer override has changed
+func sourceEqualizersChanged(oldSettings, newSettings *conf.Settings) bool {
+	if !reflect.DeepEqual(oldSettings.Realtime.Audio.SourceEqualizer, newSettings.Realtime.Audio.SourceEqualizer) {
+		return true
+	}
+	return !reflect.DeepEqual(streamEqualizerOverrides(oldSettings), streamEqualizerOverrides(newSettings))
+}
+
+// streamEqualizerOverrides returns the stream equalizer overrides keyed by stream URL
+func streamEqualizerOverrides(settings *conf.Settings) map[string]conf.EqualizerSettings {
+	overrides := make(map[string]conf.EqualizerSettings)
+	for i := range settings.Realtime.RTSP.Streams {
+		if eq := settings.Realtime.RTSP.Streams[i].Equalizer; eq != nil {
+			overrides[settings.Realtime.RTSP.Streams[i].URL] = *eq
+		}
+	}
+	return overrides
+}
+
 // handleEqualizerChange updates the audio filter chain when equalizer settings change
 func (c *Controller) handleEqualizerChange(settings *conf.Settings) error {
 	if err := myaudio.UpdateFilterChain(settings); err != nil {
@@ -64,6 +84,12 @@ func (c *Controller) handleAudioSettingsChanges(oldSettings, currentSettings *co
 			notification.MsgSettingsReconfiguringSoundLevel, nil)
 	}
 
+	// Check continuous audio archive settings
+	if !reflect.DeepEqual(oldSettings.Realtime.Audio.Archive, currentSettings.Realtime.Audio.Archive) {
+		c.Debug("Audio archive settings changed, triggering reconfiguration")
+		reconfigActions = append(reconfigActions, "reconfigure_audio_archive")
+	}
+
 	// Check audio device settings
 	if audioDeviceSettingChanged(oldSettings, currentSettings) {
 		c.Debug("Audio device changed. A restart will be required.")
@@ -73,7 +99,8 @@ func (c *Controller) handleAudioSettingsChanges(oldSettings, currentSettings *co
 	}
 
 	// Check audio equalizer settings
-	if equalizerSettingsChanged(oldSettings.Realtime.Audio.Equalizer, currentSettings.Realtime.Audio.Equalizer) {
+	if equalizerSettingsChanged(oldSettings.Realtime.Audio.Equalizer, currentSettings.Realtime.Audio.Equalizer) ||
+		sourceEqualizersChanged(oldSettings, currentSettings) {
 		c.Debug("Audio equalizer settings changed, updating filter chain")
 		// Handle audio equalizer changes synchronously as it returns an error
 		if err := c.handleEqualizerChange(currentSettings); err != nil {
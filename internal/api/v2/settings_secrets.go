@@ -0,0 +1,85 @@
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// maskedSecret replaces credentials in settings shown to read-only users
+const maskedSecret = "********"
+
+// secretSettingKeys are the lowercased JSON keys of settings holding credentials
+var secretSettingKeys = map[string]bool{
+	"password":      true,
+	"pass":          true,
+	"token":         true,
+	"secret":        true,
+	"clientsecret":  true,
+	"sessionsecret": true,
+	"apikey":        true,
+	"encryptionkey": true,
+}
+
+// maskSettingsSecrets returns value, the settings or the settings section of
+// that name, as generic JSON with its credentials masked: secret fields, the
+// BirdWeather station ID, and credentials inside URLs such as stream and
+// notification URLs.
+func maskSettingsSecrets(value any, section string) (any, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return maskSecretValue("", section, generic), nil
+}
+
+// maskSecretValue masks the credentials of v, the value of key in the object
+// held by parent.
+func maskSecretValue(parent, key string, v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		for k, item := range value {
+			value[k] = maskSecretValue(key, k, item)
+		}
+	case []any:
+		for i, item := range value {
+			value[i] = maskSecretValue(parent, key, item)
+		}
+	case string:
+		lowerKey := strings.ToLower(key)
+		switch {
+		case value == "":
+		case secretSettingKeys[lowerKey], lowerKey == "id" && strings.EqualFold(parent, "birdweather"):
+			return maskedSecret
+		case strings.Contains(value, "://"):
+			return maskURLCredentials(value)
+		}
+	}
+	return v
+}
+
+// maskURLCredentials masks the user info and secret query parameters of a URL.
+// Shoutrrr URLs carry their tokens as user info.
+func maskURLCredentials(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return maskedSecret
+	}
+	if u.User != nil {
+		u.User = url.User(maskedSecret)
+	}
+	if u.RawQuery != "" {
+		query := u.Query()
+		for k := range query {
+			if secretSettingKeys[strings.ToLower(k)] {
+				query.Set(k, maskedSecret)
+			}
+		}
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
@@ -0,0 +1,154 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// defaultSimulationDays is the date range simulated when none is given
+const defaultSimulationDays = 30
+
+// SimulationRequest is the body of a settings simulation. Settings left out
+// keep their current value.
+type SimulationRequest struct {
+	StartDate     string             `json:"start_date"`     // YYYY-MM-DD, defaults to 30 days ago
+	EndDate       string             `json:"end_date"`       // YYYY-MM-DD, defaults to today
+	Species       string             `json:"species"`        // scientific or common name
+	Source        string             `json:"source"`         // node name
+	MaxDetections int                `json:"max_detections"` // detections replayed at most
+	Settings      SimulationSettings `json:"settings"`
+}
+
+// SimulationSettings are the candidate settings of a simulation.
+type SimulationSettings struct {
+	Threshold            *float64                       `json:"threshold"`
+	Sensitivity          *float64                       `json:"sensitivity"`
+	FalsePositiveLevel   *int                           `json:"false_positive_level"`
+	DynamicThreshold     *conf.DynamicThresholdSettings `json:"dynamic_threshold"`
+	RangeFilterThreshold *float32                       `json:"range_filter_threshold"`
+	Include              []string                       `json:"include"` // replaces the include list when set
+	Exclude              []string                       `json:"exclude"` // replaces the exclude list when set
+}
+
+// initSimulatorRoutes registers the settings simulator endpoint.
+func (c *Controller) initSimulatorRoutes() {
+	c.Group.POST("/settings/simulate", c.SimulateSettings, c.authMiddleware)
+}
+
+// SimulateSettings handles POST /api/v2/settings/simulate
+// Replays stored detections and their secondary predictions through the
+// detection filters with the current and the candidate settings, and reports
+// the detections the candidate settings would gain or lose. Nothing is applied.
+func (c *Controller) SimulateSettings(ctx echo.Context) error {
+	if c.Processor == nil {
+		return c.HandleError(ctx, errors.Newf("processor not available").
+			Category(errors.CategorySystem).
+			Component("api-simulator").
+			Build(), "Processor not available", http.StatusServiceUnavailable)
+	}
+
+	var req SimulationRequest
+	if err := ctx.Bind(&req); err != nil {
+		return c.HandleError(ctx, err, "Invalid simulation request", http.StatusBadRequest)
+	}
+	opts, err := simulationOptions(&req, time.Now())
+	if err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	c.settingsMutex.RLock()
+	candidate := *c.Settings
+	c.settingsMutex.RUnlock()
+	if err := applySimulationSettings(&candidate, &req.Settings); err != nil {
+		return c.HandleError(ctx, err, err.Error(), http.StatusBadRequest)
+	}
+
+	report, err := c.Processor.Simulate(ctx.Request().Context(), c.DS, &candidate, opts)
+	if err != nil {
+		return c.HandleError(ctx, err, "Failed to simulate settings", http.StatusInternalServerError)
+	}
+	return ctx.JSON(http.StatusOK, report)
+}
+
+// simulationOptions validates the detection selection of a request.
+func simulationOptions(req *SimulationRequest, now time.Time) (processor.SimulationOptions, error) {
+	opts := processor.SimulationOptions{
+		Start:         now.AddDate(0, 0, -defaultSimulationDays),
+		End:           now,
+		Species:       req.Species,
+		Source:        req.Source,
+		MaxDetections: min(max(req.MaxDetections, 0), processor.DefaultSimulationMaxDetections),
+	}
+	for _, d := range []struct {
+		value string
+		out   *time.Time
+	}{{req.StartDate, &opts.Start}, {req.EndDate, &opts.End}} {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.ParseInLocation(time.DateOnly, d.value, time.Local)
+		if err != nil {
+			return opts, simulationValidationError(fmt.Sprintf("date %q must be YYYY-MM-DD", d.value))
+		}
+		*d.out = t
+	}
+	if opts.End.Before(opts.Start) {
+		return opts, simulationValidationError("end_date is before start_date")
+	}
+	return opts, nil
+}
+
+// applySimulationSettings applies the candidate settings of a request to a
+// copy of the current settings.
+func applySimulationSettings(settings *conf.Settings, candidate *SimulationSettings) error {
+	if v := candidate.Threshold; v != nil {
+		if *v <= 0 || *v > 1 {
+			return simulationValidationError("threshold must be above 0 and at most 1")
+		}
+		settings.BirdNET.Threshold = *v
+	}
+	if v := candidate.Sensitivity; v != nil {
+		if *v < conf.SensitivityMin || *v > conf.SensitivityMax {
+			return simulationValidationError(fmt.Sprintf("sensitivity must be between %g and %g", conf.SensitivityMin, conf.SensitivityMax))
+		}
+		settings.BirdNET.Sensitivity = *v
+	}
+	if v := candidate.FalsePositiveLevel; v != nil {
+		if *v < 0 || *v > 5 {
+			return simulationValidationError("false_positive_level must be between 0 and 5")
+		}
+		settings.Realtime.FalsePositiveFilter.Level = *v
+	}
+	if v := candidate.DynamicThreshold; v != nil {
+		if v.Enabled && (v.Trigger <= 0 || v.Trigger > 1 || v.Min < 0 || v.Min > 1 || v.ValidHours <= 0) {
+			return simulationValidationError("dynamic_threshold needs a trigger above 0, a minimum between 0 and 1 and valid hours above 0")
+		}
+		settings.Realtime.DynamicThreshold = *v
+	}
+	if v := candidate.RangeFilterThreshold; v != nil {
+		if *v < 0 || *v > 1 {
+			return simulationValidationError("range_filter_threshold must be between 0 and 1")
+		}
+		settings.BirdNET.RangeFilter.Threshold = *v
+	}
+	if candidate.Include != nil {
+		settings.Realtime.Species.Include = candidate.Include
+	}
+	if candidate.Exclude != nil {
+		settings.Realtime.Species.Exclude = candidate.Exclude
+	}
+	return nil
+}
+
+func simulationValidationError(msg string) error {
+	return errors.Newf("%s", msg).
+		Category(errors.CategoryValidation).
+		Component("api-simulator").
+		Build()
+}
@@ -0,0 +1,83 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/analysis/processor"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestSimulationOptions(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2026, 6, 15, 12, 0, 0, 0, time.Local)
+
+	opts, err := simulationOptions(&SimulationRequest{}, now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -defaultSimulationDays), opts.Start)
+	assert.Equal(t, now, opts.End)
+
+	opts, err = simulationOptions(&SimulationRequest{StartDate: "2026-05-01", EndDate: "2026-05-31", MaxDetections: 1 << 30}, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, opts.Start.Day())
+	assert.Equal(t, 31, opts.End.Day())
+	assert.Equal(t, processor.DefaultSimulationMaxDetections, opts.MaxDetections)
+
+	_, err = simulationOptions(&SimulationRequest{StartDate: "05/01/2026"}, now)
+	require.Error(t, err)
+	_, err = simulationOptions(&SimulationRequest{StartDate: "2026-05-31", EndDate: "2026-05-01"}, now)
+	require.Error(t, err)
+}
+
+func TestApplySimulationSettings(t *testing.T) {
+	t.Parallel()
+
+	current := func() *conf.Settings {
+		s := &conf.Settings{}
+		s.BirdNET.Threshold = 0.8
+		s.BirdNET.Sensitivity = 1
+		s.Realtime.Species.Include = []string{"Turdus merula"}
+		return s
+	}
+
+	threshold, sensitivity, level := 0.7, 1.2, 3
+	settings := current()
+	require.NoError(t, applySimulationSettings(settings, &SimulationSettings{
+		Threshold:          &threshold,
+		Sensitivity:        &sensitivity,
+		FalsePositiveLevel: &level,
+		Exclude:            []string{"Parus major"},
+	}))
+	assert.InDelta(t, 0.7, settings.BirdNET.Threshold, 0.0001)
+	assert.InDelta(t, 1.2, settings.BirdNET.Sensitivity, 0.0001)
+	assert.Equal(t, 3, settings.Realtime.FalsePositiveFilter.Level)
+	assert.Equal(t, []string{"Parus major"}, settings.Realtime.Species.Exclude)
+	assert.Equal(t, []string{"Turdus merula"}, settings.Realtime.Species.Include, "settings left out keep their value")
+
+	settings = current()
+	require.NoError(t, applySimulationSettings(settings, &SimulationSettings{Include: []string{}}))
+	assert.Empty(t, settings.Realtime.Species.Include, "an empty list clears the list")
+
+	highThreshold, highSensitivity, unknownLevel, negativeRange := 1.5, 2.0, 6, float32(-0.1)
+	invalid := []struct {
+		name     string
+		settings SimulationSettings
+	}{
+		{name: "threshold above one", settings: SimulationSettings{Threshold: &highThreshold}},
+		{name: "sensitivity too high", settings: SimulationSettings{Sensitivity: &highSensitivity}},
+		{name: "unknown filter level", settings: SimulationSettings{FalsePositiveLevel: &unknownLevel}},
+		{name: "range filter threshold below zero", settings: SimulationSettings{RangeFilterThreshold: &negativeRange}},
+		{name: "dynamic threshold without valid hours", settings: SimulationSettings{
+			DynamicThreshold: &conf.DynamicThresholdSettings{Enabled: true, Trigger: 0.9, Min: 0.2},
+		}},
+	}
+	for _, tt := range invalid {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Error(t, applySimulationSettings(current(), &tt.settings))
+		})
+	}
+}
@@ -57,6 +57,11 @@ type StreamHealthResponse struct {
 	ErrorHistory     []*ErrorContextResponse `json:"error_history,omitempty"`      // Recent errors (last 10)
 	// State history (for debugging state transitions)
 	StateHistory []StateTransitionResponse `json:"state_history,omitempty"` // Recent state transitions
+	// Input health diagnostics (silence, clipping, DC offset, hum, roll-off)
+	MicHealth *myaudio.MicHealth `json:"mic_health,omitempty"` // Latest input health assessment, absent until one second of audio was analyzed
+	// Failover state
+	ActiveURL      string `json:"active_url,omitempty"` // Sanitized URL currently streamed
+	UsingBackupURL bool   `json:"using_backup_url"`     // Whether the stream failed over to a backup URL
 }
 
 // ErrorContextResponse represents the API response for FFmpeg error context
@@ -103,6 +108,9 @@ type StreamSummaryResponse struct {
 	ProcessState  string   `json:"process_state"`                     // Current state
 	LastErrorType string   `json:"last_error_type,omitempty"`         // Type of last error if any
 	TimeSinceData *float64 `json:"time_since_data_seconds,omitempty"` // Seconds since last data
+	// Failover state
+	ActiveURL      string `json:"active_url,omitempty"` // Sanitized URL currently streamed
+	UsingBackupURL bool   `json:"using_backup_url"`     // Whether the stream failed over to a backup URL
 }
 
 // initStreamHealthRoutes registers all stream health monitoring endpoints
@@ -112,6 +120,7 @@ func (c *Controller) initStreamHealthRoutes() {
 
 	// REST endpoints
 	c.Group.GET("/streams/health", c.GetAllStreamsHealth, authMiddleware)
+	c.Group.GET("/streams/health/microphones", c.GetMicrophonesHealth, authMiddleware)
 	c.Group.GET("/streams/health/:url", c.GetStreamHealth, authMiddleware)
 	c.Group.GET("/streams/status", c.GetStreamsStatusSummary, authMiddleware)
 
@@ -247,6 +256,17 @@ func (c *Controller) GetStreamHealth(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, response)
 }
 
+// GetMicrophonesHealth returns input health diagnostics for every audio source
+// @Summary Get input health of all audio sources
+// @Description Returns silence, clipping, DC offset, hum, roll-off and channel imbalance diagnostics for streams and sound cards
+// @Tags streams
+// @Produce json
+// @Success 200 {array} myaudio.MicHealth "Array of input health assessments"
+// @Router /api/v2/streams/health/microphones [get]
+func (c *Controller) GetMicrophonesHealth(ctx echo.Context) error {
+	return ctx.JSON(http.StatusOK, myaudio.GetAllMicHealth())
+}
+
 // GetStreamsStatusSummary returns a high-level summary of all stream statuses
 // @Summary Get summary of all stream statuses
 // @Description Returns a high-level summary including counts of healthy/unhealthy streams
@@ -280,11 +300,15 @@ func (c *Controller) GetStreamsStatusSummary(ctx echo.Context) error {
 		// Build brief summary for this stream
 		info := c.getStreamInfo(rawURL)
 		streamSummary := StreamSummaryResponse{
-			Name:         info.Name,
-			Type:         info.Type,
-			URL:          privacy.SanitizeStreamUrl(rawURL),
-			IsHealthy:    health.IsHealthy,
-			ProcessState: health.ProcessState.String(),
+			Name:           info.Name,
+			Type:           info.Type,
+			URL:            privacy.SanitizeStreamUrl(rawURL),
+			IsHealthy:      health.IsHealthy,
+			ProcessState:   health.ProcessState.String(),
+			UsingBackupURL: health.UsingBackupURL,
+		}
+		if health.ActiveURL != "" {
+			streamSummary.ActiveURL = privacy.SanitizeStreamUrl(health.ActiveURL)
 		}
 
 		// Add time since data if available
@@ -314,6 +338,10 @@ func convertStreamHealthToResponse(rawURL string, health *myaudio.StreamHealth)
 		TotalBytesReceived: health.TotalBytesReceived,
 		BytesPerSecond:     health.BytesPerSecond,
 		IsReceivingData:    health.IsReceivingData,
+		UsingBackupURL:     health.UsingBackupURL,
+	}
+	if health.ActiveURL != "" {
+		response.ActiveURL = privacy.SanitizeStreamUrl(health.ActiveURL)
 	}
 
 	// Handle LastDataReceived (may be zero time if never received data)
@@ -343,6 +371,13 @@ func convertStreamHealthToResponse(rawURL string, health *myaudio.StreamHealth)
 		}
 	}
 
+	// Attach input health diagnostics of the stream's audio
+	if source, ok := myaudio.GetRegistry().GetSourceByConnection(rawURL); ok {
+		if micHealth, ok := myaudio.GetMicHealth(source.ID); ok {
+			response.MicHealth = &micHealth
+		}
+	}
+
 	// Convert state history
 	if len(health.StateHistory) > 0 {
 		response.StateHistory = make([]StateTransitionResponse, 0, len(health.StateHistory))
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/support"
@@ -269,7 +270,7 @@ func (c *Controller) initSupportRoutes() {
 	// Create protected group for support endpoints (consistent with other route files)
 	supportGroup := c.Group.Group("/support", c.authMiddleware)
 	supportGroup.POST("/generate", c.GenerateSupportDump)
-	supportGroup.GET("/download/:id", c.DownloadSupportDump)
+	supportGroup.GET("/download/:id", c.DownloadSupportDump, auth.RequireWrite)
 	supportGroup.GET("/status", c.GetSupportStatus)
 
 	// Start cleanup goroutine for old support dumps with proper context
@@ -310,6 +310,7 @@ func (c *Controller) initSystemRoutes() {
 	audioGroup.GET("/devices", c.GetAudioDevices)
 	audioGroup.GET("/active", c.GetActiveAudioDevice)
 	audioGroup.GET("/equalizer/config", c.GetEqualizerConfig)
+	audioGroup.GET("/overlap", c.GetAudioOverlap)
 
 	// Initialize database overview route
 	c.initDatabaseOverviewRoutes()
@@ -1315,13 +1316,31 @@ func skipFilesystem(fstype string) bool {
 	return false
 }
 
+// EqualizerConfigResponse is returned by GetEqualizerConfig when the
+// per-source filter chains are requested with ?sources=true
+type EqualizerConfigResponse struct {
+	Filters map[string]conf.EqFilterTypeConfig `json:"filters"`
+	Sources []myaudio.SourceEqualizer          `json:"sources"`
+}
+
 // GetEqualizerConfig handles GET /api/v2/system/audio/equalizer/config
+// With ?sources=true the response also lists the effective filter chain of
+// every registered audio source.
 func (c *Controller) GetEqualizerConfig(ctx echo.Context) error {
 	c.logInfoIfEnabled("Getting equalizer filter configuration",
 		logger.String("path", ctx.Request().URL.Path),
 		logger.String("ip", ctx.RealIP()),
 	)
 
+	if ctx.QueryParam("sources") == "true" {
+		// Source chains follow live settings and must not be cached
+		ctx.Response().Header().Set("Cache-Control", "no-cache")
+		return ctx.JSON(http.StatusOK, EqualizerConfigResponse{
+			Filters: conf.EqFilterConfig,
+			Sources: myaudio.GetSourceEqualizers(c.Settings),
+		})
+	}
+
 	// Set cache headers for static configuration data
 	ctx.Response().Header().Set("Cache-Control", "public, max-age=3600")
 
@@ -1329,6 +1348,35 @@ func (c *Controller) GetEqualizerConfig(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, conf.EqFilterConfig)
 }
 
+// AudioOverlapResponse describes the analysis overlap of each source and the
+// adjustments adaptive overlap made under CPU pressure.
+type AudioOverlapResponse struct {
+	Enabled     bool                        `json:"enabled"`
+	Configured  float64                     `json:"configured"`
+	MinOverlap  float64                     `json:"min_overlap"`
+	Sources     []myaudio.OverlapState      `json:"sources"`
+	Adjustments []myaudio.OverlapAdjustment `json:"adjustments"`
+}
+
+// GetAudioOverlap handles GET /api/v2/system/audio/overlap
+// Reports the overlap each source is analyzed with and the recent adjustments,
+// oldest first.
+func (c *Controller) GetAudioOverlap(ctx echo.Context) error {
+	c.logInfoIfEnabled("Getting analysis overlap",
+		logger.String("path", ctx.Request().URL.Path),
+		logger.String("ip", ctx.RealIP()),
+	)
+
+	ctx.Response().Header().Set("Cache-Control", "no-cache")
+	return ctx.JSON(http.StatusOK, AudioOverlapResponse{
+		Enabled:     c.Settings.BirdNET.AdaptiveOverlap.Enabled,
+		Configured:  c.Settings.BirdNET.Overlap,
+		MinOverlap:  c.Settings.BirdNET.AdaptiveOverlap.MinOverlap,
+		Sources:     myaudio.GetOverlapStates(),
+		Adjustments: myaudio.GetOverlapAdjustments(),
+	})
+}
+
 // GetDatabaseStats handles GET /api/v2/system/database/stats
 // This endpoint returns statistics for the primary database.
 // In v2-only mode (fresh install or post-migration), it returns v2 database stats.
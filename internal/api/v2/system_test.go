@@ -542,6 +542,61 @@ func TestGetEqualizerConfig(t *testing.T) {
 	require.NoError(t, err, "Response should be valid JSON")
 }
 
+// TestGetEqualizerConfig_Sources tests the per-source view of the GetEqualizerConfig endpoint
+func TestGetEqualizerConfig_Sources(t *testing.T) {
+	t.Parallel()
+	t.Attr("component", "system")
+	t.Attr("type", "integration")
+	t.Attr("feature", "equalizer-config")
+
+	e, controller := setupSystemTestEnvironment(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/system/audio/equalizer/config?sources=true", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/v2/system/audio/equalizer/config")
+
+	err := controller.GetEqualizerConfig(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"), "Source chains must not be cached")
+
+	var response EqualizerConfigResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err, "Response should be valid JSON")
+	assert.Contains(t, response.Filters, "HighPass", "Response should include filter type configuration")
+	assert.NotNil(t, response.Sources, "Response should include the source list")
+}
+
+// TestGetAudioOverlap tests the GetAudioOverlap endpoint
+func TestGetAudioOverlap(t *testing.T) {
+	t.Parallel()
+	t.Attr("component", "system")
+	t.Attr("type", "integration")
+	t.Attr("feature", "adaptive-overlap")
+
+	e, controller := setupSystemTestEnvironment(t)
+	controller.Settings.BirdNET.Overlap = 1.5
+	controller.Settings.BirdNET.AdaptiveOverlap = conf.AdaptiveOverlapSettings{Enabled: true, MinOverlap: 0.5}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v2/system/audio/overlap", http.NoBody)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/v2/system/audio/overlap")
+
+	err := controller.GetAudioOverlap(c)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "no-cache", rec.Header().Get("Cache-Control"))
+
+	var response AudioOverlapResponse
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	require.NoError(t, err, "Response should be valid JSON")
+	assert.True(t, response.Enabled)
+	assert.InDelta(t, 1.5, response.Configured, 1e-9)
+	assert.InDelta(t, 0.5, response.MinOverlap, 1e-9)
+}
+
 // TestGetActiveAudioDevice tests the GetActiveAudioDevice endpoint
 func TestGetActiveAudioDevice(t *testing.T) {
 	t.Parallel()
@@ -11,6 +11,7 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/labstack/echo/v4"
+	"github.com/tphakala/birdnet-go/internal/api/auth"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
@@ -56,7 +57,7 @@ func (c *Controller) initTerminalRoutes() {
 
 	terminalGroup := c.Group.Group("/terminal")
 	protectedGroup := terminalGroup.Group("", c.authMiddleware)
-	protectedGroup.GET("/ws", c.HandleTerminalWS)
+	protectedGroup.GET("/ws", c.HandleTerminalWS, auth.RequireWrite)
 
 	c.logInfoIfEnabled("Terminal routes initialized successfully")
 }
@@ -44,6 +44,16 @@ func (bn *BirdNET) PredictWithContext(ctx context.Context, sample [][]float32) (
 	bn.mu.Lock()
 	defer bn.mu.Unlock()
 
+	// Batched inference may have resized the input
+	if err := bn.resizeInputBatch(1); err != nil {
+		span.SetTag("error", "true")
+		span.SetData("error_type", "input_resize_failed")
+		if globalMetrics != nil {
+			globalMetrics.RecordPrediction(bn.ModelInfo.ID, time.Since(start).Seconds(), err)
+		}
+		return nil, err
+	}
+
 	// Get the input tensor from the interpreter
 	inputTensor := bn.AnalysisInterpreter.GetInputTensor(0)
 	if inputTensor == nil {
@@ -148,11 +158,171 @@ func (bn *BirdNET) PredictWithContext(ctx context.Context, sample [][]float32) (
 	return topResults, nil
 }
 
+// PredictSource performs inference on a chunk of one audio source. With
+// inference workers configured the chunk is analyzed remotely, falling back to
+// the local model when allowed. With a batch size above one, chunks of sources
+// waiting at the same time share a model invocation.
+func (bn *BirdNET) PredictSource(ctx context.Context, sourceID string, sample [][]float32) ([]datastore.Results, error) {
+	if remote := bn.remoteInference(); remote != nil && len(sample) > 0 {
+		results, err := bn.predictRemote(ctx, remote, sourceID, sample[0])
+		if err == nil {
+			remote.useFallback(false, nil)
+			return results, nil
+		}
+		if !bn.Settings.BirdNET.Remote.Fallback {
+			return nil, err
+		}
+		remote.useFallback(true, err)
+	}
+	return bn.predictLocal(ctx, sourceID, sample)
+}
+
+// predictLocal analyzes a chunk of one audio source with the local model.
+func (bn *BirdNET) predictLocal(ctx context.Context, sourceID string, sample [][]float32) ([]datastore.Results, error) {
+	if bn.Settings.BirdNET.BatchSize <= 1 || len(sample) == 0 {
+		return bn.PredictWithContext(ctx, sample)
+	}
+	return bn.batchScheduler().Predict(ctx, sourceID, sample[0])
+}
+
+// PredictBatch performs inference on several chunks in one invocation of the
+// model, resizing its batch dimension to the number of chunks, and returns the
+// top results of each chunk in order.
+func (bn *BirdNET) PredictBatch(ctx context.Context, samples [][]float32) ([][]datastore.Results, error) {
+	span, _ := StartSpan(ctx, "birdnet.predict", "Batched species prediction")
+	defer span.Finish()
+
+	start := time.Now()
+	span.SetTag("model", bn.ModelInfo.ID)
+	span.SetData("batch_size", len(samples))
+	if len(samples) == 0 {
+		return nil, nil
+	}
+
+	bn.mu.Lock()
+	defer bn.mu.Unlock()
+
+	fail := func(errorType string, err error) ([][]datastore.Results, error) {
+		span.SetTag("error", "true")
+		span.SetData("error_type", errorType)
+		if globalMetrics != nil {
+			globalMetrics.RecordPrediction(bn.ModelInfo.ID, time.Since(start).Seconds(), err)
+		}
+		return nil, err
+	}
+
+	if err := bn.resizeInputBatch(len(samples)); err != nil {
+		return fail("input_resize_failed", err)
+	}
+	inputTensor := bn.AnalysisInterpreter.GetInputTensor(0)
+	if inputTensor == nil {
+		return fail("input_tensor_nil", errors.Newf("cannot get input tensor").
+			Category(errors.CategoryModelInit).
+			ModelContext(bn.Settings.BirdNET.ModelPath, bn.ModelInfo.ID).
+			Build())
+	}
+
+	// Short chunks are zero padded like the single chunk path
+	input := inputTensor.Float32s()
+	clear(input)
+	sampleLength := len(input) / len(samples)
+	for i, sample := range samples {
+		copy(input[i*sampleLength:(i+1)*sampleLength], sample)
+	}
+
+	invokeStart := time.Now()
+	if status := bn.AnalysisInterpreter.Invoke(); status != tflite.OK {
+		return fail("invoke_failed", errors.Newf("tensor invoke failed: %v", status).
+			Category(errors.CategoryAudio).
+			ModelContext(bn.Settings.BirdNET.ModelPath, bn.ModelInfo.ID).
+			Context("batch_size", len(samples)).
+			Context("status_code", status).
+			Timing("prediction-invoke", time.Since(start)).
+			Build())
+	}
+	if globalMetrics != nil {
+		globalMetrics.RecordModelInvoke(bn.ModelInfo.ID, time.Since(invokeStart).Seconds())
+	}
+
+	outputTensor := bn.AnalysisInterpreter.GetOutputTensor(0)
+	output := outputTensor.Float32s()
+	classes := outputTensor.Dim(outputTensor.NumDims() - 1)
+	results := make([][]datastore.Results, len(samples))
+	for i := range samples {
+		// Rows get their own buffers, the results outlive the lock
+		confidence := applySigmoidToPredictions(output[i*classes:(i+1)*classes], bn.Settings.BirdNET.Sensitivity)
+		paired, err := pairLabelsAndConfidence(bn.Settings.BirdNET.Labels, confidence)
+		if err != nil {
+			return fail("label_mismatch", errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("label_count", len(bn.Settings.BirdNET.Labels)).
+				Context("confidence_count", len(confidence)).
+				Build())
+		}
+		results[i] = getTopKResults(paired, 10)
+	}
+
+	bn.Debug("Batch of %d predictions completed in %v", len(samples), time.Since(start))
+	span.SetData("total_duration_ms", time.Since(start).Milliseconds())
+	span.SetTag("error", "false")
+	return results, nil
+}
+
+// resizeInputBatch resizes the batch dimension of the analysis input. The
+// caller must hold bn.mu.
+func (bn *BirdNET) resizeInputBatch(batch int) error {
+	if bn.inputBatch == batch || (bn.inputBatch == 0 && batch == 1) {
+		return nil
+	}
+	inputTensor := bn.AnalysisInterpreter.GetInputTensor(0)
+	if inputTensor == nil {
+		return errors.Newf("cannot get input tensor").
+			Category(errors.CategoryModelInit).
+			ModelContext(bn.Settings.BirdNET.ModelPath, bn.ModelInfo.ID).
+			Build()
+	}
+
+	shape := inputTensor.Shape()
+	dims := make([]int32, len(shape))
+	for i, dim := range shape {
+		dims[i] = int32(dim) //nolint:gosec // G115: tensor dimensions are small
+	}
+	dims[0] = int32(batch) //nolint:gosec // G115: batch size bounded by conf.MaxBirdNETBatchSize
+	if status := bn.AnalysisInterpreter.ResizeInputTensor(0, dims); status != tflite.OK {
+		return errors.Newf("input resize to batch %d failed: %v", batch, status).
+			Category(errors.CategoryModelInit).
+			ModelContext(bn.Settings.BirdNET.ModelPath, bn.ModelInfo.ID).
+			Build()
+	}
+	if status := bn.AnalysisInterpreter.AllocateTensors(); status != tflite.OK {
+		return errors.Newf("tensor allocation for batch %d failed: %v", batch, status).
+			Category(errors.CategoryModelInit).
+			ModelContext(bn.Settings.BirdNET.ModelPath, bn.ModelInfo.ID).
+			Build()
+	}
+	bn.inputBatch = batch
+	return nil
+}
+
 // customSigmoid applies a sigmoid function with sensitivity adjustment to a value.
 func customSigmoid(x, sensitivity float64) float64 {
 	return 1.0 / (1.0 + math.Exp(-sensitivity*x))
 }
 
+// RescaleConfidence converts a confidence the model output with one sigmoid
+// sensitivity to the confidence it would have output with another, by
+// recovering the logit the sigmoid was applied to.
+func RescaleConfidence(confidence float32, fromSensitivity, toSensitivity float64) float32 {
+	// Keep the logit finite for the 0 and 1 a rounded confidence can hold
+	const epsilon = 1e-6
+	if fromSensitivity <= 0 || fromSensitivity == toSensitivity {
+		return confidence
+	}
+	c := math.Min(math.Max(float64(confidence), epsilon), 1-epsilon)
+	logit := math.Log(c/(1-c)) / fromSensitivity
+	return float32(customSigmoid(logit, toSensitivity))
+}
+
 // sortResults sorts a slice of Result by their confidence in descending order.
 func sortResults(results []datastore.Results) {
 	sort.Slice(results, func(i, j int) bool {
@@ -791,3 +791,20 @@ func generateLargeTestResults(count int) []datastore.Results {
 	}
 	return results
 }
+
+// TestRescaleConfidence tests converting confidences between sigmoid sensitivities
+func TestRescaleConfidence(t *testing.T) {
+	t.Parallel()
+
+	for _, logit := range []float64{-3, -0.5, 0, 0.8, 2.5} {
+		from := float32(customSigmoid(logit, 1.0))
+		want := customSigmoid(logit, 1.25)
+		assert.InDelta(t, want, RescaleConfidence(from, 1.0, 1.25), 1e-5, "logit %g", logit)
+	}
+
+	assert.InDelta(t, 0.5, RescaleConfidence(0.5, 1.0, 1.5), 1e-6, "the sigmoid midpoint does not move")
+	assert.Less(t, RescaleConfidence(0.3, 1.0, 1.5), float32(0.3), "a higher sensitivity lowers confidences below 0.5")
+	assert.Greater(t, RescaleConfidence(0.8, 1.0, 1.5), float32(0.8), "a higher sensitivity raises confidences above 0.5")
+	assert.InDelta(t, 0.7, RescaleConfidence(0.7, 1.0, 1.0), 1e-6)
+	assert.False(t, math.IsNaN(float64(RescaleConfidence(1, 1.0, 0.5))), "a rounded confidence of 1 stays finite")
+}
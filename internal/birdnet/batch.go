@@ -0,0 +1,258 @@
+package birdnet
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Batched inference lets installations with many sources keep up. Every
+// analysis buffer submits its chunk and waits while a single worker runs the
+// model. Whenever the model is free the worker takes up to the batch size of
+// the waiting chunks, one per source in rotating order so a source with a
+// backlog cannot starve the others, and analyzes them in one invocation.
+// Chunks never wait for a batch to fill: batches form from what queued while
+// the model was busy, so a lightly loaded system keeps single chunk latency.
+
+// ErrBatchSchedulerStopped is returned for chunks submitted or still queued
+// when the scheduler stops.
+var ErrBatchSchedulerStopped = errors.Newf("batched inference stopped").
+	Component("birdnet").
+	Category(errors.CategoryState).
+	Build()
+
+// BatchStats summarizes the batched inference since startup.
+type BatchStats struct {
+	Batches       int64         `json:"batches"`
+	Chunks        int64         `json:"chunks"`
+	AvgBatchSize  float64       `json:"avg_batch_size"`
+	MaxBatchSize  int           `json:"max_batch_size"`
+	QueueDepth    int           `json:"queue_depth"`     // chunks waiting now
+	MaxQueueDepth int           `json:"max_queue_depth"` // most chunks ever waiting at once
+	AvgQueueWait  time.Duration `json:"avg_queue_wait"`
+	MaxQueueWait  time.Duration `json:"max_queue_wait"`
+}
+
+// batchRequest is a chunk of one source waiting for inference
+type batchRequest struct {
+	ctx    context.Context
+	sample []float32
+	queued time.Time
+	done   chan batchResult
+}
+
+type batchResult struct {
+	results []datastore.Results
+	err     error
+}
+
+// batchScheduler collects chunks of several sources into batched model invocations
+type batchScheduler struct {
+	predict   func(ctx context.Context, samples [][]float32) ([][]datastore.Results, error)
+	batchSize func() int
+	model     string
+
+	mu        sync.Mutex
+	queues    map[string][]*batchRequest
+	rotation  []string // sources with queued chunks, next to be served first
+	stopped   bool
+	stats     BatchStats
+	totalWait time.Duration
+
+	wake chan struct{}
+	quit chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// newBatchScheduler starts a scheduler running predict on batches of at most
+// batchSize() chunks.
+func newBatchScheduler(predict func(context.Context, [][]float32) ([][]datastore.Results, error), batchSize func() int, model string) *batchScheduler {
+	s := &batchScheduler{
+		predict:   predict,
+		batchSize: batchSize,
+		model:     model,
+		queues:    make(map[string][]*batchRequest),
+		wake:      make(chan struct{}, 1),
+		quit:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// batchScheduler returns the batch scheduler of the model, starting it on first use.
+func (bn *BirdNET) batchScheduler() *batchScheduler {
+	bn.batcherMu.Lock()
+	defer bn.batcherMu.Unlock()
+	if bn.batcher == nil {
+		bn.batcher = newBatchScheduler(bn.PredictBatch, func() int { return bn.Settings.BirdNET.BatchSize }, bn.ModelInfo.ID)
+	}
+	return bn.batcher
+}
+
+// BatchStats reports the batched inference since startup, zero when batching
+// has not been used.
+func (bn *BirdNET) BatchStats() BatchStats {
+	bn.batcherMu.Lock()
+	defer bn.batcherMu.Unlock()
+	if bn.batcher == nil {
+		return BatchStats{}
+	}
+	return bn.batcher.Stats()
+}
+
+// Predict queues a chunk of a source and waits for its results. The sample
+// must stay unchanged until Predict returns. A chunk whose context ends while
+// queued is skipped.
+func (s *batchScheduler) Predict(ctx context.Context, sourceID string, sample []float32) ([]datastore.Results, error) {
+	req := &batchRequest{ctx: ctx, sample: sample, queued: time.Now(), done: make(chan batchResult, 1)}
+
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return nil, ErrBatchSchedulerStopped
+	}
+	if _, waiting := s.queues[sourceID]; !waiting {
+		s.rotation = append(s.rotation, sourceID)
+	}
+	s.queues[sourceID] = append(s.queues[sourceID], req)
+	s.stats.QueueDepth++
+	s.stats.MaxQueueDepth = max(s.stats.MaxQueueDepth, s.stats.QueueDepth)
+	depth := s.stats.QueueDepth
+	s.mu.Unlock()
+
+	if m := getMetrics(); m != nil {
+		m.SetBatchQueueDepth(float64(depth))
+	}
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+
+	// The worker may be reading the sample, always wait for it
+	result := <-req.done
+	return result.results, result.err
+}
+
+// Stats reports the batches run so far.
+func (s *batchScheduler) Stats() BatchStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stats := s.stats
+	if stats.Batches > 0 {
+		stats.AvgBatchSize = float64(stats.Chunks) / float64(stats.Batches)
+	}
+	if stats.Chunks > 0 {
+		stats.AvgQueueWait = s.totalWait / time.Duration(stats.Chunks)
+	}
+	return stats
+}
+
+// Stop ends the worker, failing chunks still queued.
+func (s *batchScheduler) Stop() {
+	s.once.Do(func() { close(s.quit) })
+	<-s.done
+}
+
+// run analyzes queued chunks until the scheduler stops.
+func (s *batchScheduler) run() {
+	defer close(s.done)
+	for {
+		select {
+		case <-s.quit:
+			s.drain()
+			return
+		case <-s.wake:
+		}
+		for batch := s.take(); len(batch) > 0; batch = s.take() {
+			s.process(batch)
+		}
+	}
+}
+
+// take removes the next batch from the queues, one chunk per source per round.
+func (s *batchScheduler) take() []*batchRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	limit := max(s.batchSize(), 1)
+	var batch []*batchRequest
+	for len(batch) < limit && len(s.rotation) > 0 {
+		sourceID := s.rotation[0]
+		s.rotation = s.rotation[1:]
+		queue := s.queues[sourceID]
+		req := queue[0]
+		if len(queue) > 1 {
+			s.queues[sourceID] = queue[1:]
+			s.rotation = append(s.rotation, sourceID)
+		} else {
+			delete(s.queues, sourceID)
+		}
+		s.stats.QueueDepth--
+
+		if err := req.ctx.Err(); err != nil {
+			req.done <- batchResult{err: err}
+			continue
+		}
+		batch = append(batch, req)
+	}
+	return batch
+}
+
+// process runs one batch through the model and hands out the results.
+func (s *batchScheduler) process(batch []*batchRequest) {
+	start := time.Now()
+	samples := make([][]float32, len(batch))
+	waits := make([]float64, len(batch))
+	var totalWait, maxWait time.Duration
+	for i, req := range batch {
+		samples[i] = req.sample
+		wait := start.Sub(req.queued)
+		waits[i] = wait.Seconds()
+		totalWait += wait
+		maxWait = max(maxWait, wait)
+	}
+
+	results, err := s.predict(context.Background(), samples)
+
+	s.mu.Lock()
+	s.stats.Batches++
+	s.stats.Chunks += int64(len(batch))
+	s.stats.MaxBatchSize = max(s.stats.MaxBatchSize, len(batch))
+	s.stats.MaxQueueWait = max(s.stats.MaxQueueWait, maxWait)
+	s.totalWait += totalWait
+	depth := s.stats.QueueDepth
+	s.mu.Unlock()
+
+	if m := getMetrics(); m != nil {
+		m.RecordBatch(s.model, len(batch), waits)
+		m.SetBatchQueueDepth(float64(depth))
+	}
+
+	for i, req := range batch {
+		if err != nil {
+			req.done <- batchResult{err: err}
+			continue
+		}
+		req.done <- batchResult{results: results[i]}
+	}
+}
+
+// drain fails the chunks still queued once the scheduler stops.
+func (s *batchScheduler) drain() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stopped = true
+	for sourceID, queue := range s.queues {
+		for _, req := range queue {
+			req.done <- batchResult{err: ErrBatchSchedulerStopped}
+		}
+		delete(s.queues, sourceID)
+	}
+	s.rotation = nil
+	s.stats.QueueDepth = 0
+}
@@ -0,0 +1,124 @@
+package birdnet
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+// recordingPredictor labels every chunk with its first sample and records the batches
+type recordingPredictor struct {
+	mu      sync.Mutex
+	batches [][]float32
+	gate    chan struct{} // when set, every invocation waits for a value
+	err     error
+}
+
+func (p *recordingPredictor) predict(_ context.Context, samples [][]float32) ([][]datastore.Results, error) {
+	if p.gate != nil {
+		<-p.gate
+	}
+	ids := make([]float32, len(samples))
+	results := make([][]datastore.Results, len(samples))
+	for i, sample := range samples {
+		ids[i] = sample[0]
+		results[i] = []datastore.Results{{Species: fmt.Sprint(sample[0]), Confidence: 0.9}}
+	}
+	p.mu.Lock()
+	p.batches = append(p.batches, ids)
+	p.mu.Unlock()
+	return results, p.err
+}
+
+// submit predicts a chunk identified by id in the background
+func submit(wg *sync.WaitGroup, s *batchScheduler, sourceID string, id float32) {
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		_, _ = s.Predict(context.Background(), sourceID, []float32{id})
+	}()
+}
+
+// waitForQueueDepth waits until depth chunks are queued
+func waitForQueueDepth(t *testing.T, s *batchScheduler, depth int) {
+	t.Helper()
+	require.Eventually(t, func() bool { return s.Stats().QueueDepth == depth }, 2*time.Second, time.Millisecond)
+}
+
+func TestBatchScheduler_RoundRobinAcrossSources(t *testing.T) {
+	t.Parallel()
+
+	p := &recordingPredictor{gate: make(chan struct{})}
+	s := newBatchScheduler(p.predict, func() int { return 2 }, "test")
+	defer s.Stop()
+
+	var wg sync.WaitGroup
+	// The first chunk occupies the model while the others queue
+	submit(&wg, s, "camera_a", 1)
+	require.Eventually(t, func() bool { return s.Stats().QueueDepth == 0 }, 2*time.Second, time.Millisecond)
+	// A backlog of one source must not starve the others
+	for i, source := range []string{"camera_a", "camera_a", "camera_b", "camera_c"} {
+		submit(&wg, s, source, float32(i+2))
+		waitForQueueDepth(t, s, i+1)
+	}
+	for range 3 {
+		p.gate <- struct{}{}
+	}
+	wg.Wait()
+
+	assert.Equal(t, [][]float32{{1}, {2, 4}, {5, 3}}, p.batches)
+	stats := s.Stats()
+	assert.Equal(t, int64(3), stats.Batches)
+	assert.Equal(t, int64(5), stats.Chunks)
+	assert.Equal(t, 2, stats.MaxBatchSize)
+	assert.Equal(t, 4, stats.MaxQueueDepth)
+	assert.Zero(t, stats.QueueDepth)
+	assert.InDelta(t, 5.0/3.0, stats.AvgBatchSize, 0.001)
+}
+
+func TestBatchScheduler_ReturnsResultsOfEachChunk(t *testing.T) {
+	t.Parallel()
+
+	p := &recordingPredictor{}
+	s := newBatchScheduler(p.predict, func() int { return 8 }, "test")
+	defer s.Stop()
+
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results, err := s.Predict(context.Background(), fmt.Sprintf("camera_%d", i), []float32{float32(i)})
+			assert.NoError(t, err)
+			assert.Equal(t, fmt.Sprint(i), results[0].Species)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int64(8), s.Stats().Chunks)
+}
+
+func TestBatchScheduler_Errors(t *testing.T) {
+	t.Parallel()
+
+	p := &recordingPredictor{err: fmt.Errorf("invoke failed")}
+	s := newBatchScheduler(p.predict, func() int { return 4 }, "test")
+	_, err := s.Predict(context.Background(), "camera_a", []float32{1})
+	require.Error(t, err)
+
+	// Chunks whose context ended while queued are skipped
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = s.Predict(ctx, "camera_a", []float32{2})
+	require.ErrorIs(t, err, context.Canceled)
+
+	s.Stop()
+	_, err = s.Predict(context.Background(), "camera_a", []float32{3})
+	require.ErrorIs(t, err, ErrBatchSchedulerStopped)
+	assert.Len(t, p.batches, 1)
+}
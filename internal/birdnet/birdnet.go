@@ -50,6 +50,15 @@ type BirdNET struct {
 	mu                  sync.Mutex
 	resultsBuffer       []datastore.Results // Pre-allocated buffer for results to reduce allocations
 	confidenceBuffer    []float32           // Pre-allocated buffer for confidence values to reduce allocations
+	inputBatch          int                 // Batch dimension the analysis input is resized to, 0 as loaded
+
+	// Batched inference across sources, started on first use
+	batcherMu sync.Mutex
+	batcher   *batchScheduler
+
+	// Remote inference workers, prepared on first use
+	remoteMu sync.Mutex
+	remote   *remoteInference
 
 	// Species occurrence cache to avoid repeated GetProbableSpecies calls within same day
 	speciesCacheMu sync.RWMutex
@@ -206,6 +215,7 @@ func (bn *BirdNET) initializeModel() error {
 	if status := bn.AnalysisInterpreter.AllocateTensors(); status != tflite.OK {
 		return fmt.Errorf("tensor allocation failed")
 	}
+	bn.inputBatch = 0
 
 	// Force garbage collection to reclaim memory from model loading
 	// The model data is no longer needed as TFLite has created its own internal copy
@@ -628,6 +638,13 @@ func (bn *BirdNET) getCachedSpeciesScores(targetDate time.Time) (map[string]floa
 // Delete releases resources used by the TensorFlow Lite interpreters.
 // Note: With go-tflite v0.2.0+, interpreter cleanup is handled automatically by GC.
 func (bn *BirdNET) Delete() {
+	bn.batcherMu.Lock()
+	if bn.batcher != nil {
+		bn.batcher.Stop()
+		bn.batcher = nil
+	}
+	bn.batcherMu.Unlock()
+
 	bn.AnalysisInterpreter = nil
 	bn.RangeInterpreter = nil
 	bn.clearSpeciesCache()
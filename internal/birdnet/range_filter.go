@@ -85,6 +85,13 @@ func BuildRangeFilter(bn *BirdNET) error {
 // GetProbableSpecies filters and sorts bird species based on their scores.
 // It also updates the scores for species that have custom actions defined in the speciesConfigCSV.
 func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesScore, error) {
+	return bn.GetProbableSpeciesWith(bn.Settings, date, week)
+}
+
+// GetProbableSpeciesWith is GetProbableSpecies with the location, range filter
+// threshold and species lists of settings instead of the settings of the model,
+// so candidate settings can be evaluated without changing the active ones.
+func (bn *BirdNET) GetProbableSpeciesWith(settings *conf.Settings, date time.Time, week float32) ([]SpeciesScore, error) {
 	bn.Debug("Applying range filter")
 
 	// Skip filtering if range interpreter is not initialized
@@ -94,37 +101,37 @@ func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesSc
 	}
 
 	// Skip filtering if location is not set
-	if bn.Settings.BirdNET.Latitude == 0 && bn.Settings.BirdNET.Longitude == 0 {
+	if settings.BirdNET.Latitude == 0 && settings.BirdNET.Longitude == 0 {
 		bn.Debug("Latitude and longitude not set, not using location based prediction filter")
 		return zeroScoresForAllLabels(bn.Settings.BirdNET.Labels), nil
 	}
 
 	// Apply prediction filter based on the context
-	filters, err := bn.predictFilter(date, week)
+	filters, err := bn.predictFilter(settings, date, week)
 	if err != nil {
 		return nil, errors.New(err).
 			Category(errors.CategoryValidation).
 			Context("date", date.Format(time.DateOnly)).
 			Context("week", week).
-			Context("model", bn.Settings.BirdNET.RangeFilter.Model).
+			Context("model", settings.BirdNET.RangeFilter.Model).
 			Build()
 	}
 
-	// check bn.Settings.BirdNET.LocationFilterThreshold for valid value
-	if bn.Settings.BirdNET.RangeFilter.Threshold < 0 ||
-		bn.Settings.BirdNET.RangeFilter.Threshold > 1 {
+	// check settings.BirdNET.LocationFilterThreshold for valid value
+	if settings.BirdNET.RangeFilter.Threshold < 0 ||
+		settings.BirdNET.RangeFilter.Threshold > 1 {
 		GetLogger().Warn("Invalid LocationFilterThreshold value, using default",
-			logger.Float64("invalid_value", float64(bn.Settings.BirdNET.RangeFilter.Threshold)),
+			logger.Float64("invalid_value", float64(settings.BirdNET.RangeFilter.Threshold)),
 			logger.Float64("default_value", 0.01))
-		bn.Settings.BirdNET.RangeFilter.Threshold = 0.01
+		settings.BirdNET.RangeFilter.Threshold = 0.01
 	}
 
 	// Collect species scores above a certain threshold
 	var speciesScores []SpeciesScore
 	for _, filter := range filters {
-		if filter.Score >= bn.Settings.BirdNET.RangeFilter.Threshold {
+		if filter.Score >= settings.BirdNET.RangeFilter.Threshold {
 			// Check if species is in exclude list before adding
-			if !isSpeciesExcluded(filter.Label, bn.Settings.Realtime.Species.Exclude) {
+			if !isSpeciesExcluded(filter.Label, settings.Realtime.Species.Exclude) {
 				speciesScores = append(speciesScores, SpeciesScore{Score: float64(filter.Score), Label: filter.Label})
 			} else {
 				bn.Debug("Excluding species from range filter: %s", filter.Label)
@@ -136,13 +143,13 @@ func (bn *BirdNET) GetProbableSpecies(date time.Time, week float32) ([]SpeciesSc
 	processedSpecies := make(map[string]bool)
 
 	// Process explicitly included species
-	for _, includedSpecies := range bn.Settings.Realtime.Species.Include {
+	for _, includedSpecies := range settings.Realtime.Species.Include {
 		bn.Debug("Processing included species: %s", includedSpecies)
 		addSpeciesWithMaxScore(bn, &speciesScores, includedSpecies, processedSpecies)
 	}
 
 	// Process species with configured actions
-	for species := range bn.Settings.Realtime.Species.Config {
+	for species := range settings.Realtime.Species.Config {
 		bn.Debug("Processing species with actions: %s", species)
 		addSpeciesWithMaxScore(bn, &speciesScores, species, processedSpecies)
 	}
@@ -200,7 +207,7 @@ func matchesSpecies(label, speciesName string) bool {
 }
 
 // predictFilter applies a TensorFlow Lite model to predict species based on the context.
-func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error) {
+func (bn *BirdNET) predictFilter(settings *conf.Settings, date time.Time, week float32) ([]Filter, error) {
 	start := time.Now()
 
 	input := bn.RangeInterpreter.GetInputTensor(0)
@@ -218,7 +225,7 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 	}
 
 	// Prepare the input data
-	data := []float32{float32(bn.Settings.BirdNET.Latitude), float32(bn.Settings.BirdNET.Longitude), week}
+	data := []float32{float32(settings.BirdNET.Latitude), float32(settings.BirdNET.Longitude), week}
 
 	// Retrieve the input tensor's underlying data slice
 	float32s := input.Float32s()
@@ -242,8 +249,8 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 			Category(errors.CategoryModelInit).
 			Context("model_type", "range_filter").
 			Context("status_code", status).
-			Context("latitude", bn.Settings.BirdNET.Latitude).
-			Context("longitude", bn.Settings.BirdNET.Longitude).
+			Context("latitude", settings.BirdNET.Latitude).
+			Context("longitude", settings.BirdNET.Longitude).
 			Context("week", week).
 			Timing("range-filter-invoke", time.Since(start)).
 			Build()
@@ -260,7 +267,7 @@ func (bn *BirdNET) predictFilter(date time.Time, week float32) ([]Filter, error)
 	// Filter and label the results, but only for indices that exist in bn.Labels
 	var results []Filter
 	for i, score := range filter {
-		if score >= bn.Settings.BirdNET.RangeFilter.Threshold && i < len(bn.Settings.BirdNET.Labels) {
+		if score >= settings.BirdNET.RangeFilter.Threshold && i < len(bn.Settings.BirdNET.Labels) {
 			results = append(results, Filter{Score: score, Label: bn.Settings.BirdNET.Labels[i]})
 		}
 	}
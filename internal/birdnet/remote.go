@@ -0,0 +1,304 @@
+package birdnet
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Remote inference lets capture sites too small to run the model, such as a
+// Pi Zero, send their chunks to `birdnet worker` on a stronger machine. A
+// chunk travels as 16-bit little-endian mono PCM, the worker analyzes it with
+// its own model at the sensitivity of the capture site and answers with the
+// top results, which go on to the normal
+// processor as if the local model had produced them. Chunks are spread over
+// the workers in turn, a worker that fails is skipped with a growing backoff
+// and, when allowed, the local model analyzes what no worker answered.
+
+const (
+	workerPredictPath       = "/v1/predict"
+	workerHealthPath        = "/v1/health"
+	workerSourceHeader      = "X-BirdNET-Source"
+	workerSampleRateHeader  = "X-BirdNET-Sample-Rate"
+	workerSensitivityHeader = "X-BirdNET-Sensitivity"
+	workerPCMContentType    = "application/octet-stream"
+
+	workerMinBackoff = 5 * time.Second
+	workerMaxBackoff = time.Minute
+	workerErrorBody  = 256 // bytes of a worker error response kept for the log
+)
+
+// ErrNoInferenceWorker is returned when every inference worker is backing off
+// after failures.
+var ErrNoInferenceWorker = errors.Newf("no inference worker available").
+	Component("birdnet").
+	Category(errors.CategoryNetwork).
+	Build()
+
+// workerResult is one species of the results a worker returns
+type workerResult struct {
+	Species    string  `json:"species"`
+	Confidence float32 `json:"confidence"`
+}
+
+// workerResponse is the answer of a worker to a chunk
+type workerResponse struct {
+	Model   string         `json:"model"`
+	Locale  string         `json:"locale"`
+	Results []workerResult `json:"results"`
+}
+
+// remoteWorker is a worker chunks are sent to
+type remoteWorker struct {
+	host       string
+	predictURL string
+	token      string
+	failures   int       // consecutive failures
+	retryAt    time.Time // the worker is skipped until then after a failure
+}
+
+// remoteInference spreads chunks over the configured workers
+type remoteInference struct {
+	client  *http.Client
+	timeout time.Duration
+	key     string // settings the workers were built from
+
+	mu          sync.Mutex
+	workers     []*remoteWorker
+	next        int
+	fallback    bool // the last chunk was analyzed locally
+	warnedModel bool
+}
+
+// newRemoteInference prepares the workers of the settings, nil without workers
+func newRemoteInference(settings *conf.RemoteInferenceSettings) *remoteInference {
+	if len(settings.Workers) == 0 {
+		return nil
+	}
+	r := &remoteInference{
+		client:  &http.Client{},
+		timeout: settings.Timeout.Std(),
+		key:     remoteInferenceKey(settings),
+	}
+	for _, w := range settings.Workers {
+		u, err := url.Parse(w.URL)
+		if err != nil {
+			GetLogger().Warn("skipping inference worker with invalid url",
+				logger.String("url", w.URL),
+				logger.Error(err))
+			continue
+		}
+		r.workers = append(r.workers, &remoteWorker{
+			host:       u.Host,
+			predictURL: u.JoinPath(workerPredictPath).String(),
+			token:      w.Token,
+		})
+	}
+	return r
+}
+
+// remoteInferenceKey identifies the settings a client was built from
+func remoteInferenceKey(settings *conf.RemoteInferenceSettings) string {
+	return fmt.Sprint(settings.Workers, settings.Timeout.Std())
+}
+
+// remoteInference returns the client of the configured workers, nil when
+// chunks are analyzed locally. The client follows settings changes.
+func (bn *BirdNET) remoteInference() *remoteInference {
+	settings := &bn.Settings.BirdNET.Remote
+	if len(settings.Workers) == 0 {
+		return nil
+	}
+	bn.remoteMu.Lock()
+	defer bn.remoteMu.Unlock()
+	if bn.remote == nil || bn.remote.key != remoteInferenceKey(settings) {
+		bn.remote = newRemoteInference(settings)
+		GetLogger().Info("remote inference enabled",
+			logger.Int("workers", len(settings.Workers)),
+			logger.Duration("timeout", settings.Timeout.Std()),
+			logger.Bool("local_fallback", settings.Fallback))
+	}
+	return bn.remote
+}
+
+// predictRemote analyzes a chunk on the first worker that answers.
+func (bn *BirdNET) predictRemote(ctx context.Context, remote *remoteInference, sourceID string, sample []float32) ([]datastore.Results, error) {
+	body := encodePCM16(sample)
+
+	var lastErr error
+	for _, w := range remote.candidates(time.Now()) {
+		resp, err := remote.send(ctx, w, sourceID, bn.Settings.BirdNET.Sensitivity, body)
+		remote.report(w, err, time.Now())
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		remote.checkModel(resp, bn.ModelInfo.ID, bn.Settings.BirdNET.Locale)
+		results := make([]datastore.Results, len(resp.Results))
+		for i, r := range resp.Results {
+			results[i] = datastore.Results{Species: r.Species, Confidence: r.Confidence}
+		}
+		return results, nil
+	}
+	if lastErr == nil {
+		lastErr = ErrNoInferenceWorker
+	}
+	return nil, lastErr
+}
+
+// candidates returns the workers not backing off, starting with the next in turn.
+func (r *remoteInference) candidates(now time.Time) []*remoteWorker {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.workers) == 0 {
+		return nil
+	}
+	candidates := make([]*remoteWorker, 0, len(r.workers))
+	for i := range r.workers {
+		w := r.workers[(r.next+i)%len(r.workers)]
+		if !now.Before(w.retryAt) {
+			candidates = append(candidates, w)
+		}
+	}
+	r.next = (r.next + 1) % len(r.workers)
+	return candidates
+}
+
+// send posts a chunk to a worker and decodes its answer. The worker scores the
+// chunk at the given sensitivity instead of its own.
+func (r *remoteInference) send(ctx context.Context, w *remoteWorker, sourceID string, sensitivity float64, body []byte) (*workerResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.predictURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+w.token)
+	req.Header.Set("Content-Type", workerPCMContentType)
+	req.Header.Set(workerSourceHeader, sourceID)
+	req.Header.Set(workerSampleRateHeader, strconv.Itoa(conf.SampleRate))
+	req.Header.Set(workerSensitivityHeader, strconv.FormatFloat(sensitivity, 'f', -1, 64))
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("birdnet").
+			Category(errors.CategoryNetwork).
+			Context("worker", w.host).
+			Build()
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, workerErrorBody))
+		return nil, errors.Newf("inference worker answered %s: %s", resp.Status, bytes.TrimSpace(msg)).
+			Component("birdnet").
+			Category(errors.CategoryHTTP).
+			Context("worker", w.host).
+			Context("status_code", resp.StatusCode).
+			Build()
+	}
+
+	var decoded workerResponse
+	if err := json.NewDecoder(resp.Body).Decode(&decoded); err != nil {
+		return nil, errors.New(err).
+			Component("birdnet").
+			Category(errors.CategoryHTTP).
+			Context("worker", w.host).
+			Context("operation", "decode_worker_response").
+			Build()
+	}
+	return &decoded, nil
+}
+
+// report updates the backoff of a worker after a chunk and logs when a worker
+// fails or recovers.
+func (r *remoteInference) report(w *remoteWorker, err error, now time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err == nil {
+		if w.failures > 0 {
+			GetLogger().Info("inference worker recovered",
+				logger.String("worker", w.host),
+				logger.Int("failures", w.failures))
+		}
+		w.failures = 0
+		w.retryAt = time.Time{}
+		return
+	}
+
+	w.failures++
+	backoff := min(workerMinBackoff<<min(w.failures-1, 10), workerMaxBackoff)
+	w.retryAt = now.Add(backoff)
+	GetLogger().Warn("inference worker failed",
+		logger.String("worker", w.host),
+		logger.Int("failures", w.failures),
+		logger.Duration("retry_in", backoff),
+		logger.Error(err))
+}
+
+// useFallback records whether chunks are analyzed locally and logs when that changes.
+func (r *remoteInference) useFallback(fallback bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if fallback == r.fallback {
+		return
+	}
+	r.fallback = fallback
+	if fallback {
+		GetLogger().Warn("no inference worker answered, analyzing locally", logger.Error(err))
+		return
+	}
+	GetLogger().Info("inference workers answering again, local analysis stopped")
+}
+
+// checkModel warns once when a worker runs a different model or locale, whose
+// labels the processor would not match.
+func (r *remoteInference) checkModel(resp *workerResponse, model, locale string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.warnedModel || (resp.Model == model && resp.Locale == locale) {
+		return
+	}
+	r.warnedModel = true
+	GetLogger().Warn("inference worker runs a different model or locale",
+		logger.String("worker_model", resp.Model),
+		logger.String("worker_locale", resp.Locale),
+		logger.String("local_model", model),
+		logger.String("local_locale", locale))
+}
+
+// encodePCM16 converts samples to 16-bit little-endian PCM.
+func encodePCM16(sample []float32) []byte {
+	pcm := make([]byte, len(sample)*2)
+	for i, v := range sample {
+		s := int16(math.Round(float64(max(-1, min(v, 32767.0/32768.0))) * 32768)) //nolint:gosec // G115: clamped to the int16 range
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(s))                       //nolint:gosec // G115: two's complement bit pattern
+	}
+	return pcm
+}
+
+// decodePCM16 converts 16-bit little-endian PCM to samples.
+func decodePCM16(pcm []byte) []float32 {
+	sample := make([]float32, len(pcm)/2)
+	for i := range sample {
+		sample[i] = float32(int16(binary.LittleEndian.Uint16(pcm[i*2:]))) / 32768 //nolint:gosec // G115: two's complement bit pattern
+	}
+	return sample
+}
@@ -0,0 +1,213 @@
+package birdnet
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+)
+
+const testWorkerToken = "0123456789abcdef"
+
+// fakeWorker answers chunks like a worker, failing while fail is set
+type fakeWorker struct {
+	server *httptest.Server
+	chunks atomic.Int32
+	fail   atomic.Bool
+}
+
+func newFakeWorker(t *testing.T) *fakeWorker {
+	t.Helper()
+	f := &fakeWorker{}
+	f.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.chunks.Add(1)
+		if f.fail.Load() {
+			http.Error(w, "model busy", http.StatusServiceUnavailable)
+			return
+		}
+		assert.Equal(t, workerPredictPath, r.URL.Path)
+		assert.Equal(t, "Bearer "+testWorkerToken, r.Header.Get("Authorization"))
+		assert.Equal(t, "meadow", r.Header.Get(workerSourceHeader))
+		assert.Equal(t, "1", r.Header.Get(workerSensitivityHeader))
+		pcm, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		sample := decodePCM16(pcm)
+		writeWorkerJSON(w, workerResponse{
+			Model:   "test-model",
+			Locale:  "en-us",
+			Results: []workerResult{{Species: "Turdus merula_Eurasian Blackbird", Confidence: sample[0]}},
+		})
+	}))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+// newRemoteTestBirdNET returns a model that sends chunks to the workers
+func newRemoteTestBirdNET(fallback bool, workers ...*fakeWorker) *BirdNET {
+	settings := &conf.Settings{}
+	settings.BirdNET.Locale = "en-us"
+	settings.BirdNET.Sensitivity = 1
+	settings.BirdNET.Remote = conf.RemoteInferenceSettings{Timeout: conf.Duration(2 * time.Second), Fallback: fallback}
+	for _, w := range workers {
+		settings.BirdNET.Remote.Workers = append(settings.BirdNET.Remote.Workers, conf.RemoteWorker{URL: w.server.URL, Token: testWorkerToken})
+	}
+	return &BirdNET{Settings: settings, ModelInfo: ModelInfo{ID: "test-model"}}
+}
+
+func TestPCM16RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	sample := []float32{0, 0.5, -0.5, -1, 1.5, -2}
+	decoded := decodePCM16(encodePCM16(sample))
+	require.Len(t, decoded, len(sample))
+	for i, want := range []float32{0, 0.5, -0.5, -1, 32767.0 / 32768.0, -1} {
+		assert.InDelta(t, want, decoded[i], 1.0/32768, "sample %d", i)
+	}
+}
+
+func TestPredictSource_RemoteWorker(t *testing.T) {
+	t.Parallel()
+
+	worker := newFakeWorker(t)
+	bn := newRemoteTestBirdNET(false, worker)
+
+	results, err := bn.PredictSource(t.Context(), "meadow", [][]float32{{0.25, 0}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "Turdus merula_Eurasian Blackbird", results[0].Species)
+	assert.InDelta(t, 0.25, results[0].Confidence, 1e-4)
+}
+
+func TestPredictSource_WorkerFailover(t *testing.T) {
+	t.Parallel()
+
+	broken, healthy := newFakeWorker(t), newFakeWorker(t)
+	broken.fail.Store(true)
+	bn := newRemoteTestBirdNET(false, broken, healthy)
+
+	// Chunks go to the workers in turn, a failed worker is skipped while backing off
+	for range 4 {
+		_, err := bn.PredictSource(t.Context(), "meadow", [][]float32{{0.5}})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), broken.chunks.Load())
+	assert.Equal(t, int32(4), healthy.chunks.Load())
+
+	// Without local fallback the error of the last worker is returned
+	healthy.fail.Store(true)
+	_, err := bn.PredictSource(t.Context(), "meadow", [][]float32{{0.5}})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+	_, err = bn.PredictSource(t.Context(), "meadow", [][]float32{{0.5}})
+	require.ErrorIs(t, err, ErrNoInferenceWorker)
+}
+
+func TestRemoteInference_Backoff(t *testing.T) {
+	t.Parallel()
+
+	r := &remoteInference{workers: []*remoteWorker{{host: "a"}, {host: "b"}}}
+	now := time.Now()
+	r.report(r.workers[0], assert.AnError, now)
+	assert.Equal(t, now.Add(workerMinBackoff), r.workers[0].retryAt)
+	r.report(r.workers[0], assert.AnError, now)
+	assert.Equal(t, now.Add(2*workerMinBackoff), r.workers[0].retryAt)
+	for range 10 {
+		r.report(r.workers[0], assert.AnError, now)
+	}
+	assert.Equal(t, now.Add(workerMaxBackoff), r.workers[0].retryAt)
+
+	candidates := r.candidates(now)
+	require.Len(t, candidates, 1)
+	assert.Equal(t, "b", candidates[0].host)
+	assert.Len(t, r.candidates(now.Add(workerMaxBackoff)), 2)
+
+	r.report(r.workers[0], nil, now)
+	assert.Zero(t, r.workers[0].failures)
+}
+
+func TestWorkerHandler(t *testing.T) {
+	t.Parallel()
+
+	bn := &BirdNET{Settings: &conf.Settings{}, ModelInfo: ModelInfo{ID: "test-model"}}
+	bn.Settings.BirdNET.Locale = "fi"
+	handler := NewWorkerHandler(bn, testWorkerToken)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		header map[string]string
+		body   string
+		want   int
+	}{
+		{"no token", http.MethodGet, workerHealthPath, "", nil, "", http.StatusUnauthorized},
+		{"wrong token", http.MethodGet, workerHealthPath, "guess", nil, "", http.StatusUnauthorized},
+		{"health", http.MethodGet, workerHealthPath, testWorkerToken, nil, "", http.StatusOK},
+		{"odd chunk", http.MethodPost, workerPredictPath, testWorkerToken, nil, "abc", http.StatusBadRequest},
+		{"empty chunk", http.MethodPost, workerPredictPath, testWorkerToken, nil, "", http.StatusBadRequest},
+		{"other sample rate", http.MethodPost, workerPredictPath, testWorkerToken, map[string]string{workerSampleRateHeader: "32000"}, "ab", http.StatusBadRequest},
+		{"sensitivity too high", http.MethodPost, workerPredictPath, testWorkerToken, map[string]string{workerSensitivityHeader: "2"}, "ab", http.StatusBadRequest},
+		{"sensitivity not a number", http.MethodPost, workerPredictPath, testWorkerToken, map[string]string{workerSensitivityHeader: "high"}, "ab", http.StatusBadRequest},
+		{"chunk too large", http.MethodPost, workerPredictPath, testWorkerToken, nil, strings.Repeat("a", maxWorkerChunkBytes+2), http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			assert.Equal(t, tt.want, rec.Code)
+
+			if tt.name == "health" {
+				var health workerHealth
+				require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health))
+				assert.Equal(t, "test-model", health.Model)
+				assert.Equal(t, "fi", health.Locale)
+				assert.Equal(t, conf.SampleRate, health.SampleRate)
+			}
+		})
+	}
+}
+
+func TestPredictSource_WorkerAppliesClientSensitivity(t *testing.T) {
+	t.Parallel()
+
+	// The worker runs at its own sensitivity, its model sees a logit of 1
+	workerBN := &BirdNET{Settings: &conf.Settings{}, ModelInfo: ModelInfo{ID: "test-model"}}
+	workerBN.Settings.BirdNET.Locale = "en-us"
+	workerBN.Settings.BirdNET.Sensitivity = 1.0
+	h := &workerHandler{bn: workerBN, token: testWorkerToken}
+	h.analyze = func(context.Context, string, [][]float32) ([]datastore.Results, error) {
+		confidence := float32(customSigmoid(1, workerBN.Settings.BirdNET.Sensitivity))
+		return []datastore.Results{{Species: "Turdus merula_Eurasian Blackbird", Confidence: confidence}}, nil
+	}
+	server := httptest.NewServer(h.routes())
+	t.Cleanup(server.Close)
+
+	client := newRemoteTestBirdNET(false)
+	client.Settings.BirdNET.Sensitivity = 1.5
+	client.Settings.BirdNET.Remote.Workers = []conf.RemoteWorker{{URL: server.URL, Token: testWorkerToken}}
+
+	results, err := client.PredictSource(t.Context(), "meadow", [][]float32{{0.25}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.InDelta(t, customSigmoid(1, 1.5), results[0].Confidence, 1e-4, "scored at the client sensitivity")
+	assert.Greater(t, results[0].Confidence, float32(customSigmoid(1, 1.0)))
+}
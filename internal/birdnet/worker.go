@@ -0,0 +1,189 @@
+package birdnet
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+const (
+	maxWorkerChunkBytes = 2 * conf.BufferSize // largest chunk a worker accepts
+	maxWorkerSourceLen  = 128                 // longest source name kept for batching
+	workerShutdownGrace = 10 * time.Second
+)
+
+// workerHealth describes a worker to its clients
+type workerHealth struct {
+	Model      string `json:"model"`
+	Locale     string `json:"locale"`
+	Version    string `json:"version"`
+	SampleRate int    `json:"sample_rate"`
+	BatchSize  int    `json:"batch_size"`
+}
+
+// workerHandler serves the inference API of a worker
+type workerHandler struct {
+	bn      *BirdNET
+	token   string
+	analyze func(ctx context.Context, sourceID string, sample [][]float32) ([]datastore.Results, error)
+}
+
+// NewWorkerHandler returns the inference API of a worker. Every request must
+// carry "Authorization: Bearer <token>". A chunk is scored at the sensitivity
+// of its X-BirdNET-Sensitivity header, the worker's own when it has none.
+//
+//	POST /v1/predict  analyzes a chunk of 16-bit little-endian mono PCM
+//	GET  /v1/health   reports the model and locale of the worker
+func NewWorkerHandler(bn *BirdNET, token string) http.Handler {
+	h := &workerHandler{bn: bn, token: token, analyze: bn.predictLocal}
+	return h.routes()
+}
+
+// routes returns the authenticated API of the handler.
+func (h *workerHandler) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST "+workerPredictPath, h.predict)
+	mux.HandleFunc("GET "+workerHealthPath, h.health)
+	return h.authenticate(mux)
+}
+
+// authenticate rejects requests without the worker token.
+func (h *workerHandler) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="BirdNET-Go worker"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// predict analyzes a chunk with the local model. Chunks of different clients
+// share model invocations when batching is enabled.
+func (h *workerHandler) predict(w http.ResponseWriter, r *http.Request) {
+	if rate := r.Header.Get(workerSampleRateHeader); rate != "" && rate != strconv.Itoa(conf.SampleRate) {
+		http.Error(w, "sample rate must be "+strconv.Itoa(conf.SampleRate), http.StatusBadRequest)
+		return
+	}
+
+	// Score the chunk at the sensitivity of the capture site, the worker's own
+	// applies to chunks that do not name one
+	sensitivity := h.bn.Settings.BirdNET.Sensitivity
+	if header := r.Header.Get(workerSensitivityHeader); header != "" {
+		v, err := strconv.ParseFloat(header, 64)
+		if err != nil || v < conf.SensitivityMin || v > conf.SensitivityMax {
+			http.Error(w, fmt.Sprintf("sensitivity must be between %g and %g", conf.SensitivityMin, conf.SensitivityMax), http.StatusBadRequest)
+			return
+		}
+		sensitivity = v
+	}
+
+	pcm, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWorkerChunkBytes))
+	if err != nil {
+		http.Error(w, "chunk too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if len(pcm) == 0 || len(pcm)%2 != 0 {
+		http.Error(w, "chunk must be 16-bit PCM", http.StatusBadRequest)
+		return
+	}
+
+	// Batching rotates over sources, keep clients apart
+	client, _, _ := net.SplitHostPort(r.RemoteAddr)
+	source := client + "/" + r.Header.Get(workerSourceHeader)
+	if len(source) > maxWorkerSourceLen {
+		source = source[:maxWorkerSourceLen]
+	}
+
+	results, err := h.analyze(r.Context(), source, [][]float32{decodePCM16(pcm)})
+	if err != nil {
+		GetLogger().Error("worker prediction failed",
+			logger.String("client", client),
+			logger.Error(err))
+		http.Error(w, "prediction failed", http.StatusInternalServerError)
+		return
+	}
+
+	resp := workerResponse{
+		Model:   h.bn.ModelInfo.ID,
+		Locale:  h.bn.Settings.BirdNET.Locale,
+		Results: make([]workerResult, len(results)),
+	}
+	for i, result := range results {
+		confidence := RescaleConfidence(result.Confidence, h.bn.Settings.BirdNET.Sensitivity, sensitivity)
+		resp.Results[i] = workerResult{Species: result.Species, Confidence: confidence}
+	}
+	writeWorkerJSON(w, resp)
+}
+
+// health reports the model the worker analyzes with.
+func (h *workerHandler) health(w http.ResponseWriter, _ *http.Request) {
+	writeWorkerJSON(w, workerHealth{
+		Model:      h.bn.ModelInfo.ID,
+		Locale:     h.bn.Settings.BirdNET.Locale,
+		Version:    h.bn.Settings.Version,
+		SampleRate: conf.SampleRate,
+		BatchSize:  max(h.bn.Settings.BirdNET.BatchSize, 1),
+	})
+}
+
+func writeWorkerJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		GetLogger().Debug("failed to write worker response", logger.Error(err))
+	}
+}
+
+// ServeWorker serves the inference API of the model until ctx ends.
+func ServeWorker(ctx context.Context, bn *BirdNET, settings *conf.InferenceWorkerSettings) error {
+	server := &http.Server{
+		Addr:              settings.Listen,
+		Handler:           NewWorkerHandler(bn, settings.Token),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      30 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), workerShutdownGrace)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	GetLogger().Info("inference worker listening",
+		logger.String("listen", settings.Listen),
+		logger.String("model", bn.ModelInfo.ID),
+		logger.Bool("tls", settings.CertFile != ""))
+
+	var err error
+	if settings.CertFile != "" {
+		err = server.ListenAndServeTLS(settings.CertFile, settings.KeyFile)
+	} else {
+		err = server.ListenAndServe()
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return errors.New(err).
+			Component("birdnet").
+			Category(errors.CategoryNetwork).
+			Context("operation", "serve_worker").
+			Context("listen", settings.Listen).
+			Build()
+	}
+	return nil
+}
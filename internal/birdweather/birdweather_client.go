@@ -40,9 +40,6 @@ const (
 	// httpClientTimeout is the default timeout for HTTP requests
 	httpClientTimeout = 45 * time.Second
 
-	// encodingTimeout is the timeout for audio encoding operations
-	encodingTimeout = 30 * time.Second
-
 	// detectionDurationSeconds is the duration added to timestamp for end time
 	detectionDurationSeconds = 3
 )
@@ -400,66 +397,28 @@ func handleHTTPResponse(resp *http.Response, expectedStatus int, operation, mask
 	return responseBody, nil
 }
 
-// encodeFlacUsingFFmpeg converts PCM data to FLAC format using FFmpeg directly into a bytes buffer.
-// It applies a simple gain adjustment instead of dynamic loudness normalization to avoid pumping effects.
-// This avoids writing temporary files to disk.
-// It accepts a context for timeout/cancellation control and the explicit path to the FFmpeg executable.
-func encodeFlacUsingFFmpeg(ctx context.Context, pcmData []byte, ffmpegPath string, settings *conf.Settings) (*bytes.Buffer, error) {
+// encodeFlac converts PCM data to FLAC format in process.
+// It applies a single gain adjustment towards the target loudness instead of
+// dynamic loudness normalization to avoid pumping effects.
+func encodeFlac(pcmData []byte) (*bytes.Buffer, error) {
 	log := GetLogger()
 
 	log.Debug("Starting FLAC encoding process")
-	// Add check for empty pcmData
 	if len(pcmData) == 0 {
 		log.Error("FLAC encoding failed: PCM data is empty")
 		return nil, fmt.Errorf("pcmData is empty")
 	}
 
-	// ffmpegPath is now passed directly
-	log.Debug("Using ffmpeg path", logger.String("path", ffmpegPath))
-
-	// --- Pass 1: Analyze Loudness ---
-	// Use the provided context for the analysis
-	log.Debug("Performing loudness analysis (Pass 1)")
-	loudnessStats, err := myaudio.AnalyzeAudioLoudnessWithContext(ctx, pcmData, ffmpegPath)
-	if err != nil {
-		// Check if the error is due to context cancellation
-		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
-			log.Warn("Loudness analysis cancelled or timed out", logger.Error(err))
-			return nil, err // Propagate context error
-		}
-
-		log.Warn("Loudness analysis (Pass 1) failed, falling back to fixed gain adjustment", logger.Error(err))
-		// Fallback to a conservative fixed gain adjustment
+	// --- Analyze loudness and calculate the gain needed to reach the target ---
+	inputLUFS, measured := myaudio.MeasureIntegratedLoudness(pcmData, conf.NumChannels)
+	if !measured {
+		// Too short or too quiet to measure, fall back to a conservative fixed gain
 		// A fixed gain of 15dB is a reasonable middle ground for bird call recordings
 		gainValue := 15.0
-		volumeArgs := fmt.Sprintf("volume=%.1fdB", gainValue)
-		customArgs := []string{
-			"-af", volumeArgs, // Simple gain adjustment
-			"-c:a", "flac",
-			"-f", "flac",
-		}
-
-		// Use the provided context for the fallback export operation
-		log.Debug("Starting fallback FLAC export with fixed gain", logger.Float64("gain_db", gainValue))
-		buffer, err := myaudio.ExportAudioWithCustomFFmpegArgsContext(ctx, pcmData, ffmpegPath, customArgs)
-		if err != nil {
-			log.Error("Fallback FLAC export with fixed gain failed",
-				logger.Float64("gain_db", gainValue),
-				logger.Error(err))
-			return nil, fmt.Errorf("fallback FLAC export with fixed gain failed: %w", err)
-		}
-		log.Info("Encoded PCM to FLAC using fixed gain (fallback)", logger.Float64("gain_db", gainValue))
-		return buffer, nil
+		log.Warn("Loudness analysis failed, falling back to fixed gain adjustment",
+			logger.Float64("gain_db", gainValue))
+		return myaudio.EncodeInterleavedPCMtoFLAC(myaudio.ApplyGain(pcmData, gainValue), conf.NumChannels)
 	}
-
-	log.Debug("Loudness analysis results",
-		logger.String("input_i", loudnessStats.InputI),
-		logger.String("input_lra", loudnessStats.InputLRA),
-		logger.String("input_tp", loudnessStats.InputTP),
-		logger.String("input_thresh", loudnessStats.InputThresh))
-
-	// --- Calculate gain needed to reach target loudness ---
-	inputLUFS := parseDouble(loudnessStats.InputI, -70.0)
 	gainNeeded := targetIntegratedLoudnessLUFS - inputLUFS
 
 	// Apply safety limits to prevent excessive amplification or attenuation
@@ -484,30 +443,16 @@ func encodeFlacUsingFFmpeg(ctx context.Context, pcmData []byte, ffmpegPath strin
 		logger.Float64("measured_lufs", inputLUFS),
 		logger.Bool("limited", gainLimited))
 
-	// --- Pass 2: Apply simple gain adjustment and encode ---
-	log.Debug("Applying gain adjustment and encoding to FLAC (Pass 2)", logger.Float64("gain_db", gainNeeded))
-
-	// Use simple volume filter instead of loudnorm
-	volumeArgs := fmt.Sprintf("volume=%.2fdB", gainNeeded)
-
-	customArgs := []string{
-		"-af", volumeArgs, // Simple gain adjustment filter
-		"-c:a", "flac", // Output codec: FLAC
-		"-f", "flac", // Output format: FLAC
-	}
-
-	// Use the provided context for the final encoding operation
-	buffer, err := myaudio.ExportAudioWithCustomFFmpegArgsContext(ctx, pcmData, ffmpegPath, customArgs)
+	// --- Apply gain adjustment and encode ---
+	buffer, err := myaudio.EncodeInterleavedPCMtoFLAC(myaudio.ApplyGain(pcmData, gainNeeded), conf.NumChannels)
 	if err != nil {
-		log.Error("FFmpeg FLAC encoding with gain adjustment failed",
+		log.Error("FLAC encoding with gain adjustment failed",
 			logger.Float64("gain_db", gainNeeded),
 			logger.Error(err))
-		return nil, fmt.Errorf("failed to export PCM to FLAC with gain adjustment: %w", err)
+		return nil, fmt.Errorf("failed to encode PCM to FLAC with gain adjustment: %w", err)
 	}
 
 	log.Info("Encoded PCM to FLAC with gain adjustment", logger.Float64("gain_db", gainNeeded))
-
-	// Return the buffer containing the FLAC data
 	return buffer, nil
 }
 
@@ -543,8 +488,8 @@ func (b *BwClient) UploadSoundscape(timestamp string, pcmData []byte) (soundscap
 			Build()
 	}
 
-	// Encode PCM data to audio format (FLAC with FFmpeg, or WAV fallback)
-	encodingResult, err := encodeAudioForUpload(b.Settings, pcmData, timestamp)
+	// Encode PCM data to FLAC
+	encodingResult, err := encodeAudioForUpload(pcmData, timestamp)
 	if err != nil {
 		return "", errors.New(err).
 			Component("birdweather").
@@ -910,36 +855,11 @@ type audioEncodingResult struct {
 	ext    string
 }
 
-// encodeAudioForUpload handles the PCM to FLAC encoding using FFmpeg
-// FFmpeg is required as BirdWeather only accepts FLAC format
-func encodeAudioForUpload(settings *conf.Settings, pcmData []byte, timestamp string) (*audioEncodingResult, error) {
+// encodeAudioForUpload encodes PCM to FLAC, the format BirdWeather accepts
+func encodeAudioForUpload(pcmData []byte, timestamp string) (*audioEncodingResult, error) {
 	log := GetLogger()
 
-	// Use the validated FFmpeg path from settings (validated at startup)
-	// This avoids redundant exec.LookPath calls on every upload
-	ffmpegPathForExec := settings.Realtime.Audio.FfmpegPath
-	ffmpegAvailable := ffmpegPathForExec != ""
-	log.Debug("Checking FFmpeg availability",
-		logger.String("path", ffmpegPathForExec),
-		logger.Bool("available", ffmpegAvailable))
-
-	if !ffmpegAvailable {
-		log.Error("FFmpeg not available, cannot encode to FLAC for BirdWeather",
-			logger.String("timestamp", timestamp))
-		return nil, fmt.Errorf("FFmpeg is required for BirdWeather uploads (FLAC encoding)")
-	}
-
-	return encodeWithFFmpeg(settings, pcmData, ffmpegPathForExec, timestamp)
-}
-
-// encodeWithFFmpeg encodes PCM to FLAC format using FFmpeg
-func encodeWithFFmpeg(settings *conf.Settings, pcmData []byte, ffmpegPath, timestamp string) (*audioEncodingResult, error) {
-	log := GetLogger()
-
-	ctx, cancel := context.WithTimeout(context.Background(), encodingTimeout)
-	defer cancel()
-
-	audioBuffer, err := encodeFlacUsingFFmpeg(ctx, pcmData, ffmpegPath, settings)
+	audioBuffer, err := encodeFlac(pcmData)
 	if err != nil {
 		log.Error("FLAC encoding failed",
 			logger.String("timestamp", timestamp),
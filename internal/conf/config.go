@@ -87,6 +87,59 @@ type SoundLevelSettings struct {
 	DebugRealtimeLogging bool `yaml:"debug_realtime_logging" mapstructure:"debug_realtime_logging" json:"debugRealtimeLogging"` // true to log debug messages for every realtime update, false to log only at configured interval
 }
 
+// MicHealthSettings contains thresholds for microphone health diagnostics
+type MicHealthSettings struct {
+	Enabled          bool    `yaml:"enabled" mapstructure:"enabled" json:"enabled"`                            // true to analyze the input health of every audio source
+	SilenceThreshold float64 `yaml:"silencethreshold" mapstructure:"silencethreshold" json:"silenceThreshold"` // peak level in dBFS below which input is digital silence (default: -84)
+	ClippingRatio    float64 `yaml:"clippingratio" mapstructure:"clippingratio" json:"clippingRatio"`          // fraction of full-scale samples that counts as clipping (default: 0.001)
+	DCOffset         float64 `yaml:"dcoffset" mapstructure:"dcoffset" json:"dcOffset"`                         // mean sample value, relative to full scale, that counts as DC offset (default: 0.05)
+	HumRatio         float64 `yaml:"humratio" mapstructure:"humratio" json:"humRatio"`                         // fraction of signal energy at mains frequencies that counts as hum (default: 0.5)
+	MinRollOff       float64 `yaml:"minrolloff" mapstructure:"minrolloff" json:"minRollOff"`                   // spectral roll-off in Hz below which the capsule is considered degraded, 0 disables (default: 1000)
+	MaxImbalance     float64 `yaml:"maximbalance" mapstructure:"maximbalance" json:"maxImbalance"`             // level difference between channels in dB that counts as imbalance (default: 6)
+}
+
+// NoiseReductionSettings contains settings for stationary noise reduction by spectral gating
+type NoiseReductionSettings struct {
+	Enabled   bool    `yaml:"enabled" mapstructure:"enabled" json:"enabled"`       // true to reduce stationary noise before analysis
+	Strength  float64 `yaml:"strength" mapstructure:"strength" json:"strength"`    // share of gated noise removed, 0.0-1.0 (default: 0.75)
+	Threshold float64 `yaml:"threshold" mapstructure:"threshold" json:"threshold"` // level in dB above the noise profile that passes the gate (default: 6)
+	Clips     bool    `yaml:"clips" mapstructure:"clips" json:"clips"`             // true to also reduce noise in exported audio clips
+}
+
+// ArchiveSettings contains settings for the continuous audio archive, which
+// records every capture source into fixed-length FLAC segments for later re-analysis
+type ArchiveSettings struct {
+	Enabled       bool   `yaml:"enabled" mapstructure:"enabled" json:"enabled"`                   // true to continuously record every audio source
+	Path          string `yaml:"path" mapstructure:"path" json:"path"`                            // path to the archive directory, separate from clip exports
+	SegmentLength int    `yaml:"segmentlength" mapstructure:"segmentlength" json:"segmentLength"` // segment length in seconds (default: 300)
+	MaxAge        string `yaml:"maxage" mapstructure:"maxage" json:"maxAge"`                      // segments older than this are deleted, e.g. "7d", empty to keep until the quota is reached
+	MaxSize       string `yaml:"maxsize" mapstructure:"maxsize" json:"maxSize"`                   // total archive size quota, e.g. "50GB", oldest segments are deleted first
+	CheckInterval int    `yaml:"checkinterval" mapstructure:"checkinterval" json:"checkInterval"` // retention check interval in minutes (default: 15)
+}
+
+// Channel modes of capture sources
+const (
+	ChannelModeMix    = "mix"    // capture one downmixed channel (default)
+	ChannelModeSplit  = "split"  // analyze every channel as a virtual sub-source
+	ChannelModeSelect = "select" // analyze a single channel
+)
+
+// MaxCaptureChannels is the largest number of channels captured from one source
+const MaxCaptureChannels = 8
+
+// ChannelSettings contains the channel layout of a capture source
+type ChannelSettings struct {
+	Mode    string   `yaml:"mode,omitempty" mapstructure:"mode" json:"mode,omitempty"`          // "mix", "split" or "select", empty means mix
+	Count   int      `yaml:"count,omitempty" mapstructure:"count" json:"count,omitempty"`       // number of channels captured in split and select modes
+	Channel int      `yaml:"channel,omitempty" mapstructure:"channel" json:"channel,omitempty"` // channel analyzed in select mode, starting from 1
+	Names   []string `yaml:"names,omitempty" mapstructure:"names" json:"names,omitempty"`       // display names of split channels, in channel order
+}
+
+// IsMultichannel returns true if the source is captured with all of its channels.
+func (c *ChannelSettings) IsMultichannel() bool {
+	return c.Mode == ChannelModeSplit || c.Mode == ChannelModeSelect
+}
+
 type AudioSettings struct {
 	Source          string             `yaml:"source" mapstructure:"source" json:"source"`             // audio source to use for analysis
 	FfmpegPath      string             `yaml:"ffmpegpath" mapstructure:"ffmpegpath" json:"ffmpegPath"` // path to ffmpeg, runtime value
@@ -98,8 +151,13 @@ type AudioSettings struct {
 	StreamTransport string             `json:"streamTransport"`                                        // preferred transport for audio streaming: "auto", "sse", or "ws"
 	Export          ExportSettings     `json:"export"`                                                 // export settings
 	SoundLevel      SoundLevelSettings `json:"soundLevel"`                                             // sound level monitoring settings
+	MicHealth       MicHealthSettings  `json:"micHealth"`                                              // microphone health diagnostics settings
+	Archive         ArchiveSettings    `json:"archive"`                                                // continuous audio archive settings
 
-	Equalizer EqualizerSettings `json:"equalizer"` // equalizer settings
+	NoiseReduction  NoiseReductionSettings `json:"noiseReduction"`                                                                            // stationary noise reduction settings
+	Equalizer       EqualizerSettings      `json:"equalizer"`                                                                                 // equalizer settings
+	SourceEqualizer *EqualizerSettings     `yaml:"sourceequalizer,omitempty" mapstructure:"sourceequalizer" json:"sourceEqualizer,omitempty"` // sound card equalizer override, nil inherits the global equalizer
+	Channels        ChannelSettings        `yaml:"channels,omitempty" mapstructure:"channels" json:"channels"`                                // channel layout of the sound card
 }
 
 // NeedsFfprobeWorkaround returns true if the current FFmpeg version requires
@@ -111,6 +169,79 @@ func (a *AudioSettings) NeedsFfprobeWorkaround() bool {
 	return a.FfmpegMajor == 5
 }
 
+// EqualizerFor returns the equalizer settings for the audio source identified
+// by its connection string: the stream URL or the sound card source. Sources
+// without an override use the global equalizer settings.
+func (s *Settings) EqualizerFor(connection string) EqualizerSettings {
+	if override := s.EqualizerOverride(connection); override != nil {
+		return *override
+	}
+	return s.Realtime.Audio.Equalizer
+}
+
+// EqualizerOverride returns the source specific equalizer settings for a
+// connection string, or nil when the source inherits the global equalizer.
+func (s *Settings) EqualizerOverride(connection string) *EqualizerSettings {
+	if connection == "" {
+		return nil
+	}
+	for i := range s.Realtime.RTSP.Streams {
+		stream := &s.Realtime.RTSP.Streams[i]
+		if stream.URL == connection && stream.Equalizer != nil {
+			return stream.Equalizer
+		}
+	}
+	if connection == s.Realtime.Audio.Source {
+		return s.Realtime.Audio.SourceEqualizer
+	}
+	return nil
+}
+
+// ChannelsFor returns the channel layout of the audio source identified by
+// its connection string. Unknown sources are captured downmixed to mono.
+func (s *Settings) ChannelsFor(connection string) ChannelSettings {
+	if connection == "" {
+		return ChannelSettings{}
+	}
+	for i := range s.Realtime.RTSP.Streams {
+		if s.Realtime.RTSP.Streams[i].URL == connection {
+			return s.Realtime.RTSP.Streams[i].Channels
+		}
+	}
+	if connection == s.Realtime.Audio.Source {
+		return s.Realtime.Audio.Channels
+	}
+	return ChannelSettings{}
+}
+
+// BackupURLsFor returns the backup URLs configured for a stream URL.
+func (s *Settings) BackupURLsFor(connection string) []string {
+	if connection == "" {
+		return nil
+	}
+	for i := range s.Realtime.RTSP.Streams {
+		if s.Realtime.RTSP.Streams[i].URL == connection {
+			return s.Realtime.RTSP.Streams[i].BackupURLs
+		}
+	}
+	return nil
+}
+
+// IngestDeviceByID returns the push ingest device with the given ID.
+func (s *Settings) IngestDeviceByID(id string) (IngestDevice, bool) {
+	for i := range s.Realtime.Ingest.Devices {
+		if s.Realtime.Ingest.Devices[i].ID == id {
+			return s.Realtime.Ingest.Devices[i], true
+		}
+	}
+	return IngestDevice{}, false
+}
+
+// HasIngestDevices reports whether push ingest is enabled with at least one device.
+func (s *Settings) HasIngestDevices() bool {
+	return s.Realtime.Ingest.Enabled && len(s.Realtime.Ingest.Devices) > 0
+}
+
 // HasFfmpegVersion returns true if FFmpeg version information has been detected and populated.
 func (a *AudioSettings) HasFfmpegVersion() bool {
 	return a.FfmpegVersion != "" && a.FfmpegMajor > 0
@@ -271,11 +402,13 @@ type WeatherSettings struct {
 type NotificationConfig struct {
 	Push      PushSettings          `json:"push" yaml:"push"`
 	Templates NotificationTemplates `json:"templates" yaml:"templates"`
+	Digest    DigestSettings        `json:"digest" yaml:"digest"`
 }
 
 // NotificationTemplates contains customizable notification message templates.
 type NotificationTemplates struct {
 	NewSpecies NewSpeciesTemplate `json:"newSpecies" yaml:"newspecies"`
+	Digest     DigestTemplate     `json:"digest" yaml:"digest"`
 }
 
 // NewSpeciesTemplate contains templates for new species detection notifications.
@@ -284,6 +417,29 @@ type NewSpeciesTemplate struct {
 	Message string `json:"message" yaml:"message"`
 }
 
+// DigestTemplate contains templates for scheduled digest notifications.
+// An empty message selects the built-in template for the configured digest format.
+type DigestTemplate struct {
+	Title   string `json:"title" yaml:"title"`
+	Message string `json:"message" yaml:"message"`
+}
+
+// DigestSettings configures scheduled daily and weekly summary notifications
+// delivered through the push providers.
+type DigestSettings struct {
+	Daily      DigestScheduleSettings `json:"daily" yaml:"daily"`
+	Weekly     DigestScheduleSettings `json:"weekly" yaml:"weekly"`
+	TopSpecies int                    `json:"topSpecies" yaml:"top_species" mapstructure:"top_species"` // number of most detected species listed
+	Format     string                 `json:"format" yaml:"format"`                                     // message format: "text", "markdown" or "html"
+}
+
+// DigestScheduleSettings controls when a digest is delivered.
+type DigestScheduleSettings struct {
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	Time    string `json:"time" yaml:"time"`                           // local delivery time in 24-hour HH:MM format
+	Weekday string `json:"weekday,omitempty" yaml:"weekday,omitempty"` // delivery day for weekly digests, e.g. "sunday"
+}
+
 // PushSettings controls global push delivery and provider list.
 type PushSettings struct {
 	Enabled        bool                 `json:"enabled"`
@@ -327,6 +483,10 @@ type PushProviderConfig struct {
 	Enabled bool             `json:"enabled"`
 	Name    string           `json:"name"`
 	Filter  PushFilterConfig `json:"filter"`
+	// QuietHours withholds notifications from this provider during a daily window
+	QuietHours QuietHoursConfig `json:"quiet_hours" mapstructure:"quiet_hours"`
+	// Attachments adds the detection spectrogram and audio clip where the service supports files
+	Attachments PushAttachmentConfig `json:"attachments" mapstructure:"attachments"`
 	// Shoutrrr-specific
 	URLs    []string `json:"urls"`
 	Timeout Duration `json:"timeout" mapstructure:"timeout"`
@@ -383,6 +543,28 @@ type PushFilterConfig struct {
 	MetadataFilters map[string]any `json:"metadata_filters" mapstructure:"metadata_filters"`
 }
 
+// PushAttachmentConfig controls file attachments on detection notifications.
+// Attachments are sent to ntfy, Telegram, Pushover and Discord shoutrrr URLs and
+// to webhooks as multipart/form-data; other services, oversized files and
+// missing clips fall back to the regular text message with links.
+type PushAttachmentConfig struct {
+	Spectrogram bool `json:"spectrogram"`                            // attach the spectrogram PNG
+	AudioClip   bool `json:"audio_clip" mapstructure:"audio_clip"`   // attach a short compressed audio clip
+	MaxSizeKB   int  `json:"max_size_kb" mapstructure:"max_size_kb"` // per-file size limit, 0 uses the default
+}
+
+// QuietHoursConfig defines a daily window during which a push provider stays silent.
+// Start and End accept a 24-hour clock time ("22:30") or a sun event ("sunrise",
+// "sunset", "dawn", "dusk") with an optional offset ("sunset+30m", "sunrise-1h").
+// A window whose end is before its start spans midnight.
+type QuietHoursConfig struct {
+	Enabled        bool   `json:"enabled"`
+	Start          string `json:"start"`
+	End            string `json:"end"`
+	Action         string `json:"action"`                                         // "drop", "delay" or "rollup" (default)
+	ExemptCritical bool   `json:"exempt_critical" mapstructure:"exempt_critical"` // deliver critical notifications during quiet hours
+}
+
 // WundergroundSettings contains settings for WeatherUnderground integration.
 type WundergroundSettings struct {
 	APIKey    string `json:"apiKey"`    // WeatherUnderground API key
@@ -437,6 +619,17 @@ type StreamConfig struct {
 	URL       string `yaml:"url" json:"url" mapstructure:"url"`                   // Required: stream URL
 	Type      string `yaml:"type" json:"type" mapstructure:"type"`                // Stream type: rtsp, http, hls, rtmp, udp
 	Transport string `yaml:"transport" json:"transport" mapstructure:"transport"` // Transport: tcp or udp (for RTSP/RTMP)
+
+	// BackupURLs are alternative URLs of the same audio tried in order when the
+	// primary URL fails, e.g. a camera sub-stream or a relay. Detections keep
+	// the source ID of the primary URL.
+	BackupURLs []string `yaml:"backupUrls,omitempty" json:"backupUrls,omitempty" mapstructure:"backupUrls"`
+
+	// Equalizer overrides the global equalizer for this stream, nil inherits it
+	Equalizer *EqualizerSettings `yaml:"equalizer,omitempty" json:"equalizer,omitempty" mapstructure:"equalizer"`
+
+	// Channels selects how the channels of a multichannel stream are analyzed
+	Channels ChannelSettings `yaml:"channels,omitempty" json:"channels,omitzero" mapstructure:"channels"`
 }
 
 // RTSPSettings contains settings for audio streaming (supports multiple protocols).
@@ -449,6 +642,21 @@ type RTSPSettings struct {
 	FFmpegParameters []string           `yaml:"ffmpegParameters" json:"ffmpegParameters" mapstructure:"ffmpegParameters"` // Custom FFmpeg parameters
 }
 
+// IngestSettings contains settings for HTTP push ingest, where remote recorders
+// that cannot serve a stream, e.g. behind cellular NAT, POST their audio instead
+type IngestSettings struct {
+	Enabled bool           `yaml:"enabled" mapstructure:"enabled" json:"enabled"` // true to accept pushed audio
+	Latency int            `yaml:"latency" mapstructure:"latency" json:"latency"` // seconds pushed audio is buffered before analysis, must cover the device upload interval (default: 10)
+	Devices []IngestDevice `yaml:"devices" mapstructure:"devices" json:"devices"` // registered remote recorders
+}
+
+// IngestDevice is a remote recorder allowed to push audio
+type IngestDevice struct {
+	ID    string `yaml:"id" mapstructure:"id" json:"id"`          // device identifier used in the ingest URL
+	Name  string `yaml:"name" mapstructure:"name" json:"name"`    // display name of the audio source, defaults to the ID
+	Token string `yaml:"token" mapstructure:"token" json:"token"` // bearer token the device authenticates with
+}
+
 // CRITICAL: Legacy fields (URLs, Transport) MUST include json tags to accept
 // payloads from older frontends. Without this, saving from a cached old frontend
 // would wipe the user's stream configuration. The migration runs on Load().
@@ -556,6 +764,7 @@ type RealtimeSettings struct {
 	PrivacyFilter    PrivacyFilterSettings    `json:"privacyFilter"`    // Privacy filter settings
 	DogBarkFilter    DogBarkFilterSettings    `json:"dogBarkFilter"`    // Dog bark filter settings
 	RTSP             RTSPSettings             `json:"rtsp"`             // RTSP settings
+	Ingest           IngestSettings           `json:"ingest"`           // HTTP push ingest settings
 	MQTT             MQTTSettings             `json:"mqtt"`             // MQTT settings
 	Telemetry        TelemetrySettings        `json:"telemetry"`        // Telemetry settings
 	Monitoring       MonitoringSettings       `json:"monitoring"`       // System resource monitoring settings
@@ -925,6 +1134,47 @@ type BirdNETConfig struct {
 	LabelPath   string              `json:"labelPath,omitempty" yaml:"labelPath,omitempty"` // path to external label file (empty for embedded)
 	Labels      []string            `yaml:"-" json:"-"`                                     // list of available species labels, runtime value
 	UseXNNPACK  bool                `json:"useXnnpack"`                                     // true to use XNNPACK delegate for inference acceleration
+	BatchSize   int                 `json:"batchSize"`                                      // max chunks of different sources per model invocation, 0 or 1 to disable batching
+
+	AdaptiveOverlap AdaptiveOverlapSettings `json:"adaptiveOverlap"` // lowers the overlap of a source while inference cannot keep up
+	Remote          RemoteInferenceSettings `json:"remote"`          // inference workers analyzing the chunks of realtime mode
+	Worker          InferenceWorkerSettings `json:"worker"`          // inference API served by the worker command
+}
+
+// MaxBirdNETBatchSize bounds the chunks analyzed per model invocation
+const MaxBirdNETBatchSize = 32
+
+// AdaptiveOverlapSettings controls how the analysis degrades under CPU pressure.
+// Instead of dropping audio when inference gets slower than real time, the
+// overlap of the affected source is lowered step by step down to MinOverlap
+// and restored once the load drops.
+type AdaptiveOverlapSettings struct {
+	Enabled    bool    `json:"enabled"`    // true to lower the overlap when inference cannot keep up
+	MinOverlap float64 `json:"minOverlap"` // lowest overlap in seconds to fall back to
+}
+
+// RemoteInferenceSettings lets realtime mode send its analysis chunks to
+// inference workers, for example capture sites sharing one strong server on
+// the LAN. Workers are tried in turn, a worker that fails is skipped for a
+// while, and with Fallback the local model analyzes chunks no worker answered.
+type RemoteInferenceSettings struct {
+	Workers  []RemoteWorker `yaml:"workers" mapstructure:"workers" json:"workers"`    // workers to send chunks to, empty to analyze locally
+	Timeout  Duration       `yaml:"timeout" mapstructure:"timeout" json:"timeout"`    // how long to wait for a worker to analyze a chunk (default: 5s)
+	Fallback bool           `yaml:"fallback" mapstructure:"fallback" json:"fallback"` // true to analyze locally when no worker answers
+}
+
+// RemoteWorker is an inference worker realtime mode sends chunks to
+type RemoteWorker struct {
+	URL   string `yaml:"url" mapstructure:"url" json:"url"`       // base URL of the worker, e.g. http://server:8090
+	Token string `yaml:"token" mapstructure:"token" json:"token"` // bearer token the worker requires
+}
+
+// InferenceWorkerSettings configures the inference API of the worker command
+type InferenceWorkerSettings struct {
+	Listen   string `json:"listen"`   // address and port the worker listens on
+	Token    string `json:"token"`    // bearer token clients authenticate with
+	CertFile string `json:"certFile"` // TLS certificate, empty to serve plain HTTP
+	KeyFile  string `json:"keyFile"`  // TLS private key
 }
 
 // RangeFilterSettings contains settings for the range filter
@@ -962,6 +1212,21 @@ type AllowSubnetBypass struct {
 	Subnet  string `json:"subnet"`  // disable OAuth2 in subnet
 }
 
+// TrustedProxyAuth holds settings for delegating authentication to a reverse proxy
+// running a forward-auth service such as Authelia, Authentik or oauth2-proxy.
+// The identity header is only honoured on connections whose peer address is in
+// TrustedProxies; the header is ignored (and logged) from any other source.
+type TrustedProxyAuth struct {
+	Enabled        bool   `json:"enabled"`        // true to accept identities asserted by trusted proxies
+	TrustedProxies string `json:"trustedProxies"` // comma-separated CIDR ranges of trusted proxies
+	UserHeader     string `json:"userHeader"`     // header carrying the authenticated user (e.g. Remote-User, X-Forwarded-Email)
+	GroupsHeader   string `json:"groupsHeader"`   // header carrying comma-separated groups (e.g. Remote-Groups), empty to ignore groups
+	AllowedUsers   string `json:"allowedUsers"`   // optional comma-separated list of accepted users, empty accepts any user
+	AdminGroups    string `json:"adminGroups"`    // comma-separated groups granted full access
+	ViewerGroups   string `json:"viewerGroups"`   // comma-separated groups granted read-only access
+	RequireProxy   bool   `json:"requireProxy"`   // true to reject protected requests that do not arrive via a trusted proxy
+}
+
 // OAuthProviderConfig holds settings for a single OAuth2 provider in the new array-based format.
 // This replaces the individual GoogleAuth, GithubAuth, MicrosoftAuth fields.
 type OAuthProviderConfig struct {
@@ -1000,6 +1265,7 @@ type Security struct {
 
 	RedirectToHTTPS   bool              `json:"redirectToHttps"`   // true to redirect to HTTPS
 	AllowSubnetBypass AllowSubnetBypass `json:"allowSubnetBypass"` // subnet bypass configuration
+	TrustedProxyAuth  TrustedProxyAuth  `json:"trustedProxyAuth"`  // reverse proxy forward-auth configuration
 	BasicAuth         BasicAuth         `json:"basicAuth"`         // password authentication configuration
 
 	// OAuthProviders is the new array-based OAuth configuration.
@@ -1023,6 +1289,7 @@ type WebServerSettings struct {
 	Port           string             `json:"port"`           // port for web server
 	BasePath       string             `json:"basePath"`       // reverse proxy subpath prefix (e.g., "/birdnet")
 	LiveStream     LiveStreamSettings `json:"liveStream"`     // live stream configuration
+	Icecast        IcecastSettings    `json:"icecast"`        // Icecast-compatible stream output
 	EnableTerminal bool               `json:"enableTerminal"` // Enable browser terminal (security risk)
 }
 
@@ -1034,6 +1301,25 @@ type LiveStreamSettings struct {
 	FfmpegLogLevel string `json:"ffmpegLogLevel"` // log level for ffmpeg
 }
 
+// IcecastSettings exposes sources as Icecast/SHOUTcast-compatible HTTP audio
+// streams at /stream/:source for media players.
+type IcecastSettings struct {
+	Enabled        bool     `json:"enabled"`        // true to serve sources at /stream/:source
+	Format         string   `json:"format"`         // mp3 or opus
+	BitRate        int      `json:"bitRate"`        // bitrate in kbps
+	MaxListeners   int      `json:"maxListeners"`   // concurrent listeners per source
+	NoiseReduction bool     `json:"noiseReduction"` // true to apply noise reduction to the streamed audio
+	Sources        []string `json:"sources"`        // source IDs or names to expose, empty for all
+	Username       string   `json:"username"`       // HTTP basic auth username for players, empty to require a web login
+	Password       string   `json:"password"`       // HTTP basic auth password for players
+}
+
+// Icecast stream formats
+const (
+	IcecastFormatMP3  = "mp3"
+	IcecastFormatOpus = "opus"
+)
+
 // BackupRetention defines backup retention policy
 type BackupRetention struct {
 	MaxAge     string `yaml:"maxage" json:"maxAge"`         // Duration string for the maximum age of backups to keep (e.g., "30d" for 30 days, "6m" for 6 months, "1y" for 1 year). Backups older than this may be deleted.
@@ -1251,6 +1537,45 @@ type Settings struct {
 	Notification NotificationConfig `json:"notification"` // Configuration for push notifications
 
 	Alerting AlertSettings `json:"alerting"` // Alerting rules engine settings
+
+	Hub HubSettings `json:"hub"` // Multi-station hub configuration
+}
+
+// HubSettings configures aggregating the detections of several BirdNET-Go
+// stations in one place. A hub accepts detections pushed by its registered
+// stations, a station pushes its own detections to the hub in Upstream.
+type HubSettings struct {
+	Enabled  bool                `yaml:"enabled" mapstructure:"enabled" json:"enabled"`    // true to accept detections pushed by stations
+	Stations []HubStation        `yaml:"stations" mapstructure:"stations" json:"stations"` // stations allowed to push detections
+	Upstream HubUpstreamSettings `yaml:"upstream" mapstructure:"upstream" json:"upstream"` // hub this station pushes its detections to
+}
+
+// HubStation is a station allowed to push its detections to the hub
+type HubStation struct {
+	Name  string `yaml:"name" mapstructure:"name" json:"name"`    // station name, detections of the station are stored with it as node name
+	Token string `yaml:"token" mapstructure:"token" json:"token"` // bearer token the station authenticates with
+	URL   string `yaml:"url" mapstructure:"url" json:"url"`       // optional web address of the station, for clips it does not upload
+}
+
+// HubUpstreamSettings configures pushing the detections of this station to a hub
+type HubUpstreamSettings struct {
+	Enabled     bool     `yaml:"enabled" mapstructure:"enabled" json:"enabled"`             // true to push detections to the hub
+	URL         string   `yaml:"url" mapstructure:"url" json:"url"`                         // base URL of the hub, e.g. https://hub.example.org
+	Station     string   `yaml:"station" mapstructure:"station" json:"station"`             // name the hub registered this station under
+	Token       string   `yaml:"token" mapstructure:"token" json:"token"`                   // bearer token of the station
+	Interval    Duration `yaml:"interval" mapstructure:"interval" json:"interval"`          // time between syncs (default: 30s)
+	BatchSize   int      `yaml:"batchsize" mapstructure:"batchsize" json:"batchSize"`       // detections pushed per request (default: 100)
+	UploadClips bool     `yaml:"uploadclips" mapstructure:"uploadclips" json:"uploadClips"` // true to upload audio clips, false to send references only
+}
+
+// StationByName returns the hub station with the given name.
+func (s *HubSettings) StationByName(name string) (HubStation, bool) {
+	for _, station := range s.Stations {
+		if station.Name == name {
+			return station, true
+		}
+	}
+	return HubStation{}, false
 }
 
 // AlertSettings configures the alerting rules engine.
@@ -128,3 +128,48 @@ func TestAudioSettings_HasFfmpegVersion(t *testing.T) {
 		})
 	}
 }
+
+func TestSettings_EqualizerFor(t *testing.T) {
+	t.Parallel()
+
+	global := EqualizerSettings{
+		Enabled: true,
+		Filters: []EqualizerFilter{{Type: "LowPass", Frequency: 15000, Q: 0.707, Passes: 1}},
+	}
+	roadside := &EqualizerSettings{
+		Enabled: true,
+		Filters: []EqualizerFilter{{Type: "HighPass", Frequency: 300, Q: 0.707, Passes: 4}},
+	}
+	garden := &EqualizerSettings{Enabled: false}
+
+	settings := &Settings{}
+	settings.Realtime.Audio.Source = "sysdefault"
+	settings.Realtime.Audio.Equalizer = global
+	settings.Realtime.Audio.SourceEqualizer = garden
+	settings.Realtime.RTSP.Streams = []StreamConfig{
+		{Name: "Roadside", URL: "rtsp://192.168.1.10/stream", Equalizer: roadside},
+		{Name: "Backyard", URL: "rtsp://192.168.1.20/stream"},
+	}
+
+	tests := []struct {
+		name         string
+		connection   string
+		want         EqualizerSettings
+		wantOverride bool
+	}{
+		{name: "stream override", connection: "rtsp://192.168.1.10/stream", want: *roadside, wantOverride: true},
+		{name: "stream inherits global", connection: "rtsp://192.168.1.20/stream", want: global},
+		{name: "sound card override", connection: "sysdefault", want: *garden, wantOverride: true},
+		{name: "unknown source", connection: "rtsp://192.168.1.30/stream", want: global},
+		{name: "empty connection", connection: "", want: global},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tt.want, settings.EqualizerFor(tt.connection))
+			assert.Equal(t, tt.wantOverride, settings.EqualizerOverride(tt.connection) != nil)
+		})
+	}
+}
@@ -72,6 +72,18 @@ func setDefaultConfig() {
 	viper.SetDefault("birdnet.modelpath", "")
 	viper.SetDefault("birdnet.labelpath", "")
 	viper.SetDefault("birdnet.usexnnpack", true)
+	viper.SetDefault("birdnet.batchsize", 1)
+	viper.SetDefault("birdnet.adaptiveoverlap.enabled", true)
+	viper.SetDefault("birdnet.adaptiveoverlap.minoverlap", 0.0)
+
+	// Remote inference configuration
+	viper.SetDefault("birdnet.remote.workers", []RemoteWorker{})
+	viper.SetDefault("birdnet.remote.timeout", "5s")
+	viper.SetDefault("birdnet.remote.fallback", true)
+	viper.SetDefault("birdnet.worker.listen", ":8090")
+	viper.SetDefault("birdnet.worker.token", "")
+	viper.SetDefault("birdnet.worker.certfile", "")
+	viper.SetDefault("birdnet.worker.keyfile", "")
 
 	// Range filter configuration
 	viper.SetDefault("birdnet.rangefilter.debug", false)
@@ -90,6 +102,29 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.audio.soundlevel.enabled", false)
 	viper.SetDefault("realtime.audio.soundlevel.interval", 10)
 
+	// Microphone health diagnostics configuration
+	viper.SetDefault("realtime.audio.michealth.enabled", true)
+	viper.SetDefault("realtime.audio.michealth.silencethreshold", -84.0)
+	viper.SetDefault("realtime.audio.michealth.clippingratio", 0.001)
+	viper.SetDefault("realtime.audio.michealth.dcoffset", 0.05)
+	viper.SetDefault("realtime.audio.michealth.humratio", 0.5)
+	viper.SetDefault("realtime.audio.michealth.minrolloff", 1000.0)
+	viper.SetDefault("realtime.audio.michealth.maximbalance", 6.0)
+
+	// Stationary noise reduction configuration
+	viper.SetDefault("realtime.audio.noisereduction.enabled", false)
+	viper.SetDefault("realtime.audio.noisereduction.strength", 0.75)
+	viper.SetDefault("realtime.audio.noisereduction.threshold", 6.0)
+	viper.SetDefault("realtime.audio.noisereduction.clips", false)
+
+	// Continuous audio archive configuration
+	viper.SetDefault("realtime.audio.archive.enabled", false)
+	viper.SetDefault("realtime.audio.archive.path", "archive/")
+	viper.SetDefault("realtime.audio.archive.segmentlength", 300)
+	viper.SetDefault("realtime.audio.archive.maxage", "7d")
+	viper.SetDefault("realtime.audio.archive.maxsize", "20GB")
+	viper.SetDefault("realtime.audio.archive.checkinterval", 15)
+
 	// Audio capture configuration
 	viper.SetDefault("realtime.audio.export.debug", false)
 	viper.SetDefault("realtime.audio.export.enabled", true)
@@ -225,6 +260,11 @@ func setDefaultConfig() {
 	viper.SetDefault("realtime.rtsp.health.monitoringinterval", 30)
 	viper.SetDefault("realtime.rtsp.ffmpegparameters", []string{})
 
+	// HTTP push ingest configuration
+	viper.SetDefault("realtime.ingest.enabled", false)
+	viper.SetDefault("realtime.ingest.latency", 10)
+	viper.SetDefault("realtime.ingest.devices", []IngestDevice{})
+
 	// MQTT configuration
 	viper.SetDefault("realtime.mqtt.enabled", false)
 	viper.SetDefault("realtime.mqtt.debug", false)
@@ -317,6 +357,16 @@ func setDefaultConfig() {
 	viper.SetDefault("webserver.livestream.segmentLength", 2)
 	viper.SetDefault("webserver.livestream.ffmpegLogLevel", "warning")
 
+	// Icecast stream output configuration
+	viper.SetDefault("webserver.icecast.enabled", false)
+	viper.SetDefault("webserver.icecast.format", "mp3")
+	viper.SetDefault("webserver.icecast.bitrate", 128)
+	viper.SetDefault("webserver.icecast.maxlisteners", 5)
+	viper.SetDefault("webserver.icecast.noisereduction", false)
+	viper.SetDefault("webserver.icecast.sources", []string{})
+	viper.SetDefault("webserver.icecast.username", "")
+	viper.SetDefault("webserver.icecast.password", "")
+
 	// File output configuration
 	viper.SetDefault("output.file.enabled", true)
 	viper.SetDefault("output.file.path", "output/")
@@ -344,6 +394,16 @@ func setDefaultConfig() {
 	viper.SetDefault("security.allowsubnetbypass.subnet", "")
 	viper.SetDefault("security.sessionduration", "168h") // 7 days
 
+	// Trusted reverse proxy (forward-auth) configuration
+	viper.SetDefault("security.trustedproxyauth.enabled", false)
+	viper.SetDefault("security.trustedproxyauth.trustedproxies", "")
+	viper.SetDefault("security.trustedproxyauth.userheader", "Remote-User")
+	viper.SetDefault("security.trustedproxyauth.groupsheader", "Remote-Groups")
+	viper.SetDefault("security.trustedproxyauth.allowedusers", "")
+	viper.SetDefault("security.trustedproxyauth.admingroups", "")
+	viper.SetDefault("security.trustedproxyauth.viewergroups", "")
+	viper.SetDefault("security.trustedproxyauth.requireproxy", false)
+
 	// Basic authentication configuration
 	viper.SetDefault("security.basicauth.enabled", false)
 	viper.SetDefault("security.basicauth.password", "")
@@ -399,9 +459,31 @@ func setDefaultConfig() {
 	// Notification templates
 	viper.SetDefault("notification.templates.newspecies.title", "New Species: {{.CommonName}}")
 	viper.SetDefault("notification.templates.newspecies.message", "First detection of {{.CommonName}} ({{.ScientificName}}) with {{.ConfidencePercent}}% confidence at {{.DetectionTime}}. {{.DetectionURL}}")
+	viper.SetDefault("notification.templates.digest.title", "{{.PeriodLabel}} bird digest: {{.TotalDetections}} detections")
+	viper.SetDefault("notification.templates.digest.message", "")
+
+	// Scheduled digest notifications
+	viper.SetDefault("notification.digest.daily.enabled", false)
+	viper.SetDefault("notification.digest.daily.time", "21:00")
+	viper.SetDefault("notification.digest.weekly.enabled", false)
+	viper.SetDefault("notification.digest.weekly.time", "21:00")
+	viper.SetDefault("notification.digest.weekly.weekday", "sunday")
+	viper.SetDefault("notification.digest.top_species", 5)
+	viper.SetDefault("notification.digest.format", "text")
 
 	// Alerting rules engine
 	viper.SetDefault("alerting.history_retention_days", 30)
+
+	// Multi-station hub configuration
+	viper.SetDefault("hub.enabled", false)
+	viper.SetDefault("hub.stations", []HubStation{})
+	viper.SetDefault("hub.upstream.enabled", false)
+	viper.SetDefault("hub.upstream.url", "")
+	viper.SetDefault("hub.upstream.station", "")
+	viper.SetDefault("hub.upstream.token", "")
+	viper.SetDefault("hub.upstream.interval", "30s")
+	viper.SetDefault("hub.upstream.batchsize", 100)
+	viper.SetDefault("hub.upstream.uploadclips", true)
 }
 
 // setModuleLogDefaults sets default values for a module log configuration
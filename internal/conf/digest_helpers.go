@@ -0,0 +1,60 @@
+package conf
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Digest message formats.
+const (
+	DigestFormatText     = "text"
+	DigestFormatMarkdown = "markdown"
+	DigestFormatHTML     = "html"
+)
+
+// digestWeekdays maps lowercase weekday names and abbreviations to time.Weekday.
+var digestWeekdays = map[string]time.Weekday{
+	"sunday": time.Sunday, "sun": time.Sunday,
+	"monday": time.Monday, "mon": time.Monday,
+	"tuesday": time.Tuesday, "tue": time.Tuesday,
+	"wednesday": time.Wednesday, "wed": time.Wednesday,
+	"thursday": time.Thursday, "thu": time.Thursday,
+	"friday": time.Friday, "fri": time.Friday,
+	"saturday": time.Saturday, "sat": time.Saturday,
+}
+
+// DeliveryTime parses the schedule's HH:MM delivery time.
+func (s *DigestScheduleSettings) DeliveryTime() (hour, minute int, err error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s.Time))
+	if err != nil {
+		return 0, 0, errors.Newf("invalid digest delivery time %q, expected HH:MM", s.Time).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "notification-digest-time").
+			Build()
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// DeliveryWeekday parses the schedule's weekday name (full or three-letter, case-insensitive).
+func (s *DigestScheduleSettings) DeliveryWeekday() (time.Weekday, error) {
+	day, ok := digestWeekdays[strings.ToLower(strings.TrimSpace(s.Weekday))]
+	if !ok {
+		return time.Sunday, errors.Newf("invalid digest weekday %q", s.Weekday).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "notification-digest-weekday").
+			Build()
+	}
+	return day, nil
+}
+
+// IsValidDigestFormat reports whether format is a supported digest message format.
+func IsValidDigestFormat(format string) bool {
+	switch format {
+	case DigestFormatText, DigestFormatMarkdown, DigestFormatHTML:
+		return true
+	default:
+		return false
+	}
+}
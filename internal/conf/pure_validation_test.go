@@ -156,6 +156,27 @@ func TestValidateBirdNETSettings_Invalid(t *testing.T) {
 			},
 			expectError: "threads must be at least 0",
 		},
+		{
+			name: "batch size too large",
+			config: BirdNETConfig{
+				BatchSize: MaxBirdNETBatchSize + 1,
+			},
+			expectError: "batch size must be between 0 and",
+		},
+		{
+			name: "negative batch size",
+			config: BirdNETConfig{
+				BatchSize: -1,
+			},
+			expectError: "batch size must be between 0 and",
+		},
+		{
+			name: "adaptive overlap minimum too large",
+			config: BirdNETConfig{
+				AdaptiveOverlap: AdaptiveOverlapSettings{MinOverlap: 3.0},
+			},
+			expectError: "adaptive overlap minimum must be between",
+		},
 		{
 			name: "invalid range filter model",
 			config: BirdNETConfig{
@@ -758,6 +779,35 @@ func TestValidateWebServerSettings_Valid(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "icecast enabled",
+			settings: WebServerSettings{
+				Enabled: true,
+				Port:    "8080",
+				LiveStream: LiveStreamSettings{
+					BitRate:       128,
+					SegmentLength: 5,
+				},
+				Icecast: IcecastSettings{
+					Enabled:      true,
+					Format:       IcecastFormatOpus,
+					BitRate:      64,
+					MaxListeners: 5,
+					Username:     "player",
+					Password:     "secret",
+				},
+			},
+		},
+		{
+			name: "icecast disabled ignores settings",
+			settings: WebServerSettings{
+				LiveStream: LiveStreamSettings{
+					BitRate:       128,
+					SegmentLength: 5,
+				},
+				Icecast: IcecastSettings{Format: "wav"},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -858,6 +908,39 @@ func TestValidateWebServerSettings_Invalid(t *testing.T) {
 			},
 			expectError: "segment length must be between 1 and 30 seconds",
 		},
+		{
+			name: "icecast unknown format",
+			settings: WebServerSettings{
+				LiveStream: LiveStreamSettings{
+					BitRate:       128,
+					SegmentLength: 5,
+				},
+				Icecast: IcecastSettings{Enabled: true, Format: "aac", BitRate: 128, MaxListeners: 5},
+			},
+			expectError: "Icecast format must be",
+		},
+		{
+			name: "icecast no listeners",
+			settings: WebServerSettings{
+				LiveStream: LiveStreamSettings{
+					BitRate:       128,
+					SegmentLength: 5,
+				},
+				Icecast: IcecastSettings{Enabled: true, Format: IcecastFormatMP3, BitRate: 128},
+			},
+			expectError: "max listeners must be between 1 and 100",
+		},
+		{
+			name: "icecast password without username",
+			settings: WebServerSettings{
+				LiveStream: LiveStreamSettings{
+					BitRate:       128,
+					SegmentLength: 5,
+				},
+				Icecast: IcecastSettings{Enabled: true, Format: IcecastFormatMP3, BitRate: 128, MaxListeners: 5, Password: "secret"},
+			},
+			expectError: "username and password must be set together",
+		},
 	}
 
 	for _, tt := range tests {
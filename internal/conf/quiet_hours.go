@@ -0,0 +1,81 @@
+package conf
+
+import (
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// Quiet hours actions for notifications withheld during the quiet window.
+const (
+	QuietHoursActionDrop   = "drop"   // discard the notification
+	QuietHoursActionDelay  = "delay"  // deliver each notification when the window ends
+	QuietHoursActionRollup = "rollup" // deliver one summary when the window ends
+)
+
+// Sun events usable as quiet hours boundaries.
+const (
+	SunEventSunrise = "sunrise"
+	SunEventSunset  = "sunset"
+	SunEventDawn    = "dawn" // civil dawn
+	SunEventDusk    = "dusk" // civil dusk
+)
+
+// QuietHoursTime is a parsed quiet hours boundary. When SunEvent is empty the
+// boundary is the clock time Hour:Minute, otherwise it is the sun event time
+// shifted by Offset.
+type QuietHoursTime struct {
+	SunEvent string
+	Hour     int
+	Minute   int
+	Offset   time.Duration
+}
+
+// IsSunRelative reports whether the boundary depends on a sun event.
+func (t QuietHoursTime) IsSunRelative() bool {
+	return t.SunEvent != ""
+}
+
+// ParseQuietHoursTime parses a quiet hours boundary such as "22:30", "sunset"
+// or "sunrise-45m".
+func ParseQuietHoursTime(spec string) (QuietHoursTime, error) {
+	spec = strings.ToLower(strings.TrimSpace(spec))
+
+	for _, event := range []string{SunEventSunrise, SunEventSunset, SunEventDawn, SunEventDusk} {
+		rest, found := strings.CutPrefix(spec, event)
+		if !found {
+			continue
+		}
+		result := QuietHoursTime{SunEvent: event}
+		if rest == "" {
+			return result, nil
+		}
+		if rest[0] != '+' && rest[0] != '-' {
+			break
+		}
+		offset, err := time.ParseDuration(rest)
+		if err != nil || offset > 12*time.Hour || offset < -12*time.Hour {
+			break
+		}
+		result.Offset = offset
+		return result, nil
+	}
+
+	if t, err := time.Parse("15:04", spec); err == nil {
+		return QuietHoursTime{Hour: t.Hour(), Minute: t.Minute()}, nil
+	}
+
+	return QuietHoursTime{}, errors.Newf("invalid quiet hours time %q, expected HH:MM or a sun event such as sunset+30m", spec).
+		Category(errors.CategoryValidation).
+		Context("validation_type", "notification-quiet-hours-time").
+		Build()
+}
+
+// QuietHoursAction returns the configured action, defaulting to rollup.
+func (q *QuietHoursConfig) QuietHoursAction() string {
+	if q.Action == "" {
+		return QuietHoursActionRollup
+	}
+	return strings.ToLower(q.Action)
+}
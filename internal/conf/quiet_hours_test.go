@@ -0,0 +1,60 @@
+package conf
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuietHoursTime(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		spec    string
+		want    QuietHoursTime
+		wantErr bool
+	}{
+		{spec: "22:30", want: QuietHoursTime{Hour: 22, Minute: 30}},
+		{spec: "07:00", want: QuietHoursTime{Hour: 7}},
+		{spec: "sunset", want: QuietHoursTime{SunEvent: SunEventSunset}},
+		{spec: " Sunrise ", want: QuietHoursTime{SunEvent: SunEventSunrise}},
+		{spec: "sunset+30m", want: QuietHoursTime{SunEvent: SunEventSunset, Offset: 30 * time.Minute}},
+		{spec: "dawn-1h15m", want: QuietHoursTime{SunEvent: SunEventDawn, Offset: -75 * time.Minute}},
+		{spec: "dusk", want: QuietHoursTime{SunEvent: SunEventDusk}},
+		{spec: "", wantErr: true},
+		{spec: "24:00", wantErr: true},
+		{spec: "sunset30m", wantErr: true},
+		{spec: "sunrise+13h", wantErr: true},
+		{spec: "noon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			t.Parallel()
+			got, err := ParseQuietHoursTime(tt.spec)
+			if tt.wantErr {
+				require.Error(t, err)
+				assertValidationError(t, err, "notification-quiet-hours-time")
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateQuietHours(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, validateQuietHours(&QuietHoursConfig{Start: "bogus"}), "disabled quiet hours are not validated")
+	require.NoError(t, validateQuietHours(&QuietHoursConfig{Enabled: true, Start: "22:00", End: "sunrise"}))
+	require.NoError(t, validateQuietHours(&QuietHoursConfig{Enabled: true, Start: "sunset", End: "06:00", Action: "Delay"}))
+
+	err := validateQuietHours(&QuietHoursConfig{Enabled: true, Start: "22:00", End: "later"})
+	assertValidationError(t, err, "notification-quiet-hours-time")
+
+	err = validateQuietHours(&QuietHoursConfig{Enabled: true, Start: "22:00", End: "06:00", Action: "snooze"})
+	assertValidationError(t, err, "notification-quiet-hours-action")
+}
@@ -95,6 +95,18 @@ func TestStreamConfig_YAML_RoundTrip(t *testing.T) {
 				Type: StreamTypeUDP,
 			},
 		},
+		{
+			name: "equalizer override",
+			stream: StreamConfig{
+				Name: "Roadside Camera",
+				URL:  "rtsp://192.168.1.50/stream",
+				Type: StreamTypeRTSP,
+				Equalizer: &EqualizerSettings{
+					Enabled: true,
+					Filters: []EqualizerFilter{{Type: "HighPass", Frequency: 300, Q: 0.707, Passes: 4}},
+				},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -115,6 +127,7 @@ func TestStreamConfig_YAML_RoundTrip(t *testing.T) {
 			assert.Equal(t, tt.stream.URL, result.URL, "URL mismatch")
 			assert.Equal(t, tt.stream.Type, result.Type, "Type mismatch")
 			assert.Equal(t, tt.stream.Transport, result.Transport, "Transport mismatch")
+			assert.Equal(t, tt.stream.Equalizer, result.Equalizer, "Equalizer mismatch")
 		})
 	}
 }
@@ -25,6 +25,49 @@ func TestStreamConfig_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid backup URLs with another protocol",
+			stream: StreamConfig{
+				Name:       "Front Yard",
+				URL:        "rtsp://192.168.1.10/main",
+				Type:       StreamTypeRTSP,
+				BackupURLs: []string{"rtsp://192.168.1.10/sub", "http://relay.local/front.mp3"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "backup URL duplicates primary",
+			stream: StreamConfig{
+				Name:       "Front Yard",
+				URL:        "rtsp://192.168.1.10/main",
+				Type:       StreamTypeRTSP,
+				BackupURLs: []string{"rtsp://192.168.1.10/main"},
+			},
+			wantErr: true,
+			errMsg:  "duplicates another URL",
+		},
+		{
+			name: "backup URL with unsupported scheme",
+			stream: StreamConfig{
+				Name:       "Front Yard",
+				URL:        "rtsp://192.168.1.10/main",
+				Type:       StreamTypeRTSP,
+				BackupURLs: []string{"file:///tmp/audio.wav"},
+			},
+			wantErr: true,
+			errMsg:  "backup URL 1 must use",
+		},
+		{
+			name: "empty backup URL",
+			stream: StreamConfig{
+				Name:       "Front Yard",
+				URL:        "rtsp://192.168.1.10/main",
+				Type:       StreamTypeRTSP,
+				BackupURLs: []string{" "},
+			},
+			wantErr: true,
+			errMsg:  "backup URL 1 is empty",
+		},
 		{
 			name: "valid RTSPS stream",
 			stream: StreamConfig{
@@ -236,6 +279,17 @@ func TestRTSPSettings_ValidateStreams(t *testing.T) {
 			wantErr: true,
 			errMsg:  "has a duplicate URL",
 		},
+		{
+			name: "backup URL shared with another stream",
+			rtsp: RTSPSettings{
+				Streams: []StreamConfig{
+					{Name: "Front Yard", URL: "rtsp://192.168.1.10/stream", Type: StreamTypeRTSP, BackupURLs: []string{"rtsp://192.168.1.30/relay"}},
+					{Name: "Back Yard", URL: "rtsp://192.168.1.20/stream", Type: StreamTypeRTSP, BackupURLs: []string{"rtsp://192.168.1.30/relay"}},
+				},
+			},
+			wantErr: true,
+			errMsg:  "backup URL used by another stream",
+		},
 		{
 			name: "invalid stream in list",
 			rtsp: RTSPSettings{
@@ -376,6 +430,49 @@ func TestStreamConfig_Validate_EdgeCases(t *testing.T) {
 }
 
 // Benchmark for stream validation
+func TestChannelSettings_Validate(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		channels ChannelSettings
+		errMsg   string
+	}{
+		{name: "empty mode mixes", channels: ChannelSettings{}},
+		{name: "mix ignores count", channels: ChannelSettings{Mode: ChannelModeMix, Count: 99}},
+		{name: "stereo split", channels: ChannelSettings{Mode: ChannelModeSplit, Count: 2, Names: []string{"North", "South"}}},
+		{name: "select third of four", channels: ChannelSettings{Mode: ChannelModeSelect, Count: 4, Channel: 3}},
+		{name: "unknown mode", channels: ChannelSettings{Mode: "surround", Count: 2}, errMsg: "invalid channel mode"},
+		{name: "split single channel", channels: ChannelSettings{Mode: ChannelModeSplit, Count: 1}, errMsg: "channel count"},
+		{name: "too many channels", channels: ChannelSettings{Mode: ChannelModeSplit, Count: MaxCaptureChannels + 1}, errMsg: "channel count"},
+		{name: "select without channel", channels: ChannelSettings{Mode: ChannelModeSelect, Count: 2}, errMsg: "selected channel"},
+		{name: "select beyond count", channels: ChannelSettings{Mode: ChannelModeSelect, Count: 2, Channel: 3}, errMsg: "selected channel"},
+		{name: "more names than channels", channels: ChannelSettings{Mode: ChannelModeSplit, Count: 2, Names: []string{"a", "b", "c"}}, errMsg: "channel names"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.channels.Validate()
+			if tt.errMsg != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	stream := StreamConfig{
+		Name:     "Stereo Recorder",
+		URL:      "rtsp://192.168.1.10/stream",
+		Type:     StreamTypeRTSP,
+		Channels: ChannelSettings{Mode: ChannelModeSelect, Count: 2},
+	}
+	err := stream.Validate()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Stereo Recorder")
+}
+
 func BenchmarkStreamConfig_Validate(b *testing.B) {
 	stream := &StreamConfig{
 		Name:      "Front Yard",
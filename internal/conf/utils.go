@@ -332,6 +332,31 @@ func ParseRetentionPeriod(retention string) (int, error) {
 	}
 }
 
+// ParseSize converts a size string like "500MB", "20GB" or "1TB" to bytes.
+// Units are binary multiples, a plain number is taken as bytes.
+func ParseSize(size string) (int64, error) {
+	size = strings.ToUpper(strings.TrimSpace(size))
+	multiplier := int64(1)
+	for _, unit := range []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"TB", 1 << 40}, {"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1},
+	} {
+		if before, ok := strings.CutSuffix(size, unit.suffix); ok {
+			size = strings.TrimSpace(before)
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	value, err := strconv.ParseFloat(size, 64)
+	if err != nil || value < 0 {
+		return 0, fmt.Errorf("invalid size format: %q", size)
+	}
+	return int64(value * float64(multiplier)), nil
+}
+
 // ParseWeekday converts a string to time.Weekday
 func ParseWeekday(day string) (time.Weekday, error) {
 	switch strings.ToLower(day) {
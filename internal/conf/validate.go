@@ -5,12 +5,17 @@ package conf
 import (
 	"fmt"
 	"net"
+	"net/url"
 	"os/exec"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strconv"
 	"strings"
 	"text/template"
+	"time"
+
+	"golang.org/x/net/http/httpguts"
 
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
@@ -26,6 +31,9 @@ const DefaultCleanupCheckInterval = 15
 var (
 	// birdweatherIDPattern validates Birdweather ID format (24 alphanumeric characters)
 	birdweatherIDPattern = regexp.MustCompile(`^[a-zA-Z0-9]{24}$`)
+
+	// ingestDeviceIDPattern validates push ingest device IDs, which appear in the ingest URL
+	ingestDeviceIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
 )
 
 // Audio gain limits in dB
@@ -37,6 +45,22 @@ const (
 // Stream validation constants
 const (
 	MaxStreamNameLength = 64
+	MaxStreamBackupURLs = 5
+)
+
+// Push ingest validation constants
+const (
+	MinIngestTokenLength = 16  // minimum device token length
+	MaxIngestLatency     = 600 // maximum playout buffer in seconds
+)
+
+// MinWorkerTokenLength is the minimum bearer token length of an inference worker
+const MinWorkerTokenLength = 16
+
+// Multi-station hub validation constants
+const (
+	MinHubTokenLength = 16   // minimum station token length
+	MaxHubBatchSize   = 1000 // most detections pushed per request
 )
 
 // ValidStreamTypes contains all supported stream types
@@ -117,7 +141,74 @@ func (s *StreamConfig) Validate() error {
 	}
 
 	// Validate URL scheme matches type
-	return s.validateURLScheme()
+	if err := s.validateURLScheme(); err != nil {
+		return err
+	}
+
+	if err := s.Channels.Validate(); err != nil {
+		return fmt.Errorf("stream '%s': %w", s.Name, err)
+	}
+
+	return s.validateBackupURLs()
+}
+
+// validateBackupURLs checks that backup URLs use a supported scheme and are
+// distinct. A backup may use a different protocol than the primary URL.
+func (s *StreamConfig) validateBackupURLs() error {
+	if len(s.BackupURLs) > MaxStreamBackupURLs {
+		return fmt.Errorf("stream '%s': at most %d backup URLs are supported", s.Name, MaxStreamBackupURLs)
+	}
+	seen := map[string]bool{strings.TrimSpace(s.URL): true}
+	for i, backup := range s.BackupURLs {
+		backup = strings.TrimSpace(backup)
+		if backup == "" {
+			return fmt.Errorf("stream '%s': backup URL %d is empty", s.Name, i+1)
+		}
+		if seen[backup] {
+			return fmt.Errorf("stream '%s': backup URL %d duplicates another URL of the stream", s.Name, i+1)
+		}
+		seen[backup] = true
+		if !hasStreamScheme(backup) {
+			return fmt.Errorf("stream '%s': backup URL %d must use rtsp, rtsps, http, https, rtmp, rtmps, udp or rtp", s.Name, i+1)
+		}
+	}
+	return nil
+}
+
+// hasStreamScheme reports whether a URL uses a scheme of a supported stream type.
+func hasStreamScheme(rawURL string) bool {
+	scheme, _, found := strings.Cut(strings.ToLower(rawURL), "://")
+	if !found {
+		return false
+	}
+	switch scheme {
+	case "rtsp", "rtsps", "http", "https", "rtmp", "rtmps", "udp", "rtp":
+		return true
+	default:
+		return false
+	}
+}
+
+// Validate checks the channel mode, channel count and selected channel
+func (c *ChannelSettings) Validate() error {
+	switch c.Mode {
+	case "", ChannelModeMix:
+		return nil
+	case ChannelModeSplit, ChannelModeSelect:
+	default:
+		return fmt.Errorf("invalid channel mode '%s': must be mix, split or select", c.Mode)
+	}
+
+	if c.Count < 2 || c.Count > MaxCaptureChannels {
+		return fmt.Errorf("channel count must be between 2 and %d in %s mode, got %d", MaxCaptureChannels, c.Mode, c.Count)
+	}
+	if c.Mode == ChannelModeSelect && (c.Channel < 1 || c.Channel > c.Count) {
+		return fmt.Errorf("selected channel must be between 1 and %d, got %d", c.Count, c.Channel)
+	}
+	if len(c.Names) > c.Count {
+		return fmt.Errorf("%d channel names given for %d channels", len(c.Names), c.Count)
+	}
+	return nil
 }
 
 // validateURLScheme checks URL scheme matches declared stream type
@@ -150,6 +241,122 @@ func (s *StreamConfig) validateURLScheme() error {
 	return nil
 }
 
+// Validate checks the playout latency and that ingest devices have unique IDs
+// that are safe in a URL and tokens long enough to resist guessing
+func (s *IngestSettings) Validate() error {
+	if !s.Enabled {
+		return nil
+	}
+	if s.Latency < 1 || s.Latency > MaxIngestLatency {
+		return fmt.Errorf("ingest latency must be between 1 and %d seconds, got %d", MaxIngestLatency, s.Latency)
+	}
+
+	ids := make(map[string]bool, len(s.Devices))
+	for i := range s.Devices {
+		device := &s.Devices[i]
+		if !ingestDeviceIDPattern.MatchString(device.ID) {
+			return fmt.Errorf("ingest device %d: id must be 1-64 letters, digits, '-' or '_'", i+1)
+		}
+		if ids[device.ID] {
+			return fmt.Errorf("ingest device '%s' has a duplicate id", device.ID)
+		}
+		ids[device.ID] = true
+		if len(device.Name) > MaxStreamNameLength {
+			return fmt.Errorf("ingest device '%s': name must be %d characters or less", device.ID, MaxStreamNameLength)
+		}
+		if len(strings.TrimSpace(device.Token)) < MinIngestTokenLength {
+			return fmt.Errorf("ingest device '%s': token must be at least %d characters", device.ID, MinIngestTokenLength)
+		}
+	}
+	return nil
+}
+
+// Validate checks the stations a hub accepts and the hub a station pushes to
+func (s *HubSettings) Validate() error {
+	if s.Enabled {
+		names := make(map[string]bool, len(s.Stations))
+		for i := range s.Stations {
+			station := &s.Stations[i]
+			if !ingestDeviceIDPattern.MatchString(station.Name) {
+				return fmt.Errorf("hub station %d: name must be 1-64 letters, digits, '-' or '_'", i+1)
+			}
+			if names[station.Name] {
+				return fmt.Errorf("hub station '%s' has a duplicate name", station.Name)
+			}
+			names[station.Name] = true
+			if len(strings.TrimSpace(station.Token)) < MinHubTokenLength {
+				return fmt.Errorf("hub station '%s': token must be at least %d characters", station.Name, MinHubTokenLength)
+			}
+			if station.URL != "" && !isHTTPURL(station.URL) {
+				return fmt.Errorf("hub station '%s': url must be an http or https URL, got %q", station.Name, station.URL)
+			}
+		}
+	}
+
+	up := &s.Upstream
+	if !up.Enabled {
+		return nil
+	}
+	if !isHTTPURL(up.URL) {
+		return fmt.Errorf("hub upstream url must be an http or https URL, got %q", up.URL)
+	}
+	if !ingestDeviceIDPattern.MatchString(up.Station) {
+		return fmt.Errorf("hub upstream station must be 1-64 letters, digits, '-' or '_'")
+	}
+	if strings.TrimSpace(up.Token) == "" {
+		return fmt.Errorf("hub upstream token is required")
+	}
+	if up.Interval.Std() < time.Second {
+		return fmt.Errorf("hub upstream interval must be at least 1s, got %s", up.Interval.Std())
+	}
+	if up.BatchSize < 1 || up.BatchSize > MaxHubBatchSize {
+		return fmt.Errorf("hub upstream batch size must be between 1 and %d, got %d", MaxHubBatchSize, up.BatchSize)
+	}
+	return nil
+}
+
+// isHTTPURL reports whether raw is an absolute http or https URL
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Validate checks that each inference worker has an HTTP URL and a token
+func (s *RemoteInferenceSettings) Validate() error {
+	if len(s.Workers) == 0 {
+		return nil
+	}
+	if s.Timeout <= 0 {
+		return fmt.Errorf("remote inference timeout must be positive, got %s", s.Timeout.Std())
+	}
+	for i := range s.Workers {
+		worker := &s.Workers[i]
+		u, err := url.Parse(worker.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("inference worker %d: url must be an http or https URL, got %q", i+1, worker.URL)
+		}
+		if strings.TrimSpace(worker.Token) == "" {
+			return fmt.Errorf("inference worker %s: token is required", u.Host)
+		}
+	}
+	return nil
+}
+
+// Validate checks that the worker command has an address to listen on, a
+// token long enough to resist guessing and, for TLS, both certificate and key
+func (s *InferenceWorkerSettings) Validate() error {
+	if s.Listen == "" {
+		return fmt.Errorf("inference worker listen address is required")
+	}
+	if len(strings.TrimSpace(s.Token)) < MinWorkerTokenLength {
+		return fmt.Errorf("inference worker token must be at least %d characters", MinWorkerTokenLength)
+	}
+	if (s.CertFile == "") != (s.KeyFile == "") {
+		return fmt.Errorf("inference worker TLS certificate and key must be set together")
+	}
+	return nil
+}
+
 // ValidateStreams validates the streams collection for uniqueness and individual validity
 func (r *RTSPSettings) ValidateStreams() error {
 	names := make(map[string]bool)
@@ -174,6 +381,15 @@ func (r *RTSPSettings) ValidateStreams() error {
 			return fmt.Errorf("stream '%s' has a duplicate URL: '%s'", stream.Name, stream.URL)
 		}
 		urls[urlTrimmed] = true
+
+		// A backup URL shared with another stream would mix their audio on failover
+		for _, backup := range stream.BackupURLs {
+			backupTrimmed := strings.TrimSpace(backup)
+			if urls[backupTrimmed] {
+				return fmt.Errorf("stream '%s' has a backup URL used by another stream", stream.Name)
+			}
+			urls[backupTrimmed] = true
+		}
 	}
 
 	return nil
@@ -224,6 +440,24 @@ func ValidateBirdNETSettings(cfg *BirdNETConfig) ValidationResult {
 		result.Errors = append(result.Errors, "BirdNET threads must be at least 0")
 	}
 
+	// Batch size check, 0 and 1 both disable batching
+	if cfg.BatchSize < 0 || cfg.BatchSize > MaxBirdNETBatchSize {
+		result.Valid = false
+		result.Errors = append(result.Errors, fmt.Sprintf("BirdNET batch size must be between 0 and %d, 0 or 1 disables batching", MaxBirdNETBatchSize))
+	}
+
+	// Adaptive overlap floor uses the same range as the overlap
+	if cfg.AdaptiveOverlap.MinOverlap < 0 || cfg.AdaptiveOverlap.MinOverlap > 2.99 {
+		result.Valid = false
+		result.Errors = append(result.Errors, "BirdNET adaptive overlap minimum must be between 0 and 2.99 seconds")
+	}
+
+	// Remote inference workers check
+	if err := cfg.Remote.Validate(); err != nil {
+		result.Valid = false
+		result.Errors = append(result.Errors, err.Error())
+	}
+
 	// RangeFilter model check - empty string, "latest", or "legacy" are valid
 	if cfg.RangeFilter.Model != "" && cfg.RangeFilter.Model != "latest" && cfg.RangeFilter.Model != "legacy" {
 		result.Valid = false
@@ -439,10 +673,35 @@ func ValidateWebServerSettings(settings *WebServerSettings) ValidationResult {
 			fmt.Sprintf("LiveStream segment length must be between 1 and 30 seconds, got %d", settings.LiveStream.SegmentLength))
 	}
 
+	if settings.Icecast.Enabled {
+		if errs := validateIcecastSettings(&settings.Icecast); len(errs) > 0 {
+			result.Valid = false
+			result.Errors = append(result.Errors, errs...)
+		}
+	}
+
 	result.Normalized = settings
 	return result
 }
 
+// validateIcecastSettings checks the Icecast stream output of the web server
+func validateIcecastSettings(settings *IcecastSettings) []string {
+	var errs []string
+	if settings.Format != IcecastFormatMP3 && settings.Format != IcecastFormatOpus {
+		errs = append(errs, fmt.Sprintf("Icecast format must be %q or %q, got %q", IcecastFormatMP3, IcecastFormatOpus, settings.Format))
+	}
+	if settings.BitRate < 16 || settings.BitRate > 320 {
+		errs = append(errs, fmt.Sprintf("Icecast bitrate must be between 16 and 320 kbps, got %d", settings.BitRate))
+	}
+	if settings.MaxListeners < 1 || settings.MaxListeners > 100 {
+		errs = append(errs, fmt.Sprintf("Icecast max listeners must be between 1 and 100, got %d", settings.MaxListeners))
+	}
+	if (settings.Username == "") != (settings.Password == "") {
+		errs = append(errs, "Icecast username and password must be set together")
+	}
+	return errs
+}
+
 // ValidateSettings validates the entire Settings struct
 func ValidateSettings(settings *Settings) error {
 	ve := ValidationError{}
@@ -497,6 +756,16 @@ func ValidateSettings(settings *Settings) error {
 		ve.Errors = append(ve.Errors, err.Error())
 	}
 
+	// Validate digest schedule and templates
+	if err := validateDigestSettings(&settings.Notification); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
+	// Validate multi-station hub settings
+	if err := settings.Hub.Validate(); err != nil {
+		ve.Errors = append(ve.Errors, err.Error())
+	}
+
 	// If there are any errors, return the ValidationError
 	if len(ve.Errors) > 0 {
 		return ve
@@ -613,6 +882,10 @@ func validateSecuritySettings(settings *Security) error {
 		}
 	}
 
+	if err := validateTrustedProxyAuth(&settings.TrustedProxyAuth); err != nil {
+		return err
+	}
+
 	// Validate session duration
 	if settings.SessionDuration <= 0 {
 		return errors.Newf("security.sessionduration must be a positive duration").
@@ -624,6 +897,61 @@ func validateSecuritySettings(settings *Security) error {
 	return nil
 }
 
+// validateTrustedProxyAuth validates the reverse proxy forward-auth settings.
+// A trusted proxy list is mandatory when enabled: accepting identity headers
+// from any peer would let every client impersonate any user.
+func validateTrustedProxyAuth(settings *TrustedProxyAuth) error {
+	if !settings.Enabled {
+		return nil
+	}
+
+	if strings.TrimSpace(settings.TrustedProxies) == "" {
+		return errors.Newf("security.trustedProxyAuth.trustedProxies must list at least one CIDR range when trusted proxy authentication is enabled").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "security-trusted-proxy-list").
+			Build()
+	}
+
+	for cidr := range strings.SplitSeq(settings.TrustedProxies, ",") {
+		trimmed := strings.TrimSpace(cidr)
+		if trimmed == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(trimmed); err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "security-trusted-proxy-format").
+				Context("subnet", trimmed).
+				Build()
+		}
+	}
+
+	if !httpguts.ValidHeaderFieldName(settings.UserHeader) {
+		return errors.Newf("security.trustedProxyAuth.userHeader must be a valid HTTP header name").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "security-trusted-proxy-header").
+			Context("header", settings.UserHeader).
+			Build()
+	}
+
+	if settings.GroupsHeader != "" && !httpguts.ValidHeaderFieldName(settings.GroupsHeader) {
+		return errors.Newf("security.trustedProxyAuth.groupsHeader must be a valid HTTP header name").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "security-trusted-proxy-header").
+			Context("header", settings.GroupsHeader).
+			Build()
+	}
+
+	if settings.GroupsHeader == "" && (settings.AdminGroups != "" || settings.ViewerGroups != "") {
+		return errors.Newf("security.trustedProxyAuth.groupsHeader must be set when group mappings are configured").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "security-trusted-proxy-groups").
+			Build()
+	}
+
+	return nil
+}
+
 // validateRealtimeSettings validates the Realtime-specific settings
 func validateRealtimeSettings(settings *RealtimeSettings) error {
 	// Check if interval is non-negative
@@ -644,6 +972,35 @@ func validateRealtimeSettings(settings *RealtimeSettings) error {
 		return err
 	}
 
+	// Validate microphone health settings
+	if err := validateMicHealthSettings(&settings.Audio.MicHealth); err != nil {
+		return err
+	}
+
+	if err := validateNoiseReductionSettings(&settings.Audio.NoiseReduction); err != nil {
+		return err
+	}
+
+	if err := validateArchiveSettings(&settings.Audio); err != nil {
+		return err
+	}
+
+	// Validate the sound card channel layout
+	if err := settings.Audio.Channels.Validate(); err != nil {
+		return errors.New(err).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "audio-channels").
+			Build()
+	}
+
+	// Validate push ingest devices
+	if err := settings.Ingest.Validate(); err != nil {
+		return errors.New(err).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "ingest-config").
+			Build()
+	}
+
 	// Validate species settings
 	if err := validateSpeciesConfigSettings(&settings.Species); err != nil {
 		return err
@@ -697,6 +1054,117 @@ func validateSoundLevelSettings(settings *SoundLevelSettings) error {
 	return nil
 }
 
+// validateMicHealthSettings validates the microphone health diagnostics thresholds
+func validateMicHealthSettings(settings *MicHealthSettings) error {
+	if !settings.Enabled {
+		return nil
+	}
+	ratios := []struct {
+		name  string
+		value float64
+	}{
+		{"clippingratio", settings.ClippingRatio},
+		{"dcoffset", settings.DCOffset},
+		{"humratio", settings.HumRatio},
+	}
+	for _, r := range ratios {
+		if r.value <= 0 || r.value > 1 {
+			return errors.Newf("microphone health %s must be greater than 0 and at most 1, got %g", r.name, r.value).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "mic-health-threshold").
+				Context("setting", r.name).
+				Build()
+		}
+	}
+	if settings.MinRollOff < 0 || settings.MaxImbalance <= 0 {
+		return errors.Newf("microphone health roll-off must not be negative and imbalance must be positive").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "mic-health-threshold").
+			Build()
+	}
+	return nil
+}
+
+// validateNoiseReductionSettings validates the noise reduction strength and gate threshold
+func validateNoiseReductionSettings(settings *NoiseReductionSettings) error {
+	if !settings.Enabled && !settings.Clips {
+		return nil
+	}
+	if settings.Strength < 0 || settings.Strength > 1 {
+		return errors.Newf("noise reduction strength must be between 0 and 1, got %g", settings.Strength).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "noise-reduction").
+			Context("setting", "strength").
+			Build()
+	}
+	if settings.Threshold < 0 || settings.Threshold > 30 {
+		return errors.Newf("noise reduction threshold must be between 0 and 30 dB, got %g", settings.Threshold).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "noise-reduction").
+			Context("setting", "threshold").
+			Build()
+	}
+	return nil
+}
+
+// validateArchiveSettings validates the continuous audio archive segment length,
+// retention and path, which must not overlap the clip export directory
+func validateArchiveSettings(settings *AudioSettings) error {
+	archive := &settings.Archive
+	if !archive.Enabled {
+		return nil
+	}
+	if archive.Path == "" {
+		return errors.Newf("audio archive path must not be empty").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "audio-archive").
+			Context("setting", "path").
+			Build()
+	}
+	if archivePath, exportPath := filepath.Clean(archive.Path), filepath.Clean(settings.Export.Path); archivePath == exportPath ||
+		strings.HasPrefix(archivePath, exportPath+string(filepath.Separator)) ||
+		strings.HasPrefix(exportPath, archivePath+string(filepath.Separator)) {
+		return errors.Newf("audio archive path %q must not overlap the clip export path %q", archive.Path, settings.Export.Path).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "audio-archive").
+			Context("setting", "path").
+			Build()
+	}
+	if archive.SegmentLength < 10 || archive.SegmentLength > 3600 {
+		return errors.Newf("audio archive segment length must be between 10 and 3600 seconds, got %d", archive.SegmentLength).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "audio-archive").
+			Context("setting", "segmentlength").
+			Build()
+	}
+	if archive.MaxAge == "" && archive.MaxSize == "" {
+		return errors.Newf("audio archive needs a maximum age or size to bound disk usage").
+			Category(errors.CategoryValidation).
+			Context("validation_type", "audio-archive").
+			Context("setting", "retention").
+			Build()
+	}
+	if archive.MaxAge != "" {
+		if _, err := ParseRetentionPeriod(archive.MaxAge); err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "audio-archive").
+				Context("setting", "maxage").
+				Build()
+		}
+	}
+	if archive.MaxSize != "" {
+		if _, err := ParseSize(archive.MaxSize); err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "audio-archive").
+				Context("setting", "maxsize").
+				Build()
+		}
+	}
+	return nil
+}
+
 // validateBirdweatherSettings validates the Birdweather-specific settings.
 // This function uses ValidateBirdweatherSettings internally and handles side effects
 // (logging, mutation) to maintain backward compatibility.
@@ -847,8 +1315,11 @@ func validateAudioSettings(settings *AudioSettings) error {
 		}
 
 		if settings.FfmpegPath == "" {
-			settings.Export.Type = "wav"
-			GetLogger().Warn("FFmpeg not available, using WAV format for audio export")
+			// WAV and FLAC are encoded natively, lossy formats need FFmpeg
+			if settings.Export.Type != "wav" && settings.Export.Type != "flac" {
+				settings.Export.Type = "wav"
+				GetLogger().Warn("FFmpeg not available, using WAV format for audio export")
+			}
 		} else {
 			// Validate audio type and bitrate
 			switch settings.Export.Type {
@@ -1169,6 +1640,15 @@ func validateNotificationSettings(n *NotificationConfig) error {
 	}
 	for i := range n.Push.Providers {
 		p := &n.Push.Providers[i]
+		if err := validateQuietHours(&p.QuietHours); err != nil {
+			return err
+		}
+		if p.Attachments.MaxSizeKB < 0 {
+			return errors.Newf("push provider attachments max_size_kb must be >= 0").
+				Category(errors.CategoryValidation).
+				Context("validation_type", "notification-push-attachments-size").
+				Build()
+		}
 		ptype := strings.ToLower(p.Type)
 		switch ptype {
 		case "script":
@@ -1199,6 +1679,77 @@ func validateNotificationSettings(n *NotificationConfig) error {
 	return nil
 }
 
+// validateQuietHours validates a push provider's quiet hours window.
+func validateQuietHours(q *QuietHoursConfig) error {
+	if !q.Enabled {
+		return nil
+	}
+	if _, err := ParseQuietHoursTime(q.Start); err != nil {
+		return err
+	}
+	if _, err := ParseQuietHoursTime(q.End); err != nil {
+		return err
+	}
+	switch q.QuietHoursAction() {
+	case QuietHoursActionDrop, QuietHoursActionDelay, QuietHoursActionRollup:
+		return nil
+	default:
+		return errors.Newf("invalid quiet hours action %q, must be one of: drop, delay, rollup", q.Action).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "notification-quiet-hours-action").
+			Build()
+	}
+}
+
+// validateDigestSettings validates scheduled digest configuration. Only enabled
+// schedules are checked so that partially filled defaults never block startup.
+func validateDigestSettings(n *NotificationConfig) error {
+	d := &n.Digest
+	if !d.Daily.Enabled && !d.Weekly.Enabled {
+		return nil
+	}
+
+	if d.Daily.Enabled {
+		if _, _, err := d.Daily.DeliveryTime(); err != nil {
+			return err
+		}
+	}
+	if d.Weekly.Enabled {
+		if _, _, err := d.Weekly.DeliveryTime(); err != nil {
+			return err
+		}
+		if _, err := d.Weekly.DeliveryWeekday(); err != nil {
+			return err
+		}
+	}
+
+	if !IsValidDigestFormat(d.Format) {
+		return errors.Newf("invalid digest format %q, must be one of: text, markdown, html", d.Format).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "notification-digest-format").
+			Build()
+	}
+
+	if d.TopSpecies < 1 || d.TopSpecies > 50 {
+		return errors.Newf("digest top_species must be between 1 and 50, got %d", d.TopSpecies).
+			Category(errors.CategoryValidation).
+			Context("validation_type", "notification-digest-top-species").
+			Build()
+	}
+
+	for field, tmpl := range map[string]string{"title": n.Templates.Digest.Title, "message": n.Templates.Digest.Message} {
+		if _, err := template.New("digest_" + field).Parse(tmpl); err != nil {
+			return errors.New(err).
+				Category(errors.CategoryValidation).
+				Context("validation_type", "notification-digest-template").
+				Context("field", field).
+				Build()
+		}
+	}
+
+	return nil
+}
+
 // validateWebhookProvider validates webhook provider configuration.
 // This function uses ValidateWebhookProvider internally and handles error formatting
 // to maintain backward compatibility. Authentication validation is still performed separately
@@ -464,3 +464,91 @@ func TestValidateSecuritySettings_EdgeCases(t *testing.T) {
 		})
 	}
 }
+
+// TestValidateSecuritySettings_TrustedProxyAuth tests trusted proxy forward-auth validation
+func TestValidateSecuritySettings_TrustedProxyAuth(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		proxy   TrustedProxyAuth
+		wantErr bool
+		errType string
+	}{
+		{
+			name:    "Disabled - should pass even with empty settings",
+			proxy:   TrustedProxyAuth{Enabled: false},
+			wantErr: false,
+		},
+		{
+			name: "Valid configuration - should pass",
+			proxy: TrustedProxyAuth{
+				Enabled:        true,
+				TrustedProxies: "172.18.0.0/16, 10.0.0.1/32",
+				UserHeader:     "Remote-User",
+				GroupsHeader:   "Remote-Groups",
+				AdminGroups:    "admins",
+			},
+			wantErr: false,
+		},
+		{
+			name: "Missing trusted proxies - should fail",
+			proxy: TrustedProxyAuth{
+				Enabled:    true,
+				UserHeader: "Remote-User",
+			},
+			wantErr: true,
+			errType: "security-trusted-proxy-list",
+		},
+		{
+			name: "Invalid proxy CIDR - should fail",
+			proxy: TrustedProxyAuth{
+				Enabled:        true,
+				TrustedProxies: "172.18.0.1",
+				UserHeader:     "Remote-User",
+			},
+			wantErr: true,
+			errType: "security-trusted-proxy-format",
+		},
+		{
+			name: "Invalid user header - should fail",
+			proxy: TrustedProxyAuth{
+				Enabled:        true,
+				TrustedProxies: "172.18.0.0/16",
+				UserHeader:     "Remote User",
+			},
+			wantErr: true,
+			errType: "security-trusted-proxy-header",
+		},
+		{
+			name: "Group mapping without groups header - should fail",
+			proxy: TrustedProxyAuth{
+				Enabled:        true,
+				TrustedProxies: "172.18.0.0/16",
+				UserHeader:     "X-Forwarded-Email",
+				ViewerGroups:   "family",
+			},
+			wantErr: true,
+			errType: "security-trusted-proxy-groups",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			security := Security{
+				TrustedProxyAuth: tt.proxy,
+				SessionDuration:  24 * time.Hour,
+			}
+			err := validateSecuritySettings(&security)
+
+			if tt.wantErr {
+				require.Error(t, err)
+				assertValidationError(t, err, tt.errType)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
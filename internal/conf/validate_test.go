@@ -2,6 +2,7 @@ package conf
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -151,6 +152,252 @@ func TestValidateSoundLevelSettingsEdgeCases(t *testing.T) {
 	}
 }
 
+func TestValidateMicHealthSettings(t *testing.T) {
+	valid := MicHealthSettings{
+		Enabled:          true,
+		SilenceThreshold: -84,
+		ClippingRatio:    0.001,
+		DCOffset:         0.05,
+		HumRatio:         0.5,
+		MinRollOff:       1000,
+		MaxImbalance:     6,
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(s *MicHealthSettings)
+		wantErr bool
+	}{
+		{"defaults", func(s *MicHealthSettings) {}, false},
+		{"roll-off check disabled", func(s *MicHealthSettings) { s.MinRollOff = 0 }, false},
+		{"zero clipping ratio", func(s *MicHealthSettings) { s.ClippingRatio = 0 }, true},
+		{"hum ratio above one", func(s *MicHealthSettings) { s.HumRatio = 1.5 }, true},
+		{"negative DC offset", func(s *MicHealthSettings) { s.DCOffset = -0.1 }, true},
+		{"negative roll-off", func(s *MicHealthSettings) { s.MinRollOff = -1 }, true},
+		{"zero imbalance", func(s *MicHealthSettings) { s.MaxImbalance = 0 }, true},
+		{"disabled with invalid thresholds", func(s *MicHealthSettings) { *s = MicHealthSettings{} }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := valid
+			tt.modify(&settings)
+
+			err := validateMicHealthSettings(&settings)
+			if tt.wantErr {
+				enhanced := requireEnhancedError(t, err)
+				assert.Equal(t, "mic-health-threshold", enhanced.Context["validation_type"])
+				assert.Equal(t, errors.CategoryValidation, enhanced.Category)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateNoiseReductionSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings NoiseReductionSettings
+		wantErr  bool
+	}{
+		{"defaults", NoiseReductionSettings{Enabled: true, Strength: 0.75, Threshold: 6}, false},
+		{"clips only", NoiseReductionSettings{Clips: true, Strength: 1, Threshold: 0}, false},
+		{"strength above one", NoiseReductionSettings{Enabled: true, Strength: 1.5, Threshold: 6}, true},
+		{"negative threshold", NoiseReductionSettings{Clips: true, Strength: 0.5, Threshold: -1}, true},
+		{"threshold too high", NoiseReductionSettings{Enabled: true, Strength: 0.5, Threshold: 40}, true},
+		{"disabled with invalid values", NoiseReductionSettings{Strength: -1, Threshold: -1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateNoiseReductionSettings(&tt.settings)
+			if tt.wantErr {
+				enhanced := requireEnhancedError(t, err)
+				assert.Equal(t, "noise-reduction", enhanced.Context["validation_type"])
+				assert.Equal(t, errors.CategoryValidation, enhanced.Category)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestValidateArchiveSettings(t *testing.T) {
+	valid := ArchiveSettings{Enabled: true, Path: "archive/", SegmentLength: 300, MaxAge: "7d", MaxSize: "20GB"}
+	tests := []struct {
+		name    string
+		modify  func(*ArchiveSettings)
+		wantErr bool
+	}{
+		{"defaults", func(*ArchiveSettings) {}, false},
+		{"size limit only", func(a *ArchiveSettings) { a.MaxAge = "" }, false},
+		{"empty path", func(a *ArchiveSettings) { a.Path = "" }, true},
+		{"inside export path", func(a *ArchiveSettings) { a.Path = "clips/archive" }, true},
+		{"segment too short", func(a *ArchiveSettings) { a.SegmentLength = 5 }, true},
+		{"no retention limit", func(a *ArchiveSettings) { a.MaxAge, a.MaxSize = "", "" }, true},
+		{"invalid size", func(a *ArchiveSettings) { a.MaxSize = "lots" }, true},
+		{"disabled with invalid values", func(a *ArchiveSettings) { a.Enabled, a.Path = false, "" }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := AudioSettings{Archive: valid}
+			settings.Export.Path = "clips/"
+			tt.modify(&settings.Archive)
+			err := validateArchiveSettings(&settings)
+			if tt.wantErr {
+				enhanced := requireEnhancedError(t, err)
+				assert.Equal(t, "audio-archive", enhanced.Context["validation_type"])
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestIngestSettings_Validate(t *testing.T) {
+	t.Parallel()
+
+	device := IngestDevice{ID: "meadow-pi", Name: "Meadow", Token: "0123456789abcdef"}
+	tests := []struct {
+		name    string
+		modify  func(*IngestSettings)
+		wantErr string
+	}{
+		{"valid", func(*IngestSettings) {}, ""},
+		{"latency too long", func(s *IngestSettings) { s.Latency = MaxIngestLatency + 1 }, "latency"},
+		{"id with slash", func(s *IngestSettings) { s.Devices[0].ID = "meadow/pi" }, "id must be"},
+		{"duplicate id", func(s *IngestSettings) { s.Devices = append(s.Devices, device) }, "duplicate id"},
+		{"short token", func(s *IngestSettings) { s.Devices[0].Token = "secret" }, "token must be"},
+		{"disabled with invalid devices", func(s *IngestSettings) { s.Enabled, s.Devices[0].ID = false, "" }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			settings := IngestSettings{Enabled: true, Latency: 10, Devices: []IngestDevice{device}}
+			tt.modify(&settings)
+			err := settings.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestHubSettings_Validate(t *testing.T) {
+	t.Parallel()
+
+	station := HubStation{Name: "meadow", Token: "0123456789abcdef", URL: "http://meadow.local:8080"}
+	upstream := HubUpstreamSettings{
+		URL: "https://hub.example.com", Station: "meadow", Token: "0123456789abcdef",
+		Interval: Duration(30 * time.Second), BatchSize: 100,
+	}
+	tests := []struct {
+		name    string
+		modify  func(*HubSettings)
+		wantErr string
+	}{
+		{"valid", func(*HubSettings) {}, ""},
+		{"name with slash", func(s *HubSettings) { s.Stations[0].Name = "meadow/pi" }, "name must be"},
+		{"duplicate name", func(s *HubSettings) { s.Stations = append(s.Stations, station) }, "duplicate name"},
+		{"short token", func(s *HubSettings) { s.Stations[0].Token = "secret" }, "token must be"},
+		{"bad station url", func(s *HubSettings) { s.Stations[0].URL = "meadow.local" }, "url"},
+		{"disabled with invalid stations", func(s *HubSettings) { s.Enabled, s.Stations[0].Name = false, "" }, ""},
+		{"upstream without url", func(s *HubSettings) { s.Upstream.Enabled, s.Upstream.URL = true, "" }, "url"},
+		{"upstream interval too short", func(s *HubSettings) {
+			s.Upstream.Enabled, s.Upstream.Interval = true, Duration(time.Millisecond)
+		}, "interval"},
+		{"upstream batch too large", func(s *HubSettings) {
+			s.Upstream.Enabled, s.Upstream.BatchSize = true, MaxHubBatchSize+1
+		}, "batch"},
+		{"upstream valid", func(s *HubSettings) { s.Upstream.Enabled = true }, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			settings := HubSettings{Enabled: true, Stations: []HubStation{station}, Upstream: upstream}
+			tt.modify(&settings)
+			err := settings.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestRemoteInferenceSettings_Validate(t *testing.T) {
+	t.Parallel()
+
+	worker := RemoteWorker{URL: "http://server:8090", Token: "0123456789abcdef"}
+	tests := []struct {
+		name    string
+		modify  func(*RemoteInferenceSettings)
+		wantErr string
+	}{
+		{"valid", func(*RemoteInferenceSettings) {}, ""},
+		{"no workers", func(s *RemoteInferenceSettings) { s.Workers, s.Timeout = nil, 0 }, ""},
+		{"no timeout", func(s *RemoteInferenceSettings) { s.Timeout = 0 }, "timeout must be positive"},
+		{"not http", func(s *RemoteInferenceSettings) { s.Workers[0].URL = "rtsp://server:8090" }, "url must be"},
+		{"no host", func(s *RemoteInferenceSettings) { s.Workers[0].URL = "http://" }, "url must be"},
+		{"no token", func(s *RemoteInferenceSettings) { s.Workers[0].Token = " " }, "token is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			settings := RemoteInferenceSettings{Workers: []RemoteWorker{worker}, Timeout: Duration(5 * time.Second), Fallback: true}
+			tt.modify(&settings)
+			err := settings.Validate()
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), tt.wantErr)
+		})
+	}
+}
+
+func TestInferenceWorkerSettings_Validate(t *testing.T) {
+	t.Parallel()
+
+	valid := InferenceWorkerSettings{Listen: ":8090", Token: "0123456789abcdef"}
+	require.NoError(t, valid.Validate())
+
+	short := valid
+	short.Token = "secret"
+	require.ErrorContains(t, short.Validate(), "token must be at least")
+
+	noKey := valid
+	noKey.CertFile = "worker.crt"
+	require.ErrorContains(t, noKey.Validate(), "must be set together")
+
+	noListen := valid
+	noListen.Listen = ""
+	require.Error(t, noListen.Validate())
+}
+
+func TestParseSize(t *testing.T) {
+	t.Parallel()
+
+	for input, want := range map[string]int64{"20GB": 20 << 30, "512 mb": 512 << 20, "1.5KB": 1536, "100": 100} {
+		got, err := ParseSize(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	_, err := ParseSize("-1GB")
+	require.Error(t, err)
+}
+
 func TestValidateSoundLevelSettingsErrorMessage(t *testing.T) {
 	settings := &SoundLevelSettings{
 		Enabled:  true,
@@ -185,3 +432,49 @@ func BenchmarkValidateSoundLevelSettingsWithError(b *testing.B) {
 		_ = validateSoundLevelSettings(settings)
 	}
 }
+
+func TestValidateDigestSettings(t *testing.T) {
+	t.Parallel()
+
+	valid := func() *NotificationConfig {
+		return &NotificationConfig{
+			Digest: DigestSettings{
+				Daily:      DigestScheduleSettings{Enabled: true, Time: "21:00"},
+				Weekly:     DigestScheduleSettings{Enabled: true, Time: "08:30", Weekday: "Sunday"},
+				TopSpecies: 5,
+				Format:     DigestFormatMarkdown,
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		mutate         func(n *NotificationConfig)
+		wantValidation string
+	}{
+		{"valid", func(n *NotificationConfig) {}, ""},
+		{"disabled schedules skip validation", func(n *NotificationConfig) {
+			n.Digest = DigestSettings{Daily: DigestScheduleSettings{Time: "bogus"}}
+		}, ""},
+		{"invalid daily time", func(n *NotificationConfig) { n.Digest.Daily.Time = "9pm" }, "notification-digest-time"},
+		{"invalid weekday", func(n *NotificationConfig) { n.Digest.Weekly.Weekday = "funday" }, "notification-digest-weekday"},
+		{"invalid format", func(n *NotificationConfig) { n.Digest.Format = "pdf" }, "notification-digest-format"},
+		{"top species out of range", func(n *NotificationConfig) { n.Digest.TopSpecies = 0 }, "notification-digest-top-species"},
+		{"invalid template", func(n *NotificationConfig) { n.Templates.Digest.Message = "{{.Total" }, "notification-digest-template"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			n := valid()
+			tt.mutate(n)
+			err := validateDigestSettings(n)
+			if tt.wantValidation == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assertValidationError(t, err, tt.wantValidation)
+		})
+	}
+}
@@ -132,6 +132,12 @@ func (ds *DataStore) GetSpeciesSummaryData(ctx context.Context, startDate, endDa
 		args = append(args, endDate)
 	}
 
+	// Limit to one station of a hub
+	if station := StationFromContext(ctx); station != "" {
+		whereClause += " AND notes.source_node = ?"
+		args = append(args, station)
+	}
+
 	// Complete the query
 	queryStr += whereClause + `
 		GROUP BY notes.scientific_name
@@ -285,6 +291,7 @@ func (ds *DataStore) GetHourlyAnalyticsData(ctx context.Context, date, species s
 	if species != "" {
 		query = query.Where("notes.scientific_name = ? OR notes.common_name = ?", species, species)
 	}
+	query = whereStation(ctx, query)
 
 	// Execute query
 	if err := query.Scan(&analytics).Error; err != nil {
@@ -300,6 +307,14 @@ func (ds *DataStore) GetHourlyAnalyticsData(ctx context.Context, date, species s
 	return analytics, nil
 }
 
+// whereStation limits a notes query to the station of the context, if any.
+func whereStation(ctx context.Context, query *gorm.DB) *gorm.DB {
+	if station := StationFromContext(ctx); station != "" {
+		return query.Where("notes.source_node = ?", station)
+	}
+	return query
+}
+
 // GetDailyAnalyticsData retrieves detection counts grouped by day
 func (ds *DataStore) GetDailyAnalyticsData(ctx context.Context, startDate, endDate, species string) ([]DailyAnalyticsData, error) {
 	var analytics []DailyAnalyticsData
@@ -326,6 +341,7 @@ func (ds *DataStore) GetDailyAnalyticsData(ctx context.Context, startDate, endDa
 	if species != "" {
 		query = query.Where("notes.scientific_name = ? OR notes.common_name = ?", species, species)
 	}
+	query = whereStation(ctx, query)
 
 	// Execute query
 	if err := query.Scan(&analytics).Error; err != nil {
@@ -363,6 +379,7 @@ func (ds *DataStore) GetSpeciesDiversityData(ctx context.Context, startDate, end
 	case endDate != "":
 		query = query.Where("notes.date <= ?", endDate)
 	}
+	query = whereStation(ctx, query)
 
 	// Execute query
 	if err := query.Scan(&diversity).Error; err != nil {
@@ -525,6 +542,7 @@ func (ds *DataStore) GetHourlyDistribution(ctx context.Context, startDate, endDa
 		query = query.Where("notes.common_name = ? OR notes.scientific_name = ?",
 			species, species)
 	}
+	query = whereStation(ctx, query)
 
 	// Group by hour
 	query = query.Group(hourExpr)
@@ -1981,6 +1981,7 @@ type SearchFilters struct {
 	LockedOnly     bool
 	UnlockedOnly   bool
 	Device         string
+	Station        string // exact node name of a hub station
 	TimeOfDay      string // "any", "day", "night", "sunrise", "sunset"
 	Page           int
 	PerPage        int
@@ -2109,6 +2110,10 @@ func applyCommonFilters(query *gorm.DB, filters *SearchFilters, ds *DataStore) *
 		query = query.Where("notes.source_node LIKE ?", "%"+filters.Device+"%")
 	}
 
+	if filters.Station != "" {
+		query = query.Where("notes.source_node = ?", filters.Station)
+	}
+
 	return query
 }
 
@@ -131,6 +131,9 @@ func calculateFilterComplexity(filters *SearchFilters) float64 {
 	if filters.Device != "" {
 		complexity += 1
 	}
+	if filters.Station != "" {
+		complexity += 1
+	}
 	if filters.TimeOfDay != "" && filters.TimeOfDay != "any" {
 		complexity += 2 // Time-based filters are more complex
 	}
@@ -236,6 +239,9 @@ func getAppliedFilters(filters *SearchFilters) map[string]any {
 	if filters.Device != "" {
 		applied["device"] = filters.Device
 	}
+	if filters.Station != "" {
+		applied["station"] = filters.Station
+	}
 	if filters.TimeOfDay != "" && filters.TimeOfDay != "any" {
 		applied["time_of_day"] = filters.TimeOfDay
 	}
@@ -0,0 +1,24 @@
+package datastore
+
+import "context"
+
+// stationKey is the context key of the station analytics are limited to
+type stationKey struct{}
+
+// WithStation returns a context that limits analytics queries to the
+// detections of one station of a multi-station hub. A station is identified
+// by the node name its detections are stored with. An empty name queries all
+// stations.
+func WithStation(ctx context.Context, station string) context.Context {
+	if station == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, stationKey{}, station)
+}
+
+// StationFromContext returns the station analytics queries are limited to,
+// empty for all stations.
+func StationFromContext(ctx context.Context) string {
+	station, _ := ctx.Value(stationKey{}).(string)
+	return station
+}
@@ -1,7 +1,9 @@
 package entities
 
+import "regexp"
+
 // AlertCondition defines a single condition within an alert rule.
-// All conditions in a rule use AND logic.
+// Top-level conditions are ANDed; group rows add OR and NOT logic.
 type AlertCondition struct {
 	ID          uint   `gorm:"primaryKey" json:"id"`
 	RuleID      uint   `gorm:"not null;index" json:"rule_id"`
@@ -10,6 +12,16 @@ type AlertCondition struct {
 	Value       string `gorm:"size:500;not null" json:"value"`
 	DurationSec int    `gorm:"default:0" json:"duration_sec"`
 	SortOrder   int    `gorm:"default:0" json:"sort_order"`
+	// Condition groups: a row with Logic set ("and", "or", "not") is a group
+	// whose members reference its NodeKey via ParentKey. Rows with ParentKey 0
+	// are top-level and ANDed together, so flat condition lists keep working.
+	Logic     string `gorm:"size:3;default:''" json:"logic,omitempty"`
+	NodeKey   int    `gorm:"default:0" json:"node_key,omitempty"`
+	ParentKey int    `gorm:"default:0" json:"parent_key,omitempty"`
+
+	// Pattern is the compiled Value of a "matches" condition, set when the
+	// alerting engine loads its rules.
+	Pattern *regexp.Regexp `gorm:"-" json:"-"`
 }
 
 // TableName returns the table name for GORM.
@@ -3,14 +3,20 @@ package entities
 import "time"
 
 // AlertHistory records each time an alert rule fires.
+// Stateful alerts also track their lifecycle: firing, acknowledged, resolved.
+// Firings of rules without a lifecycle leave Status empty.
 type AlertHistory struct {
-	ID        uint      `gorm:"primaryKey" json:"id"`
-	RuleID    uint      `gorm:"not null;index:idx_alert_history_rule_fired,priority:1" json:"rule_id"`
-	FiredAt   time.Time `gorm:"not null;index:idx_alert_history_rule_fired,priority:2" json:"fired_at"`
-	EventData string    `gorm:"type:text;default:''" json:"event_data"`
-	Actions   string    `gorm:"type:text;default:''" json:"actions"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	Rule      AlertRule `gorm:"foreignKey:RuleID;constraint:OnDelete:CASCADE" json:"rule,omitzero"`
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	RuleID         uint       `gorm:"not null;index:idx_alert_history_rule_fired,priority:1" json:"rule_id"`
+	FiredAt        time.Time  `gorm:"not null;index:idx_alert_history_rule_fired,priority:2" json:"fired_at"`
+	EventData      string     `gorm:"type:text;default:''" json:"event_data"`
+	Actions        string     `gorm:"type:text;default:''" json:"actions"`
+	Status         string     `gorm:"size:20;default:'';index" json:"status"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	EscalatedAt    *time.Time `json:"escalated_at,omitempty"`
+	ResolvedAt     *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	Rule           AlertRule  `gorm:"foreignKey:RuleID;constraint:OnDelete:CASCADE" json:"rule,omitzero"`
 }
 
 // TableName returns the table name for GORM.
@@ -2,10 +2,15 @@ package entities
 
 // DetectionPrediction stores additional predictions for a detection.
 // This replaces the legacy 'results' table.
+//
+// Predictions of the original analysis have JobID 0 and hold only the
+// secondary predictions. A re-analysis job stores its top predictions with
+// its own JobID, ranked from 1 for its best prediction.
 type DetectionPrediction struct {
 	ID          uint    `gorm:"primaryKey"`
-	DetectionID uint    `gorm:"not null;index;uniqueIndex:idx_pred_detection_label"`
-	LabelID     uint    `gorm:"not null;uniqueIndex:idx_pred_detection_label"`
+	DetectionID uint    `gorm:"not null;index;uniqueIndex:idx_pred_detection_job_label"`
+	JobID       uint    `gorm:"not null;default:0;uniqueIndex:idx_pred_detection_job_label"` // re-analysis job, 0 for the original analysis
+	LabelID     uint    `gorm:"not null;uniqueIndex:idx_pred_detection_job_label"`
 	Confidence  float64 `gorm:"not null"`
 	Rank        int     `gorm:"not null;default:1"` // 1 = second best, 2 = third best, etc. (rank 0 / primary is stored in Detection.LabelID)
 
@@ -18,3 +23,8 @@ type DetectionPrediction struct {
 func (DetectionPrediction) TableName() string {
 	return "detection_predictions"
 }
+
+// LegacyPredictionIndex is the unique index on (detection_id, label_id) that
+// predated re-analysis jobs. Schema initialization drops it, a job may predict
+// the labels of the original analysis again.
+const LegacyPredictionIndex = "idx_pred_detection_label"
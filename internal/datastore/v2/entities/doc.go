@@ -14,11 +14,22 @@
 // # Detection Entities
 //
 //   - Detection: Main detection table with foreign keys (replaces 'notes')
-//   - DetectionPrediction: Additional predictions per detection (replaces 'results')
+//   - DetectionPrediction: Additional predictions per detection (replaces 'results'),
+//     and the predictions of re-analysis jobs
 //   - DetectionReview: Verification status
 //   - DetectionComment: User comments
 //   - DetectionLock: Lock status
 //
+// # Re-analysis
+//
+//   - ReanalysisJob: Inference run again over stored clips with a chosen model
+//   - ReanalysisFlag: Detections a re-analysis disagrees with, for review
+//
+// # Multi-Station Hub
+//
+//   - Station: Stations pushing detections to the hub and their sync state
+//   - StationDetection: Detections received from each station, for idempotent sync
+//
 // # Migration
 //
 //   - MigrationState: Tracks migration progress (singleton table)
@@ -0,0 +1,31 @@
+package entities
+
+import "time"
+
+// Notification persists a notification shown in the UI notification bell so that
+// history, unread counts and acknowledgements survive restarts.
+// Map fields (metadata and translation parameters) are stored as JSON text.
+type Notification struct {
+	ID            string     `gorm:"primaryKey;size:36" json:"id"`
+	Type          string     `gorm:"size:20;not null;index" json:"type"`
+	Priority      string     `gorm:"size:20;not null;index" json:"priority"`
+	Status        string     `gorm:"size:20;not null;index:idx_notifications_status_toast,priority:1" json:"status"`
+	IsToast       bool       `gorm:"not null;default:false;index:idx_notifications_status_toast,priority:2" json:"is_toast"`
+	Title         string     `gorm:"size:500;not null" json:"title"`
+	Message       string     `gorm:"type:text" json:"message"`
+	Component     string     `gorm:"size:100;default:'';index" json:"component"`
+	Timestamp     time.Time  `gorm:"not null;index" json:"timestamp"`
+	ExpiresAt     *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	Metadata      string     `gorm:"type:text" json:"metadata"`
+	TitleKey      string     `gorm:"size:200;default:''" json:"title_key"`
+	TitleParams   string     `gorm:"type:text" json:"title_params"`
+	MessageKey    string     `gorm:"size:200;default:''" json:"message_key"`
+	MessageParams string     `gorm:"type:text" json:"message_params"`
+	CreatedAt     time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt     time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for GORM.
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,82 @@
+package entities
+
+import "time"
+
+// ReanalysisStatus is the state of a re-analysis job.
+type ReanalysisStatus string
+
+const (
+	ReanalysisPending   ReanalysisStatus = "pending"
+	ReanalysisRunning   ReanalysisStatus = "running"
+	ReanalysisCompleted ReanalysisStatus = "completed"
+	ReanalysisFailed    ReanalysisStatus = "failed"
+	ReanalysisCancelled ReanalysisStatus = "cancelled"
+)
+
+// ReanalysisReason tells why a re-analysis disagrees with a detection.
+type ReanalysisReason string
+
+const (
+	// ReanalysisLabelChanged means another species is the confident top prediction.
+	ReanalysisLabelChanged ReanalysisReason = "label_changed"
+	// ReanalysisBelowThreshold means the detected species fell below the job threshold.
+	ReanalysisBelowThreshold ReanalysisReason = "below_threshold"
+)
+
+// ReanalysisJob runs inference again over the stored clips of detections,
+// with a chosen model and sensitivity. Its predictions are stored in
+// detection_predictions under the job ID.
+type ReanalysisJob struct {
+	ID          uint             `gorm:"primaryKey" json:"id"`
+	Status      ReanalysisStatus `gorm:"type:varchar(20);not null;index" json:"status"`
+	ModelPath   string           `gorm:"type:varchar(500)" json:"model_path,omitempty"` // custom model file, empty for the configured model
+	LabelPath   string           `gorm:"type:varchar(500)" json:"label_path,omitempty"` // labels of a custom model
+	ModelID     *uint            `json:"model_id,omitempty"`                            // ai_models entry, set once the model is loaded
+	Sensitivity float64          `gorm:"not null" json:"sensitivity"`
+	Threshold   float64          `gorm:"not null" json:"threshold"` // confidence the new top prediction needs to count
+
+	// Detection filters, empty matches everything
+	StartDate string `gorm:"type:varchar(10)" json:"start_date,omitempty"` // YYYY-MM-DD
+	EndDate   string `gorm:"type:varchar(10)" json:"end_date,omitempty"`   // YYYY-MM-DD
+	Species   string `gorm:"type:varchar(200)" json:"species,omitempty"`   // scientific name or species code
+	Source    string `gorm:"type:varchar(100)" json:"source,omitempty"`    // node name
+
+	// Progress
+	Total           int64  `gorm:"not null;default:0" json:"total"`
+	Processed       int64  `gorm:"not null;default:0" json:"processed"`
+	Skipped         int64  `gorm:"not null;default:0" json:"skipped"` // detections without a readable clip
+	Disagreements   int64  `gorm:"not null;default:0" json:"disagreements"`
+	LastDetectionID uint   `gorm:"not null;default:0" json:"last_detection_id"` // a restarted job resumes after it
+	Error           string `gorm:"type:varchar(500)" json:"error,omitempty"`
+
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// TableName returns the table name for GORM.
+func (ReanalysisJob) TableName() string {
+	return "reanalysis_jobs"
+}
+
+// ReanalysisFlag marks a detection whose re-analysis disagrees with it, for review.
+type ReanalysisFlag struct {
+	ID                uint             `gorm:"primaryKey" json:"id"`
+	JobID             uint             `gorm:"not null;uniqueIndex:idx_reanalysis_flag_job_detection" json:"job_id"`
+	DetectionID       uint             `gorm:"not null;uniqueIndex:idx_reanalysis_flag_job_detection;index" json:"detection_id"`
+	Reason            ReanalysisReason `gorm:"type:varchar(20);not null" json:"reason"`
+	ScientificName    string           `gorm:"type:varchar(200);not null" json:"scientific_name"` // species of the detection
+	Confidence        float64          `gorm:"not null" json:"confidence"`                        // confidence of the detection
+	NewConfidence     float64          `gorm:"not null" json:"new_confidence"`                    // confidence of the detected species in the job
+	TopScientificName string           `gorm:"type:varchar(200)" json:"top_scientific_name"`      // best prediction of the job
+	TopConfidence     float64          `json:"top_confidence"`
+	CreatedAt         time.Time        `gorm:"autoCreateTime" json:"created_at"`
+
+	// Relationship
+	Job *ReanalysisJob `gorm:"foreignKey:JobID;constraint:OnDelete:CASCADE,OnUpdate:CASCADE" json:"-"`
+}
+
+// TableName returns the table name for GORM.
+func (ReanalysisFlag) TableName() string {
+	return "reanalysis_flags"
+}
@@ -0,0 +1,40 @@
+package entities
+
+import "time"
+
+// Station is a BirdNET-Go node that pushes its detections to this hub.
+// Detections of a station are stored with its name as node name, the station
+// keeps the state syncing resumes from after an outage.
+type Station struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	Name         string     `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	LastRemoteID uint       `gorm:"not null;default:0" json:"last_remote_id"` // highest detection ID of the station the hub stored
+	Detections   int64      `gorm:"not null;default:0" json:"detections"`     // detections received from the station
+	Version      string     `gorm:"size:50" json:"version,omitempty"`         // BirdNET-Go version of the station at its last sync
+	LastSyncAt   *time.Time `json:"last_sync_at,omitempty"`
+	CreatedAt    time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// TableName returns the table name for GORM.
+func (Station) TableName() string {
+	return "stations"
+}
+
+// StationDetection records a detection a station pushed, so a detection sent
+// again after an interrupted sync is not stored twice and a detection on the
+// hub can be traced back to the station.
+type StationDetection struct {
+	StationID   uint      `gorm:"primaryKey;autoIncrement:false"`
+	RemoteID    uint      `gorm:"primaryKey;autoIncrement:false"` // detection ID on the station
+	DetectionID uint      `gorm:"not null;index"`                 // detection ID on the hub
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+
+	// Relationship
+	Station *Station `gorm:"foreignKey:StationID;constraint:OnDelete:CASCADE,OnUpdate:CASCADE"`
+}
+
+// TableName returns the table name for GORM.
+func (StationDetection) TableName() string {
+	return "station_detections"
+}
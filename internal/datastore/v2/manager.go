@@ -141,11 +141,23 @@ func (m *SQLiteManager) Initialize() error {
 		&entities.AlertCondition{},
 		&entities.AlertAction{},
 		&entities.AlertHistory{},
+		// Notification bell history
+		&entities.Notification{},
+		// Multi-station hub
+		&entities.Station{},
+		&entities.StationDetection{},
+		// Re-analysis jobs
+		&entities.ReanalysisJob{},
+		&entities.ReanalysisFlag{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate v2 schema: %w", err)
 	}
 
+	if err := dropLegacyPredictionIndex(m.db); err != nil {
+		return fmt.Errorf("failed to drop legacy prediction index: %w", err)
+	}
+
 	// Fix SQLite foreign key constraints that GORM's AutoMigrate may not handle correctly.
 	// SQLite requires ON DELETE clauses to be defined at table creation time.
 	if err := m.fixSQLiteForeignKeys(); err != nil {
@@ -167,6 +179,17 @@ func (m *SQLiteManager) Initialize() error {
 	return m.seedDefaultModel()
 }
 
+// dropLegacyPredictionIndex removes the unique (detection_id, label_id) index
+// of databases created before re-analysis jobs. AutoMigrate only adds the index
+// that includes the job ID, it never drops the old one.
+func dropLegacyPredictionIndex(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasIndex(&entities.DetectionPrediction{}, entities.LegacyPredictionIndex) {
+		return nil
+	}
+	return migrator.DropIndex(&entities.DetectionPrediction{}, entities.LegacyPredictionIndex)
+}
+
 // fixSQLiteForeignKeys ensures ON DELETE SET NULL behavior for SQLite.
 // GORM's AutoMigrate doesn't always correctly apply ON DELETE clauses for SQLite.
 // We use a trigger-based approach which is idempotent and doesn't conflict with GORM.
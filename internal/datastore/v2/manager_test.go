@@ -116,6 +116,25 @@ func TestSQLiteManager_Initialize_Idempotent(t *testing.T) {
 	assert.Equal(t, int64(1), count)
 }
 
+func TestSQLiteManager_Initialize_DropsLegacyPredictionIndex(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	mgr, err := NewSQLiteManager(Config{DataDir: tmpDir})
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = mgr.Close() })
+
+	require.NoError(t, mgr.Initialize())
+
+	// Recreate the index of databases predating re-analysis jobs
+	db := mgr.DB()
+	require.NoError(t, db.Exec("CREATE UNIQUE INDEX "+entities.LegacyPredictionIndex+
+		" ON detection_predictions (detection_id, label_id)").Error)
+
+	require.NoError(t, mgr.Initialize())
+	assert.False(t, db.Migrator().HasIndex(&entities.DetectionPrediction{}, entities.LegacyPredictionIndex))
+	assert.True(t, db.Migrator().HasIndex(&entities.DetectionPrediction{}, "idx_pred_detection_job_label"))
+}
+
 func TestSQLiteManager_Exists(t *testing.T) {
 	tmpDir := t.TempDir()
 	configuredPath := filepath.Join(tmpDir, "birdnet.db")
@@ -116,11 +116,23 @@ func (m *MySQLManager) Initialize() error {
 		&entities.AlertCondition{},
 		&entities.AlertAction{},
 		&entities.AlertHistory{},
+		// Notification bell history
+		&entities.Notification{},
+		// Multi-station hub
+		&entities.Station{},
+		&entities.StationDetection{},
+		// Re-analysis jobs
+		&entities.ReanalysisJob{},
+		&entities.ReanalysisFlag{},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to migrate v2 schema: %w", err)
 	}
 
+	if err := dropLegacyPredictionIndex(m.db); err != nil {
+		return fmt.Errorf("failed to drop legacy prediction index: %w", err)
+	}
+
 	// Initialize migration state singleton using FirstOrCreate to handle race conditions
 	state := entities.MigrationState{ID: 1, State: entities.MigrationStatusIdle}
 	if err := m.db.FirstOrCreate(&state, entities.MigrationState{ID: 1}).Error; err != nil {
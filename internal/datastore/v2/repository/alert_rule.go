@@ -27,6 +27,12 @@ type AlertRuleRepository interface {
 	DeleteHistory(ctx context.Context) (int64, error)
 	DeleteHistoryBefore(ctx context.Context, before time.Time) (int64, error)
 
+	// Alert lifecycle
+	ListOpenAlerts(ctx context.Context) ([]entities.AlertHistory, error)
+	AcknowledgeAlert(ctx context.Context, id uint, at time.Time) error
+	MarkAlertEscalated(ctx context.Context, id uint, at time.Time) error
+	ResolveAlert(ctx context.Context, id uint, at time.Time) error
+
 	// Import/Export
 	CountRulesByName(ctx context.Context, name string) (int64, error)
 }
@@ -39,6 +45,13 @@ type AlertRuleFilter struct {
 	BuiltIn     *bool
 }
 
+// Alert lifecycle states stored in AlertHistory.Status.
+const (
+	AlertStatusFiring       = "firing"
+	AlertStatusAcknowledged = "acknowledged"
+	AlertStatusResolved     = "resolved"
+)
+
 // AlertHistoryFilter controls history listing queries.
 type AlertHistoryFilter struct {
 	RuleID uint
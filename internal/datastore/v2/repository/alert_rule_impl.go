@@ -185,6 +185,61 @@ func (r *alertRuleRepository) DeleteHistoryBefore(ctx context.Context, before ti
 	return result.RowsAffected, nil
 }
 
+// ListOpenAlerts returns firing and acknowledged alerts, newest first.
+func (r *alertRuleRepository) ListOpenAlerts(ctx context.Context) ([]entities.AlertHistory, error) {
+	var items []entities.AlertHistory
+	err := r.db.WithContext(ctx).Preload("Rule").
+		Where("status IN ?", []string{AlertStatusFiring, AlertStatusAcknowledged}).
+		Order("fired_at DESC").
+		Find(&items).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list open alerts: %w", err)
+	}
+	return items, nil
+}
+
+// AcknowledgeAlert marks a firing alert as acknowledged.
+func (r *alertRuleRepository) AcknowledgeAlert(ctx context.Context, id uint, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entities.AlertHistory{}).
+		Where("id = ? AND status = ?", id, AlertStatusFiring).
+		Updates(map[string]any{"status": AlertStatusAcknowledged, "acknowledged_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("failed to acknowledge alert %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrOpenAlertNotFound
+	}
+	return nil
+}
+
+// MarkAlertEscalated records when an open alert was escalated.
+func (r *alertRuleRepository) MarkAlertEscalated(ctx context.Context, id uint, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entities.AlertHistory{}).
+		Where("id = ? AND status IN ?", id, []string{AlertStatusFiring, AlertStatusAcknowledged}).
+		Update("escalated_at", at)
+	if result.Error != nil {
+		return fmt.Errorf("failed to mark alert %d escalated: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrOpenAlertNotFound
+	}
+	return nil
+}
+
+// ResolveAlert marks an open alert as resolved.
+func (r *alertRuleRepository) ResolveAlert(ctx context.Context, id uint, at time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entities.AlertHistory{}).
+		Where("id = ? AND status IN ?", id, []string{AlertStatusFiring, AlertStatusAcknowledged}).
+		Updates(map[string]any{"status": AlertStatusResolved, "resolved_at": at})
+	if result.Error != nil {
+		return fmt.Errorf("failed to resolve alert %d: %w", id, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrOpenAlertNotFound
+	}
+	return nil
+}
+
 // CountRulesByName returns the number of rules with the given name.
 func (r *alertRuleRepository) CountRulesByName(ctx context.Context, name string) (int64, error) {
 	var count int64
@@ -108,6 +108,42 @@ func TestAlertRuleRepository_CreateAndGet(t *testing.T) {
 	assert.Equal(t, "New bird!", got.Actions[1].TemplateTitle)
 }
 
+func TestAlertRuleRepository_ConditionGroupsRoundTrip(t *testing.T) {
+	db := setupAlertTestDB(t)
+	repo := NewAlertRuleRepository(db)
+	ctx := t.Context()
+
+	rule := &entities.AlertRule{
+		Name:        "Night birds",
+		Enabled:     true,
+		ObjectType:  "detection",
+		TriggerType: "event",
+		EventName:   "detection.occurred",
+		Conditions: []entities.AlertCondition{
+			{Logic: "or", NodeKey: 1, SortOrder: 0},
+			{Property: "species_name", Operator: "contains", Value: "owl", ParentKey: 1, SortOrder: 1},
+			{Property: "species_name", Operator: "contains", Value: "nightjar", ParentKey: 1, SortOrder: 2},
+			{Property: "confidence", Operator: "greater_than", Value: "0.8", SortOrder: 3},
+		},
+		Actions: []entities.AlertAction{{Target: "bell"}},
+	}
+	require.NoError(t, repo.CreateRule(ctx, rule))
+
+	got, err := repo.GetRule(ctx, rule.ID)
+	require.NoError(t, err)
+	require.Len(t, got.Conditions, 4)
+	byOrder := make(map[int]entities.AlertCondition, len(got.Conditions))
+	for _, c := range got.Conditions {
+		byOrder[c.SortOrder] = c
+	}
+	assert.Equal(t, "or", byOrder[0].Logic)
+	assert.Equal(t, 1, byOrder[0].NodeKey)
+	assert.Equal(t, 1, byOrder[1].ParentKey)
+	assert.Equal(t, 1, byOrder[2].ParentKey)
+	assert.Zero(t, byOrder[3].ParentKey)
+	assert.Empty(t, byOrder[3].Logic)
+}
+
 func TestAlertRuleRepository_ListRules(t *testing.T) {
 	db := setupAlertTestDB(t)
 	repo := NewAlertRuleRepository(db)
@@ -390,3 +426,42 @@ func TestAlertRuleRepository_CountRulesByName(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, int64(0), count)
 }
+
+func TestAlertRuleRepository_AlertLifecycle(t *testing.T) {
+	db := setupAlertTestDB(t)
+	repo := NewAlertRuleRepository(db)
+	ctx := t.Context()
+
+	rule := createTestRule(t, repo, "Lifecycle", "stream", "event", "stream.disconnected")
+	now := time.Now()
+
+	open := &entities.AlertHistory{RuleID: rule.ID, FiredAt: now, Status: AlertStatusFiring}
+	require.NoError(t, repo.SaveHistory(ctx, open))
+	plain := &entities.AlertHistory{RuleID: rule.ID, FiredAt: now.Add(-time.Hour)}
+	require.NoError(t, repo.SaveHistory(ctx, plain))
+
+	items, err := repo.ListOpenAlerts(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1, "firings without a lifecycle are not open alerts")
+	assert.Equal(t, open.ID, items[0].ID)
+	assert.Equal(t, "Lifecycle", items[0].Rule.Name)
+
+	require.ErrorIs(t, repo.AcknowledgeAlert(ctx, plain.ID, now), ErrOpenAlertNotFound)
+
+	require.NoError(t, repo.AcknowledgeAlert(ctx, open.ID, now))
+	require.ErrorIs(t, repo.AcknowledgeAlert(ctx, open.ID, now), ErrOpenAlertNotFound, "already acknowledged")
+	require.NoError(t, repo.MarkAlertEscalated(ctx, open.ID, now))
+
+	items, err = repo.ListOpenAlerts(ctx)
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	assert.Equal(t, AlertStatusAcknowledged, items[0].Status)
+	require.NotNil(t, items[0].AcknowledgedAt)
+	require.NotNil(t, items[0].EscalatedAt)
+
+	require.NoError(t, repo.ResolveAlert(ctx, open.ID, now))
+	require.ErrorIs(t, repo.ResolveAlert(ctx, open.ID, now), ErrOpenAlertNotFound)
+	items, err = repo.ListOpenAlerts(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, items)
+}
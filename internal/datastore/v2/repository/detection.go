@@ -143,10 +143,10 @@ type DetectionRepository interface {
 	// Used during migration for bulk operations.
 	SavePredictionsBatch(ctx context.Context, preds []*entities.DetectionPrediction) error
 
-	// GetPredictions retrieves all predictions for a detection.
+	// GetPredictions retrieves the predictions of the original analysis of a detection.
 	GetPredictions(ctx context.Context, detectionID uint) ([]*entities.DetectionPrediction, error)
 
-	// DeletePredictions removes all predictions for a detection.
+	// DeletePredictions removes the predictions of the original analysis of a detection.
 	DeletePredictions(ctx context.Context, detectionID uint) error
 
 	// === Reviews ===
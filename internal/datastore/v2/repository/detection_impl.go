@@ -7,6 +7,7 @@ import (
 	"slices"
 	"time"
 
+	"github.com/tphakala/birdnet-go/internal/datastore"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"gorm.io/gorm"
@@ -23,6 +24,8 @@ const (
 	SortFieldSpecies = "species"
 	// SortFieldStatus allows sorting by verification status (requires JOIN on detection_reviews).
 	SortFieldStatus = "status"
+	// SortFieldID sorts by detection ID, required by cursor-based pagination.
+	SortFieldID = "id"
 )
 
 // defaultDBBatchSize is the batch size for bulk database operations.
@@ -627,6 +630,8 @@ func (r *detectionRepository) applySearchOrdering(query *gorm.DB, filters *Searc
 
 	// Sorting
 	switch filters.SortBy {
+	case SortFieldID:
+		query = query.Order(r.tableName() + ".id" + dir)
 	case SortFieldConfidence:
 		query = query.Order("confidence" + dir)
 	case SortFieldSpecies:
@@ -997,20 +1002,21 @@ func (r *detectionRepository) SavePredictionsBatch(ctx context.Context, preds []
 		CreateInBatches(preds, defaultDBBatchSize).Error
 }
 
-// GetPredictions retrieves all predictions for a detection.
+// GetPredictions retrieves the predictions of the original analysis of a detection.
 func (r *detectionRepository) GetPredictions(ctx context.Context, detectionID uint) ([]*entities.DetectionPrediction, error) {
 	var preds []*entities.DetectionPrediction
 	err := r.db.WithContext(ctx).Table(r.predictionsTable()).
-		Where("detection_id = ?", detectionID).
+		Where("detection_id = ? AND job_id = 0", detectionID).
 		Order("rank ASC").
 		Find(&preds).Error
 	return preds, err
 }
 
-// DeletePredictions removes all predictions for a detection.
+// DeletePredictions removes the predictions of the original analysis of a detection.
+// Predictions of re-analysis jobs are kept.
 func (r *detectionRepository) DeletePredictions(ctx context.Context, detectionID uint) error {
 	return r.db.WithContext(ctx).Table(r.predictionsTable()).
-		Where("detection_id = ?", detectionID).
+		Where("detection_id = ? AND job_id = 0", detectionID).
 		Delete(&entities.DetectionPrediction{}).Error
 }
 
@@ -1337,6 +1343,7 @@ func (r *detectionRepository) GetSpeciesSummary(ctx context.Context, start, end
 	if modelID != nil {
 		query = query.Where(fmt.Sprintf("%s.model_id = ?", detTable), *modelID)
 	}
+	query = r.whereStation(ctx, query, detTable)
 
 	err := query.Group(fmt.Sprintf("%s.scientific_name", labTable)).
 		Order("total_detections DESC").
@@ -1362,7 +1369,17 @@ func (r *detectionRepository) buildAnalyticsBaseQuery(ctx context.Context, start
 		query = query.Where("d.model_id = ?", *modelID)
 	}
 
-	return query
+	return r.whereStation(ctx, query, "d")
+}
+
+// whereStation limits a detections query to the station of the context, if
+// any. Detections of a station reference audio sources with its node name.
+func (r *detectionRepository) whereStation(ctx context.Context, query *gorm.DB, detAlias string) *gorm.DB {
+	station := datastore.StationFromContext(ctx)
+	if station == "" {
+		return query
+	}
+	return query.Where(fmt.Sprintf("%s.source_id IN (SELECT id FROM %s WHERE node_name = ?)", detAlias, r.sourcesTable()), station)
 }
 
 // GetHourlyDistribution returns detection counts by hour.
@@ -70,4 +70,16 @@ var (
 
 	// ErrAlertRuleNotFound indicates the requested alert rule does not exist.
 	ErrAlertRuleNotFound = errors.NewStd("alert rule not found")
+
+	// ErrOpenAlertNotFound indicates no open alert with the requested ID exists.
+	ErrOpenAlertNotFound = errors.NewStd("open alert not found")
+
+	// ErrNotificationNotFound indicates the requested notification does not exist.
+	ErrNotificationNotFound = errors.NewStd("notification not found")
+
+	// ErrStationNotFound indicates the requested hub station does not exist.
+	ErrStationNotFound = errors.NewStd("station not found")
+
+	// ErrReanalysisJobNotFound indicates the requested re-analysis job does not exist.
+	ErrReanalysisJobNotFound = errors.NewStd("reanalysis job not found")
 )
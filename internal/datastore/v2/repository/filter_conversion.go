@@ -466,6 +466,27 @@ func ResolveDeviceToSourceIDs(ctx context.Context, deps *FilterLookupDeps, devic
 	return sourceIDs, nil
 }
 
+// intersectSourceIDs returns the source IDs in both filters. A nil filter
+// matches every source; an empty intersection yields the no-match sentinel.
+func intersectSourceIDs(a, b []uint) []uint {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	var ids []uint
+	for _, id := range a {
+		if id != 0 && slices.Contains(b, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return sentinelNoMatchIDs
+	}
+	return ids
+}
+
 // ConvertSearchFilters converts API-level SearchFilters to repository SearchFilters.
 // This handles the conversion from the /api/v2/search endpoint filters.
 //
@@ -586,6 +607,15 @@ func ConvertSearchFilters(
 		if err != nil {
 			return nil, err
 		}
+
+		// Narrow to the sources of a hub station
+		if filters.Station != "" {
+			stationIDs, err := ResolveLocationsToSourceIDs(ctx, deps, []string{filters.Station})
+			if err != nil {
+				return nil, err
+			}
+			sf.AudioSourceIDs = intersectSourceIDs(sf.AudioSourceIDs, stationIDs)
+		}
 	}
 
 	return sf, nil
@@ -650,6 +680,12 @@ func ConvertAdvancedFilters(
 		// sf.SortDesc already set from !filters.SortAscending above
 	}
 
+	// An ID cursor only visits every record when sorting by ID
+	if filters.CursorPagination {
+		sf.SortBy = SortFieldID
+		sf.SortDesc = false
+	}
+
 	// Time conversions
 	sf.StartTime, sf.EndTime = DateRangeToUnix(filters.DateRange, tz)
 
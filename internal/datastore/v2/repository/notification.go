@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// NotificationRepository handles persisted UI notifications.
+type NotificationRepository interface {
+	SaveNotification(ctx context.Context, n *entities.Notification) error
+	GetNotification(ctx context.Context, id string) (*entities.Notification, error)
+	ListNotifications(ctx context.Context, filter NotificationFilter) ([]entities.Notification, error)
+	UpdateNotification(ctx context.Context, n *entities.Notification) error
+	DeleteNotification(ctx context.Context, id string) error
+
+	// DeleteExpiredNotifications removes notifications whose expiry is before the given time.
+	DeleteExpiredNotifications(ctx context.Context, before time.Time) (int64, error)
+	// TrimNotifications deletes the oldest notifications so that at most keep remain.
+	TrimNotifications(ctx context.Context, keep int) (int64, error)
+	// CountUnreadNotifications counts unread, non-toast notifications.
+	CountUnreadNotifications(ctx context.Context) (int64, error)
+}
+
+// NotificationFilter controls notification listing queries.
+// Toast notifications are always excluded from listings.
+type NotificationFilter struct {
+	Types      []string
+	Priorities []string
+	Statuses   []string
+	Component  string
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int
+	Offset     int
+}
@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+)
+
+// notificationStatusUnread mirrors notification.StatusUnread for unread counting.
+const notificationStatusUnread = "unread"
+
+// notificationRepository implements NotificationRepository.
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new NotificationRepository.
+func NewNotificationRepository(db *gorm.DB) NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+// SaveNotification inserts a notification, replacing any row with the same ID.
+func (r *notificationRepository) SaveNotification(ctx context.Context, n *entities.Notification) error {
+	if n.ID == "" {
+		return fmt.Errorf("failed to save notification: %w", ErrInvalidInput)
+	}
+	if err := r.db.WithContext(ctx).Save(n).Error; err != nil {
+		return fmt.Errorf("failed to save notification: %w", err)
+	}
+	return nil
+}
+
+// GetNotification returns a notification by ID.
+// Returns ErrNotificationNotFound if it does not exist.
+func (r *notificationRepository) GetNotification(ctx context.Context, id string) (*entities.Notification, error) {
+	var n entities.Notification
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&n).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification %s: %w", id, err)
+	}
+	return &n, nil
+}
+
+// ListNotifications returns non-toast notifications matching the filter, newest first.
+func (r *notificationRepository) ListNotifications(ctx context.Context, filter NotificationFilter) ([]entities.Notification, error) {
+	query := r.db.WithContext(ctx).Where("is_toast = ?", false)
+
+	if len(filter.Types) > 0 {
+		query = query.Where("type IN ?", filter.Types)
+	}
+	if len(filter.Priorities) > 0 {
+		query = query.Where("priority IN ?", filter.Priorities)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.Component != "" {
+		query = query.Where("component = ?", filter.Component)
+	}
+	if filter.Since != nil {
+		query = query.Where("timestamp >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("timestamp <= ?", *filter.Until)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+
+	var items []entities.Notification
+	if err := query.Order("timestamp DESC").Order("id ASC").Find(&items).Error; err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	return items, nil
+}
+
+// UpdateNotification replaces an existing notification.
+// Returns ErrNotificationNotFound if it does not exist.
+func (r *notificationRepository) UpdateNotification(ctx context.Context, n *entities.Notification) error {
+	result := r.db.WithContext(ctx).Model(&entities.Notification{}).
+		Where("id = ?", n.ID).
+		Select("*").
+		Omit("id", "created_at").
+		Updates(n)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update notification %s: %w", n.ID, result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
+
+// DeleteNotification deletes a notification by ID. Deleting a missing notification is not an error.
+func (r *notificationRepository) DeleteNotification(ctx context.Context, id string) error {
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.Notification{}).Error; err != nil {
+		return fmt.Errorf("failed to delete notification %s: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteExpiredNotifications deletes notifications whose expiry is before the given time.
+func (r *notificationRepository) DeleteExpiredNotifications(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Where("expires_at IS NOT NULL AND expires_at < ?", before).
+		Delete(&entities.Notification{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to delete expired notifications: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// TrimNotifications deletes the oldest notifications so that at most keep remain.
+func (r *notificationRepository) TrimNotifications(ctx context.Context, keep int) (int64, error) {
+	if keep <= 0 {
+		return 0, nil
+	}
+
+	var total int64
+	if err := r.db.WithContext(ctx).Model(&entities.Notification{}).Count(&total).Error; err != nil {
+		return 0, fmt.Errorf("failed to count notifications: %w", err)
+	}
+	excess := int(total) - keep
+	if excess <= 0 {
+		return 0, nil
+	}
+
+	// Resolve IDs first: MySQL does not support LIMIT in IN subqueries
+	var ids []string
+	if err := r.db.WithContext(ctx).Model(&entities.Notification{}).
+		Order("timestamp ASC").
+		Limit(excess).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, fmt.Errorf("failed to select notifications to trim: %w", err)
+	}
+
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&entities.Notification{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to trim notifications: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// CountUnreadNotifications counts unread, non-toast notifications using the status index.
+func (r *notificationRepository) CountUnreadNotifications(ctx context.Context) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.Notification{}).
+		Where("status = ? AND is_toast = ?", notificationStatusUnread, false).
+		Count(&count).Error; err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,241 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/notification"
+)
+
+// notificationStoreTimeout bounds each database call made through the
+// context-free notification.NotificationStore interface.
+const notificationStoreTimeout = 10 * time.Second
+
+// NotificationStore implements notification.NotificationStore on top of a
+// NotificationRepository so the notification bell history survives restarts.
+//
+// Metadata and translation parameters round-trip through JSON, so numeric
+// values are returned as float64 regardless of the type they were stored with.
+type NotificationStore struct {
+	repo    NotificationRepository
+	maxSize int
+}
+
+// Compile-time check that NotificationStore satisfies the notification store contract.
+var _ notification.NotificationStore = (*NotificationStore)(nil)
+
+// NewNotificationStore creates a persistent notification store.
+// maxSize caps the number of stored notifications; the oldest are trimmed
+// during expiry cleanup. A value <= 0 disables trimming.
+func NewNotificationStore(repo NotificationRepository, maxSize int) *NotificationStore {
+	return &NotificationStore{
+		repo:    repo,
+		maxSize: maxSize,
+	}
+}
+
+// Save persists a notification.
+func (s *NotificationStore) Save(n *notification.Notification) error {
+	entity, err := notificationToEntity(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notificationStoreTimeout)
+	defer cancel()
+	return s.repo.SaveNotification(ctx, entity)
+}
+
+// Get retrieves a notification by ID.
+func (s *NotificationStore) Get(id string) (*notification.Notification, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationStoreTimeout)
+	defer cancel()
+
+	entity, err := s.repo.GetNotification(ctx, id)
+	if errors.Is(err, ErrNotificationNotFound) {
+		return nil, notification.ErrNotificationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entityToNotification(entity), nil
+}
+
+// List returns notifications matching the filter, newest first.
+func (s *NotificationStore) List(filter *notification.FilterOptions) ([]*notification.Notification, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationStoreTimeout)
+	defer cancel()
+
+	items, err := s.repo.ListNotifications(ctx, notificationFilterFromOptions(filter))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*notification.Notification, 0, len(items))
+	for i := range items {
+		results = append(results, entityToNotification(&items[i]))
+	}
+	return results, nil
+}
+
+// Update modifies an existing notification.
+func (s *NotificationStore) Update(n *notification.Notification) error {
+	entity, err := notificationToEntity(n)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), notificationStoreTimeout)
+	defer cancel()
+
+	err = s.repo.UpdateNotification(ctx, entity)
+	if errors.Is(err, ErrNotificationNotFound) {
+		return notification.ErrNotificationNotFound
+	}
+	return err
+}
+
+// Delete removes a notification.
+func (s *NotificationStore) Delete(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationStoreTimeout)
+	defer cancel()
+	return s.repo.DeleteNotification(ctx, id)
+}
+
+// DeleteExpired removes expired notifications and trims the store to maxSize.
+func (s *NotificationStore) DeleteExpired() error {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationStoreTimeout)
+	defer cancel()
+
+	if _, err := s.repo.DeleteExpiredNotifications(ctx, time.Now()); err != nil {
+		return err
+	}
+	if _, err := s.repo.TrimNotifications(ctx, s.maxSize); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetUnreadCount returns the number of unread, non-toast notifications.
+func (s *NotificationStore) GetUnreadCount() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), notificationStoreTimeout)
+	defer cancel()
+
+	count, err := s.repo.CountUnreadNotifications(ctx)
+	return int(count), err
+}
+
+// notificationFilterFromOptions converts notification filter options to a repository filter.
+func notificationFilterFromOptions(filter *notification.FilterOptions) NotificationFilter {
+	if filter == nil {
+		return NotificationFilter{}
+	}
+
+	result := NotificationFilter{
+		Component: filter.Component,
+		Since:     filter.Since,
+		Until:     filter.Until,
+		Limit:     filter.Limit,
+		Offset:    filter.Offset,
+	}
+	for _, t := range filter.Types {
+		result.Types = append(result.Types, string(t))
+	}
+	for _, p := range filter.Priorities {
+		result.Priorities = append(result.Priorities, string(p))
+	}
+	for _, st := range filter.Status {
+		result.Statuses = append(result.Statuses, string(st))
+	}
+	return result
+}
+
+// notificationToEntity converts a notification to its database entity.
+func notificationToEntity(n *notification.Notification) (*entities.Notification, error) {
+	if n == nil {
+		return nil, ErrInvalidInput
+	}
+
+	metadata, err := marshalNotificationMap(n.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	titleParams, err := marshalNotificationMap(n.TitleParams)
+	if err != nil {
+		return nil, err
+	}
+	messageParams, err := marshalNotificationMap(n.MessageParams)
+	if err != nil {
+		return nil, err
+	}
+
+	isToast, _ := n.Metadata[notification.MetadataKeyIsToast].(bool)
+
+	return &entities.Notification{
+		ID:            n.ID,
+		Type:          string(n.Type),
+		Priority:      string(n.Priority),
+		Status:        string(n.Status),
+		IsToast:       isToast,
+		Title:         n.Title,
+		Message:       n.Message,
+		Component:     n.Component,
+		Timestamp:     n.Timestamp,
+		ExpiresAt:     n.ExpiresAt,
+		Metadata:      metadata,
+		TitleKey:      n.TitleKey,
+		TitleParams:   titleParams,
+		MessageKey:    n.MessageKey,
+		MessageParams: messageParams,
+	}, nil
+}
+
+// entityToNotification converts a database entity back to a notification.
+// Malformed JSON columns are dropped rather than failing the whole read.
+func entityToNotification(e *entities.Notification) *notification.Notification {
+	return &notification.Notification{
+		ID:            e.ID,
+		Type:          notification.Type(e.Type),
+		Priority:      notification.Priority(e.Priority),
+		Status:        notification.Status(e.Status),
+		Title:         e.Title,
+		Message:       e.Message,
+		Component:     e.Component,
+		Timestamp:     e.Timestamp,
+		ExpiresAt:     e.ExpiresAt,
+		Metadata:      unmarshalNotificationMap(e.Metadata),
+		TitleKey:      e.TitleKey,
+		TitleParams:   unmarshalNotificationMap(e.TitleParams),
+		MessageKey:    e.MessageKey,
+		MessageParams: unmarshalNotificationMap(e.MessageParams),
+	}
+}
+
+// marshalNotificationMap encodes a map as JSON, storing empty maps as an empty string.
+func marshalNotificationMap(m map[string]any) (string, error) {
+	if len(m) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return "", errors.New(err).
+			Component("datastore").
+			Category(errors.CategoryValidation).
+			Context("operation", "marshal_notification_map").
+			Build()
+	}
+	return string(data), nil
+}
+
+// unmarshalNotificationMap decodes a JSON map, returning nil for empty or invalid input.
+func unmarshalNotificationMap(data string) map[string]any {
+	if data == "" {
+		return nil
+	}
+	var m map[string]any
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		return nil
+	}
+	return m
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/notification"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+// setupNotificationTestStore creates a persistent notification store backed by
+// an in-memory SQLite database.
+func setupNotificationTestStore(t *testing.T, maxSize int) *NotificationStore {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open("file::memory:?cache=shared"), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err, "failed to open in-memory database")
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err, "failed to get sql.DB")
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(&entities.Notification{}), "failed to migrate notifications table")
+	return NewNotificationStore(NewNotificationRepository(db), maxSize)
+}
+
+func TestNotificationStore_RoundTrip(t *testing.T) {
+	store := setupNotificationTestStore(t, 0)
+
+	n := notification.NewNotification(notification.TypeWarning, notification.PriorityHigh, "Disk almost full", "90% used").
+		WithComponent("diskmanager").
+		WithMetadata("usage", 90).
+		WithTitleKey("notifications.disk.title", map[string]any{"path": "/data"})
+	require.NoError(t, store.Save(n))
+
+	got, err := store.Get(n.ID)
+	require.NoError(t, err)
+	assert.Equal(t, n.Title, got.Title)
+	assert.Equal(t, n.Message, got.Message)
+	assert.Equal(t, notification.TypeWarning, got.Type)
+	assert.Equal(t, notification.PriorityHigh, got.Priority)
+	assert.Equal(t, notification.StatusUnread, got.Status)
+	assert.Equal(t, "diskmanager", got.Component)
+	assert.InDelta(t, 90, got.Metadata["usage"], 0)
+	assert.Equal(t, "notifications.disk.title", got.TitleKey)
+	assert.Equal(t, "/data", got.TitleParams["path"])
+	assert.True(t, n.Timestamp.Equal(got.Timestamp))
+
+	got.MarkAsRead()
+	require.NoError(t, store.Update(got))
+	got, err = store.Get(n.ID)
+	require.NoError(t, err)
+	assert.Equal(t, notification.StatusRead, got.Status)
+
+	require.NoError(t, store.Delete(n.ID))
+	_, err = store.Get(n.ID)
+	require.ErrorIs(t, err, notification.ErrNotificationNotFound)
+}
+
+func TestNotificationStore_ListAndUnreadExcludeToasts(t *testing.T) {
+	store := setupNotificationTestStore(t, 0)
+
+	older := notification.NewNotification(notification.TypeInfo, notification.PriorityLow, "older", "")
+	older.Timestamp = time.Now().Add(-time.Hour)
+	newer := notification.NewNotification(notification.TypeError, notification.PriorityHigh, "newer", "")
+	toast := notification.NewNotification(notification.TypeInfo, notification.PriorityLow, "toast", "").
+		WithMetadata(notification.MetadataKeyIsToast, true)
+	for _, n := range []*notification.Notification{older, newer, toast} {
+		require.NoError(t, store.Save(n))
+	}
+
+	all, err := store.List(&notification.FilterOptions{})
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	assert.Equal(t, "newer", all[0].Title, "list should be ordered newest first")
+	assert.Equal(t, "older", all[1].Title)
+
+	errorsOnly, err := store.List(&notification.FilterOptions{Types: []notification.Type{notification.TypeError}})
+	require.NoError(t, err)
+	require.Len(t, errorsOnly, 1)
+	assert.Equal(t, "newer", errorsOnly[0].Title)
+
+	paged, err := store.List(&notification.FilterOptions{Limit: 1, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, paged, 1)
+	assert.Equal(t, "older", paged[0].Title)
+
+	unread, err := store.GetUnreadCount()
+	require.NoError(t, err)
+	assert.Equal(t, 2, unread)
+}
+
+func TestNotificationStore_DeleteExpiredTrimsToMaxSize(t *testing.T) {
+	store := setupNotificationTestStore(t, 2)
+
+	expired := notification.NewNotification(notification.TypeInfo, notification.PriorityLow, "expired", "").
+		WithExpiry(time.Millisecond)
+	expired.Timestamp = time.Now().Add(-time.Minute)
+	require.NoError(t, store.Save(expired))
+
+	base := time.Now().Add(-10 * time.Minute)
+	for i, title := range []string{"first", "second", "third"} {
+		n := notification.NewNotification(notification.TypeInfo, notification.PriorityLow, title, "")
+		n.Timestamp = base.Add(time.Duration(i) * time.Minute)
+		require.NoError(t, store.Save(n))
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	require.NoError(t, store.DeleteExpired())
+
+	remaining, err := store.List(&notification.FilterOptions{})
+	require.NoError(t, err)
+	require.Len(t, remaining, 2)
+	assert.Equal(t, "third", remaining[0].Title)
+	assert.Equal(t, "second", remaining[1].Title)
+}
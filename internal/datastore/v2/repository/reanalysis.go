@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// ReanalysisRepository handles re-analysis jobs and the detections they flag.
+type ReanalysisRepository interface {
+	// CreateJob stores a new job.
+	CreateJob(ctx context.Context, job *entities.ReanalysisJob) error
+	// GetJob returns a job by ID.
+	// Returns ErrReanalysisJobNotFound if it does not exist.
+	GetJob(ctx context.Context, id uint) (*entities.ReanalysisJob, error)
+	// ListJobs returns the most recent jobs, newest first.
+	ListJobs(ctx context.Context, limit int) ([]entities.ReanalysisJob, error)
+	// NextPendingJob returns the oldest pending job.
+	// Returns ErrReanalysisJobNotFound if no job is pending.
+	NextPendingJob(ctx context.Context) (*entities.ReanalysisJob, error)
+	// StartJob moves a pending job to running. Reports false if the job was no longer pending.
+	StartJob(ctx context.Context, id uint, at time.Time) (bool, error)
+	// UpdateJobProgress stores the model, counters and cursor of a running job.
+	UpdateJobProgress(ctx context.Context, job *entities.ReanalysisJob) error
+	// FinishJob moves a running job to its final status. A job cancelled meanwhile stays cancelled.
+	FinishJob(ctx context.Context, id uint, status entities.ReanalysisStatus, errMsg string, at time.Time) error
+	// CancelJob cancels a pending or running job. Reports false if the job had already ended.
+	CancelJob(ctx context.Context, id uint, at time.Time) (bool, error)
+	// RequeueRunningJobs moves jobs left running by a shutdown back to pending.
+	RequeueRunningJobs(ctx context.Context) (int64, error)
+
+	// SaveFlag records that a job disagrees with a detection. Saving the same flag again changes nothing.
+	SaveFlag(ctx context.Context, flag *entities.ReanalysisFlag) error
+	// ListFlags returns the detections a job disagrees with and their total count.
+	ListFlags(ctx context.Context, jobID uint, limit, offset int) ([]entities.ReanalysisFlag, int64, error)
+}
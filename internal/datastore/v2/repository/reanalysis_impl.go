@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// reanalysisRepository implements ReanalysisRepository.
+type reanalysisRepository struct {
+	db *gorm.DB
+}
+
+// NewReanalysisRepository creates a new ReanalysisRepository.
+func NewReanalysisRepository(db *gorm.DB) ReanalysisRepository {
+	return &reanalysisRepository{db: db}
+}
+
+// CreateJob stores a new job.
+func (r *reanalysisRepository) CreateJob(ctx context.Context, job *entities.ReanalysisJob) error {
+	if err := r.db.WithContext(ctx).Create(job).Error; err != nil {
+		return fmt.Errorf("failed to create reanalysis job: %w", err)
+	}
+	return nil
+}
+
+// GetJob returns a job by ID.
+func (r *reanalysisRepository) GetJob(ctx context.Context, id uint) (*entities.ReanalysisJob, error) {
+	var job entities.ReanalysisJob
+	if err := r.db.WithContext(ctx).First(&job, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReanalysisJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get reanalysis job %d: %w", id, err)
+	}
+	return &job, nil
+}
+
+// ListJobs returns the most recent jobs, newest first.
+func (r *reanalysisRepository) ListJobs(ctx context.Context, limit int) ([]entities.ReanalysisJob, error) {
+	var jobs []entities.ReanalysisJob
+	if err := r.db.WithContext(ctx).Order("id DESC").Limit(limit).Find(&jobs).Error; err != nil {
+		return nil, fmt.Errorf("failed to list reanalysis jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// NextPendingJob returns the oldest pending job.
+func (r *reanalysisRepository) NextPendingJob(ctx context.Context) (*entities.ReanalysisJob, error) {
+	var job entities.ReanalysisJob
+	if err := r.db.WithContext(ctx).Where("status = ?", entities.ReanalysisPending).
+		Order("id ASC").First(&job).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReanalysisJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get next reanalysis job: %w", err)
+	}
+	return &job, nil
+}
+
+// StartJob moves a pending job to running.
+func (r *reanalysisRepository) StartJob(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entities.ReanalysisJob{}).
+		Where("id = ? AND status = ?", id, entities.ReanalysisPending).
+		Updates(map[string]any{"status": entities.ReanalysisRunning, "started_at": at})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to start reanalysis job %d: %w", id, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// UpdateJobProgress stores the model, counters and cursor of a running job.
+func (r *reanalysisRepository) UpdateJobProgress(ctx context.Context, job *entities.ReanalysisJob) error {
+	if err := r.db.WithContext(ctx).Model(&entities.ReanalysisJob{}).Where("id = ?", job.ID).
+		Updates(map[string]any{
+			"model_id":          job.ModelID,
+			"total":             job.Total,
+			"processed":         job.Processed,
+			"skipped":           job.Skipped,
+			"disagreements":     job.Disagreements,
+			"last_detection_id": job.LastDetectionID,
+		}).Error; err != nil {
+		return fmt.Errorf("failed to update reanalysis job %d: %w", job.ID, err)
+	}
+	return nil
+}
+
+// FinishJob moves a running job to its final status.
+func (r *reanalysisRepository) FinishJob(ctx context.Context, id uint, status entities.ReanalysisStatus, errMsg string, at time.Time) error {
+	if len(errMsg) > 500 {
+		errMsg = errMsg[:500]
+	}
+	if err := r.db.WithContext(ctx).Model(&entities.ReanalysisJob{}).
+		Where("id = ? AND status = ?", id, entities.ReanalysisRunning).
+		Updates(map[string]any{"status": status, "error": errMsg, "completed_at": at}).Error; err != nil {
+		return fmt.Errorf("failed to finish reanalysis job %d: %w", id, err)
+	}
+	return nil
+}
+
+// CancelJob cancels a pending or running job.
+func (r *reanalysisRepository) CancelJob(ctx context.Context, id uint, at time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entities.ReanalysisJob{}).
+		Where("id = ? AND status IN ?", id, []entities.ReanalysisStatus{entities.ReanalysisPending, entities.ReanalysisRunning}).
+		Updates(map[string]any{"status": entities.ReanalysisCancelled, "completed_at": at})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to cancel reanalysis job %d: %w", id, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RequeueRunningJobs moves jobs left running by a shutdown back to pending.
+// They resume after the last detection they processed.
+func (r *reanalysisRepository) RequeueRunningJobs(ctx context.Context) (int64, error) {
+	result := r.db.WithContext(ctx).Model(&entities.ReanalysisJob{}).
+		Where("status = ?", entities.ReanalysisRunning).
+		Update("status", entities.ReanalysisPending)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to requeue reanalysis jobs: %w", result.Error)
+	}
+	return result.RowsAffected, nil
+}
+
+// SaveFlag records that a job disagrees with a detection.
+func (r *reanalysisRepository) SaveFlag(ctx context.Context, flag *entities.ReanalysisFlag) error {
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(flag).Error; err != nil {
+		return fmt.Errorf("failed to save reanalysis flag for detection %d: %w", flag.DetectionID, err)
+	}
+	return nil
+}
+
+// ListFlags returns the detections a job disagrees with and their total count.
+func (r *reanalysisRepository) ListFlags(ctx context.Context, jobID uint, limit, offset int) ([]entities.ReanalysisFlag, int64, error) {
+	query := r.db.WithContext(ctx).Model(&entities.ReanalysisFlag{}).Where("job_id = ?", jobID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to count reanalysis flags: %w", err)
+	}
+	var flags []entities.ReanalysisFlag
+	if err := query.Order("detection_id ASC").Limit(limit).Offset(offset).Find(&flags).Error; err != nil {
+		return nil, 0, fmt.Errorf("failed to list reanalysis flags: %w", err)
+	}
+	return flags, total, nil
+}
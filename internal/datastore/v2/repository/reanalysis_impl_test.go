@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+// setupReanalysisTestRepo creates a re-analysis repository backed by an
+// in-memory SQLite database.
+func setupReanalysisTestRepo(t *testing.T) ReanalysisRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err, "failed to open in-memory database")
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err, "failed to get sql.DB")
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(&entities.ReanalysisJob{}, &entities.ReanalysisFlag{}), "failed to migrate reanalysis tables")
+	return NewReanalysisRepository(db)
+}
+
+func TestReanalysisRepository_JobLifecycle(t *testing.T) {
+	repo := setupReanalysisTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	first := &entities.ReanalysisJob{Status: entities.ReanalysisPending, Sensitivity: 1, Threshold: 0.8}
+	second := &entities.ReanalysisJob{Status: entities.ReanalysisPending, Sensitivity: 1.2, Threshold: 0.7}
+	require.NoError(t, repo.CreateJob(ctx, first))
+	require.NoError(t, repo.CreateJob(ctx, second))
+
+	next, err := repo.NextPendingJob(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, next.ID, "the oldest pending job runs first")
+
+	started, err := repo.StartJob(ctx, first.ID, now)
+	require.NoError(t, err)
+	assert.True(t, started)
+	started, err = repo.StartJob(ctx, first.ID, now)
+	require.NoError(t, err)
+	assert.False(t, started, "a running job cannot start again")
+
+	first.Total, first.Processed, first.LastDetectionID = 10, 4, 42
+	require.NoError(t, repo.UpdateJobProgress(ctx, first))
+
+	// A shutdown leaves the job running; the next start requeues it with its cursor
+	requeued, err := repo.RequeueRunningJobs(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), requeued)
+	got, err := repo.GetJob(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReanalysisPending, got.Status)
+	assert.Equal(t, uint(42), got.LastDetectionID)
+	assert.Equal(t, int64(4), got.Processed)
+
+	_, err = repo.StartJob(ctx, first.ID, now)
+	require.NoError(t, err)
+	require.NoError(t, repo.FinishJob(ctx, first.ID, entities.ReanalysisCompleted, "", now))
+	got, err = repo.GetJob(ctx, first.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReanalysisCompleted, got.Status)
+	assert.NotNil(t, got.CompletedAt)
+
+	jobs, err := repo.ListJobs(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	assert.Equal(t, second.ID, jobs[0].ID, "jobs are listed newest first")
+
+	_, err = repo.GetJob(ctx, 999)
+	require.ErrorIs(t, err, ErrReanalysisJobNotFound)
+}
+
+func TestReanalysisRepository_CancelledJobStaysCancelled(t *testing.T) {
+	repo := setupReanalysisTestRepo(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	job := &entities.ReanalysisJob{Status: entities.ReanalysisPending, Sensitivity: 1, Threshold: 0.8}
+	require.NoError(t, repo.CreateJob(ctx, job))
+	_, err := repo.StartJob(ctx, job.ID, now)
+	require.NoError(t, err)
+
+	cancelled, err := repo.CancelJob(ctx, job.ID, now)
+	require.NoError(t, err)
+	assert.True(t, cancelled)
+
+	// The runner finishing the job afterwards does not overwrite the cancellation
+	require.NoError(t, repo.FinishJob(ctx, job.ID, entities.ReanalysisCompleted, "", now))
+	got, err := repo.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReanalysisCancelled, got.Status)
+
+	cancelled, err = repo.CancelJob(ctx, job.ID, now)
+	require.NoError(t, err)
+	assert.False(t, cancelled, "an ended job cannot be cancelled")
+
+	_, err = repo.NextPendingJob(ctx)
+	require.ErrorIs(t, err, ErrReanalysisJobNotFound)
+}
+
+func TestReanalysisRepository_SaveFlagIsIdempotent(t *testing.T) {
+	repo := setupReanalysisTestRepo(t)
+	ctx := context.Background()
+
+	job := &entities.ReanalysisJob{Status: entities.ReanalysisPending, Sensitivity: 1, Threshold: 0.8}
+	require.NoError(t, repo.CreateJob(ctx, job))
+
+	for _, detectionID := range []uint{7, 3, 7} {
+		require.NoError(t, repo.SaveFlag(ctx, &entities.ReanalysisFlag{
+			JobID:          job.ID,
+			DetectionID:    detectionID,
+			Reason:         entities.ReanalysisLabelChanged,
+			ScientificName: "Turdus merula",
+		}))
+	}
+
+	flags, total, err := repo.ListFlags(ctx, job.ID, 10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total, "a resumed job flagging a detection again adds nothing")
+	require.Len(t, flags, 2)
+	assert.Equal(t, uint(3), flags[0].DetectionID)
+
+	flags, total, err = repo.ListFlags(ctx, job.ID, 1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, flags, 1)
+	assert.Equal(t, uint(7), flags[0].DetectionID)
+}
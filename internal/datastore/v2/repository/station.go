@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+)
+
+// StationRepository handles the stations of a multi-station hub and the
+// detections they pushed.
+type StationRepository interface {
+	// GetOrCreateStation returns the station with the given name, registering it on first contact.
+	GetOrCreateStation(ctx context.Context, name string) (*entities.Station, error)
+	// GetStation returns the station with the given name.
+	// Returns ErrStationNotFound if the station never pushed detections.
+	GetStation(ctx context.Context, name string) (*entities.Station, error)
+	// ListStations returns all stations ordered by name.
+	ListStations(ctx context.Context) ([]entities.Station, error)
+
+	// HasStationDetection reports whether a detection of a station was stored before.
+	HasStationDetection(ctx context.Context, stationID, remoteID uint) (bool, error)
+	// ReserveStationDetection claims a detection of a station before it is stored.
+	// Returns false if the detection was claimed or stored before.
+	ReserveStationDetection(ctx context.Context, stationID, remoteID uint) (bool, error)
+	// ReleaseStationDetection drops the claim on a detection that could not be stored.
+	ReleaseStationDetection(ctx context.Context, stationID, remoteID uint) error
+	// RecordStationDetection links a stored detection to its ID on the station and
+	// advances the station's sync cursor.
+	RecordStationDetection(ctx context.Context, stationID, remoteID, detectionID uint) error
+	// AdvanceStationCursor moves the sync cursor of a station forward without storing a detection.
+	AdvanceStationCursor(ctx context.Context, stationID, remoteID uint) error
+	// GetStationDetection returns the station detection of a detection stored on the hub.
+	// Returns ErrStationNotFound if the detection was not pushed by a station.
+	GetStationDetection(ctx context.Context, detectionID uint) (*entities.StationDetection, error)
+	// MarkStationSynced records the time and version of a station's last sync.
+	MarkStationSynced(ctx context.Context, stationID uint, version string, at time.Time) error
+}
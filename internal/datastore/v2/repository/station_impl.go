@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// stationRepository implements StationRepository.
+type stationRepository struct {
+	db *gorm.DB
+}
+
+// NewStationRepository creates a new StationRepository.
+func NewStationRepository(db *gorm.DB) StationRepository {
+	return &stationRepository{db: db}
+}
+
+// GetOrCreateStation returns the station with the given name, registering it on first contact.
+func (r *stationRepository) GetOrCreateStation(ctx context.Context, name string) (*entities.Station, error) {
+	if name == "" {
+		return nil, fmt.Errorf("failed to get station: %w", ErrInvalidInput)
+	}
+	station := entities.Station{Name: name}
+	if err := r.db.WithContext(ctx).Where("name = ?", name).FirstOrCreate(&station).Error; err != nil {
+		return nil, fmt.Errorf("failed to get or create station %s: %w", name, err)
+	}
+	return &station, nil
+}
+
+// GetStation returns the station with the given name.
+// Returns ErrStationNotFound if it does not exist.
+func (r *stationRepository) GetStation(ctx context.Context, name string) (*entities.Station, error) {
+	var station entities.Station
+	if err := r.db.WithContext(ctx).Where("name = ?", name).First(&station).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStationNotFound
+		}
+		return nil, fmt.Errorf("failed to get station %s: %w", name, err)
+	}
+	return &station, nil
+}
+
+// ListStations returns all stations ordered by name.
+func (r *stationRepository) ListStations(ctx context.Context) ([]entities.Station, error) {
+	var stations []entities.Station
+	if err := r.db.WithContext(ctx).Order("name ASC").Find(&stations).Error; err != nil {
+		return nil, fmt.Errorf("failed to list stations: %w", err)
+	}
+	return stations, nil
+}
+
+// HasStationDetection reports whether a detection of a station was stored before.
+func (r *stationRepository) HasStationDetection(ctx context.Context, stationID, remoteID uint) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.StationDetection{}).
+		Where("station_id = ? AND remote_id = ?", stationID, remoteID).
+		Count(&count).Error; err != nil {
+		return false, fmt.Errorf("failed to check station detection: %w", err)
+	}
+	return count > 0, nil
+}
+
+// ReserveStationDetection claims a detection of a station with a placeholder
+// row before the detection is stored. Returns false if the detection was
+// claimed or stored before.
+func (r *stationRepository) ReserveStationDetection(ctx context.Context, stationID, remoteID uint) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.StationDetection{
+		StationID: stationID,
+		RemoteID:  remoteID,
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to reserve detection %d of station %d: %w", remoteID, stationID, result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseStationDetection drops the placeholder row of a claimed detection
+// that could not be stored, so a resend stores it. Recorded detections stay.
+func (r *stationRepository) ReleaseStationDetection(ctx context.Context, stationID, remoteID uint) error {
+	if err := r.db.WithContext(ctx).
+		Where("station_id = ? AND remote_id = ? AND detection_id = 0", stationID, remoteID).
+		Delete(&entities.StationDetection{}).Error; err != nil {
+		return fmt.Errorf("failed to release detection %d of station %d: %w", remoteID, stationID, err)
+	}
+	return nil
+}
+
+// RecordStationDetection links a stored detection to its ID on the station,
+// filling in its claim, counts it and advances the station's sync cursor in
+// one transaction. Recording the same detection again changes nothing.
+func (r *stationRepository) RecordStationDetection(ctx context.Context, stationID, remoteID, detectionID uint) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entities.StationDetection{}).
+			Where("station_id = ? AND remote_id = ? AND detection_id = 0", stationID, remoteID).
+			Update("detection_id", detectionID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			result = tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&entities.StationDetection{
+				StationID:   stationID,
+				RemoteID:    remoteID,
+				DetectionID: detectionID,
+			})
+			if result.Error != nil {
+				return result.Error
+			}
+		}
+		if result.RowsAffected > 0 {
+			if err := tx.Model(&entities.Station{}).Where("id = ?", stationID).
+				UpdateColumn("detections", gorm.Expr("detections + 1")).Error; err != nil {
+				return err
+			}
+		}
+		return advanceCursor(tx, stationID, remoteID)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record detection %d of station %d: %w", remoteID, stationID, err)
+	}
+	return nil
+}
+
+// AdvanceStationCursor moves the sync cursor of a station forward, it never moves back.
+func (r *stationRepository) AdvanceStationCursor(ctx context.Context, stationID, remoteID uint) error {
+	if err := advanceCursor(r.db.WithContext(ctx), stationID, remoteID); err != nil {
+		return fmt.Errorf("failed to advance cursor of station %d: %w", stationID, err)
+	}
+	return nil
+}
+
+// advanceCursor raises the last remote ID of a station to remoteID
+func advanceCursor(db *gorm.DB, stationID, remoteID uint) error {
+	return db.Model(&entities.Station{}).
+		Where("id = ? AND last_remote_id < ?", stationID, remoteID).
+		UpdateColumn("last_remote_id", remoteID).Error
+}
+
+// GetStationDetection returns the station detection of a detection stored on the hub.
+// Returns ErrStationNotFound if the detection was not pushed by a station.
+func (r *stationRepository) GetStationDetection(ctx context.Context, detectionID uint) (*entities.StationDetection, error) {
+	var sd entities.StationDetection
+	if err := r.db.WithContext(ctx).Preload("Station").
+		Where("detection_id = ?", detectionID).First(&sd).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrStationNotFound
+		}
+		return nil, fmt.Errorf("failed to get station detection %d: %w", detectionID, err)
+	}
+	return &sd, nil
+}
+
+// MarkStationSynced records the time and version of a station's last sync.
+func (r *stationRepository) MarkStationSynced(ctx context.Context, stationID uint, version string, at time.Time) error {
+	if err := r.db.WithContext(ctx).Model(&entities.Station{}).Where("id = ?", stationID).
+		Updates(map[string]any{"version": version, "last_sync_at": at}).Error; err != nil {
+		return fmt.Errorf("failed to mark station %d synced: %w", stationID, err)
+	}
+	return nil
+}
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+// setupStationTestRepo creates a station repository backed by an in-memory
+// SQLite database.
+func setupStationTestRepo(t *testing.T) StationRepository {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err, "failed to open in-memory database")
+
+	sqlDB, err := db.DB()
+	require.NoError(t, err, "failed to get sql.DB")
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	require.NoError(t, db.AutoMigrate(&entities.Station{}, &entities.StationDetection{}), "failed to migrate station tables")
+	return NewStationRepository(db)
+}
+
+func TestStationRepository_RecordIsIdempotent(t *testing.T) {
+	repo := setupStationTestRepo(t)
+	ctx := context.Background()
+
+	station, err := repo.GetOrCreateStation(ctx, "meadow")
+	require.NoError(t, err)
+	again, err := repo.GetOrCreateStation(ctx, "meadow")
+	require.NoError(t, err)
+	assert.Equal(t, station.ID, again.ID, "a station is registered once")
+
+	require.NoError(t, repo.RecordStationDetection(ctx, station.ID, 7, 100))
+	require.NoError(t, repo.RecordStationDetection(ctx, station.ID, 7, 100))
+	require.NoError(t, repo.RecordStationDetection(ctx, station.ID, 3, 101))
+
+	stored, err := repo.HasStationDetection(ctx, station.ID, 7)
+	require.NoError(t, err)
+	assert.True(t, stored)
+	stored, err = repo.HasStationDetection(ctx, station.ID, 8)
+	require.NoError(t, err)
+	assert.False(t, stored)
+
+	got, err := repo.GetStation(ctx, "meadow")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), got.Detections, "recording a detection twice counts it once")
+	assert.Equal(t, uint(7), got.LastRemoteID, "the cursor never moves back")
+
+	require.NoError(t, repo.AdvanceStationCursor(ctx, station.ID, 9))
+	got, err = repo.GetStation(ctx, "meadow")
+	require.NoError(t, err)
+	assert.Equal(t, uint(9), got.LastRemoteID)
+}
+
+func TestStationRepository_ReserveAndRelease(t *testing.T) {
+	repo := setupStationTestRepo(t)
+	ctx := context.Background()
+
+	station, err := repo.GetOrCreateStation(ctx, "meadow")
+	require.NoError(t, err)
+
+	reserved, err := repo.ReserveStationDetection(ctx, station.ID, 7)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+	reserved, err = repo.ReserveStationDetection(ctx, station.ID, 7)
+	require.NoError(t, err)
+	assert.False(t, reserved, "a claimed detection cannot be claimed again")
+
+	// A released claim can be taken again
+	require.NoError(t, repo.ReleaseStationDetection(ctx, station.ID, 7))
+	reserved, err = repo.ReserveStationDetection(ctx, station.ID, 7)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	// Recording fills in the claim, releasing afterwards keeps the record
+	require.NoError(t, repo.RecordStationDetection(ctx, station.ID, 7, 100))
+	require.NoError(t, repo.ReleaseStationDetection(ctx, station.ID, 7))
+	sd, err := repo.GetStationDetection(ctx, 100)
+	require.NoError(t, err)
+	assert.Equal(t, uint(7), sd.RemoteID)
+
+	got, err := repo.GetStation(ctx, "meadow")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), got.Detections)
+	assert.Equal(t, uint(7), got.LastRemoteID)
+}
+
+func TestStationRepository_GetStationDetection(t *testing.T) {
+	repo := setupStationTestRepo(t)
+	ctx := context.Background()
+
+	station, err := repo.GetOrCreateStation(ctx, "forest")
+	require.NoError(t, err)
+	require.NoError(t, repo.RecordStationDetection(ctx, station.ID, 42, 5))
+
+	sd, err := repo.GetStationDetection(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, uint(42), sd.RemoteID)
+	require.NotNil(t, sd.Station)
+	assert.Equal(t, "forest", sd.Station.Name)
+
+	_, err = repo.GetStationDetection(ctx, 6)
+	require.ErrorIs(t, err, ErrStationNotFound)
+	_, err = repo.GetStation(ctx, "unknown")
+	require.ErrorIs(t, err, ErrStationNotFound)
+}
+
+func TestStationRepository_MarkStationSynced(t *testing.T) {
+	repo := setupStationTestRepo(t)
+	ctx := context.Background()
+
+	for _, name := range []string{"meadow", "forest"} {
+		_, err := repo.GetOrCreateStation(ctx, name)
+		require.NoError(t, err)
+	}
+	station, err := repo.GetStation(ctx, "meadow")
+	require.NoError(t, err)
+
+	at := time.Date(2026, 5, 1, 6, 30, 0, 0, time.UTC)
+	require.NoError(t, repo.MarkStationSynced(ctx, station.ID, "v1.2.3", at))
+
+	stations, err := repo.ListStations(ctx)
+	require.NoError(t, err)
+	require.Len(t, stations, 2)
+	assert.Equal(t, "forest", stations[0].Name, "stations are ordered by name")
+	assert.Equal(t, "v1.2.3", stations[1].Version)
+	require.NotNil(t, stations[1].LastSyncAt)
+	assert.True(t, at.Equal(*stations[1].LastSyncAt))
+}
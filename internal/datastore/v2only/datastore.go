@@ -495,7 +495,7 @@ func (ds *Datastore) Save(note *datastore.Note, results []datastore.Results) err
 	// DIRECT DB WRITE: We use tx.Create directly instead of ds.detection.Save()
 	// to ensure both detection and predictions are in the same transaction.
 	// The repository doesn't currently support transaction injection.
-	return ds.manager.DB().WithContext(txCtx).Transaction(func(tx *gorm.DB) error {
+	err = ds.manager.DB().WithContext(txCtx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(det).Error; err != nil {
 			return fmt.Errorf("failed to save detection: %w", err)
 		}
@@ -518,6 +518,62 @@ func (ds *Datastore) Save(note *datastore.Note, results []datastore.Results) err
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	// Report the assigned ID like the legacy store does
+	note.ID = det.ID
+	return nil
+}
+
+// ReanalysisModelID returns the model registry ID of the model a re-analysis
+// job runs, registering the model on first use.
+func (ds *Datastore) ReanalysisModelID(ctx context.Context, info detection.ModelInfo) (uint, error) {
+	model, err := ds.model.GetOrCreate(ctx, info.Name, info.Version, info.Variant, entities.ModelTypeBird, info.ClassifierPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get/create model: %w", err)
+	}
+	return model.ID, nil
+}
+
+// SaveReanalysisPredictions stores the top predictions of a re-analysis job
+// for a detection, ranked from 1 for the best. Predictions the job stored
+// before are kept, so a resumed job can save a detection again.
+func (ds *Datastore) SaveReanalysisPredictions(ctx context.Context, jobID, modelID, detectionID uint, results []datastore.Results) error {
+	if len(results) == 0 {
+		return nil
+	}
+	names := make([]string, len(results))
+	for i, r := range results {
+		names[i] = extractScientificName(r.Species)
+	}
+	labels, err := ds.label.BatchGetOrCreate(ctx, names, modelID, ds.speciesLabelTypeID, ds.avesClassID)
+	if err != nil {
+		return fmt.Errorf("failed to batch get/create prediction labels: %w", err)
+	}
+
+	preds := make([]*entities.DetectionPrediction, 0, len(results))
+	seen := make(map[uint]bool, len(results))
+	for i, r := range results {
+		lbl, ok := labels[names[i]]
+		if !ok {
+			return fmt.Errorf("label not found for species %s after batch creation", r.Species)
+		}
+		// A label may appear twice when species strings differ only in common name
+		if seen[lbl.ID] {
+			continue
+		}
+		seen[lbl.ID] = true
+		preds = append(preds, &entities.DetectionPrediction{
+			DetectionID: detectionID,
+			JobID:       jobID,
+			LabelID:     lbl.ID,
+			Confidence:  float64(r.Confidence),
+			Rank:        len(preds) + 1,
+		})
+	}
+	return ds.detection.SavePredictionsBatch(ctx, preds)
 }
 
 // Delete deletes a note by ID.
@@ -2386,6 +2442,11 @@ func (ds *Datastore) GetSpeciesDiversityData(ctx context.Context, startDate, end
 		query = query.Where(fmt.Sprintf("%s <= ?", dateExpr), endDate)
 	}
 
+	// Limit to one station of a hub
+	if station := datastore.StationFromContext(ctx); station != "" {
+		query = query.Where("d.source_id IN (SELECT id FROM audio_sources WHERE node_name = ?)", station)
+	}
+
 	// Execute query
 	if err := query.Scan(&results).Error; err != nil {
 		return nil, errors.New(err).
@@ -10,6 +10,7 @@ import (
 	v2 "github.com/tphakala/birdnet-go/internal/datastore/v2"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
 	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/detection"
 	"github.com/tphakala/birdnet-go/internal/logger"
 )
 
@@ -1051,3 +1052,42 @@ func TestV2OnlyDatastore_ConcatenatedLabelExtraction(t *testing.T) {
 			"Save should store only the extracted scientific name, not the concatenated form")
 	})
 }
+
+func TestV2OnlyDatastore_SaveReanalysisPredictions(t *testing.T) {
+	ds, cleanup := setupTestDatastore(t)
+	defer cleanup()
+	ctx := t.Context()
+
+	note := &datastore.Note{
+		Date:           "2024-01-15",
+		Time:           "12:30:00",
+		ScientificName: "Passer domesticus",
+		CommonName:     "House Sparrow",
+		Confidence:     0.85,
+	}
+	require.NoError(t, ds.Save(note, []datastore.Results{
+		{Species: "Passer domesticus", Confidence: 0.85},
+		{Species: "Passer montanus", Confidence: 0.10},
+	}))
+
+	modelID, err := ds.ReanalysisModelID(ctx, detection.DefaultModelInfo())
+	require.NoError(t, err)
+
+	// The job predicts the labels of the original analysis again, one of them twice
+	require.NoError(t, ds.SaveReanalysisPredictions(ctx, 7, modelID, note.ID, []datastore.Results{
+		{Species: "Passer montanus_Eurasian Tree Sparrow", Confidence: 0.7},
+		{Species: "Passer domesticus_House Sparrow", Confidence: 0.2},
+		{Species: "Passer montanus", Confidence: 0.1},
+	}))
+
+	original, err := ds.detection.GetPredictions(ctx, note.ID)
+	require.NoError(t, err)
+	assert.Len(t, original, 2, "the original predictions are kept apart from the job")
+
+	var jobPreds []entities.DetectionPrediction
+	require.NoError(t, ds.manager.DB().Where("job_id = ?", 7).Order("rank ASC").Find(&jobPreds).Error)
+	require.Len(t, jobPreds, 2)
+	assert.Equal(t, 1, jobPreds[0].Rank, "the best prediction of a job has rank 1")
+	assert.InDelta(t, 0.7, jobPreds[0].Confidence, 0.001)
+	assert.Equal(t, 2, jobPreds[1].Rank)
+}
@@ -0,0 +1,154 @@
+// policy_archive.go - code for audio archive retention policy
+package diskmanager
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// archiveSegmentExt is the file extension of archived audio segments
+const archiveSegmentExt = ".flac"
+
+// archiveSegment is an archived audio segment found during cleanup
+type archiveSegment struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// ArchiveCleanup enforces the retention limits of the continuous audio archive.
+// Segments older than MaxAge are removed first, then the oldest segments are
+// removed until the archive fits within MaxSize. Unfinished segments are never
+// touched, and day directories left empty are removed.
+//
+// Returns a CleanupResult containing error, number of segments removed, and current disk utilization percentage.
+func ArchiveCleanup(quit <-chan struct{}, settings *conf.ArchiveSettings) CleanupResult {
+	startTime := time.Now()
+	baseDir := settings.Path
+
+	var maxAge time.Duration
+	if strings.TrimSpace(settings.MaxAge) != "" {
+		hours, err := conf.ParseRetentionPeriod(strings.TrimSpace(settings.MaxAge))
+		if err != nil {
+			return CleanupResult{Err: fmt.Errorf("invalid archive max age '%s': %w", settings.MaxAge, err)}
+		}
+		maxAge = time.Duration(hours) * time.Hour
+	}
+	var maxSize int64
+	if strings.TrimSpace(settings.MaxSize) != "" {
+		size, err := conf.ParseSize(settings.MaxSize)
+		if err != nil {
+			return CleanupResult{Err: fmt.Errorf("invalid archive max size '%s': %w", settings.MaxSize, err)}
+		}
+		maxSize = size
+	}
+
+	segments, totalSize, err := getArchiveSegments(baseDir)
+	if err != nil {
+		return CleanupResult{Err: fmt.Errorf("failed to list archive segments: %w", err)}
+	}
+
+	// Oldest first, so both limits remove the oldest audio
+	sort.Slice(segments, func(i, j int) bool {
+		return segments[i].modTime.Before(segments[j].modTime)
+	})
+
+	cutoff := time.Now().Add(-maxAge)
+	removed := 0
+	var removeErr error
+	for i := range segments {
+		select {
+		case <-quit:
+			return archiveCleanupResult(baseDir, removed, removeErr)
+		default:
+		}
+
+		expired := maxAge > 0 && segments[i].modTime.Before(cutoff)
+		oversized := maxSize > 0 && totalSize > maxSize
+		if !expired && !oversized {
+			break
+		}
+		if err := os.Remove(segments[i].path); err != nil && !os.IsNotExist(err) {
+			GetLogger().Error("Failed to remove archive segment",
+				logger.String("policy", "archive"),
+				logger.String("path", segments[i].path),
+				logger.Error(err))
+			removeErr = err
+			continue
+		}
+		totalSize -= segments[i].size
+		removed++
+	}
+
+	removeEmptyArchiveDirs(baseDir)
+
+	GetLogger().Info("Archive cleanup run completed",
+		logger.String("policy", "archive"),
+		logger.Int("files_removed", removed),
+		logger.Int64("archive_size_bytes", totalSize),
+		logger.Int64("duration_ms", time.Since(startTime).Milliseconds()))
+
+	return archiveCleanupResult(baseDir, removed, removeErr)
+}
+
+// archiveCleanupResult builds the result of an archive cleanup run
+func archiveCleanupResult(baseDir string, removed int, err error) CleanupResult {
+	utilization := 0
+	if usage, diskErr := GetDiskUsage(baseDir); diskErr == nil {
+		utilization = int(usage)
+	}
+	return CleanupResult{Err: err, ClipsRemoved: removed, DiskUtilization: utilization}
+}
+
+// getArchiveSegments returns the completed archive segments below baseDir and their total size
+func getArchiveSegments(baseDir string) ([]archiveSegment, int64, error) {
+	var segments []archiveSegment
+	var totalSize int64
+
+	err := filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != archiveSegmentExt {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		segments = append(segments, archiveSegment{path: path, size: info.Size(), modTime: info.ModTime()})
+		totalSize += info.Size()
+		return nil
+	})
+	return segments, totalSize, err
+}
+
+// removeEmptyArchiveDirs removes empty source and day directories below baseDir
+func removeEmptyArchiveDirs(baseDir string) {
+	var dirs []string
+	_ = filepath.WalkDir(baseDir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && path != baseDir {
+			dirs = append(dirs, path)
+		}
+		return nil
+	})
+
+	// Deepest directories first so emptied parents can be removed too
+	for i := len(dirs) - 1; i >= 0; i-- {
+		entries, err := os.ReadDir(dirs[i])
+		if err == nil && len(entries) == 0 {
+			_ = os.Remove(dirs[i])
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package diskmanager
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// writeArchiveSegment creates an archive segment file of the given size and age
+func writeArchiveSegment(t *testing.T, path string, size int, age time.Duration) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, make([]byte, size), 0o600))
+	modTime := time.Now().Add(-age)
+	require.NoError(t, os.Chtimes(path, modTime, modTime))
+}
+
+func TestArchiveCleanup(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	expired := filepath.Join(dir, "yard", "2026-10-01", "yard_20261001T000000.000Z.flac")
+	oldest := filepath.Join(dir, "yard", "2026-10-17", "yard_20261017T000000.000Z.flac")
+	middle := filepath.Join(dir, "yard", "2026-10-18", "yard_20261018T000000.000Z.flac")
+	newest := filepath.Join(dir, "pond", "2026-10-18", "pond_20261018T000500.000Z.flac")
+	unfinished := filepath.Join(dir, "pond", "2026-10-18", "pond_20261018T001000.000Z.flac.temp")
+
+	writeArchiveSegment(t, expired, 1024, 10*24*time.Hour)
+	writeArchiveSegment(t, oldest, 1024, 3*time.Hour)
+	writeArchiveSegment(t, middle, 1024, 2*time.Hour)
+	writeArchiveSegment(t, newest, 1024, time.Hour)
+	writeArchiveSegment(t, unfinished, 4096, 0)
+
+	result := ArchiveCleanup(make(chan struct{}), &conf.ArchiveSettings{Path: dir, MaxAge: "7d", MaxSize: "2KB"})
+	require.NoError(t, result.Err)
+	assert.Equal(t, 2, result.ClipsRemoved, "the expired segment and the oldest segment over the size limit are removed")
+
+	for _, path := range []string{expired, oldest} {
+		assert.NoFileExists(t, path)
+	}
+	for _, path := range []string{middle, newest, unfinished} {
+		assert.FileExists(t, path)
+	}
+	assert.NoDirExists(t, filepath.Join(dir, "yard", "2026-10-01"), "empty day directories are removed")
+}
+
+func TestArchiveCleanup_InvalidSettings(t *testing.T) {
+	t.Parallel()
+
+	result := ArchiveCleanup(make(chan struct{}), &conf.ArchiveSettings{Path: t.TempDir(), MaxSize: "lots"})
+	require.Error(t, result.Err)
+}
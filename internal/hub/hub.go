@@ -0,0 +1,136 @@
+// Package hub aggregates the detections of several BirdNET-Go stations in one
+// installation. Stations push their detections, and optionally their audio
+// clips, to the hub over its API. Every detection carries its ID on the
+// station; the hub stores each ID once and keeps the highest as the cursor a
+// station resumes from after an outage. Detections of a station are stored
+// with the station name as node name, so search and analytics can be limited
+// to one station.
+package hub
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// API paths of the hub, relative to its base URL
+const (
+	apiStationsPath = "/api/v2/hub/stations/"
+	cursorSuffix    = "/cursor"
+	detectionSuffix = "/detections"
+	clipsSuffix     = "/clips/"
+)
+
+// stationClipsDir is the directory under the clip export path uploaded clips are stored in
+const stationClipsDir = "stations"
+
+// MaxClipSize bounds a clip uploaded by a station. Clips are at most 60
+// seconds long, a 60 second WAV clip is under 6 MB.
+const MaxClipSize = 16 << 20
+
+// ErrClipTooLarge is returned for clips larger than MaxClipSize.
+var ErrClipTooLarge = errors.Newf("clip exceeds the maximum size of %d bytes", MaxClipSize).
+	Component("hub").
+	Category(errors.CategoryLimit).
+	Build()
+
+// clipNamePattern limits uploaded clip paths to what the station generates
+var clipNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_./-]{1,255}$`)
+
+// Detection is a detection a station pushes to the hub
+type Detection struct {
+	ID             uint      `json:"id"`               // detection ID on the station
+	Date           string    `json:"date"`             // local date, YYYY-MM-DD
+	Time           string    `json:"time"`             // local time, HH:MM:SS
+	Source         string    `json:"source,omitempty"` // audio source on the station
+	ScientificName string    `json:"scientific_name"`
+	CommonName     string    `json:"common_name"`
+	SpeciesCode    string    `json:"species_code,omitempty"`
+	Confidence     float64   `json:"confidence"`
+	Latitude       float64   `json:"latitude,omitempty"`
+	Longitude      float64   `json:"longitude,omitempty"`
+	Threshold      float64   `json:"threshold,omitempty"`
+	Sensitivity    float64   `json:"sensitivity,omitempty"`
+	BeginTime      time.Time `json:"begin_time,omitzero"`
+	EndTime        time.Time `json:"end_time,omitzero"`
+	ClipName       string    `json:"clip_name,omitempty"`     // clip path on the station
+	ClipUploaded   bool      `json:"clip_uploaded,omitempty"` // the clip was uploaded to the hub before the detection
+}
+
+// Batch is a set of detections pushed in one request
+type Batch struct {
+	Version    string      `json:"version,omitempty"` // BirdNET-Go version of the station
+	Detections []Detection `json:"detections"`
+}
+
+// BatchResult is the answer of the hub to a batch
+type BatchResult struct {
+	Stored     int  `json:"stored"`     // detections stored
+	Duplicates int  `json:"duplicates"` // detections the hub had stored before
+	Cursor     uint `json:"cursor"`     // highest detection ID of the station the hub stored
+}
+
+// Cursor is the position a station resumes pushing from
+type Cursor struct {
+	Station string `json:"station"`
+	Cursor  uint   `json:"cursor"`
+}
+
+// Origin traces a detection on the hub back to its station
+type Origin struct {
+	Station        string `json:"station"`
+	RemoteID       uint   `json:"remote_id"`                 // detection ID on the station
+	StationURL     string `json:"station_url,omitempty"`     // web address of the station, if configured
+	AudioURL       string `json:"audio_url,omitempty"`       // clip on the station
+	SpectrogramURL string `json:"spectrogram_url,omitempty"` // spectrogram on the station
+}
+
+// validateBatch checks the detections of a batch before any is stored.
+func validateBatch(batch *Batch, maxSize int) error {
+	if len(batch.Detections) > maxSize {
+		return validationError(fmt.Sprintf("batch holds %d detections, at most %d are accepted", len(batch.Detections), maxSize))
+	}
+	for i := range batch.Detections {
+		d := &batch.Detections[i]
+		switch {
+		case d.ID == 0:
+			return validationError(fmt.Sprintf("detection %d: id is required", i+1))
+		case strings.TrimSpace(d.ScientificName) == "":
+			return validationError(fmt.Sprintf("detection %d: scientific_name is required", d.ID))
+		case d.Confidence < 0 || d.Confidence > 1:
+			return validationError(fmt.Sprintf("detection %d: confidence must be between 0 and 1", d.ID))
+		}
+		if _, err := time.Parse(time.DateTime, d.Date+" "+d.Time); err != nil {
+			return validationError(fmt.Sprintf("detection %d: date and time must be YYYY-MM-DD and HH:MM:SS", d.ID))
+		}
+	}
+	return nil
+}
+
+// ClipPath returns where a clip uploaded by a station is stored, relative to
+// the clip export path.
+func ClipPath(station, clipName string) (string, error) {
+	cleaned := path.Clean(clipName)
+	if !clipNamePattern.MatchString(clipName) || cleaned == "." || path.IsAbs(cleaned) ||
+		cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", validationError("clip name must be a relative path of letters, digits, '-', '_', '.' and '/'")
+	}
+	return path.Join(stationClipsDir, station, cleaned), nil
+}
+
+func validationError(msg string) error {
+	return errors.Newf("%s", msg).
+		Component("hub").
+		Category(errors.CategoryValidation).
+		Build()
+}
+
+// GetLogger returns the hub logger.
+func GetLogger() logger.Logger {
+	return logger.Global().Module("hub")
+}
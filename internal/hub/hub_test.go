@@ -0,0 +1,336 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+const testToken = "0123456789abcdef"
+
+// memoryStore keeps notes in memory and assigns IDs like the datastore.
+type memoryStore struct {
+	mu    sync.Mutex
+	notes []datastore.Note
+}
+
+func (m *memoryStore) Save(note *datastore.Note, _ []datastore.Results) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	note.ID = uint(len(m.notes) + 1)
+	m.notes = append(m.notes, *note)
+	return nil
+}
+
+func (m *memoryStore) SearchNotesAdvanced(filters *datastore.AdvancedSearchFilters) ([]datastore.Note, int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var notes []datastore.Note
+	for i := range m.notes {
+		if m.notes[i].ID > filters.MinID && len(notes) < filters.Limit {
+			notes = append(notes, m.notes[i])
+		}
+	}
+	return notes, int64(len(notes)), nil
+}
+
+func (m *memoryStore) all() []datastore.Note {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]datastore.Note(nil), m.notes...)
+}
+
+func setupReceiver(t *testing.T) (*Receiver, *memoryStore, repository.StationRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&entities.Station{}, &entities.StationDetection{}))
+
+	store := &memoryStore{}
+	stations := repository.NewStationRepository(db)
+	return NewReceiver(store, stations, t.TempDir()), store, stations
+}
+
+func testDetection(id uint) Detection {
+	return Detection{
+		ID:             id,
+		Date:           "2026-05-01",
+		Time:           "06:30:00",
+		ScientificName: "Turdus merula",
+		CommonName:     "Eurasian Blackbird",
+		Confidence:     0.9,
+	}
+}
+
+func TestReceiver_SkipsDuplicates(t *testing.T) {
+	t.Parallel()
+	r, store, _ := setupReceiver(t)
+	ctx := context.Background()
+
+	result, err := r.Receive(ctx, "meadow", &Batch{Version: "v1", Detections: []Detection{testDetection(2), testDetection(1)}})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Stored)
+	assert.Equal(t, uint(2), result.Cursor)
+
+	// A batch resent after a lost answer stores only what is new
+	result, err = r.Receive(ctx, "meadow", &Batch{Detections: []Detection{testDetection(1), testDetection(2), testDetection(3)}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Stored)
+	assert.Equal(t, 2, result.Duplicates)
+	assert.Equal(t, uint(3), result.Cursor)
+
+	notes := store.all()
+	require.Len(t, notes, 3)
+	assert.Equal(t, "meadow", notes[0].SourceNode)
+	assert.Equal(t, "station:meadow", notes[0].Source.SafeString)
+
+	cursor, err := r.Cursor(ctx, "meadow")
+	require.NoError(t, err)
+	assert.Equal(t, uint(3), cursor)
+	cursor, err = r.Cursor(ctx, "forest")
+	require.NoError(t, err)
+	assert.Zero(t, cursor, "a station that never pushed starts at 0")
+}
+
+// failingStations fails RecordStationDetection while fail is set.
+type failingStations struct {
+	repository.StationRepository
+	fail atomic.Bool
+}
+
+func (f *failingStations) RecordStationDetection(ctx context.Context, stationID, remoteID, detectionID uint) error {
+	if f.fail.Load() {
+		return errors.New("database is locked")
+	}
+	return f.StationRepository.RecordStationDetection(ctx, stationID, remoteID, detectionID)
+}
+
+// failingStore fails Save while fail is set.
+type failingStore struct {
+	*memoryStore
+	fail atomic.Bool
+}
+
+func (f *failingStore) Save(note *datastore.Note, results []datastore.Results) error {
+	if f.fail.Load() {
+		return errors.New("disk full")
+	}
+	return f.memoryStore.Save(note, results)
+}
+
+func TestReceiver_ResendAfterFailedRecordStoresOnce(t *testing.T) {
+	t.Parallel()
+	r, store, repo := setupReceiver(t)
+	stations := &failingStations{StationRepository: repo}
+	r.stations = stations
+	ctx := context.Background()
+	batch := &Batch{Detections: []Detection{testDetection(1)}}
+
+	stations.fail.Store(true)
+	_, err := r.Receive(ctx, "meadow", batch)
+	require.Error(t, err)
+
+	stations.fail.Store(false)
+	result, err := r.Receive(ctx, "meadow", batch)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Duplicates, "the note saved before the failure is not stored again")
+	assert.Equal(t, uint(1), result.Cursor)
+	assert.Len(t, store.all(), 1)
+}
+
+func TestReceiver_ResendAfterFailedSaveStoresOnce(t *testing.T) {
+	t.Parallel()
+	r, memory, _ := setupReceiver(t)
+	store := &failingStore{memoryStore: memory}
+	r.store = store
+	ctx := context.Background()
+	batch := &Batch{Detections: []Detection{testDetection(1)}}
+
+	store.fail.Store(true)
+	_, err := r.Receive(ctx, "meadow", batch)
+	require.Error(t, err)
+	cursor, err := r.Cursor(ctx, "meadow")
+	require.NoError(t, err)
+	assert.Zero(t, cursor, "the cursor stays before the unsaved detection")
+
+	store.fail.Store(false)
+	result, err := r.Receive(ctx, "meadow", batch)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Stored)
+	assert.Len(t, memory.all(), 1)
+}
+
+func TestReceiver_RejectsInvalidBatch(t *testing.T) {
+	t.Parallel()
+	r, store, _ := setupReceiver(t)
+
+	tests := []struct {
+		name   string
+		modify func(*Detection)
+	}{
+		{"missing id", func(d *Detection) { d.ID = 0 }},
+		{"missing species", func(d *Detection) { d.ScientificName = " " }},
+		{"confidence above 1", func(d *Detection) { d.Confidence = 1.5 }},
+		{"bad date", func(d *Detection) { d.Date = "01.05.2026" }},
+	}
+	for _, tt := range tests {
+		d := testDetection(1)
+		tt.modify(&d)
+		_, err := r.Receive(context.Background(), "meadow", &Batch{Detections: []Detection{testDetection(2), d}})
+		require.Error(t, err, tt.name)
+	}
+	assert.Empty(t, store.all(), "nothing of an invalid batch is stored")
+}
+
+func TestReceiver_SaveClipLimitsSize(t *testing.T) {
+	t.Parallel()
+	r, _, _ := setupReceiver(t)
+
+	// A default length WAV clip is larger than the 1MB API body limit
+	require.NoError(t, r.SaveClip("meadow", "2026/05/large.wav", bytes.NewReader(make([]byte, 1440044))))
+	info, err := os.Stat(filepath.Join(r.clipsDir, stationClipsDir, "meadow", "2026", "05", "large.wav"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1440044), info.Size())
+
+	err = r.SaveClip("meadow", "2026/05/huge.wav", bytes.NewReader(make([]byte, MaxClipSize+1)))
+	require.ErrorIs(t, err, ErrClipTooLarge)
+	_, err = os.Stat(filepath.Join(r.clipsDir, stationClipsDir, "meadow", "2026", "05", "huge.wav"))
+	require.True(t, os.IsNotExist(err), "an oversized clip is not kept")
+	entries, err := os.ReadDir(filepath.Join(r.clipsDir, stationClipsDir, "meadow", "2026", "05"))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "no temporary file is left behind")
+}
+
+func TestClipPath(t *testing.T) {
+	t.Parallel()
+
+	got, err := ClipPath("meadow", "2026/05/turdus_merula_90p_20260501T063000Z.wav")
+	require.NoError(t, err)
+	assert.Equal(t, "stations/meadow/2026/05/turdus_merula_90p_20260501T063000Z.wav", got)
+
+	for _, name := range []string{"", "../secret.wav", "/etc/passwd", "2026/../../x.wav", `2026\05\x.wav`, "a b.wav"} {
+		_, err := ClipPath("meadow", name)
+		assert.Error(t, err, name)
+	}
+}
+
+// newTestHub serves the station endpoints of the hub API for one station.
+// The detection push numbered failOn fails as if the hub were down.
+func newTestHub(t *testing.T, r *Receiver, failOn int32) *httptest.Server {
+	t.Helper()
+	prefix := apiStationsPath + "meadow"
+	var pushes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer "+testToken {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		ctx := req.Context()
+		switch {
+		case req.Method == http.MethodGet && req.URL.Path == prefix+cursorSuffix:
+			cursor, err := r.Cursor(ctx, "meadow")
+			require.NoError(t, err)
+			_ = json.NewEncoder(w).Encode(Cursor{Station: "meadow", Cursor: cursor})
+		case req.Method == http.MethodPost && req.URL.Path == prefix+detectionSuffix:
+			if pushes.Add(1) == failOn {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			var batch Batch
+			require.NoError(t, json.NewDecoder(req.Body).Decode(&batch))
+			result, err := r.Receive(ctx, "meadow", &batch)
+			require.NoError(t, err)
+			_ = json.NewEncoder(w).Encode(result)
+		case req.Method == http.MethodPut && strings.HasPrefix(req.URL.Path, prefix+clipsSuffix):
+			name, err := url.PathUnescape(strings.TrimPrefix(req.URL.Path, prefix+clipsSuffix))
+			require.NoError(t, err)
+			require.NoError(t, r.SaveClip("meadow", name, req.Body))
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, req)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestPusher_SyncResumesAfterOutage(t *testing.T) {
+	t.Parallel()
+	r, hubStore, _ := setupReceiver(t)
+	srv := newTestHub(t, r, 2)
+
+	clipsDir := t.TempDir()
+	clip := "2026/05/turdus_merula.wav"
+	require.NoError(t, os.MkdirAll(filepath.Join(clipsDir, "2026", "05"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(clipsDir, filepath.FromSlash(clip)), []byte("RIFF"), 0o600))
+
+	node := &memoryStore{}
+	for i := range 5 {
+		note := &datastore.Note{Date: "2026-05-01", Time: "06:30:00", ScientificName: "Turdus merula", Confidence: 0.8}
+		if i == 0 {
+			note.ClipName = clip
+		}
+		require.NoError(t, node.Save(note, nil))
+	}
+
+	settings := &conf.Settings{Version: "test"}
+	settings.Realtime.Audio.Export.Path = clipsDir
+	settings.Hub.Upstream = conf.HubUpstreamSettings{
+		Enabled: true, URL: srv.URL, Station: "meadow", Token: testToken,
+		Interval: conf.Duration(time.Minute), BatchSize: 2, UploadClips: true,
+	}
+	p := NewPusher(settings, node)
+	ctx := context.Background()
+
+	// The second batch fails, the first stays stored
+	pushed, err := p.Sync(ctx)
+	require.Error(t, err)
+	assert.Equal(t, 2, pushed)
+	assert.Len(t, hubStore.all(), 2)
+
+	// The next sync resumes after the stored batch
+	pushed, err = p.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, pushed)
+	assert.Len(t, hubStore.all(), 5)
+
+	// Only new detections are pushed on the next sync
+	require.NoError(t, node.Save(&datastore.Note{Date: "2026-05-01", Time: "07:00:00", ScientificName: "Erithacus rubecula", Confidence: 0.7}, nil))
+	pushed, err = p.Sync(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, pushed)
+
+	notes := hubStore.all()
+	require.Len(t, notes, 6)
+	assert.Equal(t, "stations/meadow/"+clip, notes[0].ClipName, "uploaded clips are stored under the station")
+	assert.FileExists(t, filepath.Join(r.clipsDir, "stations", "meadow", filepath.FromSlash(clip)))
+	assert.Empty(t, notes[1].ClipName)
+	assert.Equal(t, "Erithacus rubecula", notes[5].ScientificName)
+}
@@ -0,0 +1,275 @@
+package hub
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/httpclient"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// pushTimeout bounds one request to the hub, clip uploads included
+const pushTimeout = 2 * time.Minute
+
+// maxResponseSize limits how much of a hub response is read
+const maxResponseSize = 1 << 20
+
+// DetectionSource is the part of the datastore the pusher reads detections from.
+type DetectionSource interface {
+	SearchNotesAdvanced(filters *datastore.AdvancedSearchFilters) ([]datastore.Note, int64, error)
+}
+
+// Pusher sends the detections of this station to a hub. It asks the hub for
+// the last detection it stored and pushes everything after it, so a sync
+// resumes where the previous one stopped, however long the hub was
+// unreachable.
+type Pusher struct {
+	settings conf.HubUpstreamSettings
+	version  string
+	clipsDir string
+	store    DetectionSource
+	client   *httpclient.Client
+}
+
+// NewPusher creates a pusher for the upstream hub configured in settings.
+func NewPusher(settings *conf.Settings, store DetectionSource) *Pusher {
+	cfg := httpclient.DefaultConfig()
+	cfg.UserAgent = "BirdNET-Go-Hub/" + settings.Version
+	cfg.DefaultTimeout = pushTimeout
+	return &Pusher{
+		settings: settings.Hub.Upstream,
+		version:  settings.Version,
+		clipsDir: settings.Realtime.Audio.Export.Path,
+		store:    store,
+		client:   httpclient.New(&cfg),
+	}
+}
+
+// Start syncs with the hub at the configured interval until quit is closed.
+func (p *Pusher) Start(quit <-chan struct{}) {
+	defer p.client.Close()
+	log := GetLogger()
+	log.Info("pushing detections to hub",
+		logger.String("hub", p.settings.URL),
+		logger.String("station", p.settings.Station),
+		logger.Duration("interval", p.settings.Interval.Std()))
+
+	ticker := time.NewTicker(p.settings.Interval.Std())
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			select {
+			case <-quit:
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+		pushed, err := p.Sync(ctx)
+		cancel()
+
+		switch {
+		case err != nil && !failing:
+			failing = true
+			log.Warn("hub sync failed, retrying at next interval",
+				logger.String("hub", p.settings.URL),
+				logger.Error(err))
+		case err != nil:
+			log.Debug("hub sync still failing", logger.Error(err))
+		case failing:
+			failing = false
+			log.Info("hub sync recovered", logger.Int("pushed", pushed))
+		case pushed > 0:
+			log.Debug("pushed detections to hub", logger.Int("pushed", pushed))
+		}
+
+		select {
+		case <-quit:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sync pushes all detections the hub has not stored yet and returns how many
+// it pushed.
+func (p *Pusher) Sync(ctx context.Context) (int, error) {
+	cursor, err := p.fetchCursor(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pushed := 0
+	for {
+		notes, _, err := p.store.SearchNotesAdvanced(&datastore.AdvancedSearchFilters{
+			MinID:            cursor,
+			CursorPagination: true,
+			SortAscending:    true,
+			Limit:            p.settings.BatchSize,
+		})
+		if err != nil {
+			return pushed, err
+		}
+		if len(notes) == 0 {
+			return pushed, nil
+		}
+
+		batch := &Batch{Version: p.version, Detections: make([]Detection, 0, len(notes))}
+		for i := range notes {
+			d := detectionFromNote(&notes[i])
+			if p.settings.UploadClips && d.ClipName != "" {
+				if err := p.uploadClip(ctx, d.ClipName); err != nil && !os.IsNotExist(err) && !errors.Is(err, ErrClipTooLarge) {
+					return pushed, err
+				} else if err == nil {
+					d.ClipUploaded = true
+				}
+			}
+			batch.Detections = append(batch.Detections, d)
+		}
+
+		result, err := p.pushBatch(ctx, batch)
+		if err != nil {
+			return pushed, err
+		}
+		last := batch.Detections[len(batch.Detections)-1].ID
+		if result.Cursor < last {
+			return pushed, p.syncError(fmt.Errorf("hub cursor %d did not reach pushed detection %d", result.Cursor, last), "push_detections")
+		}
+		cursor = result.Cursor
+		pushed += result.Stored
+	}
+}
+
+// detectionFromNote converts a stored note to the detection pushed to the hub.
+func detectionFromNote(note *datastore.Note) Detection {
+	return Detection{
+		ID:             note.ID,
+		Date:           note.Date,
+		Time:           note.Time,
+		Source:         note.Source.DisplayName,
+		ScientificName: note.ScientificName,
+		CommonName:     note.CommonName,
+		SpeciesCode:    note.SpeciesCode,
+		Confidence:     note.Confidence,
+		Latitude:       note.Latitude,
+		Longitude:      note.Longitude,
+		Threshold:      note.Threshold,
+		Sensitivity:    note.Sensitivity,
+		BeginTime:      note.BeginTime,
+		EndTime:        note.EndTime,
+		ClipName:       filepath.ToSlash(note.ClipName),
+	}
+}
+
+// fetchCursor asks the hub for the last detection of this station it stored.
+func (p *Pusher) fetchCursor(ctx context.Context) (uint, error) {
+	req, err := p.newRequest(ctx, http.MethodGet, cursorSuffix, nil)
+	if err != nil {
+		return 0, err
+	}
+	var c Cursor
+	if err := p.do(req, &c, "fetch_cursor"); err != nil {
+		return 0, err
+	}
+	return c.Cursor, nil
+}
+
+// pushBatch sends a batch of detections to the hub.
+func (p *Pusher) pushBatch(ctx context.Context, batch *Batch) (*BatchResult, error) {
+	body, err := json.Marshal(batch)
+	if err != nil {
+		return nil, p.syncError(err, "encode_batch")
+	}
+	req, err := p.newRequest(ctx, http.MethodPost, detectionSuffix, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var result BatchResult
+	if err := p.do(req, &result, "push_detections"); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// uploadClip sends a clip to the hub. A clip removed from disk, for example
+// by retention, returns an error satisfying os.IsNotExist, a clip the hub
+// would refuse returns ErrClipTooLarge. Both stay on the station.
+func (p *Pusher) uploadClip(ctx context.Context, clipName string) error {
+	f, err := os.Open(filepath.Join(p.clipsDir, filepath.FromSlash(clipName)))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	if info, err := f.Stat(); err == nil && info.Size() > MaxClipSize {
+		return ErrClipTooLarge
+	}
+
+	escaped := make([]string, 0, strings.Count(clipName, "/")+1)
+	for part := range strings.SplitSeq(clipName, "/") {
+		escaped = append(escaped, url.PathEscape(part))
+	}
+	req, err := p.newRequest(ctx, http.MethodPut, clipsSuffix+strings.Join(escaped, "/"), f)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	return p.do(req, nil, "upload_clip")
+}
+
+func (p *Pusher) newRequest(ctx context.Context, method, suffix string, body io.Reader) (*http.Request, error) {
+	endpoint := strings.TrimRight(p.settings.URL, "/") + apiStationsPath + url.PathEscape(p.settings.Station) + suffix
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, p.syncError(err, "build_request")
+	}
+	req.Header.Set("Authorization", "Bearer "+p.settings.Token)
+	return req, nil
+}
+
+// do sends a request and decodes the JSON answer into out, if out is not nil.
+func (p *Pusher) do(req *http.Request, out any, operation string) error {
+	resp, err := p.client.Do(req.Context(), req)
+	if err != nil {
+		return p.syncError(err, operation)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return p.syncError(err, operation)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return p.syncError(fmt.Errorf("hub answered %s: %s", resp.Status, strings.TrimSpace(string(body))), operation)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return p.syncError(err, operation)
+	}
+	return nil
+}
+
+func (p *Pusher) syncError(err error, operation string) error {
+	return errors.New(err).
+		Component("hub").
+		Category(errors.CategoryNetwork).
+		Context("hub", p.settings.URL).
+		Context("operation", operation).
+		Build()
+}
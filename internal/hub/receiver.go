@@ -0,0 +1,231 @@
+package hub
+
+import (
+	"cmp"
+	"context"
+	stderrors "errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// DetectionStore is the part of the datastore the receiver writes detections to.
+type DetectionStore interface {
+	Save(note *datastore.Note, results []datastore.Results) error
+}
+
+// Receiver stores the detections stations push to the hub.
+type Receiver struct {
+	store    DetectionStore
+	stations repository.StationRepository
+	clipsDir string // clip export path; uploaded clips go below it
+	// mu serializes batches so a station retrying a request while the first
+	// attempt is still running cannot store a detection twice
+	mu sync.Mutex
+}
+
+// NewReceiver creates a receiver storing detections in store and uploaded
+// clips below clipsDir.
+func NewReceiver(store DetectionStore, stations repository.StationRepository, clipsDir string) *Receiver {
+	return &Receiver{
+		store:    store,
+		stations: stations,
+		clipsDir: clipsDir,
+	}
+}
+
+// Cursor returns the highest detection ID of a station stored on the hub.
+// A station that never pushed starts at 0.
+func (r *Receiver) Cursor(ctx context.Context, station string) (uint, error) {
+	s, err := r.stations.GetStation(ctx, station)
+	if stderrors.Is(err, repository.ErrStationNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return s.LastRemoteID, nil
+}
+
+// Receive stores a batch of detections of a station. Detections stored
+// before are counted as duplicates and skipped, so a station can resend a
+// batch whose answer it never received. The notes and the station tables may
+// live in different databases, so each detection is claimed before its note
+// is saved and the claim is released when the save fails: a failed request
+// never leaves a detection that a resend stores again.
+func (r *Receiver) Receive(ctx context.Context, station string, batch *Batch) (*BatchResult, error) {
+	if err := validateBatch(batch, conf.MaxHubBatchSize); err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, err := r.stations.GetOrCreateStation(ctx, station)
+	if err != nil {
+		return nil, err
+	}
+
+	detections := slices.Clone(batch.Detections)
+	slices.SortFunc(detections, func(a, b Detection) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	result := &BatchResult{}
+	for i := range detections {
+		d := &detections[i]
+		reserved, err := r.stations.ReserveStationDetection(ctx, s.ID, d.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !reserved {
+			result.Duplicates++
+			if err := r.stations.AdvanceStationCursor(ctx, s.ID, d.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		note := r.buildNote(station, d)
+		if err := r.store.Save(note, nil); err != nil {
+			if releaseErr := r.stations.ReleaseStationDetection(ctx, s.ID, d.ID); releaseErr != nil {
+				GetLogger().Warn("failed to release station detection",
+					logger.String("station", station),
+					logger.Uint64("remote_id", uint64(d.ID)),
+					logger.Error(releaseErr))
+			}
+			return nil, errors.New(err).
+				Component("hub").
+				Category(errors.CategoryDatabase).
+				Context("station", station).
+				Context("remote_id", d.ID).
+				Build()
+		}
+		// The note is stored, a failure here leaves the claim in place so a
+		// resend counts the detection as a duplicate
+		if err := r.stations.RecordStationDetection(ctx, s.ID, d.ID, note.ID); err != nil {
+			return nil, err
+		}
+		result.Stored++
+	}
+
+	if err := r.stations.MarkStationSynced(ctx, s.ID, batch.Version, time.Now()); err != nil {
+		return nil, err
+	}
+	cursor, err := r.Cursor(ctx, station)
+	if err != nil {
+		return nil, err
+	}
+	result.Cursor = cursor
+
+	if result.Stored > 0 {
+		GetLogger().Info("stored station detections",
+			logger.String("station", station),
+			logger.Int("stored", result.Stored),
+			logger.Int("duplicates", result.Duplicates),
+			logger.Uint64("cursor", uint64(cursor)))
+	}
+	return result, nil
+}
+
+// buildNote converts a pushed detection to a note of the station.
+func (r *Receiver) buildNote(station string, d *Detection) *datastore.Note {
+	source := datastore.AudioSource{
+		ID:          "station:" + station,
+		SafeString:  "station:" + station,
+		DisplayName: station,
+	}
+	if d.Source != "" {
+		source.SafeString += "/" + d.Source
+		source.DisplayName = d.Source
+	}
+
+	return &datastore.Note{
+		SourceNode:     station,
+		Source:         source,
+		Date:           d.Date,
+		Time:           d.Time,
+		BeginTime:      d.BeginTime,
+		EndTime:        d.EndTime,
+		SpeciesCode:    d.SpeciesCode,
+		ScientificName: d.ScientificName,
+		CommonName:     d.CommonName,
+		Confidence:     d.Confidence,
+		Latitude:       d.Latitude,
+		Longitude:      d.Longitude,
+		Threshold:      d.Threshold,
+		Sensitivity:    d.Sensitivity,
+		ClipName:       r.uploadedClip(station, d),
+	}
+}
+
+// uploadedClip returns the clip path of a detection if its clip was uploaded
+// to the hub. Without an upload the clip stays on the station.
+func (r *Receiver) uploadedClip(station string, d *Detection) string {
+	if !d.ClipUploaded || d.ClipName == "" {
+		return ""
+	}
+	clipPath, err := ClipPath(station, d.ClipName)
+	if err != nil {
+		return ""
+	}
+	if _, err := os.Stat(filepath.Join(r.clipsDir, filepath.FromSlash(clipPath))); err != nil {
+		return ""
+	}
+	return clipPath
+}
+
+// SaveClip stores a clip uploaded by a station. The clip is written to a
+// temporary file first so an interrupted upload never leaves a partial clip.
+// Clips larger than MaxClipSize are refused with ErrClipTooLarge.
+func (r *Receiver) SaveClip(station, clipName string, body io.Reader) error {
+	clipPath, err := ClipPath(station, clipName)
+	if err != nil {
+		return err
+	}
+	target := filepath.Join(r.clipsDir, filepath.FromSlash(clipPath))
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return clipError(err, station, "create_clip_dir")
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return clipError(err, station, "create_temp_clip")
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	n, err := io.Copy(tmp, io.LimitReader(body, MaxClipSize+1))
+	if err != nil {
+		_ = tmp.Close()
+		return clipError(err, station, "write_clip")
+	}
+	if n > MaxClipSize {
+		_ = tmp.Close()
+		return ErrClipTooLarge
+	}
+	if err := tmp.Close(); err != nil {
+		return clipError(err, station, "close_clip")
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return clipError(err, station, "rename_clip")
+	}
+	return nil
+}
+
+func clipError(err error, station, operation string) error {
+	return errors.New(err).
+		Component("hub").
+		Category(errors.CategoryFileIO).
+		Context("station", station).
+		Context("operation", operation).
+		Build()
+}
@@ -0,0 +1,295 @@
+// adaptive_overlap.go: lowers the analysis overlap of a source while inference cannot keep up
+package myaudio
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Every chunk BirdNET analyzes is 3 seconds long and a new one starts every
+// 3 - overlap seconds. When inference slows down, from thermal throttling or
+// other containers competing for the CPU, chunks take longer to analyze than
+// the interval between them, the analysis buffer fills up and audio is
+// dropped. The controller watches the inference latency of each source, the
+// analysis buffer backlog and the batched inference queue, lowers the overlap
+// of a struggling source step by step and restores it once the load has been
+// low for a while. The false positive filter follows the lowered overlap so
+// it keeps requiring the same share of possible detections.
+
+const (
+	chunkDurationSeconds = 3.0 // BirdNET analyzes 3 second chunks
+
+	overlapStep              = 0.5              // seconds the overlap moves per adjustment
+	overlapLatencySmoothing  = 0.2              // weight of the newest latency in the running average
+	overlapPressureRatio     = 0.8              // latency share of the chunk interval counted as pressure
+	overlapHeadroomRatio     = 0.5              // latency share of the restored chunk interval that allows restoring
+	overlapBacklogPressure   = 0.8              // analysis buffer fill counted as pressure, below the overrun warning
+	overlapBacklogHeadroom   = 0.7              // analysis buffer fill that allows restoring
+	overlapLowerCooldown     = 10 * time.Second // time for a lowered overlap to take effect before lowering again
+	overlapRestoreAfter      = 2 * time.Minute  // sustained headroom before restoring a step
+	maxOverlapAdjustmentsLog = 50               // adjustments kept for the API
+)
+
+// OverlapAdjustment records one change of the overlap of a source.
+type OverlapAdjustment struct {
+	Time       time.Time `json:"time"`
+	SourceID   string    `json:"source_id"`
+	From       float64   `json:"from"`
+	To         float64   `json:"to"`
+	Reason     string    `json:"reason"`
+	Latency    float64   `json:"latency_seconds"` // average inference latency when adjusting
+	BufferFill float64   `json:"buffer_fill"`     // share of the analysis buffer waiting to be read
+	QueueDepth int       `json:"queue_depth"`     // chunks waiting for batched inference
+}
+
+// Lowered reports whether the adjustment lowered the overlap.
+func (a *OverlapAdjustment) Lowered() bool {
+	return a.To < a.From
+}
+
+// OverlapState describes the overlap of a source tracked by the controller.
+type OverlapState struct {
+	SourceID   string    `json:"source_id"`
+	Configured float64   `json:"configured"`
+	Effective  float64   `json:"effective"`
+	Latency    float64   `json:"latency_seconds"` // average inference latency
+	Changed    time.Time `json:"changed,omitzero"`
+}
+
+// overlapSample is what the analysis of one chunk tells about the load
+type overlapSample struct {
+	latency    time.Duration // time to analyze the chunk, including waiting for the model
+	bufferFill float64       // share of the analysis buffer waiting to be read
+	queueDepth int           // chunks waiting for batched inference
+	batchSize  int           // chunks per model invocation
+}
+
+// sourceOverlap is the controller state of one source
+type sourceOverlap struct {
+	configured float64
+	effective  float64
+	latency    float64 // average latency in seconds
+	changed    time.Time
+	calmSince  time.Time // start of the current headroom period, zero under load
+}
+
+// overlapController adapts the overlap of each source to the inference load
+type overlapController struct {
+	mu          sync.Mutex
+	sources     map[string]*sourceOverlap
+	adjustments []OverlapAdjustment
+}
+
+// overlapCtl is the controller shared by all analysis buffers
+var overlapCtl = newOverlapController()
+
+func newOverlapController() *overlapController {
+	return &overlapController{sources: make(map[string]*sourceOverlap)}
+}
+
+// observe feeds the load seen while analyzing a chunk of a source and returns
+// the adjustment made, if any. The overlap stays between minOverlap and the
+// configured overlap.
+func (c *overlapController) observe(sourceID string, configured, minOverlap float64, sample overlapSample, now time.Time) *OverlapAdjustment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st, ok := c.sources[sourceID]
+	if !ok {
+		st = &sourceOverlap{configured: configured, effective: configured}
+		c.sources[sourceID] = st
+	}
+	if st.configured != configured {
+		// An overlap that was not lowered follows the setting, a lowered one never exceeds it
+		if st.effective == st.configured {
+			st.effective = configured
+		}
+		st.effective = min(st.effective, configured)
+		st.configured = configured
+	}
+
+	latency := sample.latency.Seconds()
+	if st.latency == 0 {
+		st.latency = latency
+	} else {
+		st.latency += overlapLatencySmoothing * (latency - st.latency)
+	}
+
+	floor := min(max(minOverlap, 0), configured)
+	interval := chunkDurationSeconds - st.effective
+	var reason string
+	switch {
+	case st.latency > overlapPressureRatio*interval:
+		reason = fmt.Sprintf("inference takes %.2fs of the %.2fs between chunks", st.latency, interval)
+	case sample.bufferFill > overlapBacklogPressure:
+		reason = fmt.Sprintf("analysis buffer %.0f%% full", sample.bufferFill*100)
+	case sample.batchSize > 1 && sample.queueDepth > sample.batchSize:
+		reason = fmt.Sprintf("%d chunks waiting for batched inference", sample.queueDepth)
+	}
+
+	if reason != "" {
+		st.calmSince = time.Time{}
+		if st.effective <= floor || now.Sub(st.changed) < overlapLowerCooldown {
+			return nil
+		}
+		return c.adjust(sourceID, st, max(floor, st.effective-overlapStep), reason, sample, now)
+	}
+
+	if st.effective >= configured {
+		return nil
+	}
+	restored := min(configured, st.effective+overlapStep)
+	if st.latency >= overlapHeadroomRatio*(chunkDurationSeconds-restored) || sample.bufferFill > overlapBacklogHeadroom {
+		st.calmSince = time.Time{}
+		return nil
+	}
+	if st.calmSince.IsZero() {
+		st.calmSince = now
+	}
+	if now.Sub(st.calmSince) < overlapRestoreAfter {
+		return nil
+	}
+	st.calmSince = now // every further step needs another period of headroom
+	return c.adjust(sourceID, st, restored, "load dropped", sample, now)
+}
+
+// release restores the configured overlap of a source once adaptation is
+// disabled and forgets the source.
+func (c *overlapController) release(sourceID string, now time.Time) *OverlapAdjustment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	st, ok := c.sources[sourceID]
+	if !ok {
+		return nil
+	}
+	delete(c.sources, sourceID)
+	if st.effective == st.configured {
+		return nil
+	}
+	return c.adjust(sourceID, st, st.configured, "adaptive overlap disabled", overlapSample{}, now)
+}
+
+// forget drops the state of a removed source.
+func (c *overlapController) forget(sourceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.sources, sourceID)
+}
+
+// adjust moves the overlap of a source and records the change. Callers hold c.mu.
+func (c *overlapController) adjust(sourceID string, st *sourceOverlap, overlap float64, reason string, sample overlapSample, now time.Time) *OverlapAdjustment {
+	adj := OverlapAdjustment{
+		Time:       now,
+		SourceID:   sourceID,
+		From:       st.effective,
+		To:         overlap,
+		Reason:     reason,
+		Latency:    st.latency,
+		BufferFill: sample.bufferFill,
+		QueueDepth: sample.queueDepth,
+	}
+	st.effective = overlap
+	st.changed = now
+
+	c.adjustments = append(c.adjustments, adj)
+	if len(c.adjustments) > maxOverlapAdjustmentsLog {
+		c.adjustments = slices.Clone(c.adjustments[len(c.adjustments)-maxOverlapAdjustmentsLog:])
+	}
+	return &adj
+}
+
+// lowered returns the overlap of a source when the controller lowered it.
+func (c *overlapController) lowered(sourceID string) (float64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	st, ok := c.sources[sourceID]
+	if !ok || st.effective >= st.configured {
+		return 0, false
+	}
+	return st.effective, true
+}
+
+// states lists the tracked sources ordered by ID.
+func (c *overlapController) states() []OverlapState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	states := make([]OverlapState, 0, len(c.sources))
+	for sourceID, st := range c.sources {
+		states = append(states, OverlapState{
+			SourceID:   sourceID,
+			Configured: st.configured,
+			Effective:  st.effective,
+			Latency:    st.latency,
+			Changed:    st.changed,
+		})
+	}
+	slices.SortFunc(states, func(a, b OverlapState) int { return strings.Compare(a.SourceID, b.SourceID) })
+	return states
+}
+
+// history returns the recent adjustments, oldest first.
+func (c *overlapController) history() []OverlapAdjustment {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.adjustments)
+}
+
+// AdaptedOverlap returns the overlap of a source in seconds when it was
+// lowered because inference could not keep up.
+func AdaptedOverlap(sourceID string) (overlap float64, adapted bool) {
+	return overlapCtl.lowered(sourceID)
+}
+
+// GetOverlapStates reports the overlap of every source analyzed with
+// adaptive overlap enabled.
+func GetOverlapStates() []OverlapState {
+	return overlapCtl.states()
+}
+
+// GetOverlapAdjustments returns the most recent overlap adjustments, oldest first.
+func GetOverlapAdjustments() []OverlapAdjustment {
+	return overlapCtl.history()
+}
+
+// adaptOverlap feeds the analysis of a chunk to the overlap controller and
+// reports any adjustment it makes.
+func adaptOverlap(sourceID string, sample overlapSample) {
+	settings := conf.Setting().BirdNET
+	now := time.Now()
+
+	var adj *OverlapAdjustment
+	if settings.AdaptiveOverlap.Enabled {
+		adj = overlapCtl.observe(sourceID, settings.Overlap, settings.AdaptiveOverlap.MinOverlap, sample, now)
+	} else {
+		adj = overlapCtl.release(sourceID, now)
+	}
+	if adj == nil {
+		return
+	}
+
+	direction, msg := "restored", "analysis overlap restored"
+	if adj.Lowered() {
+		direction, msg = "lowered", "analysis overlap lowered, inference cannot keep up"
+	}
+	GetLogger().Info(msg,
+		logger.String("source_id", sourceID),
+		logger.Float64("from", adj.From),
+		logger.Float64("to", adj.To),
+		logger.Float64("configured", settings.Overlap),
+		logger.String("reason", adj.Reason),
+		logger.Float64("latency_seconds", adj.Latency),
+		logger.Float64("buffer_fill", adj.BufferFill),
+		logger.Int("queue_depth", adj.QueueDepth),
+		logger.String("operation", "adaptive_overlap"))
+
+	if m := getAnalysisMetrics(); m != nil {
+		m.RecordAnalysisOverlapAdjustment(sourceID, direction, adj.To)
+	}
+}
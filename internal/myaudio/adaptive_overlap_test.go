@@ -0,0 +1,200 @@
+package myaudio
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/smallnest/ringbuffer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// slow is a sample of a source whose chunks take the given seconds to analyze
+func slow(seconds float64) overlapSample {
+	return overlapSample{latency: time.Duration(seconds * float64(time.Second)), bufferFill: 0.6}
+}
+
+func TestOverlapController_LowersUnderPressure(t *testing.T) {
+	t.Parallel()
+
+	c := newOverlapController()
+	start := time.Now()
+
+	adj := c.observe("camera", 1.5, 0.5, slow(2.5), start)
+	require.NotNil(t, adj)
+	assert.True(t, adj.Lowered())
+	assert.InDelta(t, 1.0, adj.To, 1e-9)
+	assert.Contains(t, adj.Reason, "inference takes")
+
+	// A lowered overlap gets time to take effect
+	assert.Nil(t, c.observe("camera", 1.5, 0.5, slow(2.5), start.Add(time.Second)))
+
+	adj = c.observe("camera", 1.5, 0.5, slow(2.5), start.Add(overlapLowerCooldown))
+	require.NotNil(t, adj)
+	assert.InDelta(t, 0.5, adj.To, 1e-9)
+
+	// Never below the minimum
+	assert.Nil(t, c.observe("camera", 1.5, 0.5, slow(2.5), start.Add(2*overlapLowerCooldown)))
+	overlap, adapted := c.lowered("camera")
+	assert.True(t, adapted)
+	assert.InDelta(t, 0.5, overlap, 1e-9)
+
+	// Other sources are not affected
+	_, adapted = c.lowered("microphone")
+	assert.False(t, adapted)
+	assert.Len(t, c.history(), 2)
+}
+
+func TestOverlapController_BacklogAndQueuePressure(t *testing.T) {
+	t.Parallel()
+
+	c := newOverlapController()
+	now := time.Now()
+
+	adj := c.observe("camera", 1.5, 0, overlapSample{latency: 100 * time.Millisecond, bufferFill: 0.85}, now)
+	require.NotNil(t, adj)
+	assert.Contains(t, adj.Reason, "analysis buffer 85% full")
+
+	// Queued chunks only count beyond a full batch
+	assert.Nil(t, c.observe("rtsp", 1.5, 0, overlapSample{latency: 100 * time.Millisecond, bufferFill: 0.6, queueDepth: 4, batchSize: 4}, now))
+	adj = c.observe("rtsp", 1.5, 0, overlapSample{latency: 100 * time.Millisecond, bufferFill: 0.6, queueDepth: 5, batchSize: 4}, now.Add(time.Second))
+	require.NotNil(t, adj)
+	assert.Equal(t, 5, adj.QueueDepth)
+}
+
+func TestOverlapController_RestoresAfterSustainedHeadroom(t *testing.T) {
+	t.Parallel()
+
+	c := newOverlapController()
+	start := time.Now()
+	require.NotNil(t, c.observe("camera", 1.5, 0, slow(2.5), start))
+	require.NotNil(t, c.observe("camera", 1.5, 0, slow(2.5), start.Add(overlapLowerCooldown)))
+
+	// Latency decays towards the fast samples before counting as headroom
+	now := start.Add(overlapLowerCooldown)
+	for range 30 {
+		now = now.Add(time.Second)
+		assert.Nil(t, c.observe("camera", 1.5, 0, slow(0.2), now))
+	}
+
+	// A spike restarts the headroom period
+	assert.Nil(t, c.observe("camera", 1.5, 0, overlapSample{latency: 200 * time.Millisecond, bufferFill: 0.75}, now))
+
+	calm := now
+	var restored *OverlapAdjustment
+	for restored == nil && now.Sub(calm) < 2*overlapRestoreAfter {
+		now = now.Add(10 * time.Second)
+		restored = c.observe("camera", 1.5, 0, slow(0.2), now)
+	}
+	require.NotNil(t, restored)
+	assert.False(t, restored.Lowered())
+	assert.InDelta(t, 1.0, restored.To, 1e-9)
+	assert.Equal(t, "load dropped", restored.Reason)
+	overlap, adapted := c.lowered("camera")
+	require.True(t, adapted)
+	assert.InDelta(t, 1.0, overlap, 1e-9)
+	assert.GreaterOrEqual(t, now.Sub(calm), overlapRestoreAfter)
+
+	// Every step needs its own headroom period
+	assert.Nil(t, c.observe("camera", 1.5, 0, slow(0.2), now.Add(time.Second)))
+	require.NotNil(t, c.observe("camera", 1.5, 0, slow(0.2), now.Add(overlapRestoreAfter)))
+	_, adapted = c.lowered("camera")
+	assert.False(t, adapted)
+}
+
+func TestOverlapController_SettingsChanges(t *testing.T) {
+	t.Parallel()
+
+	c := newOverlapController()
+	now := time.Now()
+	require.NotNil(t, c.observe("camera", 2.0, 0, slow(2.5), now))
+
+	// A lowered overlap never exceeds a lowered setting
+	assert.Nil(t, c.observe("camera", 1.0, 0, slow(0.5), now.Add(time.Second)))
+	states := c.states()
+	require.Len(t, states, 1)
+	assert.InDelta(t, 1.0, states[0].Effective, 1e-9)
+	assert.InDelta(t, 1.0, states[0].Configured, 1e-9)
+
+	// Disabling restores the configured overlap at once
+	require.NotNil(t, c.observe("camera", 1.0, 0, slow(2.5), now.Add(time.Minute)))
+	adj := c.release("camera", now.Add(2*time.Minute))
+	require.NotNil(t, adj)
+	assert.Equal(t, "adaptive overlap disabled", adj.Reason)
+	assert.InDelta(t, 1.0, adj.To, 1e-9)
+	assert.Empty(t, c.states())
+	assert.Nil(t, c.release("camera", now.Add(3*time.Minute)))
+}
+
+// NOTE: Do NOT use t.Parallel() - this test replaces the analysis buffer globals
+func TestReadFromAnalysisBuffer_FollowsAdaptedOverlap(t *testing.T) {
+	const sourceID = "adaptive_overlap_read"
+
+	savedOverlap, savedRead, savedPool := overlapSize, readSize, readBufferPool
+	overlapSize = SecondsToBytes(1.5)
+	readSize = conf.BufferSize - overlapSize
+	readBufferPool = nil
+
+	ab := ringbuffer.New(conf.BufferSize * 3)
+	abMutex.Lock()
+	if analysisBuffers == nil {
+		analysisBuffers = make(map[string]*ringbuffer.RingBuffer)
+	}
+	if prevData == nil {
+		prevData = make(map[string][]byte)
+	}
+	analysisBuffers[sourceID] = ab
+	prevData[sourceID] = nil
+	abMutex.Unlock()
+
+	t.Cleanup(func() {
+		abMutex.Lock()
+		delete(analysisBuffers, sourceID)
+		delete(prevData, sourceID)
+		overlapSize, readSize, readBufferPool = savedOverlap, savedRead, savedPool
+		abMutex.Unlock()
+		overlapCtl.forget(sourceID)
+	})
+
+	stream := make([]byte, conf.BufferSize*8)
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // G404: test data
+	for i := range stream {
+		stream[i] = byte(rng.Uint32())
+	}
+	written := 0
+	nextChunk := func() []byte {
+		t.Helper()
+		for range 20 {
+			n, _ := ab.Write(stream[written : written+min(ab.Free(), len(stream)-written)])
+			written += n
+			data, err := ReadFromAnalysisBuffer(sourceID)
+			require.NoError(t, err)
+			if data != nil {
+				return append([]byte(nil), data...)
+			}
+		}
+		require.FailNow(t, "no chunk read")
+		return nil
+	}
+
+	assert.Equal(t, stream[:conf.BufferSize], nextChunk())
+	start := conf.BufferSize - overlapSize
+
+	// Lower the overlap as the controller would under pressure
+	overlapCtl.mu.Lock()
+	overlapCtl.sources[sourceID] = &sourceOverlap{configured: 1.5, effective: 0.5}
+	overlapCtl.mu.Unlock()
+
+	assert.Equal(t, stream[start:start+conf.BufferSize], nextChunk())
+	start += conf.BufferSize - SecondsToBytes(0.5)
+	assert.Equal(t, stream[start:start+conf.BufferSize], nextChunk())
+
+	// After restoring, the overlap kept from the last chunk is the lowered one
+	overlapCtl.forget(sourceID)
+	start += conf.BufferSize - SecondsToBytes(0.5)
+	assert.Equal(t, stream[start:start+conf.BufferSize], nextChunk())
+	start += conf.BufferSize - overlapSize
+	assert.Equal(t, stream[start:start+conf.BufferSize], nextChunk())
+}
@@ -222,6 +222,7 @@ func RemoveAnalysisBuffer(sourceID string) error {
 	delete(analysisBuffers, sourceID)
 	delete(prevData, sourceID)
 	delete(warningCounter, sourceID)
+	overlapCtl.forget(sourceID)
 
 	// Clean up buffer pool if this was the last buffer (prevents memory leak)
 	if len(analysisBuffers) == 0 && readBufferPool != nil {
@@ -522,9 +523,13 @@ func ReadFromAnalysisBuffer(sourceID string) ([]byte, error) {
 		return nil, enhancedErr
 	}
 
+	// Read what completes the next chunk after the overlap kept from the previous one,
+	// the overlap may have been lowered to keep up with slow inference
+	toRead := conf.BufferSize - len(prevData[sourceID])
+
 	// Calculate the number of bytes written to the buffer
 	bytesWritten := ab.Length() - ab.Free()
-	if bytesWritten < readSize {
+	if bytesWritten < toRead {
 		// Not enough data available - record metrics but return nil (not an error)
 		if m := getAnalysisMetrics(); m != nil {
 			m.RecordBufferRead("analysis", sourceID, "insufficient_data")
@@ -535,11 +540,12 @@ func ReadFromAnalysisBuffer(sourceID string) ([]byte, error) {
 
 	// Get a buffer from the pool instead of allocating new
 	var data []byte
-	if readBufferPool != nil {
+	pooled := readBufferPool != nil && toRead == readSize
+	if pooled {
 		data = readBufferPool.Get()
 	} else {
-		// Fallback if pool not initialized
-		data = make([]byte, readSize)
+		// Fallback if pool not initialized or the overlap differs from the configured one
+		data = make([]byte, toRead)
 	}
 
 	// Read data from the ring buffer
@@ -550,7 +556,7 @@ func ReadFromAnalysisBuffer(sourceID string) ([]byte, error) {
 			Category(errors.CategorySystem).
 			Context("operation", "read_from_analysis_buffer").
 			Context("source_id", sourceID).
-			Context("requested_bytes", readSize).
+			Context("requested_bytes", toRead).
 			Context("bytes_read", bytesRead).
 			Context("buffer_length", ab.Length()).
 			Context("buffer_free", ab.Free()).
@@ -562,7 +568,7 @@ func ReadFromAnalysisBuffer(sourceID string) ([]byte, error) {
 		}
 
 		// Return buffer to pool on error
-		if readBufferPool != nil {
+		if pooled {
 			readBufferPool.Put(data)
 		}
 		return nil, enhancedErr
@@ -570,17 +576,17 @@ func ReadFromAnalysisBuffer(sourceID string) ([]byte, error) {
 
 	// Join with previous data to ensure we're processing chunkSize bytes
 	var fullData []byte
-	prevData[sourceID] = append(prevData[sourceID], data...)
+	prevData[sourceID] = append(prevData[sourceID], data[:bytesRead]...)
 	fullData = prevData[sourceID]
 
 	// Return buffer to pool after copying data
-	if readBufferPool != nil {
+	if pooled {
 		readBufferPool.Put(data)
 	}
 	if len(fullData) >= conf.BufferSize {
-		// Update prevData for the next iteration
-		prevData[sourceID] = fullData[readSize:]
+		// Keep the overlap for the next iteration
 		fullData = fullData[:conf.BufferSize]
+		prevData[sourceID] = fullData[conf.BufferSize-chunkOverlapBytes(sourceID):]
 
 		// Record successful read metrics
 		if m := getAnalysisMetrics(); m != nil {
@@ -603,6 +609,29 @@ func ReadFromAnalysisBuffer(sourceID string) ([]byte, error) {
 	}
 }
 
+// chunkOverlapBytes returns the bytes consecutive chunks of a source share,
+// aligned to whole samples.
+func chunkOverlapBytes(sourceID string) int {
+	overlap, adapted := AdaptedOverlap(sourceID)
+	if !adapted {
+		return overlapSize
+	}
+	bytes := SecondsToBytes(overlap)
+	return bytes - bytes%(conf.BitDepth/8)
+}
+
+// analysisBufferFill returns the share of the analysis buffer of a source
+// waiting to be read.
+func analysisBufferFill(sourceID string) float64 {
+	abMutex.RLock()
+	defer abMutex.RUnlock()
+	ab, exists := analysisBuffers[sourceID]
+	if !exists || ab.Capacity() == 0 {
+		return 0
+	}
+	return float64(ab.Length()) / float64(ab.Capacity())
+}
+
 // AnalysisBufferExists checks if an analysis buffer exists for the given source
 // Accepts either original source string or migrated source ID
 // This is a thread-safe exported function that encapsulates access to the internal buffer map
@@ -662,12 +691,19 @@ func AnalysisBufferMonitor(_ *sync.WaitGroup, bn *birdnet.BirdNET, quitChan chan
 				processingStart := time.Now()
 
 				err := ProcessData(bn, data, startTime, sourceID)
+				processingDuration := time.Since(processingStart)
 
 				if m := getAnalysisMetrics(); m != nil {
-					processingDuration := time.Since(processingStart).Seconds()
-					m.RecordAnalysisBufferProcessingDuration(sourceID, processingDuration)
+					m.RecordAnalysisBufferProcessingDuration(sourceID, processingDuration.Seconds())
 				}
 
+				adaptOverlap(sourceID, overlapSample{
+					latency:    processingDuration,
+					bufferFill: analysisBufferFill(sourceID),
+					queueDepth: bn.BatchStats().QueueDepth,
+					batchSize:  conf.Setting().BirdNET.BatchSize,
+				})
+
 				if err != nil {
 					log.Error("error processing data",
 						logger.String("source_id", sourceID),
@@ -0,0 +1,204 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// fakeArchiveEncoder records the PCM written to a segment into its file
+type fakeArchiveEncoder struct {
+	path     string
+	channels int
+	pcm      []byte
+}
+
+func (e *fakeArchiveEncoder) Write(pcm []byte) error {
+	e.pcm = append(e.pcm, pcm...)
+	return nil
+}
+
+func (e *fakeArchiveEncoder) Close() error {
+	return os.WriteFile(e.path, e.pcm, 0o600)
+}
+
+// useFakeArchiveEncoder replaces the segment encoder for a test and returns the created encoders
+func useFakeArchiveEncoder(t *testing.T) *[]*fakeArchiveEncoder {
+	t.Helper()
+	var (
+		mu       sync.Mutex
+		encoders []*fakeArchiveEncoder
+	)
+	original := newArchiveEncoder
+	newArchiveEncoder = func(path string, channels int) (archiveEncoder, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		encoder := &fakeArchiveEncoder{path: path, channels: channels}
+		encoders = append(encoders, encoder)
+		return encoder, nil
+	}
+	t.Cleanup(func() { newArchiveEncoder = original })
+	return &encoders
+}
+
+// writeFLACHeader writes a FLAC file holding only a STREAMINFO block
+func writeFLACHeader(t *testing.T, path string, channels int, totalSamples int64) {
+	t.Helper()
+	info := make([]byte, 34)
+	binary.BigEndian.PutUint16(info[0:], 4096)
+	binary.BigEndian.PutUint16(info[2:], 4096)
+	// 20 bits sample rate, 3 bits channels-1, 5 bits bits per sample-1, 36 bits total samples
+	packed := uint64(conf.SampleRate)<<44 | uint64(channels-1)<<41 | uint64(conf.BitDepth-1)<<36 | uint64(totalSamples) //nolint:gosec // G115: test header values are small
+	binary.BigEndian.PutUint64(info[10:], packed)
+
+	data := append([]byte("fLaC"), 0x80, 0, 0, 34)
+	data = append(data, info...)
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func TestArchiveSourceKey(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "front-yard", ArchiveSourceKey("  Front Yard "))
+	assert.Equal(t, "mic-1-left", ArchiveSourceKey("Mic #1 (left)"))
+	assert.Equal(t, "source", ArchiveSourceKey("../"))
+
+	start := time.Date(2026, 10, 18, 12, 5, 0, 0, time.UTC)
+	name := archiveSegmentName("front-yard", start)
+	assert.Equal(t, "front-yard_20261018T120500.000Z.flac", name)
+	parsed, ok := parseArchiveSegmentName("front-yard", name)
+	require.True(t, ok)
+	assert.True(t, start.Equal(parsed))
+	_, ok = parseArchiveSegmentName("pond", name)
+	assert.False(t, ok)
+}
+
+// NOTE: Do NOT use t.Parallel() - this test modifies the global source registry
+func TestArchiveKeyOfSource_SameDisplayName(t *testing.T) {
+	registry := GetRegistry()
+	register := func(url, name string) *AudioSource {
+		source, err := registry.RegisterSource(url, SourceConfig{DisplayName: name, Type: SourceTypeRTSP})
+		require.NoError(t, err)
+		t.Cleanup(func() { _ = registry.RemoveSource(source.ID) })
+		return source
+	}
+	garden := register("rtsp://archive-garden-a.example.com/stream", "Garden")
+	gardenToo := register("rtsp://archive-garden-b.example.com/stream", "garden!")
+	pond := register("rtsp://archive-pond.example.com/stream", "Pond")
+
+	gardenKey, ok := ArchiveKeyOfSource(garden.ID)
+	require.True(t, ok)
+	gardenTooKey, ok := ArchiveKeyOfSource(gardenToo.ID)
+	require.True(t, ok)
+	assert.NotEqual(t, gardenKey, gardenTooKey, "sources sharing a name get their own directory")
+	assert.Regexp(t, `^garden-[0-9a-f]{8}$`, gardenKey)
+	assert.Regexp(t, `^garden-[0-9a-f]{8}$`, gardenTooKey)
+	again, _ := ArchiveKeyOfSource(garden.ID)
+	assert.Equal(t, gardenKey, again)
+	pondKey, _ := ArchiveKeyOfSource(pond.ID)
+	assert.Equal(t, "pond", pondKey, "a unique name is used as is")
+	_, ok = ArchiveKeyOfSource("not_registered")
+	assert.False(t, ok)
+
+	archive := &audioArchive{settings: conf.ArchiveSettings{Path: t.TempDir()}, recorders: make(map[string]*archiveRecorder)}
+	first, second := archive.recorder(garden.ID), archive.recorder(gardenToo.ID)
+	for _, recorder := range []*archiveRecorder{first, second} {
+		close(recorder.stop)
+		<-recorder.done
+	}
+	assert.NotEqual(t, first.dir, second.dir)
+	assert.Equal(t, filepath.Join(archive.settings.Path, gardenKey), first.dir)
+}
+
+func TestArchiveRecorder_SplitsAtSegmentBoundaries(t *testing.T) {
+	encoders := useFakeArchiveEncoder(t)
+	dir := t.TempDir()
+	recorder := &archiveRecorder{sourceID: "archive_test", key: "yard", dir: dir, segmentLength: time.Second}
+
+	// One second of audio ending half a second into a segment
+	end := time.Date(2026, 10, 18, 12, 0, 1, 500_000_000, time.UTC)
+	recorder.write(archiveChunk{data: interleavedTestFrames(conf.SampleRate, 100), channels: 1, at: end})
+
+	require.Len(t, *encoders, 2, "the chunk crosses a segment boundary")
+	first := filepath.Join(dir, "2026-10-18", "yard_20261018T120000.500Z.flac")
+	assert.FileExists(t, first, "the completed segment is moved into place")
+	data, err := os.ReadFile(first)
+	require.NoError(t, err)
+	assert.Len(t, data, conf.SampleRate, "the first segment ends at the boundary")
+	assert.Equal(t, (*encoders)[1].path, filepath.Join(dir, "2026-10-18", "yard_20261018T120001.000Z.flac")+TempExt)
+
+	// A channel layout change starts a new segment
+	recorder.write(archiveChunk{data: interleavedTestFrames(conf.SampleRate/10, 1, 2), channels: 2, at: end.Add(100 * time.Millisecond)})
+	require.Len(t, *encoders, 3)
+	assert.Equal(t, 2, (*encoders)[2].channels)
+	assert.FileExists(t, filepath.Join(dir, "2026-10-18", "yard_20261018T120001.000Z.flac"))
+
+	recorder.finishSegment()
+	assert.FileExists(t, filepath.Join(dir, "2026-10-18", "yard_20261018T120001.500Z.flac"))
+}
+
+func TestListArchiveSegments(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	day := filepath.Join(dir, "yard", "2026-10-18")
+	base := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	for i := range 3 {
+		start := base.Add(time.Duration(i) * 5 * time.Minute)
+		writeFLACHeader(t, filepath.Join(day, archiveSegmentName("yard", start)), 2, 5*60*conf.SampleRate)
+	}
+	// Unfinished segments are not listed
+	writeFLACHeader(t, filepath.Join(day, archiveSegmentName("yard", base.Add(15*time.Minute))+TempExt), 2, 0)
+
+	segments, err := ListArchiveSegments(dir, "yard", base.Add(6*time.Minute), base.Add(11*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+	assert.True(t, base.Add(5*time.Minute).Equal(segments[0].Start))
+	assert.Equal(t, 5*time.Minute, segments[0].Duration)
+	assert.Equal(t, 2, segments[0].Channels)
+
+	sources, err := ListArchiveSources(dir)
+	require.NoError(t, err)
+	require.Len(t, sources, 1)
+	assert.Equal(t, "yard", sources[0].Source)
+	assert.Equal(t, 3, sources[0].Segments)
+
+	_, _, err = ReadArchiveRange(dir, "yard", base.Add(30*time.Minute), base.Add(31*time.Minute))
+	require.ErrorIs(t, err, ErrArchiveNoAudio)
+	_, _, err = ReadArchiveRange(dir, "yard", base, base.Add(time.Hour))
+	require.Error(t, err, "ranges longer than the maximum are rejected")
+}
+
+func TestReadArchiveRange_StitchesSegments(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	day := filepath.Join(dir, "yard", "2026-10-18")
+	require.NoError(t, os.MkdirAll(day, 0o750))
+	base := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	// Two one-second segments with a one-second gap between them
+	for i, value := range map[int]int16{0: 1000, 2: -1000} {
+		start := base.Add(time.Duration(i) * time.Second)
+		require.NoError(t, writeFLACFile(filepath.Join(day, archiveSegmentName("yard", start)),
+			interleavedTestFrames(conf.SampleRate, value, value), 2))
+	}
+
+	pcm, channels, err := ReadArchiveRange(dir, "yard", base.Add(500*time.Millisecond), base.Add(2500*time.Millisecond))
+	require.NoError(t, err)
+	assert.Equal(t, 2, channels)
+	samples := pcmSamples(pcm)
+	require.Len(t, samples, 2*2*conf.SampleRate)
+	frame := func(at time.Duration) int16 { return samples[int(at.Seconds()*conf.SampleRate)*2] }
+	assert.Equal(t, int16(1000), frame(0), "the range starts inside the first segment")
+	assert.Equal(t, int16(0), frame(time.Second), "gaps are filled with silence")
+	assert.Equal(t, int16(-1000), frame(1900*time.Millisecond), "the range ends inside the second segment")
+}
@@ -8,11 +8,19 @@ import (
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/myaudio/equalizer"
 	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+	"github.com/tphakala/birdnet-go/internal/privacy"
 )
 
-// Global variables for filter chain and mutex
+// Global variables for filter chain and mutex. filterChain is the default
+// chain built from the global equalizer settings, sourceFilterChains holds
+// the chains of individual sources keyed by source ID. Source chains are
+// built lazily from filterSettings so that every source keeps its own
+// filter state and may override the global equalizer.
 var (
 	filterChain        *equalizer.FilterChain
+	sourceFilterChains = make(map[string]*equalizer.FilterChain)
+	filterSettings     *conf.Settings
+	filterGeneration   uint64 // Incremented whenever the chains are rebuilt
 	filterMutex        sync.RWMutex
 	filterMetrics      *metrics.MyAudioMetrics // Global metrics instance for filter operations
 	filterMetricsMutex sync.RWMutex            // Mutex for thread-safe access to filterMetrics
@@ -46,6 +54,20 @@ func getFilterMetrics() *metrics.MyAudioMetrics {
 
 // InitializeFilterChain sets up the initial filter chain based on settings
 func InitializeFilterChain(settings *conf.Settings) error {
+	return rebuildFilterChains(settings, "initialize_filter_chain", "initialize_filters")
+}
+
+// UpdateFilterChain updates the filter chains based on new settings. The
+// global chain and every per-source override are validated before any
+// chain is replaced, so an invalid configuration leaves the running
+// filters untouched.
+func UpdateFilterChain(settings *conf.Settings) error {
+	return rebuildFilterChains(settings, "update_filter_chain", "update_filters")
+}
+
+// rebuildFilterChains replaces the default filter chain and drops the
+// cached source chains so they are rebuilt from the new settings.
+func rebuildFilterChains(settings *conf.Settings, operation, metricOperation string) error {
 	start := time.Now()
 
 	// Validate input
@@ -53,192 +75,235 @@ func InitializeFilterChain(settings *conf.Settings) error {
 		enhancedErr := errors.Newf("settings parameter is nil").
 			Component("myaudio").
 			Category(errors.CategoryValidation).
-			Context("operation", "initialize_filter_chain").
+			Context("operation", operation).
 			Build()
 
 		if m := getFilterMetrics(); m != nil {
-			m.RecordAudioProcessing("initialize_filters", "system", "error")
-			m.RecordAudioProcessingError("initialize_filters", "system", "nil_settings")
+			m.RecordAudioProcessing(metricOperation, "system", "error")
+			m.RecordAudioProcessingError(metricOperation, "system", "nil_settings")
 		}
 		return enhancedErr
 	}
 
+	newChain, err := buildFilterChain(&settings.Realtime.Audio.Equalizer, operation, metricOperation)
+	if err != nil {
+		return err
+	}
+
+	// Validate per-source overrides up front, their chains are built on first use
+	overrides := make(map[string]*conf.EqualizerSettings)
+	if settings.Realtime.Audio.SourceEqualizer != nil {
+		overrides[settings.Realtime.Audio.Source] = settings.Realtime.Audio.SourceEqualizer
+	}
+	for i := range settings.Realtime.RTSP.Streams {
+		if eq := settings.Realtime.RTSP.Streams[i].Equalizer; eq != nil {
+			overrides[settings.Realtime.RTSP.Streams[i].URL] = eq
+		}
+	}
+	for connection, eq := range overrides {
+		if _, err := buildFilterChain(eq, operation, metricOperation); err != nil {
+			return errors.New(err).
+				Component("myaudio").
+				Category(errors.CategoryConfiguration).
+				Context("operation", operation).
+				Context("source", privacy.SanitizeStreamUrl(connection)).
+				Build()
+		}
+	}
+
 	filterMutex.Lock()
-	defer filterMutex.Unlock()
+	filterChain = newChain
+	filterSettings = settings
+	filterGeneration++
+	clear(sourceFilterChains)
+	filterMutex.Unlock()
 
-	// Create a new filter chain
-	filterChain = equalizer.NewFilterChain()
-	if filterChain == nil {
+	// Record successful initialization or update
+	if m := getFilterMetrics(); m != nil {
+		duration := time.Since(start).Seconds()
+		m.RecordAudioProcessing(metricOperation, "system", "success")
+		m.RecordAudioProcessingDuration(metricOperation, "system", duration)
+	}
+
+	return nil
+}
+
+// buildFilterChain creates a filter chain from equalizer settings. A disabled
+// equalizer yields an empty chain.
+func buildFilterChain(eq *conf.EqualizerSettings, operation, metricOperation string) (*equalizer.FilterChain, error) {
+	chain := equalizer.NewFilterChain()
+	if chain == nil {
 		enhancedErr := errors.Newf("failed to create new filter chain").
 			Component("myaudio").
 			Category(errors.CategorySystem).
-			Context("operation", "initialize_filter_chain").
+			Context("operation", operation).
 			Build()
 
 		if m := getFilterMetrics(); m != nil {
-			m.RecordAudioProcessing("initialize_filters", "system", "error")
-			m.RecordAudioProcessingError("initialize_filters", "system", "chain_creation_failed")
+			m.RecordAudioProcessing(metricOperation, "system", "error")
+			m.RecordAudioProcessingError(metricOperation, "system", "chain_creation_failed")
 		}
-		return enhancedErr
+		return nil, enhancedErr
 	}
 
-	filterCount := 0
-	// If equalizer is enabled in settings, add filters
-	if settings.Realtime.Audio.Equalizer.Enabled {
-		for i, filterConfig := range settings.Realtime.Audio.Equalizer.Filters {
-			// Create and add each filter
-			filter, err := createFilter(filterConfig, float64(conf.SampleRate))
-			if err != nil {
-				// Skip disabled filters (not an error condition)
-				if errors.Is(err, ErrFilterDisabled) {
-					continue
-				}
+	// If equalizer is disabled there are no filters to add
+	if !eq.Enabled {
+		return chain, nil
+	}
+
+	for i, filterConfig := range eq.Filters {
+		// Create and add each filter
+		filter, err := createFilter(filterConfig, float64(conf.SampleRate))
+		if err != nil {
+			// Skip disabled filters (not an error condition)
+			if errors.Is(err, ErrFilterDisabled) {
+				continue
+			}
 
+			enhancedErr := errors.New(err).
+				Component("myaudio").
+				Category(errors.CategoryConfiguration).
+				Context("operation", operation).
+				Context("filter_index", i).
+				Context("filter_type", filterConfig.Type).
+				Context("filter_frequency", filterConfig.Frequency).
+				Build()
+
+			if m := getFilterMetrics(); m != nil {
+				m.RecordAudioProcessing(metricOperation, "system", "error")
+				m.RecordAudioProcessingError(metricOperation, "system", "filter_creation_failed")
+			}
+			return nil, enhancedErr
+		}
+		if filter != nil {
+			if err := chain.AddFilter(filter); err != nil {
 				enhancedErr := errors.New(err).
 					Component("myaudio").
-					Category(errors.CategoryConfiguration).
-					Context("operation", "initialize_filter_chain").
+					Category(errors.CategorySystem).
+					Context("operation", operation).
 					Context("filter_index", i).
 					Context("filter_type", filterConfig.Type).
-					Context("filter_frequency", filterConfig.Frequency).
 					Build()
 
 				if m := getFilterMetrics(); m != nil {
-					m.RecordAudioProcessing("initialize_filters", "system", "error")
-					m.RecordAudioProcessingError("initialize_filters", "system", "filter_creation_failed")
+					m.RecordAudioProcessing(metricOperation, "system", "error")
+					m.RecordAudioProcessingError(metricOperation, "system", "filter_add_failed")
 				}
-				return enhancedErr
-			}
-			if filter != nil {
-				if err := filterChain.AddFilter(filter); err != nil {
-					enhancedErr := errors.New(err).
-						Component("myaudio").
-						Category(errors.CategorySystem).
-						Context("operation", "initialize_filter_chain").
-						Context("filter_index", i).
-						Context("filter_type", filterConfig.Type).
-						Build()
-
-					if m := getFilterMetrics(); m != nil {
-						m.RecordAudioProcessing("initialize_filters", "system", "error")
-						m.RecordAudioProcessingError("initialize_filters", "system", "filter_add_failed")
-					}
-					return enhancedErr
-				}
-				filterCount++
+				return nil, enhancedErr
 			}
 		}
 	}
 
-	// Record successful initialization
-	if m := getFilterMetrics(); m != nil {
-		duration := time.Since(start).Seconds()
-		m.RecordAudioProcessing("initialize_filters", "system", "success")
-		m.RecordAudioProcessingDuration("initialize_filters", "system", duration)
-	}
+	return chain, nil
+}
 
-	return nil
+// sourceEqualizerSettings resolves the equalizer settings of a source through
+// its connection string in the source registry. Channel sub-sources use the
+// equalizer of their capture source.
+func sourceEqualizerSettings(settings *conf.Settings, sourceID string) conf.EqualizerSettings {
+	if source, exists := GetRegistry().GetSourceByID(captureSourceOf(sourceID)); exists {
+		if connection, err := source.GetConnectionString(); err == nil {
+			return settings.EqualizerFor(connection)
+		}
+	}
+	return settings.Realtime.Audio.Equalizer
 }
 
-// UpdateFilterChain updates the filter chain based on new settings
-func UpdateFilterChain(settings *conf.Settings) error {
-	start := time.Now()
+// getSourceFilterChain returns the filter chain of a source, building it from
+// the current settings on first use.
+func getSourceFilterChain(sourceID string) (*equalizer.FilterChain, error) {
+	filterMutex.RLock()
+	chain, exists := sourceFilterChains[sourceID]
+	settings := filterSettings
+	generation := filterGeneration
+	filterMutex.RUnlock()
+	if exists {
+		return chain, nil
+	}
 
-	// Validate input
 	if settings == nil {
-		enhancedErr := errors.Newf("settings parameter is nil").
-			Component("myaudio").
-			Category(errors.CategoryValidation).
-			Context("operation", "update_filter_chain").
-			Build()
-
-		if m := getFilterMetrics(); m != nil {
-			m.RecordAudioProcessing("update_filters", "system", "error")
-			m.RecordAudioProcessingError("update_filters", "system", "nil_settings")
+		settings = conf.Setting()
+		if settings == nil {
+			return nil, errors.Newf("filter chain is not initialized").
+				Component("myaudio").
+				Category(errors.CategorySystem).
+				Context("operation", "apply_source_filters").
+				Context("source_id", sourceID).
+				Build()
 		}
-		return enhancedErr
 	}
 
-	// Lock the mutex to ensure thread-safety
+	eq := sourceEqualizerSettings(settings, sourceID)
+	chain, err := buildFilterChain(&eq, "apply_source_filters", "apply_filters")
+	if err != nil {
+		return nil, err
+	}
+
 	filterMutex.Lock()
 	defer filterMutex.Unlock()
+	// Another goroutine or a settings update may have raced us, keep the stored chain
+	if existing, ok := sourceFilterChains[sourceID]; ok {
+		return existing, nil
+	}
+	if filterGeneration != generation {
+		// Settings changed while building, resolve again on the next call
+		return chain, nil
+	}
+	sourceFilterChains[sourceID] = chain
+	return chain, nil
+}
 
-	// Create a new filter chain
-	newChain := equalizer.NewFilterChain()
-	if newChain == nil {
-		enhancedErr := errors.Newf("failed to create new filter chain for update").
-			Component("myaudio").
-			Category(errors.CategorySystem).
-			Context("operation", "update_filter_chain").
-			Build()
+// SourceEqualizer describes the equalizer applied to a registered source
+type SourceEqualizer struct {
+	SourceID    string                 `json:"sourceId"`
+	DisplayName string                 `json:"displayName"`
+	Type        SourceType             `json:"type"`
+	Override    bool                   `json:"override"`  // true when the source overrides the global equalizer
+	Equalizer   conf.EqualizerSettings `json:"equalizer"` // effective equalizer settings
+	Active      bool                   `json:"active"`    // true when the filter chain is running
+}
 
-		if m := getFilterMetrics(); m != nil {
-			m.RecordAudioProcessing("update_filters", "system", "error")
-			m.RecordAudioProcessingError("update_filters", "system", "chain_creation_failed")
-		}
-		return enhancedErr
+// GetSourceEqualizers returns the effective equalizer of every registered
+// source in source ID order.
+func GetSourceEqualizers(settings *conf.Settings) []SourceEqualizer {
+	if settings == nil {
+		return nil
 	}
 
-	filterCount := 0
-	// If equalizer is enabled in settings, add filters
-	if settings.Realtime.Audio.Equalizer.Enabled {
-		// Iterate through each filter configuration
-		for i, filterConfig := range settings.Realtime.Audio.Equalizer.Filters {
-			// Create a new filter based on the configuration
-			filter, err := createFilter(filterConfig, float64(conf.SampleRate))
-			if err != nil {
-				// Skip disabled filters (not an error condition)
-				if errors.Is(err, ErrFilterDisabled) {
-					continue
-				}
+	registry := GetRegistry()
+	sources := registry.ListSources()
+	result := make([]SourceEqualizer, 0, len(sources))
 
-				enhancedErr := errors.New(err).
-					Component("myaudio").
-					Category(errors.CategoryConfiguration).
-					Context("operation", "update_filter_chain").
-					Context("filter_index", i).
-					Context("filter_type", filterConfig.Type).
-					Build()
+	filterMutex.RLock()
+	defer filterMutex.RUnlock()
 
-				if m := getFilterMetrics(); m != nil {
-					m.RecordAudioProcessing("update_filters", "system", "error")
-					m.RecordAudioProcessingError("update_filters", "system", "filter_creation_failed")
-				}
-				return enhancedErr
-			}
-			// If filter was successfully created, add it to the new chain
-			if filter != nil {
-				if err := newChain.AddFilter(filter); err != nil {
-					enhancedErr := errors.New(err).
-						Component("myaudio").
-						Category(errors.CategorySystem).
-						Context("operation", "update_filter_chain").
-						Context("filter_index", i).
-						Context("filter_type", filterConfig.Type).
-						Build()
-
-					if m := getFilterMetrics(); m != nil {
-						m.RecordAudioProcessing("update_filters", "system", "error")
-						m.RecordAudioProcessingError("update_filters", "system", "filter_add_failed")
-					}
-					return enhancedErr
-				}
-				filterCount++
+	for _, source := range sources {
+		entry := SourceEqualizer{
+			SourceID:    source.ID,
+			DisplayName: source.DisplayName,
+			Type:        source.Type,
+			Equalizer:   settings.Realtime.Audio.Equalizer,
+		}
+		// ListSources strips connection strings, resolve them from the registry
+		if registered, exists := registry.GetSourceByID(captureSourceOf(source.ID)); exists {
+			connection, _ := registered.GetConnectionString()
+			if override := settings.EqualizerOverride(connection); override != nil {
+				entry.Override = true
+				entry.Equalizer = *override
 			}
 		}
+		_, entry.Active = sourceFilterChains[source.ID]
+		result = append(result, entry)
 	}
+	return result
+}
 
-	// Replace the old filter chain with the new one
-	filterChain = newChain
-
-	// Record successful update
-	if m := getFilterMetrics(); m != nil {
-		duration := time.Since(start).Seconds()
-		m.RecordAudioProcessing("update_filters", "system", "success")
-		m.RecordAudioProcessingDuration("update_filters", "system", duration)
-	}
-
-	return nil
+// RemoveSourceFilterChain discards the filter chain and its state for a
+// source that stopped.
+func RemoveSourceFilterChain(sourceID string) {
+	filterMutex.Lock()
+	defer filterMutex.Unlock()
+	delete(sourceFilterChains, sourceID)
 }
 
 // createFilter creates a single filter based on the configuration
@@ -277,11 +342,64 @@ func createFilter(config conf.EqualizerFilter, sampleRate float64) (*equalizer.F
 	}
 }
 
-// ApplyFilters applies the current filter chain to a byte slice of audio samples
+// ApplyFilters applies the default filter chain, built from the global
+// equalizer settings, to a byte slice of audio samples
 func ApplyFilters(samples []byte) error {
 	start := time.Now()
 
-	// Validate input
+	if err := validateFilterSamples(samples); err != nil {
+		return err
+	}
+
+	filterMutex.RLock()
+	defer filterMutex.RUnlock()
+
+	// If no filters, return early
+	if filterChain == nil {
+		enhancedErr := errors.Newf("filter chain is not initialized").
+			Component("myaudio").
+			Category(errors.CategorySystem).
+			Context("operation", "apply_filters").
+			Build()
+
+		if m := getFilterMetrics(); m != nil {
+			m.RecordAudioProcessing("apply_filters", "filter", "error")
+			m.RecordAudioProcessingError("apply_filters", "filter", "uninitialized_chain")
+		}
+		return enhancedErr
+	}
+
+	return applyFilterChain(filterChain, samples, start)
+}
+
+// ApplySourceFilters applies the filter chain of the given source to a byte
+// slice of audio samples. Sources without an equalizer override use the
+// global equalizer settings, but each source keeps its own filter state.
+func ApplySourceFilters(sourceID string, samples []byte) error {
+	start := time.Now()
+
+	if err := validateFilterSamples(samples); err != nil {
+		return err
+	}
+
+	chain, err := getSourceFilterChain(sourceID)
+	if err != nil {
+		if m := getFilterMetrics(); m != nil {
+			m.RecordAudioProcessing("apply_filters", "filter", "error")
+			m.RecordAudioProcessingError("apply_filters", "filter", "uninitialized_chain")
+		}
+		return err
+	}
+
+	// The read lock keeps a concurrent update from swapping chains mid-batch
+	filterMutex.RLock()
+	defer filterMutex.RUnlock()
+
+	return applyFilterChain(chain, samples, start)
+}
+
+// validateFilterSamples checks that samples hold complete 16-bit samples
+func validateFilterSamples(samples []byte) error {
 	if len(samples) == 0 {
 		enhancedErr := errors.Newf("empty samples provided for filter application").
 			Component("myaudio").
@@ -313,25 +431,12 @@ func ApplyFilters(samples []byte) error {
 		return enhancedErr
 	}
 
-	filterMutex.RLock()
-	defer filterMutex.RUnlock()
-
-	// If no filters, return early
-	if filterChain == nil {
-		enhancedErr := errors.Newf("filter chain is not initialized").
-			Component("myaudio").
-			Category(errors.CategorySystem).
-			Context("operation", "apply_filters").
-			Build()
-
-		if m := getFilterMetrics(); m != nil {
-			m.RecordAudioProcessing("apply_filters", "filter", "error")
-			m.RecordAudioProcessingError("apply_filters", "filter", "uninitialized_chain")
-		}
-		return enhancedErr
-	}
+	return nil
+}
 
-	if filterChain.Length() == 0 {
+// applyFilterChain runs samples through a filter chain in place
+func applyFilterChain(chain *equalizer.FilterChain, samples []byte, start time.Time) error {
+	if chain.Length() == 0 {
 		// No filters to apply - record success but no processing
 		if m := getFilterMetrics(); m != nil {
 			duration := time.Since(start).Seconds()
@@ -346,7 +451,7 @@ func ApplyFilters(samples []byte) error {
 	sampleCount := len(floatSamples)
 
 	// Apply filters to the float samples in batch
-	filterChain.ApplyBatch(floatSamples)
+	chain.ApplyBatch(floatSamples)
 
 	// Convert back to byte slice with SIMD-accelerated clamping
 	if err := Float64ToBytesPCM16(floatSamples, samples); err != nil {
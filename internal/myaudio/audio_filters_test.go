@@ -410,3 +410,132 @@ func BenchmarkApplyFilters_FullPipeline(b *testing.B) {
 		})
 	}
 }
+
+// =============================================================================
+// Unit Tests for per-source filter chains
+// NOTE: Do NOT use t.Parallel() - these tests modify the global filter chains
+// =============================================================================
+
+// registerFilterTestSource registers a stream source for filter chain tests
+func registerFilterTestSource(t *testing.T, url string) string {
+	t.Helper()
+	registry := GetRegistry()
+	source, err := registry.RegisterSource(url, SourceConfig{Type: SourceTypeRTSP})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		RemoveSourceFilterChain(source.ID)
+		_ = registry.RemoveSource(source.ID)
+	})
+	return source.ID
+}
+
+// filterTestTone returns 0.1 seconds of a 100 Hz sine as PCM16 bytes
+func filterTestTone() []byte {
+	numSamples := conf.SampleRate / 10
+	samples := make([]byte, numSamples*2)
+	for i := range numSamples {
+		val := int16(math.Sin(2*math.Pi*100*float64(i)/float64(conf.SampleRate)) * 16384) //nolint:gosec // G115: sin*16384 is always in int16 range
+		binary.LittleEndian.PutUint16(samples[i*2:], uint16(val))                         //nolint:gosec // G115: intentional int16→uint16 for PCM test data
+	}
+	return samples
+}
+
+// restoreFilterChains rebuilds the filter chains from global settings after a test
+func restoreFilterChains(t *testing.T) {
+	t.Helper()
+	t.Cleanup(func() {
+		if settings := conf.Setting(); settings != nil {
+			_ = InitializeFilterChain(settings)
+		}
+	})
+}
+
+func TestApplySourceFilters_PerSourceOverride(t *testing.T) {
+	restoreFilterChains(t)
+
+	roadsideURL := "rtsp://filter-roadside.example.com/stream"
+	gardenURL := "rtsp://filter-garden.example.com/stream"
+	roadsideID := registerFilterTestSource(t, roadsideURL)
+	gardenID := registerFilterTestSource(t, gardenURL)
+
+	settings := &conf.Settings{}
+	settings.Realtime.RTSP.Streams = []conf.StreamConfig{
+		{
+			Name: "Roadside",
+			URL:  roadsideURL,
+			Equalizer: &conf.EqualizerSettings{
+				Enabled: true,
+				Filters: []conf.EqualizerFilter{{Type: "HighPass", Frequency: 1000, Q: 0.707, Passes: 4}},
+			},
+		},
+		{Name: "Garden", URL: gardenURL},
+	}
+	require.NoError(t, InitializeFilterChain(settings))
+
+	roadside := filterTestTone()
+	originalRMS := calculateRMSFromBytes(roadside)
+	require.NoError(t, ApplySourceFilters(roadsideID, roadside))
+	assert.Less(t, calculateRMSFromBytes(roadside)/originalRMS, 0.5, "roadside override should attenuate 100 Hz")
+
+	garden := filterTestTone()
+	require.NoError(t, ApplySourceFilters(gardenID, garden))
+	assert.Equal(t, filterTestTone(), garden, "garden inherits the disabled global equalizer")
+
+	equalizers := GetSourceEqualizers(settings)
+	byID := make(map[string]SourceEqualizer, len(equalizers))
+	for _, eq := range equalizers {
+		byID[eq.SourceID] = eq
+	}
+	assert.True(t, byID[roadsideID].Override)
+	assert.True(t, byID[roadsideID].Active)
+	assert.True(t, byID[roadsideID].Equalizer.Enabled)
+	assert.False(t, byID[gardenID].Override)
+}
+
+func TestUpdateFilterChain_LiveSourceUpdate(t *testing.T) {
+	restoreFilterChains(t)
+
+	url := "rtsp://filter-live-update.example.com/stream"
+	sourceID := registerFilterTestSource(t, url)
+
+	settings := &conf.Settings{}
+	settings.Realtime.RTSP.Streams = []conf.StreamConfig{{Name: "Live", URL: url}}
+	require.NoError(t, InitializeFilterChain(settings))
+
+	samples := filterTestTone()
+	require.NoError(t, ApplySourceFilters(sourceID, samples))
+	assert.Equal(t, filterTestTone(), samples, "no filters before the update")
+
+	updated := &conf.Settings{}
+	updated.Realtime.RTSP.Streams = []conf.StreamConfig{{
+		Name: "Live",
+		URL:  url,
+		Equalizer: &conf.EqualizerSettings{
+			Enabled: true,
+			Filters: []conf.EqualizerFilter{{Type: "HighPass", Frequency: 1000, Q: 0.707, Passes: 4}},
+		},
+	}}
+	require.NoError(t, UpdateFilterChain(updated))
+
+	samples = filterTestTone()
+	require.NoError(t, ApplySourceFilters(sourceID, samples))
+	assert.Less(t, calculateRMSFromBytes(samples)/calculateRMSFromBytes(filterTestTone()), 0.5,
+		"updated override should apply without restarting the source")
+
+	// An invalid override is rejected and the running chains are kept
+	invalid := &conf.Settings{}
+	invalid.Realtime.RTSP.Streams = []conf.StreamConfig{{
+		Name: "Live",
+		URL:  url,
+		Equalizer: &conf.EqualizerSettings{
+			Enabled: true,
+			Filters: []conf.EqualizerFilter{{Type: "Unknown", Frequency: 1000, Passes: 1}},
+		},
+	}}
+	require.Error(t, UpdateFilterChain(invalid))
+
+	filterMutex.RLock()
+	_, kept := sourceFilterChains[sourceID]
+	filterMutex.RUnlock()
+	assert.True(t, kept, "failed update must not drop running chains")
+}
@@ -0,0 +1,73 @@
+package myaudio
+
+import (
+	"bytes"
+	"sync"
+)
+
+// Audio taps receive the broadcast audio of a source next to its broadcast
+// callback. Unlike the callback, which belongs to the HLS stream, a source may
+// have any number of taps.
+
+// audioTap is one subscriber to the broadcast audio of a source
+type audioTap struct {
+	ch chan []byte
+}
+
+// Taps by source ID
+var (
+	audioTaps   = make(map[string]map[*audioTap]struct{})
+	audioTapsMu sync.RWMutex
+)
+
+// TapSourceAudio subscribes to the mono 16-bit PCM broadcast for a source at
+// the analysis sample rate, after its equalizer. Up to buffer chunks are queued,
+// further chunks are dropped until the subscriber catches up. Chunks are shared
+// between taps and must not be modified. The returned function unsubscribes and
+// closes the channel.
+func TapSourceAudio(sourceID string, buffer int) (audio <-chan []byte, cancel func()) {
+	tap := &audioTap{ch: make(chan []byte, max(buffer, 1))}
+
+	audioTapsMu.Lock()
+	taps, exists := audioTaps[sourceID]
+	if !exists {
+		taps = make(map[*audioTap]struct{})
+		audioTaps[sourceID] = taps
+	}
+	taps[tap] = struct{}{}
+	audioTapsMu.Unlock()
+
+	var once sync.Once
+	return tap.ch, func() {
+		once.Do(func() {
+			audioTapsMu.Lock()
+			// A source keeps its tap set while any tap is subscribed
+			delete(taps, tap)
+			if len(taps) == 0 {
+				delete(audioTaps, sourceID)
+			}
+			// Closed under the lock so feedAudioTaps never sends on it
+			close(tap.ch)
+			audioTapsMu.Unlock()
+		})
+	}
+}
+
+// feedAudioTaps passes broadcast audio of a source to its taps without blocking.
+func feedAudioTaps(sourceID string, data []byte) {
+	audioTapsMu.RLock()
+	defer audioTapsMu.RUnlock()
+
+	taps := audioTaps[sourceID]
+	if len(taps) == 0 {
+		return
+	}
+	// Callers reuse their buffers
+	chunk := bytes.Clone(data)
+	for tap := range taps {
+		select {
+		case tap.ch <- chunk:
+		default:
+		}
+	}
+}
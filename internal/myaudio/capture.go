@@ -74,6 +74,8 @@ func UnregisterBroadcastCallback(sourceID string) {
 
 // broadcastAudioData sends audio data to all registered callbacks
 func broadcastAudioData(sourceID string, data []byte) {
+	feedAudioTaps(sourceID, data)
+
 	broadcastCallbackMutex.RLock()
 	callback, exists := broadcastCallbacks[sourceID]
 
@@ -247,64 +249,70 @@ func ReconfigureStreams(settings *conf.Settings, wg *sync.WaitGroup, quitChan, r
 			logger.Error(err))
 	}
 
+	if err := SyncIngestDevices(settings, quitChan, unifiedAudioChan); err != nil {
+		GetLogger().Error("error syncing push ingest devices with configuration",
+			logger.Error(err))
+	}
+
 	// Note: Buffer management is handled by the FFmpegManager via the StartStream/StopStream
 	// methods which are called by SyncWithConfig. The activeStreams map is no longer needed
 	// as the FFmpegManager maintains its own internal stream tracking.
 }
 
-// initializeBuffersForSource handles the initialization of analysis and capture buffers for a given source
+// initializeBuffersForSource handles the initialization of analysis and capture buffers for a given source.
+// Sources split into channels get an analysis buffer per channel and one capture buffer
+// holding all channels.
 func initializeBuffersForSource(sourceID string) error {
-	var abExists bool
-
-	// Check if analysis buffer exists using the source ID
-	abMutex.RLock()
-	_, abExists = analysisBuffers[sourceID]
-	abMutex.RUnlock()
-
-	// Initialize analysis buffer if it doesn't exist
-	// Pass the ORIGINAL sourceID since AllocateAnalysisBuffer does its own migration
-	if !abExists {
-		if err := AllocateAnalysisBuffer(conf.BufferSize*3, sourceID); err != nil {
+	var created []string
+
+	// Initialize analysis buffers that don't exist yet
+	// Pass the ORIGINAL source IDs since AllocateAnalysisBuffer does its own migration
+	for _, analysisID := range AnalysisSourceIDs(sourceID) {
+		abMutex.RLock()
+		_, abExists := analysisBuffers[analysisID]
+		abMutex.RUnlock()
+
+		if abExists {
+			if conf.Setting().Debug {
+				log := GetLogger()
+				log.Debug("reusing existing analysis buffer",
+					logger.String("source_id", analysisID))
+			}
+			continue
+		}
+		if err := AllocateAnalysisBuffer(conf.BufferSize*3, analysisID); err != nil {
+			cleanupAnalysisBuffers(created)
 			return fmt.Errorf("failed to initialize analysis buffer: %w", err)
 		}
-	} else if conf.Setting().Debug {
-		log := GetLogger()
-		log.Debug("reusing existing analysis buffer",
-			logger.String("source_id", sourceID))
+		created = append(created, analysisID)
 	}
 
-	// Check if capture buffer exists using the source ID
-	cbMutex.RLock()
-	_, cbExists := captureBuffers[sourceID]
-	cbMutex.RUnlock()
-
-	// Initialize capture buffer if needed
+	// Initialize or resize the capture buffer
 	// Pass the ORIGINAL sourceID since AllocateCaptureBufferIfNeeded does its own migration
-	if !cbExists {
-		if err := AllocateCaptureBufferIfNeeded(60, conf.SampleRate, conf.BitDepth/8, sourceID); err != nil {
-			// Clean up the analysis buffer if we just created it and capture buffer init fails
-			if !abExists {
-				if cleanupErr := RemoveAnalysisBuffer(sourceID); cleanupErr != nil {
-					log := GetLogger()
-					log.Error("failed to cleanup analysis buffer after capture buffer init failure",
-						logger.String("source_id", sourceID),
-						logger.Error(cleanupErr))
-				}
-			}
-			return fmt.Errorf("failed to initialize capture buffer: %w", err)
-		}
-	} else if conf.Setting().Debug {
-		log := GetLogger()
-		log.Debug("reusing existing capture buffer",
-			logger.String("source_id", sourceID))
+	if err := AllocateCaptureBufferIfNeeded(60, conf.SampleRate, conf.BitDepth/8, sourceID); err != nil {
+		// Clean up the analysis buffers we just created if capture buffer init fails
+		cleanupAnalysisBuffers(created)
+		return fmt.Errorf("failed to initialize capture buffer: %w", err)
 	}
 
 	return nil
 }
 
+// cleanupAnalysisBuffers removes analysis buffers created by a failed buffer initialization.
+func cleanupAnalysisBuffers(sourceIDs []string) {
+	for _, sourceID := range sourceIDs {
+		if cleanupErr := RemoveAnalysisBuffer(sourceID); cleanupErr != nil {
+			log := GetLogger()
+			log.Error("failed to cleanup analysis buffer after capture buffer init failure",
+				logger.String("source_id", sourceID),
+				logger.Error(cleanupErr))
+		}
+	}
+}
+
 func CaptureAudio(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restartChan chan struct{}, unifiedAudioChan chan UnifiedAudioData) {
-	// If no RTSP streams and no audio device configured, return early
-	if len(settings.Realtime.RTSP.Streams) == 0 && settings.Realtime.Audio.Source == "" {
+	// If no RTSP streams, audio device or push ingest devices configured, return early
+	if len(settings.Realtime.RTSP.Streams) == 0 && settings.Realtime.Audio.Source == "" && !settings.HasIngestDevices() {
 		return
 	}
 
@@ -316,6 +324,12 @@ func CaptureAudio(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restart
 		}
 	}
 
+	// Start playout of push ingest devices
+	if err := SyncIngestDevices(settings, quitChan, unifiedAudioChan); err != nil {
+		GetLogger().Error("failed to start push ingest",
+			logger.Error(err))
+	}
+
 	// Handle sound card source if configured
 	if settings.Realtime.Audio.Source != "" {
 		log := GetLogger()
@@ -352,6 +366,13 @@ func CaptureAudio(settings *conf.Settings, wg *sync.WaitGroup, quitChan, restart
 			return
 		}
 
+		// Split the device into channel sources if configured
+		if _, err := ConfigureSourceChannels(source, settings.Realtime.Audio.Channels); err != nil {
+			log.Error("invalid channel settings for audio device, capturing mono",
+				logger.String("source_id", source.ID),
+				logger.Error(err))
+		}
+
 		// Initialize buffers using the registry source ID (UUID-based)
 		// This ensures consistency with the AnalysisBufferMonitor
 		if err := initializeBuffersForSource(source.ID); err != nil {
@@ -585,6 +606,7 @@ func processAudioFrame(
 	settings *conf.Settings,
 	source captureSource,
 	sourceID string, // Registry source ID for buffer operations
+	splitter *channelSplitter, // Nil unless the device is split into channel sources
 	unifiedAudioChan chan UnifiedAudioData,
 ) (finalBufferPtr *[]byte, fromPool bool, err error) { // Updated return signature
 
@@ -620,10 +642,10 @@ func processAudioFrame(
 	switch {
 	case needsReturn:
 		// Buffer came from the pool (currentBufferPtr) - MUST copy for safety
-		safeCopyPtr = s16BufferPool.Get().(*[]byte)        // Get a fresh buffer for the copy
-		safeCopy := (*safeCopyPtr)[:len(processedSamples)] // Slice it to the needed length
-		copy(safeCopy, processedSamples)                   // Copy the data
-		bufferToUse = safeCopy                             // This is the safe buffer to use downstream
+		safeCopyPtr = getS16PoolBuffer(len(processedSamples)) // Get a fresh buffer for the copy
+		safeCopy := (*safeCopyPtr)[:len(processedSamples)]    // Slice it to the needed length
+		copy(safeCopy, processedSamples)                      // Copy the data
+		bufferToUse = safeCopy                                // This is the safe buffer to use downstream
 
 		// Return the original pooled buffer (pointed to by currentBufferPtr) *now*
 		ReturnBufferToPool(currentBufferPtr, needsReturn)
@@ -634,10 +656,10 @@ func processAudioFrame(
 
 	case isOriginalPSamples:
 		// Using the original pSamples buffer directly - MUST copy for safety
-		safeCopyPtr = s16BufferPool.Get().(*[]byte)        // Get a buffer for the copy
-		safeCopy := (*safeCopyPtr)[:len(processedSamples)] // Slice it
-		copy(safeCopy, processedSamples)                   // Copy data
-		bufferToUse = safeCopy                             // Use the copy
+		safeCopyPtr = getS16PoolBuffer(len(processedSamples)) // Get a buffer for the copy
+		safeCopy := (*safeCopyPtr)[:len(processedSamples)]    // Slice it
+		copy(safeCopy, processedSamples)                      // Copy data
+		bufferToUse = safeCopy                                // Use the copy
 
 		// Update finalBufferPtr to point to the pooled buffer holding the safe copy
 		finalBufferPtr = safeCopyPtr
@@ -651,29 +673,81 @@ func processAudioFrame(
 	}
 	// --- End Buffer Safety Handling ---
 
-	// Apply audio EQ filters if enabled (use the safe bufferToUse)
-	if settings.Realtime.Audio.Equalizer.Enabled {
-		if eqErr := ApplyFilters(bufferToUse); eqErr != nil {
-			log.Warn("error applying audio EQ filters", logger.Error(eqErr))
-			// Non-fatal, just log
-		}
+	if splitter != nil {
+		processMultichannelFrame(bufferToUse, source, sourceID, splitter, unifiedAudioChan)
+		return finalBufferPtr, fromPool, nil
 	}
 
-	// Write to buffers using source ID (use the safe bufferToUse)
-	if writeErr := WriteToAnalysisBuffer(sourceID, bufferToUse); writeErr != nil {
-		log.Warn("error writing to analysis buffer", logger.Error(writeErr))
+	// Apply the audio EQ filters of this source and queue it for analysis (use the safe bufferToUse)
+	analyzeDeviceAudio(sourceID, bufferToUse)
+	if writeErr := WriteToCaptureBuffer(sourceID, bufferToUse); writeErr != nil {
+		log.Warn("error writing to capture buffer", logger.Error(writeErr))
 		// Potentially non-fatal, log and continue
 	}
-	if writeErr := WriteToCaptureBuffer(sourceID, bufferToUse); writeErr != nil {
+	publishDeviceAudio(sourceID, source.Name, bufferToUse, unifiedAudioChan)
+
+	return finalBufferPtr, fromPool, nil // Return pointer, pool status, and nil error
+}
+
+// processMultichannelFrame analyzes every channel source of a split device and
+// keeps the interleaved frames in the device's capture buffer.
+func processMultichannelFrame(data []byte, source captureSource, sourceID string, splitter *channelSplitter, unifiedAudioChan chan UnifiedAudioData) {
+	frames, outputs := splitter.split(data)
+	if len(frames) == 0 {
+		return
+	}
+
+	targets := splitter.layout.targets
+	for i := range targets {
+		analyzeDeviceAudio(targets[i].id, outputs[i])
+	}
+	splitter.merge()
+
+	if writeErr := WriteToCaptureBuffer(sourceID, frames); writeErr != nil {
+		log := GetLogger()
 		log.Warn("error writing to capture buffer", logger.Error(writeErr))
 		// Potentially non-fatal, log and continue
 	}
 
-	// Broadcast audio data using source ID (use the safe bufferToUse)
-	broadcastAudioData(sourceID, bufferToUse)
+	for i := range targets {
+		name := source.Name
+		if targets[i].id != sourceID {
+			name = targets[i].name
+		}
+		publishDeviceAudio(targets[i].id, name, outputs[i], unifiedAudioChan)
+	}
+	splitter.reportChannelLevels()
+}
+
+// analyzeDeviceAudio applies the audio EQ filters of a source and writes the
+// mono audio to its analysis buffer.
+func analyzeDeviceAudio(sourceID string, data []byte) {
+	log := GetLogger()
+
+	if eqErr := ApplySourceFilters(sourceID, data); eqErr != nil {
+		log.Warn("error applying audio EQ filters", logger.Error(eqErr))
+		// Non-fatal, just log
+	}
+
+	if writeErr := WriteToAnalysisBuffer(sourceID, data); writeErr != nil {
+		log.Warn("error writing to analysis buffer", logger.Error(writeErr))
+		// Potentially non-fatal, log and continue
+	}
+}
+
+// publishDeviceAudio broadcasts mono audio of a source, reports its audio level,
+// input health and sound level, and sends them to the unified audio channel.
+func publishDeviceAudio(sourceID, name string, data []byte, unifiedAudioChan chan UnifiedAudioData) {
+	log := GetLogger()
+
+	// Broadcast audio data using source ID
+	broadcastAudioData(sourceID, data)
 
-	// Calculate audio level (use the safe bufferToUse)
-	audioLevelData := calculateAudioLevel(bufferToUse, sourceID, source.Name)
+	// Calculate audio level
+	audioLevelData := calculateAudioLevel(data, sourceID, name)
+
+	// Judge input health (silence, clipping, DC offset, hum, roll-off)
+	ProcessMicHealth(sourceID, name, data)
 
 	// Create unified audio data structure
 	unifiedData := UnifiedAudioData{
@@ -681,9 +755,9 @@ func processAudioFrame(
 		Timestamp:  time.Now(),
 	}
 
-	// Process sound level data if enabled (use the safe bufferToUse) - this may be nil if 10-second window isn't complete
+	// Process sound level data if enabled - this may be nil if 10-second window isn't complete
 	if conf.Setting().Realtime.Audio.SoundLevel.Enabled {
-		if soundLevelData, err := ProcessSoundLevelData(sourceID, bufferToUse); err != nil {
+		if soundLevelData, err := ProcessSoundLevelData(sourceID, data); err != nil {
 			// Only log actual errors, not normal conditions
 			if !errors.Is(err, ErrIntervalIncomplete) && !errors.Is(err, ErrNoAudioData) {
 				log.Warn("error processing sound level data",
@@ -696,6 +770,11 @@ func processAudioFrame(
 		}
 	}
 
+	// Sources started outside audio capture have no unified channel
+	if unifiedAudioChan == nil {
+		return
+	}
+
 	// Send unified data to channel (non-blocking)
 	select {
 	case unifiedAudioChan <- unifiedData:
@@ -709,11 +788,9 @@ func processAudioFrame(
 		case unifiedAudioChan <- unifiedData:
 		default:
 			log.Warn("unified audio channel full even after clearing",
-				logger.String("source", source.Name))
+				logger.String("source", name))
 		}
 	}
-
-	return finalBufferPtr, fromPool, nil // Return pointer, pool status, and nil error
 }
 
 // handleDeviceStop contains the logic for attempting to restart the audio device
@@ -765,6 +842,10 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, sourceID s
 
 	// Clean up sound level processor when function exits
 	defer UnregisterSoundLevelProcessor(sourceID)
+	defer ResetMicHealth(sourceID)
+	defer RemoveSourceFilterChain(sourceID)
+	defer ResetNoiseReduction(sourceID)
+	defer RemoveSourceChannels(sourceID)
 
 	log.Debug("Initializing audio context")
 
@@ -791,11 +872,14 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, sourceID s
 
 	deviceConfig := malgo.DefaultDeviceConfig(malgo.Capture)
 	// deviceConfig.Capture.Format = malgo.FormatS16 // Let malgo choose or use default
-	deviceConfig.Capture.Channels = conf.NumChannels
+	deviceConfig.Capture.Channels = uint32(captureChannels(sourceID)) //nolint:gosec // G115: channel count limited to conf.MaxCaptureChannels
 	deviceConfig.SampleRate = conf.SampleRate
 	deviceConfig.Alsa.NoMMap = 1
 	deviceConfig.Capture.DeviceID = source.Pointer
 
+	// Split multichannel devices into channel sources
+	splitter := newChannelSplitter(getChannelLayout(sourceID))
+
 	// Initialize the filter chain
 	if err := InitializeFilterChain(settings); err != nil {
 		log.Warn("error initializing filter chain", logger.Error(err))
@@ -809,6 +893,11 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, sourceID s
 				logger.String("source_id", sourceID),
 				logger.String("source_name", source.Name))
 		}
+		if err := RegisterChannelSoundLevelProcessors(sourceID); err != nil {
+			log.Warn("error initializing sound level processors for channel sources",
+				logger.Error(err),
+				logger.String("source_id", sourceID))
+		}
 	}
 
 	var captureDevice *malgo.Device
@@ -820,7 +909,7 @@ func captureAudioMalgo(settings *conf.Settings, source captureSource, sourceID s
 		// processAudioFrame now handles pooling internally and returns buffer info
 		// Pass scratchBuffer as the potential destination for conversion
 		finalBufferPtr, fromPool, err := processAudioFrame(
-			pSamples, formatType, scratchBuffer, settings, source, sourceID, unifiedAudioChan,
+			pSamples, formatType, scratchBuffer, settings, source, sourceID, splitter, unifiedAudioChan,
 		)
 		if err != nil {
 			// Error already logged in processAudioFrame
@@ -1036,6 +1125,20 @@ var s16BufferPool = sync.Pool{
 	},
 }
 
+// getS16PoolBuffer returns a pooled buffer of at least size bytes. Frames of
+// multichannel devices may exceed the pooled size, their buffers are allocated
+// and join the pool when returned.
+func getS16PoolBuffer(size int) *[]byte {
+	bufferPtr := s16BufferPool.Get().(*[]byte)
+	if cap(*bufferPtr) >= size {
+		*bufferPtr = (*bufferPtr)[:cap(*bufferPtr)]
+		return bufferPtr
+	}
+	s16BufferPool.Put(bufferPtr)
+	buffer := make([]byte, size)
+	return &buffer
+}
+
 // ConvertToS16WithBuffer converts audio samples from higher bit depths to 16-bit format
 // using a caller-provided or pooled buffer to minimize allocations.
 // This version is optimized for real-time processing with fixed-size frames.
@@ -18,7 +18,8 @@ type CaptureBuffer struct {
 	data           []byte
 	writeIndex     int
 	sampleRate     int
-	bytesPerSample int
+	bytesPerSample int // bytes per frame, covering every interleaved channel
+	channels       int // interleaved channels, more than one for multichannel capture sources
 	bufferSize     int
 	bufferDuration time.Duration
 	startTime      time.Time
@@ -62,19 +63,34 @@ func getCaptureMetrics() *metrics.MyAudioMetrics {
 // AllocateCaptureBufferIfNeeded checks if a buffer exists and only allocates if needed.
 // It returns nil if the buffer already exists or was successfully created.
 // This function is thread-safe and prevents race conditions during allocation.
+//
+// Channel sub-sources share the interleaved buffer of their capture source,
+// which holds every captured channel in each frame.
 func AllocateCaptureBufferIfNeeded(durationSeconds, sampleRate, bytesPerSample int, sourceID string) error {
+	sourceID = captureSourceOf(sourceID)
+	channels := captureChannels(sourceID)
 
 	// Hold lock for entire operation to prevent race conditions
 	cbMutex.Lock()
 	defer cbMutex.Unlock()
 
 	// Check if buffer already exists using the migrated ID
-	if _, exists := captureBuffers[sourceID]; exists {
+	if cb, exists := captureBuffers[sourceID]; exists {
+		if cb.channels != channels {
+			// The channel layout changed, replace the buffer keeping its source reference
+			resized := NewCaptureBuffer(int(cb.bufferDuration.Seconds()), cb.sampleRate, bytesPerSample*channels, sourceID)
+			resized.channels = channels
+			captureBuffers[sourceID] = resized
+		}
 		return nil
 	}
 
 	// Buffer doesn't exist, allocate it while holding the lock
-	return allocateCaptureBufferInternal(durationSeconds, sampleRate, bytesPerSample, sourceID)
+	if err := allocateCaptureBufferInternal(durationSeconds, sampleRate, bytesPerSample*channels, sourceID); err != nil {
+		return err
+	}
+	captureBuffers[sourceID].channels = channels
+	return nil
 }
 
 // AllocateCaptureBuffer initializes an audio buffer for a single source.
@@ -170,8 +186,7 @@ func allocateCaptureBufferInternal(durationSeconds, sampleRate, bytesPerSample i
 	}
 
 	// Calculate buffer size and check memory requirements
-	bufferSize := durationSeconds * sampleRate * bytesPerSample
-	alignedBufferSize := ((bufferSize + 2047) / 2048) * 2048 // Round up to the nearest multiple of 2048
+	alignedBufferSize := alignCaptureBufferSize(durationSeconds*sampleRate*bytesPerSample, bytesPerSample)
 
 	// Only prevent extremely large allocations (e.g. over 1GB)
 	if alignedBufferSize > 1<<30 { // 1GB
@@ -341,30 +356,57 @@ func WriteToCaptureBuffer(sourceID string, data []byte) error {
 	}
 
 	cb.Write(data)
+	archiveAudio(sourceID, data, cb.channels)
 	return nil
 }
 
-// ReadSegmentFromCaptureBuffer extracts a segment of audio data from the buffer for a given source ID.
+// ReadSegmentFromCaptureBuffer extracts a segment of mono audio data from the buffer for a given source ID.
+// Analysis sources of multichannel capture sources get the channel they analyze.
 func ReadSegmentFromCaptureBuffer(sourceID string, requestedStartTime time.Time, duration int) ([]byte, error) {
+	segment, channels, err := ReadClipFromCaptureBuffer(sourceID, requestedStartTime, duration)
+	if err != nil {
+		return nil, err
+	}
+	return SourceChannelPCM(sourceID, segment, channels), nil
+}
+
+// ReadClipFromCaptureBuffer extracts a segment of audio data for a given source ID
+// with the original channel layout of its capture source. It returns the
+// interleaved PCM data and the number of channels.
+func ReadClipFromCaptureBuffer(sourceID string, requestedStartTime time.Time, duration int) ([]byte, int, error) {
 	cbMutex.RLock()
-	cb, exists := captureBuffers[sourceID]
+	cb, exists := captureBuffers[captureSourceOf(sourceID)]
 	cbMutex.RUnlock()
 
 	if !exists {
-		return nil, fmt.Errorf("no capture buffer found for source ID: %s", sourceID)
+		return nil, 0, fmt.Errorf("no capture buffer found for source ID: %s", sourceID)
+	}
+
+	segment, err := cb.ReadSegment(requestedStartTime, duration)
+	if err != nil {
+		return nil, 0, err
 	}
+	return segment, cb.channels, nil
+}
 
-	return cb.ReadSegment(requestedStartTime, duration)
+// alignCaptureBufferSize rounds a buffer size up to a multiple of 2048 bytes
+// that also holds whole frames, so wraparounds never split a frame.
+func alignCaptureBufferSize(bufferSize, bytesPerSample int) int {
+	alignment := 2048
+	if bytesPerSample > 0 && alignment%bytesPerSample != 0 {
+		alignment *= bytesPerSample
+	}
+	return ((bufferSize + alignment - 1) / alignment) * alignment
 }
 
 // NewCaptureBuffer initializes a new CaptureBuffer with timestamp tracking
 func NewCaptureBuffer(durationSeconds, sampleRate, bytesPerSample int, source string) *CaptureBuffer {
-	bufferSize := durationSeconds * sampleRate * bytesPerSample
-	alignedBufferSize := ((bufferSize + 2047) / 2048) * 2048 // Round up to the nearest multiple of 2048
+	alignedBufferSize := alignCaptureBufferSize(durationSeconds*sampleRate*bytesPerSample, bytesPerSample)
 	cb := &CaptureBuffer{
 		data:           make([]byte, alignedBufferSize),
 		sampleRate:     sampleRate,
 		bytesPerSample: bytesPerSample,
+		channels:       1,
 		bufferSize:     alignedBufferSize,
 		bufferDuration: time.Second * time.Duration(durationSeconds),
 		initialized:    false,
@@ -0,0 +1,422 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Multichannel capture sources are captured with all of their channels into
+// one interleaved capture buffer, so clips keep the original channel layout.
+// Analysis runs on mono channels: in split mode every channel feeds a virtual
+// sub-source with its own analysis buffer, detections and sound level, in
+// select mode a single channel feeds the capture source itself.
+
+// channelTarget is an analysis source fed by one channel of a capture source.
+type channelTarget struct {
+	id      string // analysis source ID, the capture source itself in select mode
+	name    string // display name used for audio levels and diagnostics
+	channel int    // zero-based channel index within an interleaved frame
+}
+
+// channelLayout describes how the channels of a capture source are analyzed.
+type channelLayout struct {
+	parentID string
+	channels int
+	targets  []channelTarget
+}
+
+// Global channel layout registry keyed by capture source ID
+var (
+	channelLayouts     = make(map[string]*channelLayout)
+	channelParents     = make(map[string]string) // analysis source ID -> capture source ID
+	channelLayoutMutex sync.RWMutex
+)
+
+// ChannelSourceID returns the ID of the virtual sub-source that analyzes one
+// channel, starting from 1, of a capture source in split mode.
+func ChannelSourceID(parentID string, channel int) string {
+	return fmt.Sprintf("%s_ch%d", parentID, channel)
+}
+
+// ConfigureSourceChannels applies the channel settings of a capture source and
+// returns the IDs of the sources analyzed for it: one virtual sub-source per
+// channel in split mode, otherwise the capture source itself. Sub-sources are
+// registered in the source registry so detections carry their own ID and
+// name. Calling it again with unchanged settings keeps the current layout.
+func ConfigureSourceChannels(source *AudioSource, settings conf.ChannelSettings) ([]string, error) {
+	if source == nil {
+		return nil, errors.Newf("audio source is nil").
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "configure_source_channels").
+			Build()
+	}
+	if err := settings.Validate(); err != nil {
+		return nil, errors.New(err).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "configure_source_channels").
+			Context("source_id", source.ID).
+			Build()
+	}
+
+	if !settings.IsMultichannel() {
+		removeChannelLayout(source.ID)
+		return []string{source.ID}, nil
+	}
+
+	layout := &channelLayout{parentID: source.ID, channels: settings.Count}
+	if settings.Mode == conf.ChannelModeSelect {
+		layout.targets = []channelTarget{{id: source.ID, name: source.DisplayName, channel: settings.Channel - 1}}
+	} else {
+		connection, err := source.GetConnectionString()
+		if err != nil {
+			return nil, err
+		}
+		registry := GetRegistry()
+		for ch := 1; ch <= settings.Count; ch++ {
+			label := fmt.Sprintf("channel %d", ch)
+			if ch <= len(settings.Names) && strings.TrimSpace(settings.Names[ch-1]) != "" {
+				label = strings.TrimSpace(settings.Names[ch-1])
+			}
+			sub, err := registry.RegisterSource(fmt.Sprintf("%s#ch%d", connection, ch), SourceConfig{
+				ID:          ChannelSourceID(source.ID, ch),
+				DisplayName: fmt.Sprintf("%s (%s)", source.DisplayName, label),
+				Type:        source.Type,
+			})
+			if err != nil {
+				return nil, errors.New(err).
+					Component("myaudio").
+					Category(errors.CategoryAudioSource).
+					Context("operation", "configure_source_channels").
+					Context("source_id", source.ID).
+					Context("channel", ch).
+					Build()
+			}
+			layout.targets = append(layout.targets, channelTarget{id: sub.ID, name: sub.DisplayName, channel: ch - 1})
+		}
+	}
+
+	setChannelLayout(layout)
+	return layout.targetIDs(), nil
+}
+
+// RemoveSourceChannels discards the channel layout of a capture source that
+// stopped, together with the per-channel state of its sub-sources.
+func RemoveSourceChannels(parentID string) {
+	layout := getChannelLayout(parentID)
+	if layout == nil {
+		return
+	}
+	UnregisterChannelSoundLevelProcessors(parentID)
+	for _, target := range layout.targets {
+		if target.id == parentID {
+			continue
+		}
+		ResetMicHealth(target.id)
+		RemoveSourceFilterChain(target.id)
+		ResetNoiseReduction(target.id)
+	}
+	removeChannelLayout(parentID)
+}
+
+// RegisterChannelSoundLevelProcessors registers sound level processors for the
+// channel sub-sources of a capture source in split mode.
+func RegisterChannelSoundLevelProcessors(parentID string) error {
+	layout := getChannelLayout(parentID)
+	if layout == nil {
+		return nil
+	}
+	var errs []error
+	for _, target := range layout.targets {
+		if target.id == parentID {
+			continue
+		}
+		if err := RegisterSoundLevelProcessor(target.id, target.name); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// UnregisterChannelSoundLevelProcessors removes the sound level processors of
+// the channel sub-sources of a capture source.
+func UnregisterChannelSoundLevelProcessors(parentID string) {
+	layout := getChannelLayout(parentID)
+	if layout == nil {
+		return
+	}
+	for _, target := range layout.targets {
+		if target.id != parentID {
+			UnregisterSoundLevelProcessor(target.id)
+		}
+	}
+}
+
+// AnalysisSourceIDs returns the IDs of the sources analyzed for a capture source.
+func AnalysisSourceIDs(sourceID string) []string {
+	if layout := getChannelLayout(sourceID); layout != nil {
+		return layout.targetIDs()
+	}
+	return []string{sourceID}
+}
+
+// targetIDs returns the analysis source IDs of the layout in channel order.
+func (l *channelLayout) targetIDs() []string {
+	ids := make([]string, len(l.targets))
+	for i, target := range l.targets {
+		ids[i] = target.id
+	}
+	return ids
+}
+
+// equal reports whether two layouts capture and analyze the same channels.
+func (l *channelLayout) equal(other *channelLayout) bool {
+	return other != nil && l.parentID == other.parentID && l.channels == other.channels &&
+		slices.Equal(l.targets, other.targets)
+}
+
+// setChannelLayout stores the layout of a capture source, keeping the current
+// layout when it is unchanged so running captures are not disturbed.
+func setChannelLayout(layout *channelLayout) {
+	channelLayoutMutex.Lock()
+	previous := channelLayouts[layout.parentID]
+	if layout.equal(previous) {
+		channelLayoutMutex.Unlock()
+		return
+	}
+	stale := dropChannelLayoutLocked(layout.parentID)
+	channelLayouts[layout.parentID] = layout
+	for _, target := range layout.targets {
+		channelParents[target.id] = layout.parentID
+	}
+	channelLayoutMutex.Unlock()
+
+	removeChannelSubSources(stale, layout.targetIDs())
+}
+
+// removeChannelLayout discards the layout of a capture source and unregisters
+// its sub-sources.
+func removeChannelLayout(parentID string) {
+	channelLayoutMutex.Lock()
+	stale := dropChannelLayoutLocked(parentID)
+	channelLayoutMutex.Unlock()
+
+	removeChannelSubSources(stale, nil)
+}
+
+// dropChannelLayoutLocked removes a layout and returns it. The caller holds channelLayoutMutex.
+func dropChannelLayoutLocked(parentID string) *channelLayout {
+	layout, exists := channelLayouts[parentID]
+	if !exists {
+		return nil
+	}
+	for _, target := range layout.targets {
+		delete(channelParents, target.id)
+	}
+	delete(channelLayouts, parentID)
+	return layout
+}
+
+// removeChannelSubSources unregisters the sub-sources of a replaced layout
+// that are not part of the new one.
+func removeChannelSubSources(stale *channelLayout, keep []string) {
+	if stale == nil {
+		return
+	}
+	registry := GetRegistry()
+	for _, target := range stale.targets {
+		if target.id == stale.parentID || slices.Contains(keep, target.id) {
+			continue
+		}
+		if err := registry.RemoveSource(target.id); err != nil && !errors.Is(err, ErrSourceNotFound) {
+			GetLogger().Warn("failed to remove channel sub-source",
+				logger.String("source_id", target.id),
+				logger.Error(err))
+		}
+	}
+}
+
+// getChannelLayout returns the layout of a multichannel capture source, nil for mono sources.
+func getChannelLayout(parentID string) *channelLayout {
+	channelLayoutMutex.RLock()
+	defer channelLayoutMutex.RUnlock()
+	return channelLayouts[parentID]
+}
+
+// channelTargetOf returns the capture source and zero-based channel analyzed
+// by an analysis source of a multichannel capture source.
+func channelTargetOf(sourceID string) (parentID string, channel int, ok bool) {
+	channelLayoutMutex.RLock()
+	defer channelLayoutMutex.RUnlock()
+	parentID, ok = channelParents[sourceID]
+	if !ok {
+		return "", 0, false
+	}
+	for _, target := range channelLayouts[parentID].targets {
+		if target.id == sourceID {
+			return parentID, target.channel, true
+		}
+	}
+	return "", 0, false
+}
+
+// captureSourceOf returns the capture source whose buffers hold the audio of
+// an analysis source: the parent for channel sub-sources, else the source itself.
+func captureSourceOf(sourceID string) string {
+	channelLayoutMutex.RLock()
+	defer channelLayoutMutex.RUnlock()
+	if parentID, ok := channelParents[sourceID]; ok {
+		return parentID
+	}
+	return sourceID
+}
+
+// captureChannels returns the number of channels captured from a source.
+func captureChannels(sourceID string) int {
+	if layout := getChannelLayout(sourceID); layout != nil {
+		return layout.channels
+	}
+	return conf.NumChannels
+}
+
+// SourceChannelPCM returns the 16-bit mono audio analyzed by a source from
+// an interleaved clip of its capture source. Sources that are not fed by a
+// single channel get a downmix.
+func SourceChannelPCM(sourceID string, pcmData []byte, channels int) []byte {
+	if channels <= 1 {
+		return pcmData
+	}
+	if _, channel, ok := channelTargetOf(sourceID); ok && channel < channels {
+		return deinterleaveChannel(nil, pcmData, channels, channel)
+	}
+	return downmixChannels(pcmData, channels)
+}
+
+// channelSplitter deinterleaves the 16-bit PCM of a multichannel capture
+// source into mono buffers for its analysis sources. Readers do not return
+// whole frames, so an incomplete frame is carried over to the next chunk.
+type channelSplitter struct {
+	layout  *channelLayout
+	pending []byte   // incomplete frame from the previous chunk
+	frames  []byte   // complete interleaved frames of the current chunk
+	outputs [][]byte // mono PCM per target of the current chunk
+	levels  []float64
+}
+
+// newChannelSplitter creates a splitter for a capture source, nil for mono sources.
+func newChannelSplitter(layout *channelLayout) *channelSplitter {
+	if layout == nil {
+		return nil
+	}
+	return &channelSplitter{
+		layout:  layout,
+		outputs: make([][]byte, len(layout.targets)),
+		levels:  make([]float64, layout.channels),
+	}
+}
+
+// split returns the complete interleaved frames of a chunk and the mono PCM
+// of every target. The returned buffers are reused by the next call.
+func (s *channelSplitter) split(data []byte) (frames []byte, outputs [][]byte) {
+	frameSize := s.layout.channels * 2
+	s.frames = append(s.frames[:0], s.pending...)
+	s.frames = append(s.frames, data...)
+	complete := len(s.frames) / frameSize * frameSize
+	s.pending = append(s.pending[:0], s.frames[complete:]...)
+	s.frames = s.frames[:complete]
+
+	for i, target := range s.layout.targets {
+		s.outputs[i] = deinterleaveChannel(s.outputs[i], s.frames, s.layout.channels, target.channel)
+	}
+	return s.frames, s.outputs
+}
+
+// merge writes the processed target channels back into the interleaved frames,
+// so clips carry the same filtered audio as the analysis.
+func (s *channelSplitter) merge() {
+	for i, target := range s.layout.targets {
+		interleaveChannel(s.frames, s.outputs[i], s.layout.channels, target.channel)
+	}
+}
+
+// channelLevels returns the RMS level in dBFS of every channel of the current frames.
+func (s *channelSplitter) channelLevels() []float64 {
+	channels := s.layout.channels
+	frames := len(s.frames) / (channels * 2)
+	for ch := range channels {
+		var sum float64
+		for f := range frames {
+			offset := (f*channels + ch) * 2
+			sample := float64(int16(binary.LittleEndian.Uint16(s.frames[offset:]))) / 32768.0 //nolint:gosec // G115: 16-bit PCM sample
+			sum += sample * sample
+		}
+		level := micHealthMinLevelDB
+		if frames > 0 {
+			level = max(10*math.Log10(sum/float64(frames)+micHealthLevelEpsilon), micHealthMinLevelDB)
+		}
+		s.levels[ch] = level
+	}
+	return s.levels
+}
+
+// reportChannelLevels passes the channel levels of the current frames to the
+// microphone health of every analysis source, each of which hears the device.
+func (s *channelSplitter) reportChannelLevels() {
+	levels := s.channelLevels()
+	for _, target := range s.layout.targets {
+		ReportChannelLevels(target.id, levels)
+	}
+}
+
+// deinterleaveChannel copies one channel of interleaved 16-bit PCM into dst,
+// reusing its capacity, and returns it.
+func deinterleaveChannel(dst, data []byte, channels, channel int) []byte {
+	frameSize := channels * 2
+	frames := len(data) / frameSize
+	if cap(dst) < frames*2 {
+		dst = make([]byte, frames*2)
+	}
+	dst = dst[:frames*2]
+	for f := range frames {
+		offset := f*frameSize + channel*2
+		dst[f*2] = data[offset]
+		dst[f*2+1] = data[offset+1]
+	}
+	return dst
+}
+
+// interleaveChannel writes mono 16-bit PCM into one channel of interleaved frames.
+func interleaveChannel(frames, mono []byte, channels, channel int) {
+	frameSize := channels * 2
+	count := min(len(frames)/frameSize, len(mono)/2)
+	for f := range count {
+		offset := f*frameSize + channel*2
+		frames[offset] = mono[f*2]
+		frames[offset+1] = mono[f*2+1]
+	}
+}
+
+// downmixChannels averages the channels of interleaved 16-bit PCM into mono.
+func downmixChannels(data []byte, channels int) []byte {
+	frameSize := channels * 2
+	frames := len(data) / frameSize
+	mono := make([]byte, frames*2)
+	for f := range frames {
+		var sum int
+		for ch := range channels {
+			offset := f*frameSize + ch*2
+			sum += int(int16(binary.LittleEndian.Uint16(data[offset:]))) //nolint:gosec // G115: 16-bit PCM sample
+		}
+		binary.LittleEndian.PutUint16(mono[f*2:], uint16(int16(sum/channels))) //nolint:gosec // G115: average stays within 16-bit range
+	}
+	return mono
+}
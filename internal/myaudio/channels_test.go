@@ -0,0 +1,181 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// interleavedTestFrames returns frames of 16-bit PCM where every channel holds
+// a constant sample value.
+func interleavedTestFrames(frames int, values ...int16) []byte {
+	data := make([]byte, frames*len(values)*2)
+	for f := range frames {
+		for ch, value := range values {
+			binary.LittleEndian.PutUint16(data[(f*len(values)+ch)*2:], uint16(value)) //nolint:gosec // G115: intentional int16→uint16 for PCM test data
+		}
+	}
+	return data
+}
+
+// pcmSamples decodes 16-bit PCM into sample values.
+func pcmSamples(data []byte) []int16 {
+	samples := make([]int16, len(data)/2)
+	for i := range samples {
+		samples[i] = int16(binary.LittleEndian.Uint16(data[i*2:])) //nolint:gosec // G115: 16-bit PCM sample
+	}
+	return samples
+}
+
+func TestChannelPCMHelpers(t *testing.T) {
+	t.Parallel()
+
+	data := interleavedTestFrames(3, 100, -200, 300)
+
+	assert.Equal(t, []int16{-200, -200, -200}, pcmSamples(deinterleaveChannel(nil, data, 3, 1)))
+	assert.Equal(t, []int16{66, 66, 66}, pcmSamples(downmixChannels(data, 3)))
+
+	interleaveChannel(data, interleavedTestFrames(3, 7), 3, 2)
+	assert.Equal(t, []int16{100, -200, 7, 100, -200, 7, 100, -200, 7}, pcmSamples(data))
+}
+
+func TestChannelSplitter_CarriesIncompleteFrames(t *testing.T) {
+	t.Parallel()
+
+	layout := &channelLayout{
+		parentID: "splitter_test",
+		channels: 2,
+		targets: []channelTarget{
+			{id: "splitter_test_ch1", channel: 0},
+			{id: "splitter_test_ch2", channel: 1},
+		},
+	}
+	splitter := newChannelSplitter(layout)
+	require.NotNil(t, splitter)
+	assert.Nil(t, newChannelSplitter(nil), "mono sources have no splitter")
+
+	data := interleavedTestFrames(4, 1000, -1000)
+
+	// Split a chunk ending in the middle of a frame
+	frames, outputs := splitter.split(data[:7])
+	assert.Len(t, frames, 4, "only the complete frame is returned")
+	assert.Equal(t, []int16{1000}, pcmSamples(outputs[0]))
+	assert.Equal(t, []int16{-1000}, pcmSamples(outputs[1]))
+
+	frames, outputs = splitter.split(data[7:])
+	assert.Len(t, frames, 12, "the incomplete frame is completed by the next chunk")
+	assert.Equal(t, []int16{1000, 1000, 1000}, pcmSamples(outputs[0]))
+	assert.Equal(t, []int16{-1000, -1000, -1000}, pcmSamples(outputs[1]))
+
+	// Processed channels are merged back into the frames
+	binary.LittleEndian.PutUint16(outputs[1], 5)
+	splitter.merge()
+	assert.Equal(t, []int16{1000, 5, 1000, -1000, 1000, -1000}, pcmSamples(frames))
+
+	levels := splitter.channelLevels()
+	require.Len(t, levels, 2)
+	assert.InDelta(t, -30.3, levels[0], 0.1)
+	assert.Less(t, levels[1], levels[0])
+}
+
+// NOTE: Do NOT use t.Parallel() - these tests modify the global source registry and channel layouts
+
+// registerChannelTestSource registers a stream source for channel tests
+func registerChannelTestSource(t *testing.T, url string) *AudioSource {
+	t.Helper()
+	registry := GetRegistry()
+	source, err := registry.RegisterSource(url, SourceConfig{DisplayName: "Stereo", Type: SourceTypeRTSP})
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		RemoveSourceChannels(source.ID)
+		_ = RemoveCaptureBuffer(source.ID)
+		_ = registry.RemoveSource(source.ID)
+	})
+	return source
+}
+
+func TestConfigureSourceChannels(t *testing.T) {
+	source := registerChannelTestSource(t, "rtsp://channels-split.example.com/stream")
+	registry := GetRegistry()
+
+	split := conf.ChannelSettings{Mode: conf.ChannelModeSplit, Count: 2, Names: []string{"Left"}}
+	ids, err := ConfigureSourceChannels(source, split)
+	require.NoError(t, err)
+	assert.Equal(t, []string{ChannelSourceID(source.ID, 1), ChannelSourceID(source.ID, 2)}, ids)
+	assert.Equal(t, ids, AnalysisSourceIDs(source.ID))
+	assert.Equal(t, 2, captureChannels(source.ID))
+	assert.Equal(t, source.ID, captureSourceOf(ids[1]))
+
+	left, exists := registry.GetSourceByID(ids[0])
+	require.True(t, exists, "sub-sources are registered")
+	assert.Equal(t, "Stereo (Left)", left.DisplayName)
+	right, exists := registry.GetSourceByID(ids[1])
+	require.True(t, exists)
+	assert.Equal(t, "Stereo (channel 2)", right.DisplayName)
+
+	// Unchanged settings keep the running layout
+	layout := getChannelLayout(source.ID)
+	_, err = ConfigureSourceChannels(source, split)
+	require.NoError(t, err)
+	assert.Same(t, layout, getChannelLayout(source.ID))
+
+	// Selecting a channel analyzes the source itself and drops the sub-sources
+	ids, err = ConfigureSourceChannels(source, conf.ChannelSettings{Mode: conf.ChannelModeSelect, Count: 2, Channel: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{source.ID}, ids)
+	parentID, channel, ok := channelTargetOf(source.ID)
+	require.True(t, ok)
+	assert.Equal(t, source.ID, parentID)
+	assert.Equal(t, 1, channel)
+	_, exists = registry.GetSourceByID(ChannelSourceID(source.ID, 1))
+	assert.False(t, exists, "stale sub-sources are unregistered")
+
+	// Mixing removes the layout
+	ids, err = ConfigureSourceChannels(source, conf.ChannelSettings{Mode: conf.ChannelModeMix})
+	require.NoError(t, err)
+	assert.Equal(t, []string{source.ID}, ids)
+	assert.Nil(t, getChannelLayout(source.ID))
+	assert.Equal(t, conf.NumChannels, captureChannels(source.ID))
+
+	_, err = ConfigureSourceChannels(source, conf.ChannelSettings{Mode: conf.ChannelModeSplit, Count: 1})
+	require.Error(t, err, "invalid settings are rejected")
+}
+
+func TestReadClipFromCaptureBuffer_Multichannel(t *testing.T) {
+	source := registerChannelTestSource(t, "rtsp://channels-clip.example.com/stream")
+
+	ids, err := ConfigureSourceChannels(source, conf.ChannelSettings{Mode: conf.ChannelModeSplit, Count: 2})
+	require.NoError(t, err)
+	require.NoError(t, AllocateCaptureBufferIfNeeded(5, conf.SampleRate, conf.BitDepth/8, ids[1]))
+
+	cbMutex.RLock()
+	cb := captureBuffers[source.ID]
+	cbMutex.RUnlock()
+	require.NotNil(t, cb, "sub-sources share the capture buffer of their source")
+	assert.Equal(t, 2, cb.channels)
+	assert.Zero(t, cb.bufferSize%4, "the buffer holds whole frames")
+
+	cb.Write(interleavedTestFrames(cb.bufferSize/4, 1000, -2000))
+	start := cb.startTime.Add(cb.bufferDuration / 2)
+
+	clip, channels, err := ReadClipFromCaptureBuffer(ids[0], start, 1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, channels)
+	assert.Len(t, clip, conf.SampleRate*4, "clips keep both channels")
+
+	segment, err := ReadSegmentFromCaptureBuffer(ids[1], start, 1)
+	require.NoError(t, err)
+	require.Len(t, segment, conf.SampleRate*2)
+	assert.Equal(t, int16(-2000), pcmSamples(segment)[0], "analysis sources read their own channel")
+}
+
+func TestAlignCaptureBufferSize(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 4096, alignCaptureBufferSize(4000, 2))
+	assert.Equal(t, 12288, alignCaptureBufferSize(4000, 6), "three channel frames do not divide 2048")
+	assert.Zero(t, alignCaptureBufferSize(4000, 6)%6)
+}
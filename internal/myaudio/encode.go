@@ -41,6 +41,13 @@ func recordFileOperationErrorWithValidation(operation, format, errorType, valida
 
 // SavePCMDataToWAV saves the given PCM data as a WAV file at the specified filePath.
 func SavePCMDataToWAV(filePath string, pcmData []byte) error {
+	return SaveInterleavedPCMToWAV(filePath, pcmData, conf.NumChannels, nil)
+}
+
+// SaveInterleavedPCMToWAV saves interleaved PCM data with the given number of
+// channels as a WAV file at the specified filePath. Non-nil metadata is
+// appended as LIST/INFO, bext and GUANO chunks.
+func SaveInterleavedPCMToWAV(filePath string, pcmData []byte, channels int, meta *ClipMetadata) error {
 	log := GetLogger()
 	start := time.Now()
 
@@ -66,10 +73,21 @@ func SavePCMDataToWAV(filePath string, pcmData []byte) error {
 		return recordFileOperationError("save_wav", "wav", "empty_data", enhancedErr)
 	}
 
+	if channels < 1 || channels > conf.MaxCaptureChannels {
+		enhancedErr := errors.Newf("invalid channel count for WAV save operation: %d", channels).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "save_pcm_to_wav").
+			Context("num_channels", channels).
+			Build()
+
+		return recordFileOperationError("save_wav", "wav", "invalid_channels", enhancedErr)
+	}
+
 	// Validate PCM data alignment
-	expectedAlignment := conf.BitDepth / 8
+	expectedAlignment := conf.BitDepth / 8 * channels
 	if len(pcmData)%expectedAlignment != 0 {
-		enhancedErr := errors.Newf("PCM data size (%d bytes) is not aligned with bit depth (%d bits, %d bytes per sample)", len(pcmData), conf.BitDepth, expectedAlignment).
+		enhancedErr := errors.Newf("PCM data size (%d bytes) is not aligned with bit depth (%d bits, %d bytes per frame)", len(pcmData), conf.BitDepth, expectedAlignment).
 			Component("myaudio").
 			Category(errors.CategoryValidation).
 			Context("operation", "save_pcm_to_wav").
@@ -114,7 +132,7 @@ func SavePCMDataToWAV(filePath string, pcmData []byte) error {
 	}()
 
 	// Create a new WAV encoder with the specified format settings
-	enc := wav.NewEncoder(outFile, conf.SampleRate, conf.BitDepth, conf.NumChannels, 1)
+	enc := wav.NewEncoder(outFile, conf.SampleRate, conf.BitDepth, channels, 1)
 	if enc == nil {
 		enhancedErr := errors.Newf("failed to create WAV encoder").
 			Component("myaudio").
@@ -122,7 +140,7 @@ func SavePCMDataToWAV(filePath string, pcmData []byte) error {
 			Context("operation", "save_pcm_to_wav").
 			Context("sample_rate", conf.SampleRate).
 			Context("bit_depth", conf.BitDepth).
-			Context("num_channels", conf.NumChannels).
+			Context("num_channels", channels).
 			Build()
 
 		return recordFileOperationError("save_wav", "wav", "encoder_creation_failed", enhancedErr)
@@ -142,7 +160,7 @@ func SavePCMDataToWAV(filePath string, pcmData []byte) error {
 	}
 
 	// Write the integer samples to the WAV file
-	if err := enc.Write(&audio.IntBuffer{Data: intSamples, Format: &audio.Format{SampleRate: conf.SampleRate, NumChannels: conf.NumChannels}}); err != nil {
+	if err := enc.Write(&audio.IntBuffer{Data: intSamples, Format: &audio.Format{SampleRate: conf.SampleRate, NumChannels: channels}}); err != nil {
 		enhancedErr := errors.New(err).
 			Component("myaudio").
 			Category(errors.CategoryFileIO).
@@ -166,13 +184,26 @@ func SavePCMDataToWAV(filePath string, pcmData []byte) error {
 		return recordFileOperationError("save_wav", "wav", "encoder_close_failed", enhancedErr)
 	}
 
+	if meta != nil {
+		if err := appendWAVMetadata(outFile, meta, channels, len(intSamples)/channels); err != nil {
+			enhancedErr := errors.New(err).
+				Component("myaudio").
+				Category(errors.CategoryFileIO).
+				Context("operation", "save_pcm_to_wav").
+				Context("file_operation", "write_metadata").
+				Build()
+
+			return recordFileOperationError("save_wav", "wav", "metadata_write_failed", enhancedErr)
+		}
+	}
+
 	// Record successful operation
 	if fileMetrics != nil {
 		duration := time.Since(start).Seconds()
 		fileMetrics.RecordFileOperation("save_wav", "wav", "success")
 		fileMetrics.RecordFileOperationDuration("save_wav", "wav", duration)
 		fileMetrics.RecordFileSize("save_wav", "wav", int64(len(pcmData)))
-		fileMetrics.RecordAudioFileInfo("wav", conf.SampleRate, conf.NumChannels, conf.BitDepth, len(intSamples))
+		fileMetrics.RecordAudioFileInfo("wav", conf.SampleRate, channels, conf.BitDepth, len(intSamples))
 	}
 
 	return nil
@@ -36,6 +36,13 @@ const (
 // outputPath is full path with audio file name and extension based on format
 // pcmData is the PCM data to export
 func ExportAudioWithFFmpeg(pcmData []byte, outputPath string, settings *conf.AudioSettings) error {
+	return ExportInterleavedAudioWithFFmpeg(pcmData, outputPath, settings, conf.NumChannels, nil)
+}
+
+// ExportInterleavedAudioWithFFmpeg exports interleaved PCM data with the given
+// number of channels, keeping the channel layout in the exported file.
+// Non-nil metadata is written as tags of the output format.
+func ExportInterleavedAudioWithFFmpeg(pcmData []byte, outputPath string, settings *conf.AudioSettings, channels int, meta *ClipMetadata) error {
 	start := time.Now()
 
 	// Validate inputs
@@ -180,7 +187,7 @@ func ExportAudioWithFFmpeg(pcmData []byte, outputPath string, settings *conf.Aud
 	}
 
 	// Run the FFmpeg command to process the audio
-	if err := runFFmpegCommand(settings.FfmpegPath, pcmData, tempFilePath, settings); err != nil {
+	if err := runFFmpegCommand(settings.FfmpegPath, pcmData, tempFilePath, settings, channels, meta); err != nil {
 		enhancedErr := errors.New(err).
 			Component("myaudio").
 			Category(errors.CategorySystem).
@@ -343,10 +350,10 @@ func finalizeOutput(tempFilePath string) error {
 
 // runFFmpegCommand executes the FFmpeg command to process the audio
 // This version includes a context timeout to prevent hangs.
-func runFFmpegCommand(ffmpegPath string, pcmData []byte, tempFilePath string, settings *conf.AudioSettings) error {
+func runFFmpegCommand(ffmpegPath string, pcmData []byte, tempFilePath string, settings *conf.AudioSettings, channels int, meta *ClipMetadata) error {
 	log := GetLogger()
 	// Build the FFmpeg command arguments
-	args := buildFFmpegArgs(tempFilePath, settings)
+	args := buildFFmpegArgs(tempFilePath, settings, channels, meta)
 
 	// Create a context with a timeout (e.g., 30 seconds)
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -427,8 +434,8 @@ func runFFmpegCommand(ffmpegPath string, pcmData []byte, tempFilePath string, se
 }
 
 // buildFFmpegArgs constructs the arguments for the FFmpeg command
-func buildFFmpegArgs(tempFilePath string, settings *conf.AudioSettings) []string {
-	ffmpegSampleRate, ffmpegNumChannels, ffmpegFormat := getFFmpegFormat(conf.SampleRate, conf.NumChannels, conf.BitDepth)
+func buildFFmpegArgs(tempFilePath string, settings *conf.AudioSettings, channels int, meta *ClipMetadata) []string {
+	ffmpegSampleRate, ffmpegNumChannels, ffmpegFormat := getFFmpegFormat(conf.SampleRate, channels, conf.BitDepth)
 
 	outputEncoder := getEncoder(settings.Export.Type)
 	outputFormat := getOutputFormat(settings.Export.Type)
@@ -452,6 +459,12 @@ func buildFFmpegArgs(tempFilePath string, settings *conf.AudioSettings) []string
 	args = append(args,
 		"-c:a", outputEncoder,
 		"-b:a", outputBitrate,
+	)
+
+	// Tag the output with the detection metadata
+	args = append(args, meta.ffmpegMetadataArgs(settings.Export.Type)...)
+
+	args = append(args,
 		"-f", outputFormat, // Specify the output format
 		"-y",         // Overwrite output file if it exists
 		tempFilePath, // Write to the temporary file
@@ -931,6 +944,12 @@ func AnalyzeAudioLoudnessWithContext(ctx context.Context, pcmData []byte, ffmpeg
 
 // EncodePCMtoWAVWithContext encodes PCM data in WAV format using context for cancellation/timeout
 func EncodePCMtoWAVWithContext(ctx context.Context, pcmData []byte) (*bytes.Buffer, error) {
+	return EncodeInterleavedPCMtoWAVWithContext(ctx, pcmData, conf.NumChannels)
+}
+
+// EncodeInterleavedPCMtoWAVWithContext encodes interleaved PCM data with the given
+// number of channels in WAV format using context for cancellation/timeout
+func EncodeInterleavedPCMtoWAVWithContext(ctx context.Context, pcmData []byte, numChannels int) (*bytes.Buffer, error) {
 	start := time.Now()
 
 	// Validate inputs
@@ -963,14 +982,22 @@ func EncodePCMtoWAVWithContext(ctx context.Context, pcmData []byte) (*bytes.Buff
 		return nil, enhancedErr
 	}
 
+	if numChannels < 1 || numChannels > conf.MaxCaptureChannels {
+		return nil, errors.Newf("invalid channel count for WAV encoding: %d", numChannels).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "encode_pcm_to_wav_context").
+			Context("num_channels", numChannels).
+			Build()
+	}
+
 	// Constants for WAV format
-	const bitDepth = conf.BitDepth       // Bits per sample
-	const sampleRate = conf.SampleRate   // Sample rate
-	const numChannels = conf.NumChannels // Mono audio
+	const bitDepth = conf.BitDepth     // Bits per sample
+	const sampleRate = conf.SampleRate // Sample rate
 
 	// Calculating sizes and rates
-	byteRate := sampleRate * numChannels * (bitDepth / 8) // 48000 * 1 * 2 = 96000 bytes per second
-	blockAlign := numChannels * (bitDepth / 8)            // 1 * 2 = 2 bytes per frame
+	byteRate := sampleRate * numChannels * (bitDepth / 8) // 48000 * 1 * 2 = 96000 bytes per second for mono
+	blockAlign := numChannels * (bitDepth / 8)            // 1 * 2 = 2 bytes per frame for mono
 	subChunk2Size := uint32(len(pcmData))                 //nolint:gosec // G115: PCM data length bounded by available memory
 	chunkSize := 36 + subChunk2Size                       // 36 is fixed size for header
 
@@ -980,8 +1007,8 @@ func EncodePCMtoWAVWithContext(ctx context.Context, pcmData []byte) (*bytes.Buff
 	// List of data elements to write sequentially to the buffer
 	elements := []any{
 		[]byte("RIFF"), chunkSize, []byte("WAVE"),
-		[]byte("fmt "), uint32(16), uint16(1), uint16(numChannels),
-		uint32(sampleRate), uint32(byteRate), uint16(blockAlign), uint16(bitDepth),
+		[]byte("fmt "), uint32(16), uint16(1), uint16(numChannels), //nolint:gosec // G115: channel count validated above
+		uint32(sampleRate), uint32(byteRate), uint16(blockAlign), uint16(bitDepth), //nolint:gosec // G115: small positive format values
 		[]byte("data"), subChunk2Size,
 	}
 
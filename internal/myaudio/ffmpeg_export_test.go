@@ -126,7 +126,7 @@ func TestBuildFFmpegArgs(t *testing.T) {
 		},
 	}
 
-	args := buildFFmpegArgs(tempFile, settings)
+	args := buildFFmpegArgs(tempFile, settings, conf.NumChannels, nil)
 
 	// Verify -hide_banner is the first argument
 	assert.NotEmpty(t, args, "args should not be empty")
@@ -153,7 +153,7 @@ func TestBuildFFmpegArgs(t *testing.T) {
 	settings.Export.Normalization.TruePeak = -2.0
 	settings.Export.Normalization.LoudnessRange = 7.0
 
-	args = buildFFmpegArgs(tempFile, settings)
+	args = buildFFmpegArgs(tempFile, settings, conf.NumChannels, nil)
 
 	foundLoudnorm := false
 	for i, arg := range args {
@@ -171,7 +171,7 @@ func TestBuildFFmpegArgs(t *testing.T) {
 	settings.Export.Gain = 0
 	settings.Export.Normalization.Enabled = false
 
-	args = buildFFmpegArgs(tempFile, settings)
+	args = buildFFmpegArgs(tempFile, settings, conf.NumChannels, nil)
 
 	// Ensure -af flag is NOT present when no filters are needed
 	hasAudioFilter := slices.Contains(args, "-af")
@@ -0,0 +1,195 @@
+package myaudio
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+// Stream failover. A stream with backup URLs moves to the next URL when the
+// circuit breaker would open on the active one, and opens the breaker only
+// after every URL has failed in turn. While a backup is active, the manager
+// probes the primary URL and fails back once it delivers audio again. The
+// audio source, and with it the source ID of detections, stays bound to the
+// primary URL throughout.
+
+const (
+	// failbackProbeInterval is how often the primary URL is probed while a backup URL is active
+	failbackProbeInterval = 5 * time.Minute
+
+	// failbackProbeTimeout bounds a single probe of the primary URL
+	failbackProbeTimeout = 30 * time.Second
+
+	// failbackProbeSeconds is the audio the primary URL must deliver to count as recovered
+	failbackProbeSeconds = 3
+)
+
+// probeStreamURL checks that a URL delivers audio by decoding a few seconds of
+// it. It is a variable so tests can replace the ffmpeg probe.
+var probeStreamURL = func(ctx context.Context, ffmpegPath, url, transport string) error {
+	args := []string{"-hide_banner", "-loglevel", "error"}
+	if detectSourceTypeFromString(url) == SourceTypeRTSP {
+		args = append(args, "-rtsp_transport", transport)
+	}
+	args = append(args,
+		"-timeout", strconv.FormatInt(defaultTimeoutMicroseconds, 10),
+		"-i", url,
+		"-t", strconv.Itoa(failbackProbeSeconds),
+		"-vn", "-f", "null", "-",
+	)
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...) //nolint:gosec // G204: ffmpegPath from validated settings, url from stream configuration
+	setupProcessGroup(cmd)
+	return cmd.Run()
+}
+
+// activeBackupURL returns the backup URL in use, false while on the primary URL.
+func (s *FFmpegStream) activeBackupURL() (string, bool) {
+	s.failoverMu.Lock()
+	defer s.failoverMu.Unlock()
+	if s.activeURL == 0 || s.activeURL > len(s.backupURLs) {
+		return "", false
+	}
+	return s.backupURLs[s.activeURL-1], true
+}
+
+// activeSourceType returns the protocol of the URL in use. Backup URLs may use
+// a different protocol than the primary URL.
+func (s *FFmpegStream) activeSourceType() SourceType {
+	if backup, ok := s.activeBackupURL(); ok {
+		return detectSourceTypeFromString(backup)
+	}
+	if s.source == nil {
+		return SourceTypeUnknown
+	}
+	return s.source.Type
+}
+
+// failover moves the stream to its next URL. It returns false when the stream
+// has no backup URLs or every backup has failed, in which case the primary URL
+// becomes active again for the attempt after the circuit breaker cooldown.
+func (s *FFmpegStream) failover(reason string) bool {
+	s.failoverMu.Lock()
+	if len(s.backupURLs) == 0 {
+		s.failoverMu.Unlock()
+		return false
+	}
+	from := s.activeURL
+	if from >= len(s.backupURLs) {
+		s.activeURL = 0
+		s.failoverMu.Unlock()
+		getStreamLogger().Error("all stream URLs failed, retrying primary URL after cooldown",
+			logger.String("url", privacy.SanitizeStreamUrl(s.source.SafeString)),
+			logger.Int("backup_urls", len(s.backupURLs)),
+			logger.String("reason", reason),
+			logger.String("component", "ffmpeg-stream"),
+			logger.String("operation", "stream_failover_exhausted"))
+		return false
+	}
+	s.activeURL++
+	s.lastFailbackProbe = time.Now()
+	backup := s.backupURLs[s.activeURL-1]
+	s.failoverMu.Unlock()
+
+	// The next URL starts with a fresh backoff
+	s.restartCountMu.Lock()
+	s.restartCount = 0
+	s.restartCountMu.Unlock()
+
+	getStreamLogger().Warn("stream failing over to backup URL",
+		logger.String("url", privacy.SanitizeStreamUrl(s.source.SafeString)),
+		logger.String("backup_url", privacy.SanitizeStreamUrl(backup)),
+		logger.Int("backup_index", from+1),
+		logger.String("reason", reason),
+		logger.String("component", "ffmpeg-stream"),
+		logger.String("operation", "stream_failover"))
+	return true
+}
+
+// claimFailbackProbe reports whether the primary URL is due for a probe and
+// records the probe time, so concurrent health checks probe only once.
+func (s *FFmpegStream) claimFailbackProbe(now time.Time) bool {
+	s.failoverMu.Lock()
+	defer s.failoverMu.Unlock()
+	if s.activeURL == 0 || now.Sub(s.lastFailbackProbe) < failbackProbeInterval {
+		return false
+	}
+	s.lastFailbackProbe = now
+	return true
+}
+
+// failBack returns the stream to its primary URL, restarting a running
+// process so the switch happens now rather than at the next failure.
+func (s *FFmpegStream) failBack() {
+	s.failoverMu.Lock()
+	if s.activeURL == 0 {
+		s.failoverMu.Unlock()
+		return
+	}
+	s.activeURL = 0
+	s.failoverMu.Unlock()
+
+	getStreamLogger().Info("primary stream URL recovered, failing back",
+		logger.String("url", privacy.SanitizeStreamUrl(s.source.SafeString)),
+		logger.String("component", "ffmpeg-stream"),
+		logger.String("operation", "stream_failback"))
+
+	if s.GetProcessState() == StateRunning {
+		s.Restart(false)
+	}
+}
+
+// activeURLHealth returns the URL in use and whether it is a backup URL.
+func (s *FFmpegStream) activeURLHealth() (string, bool) {
+	if backup, ok := s.activeBackupURL(); ok {
+		return backup, true
+	}
+	if s.source == nil {
+		return "", false
+	}
+	primary, err := s.source.GetConnectionString()
+	if err != nil {
+		return "", false
+	}
+	return primary, false
+}
+
+// checkFailback probes the primary URL of streams running on a backup URL and
+// fails them back when the primary delivers audio again.
+func (m *FFmpegManager) checkFailback() {
+	now := time.Now()
+	m.streamsMu.RLock()
+	var due []*FFmpegStream
+	for _, stream := range m.streams {
+		if stream.claimFailbackProbe(now) {
+			due = append(due, stream)
+		}
+	}
+	m.streamsMu.RUnlock()
+
+	ffmpegPath := conf.Setting().Realtime.Audio.FfmpegPath
+	for _, stream := range due {
+		primary, err := stream.source.GetConnectionString()
+		if err != nil || primary == "" {
+			continue
+		}
+		m.wg.Go(func() {
+			ctx, cancel := context.WithTimeout(m.ctx, failbackProbeTimeout)
+			defer cancel()
+			if err := probeStreamURL(ctx, ffmpegPath, primary, stream.transport); err != nil {
+				if conf.Setting().Debug {
+					getManagerLogger().Debug("primary stream URL still unavailable",
+						logger.String("url", privacy.SanitizeStreamUrl(primary)),
+						logger.Error(err),
+						logger.String("operation", "failback_probe"))
+				}
+				return
+			}
+			stream.failBack()
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package myaudio
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failImmediately records failures until the circuit breaker threshold of immediate failures is reached
+func failImmediately(stream *FFmpegStream) {
+	for range circuitBreakerImmediateThreshold {
+		stream.recordFailure(100 * time.Millisecond)
+	}
+}
+
+func TestFFmpegStream_FailoverCyclesThroughBackupURLs(t *testing.T) {
+	stream := NewFFmpegStream("rtsp://test.local/failover-main", "tcp", nil)
+	stream.backupURLs = []string{"rtsp://test.local/failover-sub", "http://relay.local/failover.mp3"}
+	sourceID := stream.source.ID
+
+	_, ok := stream.activeBackupURL()
+	require.False(t, ok, "streams start on the primary URL")
+
+	failImmediately(stream)
+	backup, ok := stream.activeBackupURL()
+	require.True(t, ok)
+	assert.Equal(t, "rtsp://test.local/failover-sub", backup)
+	assert.False(t, stream.isCircuitOpenSilent(), "failover replaces the circuit breaker cooldown")
+	assert.Equal(t, 0, stream.getConsecutiveFailures())
+
+	failImmediately(stream)
+	backup, ok = stream.activeBackupURL()
+	require.True(t, ok)
+	assert.Equal(t, "http://relay.local/failover.mp3", backup)
+	assert.Equal(t, SourceTypeHTTP, stream.activeSourceType())
+	assert.NotContains(t, stream.buildFFmpegInputArgs(nil), "-rtsp_transport", "HTTP backups take no RTSP options")
+
+	health := stream.GetHealth()
+	assert.True(t, health.UsingBackupURL)
+	assert.Equal(t, "http://relay.local/failover.mp3", health.ActiveURL)
+
+	failImmediately(stream)
+	_, ok = stream.activeBackupURL()
+	assert.False(t, ok, "the primary URL is retried after every backup failed")
+	assert.True(t, stream.isCircuitOpenSilent(), "the circuit opens once every URL failed")
+	assert.Equal(t, sourceID, stream.source.ID, "the source ID never changes")
+}
+
+func TestFFmpegStream_NoBackupURLsOpensCircuit(t *testing.T) {
+	stream := NewFFmpegStream("rtsp://test.local/failover-none", "tcp", nil)
+
+	failImmediately(stream)
+	_, ok := stream.activeBackupURL()
+	assert.False(t, ok)
+	assert.True(t, stream.isCircuitOpenSilent())
+	assert.False(t, stream.GetHealth().UsingBackupURL)
+}
+
+func TestFFmpegManager_FailsBackWhenPrimaryRecovers(t *testing.T) {
+	originalProbe := probeStreamURL
+	t.Cleanup(func() { probeStreamURL = originalProbe })
+
+	var primaryUp atomic.Bool
+	var probes atomic.Int32
+	probeStreamURL = func(_ context.Context, _, url, _ string) error {
+		probes.Add(1)
+		assert.Equal(t, "rtsp://test.local/failback-main", url, "only the primary URL is probed")
+		if primaryUp.Load() {
+			return nil
+		}
+		return errors.New("connection refused")
+	}
+
+	manager := NewFFmpegManager()
+	t.Cleanup(func() { manager.cancel(nil) })
+	stream := NewFFmpegStream("rtsp://test.local/failback-main", "tcp", nil)
+	stream.backupURLs = []string{"rtsp://test.local/failback-sub"}
+	manager.streams["rtsp://test.local/failback-main"] = stream
+
+	failImmediately(stream)
+	_, ok := stream.activeBackupURL()
+	require.True(t, ok)
+
+	// Not yet due for a probe right after failing over
+	manager.checkFailback()
+	manager.wg.Wait()
+	assert.Zero(t, probes.Load())
+
+	// The primary is still down
+	stream.lastFailbackProbe = time.Now().Add(-failbackProbeInterval)
+	manager.checkFailback()
+	manager.wg.Wait()
+	assert.Equal(t, int32(1), probes.Load())
+	_, ok = stream.activeBackupURL()
+	assert.True(t, ok)
+
+	// The primary answers again
+	primaryUp.Store(true)
+	stream.lastFailbackProbe = time.Now().Add(-failbackProbeInterval)
+	manager.checkFailback()
+	manager.wg.Wait()
+	assert.Equal(t, int32(2), probes.Load())
+	_, ok = stream.activeBackupURL()
+	assert.False(t, ok, "the stream fails back to the primary URL")
+}
@@ -96,6 +96,14 @@ func registerSoundLevelProcessorIfEnabled(source string, log logger.Logger) erro
 			logger.String("display_name", audioSource.DisplayName),
 			logger.String("operation", "register_sound_level"))
 	}
+
+	// Channel sources of a split stream measure their own sound levels
+	if err := RegisterChannelSoundLevelProcessors(audioSource.ID); err != nil {
+		log.Warn("failed to register sound level processors for channel sources",
+			logger.String("id", audioSource.ID),
+			logger.Error(err),
+			logger.String("operation", "register_sound_level"))
+	}
 	return nil
 }
 
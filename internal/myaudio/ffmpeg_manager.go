@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"reflect"
 	"slices"
 	"sync"
 	"time"
@@ -102,6 +103,19 @@ func (m *FFmpegManager) StartStream(url, transport string, audioChan chan Unifie
 	// Create new stream first to get the source ID
 	stream := NewFFmpegStream(url, transport, audioChan)
 
+	// Alternative URLs of the same audio for failover
+	stream.backupURLs = slices.Clone(conf.Setting().BackupURLsFor(url))
+
+	// Split the stream into channel sources if configured
+	stream.channels = conf.Setting().ChannelsFor(url)
+	if _, err := ConfigureSourceChannels(stream.source, stream.channels); err != nil {
+		getManagerLogger().Error("invalid channel settings for stream, capturing mono",
+			logger.String("url", privacy.SanitizeStreamUrl(url)),
+			logger.String("source_id", stream.source.ID),
+			logger.Error(err),
+			logger.String("operation", "start_stream_channels"))
+	}
+
 	// Initialize buffers for the stream using the source ID, not the raw URL
 	if err := initializeBuffersForSource(stream.source.ID); err != nil {
 		getManagerLogger().Error("failed to initialize buffers for stream",
@@ -166,6 +180,18 @@ func (m *FFmpegManager) StopStream(url string) error {
 
 	// Unregister sound level processor while holding lock
 	UnregisterSoundLevelProcessor(url)
+	var channelSourceIDs []string
+	if stream.source != nil {
+		ResetMicHealth(stream.source.ID)
+		RemoveSourceFilterChain(stream.source.ID)
+		ResetNoiseReduction(stream.source.ID)
+		for _, id := range AnalysisSourceIDs(stream.source.ID) {
+			if id != stream.source.ID {
+				channelSourceIDs = append(channelSourceIDs, id)
+			}
+		}
+		RemoveSourceChannels(stream.source.ID)
+	}
 	getManagerLogger().Debug("unregistered sound level processor",
 		logger.String("url", privacy.SanitizeStreamUrl(url)),
 		logger.String("operation", "stop_stream"))
@@ -192,6 +218,16 @@ func (m *FFmpegManager) StopStream(url string) error {
 			logger.String("operation", "stop_stream_buffer_cleanup"))
 	}
 
+	for _, id := range channelSourceIDs {
+		if err := RemoveAnalysisBuffer(id); err != nil {
+			getManagerLogger().Warn("failed to remove channel analysis buffer",
+				logger.String("url", privacy.SanitizeStreamUrl(url)),
+				logger.String("source_id", id),
+				logger.Error(err),
+				logger.String("operation", "stop_stream_buffer_cleanup"))
+		}
+	}
+
 	if err := RemoveCaptureBuffer(url); err != nil {
 		getManagerLogger().Warn("failed to remove capture buffer",
 			logger.String("url", privacy.SanitizeStreamUrl(url)),
@@ -282,8 +318,10 @@ func (m *FFmpegManager) SyncWithConfig(audioChan chan UnifiedAudioData) error {
 	}
 	for url, stream := range m.streams {
 		if configTransport, configured := configuredURLs[url]; configured {
-			// Check if transport setting has changed for this stream
-			if stream.transport != configTransport {
+			// Check if transport, channel or backup URL settings have changed for this stream.
+			// Channel changes alter the capture format, so they restart the stream too.
+			if stream.transport != configTransport || !reflect.DeepEqual(stream.channels, settings.ChannelsFor(url)) ||
+				!slices.Equal(stream.backupURLs, settings.BackupURLsFor(url)) {
 				toRestart = append(toRestart, struct {
 					url          string
 					oldTransport string
@@ -433,6 +471,7 @@ func (m *FFmpegManager) StartMonitoring(interval time.Duration, audioChan chan U
 				return
 			case <-ticker.C:
 				m.checkStreamHealth()
+				m.checkFailback()
 			}
 		}
 	}()
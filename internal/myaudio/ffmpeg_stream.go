@@ -305,6 +305,9 @@ type StreamHealth struct {
 	// Note: Internally stores up to 100 errors for analysis, but only exposes the 10 most recent
 	LastErrorContext *ErrorContext   // Most recent error detected
 	ErrorHistory     []*ErrorContext // Recent error history (last 10 for diagnostics)
+	// Failover state
+	ActiveURL      string // URL currently streamed, a backup URL after failover
+	UsingBackupURL bool   // Whether the stream has failed over to a backup URL
 }
 
 // FFmpegStream manages a single FFmpeg process for audio streaming.
@@ -312,6 +315,7 @@ type StreamHealth struct {
 type FFmpegStream struct {
 	source    *AudioSource
 	transport string
+	channels  conf.ChannelSettings // channel settings applied when the stream was started
 	audioChan chan UnifiedAudioData
 
 	// Process management
@@ -321,6 +325,10 @@ type FFmpegStream struct {
 	stderr   bytes.Buffer
 	stderrMu sync.RWMutex // Protect stderr buffer access
 
+	// Channel splitting of multichannel streams, nil for mono streams.
+	// Set when the process starts and used by the reading goroutine.
+	splitter *channelSplitter
+
 	// State management
 	ctx         context.Context
 	cancel      context.CancelCauseFunc // Changed to CancelCauseFunc for better diagnostics
@@ -384,6 +392,12 @@ type FFmpegStream struct {
 	errorContexts   []*ErrorContext // Ring buffer of last N errors
 	errorContextsMu sync.RWMutex
 	maxErrorHistory int // Maximum number of error contexts to store
+
+	// Failover to backup URLs, set when the stream is started
+	backupURLs        []string  // alternative URLs tried in order after the primary
+	activeURL         int       // 0 for the primary URL, n for backupURLs[n-1]
+	lastFailbackProbe time.Time // last probe of the primary URL while on a backup
+	failoverMu        sync.Mutex
 }
 
 // threadSafeWriter wraps a bytes.Buffer with mutex protection for concurrent access
@@ -816,9 +830,6 @@ func (s *FFmpegStream) startProcess() error {
 			Build()
 	}
 
-	// Get FFmpeg format settings
-	sampleRate, numChannels, format := getFFmpegFormat(conf.SampleRate, conf.NumChannels, conf.BitDepth)
-
 	// Validate input source before building command to prevent nil dereference in
 	// buildFFmpegInputArgs (which accesses s.source.SafeString and s.source.Type).
 	if s.source == nil {
@@ -829,6 +840,11 @@ func (s *FFmpegStream) startProcess() error {
 			Build()
 	}
 
+	// Get FFmpeg format settings, capturing every channel of multichannel streams
+	layout := getChannelLayout(s.source.ID)
+	s.splitter = newChannelSplitter(layout)
+	sampleRate, numChannels, format := getFFmpegFormat(conf.SampleRate, captureChannels(s.source.ID), conf.BitDepth)
+
 	// Get RTSP settings for custom FFmpeg parameters
 	rtspSettings := conf.Setting().Realtime.RTSP
 
@@ -847,6 +863,11 @@ func (s *FFmpegStream) startProcess() error {
 			Build()
 	}
 
+	// A failed over stream reads a backup URL under the same source
+	if backup, ok := s.activeBackupURL(); ok {
+		connStr = backup
+	}
+
 	// Prevent FFmpeg from starting with empty input which causes hard-to-debug restart loops
 	if connStr == "" {
 		return errors.Newf("connection string is empty for source %s, cannot start FFmpeg", s.source.ID).
@@ -953,7 +974,7 @@ func (s *FFmpegStream) buildFFmpegInputArgs(ffmpegParameters []string) []string
 
 	// Only add -rtsp_transport for RTSP streams. HTTP, HLS, RTMP and UDP streams
 	// do not support this option and FFmpeg will fail with "Option rtsp_transport not found".
-	if s.source != nil && s.source.Type == SourceTypeRTSP {
+	if s.source != nil && s.activeSourceType() == SourceTypeRTSP {
 		args = append(args, "-rtsp_transport", s.transport)
 	}
 
@@ -1331,30 +1352,17 @@ func (s *FFmpegStream) processAudio() error {
 
 // handleAudioData processes a chunk of audio data
 func (s *FFmpegStream) handleAudioData(data []byte) error {
-	// Apply audio EQ filters if enabled
-	// This must happen BEFORE writing to buffers so filtered audio is used for analysis
-	if conf.Setting().Realtime.Audio.Equalizer.Enabled {
-		// Ensure data length is even (required for 16-bit PCM samples)
-		// io.Reader doesn't guarantee aligned reads, so handle odd lengths defensively
-		filterLen := len(data)
-		if filterLen%2 != 0 {
-			filterLen-- // Truncate to even length; trailing byte remains unfiltered
-		}
-		if filterLen > 0 {
-			if eqErr := ApplyFilters(data[:filterLen]); eqErr != nil {
-				getStreamLogger().Warn("error applying audio EQ filters",
-					logger.String("url", privacy.SanitizeStreamUrl(s.source.SafeString)),
-					logger.Error(eqErr),
-					logger.String("component", "ffmpeg-stream"),
-					logger.String("operation", "apply_filters"))
-				// Non-fatal: continue processing with unfiltered audio
-			}
-		}
+	if s.splitter != nil {
+		return s.handleMultichannelAudioData(data)
 	}
 
-	// Write to analysis buffer using source ID
-	if err := WriteToAnalysisBuffer(s.source.ID, data); err != nil {
-		return errors.Newf("failed to write to analysis buffer: %w", err).
+	if err := s.analyzeAudioData(s.source.ID, data); err != nil {
+		return err
+	}
+
+	// Write to capture buffer using source ID
+	if err := WriteToCaptureBuffer(s.source.ID, data); err != nil {
+		return errors.Newf("failed to write to capture buffer: %w", err).
 			Category(errors.CategoryAudio).
 			Component("ffmpeg-stream").
 			Context("operation", "handle_audio_data").
@@ -1363,22 +1371,91 @@ func (s *FFmpegStream) handleAudioData(data []byte) error {
 			Build()
 	}
 
-	// Write to capture buffer using source ID
-	if err := WriteToCaptureBuffer(s.source.ID, data); err != nil {
+	s.publishAudioData(s.source.ID, s.source.DisplayName, data)
+	return nil
+}
+
+// handleMultichannelAudioData analyzes every channel target of a multichannel
+// stream and keeps the interleaved frames in the stream's capture buffer.
+func (s *FFmpegStream) handleMultichannelAudioData(data []byte) error {
+	frames, outputs := s.splitter.split(data)
+	if len(frames) == 0 {
+		return nil
+	}
+
+	targets := s.splitter.layout.targets
+	for i := range targets {
+		if err := s.analyzeAudioData(targets[i].id, outputs[i]); err != nil {
+			return err
+		}
+	}
+	s.splitter.merge()
+
+	if err := WriteToCaptureBuffer(s.source.ID, frames); err != nil {
 		return errors.Newf("failed to write to capture buffer: %w", err).
 			Category(errors.CategoryAudio).
 			Component("ffmpeg-stream").
 			Context("operation", "handle_audio_data").
 			Context("url", privacy.SanitizeStreamUrl(s.source.SafeString)).
+			Context("data_size", len(frames)).
+			Context("channels", s.splitter.layout.channels).
+			Build()
+	}
+
+	for i := range targets {
+		s.publishAudioData(targets[i].id, targets[i].name, outputs[i])
+	}
+	s.splitter.reportChannelLevels()
+	return nil
+}
+
+// analyzeAudioData filters mono audio of an analysis source and queues it for analysis.
+func (s *FFmpegStream) analyzeAudioData(sourceID string, data []byte) error {
+	// Apply the audio EQ filters of this source, which may override the global equalizer
+	// This must happen BEFORE writing to buffers so filtered audio is used for analysis
+	// Ensure data length is even (required for 16-bit PCM samples)
+	// io.Reader doesn't guarantee aligned reads, so handle odd lengths defensively
+	filterLen := len(data)
+	if filterLen%2 != 0 {
+		filterLen-- // Truncate to even length; trailing byte remains unfiltered
+	}
+	if filterLen > 0 {
+		if eqErr := ApplySourceFilters(sourceID, data[:filterLen]); eqErr != nil {
+			getStreamLogger().Warn("error applying audio EQ filters",
+				logger.String("url", privacy.SanitizeStreamUrl(s.source.SafeString)),
+				logger.String("source_id", sourceID),
+				logger.Error(eqErr),
+				logger.String("component", "ffmpeg-stream"),
+				logger.String("operation", "apply_filters"))
+			// Non-fatal: continue processing with unfiltered audio
+		}
+	}
+
+	// Write to analysis buffer using source ID
+	if err := WriteToAnalysisBuffer(sourceID, data); err != nil {
+		return errors.Newf("failed to write to analysis buffer: %w", err).
+			Category(errors.CategoryAudio).
+			Component("ffmpeg-stream").
+			Context("operation", "handle_audio_data").
+			Context("url", privacy.SanitizeStreamUrl(s.source.SafeString)).
+			Context("source_id", sourceID).
 			Context("data_size", len(data)).
 			Build()
 	}
+	return nil
+}
 
+// publishAudioData broadcasts mono audio of an analysis source and reports its
+// audio level, input health and sound level.
+func (s *FFmpegStream) publishAudioData(sourceID, displayName string, data []byte) {
 	// Broadcast to WebSocket clients using source ID
-	broadcastAudioData(s.source.ID, data)
+	broadcastAudioData(sourceID, data)
 
 	// Calculate audio level using source ID and DisplayName
-	audioLevel := calculateAudioLevel(data, s.source.ID, s.source.DisplayName)
+	audioLevel := calculateAudioLevel(data, sourceID, displayName)
+
+	// Judge input health (silence, clipping, DC offset, hum, roll-off)
+	ProcessMicHealth(sourceID, displayName, data)
 
 	// Create unified audio data
 	unifiedData := UnifiedAudioData{
@@ -1388,7 +1465,7 @@ func (s *FFmpegStream) handleAudioData(data []byte) error {
 
 	// Process sound level if enabled
 	if conf.Setting().Realtime.Audio.SoundLevel.Enabled {
-		if soundLevel, err := ProcessSoundLevelData(s.source.ID, data); err != nil {
+		if soundLevel, err := ProcessSoundLevelData(sourceID, data); err != nil {
 			// Log as warning if it's a registration issue, debug otherwise
 			// Skip logging for normal conditions (interval incomplete, no data)
 			if errors.Is(err, ErrSoundLevelProcessorNotRegistered) {
@@ -1422,8 +1499,6 @@ func (s *FFmpegStream) handleAudioData(data []byte) error {
 		// Channel full, drop data to avoid blocking
 		s.logDroppedData()
 	}
-
-	return nil
 }
 
 // logDroppedData logs dropped audio data with rate limiting
@@ -1991,7 +2066,11 @@ func (s *FFmpegStream) GetHealth() StreamHealth {
 			logger.String("operation", "get_health"))
 	}
 
+	activeURL, usingBackup := s.activeURLHealth()
+
 	return StreamHealth{
+		ActiveURL:          activeURL,
+		UsingBackupURL:     usingBackup,
 		IsHealthy:          isHealthy,
 		LastDataReceived:   lastData,
 		RestartCount:       restarts,
@@ -2211,6 +2290,13 @@ func (s *FFmpegStream) recordFailure(runtime time.Duration) {
 		reason = "consecutive failure threshold"
 	}
 
+	// Streams with backup URLs move on to the next URL instead of waiting
+	// out the cooldown, the circuit opens once every URL has failed
+	if shouldOpenCircuit && s.failover(reason) {
+		s.consecutiveFailures = 0
+		return
+	}
+
 	if shouldOpenCircuit {
 		currentFailures := s.consecutiveFailures
 		s.circuitOpenTime = time.Now()
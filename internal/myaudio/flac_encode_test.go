@@ -0,0 +1,124 @@
+package myaudio
+
+import (
+	"bytes"
+	"crypto/md5" //nolint:gosec // G501: FLAC checksum
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/flac"
+)
+
+// decodeFLAC decodes a FLAC stream into interleaved 16-bit PCM
+func decodeFLAC(t *testing.T, r io.Reader) (pcm []byte, decoder *flac.Decoder) {
+	t.Helper()
+	decoder, err := flac.NewDecoder(r)
+	require.NoError(t, err)
+	for {
+		frame, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			return pcm, decoder
+		}
+		require.NoError(t, err)
+		pcm = append(pcm, frame...)
+	}
+}
+
+// birdLikePCM returns interleaved PCM of a chirp with noise, the channels differ in level and phase
+func birdLikePCM(frames, channels int) []byte {
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // G404: deterministic test data
+	pcm := make([]byte, frames*channels*2)
+	for f := range frames {
+		tSec := float64(f) / conf.SampleRate
+		chirp := math.Sin(2 * math.Pi * (2000 + 500*tSec) * tSec)
+		for ch := range channels {
+			value := 8000*chirp/float64(ch+1) + 30*rng.NormFloat64()
+			binary.LittleEndian.PutUint16(pcm[(f*channels+ch)*2:], uint16(int16(value))) //nolint:gosec // G115: bounded test sample
+		}
+	}
+	return pcm
+}
+
+func TestEncodeInterleavedPCMtoFLAC_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		pcm      []byte
+		channels int
+	}{
+		{"mono partial block", birdLikePCM(conf.SampleRate+123, 1), 1},
+		{"stereo", birdLikePCM(conf.SampleRate/2, 2), 2},
+		{"identical stereo channels", interleavedTestFrames(10000, 1234, 1234), 2},
+		{"four channels", birdLikePCM(9000, 4), 4},
+		{"silence", make([]byte, 8192*2), 1},
+		{"full scale", interleavedTestFrames(5000, math.MaxInt16, math.MinInt16), 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			encoded, err := EncodeInterleavedPCMtoFLAC(tt.pcm, tt.channels)
+			require.NoError(t, err)
+			assert.Less(t, encoded.Len(), len(tt.pcm)+1024, "encoding never grows much beyond verbatim")
+
+			decoded, decoder := decodeFLAC(t, bytes.NewReader(encoded.Bytes()))
+			assert.Equal(t, tt.pcm, decoded, "decoding is lossless")
+			assert.Equal(t, tt.channels, decoder.NChannels)
+			assert.Equal(t, conf.SampleRate, decoder.SampleRate)
+			assert.Equal(t, int64(len(tt.pcm)/(2*tt.channels)), decoder.TotalSamples)
+			checksum := md5.Sum(tt.pcm) //nolint:gosec // G401: FLAC checksum
+			assert.Equal(t, checksum[:], encoded.Bytes()[26:42], "STREAMINFO carries the MD5 of the samples")
+		})
+	}
+}
+
+func TestEncodeInterleavedPCMtoFLAC_Compresses(t *testing.T) {
+	t.Parallel()
+
+	pcm := birdLikePCM(3*conf.SampleRate, 1)
+	encoded, err := EncodeInterleavedPCMtoFLAC(pcm, 1)
+	require.NoError(t, err)
+	assert.Less(t, encoded.Len(), len(pcm)*3/4)
+
+	_, err = EncodeInterleavedPCMtoFLAC(nil, 1)
+	require.Error(t, err)
+	_, err = EncodeInterleavedPCMtoFLAC(pcm, 9)
+	require.Error(t, err)
+}
+
+func TestFLACEncoder_StreamsToFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "stream.flac")
+	f, err := os.Create(path) //nolint:gosec // G304: test temp file
+	require.NoError(t, err)
+
+	pcm := birdLikePCM(20000, 2)
+	encoder, err := NewFLACEncoder(f, 2)
+	require.NoError(t, err)
+	// Chunks that split frames exercise the pending buffer
+	for chunk := range slices.Chunk(pcm, 1001) {
+		require.NoError(t, encoder.Write(chunk))
+	}
+	require.NoError(t, encoder.Close())
+	require.NoError(t, f.Close())
+
+	f, err = os.Open(path) //nolint:gosec // G304: test temp file
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	decoded, decoder := decodeFLAC(t, f)
+	assert.Equal(t, pcm, decoded)
+	assert.Equal(t, int64(20000), decoder.TotalSamples, "the sample count is written on close")
+}
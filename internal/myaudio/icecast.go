@@ -0,0 +1,373 @@
+package myaudio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"os/exec"
+	"strconv"
+	"sync"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Icecast streams encode the live audio of a source once with FFmpeg and fan
+// the encoded bytes out to every listener of that source. The encoder starts
+// with the first listener and stops with the last. Ogg streams replay their
+// header pages to listeners joining later, MP3 frames need no header.
+
+const (
+	icecastTapBuffer      = 32  // PCM chunks queued for the encoder
+	icecastListenerBuffer = 128 // encoded chunks queued per listener before it is dropped
+	icecastReadSize       = 4096
+	icecastNoiseBlock     = conf.SampleRate / 2 // samples denoised at once, bounds added latency
+
+	oggPageHeaderSize = 27
+	oggHeaderPages    = 2 // OpusHead and OpusTags
+)
+
+// ErrTooManyIcecastListeners is returned when a source already streams to its
+// maximum number of listeners.
+var ErrTooManyIcecastListeners = errors.Newf("too many listeners for this stream").
+	Component("myaudio").
+	Category(errors.CategoryLimit).
+	Build()
+
+// IcecastContentType returns the MIME type of a stream format.
+func IcecastContentType(format string) string {
+	if format == conf.IcecastFormatOpus {
+		return "audio/ogg"
+	}
+	return "audio/mpeg"
+}
+
+// IcecastListener receives the encoded stream of one source.
+type IcecastListener struct {
+	ch     chan []byte
+	stream *icecastStream
+	once   sync.Once
+}
+
+// Chunks delivers the encoded stream. It closes when the listener falls too
+// far behind, the encoder stops or the listener is closed.
+func (l *IcecastListener) Chunks() <-chan []byte {
+	return l.ch
+}
+
+// Close disconnects the listener, stopping the encoder after the last one.
+func (l *IcecastListener) Close() {
+	l.once.Do(func() { l.stream.removeListener(l) })
+}
+
+// icecastStream is the running encoder of a source and its listeners
+type icecastStream struct {
+	sourceID string
+	format   string
+	cancel   context.CancelFunc
+
+	mu        sync.Mutex
+	listeners map[*IcecastListener]struct{}
+	headers   [][]byte // Ogg header pages, replayed to every new listener
+}
+
+// Running streams by source ID
+var (
+	icecastStreams   = make(map[string]*icecastStream)
+	icecastStreamsMu sync.Mutex
+)
+
+// icecastEncoder encodes mono analysis-rate PCM written to stdin into the
+// stream format read from stdout. wait returns once the encoder has exited.
+type icecastEncoder struct {
+	stdin  io.WriteCloser
+	stdout io.Reader
+	wait   func() error
+}
+
+// startIcecastEncoder starts the encoder of a stream, replaced in tests.
+var startIcecastEncoder = startFFmpegIcecastEncoder
+
+// AddIcecastListener connects a listener to the live stream of a source,
+// starting its encoder when it is the first one.
+func AddIcecastListener(sourceID string, settings *conf.IcecastSettings) (*IcecastListener, error) {
+	icecastStreamsMu.Lock()
+	defer icecastStreamsMu.Unlock()
+
+	stream, running := icecastStreams[sourceID]
+	if !running {
+		var err error
+		if stream, err = newIcecastStream(sourceID, settings); err != nil {
+			return nil, err
+		}
+		icecastStreams[sourceID] = stream
+	}
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	if len(stream.listeners) >= settings.MaxListeners {
+		return nil, ErrTooManyIcecastListeners
+	}
+	listener := &IcecastListener{ch: make(chan []byte, icecastListenerBuffer+oggHeaderPages), stream: stream}
+	for _, page := range stream.headers {
+		listener.ch <- page
+	}
+	stream.listeners[listener] = struct{}{}
+	return listener, nil
+}
+
+// IcecastListenerCount returns the number of listeners streaming a source.
+func IcecastListenerCount(sourceID string) int {
+	icecastStreamsMu.Lock()
+	defer icecastStreamsMu.Unlock()
+	stream, running := icecastStreams[sourceID]
+	if !running {
+		return 0
+	}
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+	return len(stream.listeners)
+}
+
+// newIcecastStream starts the encoder of a source fed from its audio tap.
+func newIcecastStream(sourceID string, settings *conf.IcecastSettings) (*icecastStream, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	encoder, err := startIcecastEncoder(ctx, settings)
+	if err != nil {
+		cancel()
+		return nil, errors.New(err).
+			Component("myaudio").
+			Category(errors.CategorySystem).
+			Context("operation", "start_icecast_stream").
+			Context("format", settings.Format).
+			Build()
+	}
+
+	stream := &icecastStream{
+		sourceID:  sourceID,
+		format:    settings.Format,
+		cancel:    cancel,
+		listeners: make(map[*IcecastListener]struct{}),
+	}
+	audio, cancelTap := TapSourceAudio(sourceID, icecastTapBuffer)
+	go stream.feed(ctx, audio, cancelTap, encoder.stdin, settings.NoiseReduction)
+	go stream.fanOut(encoder)
+
+	GetLogger().Info("icecast stream started",
+		logger.String("source_id", sourceID),
+		logger.String("format", settings.Format),
+		logger.Int("bitrate_kbps", settings.BitRate))
+	return stream, nil
+}
+
+// feed writes the tapped audio of the source to the encoder, denoising it
+// first when enabled.
+func (s *icecastStream) feed(ctx context.Context, audio <-chan []byte, cancelTap func(), stdin io.WriteCloser, denoise bool) {
+	defer func() { _ = stdin.Close() }()
+	defer cancelTap()
+
+	var reducer *noiseReducer
+	var pending []float64
+	if denoise {
+		reducer = newSourceNoiseReducer(s.sourceID)
+	}
+
+	for {
+		var data []byte
+		select {
+		case <-ctx.Done():
+			return
+		case data = <-audio:
+		}
+
+		if reducer != nil {
+			pending = append(pending, BytesToFloat64PCM16(data)...)
+			if len(pending) < icecastNoiseBlock {
+				continue
+			}
+			settings := conf.Setting().Realtime.Audio.NoiseReduction
+			reducer.process(pending, &settings, true)
+			data = make([]byte, len(pending)*2)
+			_ = Float64ToBytesPCM16(pending, data)
+			pending = pending[:0]
+		}
+
+		if _, err := stdin.Write(data); err != nil {
+			return
+		}
+	}
+}
+
+// fanOut passes the encoded stream to the listeners until the encoder exits,
+// then disconnects them.
+func (s *icecastStream) fanOut(encoder *icecastEncoder) {
+	r := bufio.NewReaderSize(encoder.stdout, icecastReadSize)
+	var err error
+	for pages := 0; ; pages++ {
+		var chunk []byte
+		if s.format == conf.IcecastFormatOpus {
+			// Whole pages, so listeners can join at any page boundary
+			chunk, err = readOggPage(r)
+		} else {
+			buf := make([]byte, icecastReadSize)
+			var n int
+			n, err = r.Read(buf)
+			chunk = buf[:n]
+		}
+		if len(chunk) > 0 {
+			s.broadcast(chunk, s.format == conf.IcecastFormatOpus && pages < oggHeaderPages)
+		}
+		if err != nil {
+			break
+		}
+	}
+	if !errors.Is(err, io.EOF) {
+		// Unreadable output, stop the encoder and drain it so it can exit
+		s.cancel()
+		_, _ = io.Copy(io.Discard, r)
+	}
+	if err := encoder.wait(); err != nil {
+		GetLogger().Warn("icecast encoder failed",
+			logger.String("source_id", s.sourceID),
+			logger.Error(err))
+	}
+
+	icecastStreamsMu.Lock()
+	if icecastStreams[s.sourceID] == s {
+		delete(icecastStreams, s.sourceID)
+	}
+	icecastStreamsMu.Unlock()
+	s.cancel()
+
+	s.mu.Lock()
+	for listener := range s.listeners {
+		delete(s.listeners, listener)
+		close(listener.ch)
+	}
+	s.mu.Unlock()
+
+	GetLogger().Info("icecast stream stopped",
+		logger.String("source_id", s.sourceID))
+}
+
+// broadcast queues an encoded chunk for every listener, dropping listeners
+// whose queue is full. Header chunks are kept for later listeners.
+func (s *icecastStream) broadcast(chunk []byte, header bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if header {
+		s.headers = append(s.headers, chunk)
+	}
+	for listener := range s.listeners {
+		select {
+		case listener.ch <- chunk:
+		default:
+			GetLogger().Info("dropping slow icecast listener",
+				logger.String("source_id", s.sourceID))
+			delete(s.listeners, listener)
+			close(listener.ch)
+		}
+	}
+}
+
+// removeListener disconnects a listener and stops the stream when it was the last.
+func (s *icecastStream) removeListener(listener *IcecastListener) {
+	icecastStreamsMu.Lock()
+	defer icecastStreamsMu.Unlock()
+
+	s.mu.Lock()
+	if _, connected := s.listeners[listener]; connected {
+		delete(s.listeners, listener)
+		close(listener.ch)
+	}
+	remaining := len(s.listeners)
+	s.mu.Unlock()
+
+	if remaining == 0 && icecastStreams[s.sourceID] == s {
+		delete(icecastStreams, s.sourceID)
+		s.cancel()
+	}
+}
+
+// readOggPage reads one complete Ogg page.
+func readOggPage(r *bufio.Reader) ([]byte, error) {
+	header, err := r.Peek(oggPageHeaderSize)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(header[:4], []byte("OggS")) {
+		return nil, errors.Newf("invalid ogg page").
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "read_ogg_page").
+			Build()
+	}
+	segments := int(header[oggPageHeaderSize-1])
+	table, err := r.Peek(oggPageHeaderSize + segments)
+	if err != nil {
+		return nil, err
+	}
+	size := oggPageHeaderSize + segments
+	for _, lacing := range table[oggPageHeaderSize:] {
+		size += int(lacing)
+	}
+
+	page := make([]byte, size)
+	if _, err := io.ReadFull(r, page); err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// startFFmpegIcecastEncoder encodes with FFmpeg.
+func startFFmpegIcecastEncoder(ctx context.Context, settings *conf.IcecastSettings) (*icecastEncoder, error) {
+	ffmpegPath := conf.Setting().Realtime.Audio.FfmpegPath
+	if err := validateFFmpegPath(ffmpegPath); err != nil {
+		return nil, err
+	}
+
+	args := []string{
+		"-hide_banner", "-loglevel", "error",
+		"-f", "s16le", "-ar", strconv.Itoa(conf.SampleRate), "-ac", "1", "-i", "pipe:0",
+		"-b:a", strconv.Itoa(settings.BitRate) + "k",
+		"-flush_packets", "1",
+	}
+	if settings.Format == conf.IcecastFormatOpus {
+		args = append(args, "-c:a", "libopus", "-application", "audio", "-f", "ogg")
+	} else {
+		args = append(args, "-c:a", "libmp3lame", "-f", "mp3")
+	}
+	args = append(args, "pipe:1")
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, ffmpegPath, args...) //nolint:gosec // G204: ffmpegPath from validated settings, args are fixed
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	return &icecastEncoder{
+		stdin:  stdin,
+		stdout: stdout,
+		wait: func() error {
+			if err := cmd.Wait(); err != nil && ctx.Err() == nil {
+				return errors.Newf("encoder failed: %s", bytes.TrimSpace(stderr.Bytes())).
+					Component("myaudio").
+					Category(errors.CategorySystem).
+					Context("operation", "icecast_encoder").
+					Build()
+			}
+			return nil
+		},
+	}, nil
+}
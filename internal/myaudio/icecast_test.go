@@ -0,0 +1,156 @@
+package myaudio
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// NOTE: Do NOT use t.Parallel() - these tests replace the global stream encoder
+
+// usePassthroughIcecastEncoder replaces FFmpeg with an encoder whose output is its input
+func usePassthroughIcecastEncoder(t *testing.T) {
+	t.Helper()
+	original := startIcecastEncoder
+	startIcecastEncoder = func(ctx context.Context, _ *conf.IcecastSettings) (*icecastEncoder, error) {
+		inR, inW := io.Pipe()
+		outR, outW := io.Pipe()
+		done := make(chan struct{})
+		go func() {
+			<-ctx.Done()
+			_ = inR.Close()
+		}()
+		go func() {
+			defer close(done)
+			_, _ = io.Copy(outW, inR)
+			_ = outW.Close()
+		}()
+		return &icecastEncoder{stdin: inW, stdout: outR, wait: func() error { <-done; return nil }}, nil
+	}
+	t.Cleanup(func() { startIcecastEncoder = original })
+}
+
+// testOggPage builds an Ogg page holding body in a single segment
+func testOggPage(seq uint32, body string) []byte {
+	page := make([]byte, oggPageHeaderSize+1, oggPageHeaderSize+1+len(body))
+	copy(page, "OggS")
+	binary.LittleEndian.PutUint32(page[18:], seq)
+	page[oggPageHeaderSize-1] = 1
+	page[oggPageHeaderSize] = byte(len(body))
+	return append(page, body...)
+}
+
+// receiveChunk waits for the next chunk of a listener
+func receiveChunk(t *testing.T, listener *IcecastListener) []byte {
+	t.Helper()
+	select {
+	case chunk, ok := <-listener.Chunks():
+		require.True(t, ok, "listener disconnected")
+		return chunk
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "timed out waiting for stream data")
+		return nil
+	}
+}
+
+func TestTapSourceAudio_FanOut(t *testing.T) {
+	first, cancelFirst := TapSourceAudio("tap_source", 1)
+	second, cancelSecond := TapSourceAudio("tap_source", 1)
+	defer cancelSecond()
+
+	data := []byte{1, 2, 3, 4}
+	feedAudioTaps("tap_source", data)
+	data[0] = 9 // callers reuse their buffers
+	assert.Equal(t, []byte{1, 2, 3, 4}, <-first)
+	assert.Equal(t, []byte{1, 2, 3, 4}, <-second)
+
+	// A full tap drops chunks instead of blocking
+	feedAudioTaps("tap_source", []byte{5, 6})
+	feedAudioTaps("tap_source", []byte{7, 8})
+	assert.Equal(t, []byte{5, 6}, <-second)
+
+	cancelFirst()
+	cancelFirst()
+	assert.Equal(t, []byte{5, 6}, <-first)
+	_, open := <-first
+	assert.False(t, open)
+}
+
+func TestReadOggPage(t *testing.T) {
+	t.Parallel()
+
+	stream := append(testOggPage(0, "OpusHead"), testOggPage(1, "OpusTags")...)
+	r := bufio.NewReader(bytes.NewReader(stream))
+
+	page, err := readOggPage(r)
+	require.NoError(t, err)
+	assert.Equal(t, testOggPage(0, "OpusHead"), page)
+	page, err = readOggPage(r)
+	require.NoError(t, err)
+	assert.Equal(t, testOggPage(1, "OpusTags"), page)
+	_, err = readOggPage(r)
+	require.ErrorIs(t, err, io.EOF)
+
+	_, err = readOggPage(bufio.NewReader(bytes.NewReader(bytes.Repeat([]byte("x"), 64))))
+	require.Error(t, err)
+}
+
+func TestIcecastListeners_LimitAndStop(t *testing.T) {
+	usePassthroughIcecastEncoder(t)
+	settings := &conf.IcecastSettings{Format: conf.IcecastFormatMP3, BitRate: 128, MaxListeners: 2}
+
+	first, err := AddIcecastListener("icecast_mp3", settings)
+	require.NoError(t, err)
+	second, err := AddIcecastListener("icecast_mp3", settings)
+	require.NoError(t, err)
+	_, err = AddIcecastListener("icecast_mp3", settings)
+	require.ErrorIs(t, err, ErrTooManyIcecastListeners)
+	assert.Equal(t, 2, IcecastListenerCount("icecast_mp3"))
+
+	feedAudioTaps("icecast_mp3", []byte("frame"))
+	assert.Equal(t, []byte("frame"), receiveChunk(t, first))
+	assert.Equal(t, []byte("frame"), receiveChunk(t, second))
+
+	first.Close()
+	assert.Equal(t, 1, IcecastListenerCount("icecast_mp3"))
+	second.Close()
+	assert.Zero(t, IcecastListenerCount("icecast_mp3"))
+
+	// The encoder released its audio tap
+	assert.Eventually(t, func() bool {
+		audioTapsMu.RLock()
+		defer audioTapsMu.RUnlock()
+		return len(audioTaps["icecast_mp3"]) == 0
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+func TestIcecastListeners_OggHeadersReplayed(t *testing.T) {
+	usePassthroughIcecastEncoder(t)
+	settings := &conf.IcecastSettings{Format: conf.IcecastFormatOpus, BitRate: 64, MaxListeners: 5}
+
+	first, err := AddIcecastListener("icecast_opus", settings)
+	require.NoError(t, err)
+	defer first.Close()
+
+	for seq, body := range []string{"OpusHead", "OpusTags", "audio-1"} {
+		feedAudioTaps("icecast_opus", testOggPage(uint32(seq), body)) //nolint:gosec // G115: small test index
+		receiveChunk(t, first)
+	}
+
+	late, err := AddIcecastListener("icecast_opus", settings)
+	require.NoError(t, err)
+	defer late.Close()
+	feedAudioTaps("icecast_opus", testOggPage(3, "audio-2"))
+
+	assert.Equal(t, testOggPage(0, "OpusHead"), receiveChunk(t, late))
+	assert.Equal(t, testOggPage(1, "OpusTags"), receiveChunk(t, late))
+	assert.Equal(t, testOggPage(3, "audio-2"), receiveChunk(t, late))
+}
@@ -0,0 +1,578 @@
+package myaudio
+
+import (
+	"context"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// HTTP push ingest. Remote recorders that cannot serve a stream POST their
+// audio, each request carrying the device capture time of its first sample.
+// Every device is a registered source with its own playout buffer: pushed
+// chunks are placed on a server time line, corrected for the device clock
+// offset, and played out into the normal analysis and capture buffers at real
+// time, latency behind the recording. Missing audio is filled with silence so
+// the capture buffer time line stays aligned, audio arriving after its playout
+// time is dropped. Detections are timestamped at playout.
+
+const (
+	ingestConnectionPrefix = "ingest://"
+
+	ingestPlayoutInterval = 100 * time.Millisecond // playout tick of every device
+	ingestMaxBlock        = time.Second            // most audio played out per tick when catching up
+	ingestMaxBacklog      = 2 * time.Minute        // playout further behind than this skips ahead
+	ingestMaxPending      = time.Minute            // audio buffered beyond the latency before chunks are dropped
+	ingestIdleTimeout     = time.Minute            // a device without audio for this long stops playout
+	ingestOffsetWindow    = 32                     // clock offset observations the estimate is taken over
+)
+
+// Ingest audio formats
+const (
+	IngestFormatPCM  = "pcm"  // raw signed 16-bit little-endian PCM
+	IngestFormatFLAC = "flac" // FLAC stream
+	IngestFormatOpus = "opus" // Ogg Opus, decoded with FFmpeg
+)
+
+// ErrIngestDeviceNotFound is returned when audio is pushed for a device that is not active.
+var ErrIngestDeviceNotFound = errors.Newf("ingest device not found").
+	Component("myaudio").
+	Category(errors.CategoryNotFound).
+	Build()
+
+// IngestParams describes the audio of one push request.
+type IngestParams struct {
+	Format     string    // pcm, flac or opus
+	SampleRate int       // sample rate of PCM audio, FLAC and Opus carry their own
+	Channels   int       // channel count of PCM audio, downmixed to mono
+	Start      time.Time // device capture time of the first sample, zero for live audio
+}
+
+// IngestResult summarizes an accepted push request.
+type IngestResult struct {
+	Device        string  `json:"device"`
+	Seconds       float64 `json:"seconds"`         // audio received in this request
+	ClockOffsetMs int64   `json:"clock_offset_ms"` // estimated server minus device clock
+}
+
+// IngestDeviceStatus reports the state of a push ingest device.
+type IngestDeviceStatus struct {
+	ID              string    `json:"id"`
+	Name            string    `json:"name"`
+	SourceID        string    `json:"source_id"`
+	Active          bool      `json:"active"` // audio is being played out
+	LastReceived    time.Time `json:"last_received,omitzero"`
+	ReceivedSeconds float64   `json:"received_seconds"`
+	BufferedSeconds float64   `json:"buffered_seconds"` // audio waiting for playout
+	Gaps            int64     `json:"gaps"`             // gaps filled with silence
+	GapSeconds      float64   `json:"gap_seconds"`
+	LateSeconds     float64   `json:"late_seconds"` // audio dropped because it arrived after its playout time
+	ClockOffsetMs   int64     `json:"clock_offset_ms"`
+}
+
+// IngestConnectionString returns the registry connection string of a push ingest device.
+func IngestConnectionString(deviceID string) string {
+	return ingestConnectionPrefix + deviceID
+}
+
+// ingestChunk is pushed audio waiting for playout, start is its position on
+// the server time line in samples.
+type ingestChunk struct {
+	start int64
+	pcm   []byte
+}
+
+// end returns the position after the last sample of the chunk.
+func (c *ingestChunk) end() int64 {
+	return c.start + int64(len(c.pcm)/2)
+}
+
+// ingestDevice buffers the audio of one remote recorder for playout.
+type ingestDevice struct {
+	id string
+
+	mu           sync.Mutex
+	name         string
+	sourceID     string        // registry source ID, empty until activated
+	pending      []ingestChunk // sorted by start
+	cursor       int64         // next sample to play out, 0 before playout started
+	inGap        bool          // the last played sample was silence
+	offsets      []time.Duration
+	clockOffset  time.Duration
+	lastReceived time.Time
+
+	received int64 // samples received
+	late     int64 // samples dropped after their playout time
+	gaps     int64
+	silence  int64 // samples of gaps filled with silence
+}
+
+// ingestService plays out the audio of all push ingest devices.
+type ingestService struct {
+	mu        sync.RWMutex
+	devices   map[string]*ingestDevice
+	latency   time.Duration
+	audioChan chan UnifiedAudioData
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// Running push ingest, nil when ingest is disabled
+var (
+	activeIngest    atomic.Pointer[ingestService]
+	ingestLifecycle sync.Mutex
+)
+
+// SyncIngestDevices starts push ingest for the configured devices, keeping the
+// buffered audio of devices that remain configured. It stops ingest when it is
+// disabled or no device is configured, and when quitChan closes.
+func SyncIngestDevices(settings *conf.Settings, quitChan <-chan struct{}, audioChan chan UnifiedAudioData) error {
+	ingestLifecycle.Lock()
+	defer ingestLifecycle.Unlock()
+
+	ingest := settings.Realtime.Ingest
+	if !ingest.Enabled || len(ingest.Devices) == 0 {
+		stopIngestLocked()
+		return nil
+	}
+
+	svc := activeIngest.Load()
+	if svc == nil {
+		svc = &ingestService{
+			devices: make(map[string]*ingestDevice),
+			stop:    make(chan struct{}),
+			done:    make(chan struct{}),
+		}
+		activeIngest.Store(svc)
+		go svc.run(quitChan)
+	}
+
+	svc.mu.Lock()
+	svc.latency = time.Duration(ingest.Latency) * time.Second
+	svc.audioChan = audioChan
+	configured := make(map[string]bool, len(ingest.Devices))
+	var added []string
+	for i := range ingest.Devices {
+		device := &ingest.Devices[i]
+		configured[device.ID] = true
+		if existing, ok := svc.devices[device.ID]; ok {
+			if device.Name != "" {
+				existing.mu.Lock()
+				existing.name = device.Name
+				existing.mu.Unlock()
+			}
+			continue
+		}
+		added = append(added, device.ID)
+		svc.devices[device.ID] = &ingestDevice{id: device.ID, name: device.Name}
+	}
+	var removed []*ingestDevice
+	for id, device := range svc.devices {
+		if !configured[id] {
+			removed = append(removed, device)
+			delete(svc.devices, id)
+		}
+	}
+	svc.mu.Unlock()
+
+	for _, device := range removed {
+		releaseIngestDevice(device)
+	}
+
+	var errs []error
+	for _, id := range added {
+		if err := svc.activateDevice(id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StopIngest stops push ingest and releases the buffers of every device.
+func StopIngest() {
+	ingestLifecycle.Lock()
+	defer ingestLifecycle.Unlock()
+	stopIngestLocked()
+}
+
+// stopIngestLocked stops the running ingest service. The caller holds ingestLifecycle.
+func stopIngestLocked() {
+	svc := activeIngest.Swap(nil)
+	if svc == nil {
+		return
+	}
+	close(svc.stop)
+	<-svc.done
+
+	svc.mu.Lock()
+	devices := slices.Collect(maps.Values(svc.devices))
+	svc.devices = nil
+	svc.mu.Unlock()
+
+	for _, device := range devices {
+		releaseIngestDevice(device)
+	}
+	GetLogger().Info("push ingest stopped")
+}
+
+// activateDevice registers a device as an audio source and allocates its
+// buffers. Devices that fail to activate are removed again.
+func (svc *ingestService) activateDevice(id string) error {
+	svc.mu.RLock()
+	device := svc.devices[id]
+	svc.mu.RUnlock()
+	if device == nil {
+		return nil
+	}
+	device.mu.Lock()
+	name := device.name
+	device.mu.Unlock()
+
+	source, err := GetRegistry().RegisterSource(IngestConnectionString(id), SourceConfig{
+		DisplayName: name,
+		Type:        SourceTypePush,
+	})
+	if err == nil {
+		err = initializeBuffersForSource(source.ID)
+	}
+	if err != nil {
+		svc.mu.Lock()
+		delete(svc.devices, id)
+		svc.mu.Unlock()
+		return errors.New(err).
+			Component("myaudio").
+			Category(errors.CategoryAudioSource).
+			Context("operation", "activate_ingest_device").
+			Context("device_id", id).
+			Build()
+	}
+
+	if err := RegisterSoundLevelProcessor(source.ID, source.DisplayName); err != nil {
+		GetLogger().Warn("failed to register sound level processor for ingest device",
+			logger.String("device_id", id),
+			logger.Error(err))
+	}
+
+	device.mu.Lock()
+	device.sourceID = source.ID
+	device.name = source.DisplayName
+	device.mu.Unlock()
+
+	GetLogger().Info("push ingest device ready",
+		logger.String("device_id", id),
+		logger.String("source_id", source.ID))
+	return nil
+}
+
+// releaseIngestDevice removes the buffers and per-source state of a device.
+func releaseIngestDevice(device *ingestDevice) {
+	device.mu.Lock()
+	sourceID := device.sourceID
+	device.sourceID = ""
+	device.pending = nil
+	device.mu.Unlock()
+	if sourceID == "" {
+		return
+	}
+
+	UnregisterSoundLevelProcessor(sourceID)
+	ResetMicHealth(sourceID)
+	RemoveSourceFilterChain(sourceID)
+	ResetNoiseReduction(sourceID)
+	if err := RemoveAnalysisBuffer(sourceID); err != nil {
+		GetLogger().Warn("failed to remove ingest analysis buffer",
+			logger.String("device_id", device.id),
+			logger.Error(err))
+	}
+	if err := RemoveCaptureBuffer(sourceID); err != nil {
+		GetLogger().Warn("failed to remove ingest capture buffer",
+			logger.String("device_id", device.id),
+			logger.Error(err))
+	}
+}
+
+// run plays out the buffered audio of every device until ingest stops.
+func (svc *ingestService) run(quitChan <-chan struct{}) {
+	defer close(svc.done)
+	ticker := time.NewTicker(ingestPlayoutInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-svc.stop:
+			return
+		case <-quitChan:
+			// Release the devices unless ingest is being stopped already
+			go func() {
+				ingestLifecycle.Lock()
+				defer ingestLifecycle.Unlock()
+				if activeIngest.Load() == svc {
+					stopIngestLocked()
+				}
+			}()
+			<-svc.stop
+			return
+		case now := <-ticker.C:
+			svc.playout(now)
+		}
+	}
+}
+
+// playout hands the audio due at now of every device to the analysis pipeline.
+func (svc *ingestService) playout(now time.Time) {
+	svc.mu.RLock()
+	devices := slices.Collect(maps.Values(svc.devices))
+	latency := svc.latency
+	audioChan := svc.audioChan
+	svc.mu.RUnlock()
+
+	for _, device := range devices {
+		block := device.playout(now, latency)
+		if len(block) == 0 {
+			continue
+		}
+		device.mu.Lock()
+		sourceID, name := device.sourceID, device.name
+		device.mu.Unlock()
+		if sourceID == "" {
+			continue
+		}
+
+		analyzeDeviceAudio(sourceID, block)
+		if err := WriteToCaptureBuffer(sourceID, block); err != nil {
+			GetLogger().Warn("error writing ingest audio to capture buffer",
+				logger.String("device_id", device.id),
+				logger.Error(err))
+		}
+		publishDeviceAudio(sourceID, name, block, audioChan)
+	}
+}
+
+// IngestAudio decodes audio pushed by a device and queues it for playout. The
+// body may be a complete recording or a stream read until it ends.
+func IngestAudio(ctx context.Context, deviceID string, params IngestParams, body io.Reader) (IngestResult, error) {
+	svc := activeIngest.Load()
+	if svc == nil {
+		return IngestResult{}, ErrIngestDeviceNotFound
+	}
+	svc.mu.RLock()
+	device := svc.devices[deviceID]
+	svc.mu.RUnlock()
+	if device == nil {
+		return IngestResult{}, ErrIngestDeviceNotFound
+	}
+
+	result := IngestResult{Device: deviceID}
+	start := params.Start
+	var samples int64
+	err := decodeIngestAudio(ctx, params, body, func(pcm []byte) error {
+		n := int64(len(pcm) / 2)
+		receivedAt := time.Now()
+		if start.IsZero() {
+			// Live audio is taken to have been captured just now
+			start = receivedAt.Add(-samplesDuration(n))
+		}
+		device.push(start.Add(samplesDuration(samples)), pcm, receivedAt)
+		samples += n
+		return nil
+	})
+	result.Seconds = float64(samples) / float64(conf.SampleRate)
+	device.mu.Lock()
+	result.ClockOffsetMs = device.clockOffset.Milliseconds()
+	device.mu.Unlock()
+	return result, err
+}
+
+// GetIngestStatus reports the state of every push ingest device sorted by ID.
+func GetIngestStatus() []IngestDeviceStatus {
+	svc := activeIngest.Load()
+	if svc == nil {
+		return []IngestDeviceStatus{}
+	}
+	svc.mu.RLock()
+	statuses := make([]IngestDeviceStatus, 0, len(svc.devices))
+	for _, device := range svc.devices {
+		statuses = append(statuses, device.status())
+	}
+	svc.mu.RUnlock()
+
+	slices.SortFunc(statuses, func(a, b IngestDeviceStatus) int { return strings.Compare(a.ID, b.ID) })
+	return statuses
+}
+
+// push places mono analysis-rate PCM captured at start on the device clock on
+// the server time line.
+func (d *ingestDevice) push(start time.Time, pcm []byte, receivedAt time.Time) {
+	n := int64(len(pcm) / 2)
+	if n == 0 {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.received += n
+	d.lastReceived = receivedAt
+	d.observeClockOffset(receivedAt.Sub(start.Add(samplesDuration(n))))
+
+	chunk := ingestChunk{start: sampleIndex(start.Add(d.clockOffset)), pcm: pcm[:n*2]}
+	if d.cursor != 0 && chunk.start < d.cursor {
+		if chunk.end() <= d.cursor {
+			d.late += n
+			return
+		}
+		trim := d.cursor - chunk.start
+		d.late += trim
+		chunk.pcm = chunk.pcm[trim*2:]
+		chunk.start = d.cursor
+	}
+
+	pos := sort.Search(len(d.pending), func(i int) bool { return d.pending[i].start > chunk.start })
+	d.pending = slices.Insert(d.pending, pos, chunk)
+
+	// Bound memory of devices pushing far ahead of playout
+	var buffered int64
+	for i := range d.pending {
+		buffered += int64(len(d.pending[i].pcm) / 2)
+	}
+	limit := int64(ingestMaxPending.Seconds()) * conf.SampleRate
+	for buffered > limit && len(d.pending) > 1 {
+		dropped := int64(len(d.pending[len(d.pending)-1].pcm) / 2)
+		d.pending = d.pending[:len(d.pending)-1]
+		d.late += dropped
+		buffered -= dropped
+	}
+}
+
+// observeClockOffset updates the clock offset estimate with the delay between
+// capture and reception of a chunk. Network delay only adds to the observed
+// offset, so the smallest recent observation is the best estimate.
+func (d *ingestDevice) observeClockOffset(observed time.Duration) {
+	d.offsets = append(d.offsets, observed)
+	if len(d.offsets) > ingestOffsetWindow {
+		d.offsets = d.offsets[1:]
+	}
+	d.clockOffset = slices.Min(d.offsets)
+}
+
+// playout returns the audio of the device due at now, silence where audio is
+// missing. It returns nil before the first audio arrived and once the device
+// has been idle for ingestIdleTimeout.
+func (d *ingestDevice) playout(now time.Time, latency time.Duration) []byte {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.cursor == 0 {
+		if len(d.pending) == 0 {
+			return nil
+		}
+		d.cursor = d.pending[0].start
+		d.inGap = false
+	}
+	if len(d.pending) == 0 && now.Sub(d.lastReceived) > ingestIdleTimeout+latency {
+		// Start over at the next audio the device sends
+		d.cursor = 0
+		return nil
+	}
+
+	target := sampleIndex(now.Add(-latency))
+	if behind := target - d.cursor; behind > int64(ingestMaxBacklog.Seconds())*conf.SampleRate {
+		d.cursor = target - int64(ingestMaxBlock.Seconds())*conf.SampleRate
+	}
+	blockEnd := min(target, d.cursor+int64(ingestMaxBlock.Seconds())*conf.SampleRate)
+	if blockEnd <= d.cursor {
+		return nil
+	}
+
+	block := make([]byte, (blockEnd-d.cursor)*2)
+	pos := d.cursor
+	consumed := 0
+	for i := range d.pending {
+		chunk := &d.pending[i]
+		if chunk.start >= blockEnd {
+			break
+		}
+		if chunk.end() <= pos {
+			// Overlapped by audio already played
+			d.late += int64(len(chunk.pcm) / 2)
+			consumed = i + 1
+			continue
+		}
+		from := max(chunk.start, pos)
+		d.fillGap(pos, from)
+		to := min(chunk.end(), blockEnd)
+		copy(block[(from-d.cursor)*2:], chunk.pcm[(from-chunk.start)*2:(to-chunk.start)*2])
+		if from > chunk.start {
+			d.late += from - chunk.start
+		}
+		pos = to
+		d.inGap = false
+		if chunk.end() > blockEnd {
+			chunk.pcm = chunk.pcm[(blockEnd-chunk.start)*2:]
+			chunk.start = blockEnd
+			break
+		}
+		consumed = i + 1
+	}
+	d.fillGap(pos, blockEnd)
+	d.pending = d.pending[consumed:]
+	d.cursor = blockEnd
+	return block
+}
+
+// fillGap accounts the silence played between from and to.
+func (d *ingestDevice) fillGap(from, to int64) {
+	if to <= from {
+		return
+	}
+	if !d.inGap {
+		d.gaps++
+		d.inGap = true
+	}
+	d.silence += to - from
+}
+
+// status reports the state of the device.
+func (d *ingestDevice) status() IngestDeviceStatus {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	var buffered int64
+	for i := range d.pending {
+		buffered += int64(len(d.pending[i].pcm) / 2)
+	}
+	rate := float64(conf.SampleRate)
+	return IngestDeviceStatus{
+		ID:              d.id,
+		Name:            d.name,
+		SourceID:        d.sourceID,
+		Active:          d.cursor != 0,
+		LastReceived:    d.lastReceived,
+		ReceivedSeconds: float64(d.received) / rate,
+		BufferedSeconds: float64(buffered) / rate,
+		Gaps:            d.gaps,
+		GapSeconds:      float64(d.silence) / rate,
+		LateSeconds:     float64(d.late) / rate,
+		ClockOffsetMs:   d.clockOffset.Milliseconds(),
+	}
+}
+
+// sampleIndex returns the position of a time on the analysis-rate sample time line.
+func sampleIndex(t time.Time) int64 {
+	return t.Unix()*conf.SampleRate + int64(t.Nanosecond())*conf.SampleRate/int64(time.Second)
+}
+
+// samplesDuration returns the duration of a number of analysis-rate samples.
+func samplesDuration(samples int64) time.Duration {
+	return time.Duration(samples) * time.Second / conf.SampleRate
+}
@@ -0,0 +1,190 @@
+package myaudio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"os/exec"
+	"strconv"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/flac"
+)
+
+// Limits of pushed PCM audio
+const (
+	ingestMinSampleRate = 8000
+	ingestMaxSampleRate = 384000
+	ingestMaxChannels   = 8
+)
+
+// decodeIngestAudio decodes pushed audio and calls emit with consecutive
+// blocks of mono analysis-rate 16-bit PCM as they are decoded.
+func decodeIngestAudio(ctx context.Context, params IngestParams, body io.Reader, emit func(pcm []byte) error) error {
+	switch params.Format {
+	case IngestFormatPCM, "":
+		return decodeIngestPCM(body, params.SampleRate, params.Channels, emit)
+	case IngestFormatFLAC:
+		return decodeIngestFLAC(body, emit)
+	case IngestFormatOpus:
+		return decodeIngestOpus(ctx, body, emit)
+	default:
+		return errors.Newf("unsupported ingest format: %s", params.Format).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "decode_ingest_audio").
+			Context("supported_formats", "pcm,flac,opus").
+			Build()
+	}
+}
+
+// decodeIngestPCM reads raw signed 16-bit little-endian PCM in blocks of 100 ms.
+func decodeIngestPCM(body io.Reader, sampleRate, channels int, emit func([]byte) error) error {
+	if sampleRate < ingestMinSampleRate || sampleRate > ingestMaxSampleRate ||
+		channels < 1 || channels > ingestMaxChannels {
+		return errors.Newf("invalid PCM format: %d Hz, %d channels", sampleRate, channels).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "decode_ingest_pcm").
+			Build()
+	}
+
+	frameSize := channels * 2
+	buf := make([]byte, sampleRate/10*frameSize)
+	for {
+		n, err := io.ReadFull(body, buf)
+		if n -= n % frameSize; n > 0 {
+			if emitErr := emit(normalizeIngestPCM(buf[:n], sampleRate, channels)); emitErr != nil {
+				return emitErr
+			}
+		}
+		switch {
+		case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+			return nil
+		case err != nil:
+			return err
+		}
+	}
+}
+
+// decodeIngestFLAC decodes a FLAC stream frame by frame.
+func decodeIngestFLAC(body io.Reader, emit func([]byte) error) error {
+	decoder, err := flac.NewDecoder(body)
+	if err != nil {
+		return errors.New(err).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "decode_ingest_flac").
+			Build()
+	}
+	bytesPerSample := decoder.BitsPerSample / 8
+	if decoder.BitsPerSample%8 != 0 || bytesPerSample < 2 || bytesPerSample > 4 ||
+		decoder.NChannels < 1 || decoder.NChannels > ingestMaxChannels ||
+		decoder.SampleRate < ingestMinSampleRate || decoder.SampleRate > ingestMaxSampleRate {
+		return errors.Newf("unsupported FLAC format: %d Hz, %d bits, %d channels",
+			decoder.SampleRate, decoder.BitsPerSample, decoder.NChannels).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "decode_ingest_flac").
+			Build()
+	}
+
+	for {
+		frame, err := decoder.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		pcm := frame
+		if bytesPerSample != 2 {
+			// Keep the two most significant bytes of every sample
+			pcm = make([]byte, len(frame)/bytesPerSample*2)
+			for i := range len(pcm) / 2 {
+				copy(pcm[i*2:], frame[i*bytesPerSample+bytesPerSample-2:(i+1)*bytesPerSample])
+			}
+		}
+		if err := emit(normalizeIngestPCM(pcm, decoder.SampleRate, decoder.NChannels)); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeIngestOpus decodes Ogg Opus with FFmpeg, which outputs mono
+// analysis-rate PCM directly.
+func decodeIngestOpus(ctx context.Context, body io.Reader, emit func([]byte) error) error {
+	ffmpegPath := conf.Setting().Realtime.Audio.FfmpegPath
+	if err := validateFFmpegPath(ffmpegPath); err != nil {
+		return errors.New(err).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "decode_ingest_opus").
+			Build()
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, ffmpegPath, //nolint:gosec // G204: ffmpegPath from validated settings, args are fixed
+		"-hide_banner", "-loglevel", "error",
+		"-i", "pipe:0",
+		"-f", "s16le", "-ac", "1", "-ar", strconv.Itoa(conf.SampleRate),
+		"pipe:1")
+	cmd.Stdin = body
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+
+	readErr := decodeIngestPCM(stdout, conf.SampleRate, 1, emit)
+	if readErr != nil {
+		// Unblock FFmpeg before waiting for it
+		_, _ = io.Copy(io.Discard, stdout)
+	}
+	if err := cmd.Wait(); err != nil && readErr == nil {
+		return errors.Newf("opus decoding failed: %s", bytes.TrimSpace(stderr.Bytes())).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "decode_ingest_opus").
+			Build()
+	}
+	return readErr
+}
+
+// normalizeIngestPCM converts interleaved 16-bit PCM to mono at the analysis sample rate.
+func normalizeIngestPCM(pcm []byte, sampleRate, channels int) []byte {
+	if channels > 1 {
+		pcm = downmixChannels(pcm, channels)
+	}
+	if sampleRate == conf.SampleRate {
+		// Decoders reuse their buffers
+		return bytes.Clone(pcm)
+	}
+
+	samples := make([]float32, len(pcm)/2)
+	for i := range samples {
+		samples[i] = float32(int16(binary.LittleEndian.Uint16(pcm[i*2:]))) / 32768 //nolint:gosec // G115: 16-bit PCM sample
+	}
+	var resampled []float32
+	if len(samples) >= 4 {
+		resampled, _ = ResampleAudio(samples, sampleRate, conf.SampleRate)
+	} else {
+		// Too short for cubic interpolation, repeat the nearest sample
+		resampled = make([]float32, len(samples)*conf.SampleRate/sampleRate)
+		for i := range resampled {
+			resampled[i] = samples[i*sampleRate/conf.SampleRate]
+		}
+	}
+
+	out := make([]byte, len(resampled)*2)
+	for i, s := range resampled {
+		s = max(-1, min(s, 32767.0/32768.0))
+		binary.LittleEndian.PutUint16(out[i*2:], uint16(int16(s*32768))) //nolint:gosec // G115: clamped to 16-bit range
+	}
+	return out
+}
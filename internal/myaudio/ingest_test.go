@@ -0,0 +1,172 @@
+package myaudio
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// ingestTone returns mono analysis-rate PCM of the given duration holding a constant value
+func ingestTone(d time.Duration, value int16) []byte {
+	pcm := make([]byte, int64(d.Seconds()*conf.SampleRate)*2)
+	for i := 0; i < len(pcm); i += 2 {
+		binary.LittleEndian.PutUint16(pcm[i:], uint16(value)) //nolint:gosec // G115: test sample
+	}
+	return pcm
+}
+
+// ingestSample returns the sample of mono PCM at an offset
+func ingestSample(pcm []byte, at time.Duration) int16 {
+	i := int(at.Seconds()*conf.SampleRate) * 2
+	return int16(binary.LittleEndian.Uint16(pcm[i:])) //nolint:gosec // G115: test sample
+}
+
+func TestIngestDevice_PlayoutOrdersChunksAndFillsGaps(t *testing.T) {
+	t.Parallel()
+
+	base := time.Unix(1_800_000_000, 0)
+	d := &ingestDevice{id: "meadow"}
+
+	// Pushed out of order with a gap from 1s to 2s
+	d.push(base.Add(2*time.Second), ingestTone(time.Second, 200), base.Add(3*time.Second))
+	d.push(base, ingestTone(time.Second, 100), base.Add(time.Second))
+
+	first := d.playout(base.Add(time.Second), 0)
+	require.Len(t, first, conf.SampleRate*2)
+	assert.Equal(t, int16(100), ingestSample(first, 0))
+	assert.Equal(t, int16(100), ingestSample(first, 999*time.Millisecond))
+
+	// Catching up plays out at most ingestMaxBlock per tick
+	gap := d.playout(base.Add(3*time.Second), 0)
+	require.Len(t, gap, conf.SampleRate*2)
+	assert.Equal(t, int16(0), ingestSample(gap, 500*time.Millisecond))
+
+	second := d.playout(base.Add(3*time.Second), 0)
+	require.Len(t, second, conf.SampleRate*2)
+	assert.Equal(t, int16(200), ingestSample(second, 0))
+	assert.Equal(t, int16(200), ingestSample(second, 999*time.Millisecond))
+
+	status := d.status()
+	assert.Equal(t, int64(1), status.Gaps)
+	assert.InDelta(t, 1.0, status.GapSeconds, 0.001)
+	assert.InDelta(t, 2.0, status.ReceivedSeconds, 0.001)
+	assert.Zero(t, status.BufferedSeconds)
+}
+
+func TestIngestDevice_PlayoutWaitsForLatency(t *testing.T) {
+	t.Parallel()
+
+	base := time.Unix(1_800_000_000, 0)
+	d := &ingestDevice{id: "meadow"}
+	d.push(base, ingestTone(time.Second, 100), base.Add(time.Second))
+
+	assert.Nil(t, d.playout(base.Add(time.Second), 5*time.Second))
+	block := d.playout(base.Add(5500*time.Millisecond), 5*time.Second)
+	assert.Len(t, block, int(0.5*conf.SampleRate)*2)
+}
+
+func TestIngestDevice_DropsLateAudio(t *testing.T) {
+	t.Parallel()
+
+	base := time.Unix(1_800_000_000, 0)
+	d := &ingestDevice{id: "meadow"}
+	d.push(base, ingestTone(time.Second, 100), base.Add(time.Second))
+	d.playout(base.Add(time.Second), 0)
+	d.playout(base.Add(2*time.Second), 0)
+
+	// Arrives after its playout time, entirely behind the cursor
+	d.push(base.Add(time.Second), ingestTone(500*time.Millisecond, 300), base.Add(1500*time.Millisecond))
+	// Straddles the cursor, only the part ahead of it is kept
+	d.push(base.Add(1500*time.Millisecond), ingestTone(time.Second, 400), base.Add(2500*time.Millisecond))
+
+	status := d.status()
+	assert.InDelta(t, 1.0, status.LateSeconds, 0.001)
+	assert.InDelta(t, 0.5, status.BufferedSeconds, 0.001)
+
+	block := d.playout(base.Add(2500*time.Millisecond), 0)
+	require.Len(t, block, int(0.5*conf.SampleRate)*2)
+	assert.Equal(t, int16(400), ingestSample(block, 0))
+}
+
+func TestIngestDevice_CorrectsClockOffset(t *testing.T) {
+	t.Parallel()
+
+	base := time.Unix(1_800_000_000, 0)
+	d := &ingestDevice{id: "meadow"}
+
+	// The device clock runs 5 s behind, network delay varies
+	d.push(base.Add(-5*time.Second), ingestTone(time.Second, 100), base.Add(1300*time.Millisecond))
+	d.push(base.Add(-4*time.Second), ingestTone(time.Second, 100), base.Add(2*time.Second))
+	assert.Equal(t, int64(5000), d.status().ClockOffsetMs)
+
+	// Playout starts at the corrected capture time of the first chunk
+	block := d.playout(base.Add(time.Second), 0)
+	require.NotNil(t, block)
+	assert.Len(t, block, int(0.7*conf.SampleRate)*2)
+}
+
+func TestIngestDevice_IdleResetsPlayout(t *testing.T) {
+	t.Parallel()
+
+	base := time.Unix(1_800_000_000, 0)
+	d := &ingestDevice{id: "meadow"}
+	d.push(base, ingestTone(time.Second, 100), base.Add(time.Second))
+	d.playout(base.Add(time.Second), 0)
+
+	idle := base.Add(time.Second + ingestIdleTimeout + time.Second)
+	assert.Nil(t, d.playout(idle, 0))
+	assert.False(t, d.status().Active)
+}
+
+func TestDecodeIngestAudio_PCMNormalized(t *testing.T) {
+	t.Parallel()
+
+	// 250 ms of 16 kHz stereo
+	stereo := make([]byte, 4000*4)
+	for f := range 4000 {
+		binary.LittleEndian.PutUint16(stereo[f*4:], uint16(1000))
+		binary.LittleEndian.PutUint16(stereo[f*4+2:], uint16(3000))
+	}
+
+	var out []byte
+	params := IngestParams{Format: IngestFormatPCM, SampleRate: 16000, Channels: 2}
+	err := decodeIngestAudio(context.Background(), params, bytes.NewReader(stereo), func(pcm []byte) error {
+		out = append(out, pcm...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, out, int(0.25*conf.SampleRate)*2)
+	assert.InDelta(t, 2000, ingestSample(out, 100*time.Millisecond), 2)
+}
+
+func TestDecodeIngestAudio_FLAC(t *testing.T) {
+	t.Parallel()
+
+	encoded, err := EncodeInterleavedPCMtoFLAC(ingestTone(time.Second, 500), 1)
+	require.NoError(t, err)
+
+	var out []byte
+	err = decodeIngestAudio(context.Background(), IngestParams{Format: IngestFormatFLAC}, encoded, func(pcm []byte) error {
+		out = append(out, pcm...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Len(t, out, conf.SampleRate*2)
+	assert.Equal(t, int16(500), ingestSample(out, 500*time.Millisecond))
+}
+
+func TestDecodeIngestAudio_Invalid(t *testing.T) {
+	t.Parallel()
+
+	emit := func([]byte) error { return nil }
+	ctx := context.Background()
+	require.Error(t, decodeIngestAudio(ctx, IngestParams{Format: "mp3"}, bytes.NewReader(nil), emit))
+	require.Error(t, decodeIngestAudio(ctx, IngestParams{Format: IngestFormatPCM, SampleRate: 100, Channels: 1}, bytes.NewReader(nil), emit))
+	require.Error(t, decodeIngestAudio(ctx, IngestParams{Format: IngestFormatFLAC}, bytes.NewReader([]byte("not flac")), emit))
+}
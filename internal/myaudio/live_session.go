@@ -0,0 +1,276 @@
+package myaudio
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// Live sessions stream the microphone of a browser or phone as a temporary
+// audio source. Audio arrives in real time and goes straight into the analysis
+// and capture buffers of the session source, detections carry its source ID
+// and display name. The buffers outlive the session briefly so clips of the
+// last detections can still be saved.
+
+const (
+	liveConnectionPrefix = "browser://"
+
+	// MaxLiveSessions bounds the number of concurrent browser sessions
+	MaxLiveSessions = 4
+
+	liveSessionLinger = time.Minute // buffers are kept this long after a session ends
+)
+
+// ErrTooManyLiveSessions is returned when MaxLiveSessions are already streaming.
+var ErrTooManyLiveSessions = errors.Newf("too many live sessions, at most %d may stream at once", MaxLiveSessions).
+	Component("myaudio").
+	Category(errors.CategoryLimit).
+	Build()
+
+// LiveSessionInfo describes a running live session.
+type LiveSessionInfo struct {
+	ID              string    `json:"id"`
+	SourceID        string    `json:"source_id"`
+	Name            string    `json:"name"`
+	User            string    `json:"user,omitempty"`
+	SampleRate      int       `json:"sample_rate"`
+	Started         time.Time `json:"started"`
+	ReceivedSeconds float64   `json:"received_seconds"`
+}
+
+// LiveSession is a browser microphone streaming into the analysis pipeline.
+type LiveSession struct {
+	id         string
+	sourceID   string
+	name       string
+	user       string
+	sampleRate int
+	started    time.Time
+
+	mu       sync.Mutex
+	received int64 // analysis-rate samples
+	closed   bool
+}
+
+// Running live sessions by ID
+var (
+	liveSessions   = make(map[string]*LiveSession)
+	liveSessionsMu sync.Mutex
+)
+
+// StartLiveSession registers a temporary source for a browser microphone
+// recording mono 16-bit PCM at sampleRate. The name labels the session source,
+// user records who started it.
+func StartLiveSession(name, user string, sampleRate int) (*LiveSession, error) {
+	if sampleRate < ingestMinSampleRate || sampleRate > ingestMaxSampleRate {
+		return nil, errors.Newf("unsupported sample rate: %d Hz", sampleRate).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "start_live_session").
+			Build()
+	}
+
+	name = strings.TrimSpace(name)
+	if len(name) > conf.MaxStreamNameLength {
+		return nil, errors.Newf("session name must be %d characters or less", conf.MaxStreamNameLength).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "start_live_session").
+			Build()
+	}
+	if name == "" {
+		name = "Field Session " + time.Now().Format("2006-01-02 15:04")
+	}
+
+	id, err := newLiveSessionID()
+	if err != nil {
+		return nil, err
+	}
+
+	liveSessionsMu.Lock()
+	if len(liveSessions) >= MaxLiveSessions {
+		liveSessionsMu.Unlock()
+		return nil, ErrTooManyLiveSessions
+	}
+	session := &LiveSession{id: id, name: name, user: user, sampleRate: sampleRate, started: time.Now()}
+	liveSessions[id] = session
+	liveSessionsMu.Unlock()
+
+	source, err := GetRegistry().RegisterSource(liveConnectionPrefix+id, SourceConfig{
+		DisplayName: name,
+		Type:        SourceTypeBrowser,
+	})
+	if err == nil {
+		err = initializeBuffersForSource(source.ID)
+	}
+	if err != nil {
+		liveSessionsMu.Lock()
+		delete(liveSessions, id)
+		liveSessionsMu.Unlock()
+		return nil, errors.New(err).
+			Component("myaudio").
+			Category(errors.CategoryAudioSource).
+			Context("operation", "start_live_session").
+			Build()
+	}
+	if err := RegisterSoundLevelProcessor(source.ID, name); err != nil {
+		GetLogger().Warn("failed to register sound level processor for live session",
+			logger.String("session_id", id),
+			logger.Error(err))
+	}
+
+	session.mu.Lock()
+	session.sourceID = source.ID
+	session.mu.Unlock()
+
+	GetLogger().Info("live session started",
+		logger.String("session_id", id),
+		logger.String("source_id", source.ID),
+		logger.String("name", name),
+		logger.Int("sample_rate", sampleRate))
+	return session, nil
+}
+
+// releaseLiveSessionSource removes the buffers, per-source state and registry
+// entry of an ended session.
+func releaseLiveSessionSource(sourceID string) {
+	UnregisterSoundLevelProcessor(sourceID)
+	ResetMicHealth(sourceID)
+	RemoveSourceFilterChain(sourceID)
+	ResetNoiseReduction(sourceID)
+	if err := RemoveAnalysisBuffer(sourceID); err != nil {
+		GetLogger().Warn("failed to remove live session analysis buffer",
+			logger.String("source_id", sourceID),
+			logger.Error(err))
+	}
+	if err := RemoveCaptureBuffer(sourceID); err != nil {
+		GetLogger().Warn("failed to remove live session capture buffer",
+			logger.String("source_id", sourceID),
+			logger.Error(err))
+	}
+	if err := GetRegistry().RemoveSource(sourceID); err != nil {
+		GetLogger().Warn("failed to remove live session source",
+			logger.String("source_id", sourceID),
+			logger.Error(err))
+	}
+}
+
+// Write feeds mono 16-bit little-endian PCM at the session sample rate into
+// the analysis pipeline.
+func (s *LiveSession) Write(pcm []byte) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return errors.Newf("live session closed").
+			Component("myaudio").
+			Category(errors.CategoryState).
+			Context("operation", "write_live_session").
+			Build()
+	}
+	s.mu.Unlock()
+
+	data := normalizeIngestPCM(pcm[:len(pcm)-len(pcm)%2], s.sampleRate, 1)
+	if len(data) == 0 {
+		return nil
+	}
+	analyzeDeviceAudio(s.sourceID, data)
+	if err := WriteToCaptureBuffer(s.sourceID, data); err != nil {
+		GetLogger().Warn("error writing live session audio to capture buffer",
+			logger.String("session_id", s.id),
+			logger.Error(err))
+	}
+	publishDeviceAudio(s.sourceID, s.name, data, nil)
+
+	s.mu.Lock()
+	s.received += int64(len(data) / 2)
+	s.mu.Unlock()
+	return nil
+}
+
+// Close ends the session. Its buffers are released after liveSessionLinger.
+func (s *LiveSession) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	received := s.received
+	s.mu.Unlock()
+
+	liveSessionsMu.Lock()
+	delete(liveSessions, s.id)
+	liveSessionsMu.Unlock()
+
+	sourceID := s.sourceID
+	time.AfterFunc(liveSessionLinger, func() { releaseLiveSessionSource(sourceID) })
+
+	GetLogger().Info("live session ended",
+		logger.String("session_id", s.id),
+		logger.String("source_id", sourceID),
+		logger.Float64("received_seconds", float64(received)/conf.SampleRate))
+}
+
+// Info describes the session.
+func (s *LiveSession) Info() LiveSessionInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return LiveSessionInfo{
+		ID:              s.id,
+		SourceID:        s.sourceID,
+		Name:            s.name,
+		User:            s.user,
+		SampleRate:      s.sampleRate,
+		Started:         s.started,
+		ReceivedSeconds: float64(s.received) / conf.SampleRate,
+	}
+}
+
+// ListLiveSessions describes the running live sessions, oldest first.
+func ListLiveSessions() []LiveSessionInfo {
+	liveSessionsMu.Lock()
+	sessions := make([]*LiveSession, 0, len(liveSessions))
+	for _, session := range liveSessions {
+		sessions = append(sessions, session)
+	}
+	liveSessionsMu.Unlock()
+
+	infos := make([]LiveSessionInfo, 0, len(sessions))
+	for _, session := range sessions {
+		infos = append(infos, session.Info())
+	}
+	slices.SortFunc(infos, func(a, b LiveSessionInfo) int { return a.Started.Compare(b.Started) })
+	return infos
+}
+
+// LiveSessionSourceIDs returns the source IDs of the running live sessions.
+func LiveSessionSourceIDs() []string {
+	infos := ListLiveSessions()
+	ids := make([]string, 0, len(infos))
+	for i := range infos {
+		if infos[i].SourceID != "" {
+			ids = append(ids, infos[i].SourceID)
+		}
+	}
+	return ids
+}
+
+// newLiveSessionID returns a random session identifier.
+func newLiveSessionID() (string, error) {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.New(err).
+			Component("myaudio").
+			Category(errors.CategorySystem).
+			Context("operation", "new_live_session_id").
+			Build()
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,78 @@
+package myaudio
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// NOTE: Do NOT use t.Parallel() - these tests modify the global live sessions and source registry
+
+// startLiveTestSession starts a live session and releases its source after the test
+func startLiveTestSession(t *testing.T, name string) *LiveSession {
+	t.Helper()
+	session, err := StartLiveSession(name, "walker", 16000)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		session.Close()
+		sourceID := session.Info().SourceID
+		_ = RemoveAnalysisBuffer(sourceID)
+		_ = RemoveCaptureBuffer(sourceID)
+		_ = GetRegistry().RemoveSource(sourceID)
+	})
+	return session
+}
+
+func TestLiveSession_StreamsIntoSource(t *testing.T) {
+	session := startLiveTestSession(t, "Morning walk")
+	info := session.Info()
+
+	source, exists := GetRegistry().GetSourceByID(info.SourceID)
+	require.True(t, exists)
+	assert.Equal(t, SourceTypeBrowser, source.Type)
+	assert.Equal(t, "Morning walk", source.DisplayName)
+	assert.Contains(t, LiveSessionSourceIDs(), info.SourceID)
+
+	// 500 ms at 16 kHz is resampled to the analysis rate
+	require.NoError(t, session.Write(make([]byte, 8000*2)))
+	assert.InDelta(t, 0.5, session.Info().ReceivedSeconds, 0.001)
+
+	session.Close()
+	assert.NotContains(t, LiveSessionSourceIDs(), info.SourceID)
+	require.Error(t, session.Write(make([]byte, 320)))
+}
+
+func TestLiveSession_DefaultName(t *testing.T) {
+	session := startLiveTestSession(t, "  ")
+	assert.Contains(t, session.Info().Name, "Field Session "+time.Now().Format("2006-01-02"))
+}
+
+func TestLiveSession_ReleaseRemovesSource(t *testing.T) {
+	session := startLiveTestSession(t, "Evening walk")
+	sourceID := session.Info().SourceID
+
+	session.Close()
+	releaseLiveSessionSource(sourceID)
+
+	_, exists := GetRegistry().GetSourceByID(sourceID)
+	assert.False(t, exists, "the browser source is unregistered after the linger")
+	_, exists = GetRegistry().GetSourceByConnection("browser://" + session.Info().ID)
+	assert.False(t, exists)
+}
+
+func TestStartLiveSession_Limits(t *testing.T) {
+	_, err := StartLiveSession("", "", 100)
+	require.Error(t, err)
+	_, err = StartLiveSession(strings.Repeat("n", conf.MaxStreamNameLength+1), "", 48000)
+	require.Error(t, err, "names are bounded like stream names")
+
+	for range MaxLiveSessions {
+		startLiveTestSession(t, "")
+	}
+	_, err = StartLiveSession("", "", 48000)
+	require.ErrorIs(t, err, ErrTooManyLiveSessions)
+}
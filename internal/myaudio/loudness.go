@@ -0,0 +1,178 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Loudness measurement follows ITU-R BS.1770-4 / EBU R128: K-weighted mean
+// square over 400 ms blocks with 75% overlap, an absolute gate at -70 LUFS and
+// a relative gate 10 LU below the ungated loudness. Every channel is weighted
+// equally, microphone channels have no surround positions.
+
+const (
+	loudnessBlockSeconds = 0.4
+	loudnessBlockStep    = 0.1
+	loudnessAbsoluteGate = -70.0
+	loudnessRelativeGate = -10.0
+)
+
+// biquad is a direct form I second order IIR filter
+type biquad struct {
+	b0, b1, b2, a1, a2 float64
+	x1, x2, y1, y2     float64
+}
+
+func (f *biquad) process(x float64) float64 {
+	y := f.b0*x + f.b1*f.x1 + f.b2*f.x2 - f.a1*f.y1 - f.a2*f.y2
+	f.x2, f.x1 = f.x1, x
+	f.y2, f.y1 = f.y1, y
+	return y
+}
+
+// kWeightingFilters returns the BS.1770 pre-filter (high shelf) and RLB
+// high-pass for a sample rate, derived as in libebur128.
+func kWeightingFilters(sampleRate float64) (shelf, highPass biquad) {
+	f0, gain, q := 1681.974450955533, 3.999843853973347, 0.7071752369554196
+	k := math.Tan(math.Pi * f0 / sampleRate)
+	vh := math.Pow(10, gain/20)
+	vb := math.Pow(vh, 0.4996667741545416)
+	a0 := 1 + k/q + k*k
+	shelf = biquad{
+		b0: (vh + vb*k/q + k*k) / a0,
+		b1: 2 * (k*k - vh) / a0,
+		b2: (vh - vb*k/q + k*k) / a0,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+
+	f0, q = 38.13547087602444, 0.5003270373238773
+	k = math.Tan(math.Pi * f0 / sampleRate)
+	a0 = 1 + k/q + k*k
+	highPass = biquad{
+		b0: 1, b1: -2, b2: 1,
+		a1: 2 * (k*k - 1) / a0,
+		a2: (1 - k/q + k*k) / a0,
+	}
+	return shelf, highPass
+}
+
+// MeasureIntegratedLoudness returns the gated integrated loudness in LUFS of
+// interleaved 16-bit PCM. It returns false for audio shorter than one
+// measurement block or entirely below the absolute gate.
+func MeasureIntegratedLoudness(pcmData []byte, channels int) (float64, bool) {
+	channels = max(channels, 1)
+	frames := len(pcmData) / (2 * channels)
+	blockFrames := int(loudnessBlockSeconds * conf.SampleRate)
+	stepFrames := int(loudnessBlockStep * conf.SampleRate)
+	if frames < blockFrames {
+		return 0, false
+	}
+
+	// K-weighted energy per 100 ms step, summed over channels
+	steps := make([]float64, frames/stepFrames)
+	for ch := range channels {
+		shelf, highPass := kWeightingFilters(conf.SampleRate)
+		for f := range len(steps) * stepFrames {
+			sample := float64(int16(binary.LittleEndian.Uint16(pcmData[(f*channels+ch)*2:]))) / 32768.0 //nolint:gosec // G115: 16-bit PCM sample
+			y := highPass.process(shelf.process(sample))
+			steps[f/stepFrames] += y * y
+		}
+	}
+
+	// 400 ms blocks of four overlapping steps
+	stepsPerBlock := blockFrames / stepFrames
+	blocks := make([]float64, 0, len(steps))
+	for i := 0; i+stepsPerBlock <= len(steps); i++ {
+		energy := 0.0
+		for _, e := range steps[i : i+stepsPerBlock] {
+			energy += e
+		}
+		blocks = append(blocks, energy/float64(blockFrames))
+	}
+
+	gated := func(threshold float64) (float64, int) {
+		sum, n := 0.0, 0
+		for _, e := range blocks {
+			if energyToLUFS(e) > threshold {
+				sum += e
+				n++
+			}
+		}
+		return sum, n
+	}
+	sum, n := gated(loudnessAbsoluteGate)
+	if n == 0 {
+		return 0, false
+	}
+	relative := energyToLUFS(sum/float64(n)) + loudnessRelativeGate
+	sum, n = gated(max(relative, loudnessAbsoluteGate))
+	if n == 0 {
+		return 0, false
+	}
+	return energyToLUFS(sum / float64(n)), true
+}
+
+// energyToLUFS converts a K-weighted mean square to LUFS.
+func energyToLUFS(energy float64) float64 {
+	if energy <= 0 {
+		return math.Inf(-1)
+	}
+	return -0.691 + 10*math.Log10(energy)
+}
+
+// samplePeakDBFS returns the absolute sample peak of 16-bit PCM in dBFS.
+func samplePeakDBFS(pcmData []byte) float64 {
+	peak := 0
+	for i := 0; i+1 < len(pcmData); i += 2 {
+		sample := int(int16(binary.LittleEndian.Uint16(pcmData[i:]))) //nolint:gosec // G115: 16-bit PCM sample
+		peak = max(peak, sample, -sample)
+	}
+	if peak == 0 {
+		return math.Inf(-1)
+	}
+	return 20 * math.Log10(float64(peak)/32768.0)
+}
+
+// ApplyGain returns a copy of 16-bit PCM amplified by gainDB, clipping at full scale.
+func ApplyGain(pcmData []byte, gainDB float64) []byte {
+	out := make([]byte, len(pcmData)/2*2)
+	factor := math.Pow(10, gainDB/20)
+	for i := 0; i+1 < len(pcmData); i += 2 {
+		sample := float64(int16(binary.LittleEndian.Uint16(pcmData[i:]))) * factor //nolint:gosec // G115: 16-bit PCM sample
+		sample = math.Round(max(min(sample, math.MaxInt16), math.MinInt16))
+		binary.LittleEndian.PutUint16(out[i:], uint16(int16(sample))) //nolint:gosec // G115: clamped to the int16 range
+	}
+	return out
+}
+
+// NormalizationGain returns the gain in dB that brings interleaved PCM to
+// targetLUFS without pushing the sample peak above peakLimitDB. It returns
+// false when the loudness cannot be measured.
+func NormalizationGain(pcmData []byte, channels int, targetLUFS, peakLimitDB float64) (float64, bool) {
+	loudness, ok := MeasureIntegratedLoudness(pcmData, channels)
+	if !ok {
+		return 0, false
+	}
+	gain := targetLUFS - loudness
+	if peak := samplePeakDBFS(pcmData); !math.IsInf(peak, -1) {
+		gain = min(gain, peakLimitDB-peak)
+	}
+	return gain, true
+}
+
+// exportGain returns the gain in dB the export settings apply to a clip.
+// Normalization takes precedence over the fixed gain, as in the ffmpeg export.
+func exportGain(pcmData []byte, channels int, settings *conf.AudioSettings) float64 {
+	if settings.Export.Normalization.Enabled {
+		gain, ok := NormalizationGain(pcmData, channels,
+			settings.Export.Normalization.TargetLUFS, settings.Export.Normalization.TruePeak)
+		if !ok {
+			return 0
+		}
+		return gain
+	}
+	return settings.Export.Gain
+}
@@ -0,0 +1,82 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// sinePCM returns mono PCM of a sine wave with the given peak level in dBFS
+func sinePCM(seconds, frequency, levelDB float64) []byte {
+	frames := int(seconds * conf.SampleRate)
+	amplitude := 32767 * math.Pow(10, levelDB/20)
+	pcm := make([]byte, frames*2)
+	for i := range frames {
+		value := amplitude * math.Sin(2*math.Pi*frequency*float64(i)/conf.SampleRate)
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(int16(value))) //nolint:gosec // G115: bounded test sample
+	}
+	return pcm
+}
+
+func TestMeasureIntegratedLoudness(t *testing.T) {
+	t.Parallel()
+
+	// A 997 Hz sine with a -20 dBFS peak measures -23 LUFS per BS.1770
+	loudness, ok := MeasureIntegratedLoudness(sinePCM(3, 997, -20), 1)
+	require.True(t, ok)
+	assert.InDelta(t, -23.0, loudness, 0.1)
+
+	// Identical channels add their energy
+	mono := sinePCM(3, 997, -20)
+	stereo := make([]byte, 0, len(mono)*2)
+	for i := 0; i < len(mono); i += 2 {
+		stereo = append(stereo, mono[i], mono[i+1], mono[i], mono[i+1])
+	}
+	stereoLoudness, ok := MeasureIntegratedLoudness(stereo, 2)
+	require.True(t, ok)
+	assert.InDelta(t, loudness+3.01, stereoLoudness, 0.1)
+
+	_, ok = MeasureIntegratedLoudness(make([]byte, conf.SampleRate*2), 1)
+	assert.False(t, ok, "silence is below the absolute gate")
+	_, ok = MeasureIntegratedLoudness(sinePCM(0.2, 997, -20), 1)
+	assert.False(t, ok, "audio shorter than a block cannot be measured")
+}
+
+func TestNormalizationGain_RespectsPeakLimit(t *testing.T) {
+	t.Parallel()
+
+	pcm := sinePCM(2, 997, -20)
+	gain, ok := NormalizationGain(pcm, 1, -23, -1)
+	require.True(t, ok)
+	assert.InDelta(t, 0, gain, 0.1)
+
+	gain, ok = NormalizationGain(pcm, 1, -5, -6)
+	require.True(t, ok)
+	assert.InDelta(t, 14, gain, 0.1, "the gain stops at the peak limit")
+}
+
+func TestExportInterleavedAudioToFLAC_AppliesGain(t *testing.T) {
+	t.Parallel()
+
+	outputPath := filepath.Join(t.TempDir(), "clips", "clip.flac")
+	settings := &conf.AudioSettings{}
+	settings.Export.Gain = 6
+	pcm := sinePCM(1, 1000, -20)
+
+	require.NoError(t, ExportInterleavedAudioToFLAC(pcm, outputPath, settings, 1, nil))
+	assert.NoFileExists(t, outputPath+TempExt, "the temporary file is renamed")
+
+	f, err := os.Open(outputPath) //nolint:gosec // G304: test temp file
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	decoded, _ := decodeFLAC(t, f)
+	assert.InDelta(t, samplePeakDBFS(pcm)+6, samplePeakDBFS(decoded), 0.05)
+
+	require.Error(t, ExportInterleavedAudioToFLAC(nil, outputPath, settings, 1, nil))
+}
@@ -0,0 +1,320 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// Exported clips carry their detection as standard metadata so they stay
+// self-describing outside the database: Vorbis comments in FLAC and Opus, ID3
+// tags in MP3 (both written by ffmpeg from the same fields), and LIST/INFO,
+// BWF bext and GUANO chunks in WAV.
+
+const (
+	metadataSoftware  = "BirdNET-Go"
+	guanoNamespace    = "BirdNET-Go"
+	bextFixedLength   = 602
+	bextVersion       = 1
+	bextDescLength    = 256
+	bextOrigLength    = 32
+	bextOrigRefLength = 32
+)
+
+// ClipMetadata describes the detection an exported clip belongs to.
+type ClipMetadata struct {
+	CommonName     string
+	ScientificName string
+	SpeciesCode    string
+	Confidence     float64   // 0.0-1.0
+	Timestamp      time.Time // detection time, keeps its time zone
+	RecordedAt     time.Time // capture time of the first sample, zero when unknown
+	Latitude       float64
+	Longitude      float64
+	SourceName     string
+	ModelName      string
+	ModelVersion   string
+	AppVersion     string
+}
+
+// metadataField is a tag written to an exported clip.
+type metadataField struct {
+	key   string
+	value string
+}
+
+// title returns the species title of the clip.
+func (m *ClipMetadata) title() string {
+	switch {
+	case m.CommonName != "" && m.ScientificName != "":
+		return fmt.Sprintf("%s (%s)", m.CommonName, m.ScientificName)
+	case m.CommonName != "":
+		return m.CommonName
+	default:
+		return m.ScientificName
+	}
+}
+
+// description returns a one sentence summary of the detection.
+func (m *ClipMetadata) description() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s detected with %.0f%% confidence", m.title(), m.Confidence*100)
+	if model := m.model(); model != "" {
+		fmt.Fprintf(&b, " by %s", model)
+	}
+	if m.SourceName != "" {
+		fmt.Fprintf(&b, " on %s", m.SourceName)
+	}
+	return b.String()
+}
+
+// model returns the model name and version.
+func (m *ClipMetadata) model() string {
+	return strings.TrimSpace(m.ModelName + " " + m.ModelVersion)
+}
+
+// software returns the application name and version.
+func (m *ClipMetadata) software() string {
+	return strings.TrimSpace(metadataSoftware + " " + m.AppVersion)
+}
+
+// hasLocation reports whether the clip has coordinates, 0,0 means unset.
+func (m *ClipMetadata) hasLocation() bool {
+	return m.Latitude != 0 || m.Longitude != 0
+}
+
+// recordedAt returns the capture time of the first sample, falling back to the detection time.
+func (m *ClipMetadata) recordedAt() time.Time {
+	if m.RecordedAt.IsZero() {
+		return m.Timestamp
+	}
+	return m.RecordedAt
+}
+
+// fields returns the tags of the clip in Vorbis comment naming, empty values omitted.
+func (m *ClipMetadata) fields() []metadataField {
+	fields := []metadataField{
+		{"TITLE", m.title()},
+		{"ARTIST", m.SourceName},
+		{"DATE", formatMetadataTime(m.Timestamp)},
+		{"COMMENT", m.description()},
+		{"ENCODED_BY", m.software()},
+		{"SPECIES", m.CommonName},
+		{"SCIENTIFIC_NAME", m.ScientificName},
+		{"SPECIES_CODE", m.SpeciesCode},
+		{"CONFIDENCE", strconv.FormatFloat(m.Confidence, 'f', 4, 64)},
+		{"SOURCE", m.SourceName},
+		{"MODEL", m.ModelName},
+		{"MODEL_VERSION", m.ModelVersion},
+		{"BIRDNET_GO_VERSION", m.AppVersion},
+	}
+	if m.hasLocation() {
+		fields = append(fields,
+			metadataField{"LATITUDE", formatCoordinate(m.Latitude)},
+			metadataField{"LONGITUDE", formatCoordinate(m.Longitude)})
+	}
+
+	nonEmpty := fields[:0]
+	for _, f := range fields {
+		if f.value = sanitizeMetadataValue(f.value); f.value != "" {
+			nonEmpty = append(nonEmpty, f)
+		}
+	}
+	return nonEmpty
+}
+
+// VorbisComments returns the clip tags as KEY=value Vorbis comments.
+func (m *ClipMetadata) VorbisComments() []string {
+	if m == nil {
+		return nil
+	}
+	fields := m.fields()
+	comments := make([]string, 0, len(fields))
+	for _, f := range fields {
+		comments = append(comments, f.key+"="+f.value)
+	}
+	return comments
+}
+
+// ffmpegMetadataArgs returns the ffmpeg arguments that tag an export. ffmpeg
+// maps the generic keys to ID3 frames for MP3 and writes Vorbis comments for Opus.
+func (m *ClipMetadata) ffmpegMetadataArgs(format string) []string {
+	if m == nil {
+		return nil
+	}
+	fields := m.fields()
+	args := make([]string, 0, 2*len(fields)+2)
+	for _, f := range fields {
+		args = append(args, "-metadata", strings.ToLower(f.key)+"="+f.value)
+	}
+	if format == FormatMP3 {
+		// ID3v2.3 is the most widely supported tag version
+		args = append(args, "-id3v2_version", "3")
+	}
+	return args
+}
+
+// guano returns the GUANO metadata text of a WAV clip.
+func (m *ClipMetadata) guano(frames int) string {
+	lines := []string{
+		"GUANO|Version: 1.0",
+		"Timestamp: " + formatMetadataTime(m.recordedAt()),
+		"Length: " + strconv.FormatFloat(float64(frames)/conf.SampleRate, 'f', 3, 64),
+		"Samplerate: " + strconv.Itoa(conf.SampleRate),
+		"Make: " + metadataSoftware,
+		"Firmware Version: " + m.AppVersion,
+		"Species Auto ID: " + m.ScientificName,
+		"Note: " + m.description(),
+		guanoNamespace + "|Common Name: " + m.CommonName,
+		guanoNamespace + "|Species Code: " + m.SpeciesCode,
+		guanoNamespace + "|Confidence: " + strconv.FormatFloat(m.Confidence, 'f', 4, 64),
+		guanoNamespace + "|Detection Time: " + formatMetadataTime(m.Timestamp),
+		guanoNamespace + "|Source: " + m.SourceName,
+		guanoNamespace + "|Model: " + m.ModelName,
+		guanoNamespace + "|Model Version: " + m.ModelVersion,
+	}
+	if m.hasLocation() {
+		lines = append(lines, "Loc Position: "+formatCoordinate(m.Latitude)+" "+formatCoordinate(m.Longitude))
+	}
+
+	var b strings.Builder
+	for _, line := range lines {
+		key, value, _ := strings.Cut(line, ": ")
+		if value = sanitizeMetadataValue(value); value != "" {
+			b.WriteString(key + ": " + value + "\n")
+		}
+	}
+	return b.String()
+}
+
+// bext returns the Broadcast Wave Format bext chunk body of a WAV clip.
+func (m *ClipMetadata) bext(channels int) []byte {
+	recordedAt := m.recordedAt()
+	midnight := time.Date(recordedAt.Year(), recordedAt.Month(), recordedAt.Day(), 0, 0, 0, 0, recordedAt.Location())
+	mode := "mono"
+	if channels > 1 {
+		mode = fmt.Sprintf("%dch", channels)
+	}
+	codingHistory := fmt.Sprintf("A=PCM,F=%d,W=%d,M=%s,T=%s\r\n", conf.SampleRate, conf.BitDepth, mode, m.software())
+
+	body := make([]byte, bextFixedLength, bextFixedLength+len(codingHistory))
+	offset := 0
+	putText := func(s string, length int) {
+		copy(body[offset:offset+length], asciiMetadata(s, length))
+		offset += length
+	}
+	putText(m.description(), bextDescLength)
+	putText(metadataSoftware, bextOrigLength)
+	putText(m.SourceName, bextOrigRefLength)
+	putText(recordedAt.Format("2006-01-02"), 10)
+	putText(recordedAt.Format("15:04:05"), 8)
+	binary.LittleEndian.PutUint64(body[offset:], uint64(recordedAt.Sub(midnight)/time.Second)*conf.SampleRate) //nolint:gosec // G115: seconds since midnight are positive
+	offset += 8
+	binary.LittleEndian.PutUint16(body[offset:], bextVersion)
+	// UMID and reserved bytes stay zero
+	return append(body, codingHistory...)
+}
+
+// infoList returns the RIFF LIST/INFO chunk body of a WAV clip.
+func (m *ClipMetadata) infoList() []byte {
+	entries := []metadataField{
+		{"INAM", m.title()},
+		{"IART", m.SourceName},
+		{"ICRD", formatMetadataTime(m.Timestamp)},
+		{"ICMT", m.description()},
+		{"IKEY", strings.Join(nonEmptyStrings(m.CommonName, m.ScientificName, m.SpeciesCode), "; ")},
+		{"ISFT", m.software()},
+	}
+	body := []byte("INFO")
+	for _, e := range entries {
+		value := sanitizeMetadataValue(e.value)
+		if value == "" {
+			continue
+		}
+		body = appendRIFFChunk(body, e.key, append([]byte(value), 0))
+	}
+	return body
+}
+
+// appendWAVMetadata appends LIST/INFO, bext and GUANO chunks to a complete
+// WAV file and updates its RIFF size. Readers that only know the fmt and data
+// chunks skip the trailing chunks.
+func appendWAVMetadata(f *os.File, meta *ClipMetadata, channels, frames int) error {
+	var chunks []byte
+	chunks = appendRIFFChunk(chunks, "LIST", meta.infoList())
+	chunks = appendRIFFChunk(chunks, "bext", meta.bext(channels))
+	chunks = appendRIFFChunk(chunks, "guan", []byte(meta.guano(frames)))
+
+	end, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(chunks); err != nil {
+		return err
+	}
+	riffSize := make([]byte, 4)
+	binary.LittleEndian.PutUint32(riffSize, uint32(end+int64(len(chunks))-8)) //nolint:gosec // G115: clips are far below 4 GB
+	_, err = f.WriteAt(riffSize, 4)
+	return err
+}
+
+// appendRIFFChunk appends a chunk with its header and even-length padding.
+func appendRIFFChunk(dst []byte, id string, body []byte) []byte {
+	dst = append(dst, id...)
+	dst = binary.LittleEndian.AppendUint32(dst, uint32(len(body))) //nolint:gosec // G115: metadata chunks are small
+	dst = append(dst, body...)
+	if len(body)%2 == 1 {
+		dst = append(dst, 0)
+	}
+	return dst
+}
+
+// formatMetadataTime formats a time as ISO 8601 with its UTC offset.
+func formatMetadataTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// formatCoordinate formats a coordinate in decimal degrees.
+func formatCoordinate(v float64) string {
+	return strconv.FormatFloat(v, 'f', 6, 64)
+}
+
+// sanitizeMetadataValue removes line breaks that would split tag formats.
+func sanitizeMetadataValue(s string) string {
+	return strings.TrimSpace(strings.NewReplacer("\r", " ", "\n", " ", "\x00", "").Replace(s))
+}
+
+// asciiMetadata returns s as printable ASCII truncated to length, as the bext fields require.
+func asciiMetadata(s string, length int) []byte {
+	out := make([]byte, 0, length)
+	for _, r := range sanitizeMetadataValue(s) {
+		if len(out) == length {
+			break
+		}
+		if r < 0x20 || r > 0x7E {
+			r = '?'
+		}
+		out = append(out, byte(r))
+	}
+	return out
+}
+
+// nonEmptyStrings returns the non-empty values.
+func nonEmptyStrings(values ...string) []string {
+	out := values[:0]
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,174 @@
+package myaudio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-audio/wav"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// testClipMetadata returns metadata of a detection in a UTC+2 time zone
+func testClipMetadata() *ClipMetadata {
+	zone := time.FixedZone("EET", 2*60*60)
+	return &ClipMetadata{
+		CommonName:     "Common Blackbird",
+		ScientificName: "Turdus merula",
+		SpeciesCode:    "eurbla",
+		Confidence:     0.8734,
+		Timestamp:      time.Date(2026, 5, 14, 4, 30, 12, 0, zone),
+		RecordedAt:     time.Date(2026, 5, 14, 4, 30, 5, 0, zone),
+		Latitude:       60.1699,
+		Longitude:      24.9384,
+		SourceName:     "Garden mic",
+		ModelName:      "BirdNET",
+		ModelVersion:   "2.4",
+		AppVersion:     "v1.2.3",
+	}
+}
+
+// riffChunks returns the top level chunks of a RIFF/WAVE file by ID
+func riffChunks(t *testing.T, data []byte) map[string][]byte {
+	t.Helper()
+	require.Equal(t, "RIFF", string(data[0:4]))
+	require.Equal(t, "WAVE", string(data[8:12]))
+	assert.Equal(t, uint32(len(data)-8), binary.LittleEndian.Uint32(data[4:8]), "RIFF size covers the whole file") //nolint:gosec // G115: small test file
+
+	chunks := make(map[string][]byte)
+	for offset := 12; offset+8 <= len(data); {
+		id := string(data[offset : offset+4])
+		size := int(binary.LittleEndian.Uint32(data[offset+4 : offset+8]))
+		require.LessOrEqual(t, offset+8+size, len(data), "chunk %s exceeds the file", id)
+		chunks[id] = data[offset+8 : offset+8+size]
+		offset += 8 + size + size%2
+	}
+	return chunks
+}
+
+func TestSaveInterleavedPCMToWAV_EmbedsMetadata(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "clip.wav")
+	pcm := sinePCM(1, 1000, -20)
+	require.NoError(t, SaveInterleavedPCMToWAV(path, pcm, 1, testClipMetadata()))
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: test temp file
+	require.NoError(t, err)
+	chunks := riffChunks(t, data)
+	assert.Equal(t, pcm, chunks["data"])
+
+	info := string(chunks["LIST"])
+	assert.True(t, strings.HasPrefix(info, "INFO"))
+	assert.Contains(t, info, "INAM")
+	assert.Contains(t, info, "Common Blackbird (Turdus merula)")
+	assert.Contains(t, info, "BirdNET-Go v1.2.3")
+
+	bext := chunks["bext"]
+	require.GreaterOrEqual(t, len(bext), bextFixedLength)
+	assert.Equal(t, "Common Blackbird (Turdus merula) detected with 87% confidence by BirdNET 2.4 on Garden mic",
+		string(bytes.TrimRight(bext[:bextDescLength], "\x00")))
+	assert.Equal(t, "2026-05-14", string(bext[320:330]))
+	assert.Equal(t, "04:30:05", string(bext[330:338]))
+	assert.Equal(t, uint64((4*3600+30*60+5)*conf.SampleRate), binary.LittleEndian.Uint64(bext[338:346]), "time reference counts samples since midnight")
+	assert.Equal(t, uint16(bextVersion), binary.LittleEndian.Uint16(bext[346:348]))
+	assert.Contains(t, string(bext[bextFixedLength:]), "A=PCM,F=48000,W=16,M=mono")
+
+	guano := string(chunks["guan"])
+	assert.True(t, strings.HasPrefix(guano, "GUANO|Version: 1.0\n"))
+	assert.Contains(t, guano, "Timestamp: 2026-05-14T04:30:05+02:00\n")
+	assert.Contains(t, guano, "Loc Position: 60.169900 24.938400\n")
+	assert.Contains(t, guano, "Species Auto ID: Turdus merula\n")
+	assert.Contains(t, guano, "Length: 1.000\n")
+	assert.Contains(t, guano, "BirdNET-Go|Confidence: 0.8734\n")
+
+	// Standard readers skip the trailing chunks
+	f, err := os.Open(path) //nolint:gosec // G304: test temp file
+	require.NoError(t, err)
+	defer func() { _ = f.Close() }()
+	decoder := wav.NewDecoder(f)
+	buf, err := decoder.FullPCMBuffer()
+	require.NoError(t, err)
+	assert.Len(t, buf.Data, len(pcm)/2)
+}
+
+func TestSaveInterleavedPCMToWAV_WithoutMetadata(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "clip.wav")
+	require.NoError(t, SaveInterleavedPCMToWAV(path, sinePCM(0.5, 1000, -20), 1, nil))
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: test temp file
+	require.NoError(t, err)
+	chunks := riffChunks(t, data)
+	assert.NotContains(t, chunks, "bext")
+	assert.NotContains(t, chunks, "guan")
+}
+
+func TestExportInterleavedAudioToFLAC_WritesVorbisComments(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "clip.flac")
+	pcm := birdLikePCM(conf.SampleRate/2, 2)
+	require.NoError(t, ExportInterleavedAudioToFLAC(pcm, path, &conf.AudioSettings{}, 2, testClipMetadata()))
+
+	data, err := os.ReadFile(path) //nolint:gosec // G304: test temp file
+	require.NoError(t, err)
+	require.Equal(t, "fLaC", string(data[:4]))
+	assert.Equal(t, byte(0x00), data[4], "STREAMINFO is no longer the last block")
+
+	header := data[8+flacStreamInfoLength:]
+	assert.Equal(t, byte(0x84), header[0], "last block is VORBIS_COMMENT")
+	length := int(header[1])<<16 | int(header[2])<<8 | int(header[3])
+	block := header[4 : 4+length]
+
+	vendorLength := binary.LittleEndian.Uint32(block)
+	assert.Equal(t, metadataSoftware, string(block[4:4+vendorLength]))
+	block = block[4+vendorLength:]
+	count := binary.LittleEndian.Uint32(block)
+	block = block[4:]
+	comments := make([]string, 0, count)
+	for range count {
+		n := binary.LittleEndian.Uint32(block)
+		comments = append(comments, string(block[4:4+n]))
+		block = block[4+n:]
+	}
+	assert.Empty(t, block)
+	assert.Equal(t, testClipMetadata().VorbisComments(), comments)
+	assert.Contains(t, comments, "SCIENTIFIC_NAME=Turdus merula")
+	assert.Contains(t, comments, "DATE=2026-05-14T04:30:12+02:00")
+	assert.Contains(t, comments, "LATITUDE=60.169900")
+	assert.Contains(t, comments, "BIRDNET_GO_VERSION=v1.2.3")
+
+	decoded, _ := decodeFLAC(t, bytes.NewReader(data))
+	assert.Equal(t, pcm, decoded, "the audio decodes past the comment block")
+}
+
+func TestClipMetadata_FFmpegArgs(t *testing.T) {
+	t.Parallel()
+
+	meta := testClipMetadata()
+	meta.Latitude, meta.Longitude = 0, 0
+	meta.SpeciesCode = ""
+
+	settings := &conf.AudioSettings{Export: conf.ExportSettings{Type: FormatMP3, Bitrate: "96k"}}
+	args := buildFFmpegArgs("/tmp/clip.mp3", settings, 1, meta)
+	joined := strings.Join(args, " ")
+	assert.Contains(t, joined, "-metadata title=Common Blackbird (Turdus merula)")
+	assert.Contains(t, joined, "-metadata confidence=0.8734")
+	assert.Contains(t, joined, "-id3v2_version 3")
+	assert.NotContains(t, joined, "latitude=", "unset coordinates are omitted")
+	assert.NotContains(t, joined, "species_code=", "empty fields are omitted")
+	assert.Equal(t, "/tmp/clip.mp3", args[len(args)-1])
+
+	settings.Export.Type = FormatOpus
+	args = buildFFmpegArgs("/tmp/clip.opus", settings, 1, meta)
+	assert.NotContains(t, args, "-id3v2_version")
+	assert.Contains(t, args, "model_version=2.4")
+}
@@ -0,0 +1,546 @@
+package myaudio
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/alerting"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+)
+
+// MicHealthIssue identifies a problem with an audio input.
+type MicHealthIssue string
+
+// Microphone health issues detected by the analyzer
+const (
+	MicIssueSilence          MicHealthIssue = "silence"           // input is digital silence, e.g. an unplugged preamp
+	MicIssueClipping         MicHealthIssue = "clipping"          // input persistently reaches full scale
+	MicIssueDCOffset         MicHealthIssue = "dc_offset"         // input is offset from zero, e.g. a failing preamp
+	MicIssueHum              MicHealthIssue = "hum"               // input is dominated by 50/60 Hz mains hum, e.g. a ground loop
+	MicIssueRollOff          MicHealthIssue = "rolloff"           // high frequencies are missing, e.g. a wet or degraded capsule
+	MicIssueChannelImbalance MicHealthIssue = "channel_imbalance" // channels differ in level, e.g. one dead capsule
+)
+
+// micHealthIssues lists issues in reporting order.
+var micHealthIssues = []MicHealthIssue{
+	MicIssueSilence, MicIssueClipping, MicIssueDCOffset, MicIssueHum, MicIssueRollOff, MicIssueChannelImbalance,
+}
+
+// micHealthHoldSeconds is how long a condition must persist before it is reported.
+// Roll-off uses a long hold because quiet soundscapes can lack high frequencies for a while.
+var micHealthHoldSeconds = map[MicHealthIssue]int{
+	MicIssueSilence:          30,
+	MicIssueClipping:         10,
+	MicIssueDCOffset:         30,
+	MicIssueHum:              60,
+	MicIssueRollOff:          600,
+	MicIssueChannelImbalance: 60,
+}
+
+const (
+	micHealthClearSeconds    = 10                // healthy seconds before a reported issue clears
+	micHealthFFTSize         = 1024              // frame size for the roll-off spectrum
+	micHealthRollOffFraction = 0.85              // share of spectral energy below the roll-off frequency
+	micHealthRollOffMinFreq  = 200.0             // roll-off ignores rumble, wind and hum below this frequency
+	micHealthHumHarmonics    = 3                 // mains harmonics summed into the hum ratio
+	micHealthClipLevel       = 32767.0 / 32768.0 // normalized sample value treated as clipped
+	micHealthMinLevelDB      = -120.0            // floor for reported levels
+	micHealthLevelEpsilon    = 1e-12             // avoids log10(0)
+	micHealthMainsFrequency  = 50.0              // mains frequency in most of the world
+	micHealthMainsFreqAlt    = 60.0              // mains frequency in the Americas and parts of Asia
+)
+
+// MicHealth is the latest health assessment of an audio source.
+type MicHealth struct {
+	Source           string           `json:"source"`
+	Name             string           `json:"name"`
+	Healthy          bool             `json:"healthy"`
+	Issues           []MicHealthIssue `json:"issues"`
+	RMSLevel         float64          `json:"rms_dbfs"`
+	PeakLevel        float64          `json:"peak_dbfs"`
+	ClippingRatio    float64          `json:"clipping_ratio"`
+	DCOffset         float64          `json:"dc_offset"`
+	HumFrequency     float64          `json:"hum_frequency_hz"`
+	HumRatio         float64          `json:"hum_ratio"`
+	RollOff          float64          `json:"rolloff_hz"`
+	ChannelImbalance *float64         `json:"channel_imbalance_db,omitempty"` // nil for mono sources
+	UpdatedAt        time.Time        `json:"updated_at"`
+}
+
+// micWindowStats are the measurements of one second of audio.
+type micWindowStats struct {
+	rmsDB    float64
+	peakDB   float64
+	clipping float64
+	dcOffset float64
+	humFreq  float64
+	humRatio float64
+	rollOff  float64
+}
+
+// micIssueState counts consecutive seconds a condition held or did not hold.
+type micIssueState struct {
+	bad    int
+	good   int
+	active bool
+}
+
+// micHealthChange is an issue that started or cleared.
+type micHealthChange struct {
+	issue  MicHealthIssue
+	active bool
+	value  float64
+}
+
+// micHealthAnalyzer accumulates one second of samples from a source and
+// judges whether its input is healthy.
+type micHealthAnalyzer struct {
+	source     string
+	name       string
+	sampleRate int
+
+	window        []float64
+	channelLevels []float64 // latest per-channel RMS in dBFS, nil for mono sources
+	hann          []float64
+	fftRe         []float64
+	fftIm         []float64
+
+	states map[MicHealthIssue]*micIssueState
+	health MicHealth
+
+	mu sync.Mutex
+}
+
+// Global microphone health analyzer registry
+var (
+	micHealthAnalyzers = make(map[string]*micHealthAnalyzer)
+	micHealthMutex     sync.RWMutex
+
+	micHealthMetrics      *metrics.MyAudioMetrics
+	micHealthMetricsMutex sync.RWMutex
+	micHealthMetricsOnce  sync.Once
+)
+
+// SetMicHealthMetrics sets the metrics instance for microphone health diagnostics.
+// Subsequent calls are ignored.
+func SetMicHealthMetrics(myAudioMetrics *metrics.MyAudioMetrics) {
+	micHealthMetricsOnce.Do(func() {
+		micHealthMetricsMutex.Lock()
+		defer micHealthMetricsMutex.Unlock()
+		micHealthMetrics = myAudioMetrics
+	})
+}
+
+func getMicHealthMetrics() *metrics.MyAudioMetrics {
+	micHealthMetricsMutex.RLock()
+	defer micHealthMetricsMutex.RUnlock()
+	return micHealthMetrics
+}
+
+// getMicHealthLogger returns the microphone health logger.
+func getMicHealthLogger() logger.Logger {
+	return logger.Global().Module("audio").Module("michealth")
+}
+
+// newMicHealthAnalyzer creates an analyzer for a source.
+func newMicHealthAnalyzer(source, name string, sampleRate int) *micHealthAnalyzer {
+	a := &micHealthAnalyzer{
+		source:     source,
+		name:       name,
+		sampleRate: sampleRate,
+		window:     make([]float64, 0, sampleRate),
+		hann:       make([]float64, micHealthFFTSize),
+		fftRe:      make([]float64, micHealthFFTSize),
+		fftIm:      make([]float64, micHealthFFTSize),
+		states:     make(map[MicHealthIssue]*micIssueState, len(micHealthIssues)),
+	}
+	for i := range a.hann {
+		a.hann[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(micHealthFFTSize-1))
+	}
+	for _, issue := range micHealthIssues {
+		a.states[issue] = &micIssueState{}
+	}
+	return a
+}
+
+// process adds 16-bit PCM samples and evaluates every completed second.
+// It returns the issues that started or cleared.
+func (a *micHealthAnalyzer) process(samples []byte, settings *conf.MicHealthSettings) []micHealthChange {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var changes []micHealthChange
+	for i := 0; i+1 < len(samples); i += 2 {
+		sample := int16(samples[i]) | int16(samples[i+1])<<8
+		a.window = append(a.window, float64(sample)/32768.0)
+		if len(a.window) < a.sampleRate {
+			continue
+		}
+		stats := a.analyzeWindow(a.window)
+		changes = append(changes, a.evaluate(&stats, settings, time.Now())...)
+		a.window = a.window[:0]
+	}
+	return changes
+}
+
+// analyzeWindow measures level, clipping, DC offset, hum and roll-off.
+func (a *micHealthAnalyzer) analyzeWindow(samples []float64) micWindowStats {
+	n := float64(len(samples))
+	var sum, sumSquares, peak float64
+	var clipped int
+	for _, s := range samples {
+		sum += s
+		sumSquares += s * s
+		abs := math.Abs(s)
+		peak = max(peak, abs)
+		if abs >= micHealthClipLevel {
+			clipped++
+		}
+	}
+	mean := sum / n
+
+	stats := micWindowStats{
+		rmsDB:    levelDB(math.Sqrt(sumSquares / n)),
+		peakDB:   levelDB(peak),
+		clipping: float64(clipped) / n,
+		dcOffset: mean,
+	}
+
+	// Hum is the share of AC energy at the mains frequency and its harmonics
+	acEnergy := sumSquares - n*mean*mean
+	if acEnergy > micHealthLevelEpsilon {
+		for _, mains := range []float64{micHealthMainsFrequency, micHealthMainsFreqAlt} {
+			var humPower float64
+			for h := 1; h <= micHealthHumHarmonics; h++ {
+				humPower += goertzelPower(samples, mean, mains*float64(h), a.sampleRate)
+			}
+			ratio := math.Min(2*humPower/n/acEnergy, 1)
+			if ratio > stats.humRatio {
+				stats.humRatio = ratio
+				stats.humFreq = mains
+			}
+		}
+	}
+
+	stats.rollOff = a.spectralRollOff(samples, mean)
+	return stats
+}
+
+// spectralRollOff returns the frequency below which micHealthRollOffFraction
+// of the energy above micHealthRollOffMinFreq lies, averaged over FFT frames.
+func (a *micHealthAnalyzer) spectralRollOff(samples []float64, mean float64) float64 {
+	bins := micHealthFFTSize/2 + 1
+	power := make([]float64, bins)
+	frames := 0
+	for start := 0; start+micHealthFFTSize <= len(samples); start += micHealthFFTSize {
+		for i := range micHealthFFTSize {
+			a.fftRe[i] = (samples[start+i] - mean) * a.hann[i]
+			a.fftIm[i] = 0
+		}
+		fftRadix2(a.fftRe, a.fftIm)
+		for k := range bins {
+			power[k] += a.fftRe[k]*a.fftRe[k] + a.fftIm[k]*a.fftIm[k]
+		}
+		frames++
+	}
+	if frames == 0 {
+		return 0
+	}
+
+	binHz := float64(a.sampleRate) / micHealthFFTSize
+	first := int(math.Ceil(micHealthRollOffMinFreq / binHz))
+	var total float64
+	for k := first; k < bins; k++ {
+		total += power[k]
+	}
+	if total <= micHealthLevelEpsilon {
+		return 0
+	}
+	var cumulative float64
+	for k := first; k < bins; k++ {
+		cumulative += power[k]
+		if cumulative >= micHealthRollOffFraction*total {
+			return float64(k) * binHz
+		}
+	}
+	return float64(bins-1) * binHz
+}
+
+// evaluate updates issue states with one second of measurements.
+func (a *micHealthAnalyzer) evaluate(stats *micWindowStats, settings *conf.MicHealthSettings, now time.Time) []micHealthChange {
+	silent := stats.peakDB < settings.SilenceThreshold
+	imbalance, hasChannels := channelSpread(a.channelLevels)
+
+	conditions := map[MicHealthIssue]bool{
+		MicIssueSilence:          silent,
+		MicIssueClipping:         stats.clipping >= settings.ClippingRatio,
+		MicIssueDCOffset:         !silent && math.Abs(stats.dcOffset) >= settings.DCOffset,
+		MicIssueHum:              !silent && stats.humRatio >= settings.HumRatio,
+		MicIssueRollOff:          !silent && settings.MinRollOff > 0 && stats.rollOff > 0 && stats.rollOff < settings.MinRollOff,
+		MicIssueChannelImbalance: !silent && hasChannels && imbalance >= settings.MaxImbalance,
+	}
+	values := map[MicHealthIssue]float64{
+		MicIssueSilence:          stats.peakDB,
+		MicIssueClipping:         stats.clipping,
+		MicIssueDCOffset:         stats.dcOffset,
+		MicIssueHum:              stats.humRatio,
+		MicIssueRollOff:          stats.rollOff,
+		MicIssueChannelImbalance: imbalance,
+	}
+
+	var changes []micHealthChange
+	issues := make([]MicHealthIssue, 0, len(micHealthIssues))
+	for _, issue := range micHealthIssues {
+		state := a.states[issue]
+		if conditions[issue] {
+			state.bad++
+			state.good = 0
+			if !state.active && state.bad >= micHealthHoldSeconds[issue] {
+				state.active = true
+				changes = append(changes, micHealthChange{issue: issue, active: true, value: values[issue]})
+			}
+		} else {
+			state.good++
+			state.bad = 0
+			if state.active && state.good >= micHealthClearSeconds {
+				state.active = false
+				changes = append(changes, micHealthChange{issue: issue, active: false, value: values[issue]})
+			}
+		}
+		if state.active {
+			issues = append(issues, issue)
+		}
+	}
+
+	a.health = MicHealth{
+		Source:        a.source,
+		Name:          a.name,
+		Healthy:       len(issues) == 0,
+		Issues:        issues,
+		RMSLevel:      stats.rmsDB,
+		PeakLevel:     stats.peakDB,
+		ClippingRatio: stats.clipping,
+		DCOffset:      stats.dcOffset,
+		HumFrequency:  stats.humFreq,
+		HumRatio:      stats.humRatio,
+		RollOff:       stats.rollOff,
+		UpdatedAt:     now,
+	}
+	if hasChannels {
+		a.health.ChannelImbalance = &imbalance
+	}
+	return changes
+}
+
+// snapshot returns a copy of the latest assessment.
+func (a *micHealthAnalyzer) snapshot() (MicHealth, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.health.UpdatedAt.IsZero() {
+		return MicHealth{}, false
+	}
+	health := a.health
+	health.Issues = slices.Clone(a.health.Issues)
+	if a.health.ChannelImbalance != nil {
+		imbalance := *a.health.ChannelImbalance
+		health.ChannelImbalance = &imbalance
+	}
+	return health, true
+}
+
+// channelSpread returns the level difference between the loudest and quietest channel.
+func channelSpread(levelsDB []float64) (float64, bool) {
+	if len(levelsDB) < 2 {
+		return 0, false
+	}
+	return slices.Max(levelsDB) - slices.Min(levelsDB), true
+}
+
+// goertzelPower returns the power of samples, with mean removed, at freq.
+func goertzelPower(samples []float64, mean, freq float64, sampleRate int) float64 {
+	coeff := 2 * math.Cos(2*math.Pi*freq/float64(sampleRate))
+	var s1, s2 float64
+	for _, x := range samples {
+		s0 := (x - mean) + coeff*s1 - s2
+		s2 = s1
+		s1 = s0
+	}
+	return s1*s1 + s2*s2 - coeff*s1*s2
+}
+
+// fftRadix2 computes an in-place FFT. The length must be a power of two.
+func fftRadix2(re, im []float64) {
+	n := len(re)
+	for i, j := 1, 0; i < n; i++ {
+		bit := n >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			re[i], re[j] = re[j], re[i]
+			im[i], im[j] = im[j], im[i]
+		}
+	}
+	for size := 2; size <= n; size <<= 1 {
+		angle := -2 * math.Pi / float64(size)
+		wRe, wIm := math.Cos(angle), math.Sin(angle)
+		for start := 0; start < n; start += size {
+			curRe, curIm := 1.0, 0.0
+			for k := range size / 2 {
+				a, b := start+k, start+k+size/2
+				tRe := curRe*re[b] - curIm*im[b]
+				tIm := curRe*im[b] + curIm*re[b]
+				re[b], im[b] = re[a]-tRe, im[a]-tIm
+				re[a], im[a] = re[a]+tRe, im[a]+tIm
+				curRe, curIm = curRe*wRe-curIm*wIm, curRe*wIm+curIm*wRe
+			}
+		}
+	}
+}
+
+// levelDB converts a normalized amplitude to dBFS.
+func levelDB(amplitude float64) float64 {
+	return math.Max(20*math.Log10(amplitude+micHealthLevelEpsilon), micHealthMinLevelDB)
+}
+
+// ProcessMicHealth feeds 16-bit PCM samples from a source into its health
+// analyzer. Issues that persist are logged, exported as metrics and published
+// as device.health_issue alert events.
+func ProcessMicHealth(source, name string, samples []byte) {
+	settings := conf.Setting().Realtime.Audio.MicHealth
+	if !settings.Enabled || len(samples) == 0 {
+		return
+	}
+
+	micHealthMutex.Lock()
+	analyzer, exists := micHealthAnalyzers[source]
+	if !exists {
+		analyzer = newMicHealthAnalyzer(source, name, conf.SampleRate)
+		micHealthAnalyzers[source] = analyzer
+	}
+	micHealthMutex.Unlock()
+
+	changes := analyzer.process(samples, &settings)
+	health, ok := analyzer.snapshot()
+	if !ok {
+		return
+	}
+	recordMicHealthMetrics(&health)
+	for _, change := range changes {
+		publishMicHealthChange(&health, change)
+	}
+}
+
+// ReportChannelLevels records per-channel RMS levels in dBFS for a source
+// captured with more than one channel, enabling the channel imbalance check.
+func ReportChannelLevels(source string, levelsDB []float64) {
+	micHealthMutex.RLock()
+	analyzer, exists := micHealthAnalyzers[source]
+	micHealthMutex.RUnlock()
+	if !exists {
+		return
+	}
+	analyzer.mu.Lock()
+	analyzer.channelLevels = slices.Clone(levelsDB)
+	analyzer.mu.Unlock()
+}
+
+// GetMicHealth returns the latest health assessment of a source.
+func GetMicHealth(source string) (MicHealth, bool) {
+	micHealthMutex.RLock()
+	analyzer, exists := micHealthAnalyzers[source]
+	micHealthMutex.RUnlock()
+	if !exists {
+		return MicHealth{}, false
+	}
+	return analyzer.snapshot()
+}
+
+// GetAllMicHealth returns the latest health assessment of every analyzed source.
+func GetAllMicHealth() []MicHealth {
+	micHealthMutex.RLock()
+	analyzers := make([]*micHealthAnalyzer, 0, len(micHealthAnalyzers))
+	for _, analyzer := range micHealthAnalyzers {
+		analyzers = append(analyzers, analyzer)
+	}
+	micHealthMutex.RUnlock()
+
+	result := make([]MicHealth, 0, len(analyzers))
+	for _, analyzer := range analyzers {
+		if health, ok := analyzer.snapshot(); ok {
+			result = append(result, health)
+		}
+	}
+	slices.SortFunc(result, func(a, b MicHealth) int {
+		return cmp.Compare(a.Source, b.Source)
+	})
+	return result
+}
+
+// ResetMicHealth discards the health state of a source that stopped.
+func ResetMicHealth(source string) {
+	micHealthMutex.Lock()
+	delete(micHealthAnalyzers, source)
+	micHealthMutex.Unlock()
+
+	if m := getMicHealthMetrics(); m != nil {
+		for _, issue := range micHealthIssues {
+			m.UpdateMicHealthIssue(source, string(issue), false)
+		}
+	}
+}
+
+func recordMicHealthMetrics(health *MicHealth) {
+	m := getMicHealthMetrics()
+	if m == nil {
+		return
+	}
+	m.UpdateMicHealthMeasurement(health.Source, "rms_db", health.RMSLevel)
+	m.UpdateMicHealthMeasurement(health.Source, "peak_db", health.PeakLevel)
+	m.UpdateMicHealthMeasurement(health.Source, "clipping_ratio", health.ClippingRatio)
+	m.UpdateMicHealthMeasurement(health.Source, "dc_offset", health.DCOffset)
+	m.UpdateMicHealthMeasurement(health.Source, "hum_ratio", health.HumRatio)
+	m.UpdateMicHealthMeasurement(health.Source, "rolloff_hz", health.RollOff)
+	if health.ChannelImbalance != nil {
+		m.UpdateMicHealthMeasurement(health.Source, "channel_imbalance_db", *health.ChannelImbalance)
+	}
+	for _, issue := range micHealthIssues {
+		m.UpdateMicHealthIssue(health.Source, string(issue), slices.Contains(health.Issues, issue))
+	}
+}
+
+func publishMicHealthChange(health *MicHealth, change micHealthChange) {
+	log := getMicHealthLogger()
+	eventName := alerting.EventDeviceHealthIssue
+	if change.active {
+		log.Warn("microphone health issue detected",
+			logger.String("source", health.Source),
+			logger.String("name", health.Name),
+			logger.String("issue", string(change.issue)),
+			logger.Float64("value", change.value))
+	} else {
+		eventName = alerting.EventDeviceHealthRecovered
+		log.Info("microphone health issue cleared",
+			logger.String("source", health.Source),
+			logger.String("name", health.Name),
+			logger.String("issue", string(change.issue)))
+	}
+
+	alerting.TryPublish(&alerting.AlertEvent{
+		ObjectType: alerting.ObjectTypeDevice,
+		EventName:  eventName,
+		Properties: map[string]any{
+			alerting.PropertyDeviceName: health.Name,
+			alerting.PropertySource:     health.Source,
+			alerting.PropertyIssue:      string(change.issue),
+			alerting.PropertyValue:      change.value,
+		},
+		Timestamp: health.UpdatedAt,
+	})
+}
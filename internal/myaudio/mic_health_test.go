@@ -0,0 +1,306 @@
+package myaudio
+
+import (
+	"encoding/binary"
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+const micTestSampleRate = 48000
+
+func micTestSettings() *conf.MicHealthSettings {
+	return &conf.MicHealthSettings{
+		Enabled:          true,
+		SilenceThreshold: -84,
+		ClippingRatio:    0.001,
+		DCOffset:         0.05,
+		HumRatio:         0.5,
+		MinRollOff:       1000,
+		MaxImbalance:     6,
+	}
+}
+
+func micTestSine(freq, amplitude float64) []float64 {
+	samples := make([]float64, micTestSampleRate)
+	for i := range samples {
+		samples[i] = amplitude * math.Sin(2*math.Pi*freq*float64(i)/micTestSampleRate)
+	}
+	return samples
+}
+
+func TestMicHealthAnalyzeWindow(t *testing.T) {
+	t.Parallel()
+
+	rng := rand.New(rand.NewPCG(1, 2)) //nolint:gosec // deterministic test noise
+	noise := make([]float64, micTestSampleRate)
+	for i := range noise {
+		noise[i] = 0.1 * (rng.Float64()*2 - 1)
+	}
+
+	clipped := micTestSine(440, 2)
+	for i, s := range clipped {
+		clipped[i] = math.Max(-1, math.Min(1, s))
+	}
+
+	offset := micTestSine(1000, 0.1)
+	for i := range offset {
+		offset[i] += 0.2
+	}
+
+	tests := []struct {
+		name    string
+		samples []float64
+		check   func(t *testing.T, stats micWindowStats)
+	}{
+		{
+			name:    "digital silence",
+			samples: make([]float64, micTestSampleRate),
+			check: func(t *testing.T, stats micWindowStats) {
+				t.Helper()
+				assert.InDelta(t, micHealthMinLevelDB, stats.peakDB, 0.01)
+				assert.Zero(t, stats.humRatio)
+				assert.Zero(t, stats.rollOff)
+			},
+		},
+		{
+			name:    "clipping",
+			samples: clipped,
+			check: func(t *testing.T, stats micWindowStats) {
+				t.Helper()
+				assert.Greater(t, stats.clipping, 0.3)
+				assert.InDelta(t, 0, stats.peakDB, 0.01)
+			},
+		},
+		{
+			name:    "DC offset",
+			samples: offset,
+			check: func(t *testing.T, stats micWindowStats) {
+				t.Helper()
+				assert.InDelta(t, 0.2, stats.dcOffset, 0.001)
+				assert.Less(t, stats.humRatio, 0.01, "offset must not count as hum")
+			},
+		},
+		{
+			name:    "50 Hz hum",
+			samples: micTestSine(50, 0.3),
+			check: func(t *testing.T, stats micWindowStats) {
+				t.Helper()
+				assert.InDelta(t, 50, stats.humFreq, 0.01)
+				assert.Greater(t, stats.humRatio, 0.95)
+			},
+		},
+		{
+			name:    "60 Hz hum",
+			samples: micTestSine(60, 0.3),
+			check: func(t *testing.T, stats micWindowStats) {
+				t.Helper()
+				assert.InDelta(t, 60, stats.humFreq, 0.01)
+				assert.Greater(t, stats.humRatio, 0.95)
+			},
+		},
+		{
+			name:    "low roll-off",
+			samples: micTestSine(400, 0.3),
+			check: func(t *testing.T, stats micWindowStats) {
+				t.Helper()
+				assert.Less(t, stats.rollOff, 1000.0)
+				assert.Less(t, stats.humRatio, 0.01)
+			},
+		},
+		{
+			name:    "broadband noise",
+			samples: noise,
+			check: func(t *testing.T, stats micWindowStats) {
+				t.Helper()
+				assert.Greater(t, stats.rollOff, 15000.0)
+				assert.Less(t, stats.humRatio, 0.01)
+				assert.Zero(t, stats.clipping)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			analyzer := newMicHealthAnalyzer("test", "Test", micTestSampleRate)
+			tt.check(t, analyzer.analyzeWindow(tt.samples))
+		})
+	}
+}
+
+func TestMicHealthEvaluate_HoldAndClear(t *testing.T) {
+	t.Parallel()
+
+	analyzer := newMicHealthAnalyzer("test", "Test", micTestSampleRate)
+	settings := micTestSettings()
+	now := time.Now()
+	clipping := micWindowStats{rmsDB: -3, peakDB: 0, clipping: 0.05, rollOff: 5000}
+	healthy := micWindowStats{rmsDB: -40, peakDB: -20, rollOff: 5000}
+
+	hold := micHealthHoldSeconds[MicIssueClipping]
+	for range hold - 1 {
+		assert.Empty(t, analyzer.evaluate(&clipping, settings, now))
+	}
+	changes := analyzer.evaluate(&clipping, settings, now)
+	require.Len(t, changes, 1)
+	assert.Equal(t, MicIssueClipping, changes[0].issue)
+	assert.True(t, changes[0].active)
+	assert.InDelta(t, 0.05, changes[0].value, 1e-9)
+
+	health, ok := analyzer.snapshot()
+	require.True(t, ok)
+	assert.False(t, health.Healthy)
+	assert.Equal(t, []MicHealthIssue{MicIssueClipping}, health.Issues)
+
+	// A single healthy second does not clear the issue
+	assert.Empty(t, analyzer.evaluate(&healthy, settings, now))
+	assert.Empty(t, analyzer.evaluate(&clipping, settings, now))
+
+	for range micHealthClearSeconds - 1 {
+		assert.Empty(t, analyzer.evaluate(&healthy, settings, now))
+	}
+	changes = analyzer.evaluate(&healthy, settings, now)
+	require.Len(t, changes, 1)
+	assert.Equal(t, MicIssueClipping, changes[0].issue)
+	assert.False(t, changes[0].active)
+
+	health, ok = analyzer.snapshot()
+	require.True(t, ok)
+	assert.True(t, health.Healthy)
+	assert.Empty(t, health.Issues)
+}
+
+func TestMicHealthEvaluate_SilenceSuppressesSpectralChecks(t *testing.T) {
+	t.Parallel()
+
+	analyzer := newMicHealthAnalyzer("test", "Test", micTestSampleRate)
+	analyzer.channelLevels = []float64{-120, -120}
+	settings := micTestSettings()
+	silent := micWindowStats{rmsDB: -120, peakDB: -120, humRatio: 1, humFreq: 50, rollOff: 300}
+
+	var changes []micHealthChange
+	for range micHealthHoldSeconds[MicIssueRollOff] {
+		changes = append(changes, analyzer.evaluate(&silent, settings, time.Now())...)
+	}
+	require.Len(t, changes, 1)
+	assert.Equal(t, MicIssueSilence, changes[0].issue)
+}
+
+func TestMicHealthEvaluate_ChannelImbalance(t *testing.T) {
+	t.Parallel()
+
+	analyzer := newMicHealthAnalyzer("test", "Test", micTestSampleRate)
+	settings := micTestSettings()
+	stats := micWindowStats{rmsDB: -30, peakDB: -10, rollOff: 5000}
+
+	// Mono sources never report imbalance
+	for range micHealthHoldSeconds[MicIssueChannelImbalance] {
+		assert.Empty(t, analyzer.evaluate(&stats, settings, time.Now()))
+	}
+	health, _ := analyzer.snapshot()
+	assert.Nil(t, health.ChannelImbalance)
+
+	analyzer.channelLevels = []float64{-30, -45}
+	var changes []micHealthChange
+	for range micHealthHoldSeconds[MicIssueChannelImbalance] {
+		changes = append(changes, analyzer.evaluate(&stats, settings, time.Now())...)
+	}
+	require.Len(t, changes, 1)
+	assert.Equal(t, MicIssueChannelImbalance, changes[0].issue)
+	assert.InDelta(t, 15, changes[0].value, 1e-9)
+
+	health, _ = analyzer.snapshot()
+	require.NotNil(t, health.ChannelImbalance)
+	assert.InDelta(t, 15, *health.ChannelImbalance, 1e-9)
+}
+
+func TestChannelSplitter_ReportsLevelsToEveryChannelSource(t *testing.T) {
+	layout := &channelLayout{
+		parentID: "mic_levels_test",
+		channels: 2,
+		targets: []channelTarget{
+			{id: "mic_levels_test_ch1", channel: 0},
+			{id: "mic_levels_test_ch2", channel: 1},
+		},
+	}
+	analyzers := make([]*micHealthAnalyzer, len(layout.targets))
+	micHealthMutex.Lock()
+	for i, target := range layout.targets {
+		analyzers[i] = newMicHealthAnalyzer(target.id, target.id, micTestSampleRate)
+		micHealthAnalyzers[target.id] = analyzers[i]
+	}
+	micHealthMutex.Unlock()
+	t.Cleanup(func() {
+		for _, target := range layout.targets {
+			ResetMicHealth(target.id)
+		}
+	})
+
+	splitter := newChannelSplitter(layout)
+	splitter.split(interleavedTestFrames(100, 16384, 0))
+	splitter.reportChannelLevels()
+
+	for i, analyzer := range analyzers {
+		analyzer.mu.Lock()
+		levels := analyzer.channelLevels
+		analyzer.mu.Unlock()
+		require.Len(t, levels, 2, "channel source %d", i+1)
+		assert.InDelta(t, -6, levels[0], 0.1)
+		assert.InDelta(t, micHealthMinLevelDB, levels[1], 0.1, "the silent channel")
+	}
+}
+
+func TestMicHealthProcess_EvaluatesEachSecond(t *testing.T) {
+	t.Parallel()
+
+	analyzer := newMicHealthAnalyzer("test", "Test", micTestSampleRate)
+	settings := micTestSettings()
+
+	_, ok := analyzer.snapshot()
+	assert.False(t, ok, "no assessment before one second of audio")
+
+	pcm := make([]byte, micTestSampleRate) // half a second of 16-bit samples
+	analyzer.process(pcm, settings)
+	_, ok = analyzer.snapshot()
+	assert.False(t, ok)
+
+	sine := micTestSine(1000, 0.5)
+	pcm = make([]byte, 0, len(sine))
+	for _, s := range sine[:micTestSampleRate/2] {
+		pcm = binary.LittleEndian.AppendUint16(pcm, uint16(int16(s*32767)))
+	}
+	analyzer.process(pcm, settings)
+
+	health, ok := analyzer.snapshot()
+	require.True(t, ok)
+	assert.Equal(t, "test", health.Source)
+	assert.Greater(t, health.PeakLevel, -10.0)
+	assert.True(t, health.Healthy)
+}
+
+func TestFFTRadix2(t *testing.T) {
+	t.Parallel()
+
+	const n = 16
+	re := make([]float64, n)
+	im := make([]float64, n)
+	for i := range re {
+		re[i] = math.Cos(2 * math.Pi * 3 * float64(i) / n)
+	}
+	fftRadix2(re, im)
+
+	for k := range n {
+		magnitude := math.Hypot(re[k], im[k])
+		if k == 3 || k == n-3 {
+			assert.InDelta(t, n/2, magnitude, 1e-9, "bin %d", k)
+		} else {
+			assert.InDelta(t, 0, magnitude, 1e-9, "bin %d", k)
+		}
+	}
+}
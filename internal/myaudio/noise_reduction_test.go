@@ -0,0 +1,158 @@
+package myaudio
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func noiseTestSettings() *conf.NoiseReductionSettings {
+	return &conf.NoiseReductionSettings{Enabled: true, Strength: 0.75, Threshold: 6, Clips: true}
+}
+
+// noiseTestChunk returns three seconds of white noise with a 3 kHz call in the second half.
+func noiseTestChunk(seed uint64) []float64 {
+	rng := rand.New(rand.NewPCG(seed, seed+1)) //nolint:gosec // deterministic test noise
+	samples := make([]float64, 3*conf.SampleRate)
+	for i := range samples {
+		samples[i] = 0.05 * (rng.Float64()*2 - 1)
+		if i >= len(samples)/2 && i < len(samples)/2+conf.SampleRate/2 {
+			samples[i] += 0.3 * math.Sin(2*math.Pi*3000*float64(i)/float64(conf.SampleRate))
+		}
+	}
+	return samples
+}
+
+func rmsOf(samples []float64) float64 {
+	var sum float64
+	for _, s := range samples {
+		sum += s * s
+	}
+	return math.Sqrt(sum / float64(len(samples)))
+}
+
+func TestNoiseReducer_ReconstructsWhenNothingIsGated(t *testing.T) {
+	t.Parallel()
+
+	reducer := newNoiseReducer()
+	reducer.profile = make([]float64, noiseReductionBins) // zero noise profile passes every cell
+
+	samples := noiseTestChunk(1)[:10000] // not a multiple of the hop
+	original := append([]float64(nil), samples...)
+	reducer.process(samples, noiseTestSettings(), false)
+
+	for i := range samples {
+		require.InDelta(t, original[i], samples[i], 1e-9, "sample %d", i)
+	}
+}
+
+func TestNoiseReducer_AttenuatesStationaryNoise(t *testing.T) {
+	t.Parallel()
+
+	reducer := newNoiseReducer()
+	settings := noiseTestSettings()
+
+	// Learn the noise profile from a first chunk, as the analysis path does
+	reducer.process(noiseTestChunk(1), settings, true)
+
+	samples := noiseTestChunk(2)
+	original := append([]float64(nil), samples...)
+	reducer.process(samples, settings, true)
+
+	noiseEnd := len(samples) / 2
+	callStart, callEnd := len(samples)/2+conf.SampleRate/10, len(samples)/2+conf.SampleRate*4/10
+
+	noiseRatio := rmsOf(samples[conf.SampleRate/10:noiseEnd]) / rmsOf(original[conf.SampleRate/10:noiseEnd])
+	assert.Less(t, noiseRatio, 0.45, "stationary noise should be attenuated")
+
+	callPower := goertzelPower(samples[callStart:callEnd], 0, 3000, conf.SampleRate)
+	originalCallPower := goertzelPower(original[callStart:callEnd], 0, 3000, conf.SampleRate)
+	assert.Greater(t, callPower/originalCallPower, 0.8, "the call should pass the gate")
+}
+
+func TestNoiseReducer_StrengthZeroIsPassthrough(t *testing.T) {
+	t.Parallel()
+
+	samples := noiseTestChunk(3)
+	original := append([]float64(nil), samples...)
+	settings := noiseTestSettings()
+	settings.Strength = 0
+
+	newNoiseReducer().process(samples, settings, true)
+	assert.Equal(t, original, samples)
+}
+
+// NOTE: Do NOT use t.Parallel() - these tests modify the global noise reducer registry
+func TestReduceAnalysisNoise_ProfilePerSource(t *testing.T) {
+	sourceA := "noise_test_a"
+	sourceB := "noise_test_b"
+	t.Cleanup(func() {
+		ResetNoiseReduction(sourceA)
+		ResetNoiseReduction(sourceB)
+	})
+
+	chunk := noiseTestChunk(4)
+	samples := make([]float32, len(chunk))
+	for i, s := range chunk {
+		samples[i] = float32(s)
+	}
+	reduceAnalysisNoise(sourceA, samples, noiseTestSettings())
+
+	profile := getNoiseReducer(sourceA).snapshotProfile()
+	require.Len(t, profile, noiseReductionBins)
+	assert.Nil(t, getNoiseReducer(sourceB).snapshotProfile(), "sources must not share noise profiles")
+
+	ResetNoiseReduction(sourceA)
+	assert.Nil(t, getNoiseReducer(sourceA).snapshotProfile(), "reset discards the profile")
+}
+
+func TestReduceClipNoise(t *testing.T) {
+	source := "noise_test_clip"
+	t.Cleanup(func() { ResetNoiseReduction(source) })
+
+	settings := noiseTestSettings()
+	getNoiseReducer(source).process(noiseTestChunk(5), settings, true)
+	profile := getNoiseReducer(source).snapshotProfile()
+
+	chunk := noiseTestChunk(6)
+	pcm := make([]byte, len(chunk)*2)
+	require.NoError(t, Float64ToBytesPCM16(chunk, pcm))
+	before := rmsOf(BytesToFloat64PCM16(pcm)[:len(chunk)/2])
+
+	require.NoError(t, ReduceClipNoise(source, pcm, settings))
+	after := rmsOf(BytesToFloat64PCM16(pcm)[:len(chunk)/2])
+	assert.Less(t, after/before, 0.45, "clip noise should be attenuated")
+	assert.Equal(t, profile, getNoiseReducer(source).snapshotProfile(), "clips must not change the source profile")
+
+	require.Error(t, ReduceClipNoise(source, []byte{0, 0, 0}, settings), "odd PCM length")
+	require.Error(t, ReduceClipNoise(source, pcm, nil), "nil settings")
+}
+
+// BenchmarkNoiseReducer measures one 3 second analysis chunk. The realtime
+// metric must stay well above 1 on a Raspberry Pi 4 to leave headroom for
+// inference and several sources.
+func BenchmarkNoiseReducer(b *testing.B) {
+	reducer := newNoiseReducer()
+	settings := noiseTestSettings()
+	chunk := noiseTestChunk(7)
+	samples := make([]float32, len(chunk))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		for i, s := range chunk {
+			samples[i] = float32(s)
+		}
+		reducer.processFloat32(samples, settings, true)
+	}
+
+	perChunk := b.Elapsed() / time.Duration(b.N)
+	if perChunk > 0 {
+		b.ReportMetric(float64(3*time.Second)/float64(perChunk), "x_realtime")
+	}
+}
@@ -2,6 +2,7 @@
 package myaudio
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"time"
@@ -78,8 +79,13 @@ func ProcessData(bn *birdnet.BirdNET, data []byte, startTime time.Time, source s
 		return fmt.Errorf("error converting %v bit PCM data to float32: %w", conf.BitDepth, err)
 	}
 
-	// run BirdNET inference
-	results, err := bn.Predict(sampleData)
+	// reduce stationary noise of the source before inference
+	if noiseReduction := conf.Setting().Realtime.Audio.NoiseReduction; noiseReduction.Enabled && len(sampleData) > 0 {
+		reduceAnalysisNoise(source, sampleData[0], &noiseReduction)
+	}
+
+	// run BirdNET inference, batched with other sources when enabled
+	results, err := bn.PredictSource(context.Background(), source, sampleData)
 
 	// Return float32 buffer to pool after prediction
 	// This is safe because Predict copies the data to the input tensor
@@ -123,7 +129,11 @@ func ProcessData(bn *birdnet.BirdNET, data []byte, startTime time.Time, source s
 
 	// Calculate the effective buffer duration
 	bufferDuration := 3 * time.Second // base duration
-	overlapDuration := time.Duration(settings.BirdNET.Overlap * float64(time.Second))
+	overlap := settings.BirdNET.Overlap
+	if adapted, ok := AdaptedOverlap(source); ok {
+		overlap = adapted
+	}
+	overlapDuration := time.Duration(overlap * float64(time.Second))
 	effectiveBufferDuration := bufferDuration - overlapDuration
 
 	// Check if processing time exceeds effective buffer duration
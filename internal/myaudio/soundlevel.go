@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/tphakala/birdnet-go/internal/alerting"
 	"github.com/tphakala/birdnet-go/internal/conf"
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
@@ -588,5 +589,18 @@ func ProcessSoundLevelData(source string, audioData []byte) (*SoundLevelData, er
 			Build()
 	}
 
-	return processor.ProcessAudioData(audioData)
+	data, err := processor.ProcessAudioData(audioData)
+	if err == nil && data != nil {
+		// Completed intervals feed absence alert rules that detect silent or stalled sources
+		alerting.TryPublish(&alerting.AlertEvent{
+			ObjectType: alerting.ObjectTypeDevice,
+			EventName:  alerting.EventDeviceSoundLevel,
+			Properties: map[string]any{
+				alerting.PropertyDeviceName: data.Name,
+				alerting.PropertySource:     data.Source,
+			},
+			Timestamp: data.Timestamp,
+		})
+	}
+	return data, err
 }
@@ -32,15 +32,17 @@ const (
 
 // SourceStats provides structured statistics about registered sources
 type SourceStats struct {
-	Total  int `json:"total_sources"`
-	Active int `json:"active_sources"`
-	RTSP   int `json:"rtsp_sources"`
-	HTTP   int `json:"http_sources"`
-	HLS    int `json:"hls_sources"`
-	RTMP   int `json:"rtmp_sources"`
-	UDP    int `json:"udp_sources"`
-	Device int `json:"device_sources"`
-	File   int `json:"file_sources"`
+	Total   int `json:"total_sources"`
+	Active  int `json:"active_sources"`
+	RTSP    int `json:"rtsp_sources"`
+	HTTP    int `json:"http_sources"`
+	HLS     int `json:"hls_sources"`
+	RTMP    int `json:"rtmp_sources"`
+	UDP     int `json:"udp_sources"`
+	Device  int `json:"device_sources"`
+	File    int `json:"file_sources"`
+	Push    int `json:"push_sources"`
+	Browser int `json:"browser_sources"`
 }
 
 // AudioSourceRegistry manages all audio sources in the system
@@ -232,6 +234,16 @@ func detectSourceTypeFromString(connectionString string) SourceType {
 		return SourceTypeUDP
 	}
 
+	// Remote recorders pushing audio over HTTP
+	if strings.HasPrefix(connectionString, ingestConnectionPrefix) {
+		return SourceTypePush
+	}
+
+	// Browser microphones streamed over WebSocket
+	if strings.HasPrefix(connectionString, liveConnectionPrefix) {
+		return SourceTypeBrowser
+	}
+
 	// Audio device patterns
 	if strings.HasPrefix(connectionString, "hw:") ||
 		strings.HasPrefix(connectionString, "plughw:") ||
@@ -572,6 +584,10 @@ func (r *AudioSourceRegistry) validateConnectionString(connectionString string,
 		return r.validateFilePath(connectionString)
 	case SourceTypeAudioCard:
 		return r.validateAudioDevice(connectionString)
+	case SourceTypePush:
+		return r.validateSessionConnection(connectionString, ingestConnectionPrefix)
+	case SourceTypeBrowser:
+		return r.validateSessionConnection(connectionString, liveConnectionPrefix)
 	default:
 		// Unknown types are allowed but logged
 		r.logger.Warn("Unknown source type for validation",
@@ -686,6 +702,20 @@ func (r *AudioSourceRegistry) validateUDPURL(udpURL string) error {
 	return r.validateStreamURL(udpURL, "UDP", "validate_udp_url", []string{"udp://", "rtp://"})
 }
 
+// validateSessionConnection validates the connection string of a push ingest
+// device or browser session, which is a prefix followed by a plain identifier
+func (r *AudioSourceRegistry) validateSessionConnection(connectionString, prefix string) error {
+	id := strings.TrimPrefix(connectionString, prefix)
+	if id == connectionString || id == "" || strings.ContainsAny(id, "/\\ ") {
+		return errors.Newf("invalid connection string for %s source", strings.TrimSuffix(prefix, "://")).
+			Component("myaudio").
+			Category(errors.CategoryValidation).
+			Context("operation", "validate_session_connection").
+			Build()
+	}
+	return nil
+}
+
 // validateFilePath validates file paths for security
 func (r *AudioSourceRegistry) validateFilePath(filePath string) error {
 	// Clean the path to prevent directory traversal
@@ -793,6 +823,10 @@ func (r *AudioSourceRegistry) GetSourceStats() SourceStats {
 			stats.Device++
 		case SourceTypeFile:
 			stats.File++
+		case SourceTypePush:
+			stats.Push++
+		case SourceTypeBrowser:
+			stats.Browser++
 		case SourceTypeUnknown:
 			// Unknown sources shouldn't normally exist, but handle for completeness
 			// These would be sources that failed type detection
@@ -809,7 +843,7 @@ func (r *AudioSourceRegistry) sanitizeConnectionString(conn string, sourceType S
 	case SourceTypeRTSP, SourceTypeHTTP, SourceTypeHLS, SourceTypeRTMP, SourceTypeUDP:
 		// All stream types may contain credentials and should be sanitized
 		return privacy.SanitizeStreamUrl(conn)
-	case SourceTypeAudioCard, SourceTypeFile:
+	case SourceTypeAudioCard, SourceTypeFile, SourceTypePush, SourceTypeBrowser:
 		// These are generally safe to log as-is
 		return conn
 	default:
@@ -875,6 +909,10 @@ func (r *AudioSourceRegistry) generateDisplayName(source *AudioSource) string {
 			return fmt.Sprintf("Audio File: %s", filepath.Base(source.SafeString))
 		}
 		return "Audio File"
+	case SourceTypePush:
+		return fmt.Sprintf("Remote Recorder: %s", strings.TrimPrefix(source.SafeString, ingestConnectionPrefix))
+	case SourceTypeBrowser:
+		return fmt.Sprintf("Field Session: %s", strings.TrimPrefix(source.SafeString, liveConnectionPrefix))
 	default:
 		return "Audio Source"
 	}
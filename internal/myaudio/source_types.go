@@ -18,6 +18,8 @@ const (
 	SourceTypeUDP       SourceType = "udp"        // UDP/RTP streams
 	SourceTypeAudioCard SourceType = "audio_card" // Local audio devices
 	SourceTypeFile      SourceType = "file"       // Audio files
+	SourceTypePush      SourceType = "push"       // Audio pushed by remote recorders over HTTP
+	SourceTypeBrowser   SourceType = "browser"    // Microphone of a browser streamed over WebSocket
 	SourceTypeUnknown   SourceType = "unknown"    // Used when type needs to be detected
 )
 
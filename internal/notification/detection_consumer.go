@@ -84,7 +84,7 @@ func (c *DetectionNotificationConsumer) ProcessDetectionEvent(event events.Detec
 	title, message := c.renderTitleAndMessage(event, templateData)
 	notification := c.buildDetectionNotification(event, title, message, templateData)
 
-	if err := c.service.store.Save(notification); err != nil {
+	if err := c.service.getStore().Save(notification); err != nil {
 		c.logger.Error("failed to save new species notification",
 			logger.String("species", event.GetSpeciesName()),
 			logger.Error(err))
@@ -221,11 +221,14 @@ func (c *DetectionNotificationConsumer) buildDetectionNotification(event events.
 	// Expose all TemplateData fields with bg_ prefix for use in provider templates
 	notification = EnrichWithTemplateData(notification, templateData)
 
-	// Add note_id from event metadata if available
+	// Add note_id and clip name from event metadata if available
 	if eventMetadata := event.GetMetadata(); eventMetadata != nil {
 		if noteID, ok := eventMetadata["note_id"]; ok {
 			notification = notification.WithMetadata("note_id", noteID)
 		}
+		if clipName, ok := eventMetadata[MetadataKeyClipName].(string); ok && clipName != "" {
+			notification = notification.WithMetadata(MetadataKeyClipName, clipName)
+		}
 	}
 
 	return notification
@@ -0,0 +1,295 @@
+// Package digest builds scheduled daily and weekly summary notifications from
+// the detection datastore and delivers them through the notification service,
+// which forwards them to the configured push providers.
+package digest
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/notification"
+)
+
+// Period identifies the time span a digest covers.
+type Period string
+
+const (
+	// PeriodDaily covers the delivery day.
+	PeriodDaily Period = "daily"
+	// PeriodWeekly covers the seven days ending on the delivery day.
+	PeriodWeekly Period = "weekly"
+)
+
+// maxNewSpecies caps the number of new arrivals listed in a digest.
+const maxNewSpecies = 20
+
+// DataSource is the subset of datastore.Interface used to build digests.
+type DataSource interface {
+	GetSpeciesSummaryData(ctx context.Context, startDate, endDate string) ([]datastore.SpeciesSummaryData, error)
+	GetNewSpeciesDetections(ctx context.Context, startDate, endDate string, limit, offset int) ([]datastore.NewSpeciesData, error)
+	GetHourlyDistribution(ctx context.Context, startDate, endDate string, species string) ([]datastore.HourlyDistributionData, error)
+	GetHourlyWeather(date string) ([]datastore.HourlyWeather, error)
+}
+
+// NotificationLister provides recent notifications for the system health section.
+type NotificationLister interface {
+	List(filter *notification.FilterOptions) ([]*notification.Notification, error)
+}
+
+// SpeciesCount is a species with its detection count in the digest period.
+type SpeciesCount struct {
+	CommonName     string
+	ScientificName string
+	Count          int
+}
+
+// NewArrival is a species detected for the first time during the digest period.
+type NewArrival struct {
+	CommonName     string
+	ScientificName string
+	FirstSeenDate  string
+}
+
+// WeatherSummary aggregates hourly weather observations over the digest period.
+// Temperatures are in degrees Celsius as stored by the weather providers.
+type WeatherSummary struct {
+	MinTemperature float64
+	MaxTemperature float64
+	Conditions     string // most frequently observed weather description
+}
+
+// HealthSummary counts system problems reported during the digest period.
+type HealthSummary struct {
+	Errors   int
+	Warnings int
+}
+
+// Healthy reports whether no errors were raised during the period.
+func (h HealthSummary) Healthy() bool {
+	return h.Errors == 0
+}
+
+// Summary is the data passed to digest templates.
+type Summary struct {
+	Period           Period
+	PeriodLabel      string // "Daily" or "Weekly"
+	StartDate        string // YYYY-MM-DD
+	EndDate          string // YYYY-MM-DD
+	TotalDetections  int
+	SpeciesCount     int
+	TopSpecies       []SpeciesCount
+	NewSpecies       []NewArrival
+	BusiestHour      int // hour of day 0-23, -1 when there were no detections
+	BusiestHourCount int
+	Weather          *WeatherSummary // nil when no weather data is available
+	Health           HealthSummary
+	DashboardURL     string
+}
+
+// HasDetections reports whether anything was detected during the period.
+func (s *Summary) HasDetections() bool {
+	return s.TotalDetections > 0
+}
+
+// Builder collects digest data from the datastore and notification history.
+type Builder struct {
+	source        DataSource
+	notifications NotificationLister // optional
+	topSpecies    int
+	dashboardURL  string
+	logger        logger.Logger
+}
+
+// NewBuilder creates a digest builder. notifications may be nil, in which case
+// the health section reports no problems.
+func NewBuilder(source DataSource, notifications NotificationLister, topSpecies int, dashboardURL string) *Builder {
+	return &Builder{
+		source:        source,
+		notifications: notifications,
+		topSpecies:    topSpecies,
+		dashboardURL:  dashboardURL,
+		logger:        GetLogger(),
+	}
+}
+
+// PeriodRange returns the inclusive start and end dates a digest delivered at
+// the given time covers.
+func PeriodRange(period Period, at time.Time) (start, end time.Time) {
+	end = time.Date(at.Year(), at.Month(), at.Day(), 0, 0, 0, 0, at.Location())
+	start = end
+	if period == PeriodWeekly {
+		start = end.AddDate(0, 0, -6)
+	}
+	return start, end
+}
+
+// Build assembles the digest summary for the period ending on the day of at.
+// Detection statistics are required; weather and health sections are best
+// effort and are omitted when their data cannot be loaded.
+func (b *Builder) Build(ctx context.Context, period Period, at time.Time) (*Summary, error) {
+	start, end := PeriodRange(period, at)
+	startDate, endDate := start.Format(time.DateOnly), end.Format(time.DateOnly)
+
+	summary := &Summary{
+		Period:       period,
+		PeriodLabel:  periodLabel(period),
+		StartDate:    startDate,
+		EndDate:      endDate,
+		BusiestHour:  -1,
+		DashboardURL: b.dashboardURL,
+	}
+
+	species, err := b.source.GetSpeciesSummaryData(ctx, startDate, endDate)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("notification").
+			Category(errors.CategoryDatabase).
+			Context("operation", "digest_species_summary").
+			Context("period", string(period)).
+			Build()
+	}
+	b.addSpecies(summary, species)
+
+	arrivals, err := b.source.GetNewSpeciesDetections(ctx, startDate, endDate, maxNewSpecies, 0)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("notification").
+			Category(errors.CategoryDatabase).
+			Context("operation", "digest_new_species").
+			Context("period", string(period)).
+			Build()
+	}
+	for _, a := range arrivals {
+		summary.NewSpecies = append(summary.NewSpecies, NewArrival{
+			CommonName:     a.CommonName,
+			ScientificName: a.ScientificName,
+			FirstSeenDate:  a.FirstSeenDate,
+		})
+	}
+
+	hourly, err := b.source.GetHourlyDistribution(ctx, startDate, endDate, "")
+	if err != nil {
+		return nil, errors.New(err).
+			Component("notification").
+			Category(errors.CategoryDatabase).
+			Context("operation", "digest_hourly_distribution").
+			Context("period", string(period)).
+			Build()
+	}
+	for _, h := range hourly {
+		if h.Count > summary.BusiestHourCount {
+			summary.BusiestHour = h.Hour
+			summary.BusiestHourCount = h.Count
+		}
+	}
+
+	summary.Weather = b.buildWeather(start, end)
+	summary.Health = b.buildHealth(start)
+
+	return summary, nil
+}
+
+// addSpecies fills totals and the top species list.
+func (b *Builder) addSpecies(summary *Summary, species []datastore.SpeciesSummaryData) {
+	for i := range species {
+		summary.TotalDetections += species[i].Count
+	}
+	summary.SpeciesCount = len(species)
+
+	sorted := slices.Clone(species)
+	slices.SortStableFunc(sorted, func(a, b datastore.SpeciesSummaryData) int {
+		return cmp.Compare(b.Count, a.Count)
+	})
+	limit := min(b.topSpecies, len(sorted))
+	for i := range limit {
+		summary.TopSpecies = append(summary.TopSpecies, SpeciesCount{
+			CommonName:     sorted[i].CommonName,
+			ScientificName: sorted[i].ScientificName,
+			Count:          sorted[i].Count,
+		})
+	}
+}
+
+// buildWeather aggregates stored hourly weather for each day in the period.
+func (b *Builder) buildWeather(start, end time.Time) *WeatherSummary {
+	var summary *WeatherSummary
+	conditions := make(map[string]int)
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		observations, err := b.source.GetHourlyWeather(day.Format(time.DateOnly))
+		if err != nil {
+			b.logger.Debug("no weather data for digest day",
+				logger.String("date", day.Format(time.DateOnly)),
+				logger.Error(err))
+			continue
+		}
+		for i := range observations {
+			obs := &observations[i]
+			if summary == nil {
+				summary = &WeatherSummary{MinTemperature: obs.Temperature, MaxTemperature: obs.Temperature}
+			}
+			summary.MinTemperature = min(summary.MinTemperature, obs.Temperature)
+			summary.MaxTemperature = max(summary.MaxTemperature, obs.Temperature)
+			if obs.WeatherDesc != "" {
+				conditions[obs.WeatherDesc]++
+			}
+		}
+	}
+
+	if summary != nil {
+		best := 0
+		for desc, count := range conditions {
+			// Break ties alphabetically so the output is deterministic
+			if count > best || (count == best && desc < summary.Conditions) {
+				summary.Conditions = desc
+				best = count
+			}
+		}
+	}
+	return summary
+}
+
+// buildHealth counts error and warning notifications raised since start.
+func (b *Builder) buildHealth(start time.Time) HealthSummary {
+	var health HealthSummary
+	if b.notifications == nil {
+		return health
+	}
+
+	recent, err := b.notifications.List(&notification.FilterOptions{
+		Types: []notification.Type{notification.TypeError, notification.TypeWarning},
+		Since: &start,
+	})
+	if err != nil {
+		b.logger.Warn("failed to load notifications for digest health summary", logger.Error(err))
+		return health
+	}
+
+	for _, n := range recent {
+		switch n.Type {
+		case notification.TypeError:
+			health.Errors++
+		case notification.TypeWarning:
+			health.Warnings++
+		}
+	}
+	return health
+}
+
+// periodLabel returns the human readable label for a period.
+func periodLabel(period Period) string {
+	if period == PeriodWeekly {
+		return "Weekly"
+	}
+	return "Daily"
+}
+
+// GetLogger returns the digest logger.
+func GetLogger() logger.Logger {
+	return logger.Global().Module("notification").With(logger.String("component", "digest"))
+}
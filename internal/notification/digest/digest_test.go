@@ -0,0 +1,208 @@
+package digest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/notification"
+)
+
+// fakeSource is an in-memory DataSource for digest tests.
+type fakeSource struct {
+	species    []datastore.SpeciesSummaryData
+	arrivals   []datastore.NewSpeciesData
+	hourly     []datastore.HourlyDistributionData
+	weather    map[string][]datastore.HourlyWeather
+	speciesErr error
+
+	gotStart, gotEnd string
+}
+
+func (f *fakeSource) GetSpeciesSummaryData(_ context.Context, startDate, endDate string) ([]datastore.SpeciesSummaryData, error) {
+	f.gotStart, f.gotEnd = startDate, endDate
+	return f.species, f.speciesErr
+}
+
+func (f *fakeSource) GetNewSpeciesDetections(_ context.Context, _, _ string, _, _ int) ([]datastore.NewSpeciesData, error) {
+	return f.arrivals, nil
+}
+
+func (f *fakeSource) GetHourlyDistribution(_ context.Context, _, _, _ string) ([]datastore.HourlyDistributionData, error) {
+	return f.hourly, nil
+}
+
+func (f *fakeSource) GetHourlyWeather(date string) ([]datastore.HourlyWeather, error) {
+	obs, ok := f.weather[date]
+	if !ok {
+		return nil, errors.NewStd("no weather")
+	}
+	return obs, nil
+}
+
+// fakeLister returns a fixed notification list.
+type fakeLister struct {
+	items []*notification.Notification
+}
+
+func (f *fakeLister) List(_ *notification.FilterOptions) ([]*notification.Notification, error) {
+	return f.items, nil
+}
+
+func newTestSource() *fakeSource {
+	return &fakeSource{
+		species: []datastore.SpeciesSummaryData{
+			{CommonName: "Great Tit", ScientificName: "Parus major", Count: 12},
+			{CommonName: "Eurasian Blackbird", ScientificName: "Turdus merula", Count: 30},
+			{CommonName: "European Robin", ScientificName: "Erithacus rubecula", Count: 5},
+		},
+		arrivals: []datastore.NewSpeciesData{
+			{CommonName: "Common Swift", ScientificName: "Apus apus", FirstSeenDate: "2026-05-10"},
+		},
+		hourly: []datastore.HourlyDistributionData{
+			{Hour: 5, Count: 20},
+			{Hour: 6, Count: 15},
+		},
+		weather: map[string][]datastore.HourlyWeather{
+			"2026-05-10": {
+				{Temperature: 8.5, WeatherDesc: "clear sky"},
+				{Temperature: 17.2, WeatherDesc: "few clouds"},
+				{Temperature: 14.0, WeatherDesc: "clear sky"},
+			},
+		},
+	}
+}
+
+func TestPeriodRange(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2026, 5, 10, 21, 0, 0, 0, time.UTC)
+
+	start, end := PeriodRange(PeriodDaily, at)
+	assert.Equal(t, "2026-05-10", start.Format(time.DateOnly))
+	assert.Equal(t, "2026-05-10", end.Format(time.DateOnly))
+
+	start, end = PeriodRange(PeriodWeekly, at)
+	assert.Equal(t, "2026-05-04", start.Format(time.DateOnly))
+	assert.Equal(t, "2026-05-10", end.Format(time.DateOnly))
+}
+
+func TestBuilder_Build(t *testing.T) {
+	t.Parallel()
+
+	source := newTestSource()
+	lister := &fakeLister{items: []*notification.Notification{
+		notification.NewNotification(notification.TypeError, notification.PriorityHigh, "e", ""),
+		notification.NewNotification(notification.TypeWarning, notification.PriorityMedium, "w1", ""),
+		notification.NewNotification(notification.TypeWarning, notification.PriorityMedium, "w2", ""),
+	}}
+	builder := NewBuilder(source, lister, 2, "https://birds.example")
+
+	summary, err := builder.Build(t.Context(), PeriodDaily, time.Date(2026, 5, 10, 21, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.Equal(t, "2026-05-10", source.gotStart)
+	assert.Equal(t, "2026-05-10", source.gotEnd)
+	assert.Equal(t, 47, summary.TotalDetections)
+	assert.Equal(t, 3, summary.SpeciesCount)
+	require.Len(t, summary.TopSpecies, 2)
+	assert.Equal(t, "Eurasian Blackbird", summary.TopSpecies[0].CommonName)
+	assert.Equal(t, "Great Tit", summary.TopSpecies[1].CommonName)
+	require.Len(t, summary.NewSpecies, 1)
+	assert.Equal(t, "Common Swift", summary.NewSpecies[0].CommonName)
+	assert.Equal(t, 5, summary.BusiestHour)
+	assert.Equal(t, 20, summary.BusiestHourCount)
+
+	require.NotNil(t, summary.Weather)
+	assert.InDelta(t, 8.5, summary.Weather.MinTemperature, 0.001)
+	assert.InDelta(t, 17.2, summary.Weather.MaxTemperature, 0.001)
+	assert.Equal(t, "clear sky", summary.Weather.Conditions)
+
+	assert.Equal(t, 1, summary.Health.Errors)
+	assert.Equal(t, 2, summary.Health.Warnings)
+	assert.False(t, summary.Health.Healthy())
+}
+
+func TestBuilder_BuildEmptyPeriod(t *testing.T) {
+	t.Parallel()
+
+	builder := NewBuilder(&fakeSource{}, nil, 5, "")
+	summary, err := builder.Build(t.Context(), PeriodWeekly, time.Date(2026, 5, 10, 21, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	assert.False(t, summary.HasDetections())
+	assert.Equal(t, -1, summary.BusiestHour)
+	assert.Nil(t, summary.Weather)
+	assert.True(t, summary.Health.Healthy())
+}
+
+func TestBuilder_BuildDatastoreError(t *testing.T) {
+	t.Parallel()
+
+	builder := NewBuilder(&fakeSource{speciesErr: errors.NewStd("db down")}, nil, 5, "")
+	_, err := builder.Build(t.Context(), PeriodDaily, time.Now())
+	require.Error(t, err)
+}
+
+func TestRender_DefaultTemplates(t *testing.T) {
+	t.Parallel()
+
+	summary, err := NewBuilder(newTestSource(), nil, 3, "https://birds.example").
+		Build(t.Context(), PeriodDaily, time.Date(2026, 5, 10, 21, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+
+	tests := []struct {
+		format   string
+		contains []string
+	}{
+		{conf.DigestFormatText, []string{"47 detections of 3 species", "Busiest hour: 05:00", "- Eurasian Blackbird: 30", "Common Swift (Apus apus)", "clear sky, 8.5 to 17.2 °C", "System health: OK"}},
+		{conf.DigestFormatMarkdown, []string{"**47** detections", "_Apus apus_", "[Open dashboard](https://birds.example)"}},
+		{conf.DigestFormatHTML, []string{"<li>Eurasian Blackbird: 30</li>", `<a href="https://birds.example">`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+			title, message, err := Render(summary, tt.format, "", "")
+			require.NoError(t, err)
+			assert.Equal(t, "Daily bird digest: 47 detections", title)
+			for _, want := range tt.contains {
+				assert.Contains(t, message, want)
+			}
+		})
+	}
+}
+
+func TestRender_HTMLEscapesSpeciesNames(t *testing.T) {
+	t.Parallel()
+
+	summary := &Summary{
+		PeriodLabel:     "Daily",
+		TotalDetections: 1,
+		SpeciesCount:    1,
+		BusiestHour:     -1,
+		TopSpecies:      []SpeciesCount{{CommonName: "<script>alert(1)</script>", Count: 1}},
+	}
+	_, message, err := Render(summary, conf.DigestFormatHTML, "", "")
+	require.NoError(t, err)
+	assert.NotContains(t, message, "<script>")
+	assert.Contains(t, message, "&lt;script&gt;")
+}
+
+func TestRender_CustomTemplates(t *testing.T) {
+	t.Parallel()
+
+	summary := &Summary{PeriodLabel: "Weekly", TotalDetections: 3, SpeciesCount: 2}
+	title, message, err := Render(summary, conf.DigestFormatText, "{{.PeriodLabel}}", "{{.SpeciesCount}} species")
+	require.NoError(t, err)
+	assert.Equal(t, "Weekly", title)
+	assert.Equal(t, "2 species", message)
+
+	_, _, err = Render(summary, conf.DigestFormatText, "", "{{.Missing")
+	require.Error(t, err)
+}
@@ -0,0 +1,187 @@
+package digest
+
+import (
+	"context"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/notification"
+)
+
+const (
+	// buildTimeout bounds datastore queries for a single digest.
+	buildTimeout = 2 * time.Minute
+	// digestExpiry is how long a digest stays in the notification history.
+	digestExpiry = 7 * 24 * time.Hour
+)
+
+// Scheduler delivers digests on their configured daily and weekly schedules.
+type Scheduler struct {
+	settings *conf.Settings
+	builder  *Builder
+	service  *notification.Service
+	logger   logger.Logger
+	now      func() time.Time
+}
+
+// NewScheduler creates a digest scheduler that reads detection data from source
+// and publishes digests through the notification service.
+func NewScheduler(settings *conf.Settings, source DataSource, service *notification.Service) *Scheduler {
+	baseURL := settings.Security.GetBaseURL(settings.WebServer.Port)
+	return &Scheduler{
+		settings: settings,
+		builder:  NewBuilder(source, service, settings.Notification.Digest.TopSpecies, baseURL),
+		service:  service,
+		logger:   GetLogger(),
+		now:      time.Now,
+	}
+}
+
+// NextRun returns the first delivery time of schedule strictly after now.
+func NextRun(period Period, schedule *conf.DigestScheduleSettings, now time.Time) (time.Time, error) {
+	hour, minute, err := schedule.DeliveryTime()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if period == PeriodWeekly {
+		weekday, err := schedule.DeliveryWeekday()
+		if err != nil {
+			return time.Time{}, err
+		}
+		daysAhead := (int(weekday) - int(now.Weekday()) + 7) % 7
+		next := time.Date(now.Year(), now.Month(), now.Day()+daysAhead, hour, minute, 0, 0, now.Location())
+		if !next.After(now) {
+			next = time.Date(now.Year(), now.Month(), now.Day()+daysAhead+7, hour, minute, 0, 0, now.Location())
+		}
+		return next, nil
+	}
+
+	next := time.Date(now.Year(), now.Month(), now.Day(), hour, minute, 0, 0, now.Location())
+	if !next.After(now) {
+		next = time.Date(now.Year(), now.Month(), now.Day()+1, hour, minute, 0, 0, now.Location())
+	}
+	return next, nil
+}
+
+// nextDelivery returns the earliest upcoming delivery time after now and every
+// period due at that time. ok is false when no valid schedule is enabled.
+func (s *Scheduler) nextDelivery(now time.Time) (next time.Time, periods []Period, ok bool) {
+	schedules := []struct {
+		period   Period
+		schedule *conf.DigestScheduleSettings
+	}{
+		{PeriodDaily, &s.settings.Notification.Digest.Daily},
+		{PeriodWeekly, &s.settings.Notification.Digest.Weekly},
+	}
+
+	for _, entry := range schedules {
+		if !entry.schedule.Enabled {
+			continue
+		}
+		at, err := NextRun(entry.period, entry.schedule, now)
+		if err != nil {
+			s.logger.Error("invalid digest schedule, skipping",
+				logger.String("period", string(entry.period)),
+				logger.Error(err))
+			continue
+		}
+		switch {
+		case !ok || at.Before(next):
+			next, periods, ok = at, []Period{entry.period}, true
+		case at.Equal(next):
+			periods = append(periods, entry.period)
+		}
+	}
+	return next, periods, ok
+}
+
+// Start delivers digests until quitChan is closed. It blocks and is intended
+// to run in its own goroutine.
+func (s *Scheduler) Start(quitChan <-chan struct{}) {
+	last := s.now()
+	for {
+		now := s.now()
+		if now.Before(last) {
+			now = last
+		}
+
+		next, periods, ok := s.nextDelivery(now)
+		if !ok {
+			s.logger.Info("no digest schedule enabled, scheduler stopping")
+			return
+		}
+
+		s.logger.Debug("next digest scheduled",
+			logger.String("at", next.Format(time.RFC3339)),
+			logger.Int("period_count", len(periods)))
+
+		timer := time.NewTimer(next.Sub(s.now()))
+		select {
+		case <-quitChan:
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		for _, period := range periods {
+			ctx, cancel := context.WithTimeout(context.Background(), buildTimeout)
+			if err := s.Send(ctx, period, next); err != nil {
+				s.logger.Error("failed to send digest",
+					logger.String("period", string(period)),
+					logger.Error(err))
+			}
+			cancel()
+		}
+		last = next
+	}
+}
+
+// Send builds the digest for the period ending on the day of at and publishes it.
+func (s *Scheduler) Send(ctx context.Context, period Period, at time.Time) error {
+	n, err := s.Build(ctx, period, at)
+	if err != nil {
+		return err
+	}
+	if err := s.service.CreateWithMetadata(n); err != nil {
+		return errors.New(err).
+			Component("notification").
+			Category(errors.CategorySystem).
+			Context("operation", "digest_publish").
+			Context("period", string(period)).
+			Build()
+	}
+
+	s.logger.Info("digest sent",
+		logger.String("period", string(period)),
+		logger.String("end_date", at.Format(time.DateOnly)))
+	return nil
+}
+
+// Build renders the digest notification without publishing it.
+func (s *Scheduler) Build(ctx context.Context, period Period, at time.Time) (*notification.Notification, error) {
+	summary, err := s.builder.Build(ctx, period, at)
+	if err != nil {
+		return nil, err
+	}
+
+	format := s.settings.Notification.Digest.Format
+	tmpl := s.settings.Notification.Templates.Digest
+	title, message, err := Render(summary, format, tmpl.Title, tmpl.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	return notification.NewNotification(notification.TypeInfo, notification.PriorityMedium, title, message).
+		WithComponent("digest").
+		WithMetadata("digest_period", string(period)).
+		WithMetadata("digest_format", format).
+		WithMetadata("digest_start_date", summary.StartDate).
+		WithMetadata("digest_end_date", summary.EndDate).
+		WithMetadata("total_detections", summary.TotalDetections).
+		WithMetadata("species_count", summary.SpeciesCount).
+		WithMetadata("new_species_count", len(summary.NewSpecies)).
+		WithExpiry(digestExpiry), nil
+}
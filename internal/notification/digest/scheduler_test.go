@@ -0,0 +1,76 @@
+package digest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+func TestNextRun(t *testing.T) {
+	t.Parallel()
+
+	// Wednesday
+	now := time.Date(2026, 5, 13, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		period   Period
+		schedule conf.DigestScheduleSettings
+		want     time.Time
+	}{
+		{"daily later today", PeriodDaily, conf.DigestScheduleSettings{Time: "21:00"}, time.Date(2026, 5, 13, 21, 0, 0, 0, time.UTC)},
+		{"daily already passed", PeriodDaily, conf.DigestScheduleSettings{Time: "07:15"}, time.Date(2026, 5, 14, 7, 15, 0, 0, time.UTC)},
+		{"daily exactly now", PeriodDaily, conf.DigestScheduleSettings{Time: "10:30"}, time.Date(2026, 5, 14, 10, 30, 0, 0, time.UTC)},
+		{"weekly later this week", PeriodWeekly, conf.DigestScheduleSettings{Time: "21:00", Weekday: "Sunday"}, time.Date(2026, 5, 17, 21, 0, 0, 0, time.UTC)},
+		{"weekly today later", PeriodWeekly, conf.DigestScheduleSettings{Time: "21:00", Weekday: "wed"}, time.Date(2026, 5, 13, 21, 0, 0, 0, time.UTC)},
+		{"weekly today passed", PeriodWeekly, conf.DigestScheduleSettings{Time: "08:00", Weekday: "wednesday"}, time.Date(2026, 5, 20, 8, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := NextRun(tt.period, &tt.schedule, now)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNextRun_InvalidSchedule(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	_, err := NextRun(PeriodDaily, &conf.DigestScheduleSettings{Time: "25:00"}, now)
+	require.Error(t, err)
+
+	_, err = NextRun(PeriodWeekly, &conf.DigestScheduleSettings{Time: "20:00", Weekday: "someday"}, now)
+	require.Error(t, err)
+}
+
+func TestScheduler_NextDeliveryGroupsSimultaneousPeriods(t *testing.T) {
+	t.Parallel()
+
+	settings := &conf.Settings{}
+	settings.Notification.Digest.Daily = conf.DigestScheduleSettings{Enabled: true, Time: "21:00"}
+	settings.Notification.Digest.Weekly = conf.DigestScheduleSettings{Enabled: true, Time: "21:00", Weekday: "sunday"}
+	s := &Scheduler{settings: settings, logger: GetLogger()}
+
+	// Sunday morning: both digests are due at 21:00 the same day
+	next, periods, ok := s.nextDelivery(time.Date(2026, 5, 17, 9, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 5, 17, 21, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, []Period{PeriodDaily, PeriodWeekly}, periods)
+
+	// Monday: only the daily digest is due next
+	_, periods, ok = s.nextDelivery(time.Date(2026, 5, 18, 9, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, []Period{PeriodDaily}, periods)
+
+	settings.Notification.Digest.Daily.Enabled = false
+	settings.Notification.Digest.Weekly.Enabled = false
+	_, _, ok = s.nextDelivery(time.Now())
+	assert.False(t, ok)
+}
@@ -0,0 +1,187 @@
+package digest
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"strings"
+	"text/template"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+)
+
+// defaultTitleTemplate is used when no title template is configured.
+const defaultTitleTemplate = "{{.PeriodLabel}} bird digest: {{.TotalDetections}} detections"
+
+// defaultTextTemplate is the built-in plain text digest body.
+const defaultTextTemplate = `{{.PeriodLabel}} summary {{if eq .StartDate .EndDate}}for {{.EndDate}}{{else}}{{.StartDate}} to {{.EndDate}}{{end}}
+{{if .HasDetections}}
+{{.TotalDetections}} detections of {{.SpeciesCount}} species.
+{{- if ge .BusiestHour 0}}
+Busiest hour: {{printf "%02d:00" .BusiestHour}} ({{.BusiestHourCount}} detections)
+{{- end}}
+
+Top species:
+{{- range .TopSpecies}}
+- {{.CommonName}}: {{.Count}}
+{{- end}}
+{{- else}}
+No detections.
+{{- end}}
+{{- if .NewSpecies}}
+
+New arrivals:
+{{- range .NewSpecies}}
+- {{.CommonName}} ({{.ScientificName}})
+{{- end}}
+{{- end}}
+{{- with .Weather}}
+
+Weather: {{if .Conditions}}{{.Conditions}}, {{end}}{{printf "%.1f" .MinTemperature}} to {{printf "%.1f" .MaxTemperature}} °C
+{{- end}}
+
+System health: {{if .Health.Healthy}}OK{{else}}{{.Health.Errors}} errors{{end}}{{if .Health.Warnings}}, {{.Health.Warnings}} warnings{{end}}
+{{- if .DashboardURL}}
+
+{{.DashboardURL}}
+{{- end}}`
+
+// defaultMarkdownTemplate is the built-in Markdown digest body.
+const defaultMarkdownTemplate = `**{{.PeriodLabel}} summary {{if eq .StartDate .EndDate}}for {{.EndDate}}{{else}}{{.StartDate}} to {{.EndDate}}{{end}}**
+{{if .HasDetections}}
+**{{.TotalDetections}}** detections of **{{.SpeciesCount}}** species.
+{{- if ge .BusiestHour 0}}
+Busiest hour: **{{printf "%02d:00" .BusiestHour}}** ({{.BusiestHourCount}} detections)
+{{- end}}
+
+**Top species**
+{{- range .TopSpecies}}
+- {{.CommonName}}: {{.Count}}
+{{- end}}
+{{- else}}
+_No detections._
+{{- end}}
+{{- if .NewSpecies}}
+
+**New arrivals**
+{{- range .NewSpecies}}
+- {{.CommonName}} (_{{.ScientificName}}_)
+{{- end}}
+{{- end}}
+{{- with .Weather}}
+
+**Weather:** {{if .Conditions}}{{.Conditions}}, {{end}}{{printf "%.1f" .MinTemperature}} to {{printf "%.1f" .MaxTemperature}} °C
+{{- end}}
+
+**System health:** {{if .Health.Healthy}}OK{{else}}{{.Health.Errors}} errors{{end}}{{if .Health.Warnings}}, {{.Health.Warnings}} warnings{{end}}
+{{- if .DashboardURL}}
+
+[Open dashboard]({{.DashboardURL}})
+{{- end}}`
+
+// defaultHTMLTemplate is the built-in HTML digest body.
+const defaultHTMLTemplate = `<h3>{{.PeriodLabel}} summary {{if eq .StartDate .EndDate}}for {{.EndDate}}{{else}}{{.StartDate}} to {{.EndDate}}{{end}}</h3>
+{{- if .HasDetections}}
+<p><b>{{.TotalDetections}}</b> detections of <b>{{.SpeciesCount}}</b> species.
+{{- if ge .BusiestHour 0}}<br>Busiest hour: <b>{{printf "%02d:00" .BusiestHour}}</b> ({{.BusiestHourCount}} detections){{end}}</p>
+<h4>Top species</h4>
+<ul>
+{{- range .TopSpecies}}
+<li>{{.CommonName}}: {{.Count}}</li>
+{{- end}}
+</ul>
+{{- else}}
+<p><i>No detections.</i></p>
+{{- end}}
+{{- if .NewSpecies}}
+<h4>New arrivals</h4>
+<ul>
+{{- range .NewSpecies}}
+<li>{{.CommonName}} (<i>{{.ScientificName}}</i>)</li>
+{{- end}}
+</ul>
+{{- end}}
+{{- with .Weather}}
+<p><b>Weather:</b> {{if .Conditions}}{{.Conditions}}, {{end}}{{printf "%.1f" .MinTemperature}} to {{printf "%.1f" .MaxTemperature}} °C</p>
+{{- end}}
+<p><b>System health:</b> {{if .Health.Healthy}}OK{{else}}{{.Health.Errors}} errors{{end}}{{if .Health.Warnings}}, {{.Health.Warnings}} warnings{{end}}</p>
+{{- if .DashboardURL}}
+<p><a href="{{.DashboardURL}}">Open dashboard</a></p>
+{{- end}}`
+
+// DefaultMessageTemplate returns the built-in message template for a format.
+// Unknown formats fall back to plain text.
+func DefaultMessageTemplate(format string) string {
+	switch format {
+	case conf.DigestFormatMarkdown:
+		return defaultMarkdownTemplate
+	case conf.DigestFormatHTML:
+		return defaultHTMLTemplate
+	default:
+		return defaultTextTemplate
+	}
+}
+
+// Render produces the digest title and message. Empty templates select the
+// built-in defaults. HTML messages are rendered with contextual escaping;
+// titles are always plain text.
+func Render(summary *Summary, format, titleTmpl, messageTmpl string) (title, message string, err error) {
+	if strings.TrimSpace(titleTmpl) == "" {
+		titleTmpl = defaultTitleTemplate
+	}
+	if strings.TrimSpace(messageTmpl) == "" {
+		messageTmpl = DefaultMessageTemplate(format)
+	}
+
+	title, err = renderText("digest_title", titleTmpl, summary)
+	if err != nil {
+		return "", "", err
+	}
+
+	if format == conf.DigestFormatHTML {
+		message, err = renderHTML("digest_message", messageTmpl, summary)
+	} else {
+		message, err = renderText("digest_message", messageTmpl, summary)
+	}
+	if err != nil {
+		return "", "", err
+	}
+
+	return strings.TrimSpace(title), strings.TrimSpace(message), nil
+}
+
+// renderText executes a text/template.
+func renderText(name, tmplStr string, data any) (string, error) {
+	tmpl, err := template.New(name).Parse(tmplStr)
+	if err != nil {
+		return "", templateError(err, name, "parse")
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", templateError(err, name, "execute")
+	}
+	return buf.String(), nil
+}
+
+// renderHTML executes an html/template.
+func renderHTML(name, tmplStr string, data any) (string, error) {
+	tmpl, err := htmltemplate.New(name).Parse(tmplStr)
+	if err != nil {
+		return "", templateError(err, name, "parse")
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", templateError(err, name, "execute")
+	}
+	return buf.String(), nil
+}
+
+// templateError wraps a template failure with context.
+func templateError(err error, name, stage string) error {
+	return errors.New(err).
+		Component("notification").
+		Category(errors.CategoryValidation).
+		Context("operation", "digest_template_"+stage).
+		Context("template", name).
+		Build()
+}
@@ -0,0 +1,397 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"mime"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/securefs"
+	"github.com/tphakala/birdnet-go/internal/spectrogram"
+)
+
+const (
+	defaultAttachmentMaxSizeKB = 5120             // Per-file limit when max_size_kb is not set
+	attachmentResolveTimeout   = 90 * time.Second // Upper bound for clip wait, rendering and compression
+	attachmentClipWaitTimeout  = 30 * time.Second // How long to wait for the clip to be written after the event
+	attachmentPollInterval     = 500 * time.Millisecond
+	attachmentPreRenderWait    = 10 * time.Second // Grace period for the background pre-renderer
+	attachmentCacheTTL         = 5 * time.Minute  // Resolved attachments are shared by providers for this long
+	attachmentAudioSeconds     = "10"             // Compressed clip length passed to ffmpeg
+	attachmentAudioBitrate     = "64k"
+)
+
+// AttachmentKind identifies what an attachment contains.
+type AttachmentKind string
+
+const (
+	AttachmentKindSpectrogram AttachmentKind = "spectrogram"
+	AttachmentKindAudio       AttachmentKind = "audio"
+)
+
+// Attachment is a file sent alongside a push notification.
+type Attachment struct {
+	Kind        AttachmentKind
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// AttachmentSender is implemented by providers that can upload files with a notification.
+// Providers return ErrAttachmentsUnsupported when a particular target cannot take files,
+// in which case the dispatcher falls back to a regular Send with links only.
+type AttachmentSender interface {
+	SendWithAttachments(ctx context.Context, n *Notification, attachments []Attachment) error
+}
+
+// ErrAttachmentsUnsupported indicates the provider target cannot receive attachments.
+var ErrAttachmentsUnsupported = errors.NewStd("attachments not supported by provider target")
+
+// UndeliveredError reports the targets of a provider that a send did not reach.
+// Targets left out were delivered, so a retry only resends to Targets.
+type UndeliveredError struct {
+	Targets []string
+	Err     error
+}
+
+func (e *UndeliveredError) Error() string { return e.Err.Error() }
+func (e *UndeliveredError) Unwrap() error { return e.Err }
+
+// targetedAttachmentSender is implemented by providers that can resend a
+// notification to the targets reported by an UndeliveredError.
+type targetedAttachmentSender interface {
+	sendWithAttachmentsTo(ctx context.Context, n *Notification, attachments []Attachment, targets []string) error
+}
+
+// attachmentEntry caches the files resolved for one notification so that several
+// providers attaching the same detection only render and compress it once.
+type attachmentEntry struct {
+	created         time.Time
+	spectrogramOnce sync.Once
+	spectrogram     *Attachment
+	audioOnce       sync.Once
+	audio           *Attachment
+}
+
+// attachmentResolver loads detection spectrograms and audio clips from the export directory.
+type attachmentResolver struct {
+	settings   *conf.Settings
+	exportDir  string
+	ffmpegPath string
+	log        logger.Logger
+
+	clipWait      time.Duration
+	preRenderWait time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*attachmentEntry
+}
+
+// newAttachmentResolver creates a resolver rooted at the audio export directory.
+func newAttachmentResolver(settings *conf.Settings, log logger.Logger) (*attachmentResolver, error) {
+	exportDir, err := filepath.Abs(settings.Realtime.Audio.Export.Path)
+	if err != nil {
+		return nil, errors.New(err).
+			Component("notification").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "attachment_export_dir").
+			Build()
+	}
+	return &attachmentResolver{
+		settings:      settings,
+		exportDir:     exportDir,
+		ffmpegPath:    settings.Realtime.Audio.FfmpegPath,
+		log:           log,
+		clipWait:      attachmentClipWaitTimeout,
+		preRenderWait: attachmentPreRenderWait,
+		entries:       make(map[string]*attachmentEntry),
+	}, nil
+}
+
+// attachmentsEnabled reports whether the provider wants any attachment.
+func attachmentsEnabled(cfg *conf.PushAttachmentConfig) bool {
+	return cfg != nil && (cfg.Spectrogram || cfg.AudioClip)
+}
+
+// attachmentMaxBytes returns the per-file size limit in bytes.
+func attachmentMaxBytes(cfg *conf.PushAttachmentConfig) int {
+	if cfg.MaxSizeKB > 0 {
+		return cfg.MaxSizeKB * 1024
+	}
+	return defaultAttachmentMaxSizeKB * 1024
+}
+
+// resolve returns the attachments requested by cfg for a detection notification.
+// Anything that cannot be produced or exceeds the size limit is left out; the
+// provider then sends only what is available, or falls back to links.
+func (r *attachmentResolver) resolve(ctx context.Context, notif *Notification, cfg *conf.PushAttachmentConfig) []Attachment {
+	if r == nil || !attachmentsEnabled(cfg) || notif == nil || notif.Type != TypeDetection {
+		return nil
+	}
+	clipName, _ := notif.Metadata[MetadataKeyClipName].(string)
+	if clipName == "" {
+		return nil
+	}
+	clipPath, err := r.clipPath(clipName)
+	if err != nil {
+		r.log.Warn("rejected attachment clip path",
+			logger.String("notification_id", notif.ID),
+			logger.Error(err))
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, attachmentResolveTimeout)
+	defer cancel()
+
+	entry := r.entry(notif.ID)
+	limit := attachmentMaxBytes(cfg)
+	var result []Attachment
+
+	if cfg.Spectrogram {
+		entry.spectrogramOnce.Do(func() {
+			entry.spectrogram = r.loadSpectrogram(ctx, clipPath)
+		})
+		result = r.appendWithinLimit(result, entry.spectrogram, limit, notif.ID)
+	}
+	if cfg.AudioClip {
+		entry.audioOnce.Do(func() {
+			entry.audio = r.loadAudio(ctx, clipPath)
+		})
+		result = r.appendWithinLimit(result, entry.audio, limit, notif.ID)
+	}
+	return result
+}
+
+// appendWithinLimit appends a copy of a when it exists and fits the size limit.
+func (r *attachmentResolver) appendWithinLimit(list []Attachment, a *Attachment, limit int, notifID string) []Attachment {
+	if a == nil {
+		return list
+	}
+	if len(a.Data) > limit {
+		r.log.Info("attachment exceeds size limit, sending link instead",
+			logger.String("notification_id", notifID),
+			logger.String("kind", string(a.Kind)),
+			logger.Int("size_bytes", len(a.Data)),
+			logger.Int("limit_bytes", limit))
+		return list
+	}
+	return append(list, *a)
+}
+
+// clipPath resolves a clip name relative to the export directory, refusing paths
+// that would escape it.
+func (r *attachmentResolver) clipPath(clipName string) (string, error) {
+	rel := filepath.Clean(filepath.FromSlash(clipName))
+	if filepath.IsAbs(rel) {
+		// Older records may store the clip with the export path prefix
+		var err error
+		if rel, err = filepath.Rel(r.exportDir, rel); err != nil {
+			return "", err
+		}
+	} else if prefix := filepath.Clean(r.settings.Realtime.Audio.Export.Path); prefix != "." {
+		rel = strings.TrimPrefix(rel, prefix+string(filepath.Separator))
+	}
+	if !filepath.IsLocal(rel) {
+		return "", errors.Newf("clip path escapes export directory").
+			Component("notification").
+			Category(errors.CategoryValidation).
+			Context("operation", "attachment_clip_path").
+			Build()
+	}
+	return filepath.Join(r.exportDir, rel), nil
+}
+
+// entry returns the cache entry for a notification, pruning expired entries.
+func (r *attachmentResolver) entry(notifID string) *attachmentEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	for id, e := range r.entries {
+		if now.Sub(e.created) > attachmentCacheTTL {
+			delete(r.entries, id)
+		}
+	}
+	e, ok := r.entries[notifID]
+	if !ok {
+		e = &attachmentEntry{created: now}
+		r.entries[notifID] = e
+	}
+	return e
+}
+
+// waitForClip waits until the clip exists and its size has stopped changing.
+// The clip is written after the detection event is published, so it is usually
+// not on disk yet when the notification is dispatched.
+func (r *attachmentResolver) waitForClip(ctx context.Context, clipPath string) error {
+	return pollFile(ctx, clipPath, r.clipWait)
+}
+
+// pollFile waits for path to exist with a stable, non-zero size.
+func pollFile(ctx context.Context, path string, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	lastSize := int64(-1)
+	for {
+		if info, err := os.Stat(path); err == nil && info.Size() > 0 {
+			if info.Size() == lastSize {
+				return nil
+			}
+			lastSize = info.Size()
+		}
+		if time.Now().After(deadline) {
+			return errors.Newf("file not ready within %s", timeout).
+				Component("notification").
+				Category(errors.CategoryFileIO).
+				Context("operation", "attachment_wait_file").
+				Build()
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(attachmentPollInterval):
+		}
+	}
+}
+
+// loadSpectrogram returns the clip's spectrogram, waiting briefly for the
+// pre-renderer and generating it on demand when it is still missing.
+func (r *attachmentResolver) loadSpectrogram(ctx context.Context, clipPath string) *Attachment {
+	specPath, err := spectrogram.BuildSpectrogramPath(clipPath)
+	if err != nil {
+		r.log.Debug("cannot derive spectrogram path", logger.Error(err))
+		return nil
+	}
+
+	if _, err := os.Stat(specPath); err != nil {
+		if err := r.waitForClip(ctx, clipPath); err != nil {
+			r.log.Debug("audio clip not available for spectrogram attachment",
+				logger.String("clip", filepath.Base(clipPath)),
+				logger.Error(err))
+			return nil
+		}
+		if r.settings.Realtime.Dashboard.Spectrogram.IsPreRenderEnabled() {
+			_ = pollFile(ctx, specPath, r.preRenderWait)
+		}
+		if _, err := os.Stat(specPath); err != nil {
+			if err := r.generateSpectrogram(ctx, clipPath, specPath); err != nil {
+				r.log.Warn("failed to generate spectrogram for attachment",
+					logger.String("clip", filepath.Base(clipPath)),
+					logger.Error(err))
+				return nil
+			}
+		}
+	}
+
+	data, err := os.ReadFile(specPath) //nolint:gosec // G304: path is confined to the export directory by clipPath
+	if err != nil {
+		r.log.Debug("failed to read spectrogram", logger.Error(err))
+		return nil
+	}
+	return &Attachment{
+		Kind:        AttachmentKindSpectrogram,
+		Name:        filepath.Base(specPath),
+		ContentType: "image/png",
+		Data:        data,
+	}
+}
+
+// generateSpectrogram renders the spectrogram using the dashboard size and style settings.
+func (r *attachmentResolver) generateSpectrogram(ctx context.Context, clipPath, specPath string) error {
+	sfs, err := securefs.New(r.exportDir)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = sfs.Close() }()
+
+	width, err := spectrogram.SizeToPixels(r.settings.Realtime.Dashboard.Spectrogram.Size)
+	if err != nil {
+		width, _ = spectrogram.SizeToPixels("md")
+	}
+	gen := spectrogram.NewGenerator(r.settings, sfs, nil)
+	return gen.GenerateFromFile(ctx, clipPath, specPath, width, r.settings.Realtime.Dashboard.Spectrogram.Raw)
+}
+
+// loadAudio returns a short MP3 excerpt of the clip, or the original clip when
+// ffmpeg is not available.
+func (r *attachmentResolver) loadAudio(ctx context.Context, clipPath string) *Attachment {
+	if err := r.waitForClip(ctx, clipPath); err != nil {
+		r.log.Debug("audio clip not available for attachment",
+			logger.String("clip", filepath.Base(clipPath)),
+			logger.Error(err))
+		return nil
+	}
+
+	base := strings.TrimSuffix(filepath.Base(clipPath), filepath.Ext(clipPath))
+	if r.ffmpegPath != "" {
+		data, err := r.compressClip(ctx, clipPath)
+		if err == nil {
+			return &Attachment{
+				Kind:        AttachmentKindAudio,
+				Name:        base + ".mp3",
+				ContentType: "audio/mpeg",
+				Data:        data,
+			}
+		}
+		r.log.Debug("audio clip compression failed, attaching original clip",
+			logger.String("clip", filepath.Base(clipPath)),
+			logger.Error(err))
+	}
+
+	data, err := os.ReadFile(clipPath) //nolint:gosec // G304: path is confined to the export directory by clipPath
+	if err != nil {
+		r.log.Debug("failed to read audio clip", logger.Error(err))
+		return nil
+	}
+	contentType := mime.TypeByExtension(filepath.Ext(clipPath))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return &Attachment{
+		Kind:        AttachmentKindAudio,
+		Name:        filepath.Base(clipPath),
+		ContentType: contentType,
+		Data:        data,
+	}
+}
+
+// compressClip encodes the first seconds of the clip as mono MP3 on stdout.
+func (r *attachmentResolver) compressClip(ctx context.Context, clipPath string) ([]byte, error) {
+	args := []string{
+		"-hide_banner", "-loglevel", "error",
+		"-i", clipPath,
+		"-t", attachmentAudioSeconds,
+		"-ac", "1",
+		"-c:a", "libmp3lame",
+		"-b:a", attachmentAudioBitrate,
+		"-f", "mp3",
+		"pipe:1",
+	}
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, r.ffmpegPath, args...) //nolint:gosec // G204: ffmpegPath is from validated settings, args built internally
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, errors.New(err).
+			Component("notification").
+			Category(errors.CategoryAudio).
+			Context("operation", "attachment_compress_clip").
+			Context("stderr", strings.TrimSpace(stderr.String())).
+			Build()
+	}
+	if stdout.Len() == 0 {
+		return nil, errors.Newf("ffmpeg produced no output").
+			Component("notification").
+			Category(errors.CategoryAudio).
+			Context("operation", "attachment_compress_clip").
+			Build()
+	}
+	return stdout.Bytes(), nil
+}
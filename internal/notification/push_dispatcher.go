@@ -16,6 +16,7 @@ import (
 	"github.com/tphakala/birdnet-go/internal/errors"
 	"github.com/tphakala/birdnet-go/internal/logger"
 	"github.com/tphakala/birdnet-go/internal/observability/metrics"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
 	"golang.org/x/sync/semaphore"
 )
 
@@ -55,6 +56,7 @@ type pushDispatcher struct {
 	healthChecker     *HealthChecker
 	concurrencySem    *semaphore.Weighted // Limits concurrent dispatch goroutines to prevent resource exhaustion
 	maxConcurrentJobs int64               // Maximum concurrent dispatches - dynamically calculated as max(defaultMaxConcurrentJobs, providers*jobsPerProvider)
+	attachments       *attachmentResolver // nil unless a provider has attachments enabled
 	// rateLimiter removed - now per-provider in enhancedProvider
 }
 
@@ -66,6 +68,8 @@ type enhancedProvider struct {
 	rateLimiter    *PushRateLimiter // Per-provider rate limiting
 	filter         conf.PushFilterConfig
 	name           string
+	quietHours     *quietHours // nil when quiet hours are disabled
+	attachments    conf.PushAttachmentConfig
 }
 
 var (
@@ -196,6 +200,7 @@ func (d *pushDispatcher) start() error {
 
 	go d.runDispatchLoop(ctx, ch, service)
 	d.startHealthChecker(ctx)
+	d.startQuietHoursLoop(ctx)
 
 	d.log.Info("push dispatcher started",
 		logger.Int("providers", len(d.providers)),
@@ -240,6 +245,10 @@ func (d *pushDispatcher) dispatch(ctx context.Context, notif *Notification) {
 			continue
 		}
 
+		if d.holdForQuietHours(ep, notif) {
+			continue
+		}
+
 		if !d.acquireSemaphoreSlot(ctx, ep, notif) {
 			continue
 		}
@@ -253,6 +262,9 @@ func (d *pushDispatcher) shouldDispatchToProvider(ep *enhancedProvider, notif *N
 	if !ep.prov.IsEnabled() || !ep.prov.SupportsType(notif.Type) {
 		return false
 	}
+	if target, ok := notif.Metadata[MetadataKeyPushTarget].(string); ok && target != "" && target != ep.name {
+		return false
+	}
 	return d.matchesFilter(ep, notif)
 }
 
@@ -336,7 +348,12 @@ func (d *pushDispatcher) dispatchEnhanced(ctx context.Context, notif *Notificati
 		defer d.metrics.DecDispatchActive()
 	}
 
-	d.retryLoop(ctx, notif, ep)
+	var attachments []Attachment
+	if attachmentsEnabled(&ep.attachments) {
+		attachments = d.attachments.resolve(ctx, notif, &ep.attachments)
+	}
+
+	d.retryLoop(ctx, notif, ep, attachments)
 }
 
 // checkRateLimit checks if notification is rate limited.
@@ -357,13 +374,16 @@ func (d *pushDispatcher) checkRateLimit(ep *enhancedProvider, notif *Notificatio
 }
 
 // retryLoop handles the retry logic for sending notifications.
-func (d *pushDispatcher) retryLoop(ctx context.Context, notif *Notification, ep *enhancedProvider) {
+// Attachments are resolved once by the caller and reused for every attempt.
+// Retries skip the targets an earlier attempt already delivered to.
+func (d *pushDispatcher) retryLoop(ctx context.Context, notif *Notification, ep *enhancedProvider, attachments []Attachment) {
 	attempts := 0
 	notifType := string(notif.Type)
+	var pending []string
 
 	for {
 		attempts++
-		duration, err := d.attemptSend(ctx, notif, ep)
+		duration, err := d.attemptSend(ctx, notif, ep, attachments, pending)
 
 		// Record metrics
 		d.recordAttemptMetrics(ep.name, notifType, err, duration, attempts)
@@ -385,6 +405,11 @@ func (d *pushDispatcher) retryLoop(ctx context.Context, notif *Notification, ep
 			return
 		}
 
+		var undelivered *UndeliveredError
+		if errors.As(err, &undelivered) {
+			pending = undelivered.Targets
+		}
+
 		// Wait for retry delay
 		if !d.waitForRetry(ctx, ep.name, attempts) {
 			return
@@ -392,8 +417,9 @@ func (d *pushDispatcher) retryLoop(ctx context.Context, notif *Notification, ep
 	}
 }
 
-// attemptSend attempts to send a notification.
-func (d *pushDispatcher) attemptSend(ctx context.Context, notif *Notification, ep *enhancedProvider) (time.Duration, error) {
+// attemptSend attempts to send a notification. A non-empty pending limits the
+// send to those targets of the provider.
+func (d *pushDispatcher) attemptSend(ctx context.Context, notif *Notification, ep *enhancedProvider, attachments []Attachment, pending []string) (time.Duration, error) {
 	attemptCtx := ctx
 	var cancel context.CancelFunc
 	if deadline := d.defaultTimeout; deadline > 0 {
@@ -409,14 +435,36 @@ func (d *pushDispatcher) attemptSend(ctx context.Context, notif *Notification, e
 	var err error
 	if ep.circuitBreaker != nil {
 		err = ep.circuitBreaker.Call(attemptCtx, func(ctx context.Context) error {
-			return ep.prov.Send(ctx, notif)
+			return d.sendWithAttachments(ctx, notif, ep, attachments, pending)
 		})
 	} else {
-		err = ep.prov.Send(attemptCtx, notif)
+		err = d.sendWithAttachments(attemptCtx, notif, ep, attachments, pending)
 	}
 	return time.Since(timer), err
 }
 
+// sendWithAttachments sends through the provider's attachment support when there
+// are attachments, falling back to a regular Send (links only) when the provider
+// or its target cannot take files. Pending targets left by an earlier attempt
+// are resent alone when the provider supports it.
+func (d *pushDispatcher) sendWithAttachments(ctx context.Context, notif *Notification, ep *enhancedProvider, attachments []Attachment, pending []string) error {
+	sender, ok := ep.prov.(AttachmentSender)
+	if !ok || len(attachments) == 0 {
+		return ep.prov.Send(ctx, notif)
+	}
+	if targeted, ok := ep.prov.(targetedAttachmentSender); ok && len(pending) > 0 {
+		return targeted.sendWithAttachmentsTo(ctx, notif, attachments, pending)
+	}
+	err := sender.SendWithAttachments(ctx, notif, attachments)
+	if errors.Is(err, ErrAttachmentsUnsupported) {
+		d.log.Debug("provider target does not support attachments, sending links only",
+			logger.String("provider", ep.name),
+			logger.String("id", notif.ID))
+		return ep.prov.Send(ctx, notif)
+	}
+	return err
+}
+
 // recordAttemptMetrics records metrics for an attempt.
 func (d *pushDispatcher) recordAttemptMetrics(providerName, notifType string, err error, duration time.Duration, attempts int) {
 	if d.metrics == nil {
@@ -972,14 +1020,39 @@ func (e *providerError) Unwrap() error { return e.Err }
 // initializeEnhancedProviders creates enhanced providers with circuit breakers and metrics.
 func (d *pushDispatcher) initializeEnhancedProviders(settings *conf.Settings, notificationMetrics *metrics.NotificationMetrics) []enhancedProvider {
 	cbConfig := d.getCircuitBreakerConfig(settings)
+	sun := suncalc.NewSunCalc(settings.BirdNET.Latitude, settings.BirdNET.Longitude)
 	var enhanced []enhancedProvider
 
 	for i := range settings.Notification.Push.Providers {
 		pc := &settings.Notification.Push.Providers[i]
 		ep := d.buildEnhancedProvider(pc, cbConfig, settings, notificationMetrics)
-		if ep != nil {
-			enhanced = append(enhanced, *ep)
+		if ep == nil {
+			continue
+		}
+		if pc.QuietHours.Enabled {
+			qh, err := newQuietHours(&pc.QuietHours, sun)
+			if err != nil {
+				d.log.Error("invalid quiet hours configuration, quiet hours disabled",
+					logger.String("name", ep.name),
+					logger.Error(err))
+			}
+			ep.quietHours = qh
+		}
+		if attachmentsEnabled(&pc.Attachments) {
+			if d.attachments == nil {
+				resolver, err := newAttachmentResolver(settings, d.log)
+				if err != nil {
+					d.log.Error("failed to initialize notification attachments, sending links only",
+						logger.String("name", ep.name),
+						logger.Error(err))
+				}
+				d.attachments = resolver
+			}
+			if d.attachments != nil {
+				ep.attachments = pc.Attachments
+			}
 		}
+		enhanced = append(enhanced, *ep)
 	}
 
 	return enhanced
@@ -449,6 +449,26 @@ func TestPushDispatcher_shouldDispatchToProvider(t *testing.T) {
 			notif:    &Notification{Type: TypeInfo},
 			expected: false,
 		},
+		{
+			name: "push_target_matches",
+			provider: &fakeProvider{
+				name:    "test",
+				enabled: true,
+				types:   map[Type]bool{TypeInfo: true},
+			},
+			notif:    &Notification{Type: TypeInfo, Metadata: map[string]any{MetadataKeyPushTarget: "test"}},
+			expected: true,
+		},
+		{
+			name: "push_target_other_provider",
+			provider: &fakeProvider{
+				name:    "test",
+				enabled: true,
+				types:   map[Type]bool{TypeInfo: true},
+			},
+			notif:    &Notification{Type: TypeInfo, Metadata: map[string]any{MetadataKeyPushTarget: "pager"}},
+			expected: false,
+		},
 	}
 
 	for _, tt := range tests {
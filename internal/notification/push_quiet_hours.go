@@ -0,0 +1,245 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
+)
+
+const (
+	// maxQuietHoursQueue caps notifications held per provider during quiet hours.
+	// The oldest entries are discarded when the cap is reached.
+	maxQuietHoursQueue = 100
+	// quietHoursCheckInterval is how often held notifications are released after a window ends.
+	quietHoursCheckInterval = time.Minute
+	// maxRollupLines limits how many notification titles a rollup message lists.
+	maxRollupLines = 20
+
+	filterReasonQuietHours = "quiet_hours" // Notification withheld by provider quiet hours
+)
+
+// quietHours holds a provider's quiet window and the notifications withheld during it.
+type quietHours struct {
+	start          conf.QuietHoursTime
+	end            conf.QuietHoursTime
+	action         string
+	exemptCritical bool
+	sun            *suncalc.SunCalc // required only for sun-relative boundaries
+
+	mu       sync.Mutex
+	queue    []*Notification
+	overflow int // notifications discarded because the queue was full
+}
+
+// newQuietHours builds a quiet hours window from configuration.
+func newQuietHours(cfg *conf.QuietHoursConfig, sun *suncalc.SunCalc) (*quietHours, error) {
+	start, err := conf.ParseQuietHoursTime(cfg.Start)
+	if err != nil {
+		return nil, err
+	}
+	end, err := conf.ParseQuietHoursTime(cfg.End)
+	if err != nil {
+		return nil, err
+	}
+	if (start.IsSunRelative() || end.IsSunRelative()) && sun == nil {
+		return nil, fmt.Errorf("sun-relative quiet hours require a configured location")
+	}
+	return &quietHours{
+		start:          start,
+		end:            end,
+		action:         cfg.QuietHoursAction(),
+		exemptCritical: cfg.ExemptCritical,
+		sun:            sun,
+	}, nil
+}
+
+// resolve returns the wall-clock time of a boundary on the given day.
+func (q *quietHours) resolve(t conf.QuietHoursTime, day time.Time) (time.Time, error) {
+	return q.sun.ResolveTime(t, day)
+}
+
+// isActive reports whether now falls inside the quiet window. A window whose
+// end is not after its start spans midnight. If a boundary cannot be resolved
+// the window is treated as inactive so notifications are never lost silently.
+func (q *quietHours) isActive(now time.Time) bool {
+	start, err := q.resolve(q.start, now)
+	if err != nil {
+		GetLogger().Warn("failed to resolve quiet hours start", logger.Error(err))
+		return false
+	}
+	end, err := q.resolve(q.end, now)
+	if err != nil {
+		GetLogger().Warn("failed to resolve quiet hours end", logger.Error(err))
+		return false
+	}
+
+	if start.Equal(end) {
+		return false
+	}
+	if start.Before(end) {
+		return !now.Before(start) && now.Before(end)
+	}
+	return !now.Before(start) || now.Before(end)
+}
+
+// hold withholds notif when the quiet window is active. It returns false when
+// the notification should be delivered now.
+func (q *quietHours) hold(notif *Notification, now time.Time) bool {
+	if q.exemptCritical && notif.Priority == PriorityCritical {
+		return false
+	}
+	if !q.isActive(now) {
+		return false
+	}
+	if q.action == conf.QuietHoursActionDrop {
+		return true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.queue) >= maxQuietHoursQueue {
+		q.queue = q.queue[1:]
+		q.overflow++
+	}
+	q.queue = append(q.queue, notif)
+	return true
+}
+
+// release returns the withheld notifications once the quiet window has ended.
+// For the rollup action the notifications are combined into a single summary.
+func (q *quietHours) release(now time.Time) []*Notification {
+	q.mu.Lock()
+	if len(q.queue) == 0 {
+		q.mu.Unlock()
+		return nil
+	}
+	q.mu.Unlock()
+
+	if q.isActive(now) {
+		return nil
+	}
+
+	q.mu.Lock()
+	held, overflow := q.queue, q.overflow
+	q.queue, q.overflow = nil, 0
+	q.mu.Unlock()
+
+	if q.action == conf.QuietHoursActionRollup {
+		return []*Notification{buildQuietHoursRollup(held, overflow)}
+	}
+	return held
+}
+
+// buildQuietHoursRollup combines notifications withheld during quiet hours into one summary.
+func buildQuietHoursRollup(held []*Notification, overflow int) *Notification {
+	total := len(held) + overflow
+	notifType := held[0].Type
+	priority := held[0].Priority
+	var lines []string
+	for i, n := range held {
+		if n.Type != notifType {
+			notifType = TypeInfo
+		}
+		if priorityRank(n.Priority) > priorityRank(priority) {
+			priority = n.Priority
+		}
+		if i < maxRollupLines {
+			lines = append(lines, "- "+n.Title)
+		}
+	}
+	if hidden := total - len(lines); hidden > 0 {
+		lines = append(lines, fmt.Sprintf("...and %d more", hidden))
+	}
+
+	title := fmt.Sprintf("%d notifications during quiet hours", total)
+	return NewNotification(notifType, priority, title, strings.Join(lines, "\n")).
+		WithComponent("notification").
+		WithMetadata("quiet_hours_rollup", true).
+		WithMetadata("rollup_count", total)
+}
+
+// priorityRank orders priorities from low to critical.
+func priorityRank(p Priority) int {
+	switch p {
+	case PriorityCritical:
+		return 3
+	case PriorityHigh:
+		return 2
+	case PriorityMedium:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// holdForQuietHours withholds notif from the provider while its quiet window is
+// active, recording the decision. It returns true when the notification must not
+// be sent now.
+func (d *pushDispatcher) holdForQuietHours(ep *enhancedProvider, notif *Notification) bool {
+	if ep.quietHours == nil || !ep.quietHours.hold(notif, time.Now()) {
+		return false
+	}
+	if d.metrics != nil {
+		d.metrics.RecordFilterRejection(ep.name, filterReasonQuietHours)
+	}
+	logDebug(d.log, "notification withheld by quiet hours",
+		logger.String("provider", ep.name),
+		logger.String("notification_id", notif.ID),
+		logger.String("action", ep.quietHours.action))
+	return true
+}
+
+// startQuietHoursLoop starts releasing withheld notifications if any provider uses quiet hours.
+func (d *pushDispatcher) startQuietHoursLoop(ctx context.Context) {
+	for i := range d.providers {
+		if d.providers[i].quietHours != nil {
+			go d.runQuietHoursLoop(ctx)
+			return
+		}
+	}
+}
+
+// runQuietHoursLoop periodically delivers notifications held during quiet hours
+// once each provider's window has ended.
+func (d *pushDispatcher) runQuietHoursLoop(ctx context.Context) {
+	ticker := time.NewTicker(quietHoursCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			d.releaseQuietHours(ctx, now)
+		}
+	}
+}
+
+// releaseQuietHours dispatches held notifications for providers whose quiet window has ended.
+func (d *pushDispatcher) releaseQuietHours(ctx context.Context, now time.Time) {
+	for i := range d.providers {
+		ep := &d.providers[i]
+		if ep.quietHours == nil {
+			continue
+		}
+		released := ep.quietHours.release(now)
+		if len(released) == 0 {
+			continue
+		}
+		d.log.Info("quiet hours ended, delivering held notifications",
+			logger.String("provider", ep.name),
+			logger.String("action", ep.quietHours.action),
+			logger.Int("count", len(released)))
+		for _, notif := range released {
+			if !d.acquireSemaphoreSlot(ctx, ep, notif) {
+				continue
+			}
+			d.spawnDispatchGoroutine(ctx, ep, notif)
+		}
+	}
+}
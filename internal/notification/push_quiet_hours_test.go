@@ -0,0 +1,126 @@
+package notification
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/suncalc"
+)
+
+func mustQuietHours(t *testing.T, cfg conf.QuietHoursConfig) *quietHours {
+	t.Helper()
+	cfg.Enabled = true
+	q, err := newQuietHours(&cfg, suncalc.NewSunCalc(60.17, 24.94))
+	require.NoError(t, err)
+	return q
+}
+
+func TestQuietHours_IsActive(t *testing.T) {
+	t.Parallel()
+
+	day := func(hour, minute int) time.Time {
+		return time.Date(2026, 6, 1, hour, minute, 0, 0, time.Local)
+	}
+
+	overnight := mustQuietHours(t, conf.QuietHoursConfig{Start: "22:00", End: "06:30"})
+	assert.True(t, overnight.isActive(day(23, 0)))
+	assert.True(t, overnight.isActive(day(3, 0)))
+	assert.True(t, overnight.isActive(day(22, 0)), "start is inclusive")
+	assert.False(t, overnight.isActive(day(6, 30)), "end is exclusive")
+	assert.False(t, overnight.isActive(day(12, 0)))
+
+	daytime := mustQuietHours(t, conf.QuietHoursConfig{Start: "09:00", End: "17:00"})
+	assert.True(t, daytime.isActive(day(12, 0)))
+	assert.False(t, daytime.isActive(day(20, 0)))
+
+	empty := mustQuietHours(t, conf.QuietHoursConfig{Start: "09:00", End: "09:00"})
+	assert.False(t, empty.isActive(day(9, 0)))
+}
+
+func TestQuietHours_SunRelative(t *testing.T) {
+	t.Parallel()
+
+	sun := suncalc.NewSunCalc(60.17, 24.94)
+	q, err := newQuietHours(&conf.QuietHoursConfig{Enabled: true, Start: "sunset+30m", End: "sunrise"}, sun)
+	require.NoError(t, err)
+
+	date := time.Date(2026, 3, 20, 12, 0, 0, 0, time.Local)
+	events, err := sun.GetSunEventTimes(date)
+	require.NoError(t, err)
+
+	assert.False(t, q.isActive(events.Sunset.Add(15*time.Minute)), "before offset sunset")
+	assert.True(t, q.isActive(events.Sunset.Add(45*time.Minute)))
+	assert.True(t, q.isActive(events.Sunrise.Add(-time.Minute)))
+	assert.False(t, q.isActive(events.Sunrise.Add(time.Minute)))
+
+	_, err = newQuietHours(&conf.QuietHoursConfig{Enabled: true, Start: "sunset", End: "06:00"}, nil)
+	require.Error(t, err, "sun-relative boundaries need a sun calculator")
+}
+
+func TestQuietHours_Actions(t *testing.T) {
+	t.Parallel()
+
+	night := time.Date(2026, 6, 1, 23, 0, 0, 0, time.Local)
+	morning := time.Date(2026, 6, 2, 7, 0, 0, 0, time.Local)
+
+	t.Run("drop", func(t *testing.T) {
+		t.Parallel()
+		q := mustQuietHours(t, conf.QuietHoursConfig{Start: "22:00", End: "06:00", Action: conf.QuietHoursActionDrop})
+		assert.True(t, q.hold(NewNotification(TypeDetection, PriorityHigh, "Owl", ""), night))
+		assert.Empty(t, q.release(morning))
+	})
+
+	t.Run("delay", func(t *testing.T) {
+		t.Parallel()
+		q := mustQuietHours(t, conf.QuietHoursConfig{Start: "22:00", End: "06:00", Action: conf.QuietHoursActionDelay})
+		assert.True(t, q.hold(NewNotification(TypeDetection, PriorityHigh, "Owl", ""), night))
+		assert.True(t, q.hold(NewNotification(TypeDetection, PriorityHigh, "Nightjar", ""), night))
+		assert.False(t, q.hold(NewNotification(TypeDetection, PriorityHigh, "Robin", ""), morning))
+
+		assert.Empty(t, q.release(night), "nothing is released while the window is active")
+		released := q.release(morning)
+		require.Len(t, released, 2)
+		assert.Equal(t, "Owl", released[0].Title)
+		assert.Equal(t, "Nightjar", released[1].Title)
+		assert.Empty(t, q.release(morning), "queue is cleared after release")
+	})
+
+	t.Run("rollup", func(t *testing.T) {
+		t.Parallel()
+		q := mustQuietHours(t, conf.QuietHoursConfig{Start: "22:00", End: "06:00"})
+		q.hold(NewNotification(TypeDetection, PriorityMedium, "Owl", ""), night)
+		q.hold(NewNotification(TypeWarning, PriorityHigh, "Disk filling up", ""), night)
+
+		released := q.release(morning)
+		require.Len(t, released, 1)
+		rollup := released[0]
+		assert.Equal(t, "2 notifications during quiet hours", rollup.Title)
+		assert.Equal(t, TypeInfo, rollup.Type, "mixed types roll up as info")
+		assert.Equal(t, PriorityHigh, rollup.Priority, "rollup uses highest held priority")
+		assert.Contains(t, rollup.Message, "- Owl")
+		assert.Contains(t, rollup.Message, "- Disk filling up")
+		assert.Equal(t, 2, rollup.Metadata["rollup_count"])
+	})
+
+	t.Run("critical exempt", func(t *testing.T) {
+		t.Parallel()
+		q := mustQuietHours(t, conf.QuietHoursConfig{Start: "22:00", End: "06:00", ExemptCritical: true})
+		assert.False(t, q.hold(NewNotification(TypeError, PriorityCritical, "Database down", ""), night))
+		assert.True(t, q.hold(NewNotification(TypeError, PriorityHigh, "Stream lost", ""), night))
+	})
+
+	t.Run("queue cap", func(t *testing.T) {
+		t.Parallel()
+		q := mustQuietHours(t, conf.QuietHoursConfig{Start: "22:00", End: "06:00"})
+		for range maxQuietHoursQueue + 5 {
+			q.hold(NewNotification(TypeDetection, PriorityLow, "Owl", ""), night)
+		}
+		released := q.release(morning)
+		require.Len(t, released, 1)
+		assert.Equal(t, maxQuietHoursQueue+5, released[0].Metadata["rollup_count"])
+		assert.Contains(t, released[0].Message, "...and 85 more")
+	})
+}
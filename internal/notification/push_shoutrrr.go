@@ -7,11 +7,13 @@ import (
 	"log"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	shoutrrr "github.com/nicholas-fedor/shoutrrr"
 	router "github.com/nicholas-fedor/shoutrrr/pkg/router"
 	stypes "github.com/nicholas-fedor/shoutrrr/pkg/types"
+	"github.com/tphakala/birdnet-go/internal/httpclient"
 	"github.com/tphakala/birdnet-go/internal/privacy"
 )
 
@@ -20,14 +22,17 @@ import (
 const shoutrrrDefaultTimeout = 30 * time.Second
 
 // ShoutrrrProvider sends via nicholas-fedor/shoutrrr
-// Creates a single sender for multiple URLs.
+// Creates one sender per URL so a failed URL can be resent on its own.
 type ShoutrrrProvider struct {
 	name    string
 	enabled bool
 	urls    []string
 	types   map[string]bool
-	sender  *router.ServiceRouter
+	senders map[string]*router.ServiceRouter // keyed by URL
 	timeout time.Duration
+
+	clientOnce sync.Once
+	client     *httpclient.Client // HTTP client for native attachment uploads
 }
 
 func NewShoutrrrProvider(name string, enabled bool, urls, supportedTypes []string, timeout time.Duration) *ShoutrrrProvider {
@@ -66,47 +71,70 @@ func (s *ShoutrrrProvider) ValidateConfig() error {
 	if len(s.urls) == 0 {
 		return fmt.Errorf("at least one URL is required")
 	}
-	// Build sender to validate URLs
-	sender, err := shoutrrr.CreateSender(s.urls...)
-	if err != nil {
-		// Wrap error to sanitize any URLs that may contain tokens/credentials
-		return privacy.WrapError(err)
-	}
-	s.sender = sender
-	// Apply configured timeout; fall back to a sane default to prevent indefinite hangs
-	if s.timeout > 0 {
-		s.sender.Timeout = s.timeout
-	} else {
-		s.sender.Timeout = shoutrrrDefaultTimeout
+	// Build senders to validate URLs
+	senders := make(map[string]*router.ServiceRouter, len(s.urls))
+	for _, raw := range s.urls {
+		sender, err := shoutrrr.CreateSender(raw)
+		if err != nil {
+			// Wrap error to sanitize any URLs that may contain tokens/credentials
+			return privacy.WrapError(err)
+		}
+		// Apply configured timeout; fall back to a sane default to prevent indefinite hangs
+		sender.Timeout = s.effectiveTimeout()
+		sender.SetLogger(log.New(io.Discard, "", 0))
+		senders[raw] = sender
 	}
-	s.sender.SetLogger(log.New(io.Discard, "", 0))
+	s.senders = senders
 	return nil
 }
 
 func (s *ShoutrrrProvider) Send(ctx context.Context, n *Notification) error {
-	if s.sender == nil {
+	if s.senders == nil {
 		return fmt.Errorf("shoutrrr sender not initialized")
 	}
 	_ = ctx // router handles its own timeouts
 
-	body := n.Message
+	failed := s.sendText(n, s.urls)
+	for _, raw := range s.urls {
+		if err := failed[raw]; err != nil {
+			// Wrap error to sanitize any URLs that may contain tokens/credentials
+			return privacy.WrapError(err)
+		}
+	}
+	return nil
+}
+
+// sendText sends the text notification to the given URLs concurrently and
+// returns the error of each URL that failed.
+func (s *ShoutrrrProvider) sendText(n *Notification, urls []string) map[string]error {
 	params := stypes.Params{}
 	if n.Title != "" {
 		params.SetTitle(n.Title)
 	}
-	errs := s.sender.Send(body, &params)
-	if len(errs) > 0 {
-		var firstErr error
-		for _, e := range errs {
-			if e != nil {
-				firstErr = e
-				break
-			}
-		}
-		if firstErr != nil {
-			// Wrap error to sanitize any URLs that may contain tokens/credentials
-			return privacy.WrapError(firstErr)
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed = map[string]error{}
+	)
+	for _, raw := range urls {
+		sender, ok := s.senders[raw]
+		if !ok {
+			failed[raw] = fmt.Errorf("shoutrrr sender not initialized")
+			continue
 		}
+		wg.Go(func() {
+			p := params
+			for _, err := range sender.Send(n.Message, &p) {
+				if err != nil {
+					mu.Lock()
+					failed[raw] = err
+					mu.Unlock()
+					return
+				}
+			}
+		})
 	}
-	return nil
+	wg.Wait()
+	return failed
 }
@@ -0,0 +1,382 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/httpclient"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/privacy"
+)
+
+// API endpoints used for native attachment uploads. Variables so tests can
+// point them at a local server.
+var (
+	telegramAPIBase = "https://api.telegram.org"
+	pushoverAPIURL  = "https://api.pushover.net/1/messages.json"
+	discordAPIBase  = "https://discord.com/api/webhooks"
+)
+
+const (
+	telegramCaptionLimit = 1024 // Telegram media caption limit in characters
+	discordContentLimit  = 2000 // Discord message content limit in characters
+)
+
+// attachmentUploader uploads a notification with attachments to one shoutrrr URL.
+type attachmentUploader func(ctx context.Context, client *httpclient.Client, u *url.URL, n *Notification, attachments []Attachment) error
+
+// attachmentUploaders lists the shoutrrr services with native attachment support.
+var attachmentUploaders = map[string]attachmentUploader{
+	"ntfy":     uploadNtfy,
+	"telegram": uploadTelegram,
+	"pushover": uploadPushover,
+	"discord":  uploadDiscord,
+}
+
+// SendWithAttachments uploads attachments to ntfy, Telegram, Pushover and Discord
+// URLs directly through their HTTP APIs, since shoutrrr only sends text. Other URLs
+// in the same provider receive the regular text message. A failed upload falls back
+// to the text message for that URL so the notification is never lost. URLs that
+// received neither are reported in an UndeliveredError.
+func (s *ShoutrrrProvider) SendWithAttachments(ctx context.Context, n *Notification, attachments []Attachment) error {
+	if !slices.ContainsFunc(s.urls, func(raw string) bool { return nativeUploader(raw) != nil }) {
+		return ErrAttachmentsUnsupported
+	}
+	return s.sendWithAttachmentsTo(ctx, n, attachments, s.urls)
+}
+
+// sendWithAttachmentsTo sends the notification with attachments to a subset of
+// the provider URLs.
+func (s *ShoutrrrProvider) sendWithAttachmentsTo(ctx context.Context, n *Notification, attachments []Attachment, targets []string) error {
+	var plain []string
+	client := s.attachmentClient()
+	for i, raw := range targets {
+		upload := nativeUploader(raw)
+		if upload == nil {
+			plain = append(plain, raw)
+			continue
+		}
+		u, _ := url.Parse(raw)
+		uploadCtx, cancel := context.WithTimeout(ctx, s.effectiveTimeout())
+		err := upload(uploadCtx, client, u, n, attachments)
+		cancel()
+		if err == nil {
+			continue
+		}
+		if ctx.Err() != nil {
+			return &UndeliveredError{Targets: slices.Concat(plain, targets[i:]), Err: ctx.Err()}
+		}
+		if !errors.Is(err, ErrAttachmentsUnsupported) {
+			GetLogger().Warn("attachment upload failed, sending text notification instead",
+				logger.String("provider", s.name),
+				logger.String("service", u.Scheme),
+				logger.Error(privacy.WrapError(err)))
+		}
+		plain = append(plain, raw)
+	}
+
+	failed := s.sendText(n, plain)
+	if len(failed) == 0 {
+		return nil
+	}
+	undelivered := &UndeliveredError{}
+	var errs []error
+	for _, raw := range plain {
+		if err := failed[raw]; err != nil {
+			undelivered.Targets = append(undelivered.Targets, raw)
+			errs = append(errs, privacy.WrapError(err))
+		}
+	}
+	undelivered.Err = errors.Join(errs...)
+	return undelivered
+}
+
+// nativeUploader returns the attachment uploader for a shoutrrr URL, or nil
+// when its service only takes text.
+func nativeUploader(raw string) attachmentUploader {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil
+	}
+	return attachmentUploaders[strings.ToLower(u.Scheme)]
+}
+
+// effectiveTimeout returns the configured timeout or the shoutrrr default.
+func (s *ShoutrrrProvider) effectiveTimeout() time.Duration {
+	if s.timeout > 0 {
+		return s.timeout
+	}
+	return shoutrrrDefaultTimeout
+}
+
+// attachmentClient lazily creates the HTTP client used for native uploads.
+func (s *ShoutrrrProvider) attachmentClient() *httpclient.Client {
+	s.clientOnce.Do(func() {
+		cfg := httpclient.DefaultConfig()
+		cfg.UserAgent = "BirdNET-Go-Notifications/1.0"
+		cfg.DefaultTimeout = s.effectiveTimeout()
+		s.client = httpclient.New(&cfg)
+	})
+	return s.client
+}
+
+// uploadNtfy publishes one ntfy message per attachment. The first message carries
+// the title and text; ntfy allows a single attachment per message.
+func uploadNtfy(ctx context.Context, client *httpclient.Client, u *url.URL, n *Notification, attachments []Attachment) error {
+	scheme := u.Query().Get("scheme")
+	if scheme == "" {
+		scheme = "https"
+	}
+	host := u.Host
+	if host == "" {
+		host = "ntfy.sh"
+	}
+	topic := strings.Trim(u.Path, "/")
+	if topic == "" {
+		return errors.Newf("ntfy URL has no topic").
+			Component("notification").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "ntfy_attachment_upload").
+			Build()
+	}
+	endpoint := (&url.URL{Scheme: scheme, Host: host, Path: "/" + topic}).String()
+
+	for i := range attachments {
+		a := &attachments[i]
+		req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(a.Data))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Filename", a.Name)
+		if n.Title != "" {
+			req.Header.Set("Title", encodeHeaderValue(n.Title))
+		}
+		if i == 0 {
+			// ntfy expands the literal \n sequence into line breaks in header messages
+			req.Header.Set("Message", encodeHeaderValue(strings.ReplaceAll(n.Message, "\n", `\n`)))
+		}
+		if u.User != nil {
+			password, _ := u.User.Password()
+			req.SetBasicAuth(u.User.Username(), password)
+		}
+		if err := doAttachmentRequest(ctx, client, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// uploadTelegram sends the spectrogram with sendPhoto and the clip with sendAudio
+// to every chat in the URL. The notification text is the caption of the first file.
+func uploadTelegram(ctx context.Context, client *httpclient.Client, u *url.URL, n *Notification, attachments []Attachment) error {
+	var password string
+	if u.User != nil {
+		password, _ = u.User.Password()
+	}
+	chats := splitList(u.Query().Get("chats"))
+	if password == "" || len(chats) == 0 {
+		return errors.Newf("telegram URL requires a bot token and chats").
+			Component("notification").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "telegram_attachment_upload").
+			Build()
+	}
+
+	token := u.User.Username() + ":" + password
+	caption := truncateRunes(joinTitleMessage(n), telegramCaptionLimit)
+	for _, chat := range chats {
+		for i := range attachments {
+			a := &attachments[i]
+			method, field := "sendAudio", "audio"
+			if a.Kind == AttachmentKindSpectrogram {
+				method, field = "sendPhoto", "photo"
+			}
+			fields := map[string]string{"chat_id": chat}
+			if chatID, thread, ok := strings.Cut(chat, ":"); ok {
+				fields["chat_id"] = chatID
+				fields["message_thread_id"] = thread
+			}
+			if i == 0 {
+				fields["caption"] = caption
+			}
+			endpoint := fmt.Sprintf("%s/bot%s/%s", telegramAPIBase, token, method)
+			if err := postMultipart(ctx, client, endpoint, fields, map[string]*Attachment{field: a}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// uploadPushover sends the message with the spectrogram attached. Pushover only
+// accepts image attachments, so audio-only requests are reported as unsupported.
+func uploadPushover(ctx context.Context, client *httpclient.Client, u *url.URL, n *Notification, attachments []Attachment) error {
+	var image *Attachment
+	for i := range attachments {
+		if attachments[i].Kind == AttachmentKindSpectrogram {
+			image = &attachments[i]
+			break
+		}
+	}
+	if image == nil {
+		return ErrAttachmentsUnsupported
+	}
+	var token string
+	if u.User != nil {
+		token, _ = u.User.Password()
+	}
+	fields := map[string]string{
+		"token":   token,
+		"user":    u.Host,
+		"message": n.Message,
+	}
+	if n.Title != "" {
+		fields["title"] = n.Title
+	}
+	if devices := u.Query().Get("devices"); devices != "" {
+		fields["device"] = devices
+	}
+	return postMultipart(ctx, client, pushoverAPIURL, fields, map[string]*Attachment{"attachment": image})
+}
+
+// uploadDiscord posts the message and all attachments to the Discord webhook.
+func uploadDiscord(ctx context.Context, client *httpclient.Client, u *url.URL, n *Notification, attachments []Attachment) error {
+	if u.User == nil || u.Host == "" {
+		return errors.Newf("discord URL requires a webhook token and ID").
+			Component("notification").
+			Category(errors.CategoryConfiguration).
+			Context("operation", "discord_attachment_upload").
+			Build()
+	}
+	payload, err := json.Marshal(map[string]string{
+		"content": truncateRunes(joinTitleMessage(n), discordContentLimit),
+	})
+	if err != nil {
+		return err
+	}
+	endpoint := fmt.Sprintf("%s/%s/%s", discordAPIBase, u.Host, u.User.Username())
+	if thread := u.Query().Get("thread_id"); thread != "" {
+		endpoint += "?thread_id=" + url.QueryEscape(thread)
+	}
+	files := make(map[string]*Attachment, len(attachments))
+	for i := range attachments {
+		files[fmt.Sprintf("files[%d]", i)] = &attachments[i]
+	}
+	return postMultipart(ctx, client, endpoint, map[string]string{"payload_json": string(payload)}, files)
+}
+
+// postMultipart posts form fields and files as multipart/form-data.
+func postMultipart(ctx context.Context, client *httpclient.Client, endpoint string, fields map[string]string, files map[string]*Attachment) error {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, value := range fields {
+		if err := mw.WriteField(name, value); err != nil {
+			return err
+		}
+	}
+	for name, a := range files {
+		if err := writeMultipartFile(mw, name, a); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	return doAttachmentRequest(ctx, client, req)
+}
+
+// writeMultipartFile adds an attachment as a file part with its content type.
+func writeMultipartFile(mw *multipart.Writer, field string, a *Attachment) error {
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", mime.FormatMediaType("form-data", map[string]string{
+		"name":     field,
+		"filename": a.Name,
+	}))
+	header.Set("Content-Type", a.ContentType)
+	part, err := mw.CreatePart(header)
+	if err != nil {
+		return fmt.Errorf("failed to create %s part: %w", field, err)
+	}
+	if _, err := part.Write(a.Data); err != nil {
+		return fmt.Errorf("failed to write %s part: %w", field, err)
+	}
+	return nil
+}
+
+// doAttachmentRequest executes req and treats any non-2xx status as an error.
+func doAttachmentRequest(ctx context.Context, client *httpclient.Client, req *http.Request) error {
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+		return errors.Newf("attachment upload returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body))).
+			Component("notification").
+			Category(errors.CategoryNetwork).
+			Context("operation", "attachment_upload").
+			Context("status_code", resp.StatusCode).
+			Build()
+	}
+	return nil
+}
+
+// encodeHeaderValue RFC 2047 encodes values that are not plain ASCII.
+func encodeHeaderValue(v string) string {
+	for _, r := range v {
+		if r < 0x20 || r > 0x7e {
+			return mime.QEncoding.Encode("utf-8", v)
+		}
+	}
+	return v
+}
+
+// joinTitleMessage combines the title and message for services without a title field.
+func joinTitleMessage(n *Notification) string {
+	if n.Title == "" {
+		return n.Message
+	}
+	return n.Title + "\n" + n.Message
+}
+
+// truncateRunes shortens s to at most limit runes.
+func truncateRunes(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// splitList splits a comma-separated list, dropping empty items.
+func splitList(s string) []string {
+	var out []string
+	for item := range strings.SplitSeq(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
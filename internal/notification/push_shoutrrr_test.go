@@ -168,7 +168,7 @@ func runRetryTest(t *testing.T, tc retryTestCase) {
 
 	ctx := t.Context()
 	ep := &d.providers[0]
-	d.retryLoop(ctx, notif, ep)
+	d.retryLoop(ctx, notif, ep, nil)
 
 	assert.Equal(t, tc.expectedCount, int(sendAttempts.Load()), tc.description)
 }
@@ -34,7 +34,9 @@ import (
 	"fmt"
 	"io"
 	"maps"
+	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
 	"slices"
 	"strings"
@@ -390,6 +392,58 @@ func (w *WebhookProvider) Send(ctx context.Context, n *Notification) error {
 		return fmt.Errorf("failed to build webhook payload: %w", err)
 	}
 
+	return w.sendToEndpoints(ctx, payload, "application/json")
+}
+
+// SendWithAttachments sends the notification as multipart/form-data. The JSON
+// payload normally sent by Send is the "payload" field and each attachment is a
+// file field named after its kind ("spectrogram" or "audio"). If every endpoint
+// rejects the multipart body, the plain JSON payload is sent instead.
+func (w *WebhookProvider) SendWithAttachments(ctx context.Context, n *Notification, attachments []Attachment) error {
+	if len(w.endpoints) == 0 {
+		return fmt.Errorf("no webhook endpoints configured")
+	}
+
+	payload, err := w.buildPayload(n)
+	if err != nil {
+		return fmt.Errorf("failed to build webhook payload: %w", err)
+	}
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	payloadHeader := textproto.MIMEHeader{}
+	payloadHeader.Set("Content-Disposition", `form-data; name="payload"`)
+	payloadHeader.Set("Content-Type", "application/json")
+	part, err := mw.CreatePart(payloadHeader)
+	if err != nil {
+		return fmt.Errorf("failed to create payload part: %w", err)
+	}
+	if _, err := part.Write(payload); err != nil {
+		return fmt.Errorf("failed to write payload part: %w", err)
+	}
+	for i := range attachments {
+		if err := writeMultipartFile(mw, string(attachments[i].Kind), &attachments[i]); err != nil {
+			return err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return fmt.Errorf("failed to finalize multipart body: %w", err)
+	}
+
+	err = w.sendToEndpoints(ctx, body.Bytes(), mw.FormDataContentType())
+	if err == nil || ctx.Err() != nil {
+		return err
+	}
+
+	// Receivers that reject multipart bodies still get the plain JSON payload
+	if jsonErr := w.sendToEndpoints(ctx, payload, "application/json"); jsonErr != nil {
+		return fmt.Errorf("multipart delivery failed (%w), JSON fallback failed: %w", err, jsonErr)
+	}
+	return nil
+}
+
+// sendToEndpoints tries each endpoint in order until one accepts the body.
+func (w *WebhookProvider) sendToEndpoints(ctx context.Context, payload []byte, contentType string) error {
 	// Try each endpoint until one succeeds (use index to avoid copying)
 	errs := make([]error, 0, len(w.endpoints))
 	for i := range w.endpoints {
@@ -402,7 +456,7 @@ func (w *WebhookProvider) Send(ctx context.Context, n *Notification) error {
 			endpointCtx, cancel = context.WithTimeout(ctx, endpoint.Timeout)
 		}
 
-		err := w.sendToEndpoint(endpointCtx, endpoint, payload)
+		err := w.sendToEndpoint(endpointCtx, endpoint, payload, contentType)
 
 		// Cancel timeout context if created
 		if cancel != nil {
@@ -469,7 +523,7 @@ func (w *WebhookProvider) buildPayload(n *Notification) ([]byte, error) {
 //   - Uses http.NoBody for requests without body (though webhooks need body)
 //   - Always closes response body to prevent connection leaks
 //   - Properly propagates context for cancellation
-func (w *WebhookProvider) sendToEndpoint(ctx context.Context, endpoint *WebhookEndpoint, payload []byte) error {
+func (w *WebhookProvider) sendToEndpoint(ctx context.Context, endpoint *WebhookEndpoint, payload []byte, contentType string) error {
 	// Create HTTP request with context
 	// LLM Note: Use bytes.NewReader for request body to support retries
 	req, err := http.NewRequestWithContext(ctx, endpoint.Method, endpoint.URL, bytes.NewReader(payload))
@@ -478,7 +532,7 @@ func (w *WebhookProvider) sendToEndpoint(ctx context.Context, endpoint *WebhookE
 	}
 
 	// Set headers
-	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Content-Type", contentType)
 	for key, value := range endpoint.Headers {
 		req.Header.Set(key, value)
 	}
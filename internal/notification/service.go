@@ -20,6 +20,7 @@ type Subscriber struct {
 // Service manages notifications and provides rate limiting
 type Service struct {
 	store         NotificationStore
+	storeMu       sync.RWMutex // Protects store replacement via SetStore
 	subscribers   []*Subscriber
 	subscribersMu sync.RWMutex
 	rateLimiter   *RateLimiter
@@ -143,7 +144,7 @@ func (s *Service) Create(notifType Type, priority Priority, title, message strin
 	}
 
 	// Save to store
-	if err := s.store.Save(notification); err != nil {
+	if err := s.getStore().Save(notification); err != nil {
 		return nil, errors.New(err).
 			Component("notification").
 			Category(errors.CategorySystem).
@@ -177,7 +178,7 @@ func (s *Service) CreateWithComponent(notifType Type, priority Priority, title,
 		WithComponent(component)
 
 	// Save to store
-	if err := s.store.Save(notification); err != nil {
+	if err := s.getStore().Save(notification); err != nil {
 		return nil, errors.New(err).
 			Component("notification").
 			Category(errors.CategorySystem).
@@ -193,12 +194,12 @@ func (s *Service) CreateWithComponent(notifType Type, priority Priority, title,
 
 // Get retrieves a notification by ID
 func (s *Service) Get(id string) (*Notification, error) {
-	return s.store.Get(id)
+	return s.getStore().Get(id)
 }
 
 // List returns notifications based on filter options
 func (s *Service) List(filter *FilterOptions) ([]*Notification, error) {
-	return s.store.List(filter)
+	return s.getStore().List(filter)
 }
 
 // MarkAsRead updates a notification's status to read
@@ -210,13 +211,13 @@ func (s *Service) MarkAsRead(id string) error {
 			Build()
 	}
 
-	notification, err := s.store.Get(id)
+	notification, err := s.getStore().Get(id)
 	if err != nil {
 		return err
 	}
 
 	notification.MarkAsRead()
-	return s.store.Update(notification)
+	return s.getStore().Update(notification)
 }
 
 // MarkAsAcknowledged updates a notification's status to acknowledged
@@ -228,13 +229,13 @@ func (s *Service) MarkAsAcknowledged(id string) error {
 			Build()
 	}
 
-	notification, err := s.store.Get(id)
+	notification, err := s.getStore().Get(id)
 	if err != nil {
 		return err
 	}
 
 	notification.MarkAsAcknowledged()
-	return s.store.Update(notification)
+	return s.getStore().Update(notification)
 }
 
 // Delete removes a notification
@@ -246,7 +247,7 @@ func (s *Service) Delete(id string) error {
 			Build()
 	}
 
-	return s.store.Delete(id)
+	return s.getStore().Delete(id)
 }
 
 // Subscribe creates a channel to receive real-time notifications.
@@ -325,7 +326,7 @@ func (s *Service) Unsubscribe(ch <-chan *Notification) {
 
 // GetUnreadCount returns the number of unread notifications
 func (s *Service) GetUnreadCount() (int, error) {
-	return s.store.GetUnreadCount()
+	return s.getStore().GetUnreadCount()
 }
 
 // CreateErrorNotification creates a notification from an error
@@ -499,11 +500,54 @@ func (s *Service) cleanupLoop() {
 	}
 }
 
+// getStore returns the active notification store.
+func (s *Service) getStore() NotificationStore {
+	s.storeMu.RLock()
+	defer s.storeMu.RUnlock()
+	return s.store
+}
+
+// SetStore replaces the notification store, typically to switch from the
+// startup in-memory store to a persistent one once the database is available.
+// Notifications already held by the current store are copied into the new one
+// so that entries raised during startup are not lost.
+func (s *Service) SetStore(store NotificationStore) error {
+	if store == nil {
+		return errors.Newf("notification store cannot be nil").
+			Component("notification").
+			Category(errors.CategoryValidation).
+			Build()
+	}
+
+	s.storeMu.Lock()
+	defer s.storeMu.Unlock()
+
+	existing, err := s.store.List(&FilterOptions{})
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, n := range existing {
+		if err := store.Save(n); err != nil {
+			failed++
+		}
+	}
+	if failed > 0 {
+		s.logger.Warn("failed to copy some notifications to new store",
+			logger.Int("failed_count", failed),
+			logger.Int("total_count", len(existing)))
+	}
+
+	s.store = store
+	return nil
+}
+
 // performCleanup executes a single cleanup cycle with optional debug logging.
 func (s *Service) performCleanup() {
 	s.logCleanupStart()
 
-	if err := s.store.DeleteExpired(); err != nil {
+	if err := s.getStore().DeleteExpired(); err != nil {
 		s.logger.Error("error cleaning up expired notifications", logger.Error(err))
 	} else if s.config.Debug {
 		s.logger.Debug("notification cleanup completed")
@@ -516,7 +560,7 @@ func (s *Service) logCleanupStart() {
 		return
 	}
 
-	notifications, _ := s.store.List(&FilterOptions{})
+	notifications, _ := s.getStore().List(&FilterOptions{})
 	expiredCount := s.countExpired(notifications)
 
 	if expiredCount > 0 {
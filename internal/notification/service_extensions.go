@@ -15,7 +15,7 @@ func (s *Service) UpdateNotification(notification *Notification) error {
 			Category(errors.CategorySystem).
 			Build()
 	}
-	return s.store.Update(notification)
+	return s.getStore().Update(notification)
 }
 
 // CreateWithMetadata creates a notification with full metadata support
@@ -53,7 +53,7 @@ func (s *Service) CreateWithMetadata(notification *Notification) error {
 	}
 
 	// Save to store
-	if err := s.store.Save(notification); err != nil {
+	if err := s.getStore().Save(notification); err != nil {
 		return errors.New(err).
 			Component("notification").
 			Category(errors.CategorySystem).
@@ -292,3 +292,26 @@ func BenchmarkService_CreateWithMetadata(b *testing.B) {
 		}
 	}
 }
+
+func TestService_SetStore(t *testing.T) {
+	t.Parallel()
+
+	service := createTestService()
+
+	existing, err := service.Create(TypeInfo, PriorityLow, "Startup", "created before the database was ready")
+	require.NoError(t, err)
+
+	newStore := NewInMemoryStore(DefaultMaxNotifications)
+	require.NoError(t, service.SetStore(newStore))
+
+	copied, err := newStore.Get(existing.ID)
+	require.NoError(t, err, "existing notifications should be copied into the new store")
+	assert.Equal(t, "Startup", copied.Title)
+
+	created, err := service.Create(TypeInfo, PriorityLow, "After switch", "")
+	require.NoError(t, err)
+	_, err = newStore.Get(created.ID)
+	require.NoError(t, err, "new notifications should be saved to the new store")
+
+	require.Error(t, service.SetStore(nil), "nil store should be rejected")
+}
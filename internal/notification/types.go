@@ -66,6 +66,10 @@ const (
 const (
 	// MetadataKeyIsToast identifies toast notifications in metadata
 	MetadataKeyIsToast = "isToast"
+	// MetadataKeyClipName holds the detection's audio clip path relative to the export directory
+	MetadataKeyClipName = "clip_name"
+	// MetadataKeyPushTarget restricts push delivery to the provider with this name
+	MetadataKeyPushTarget = "push_target"
 )
 
 // isToastNotification checks if a notification is a toast notification
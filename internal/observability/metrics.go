@@ -144,4 +144,5 @@ func initializeMyAudioMetrics(myAudioMetrics *metrics.MyAudioMetrics) {
 	myaudio.SetFileMetrics(myAudioMetrics)
 	myaudio.SetProcessMetrics(myAudioMetrics)
 	myaudio.SetFilterMetrics(myAudioMetrics)
+	myaudio.SetMicHealthMetrics(myAudioMetrics)
 }
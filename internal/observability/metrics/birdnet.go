@@ -30,6 +30,11 @@ type BirdNETMetrics struct {
 	ActiveProcessingGauge prometheus.Gauge
 	ModelLoadedGauge      prometheus.Gauge
 
+	// Batched inference across sources
+	BatchSize       *prometheus.HistogramVec
+	BatchQueueWait  *prometheus.HistogramVec
+	BatchQueueDepth prometheus.Gauge
+
 	registry *prometheus.Registry
 }
 
@@ -149,6 +154,32 @@ func (m *BirdNETMetrics) initMetrics() error {
 		},
 	)
 
+	// Batched inference
+	m.BatchSize = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "birdnet_batch_size",
+			Help:    "Number of audio chunks analyzed per batched model invocation",
+			Buckets: prometheus.LinearBuckets(1, 1, 16), // 1 to 16 chunks
+		},
+		[]string{"model"},
+	)
+
+	m.BatchQueueWait = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "birdnet_batch_queue_wait_seconds",
+			Help:    "Time audio chunks wait for a batched model invocation",
+			Buckets: prometheus.ExponentialBuckets(BucketStart1ms, BucketFactor2, BucketCount12), // 1ms to ~4s
+		},
+		[]string{"model"},
+	)
+
+	m.BatchQueueDepth = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Name: "birdnet_batch_queue_depth",
+			Help: "Number of audio chunks waiting for a batched model invocation",
+		},
+	)
+
 	return nil
 }
 
@@ -201,6 +232,19 @@ func (m *BirdNETMetrics) RecordModelLoad(model string, err error) {
 	}
 }
 
+// RecordBatch records a batched model invocation and how long its chunks waited
+func (m *BirdNETMetrics) RecordBatch(model string, size int, queueWaitSeconds []float64) {
+	m.BatchSize.WithLabelValues(model).Observe(float64(size))
+	for _, wait := range queueWaitSeconds {
+		m.BatchQueueWait.WithLabelValues(model).Observe(wait)
+	}
+}
+
+// SetBatchQueueDepth sets the number of chunks waiting for batched inference
+func (m *BirdNETMetrics) SetBatchQueueDepth(depth float64) {
+	m.BatchQueueDepth.Set(depth)
+}
+
 // SetActiveProcessing sets the number of active processing operations
 func (m *BirdNETMetrics) SetActiveProcessing(count float64) {
 	m.ActiveProcessingGauge.Set(count)
@@ -267,6 +311,11 @@ func (m *BirdNETMetrics) Describe(ch chan<- *prometheus.Desc) {
 	// State gauges
 	m.ActiveProcessingGauge.Describe(ch)
 	m.ModelLoadedGauge.Describe(ch)
+
+	// Batched inference
+	m.BatchSize.Describe(ch)
+	m.BatchQueueWait.Describe(ch)
+	m.BatchQueueDepth.Describe(ch)
 }
 
 // Collect implements the prometheus.Collector interface.
@@ -289,6 +338,11 @@ func (m *BirdNETMetrics) Collect(ch chan<- prometheus.Metric) {
 	// State gauges
 	m.ActiveProcessingGauge.Collect(ch)
 	m.ModelLoadedGauge.Collect(ch)
+
+	// Batched inference
+	m.BatchSize.Collect(ch)
+	m.BatchQueueWait.Collect(ch)
+	m.BatchQueueDepth.Collect(ch)
 }
 
 // RecordOperation implements the Recorder interface.
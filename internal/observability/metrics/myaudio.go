@@ -45,6 +45,8 @@ type MyAudioMetrics struct {
 	analysisBufferProcessingDuration *prometheus.HistogramVec
 	analysisBufferPollTotal          *prometheus.CounterVec
 	analysisBufferDataDropsTotal     *prometheus.CounterVec
+	analysisOverlapGauge             *prometheus.GaugeVec
+	analysisOverlapAdjustmentsTotal  *prometheus.CounterVec
 
 	// Capture buffer specific metrics
 	captureBufferSegmentReadsTotal    *prometheus.CounterVec
@@ -56,6 +58,10 @@ type MyAudioMetrics struct {
 	audioSilenceDetections    *prometheus.CounterVec
 	audioDataCorruptionTotal  *prometheus.CounterVec
 
+	// Microphone health metrics
+	micHealthIssueGauge       *prometheus.GaugeVec
+	micHealthMeasurementGauge *prometheus.GaugeVec
+
 	// File operation metrics
 	fileOperationsTotal   *prometheus.CounterVec
 	fileOperationDuration *prometheus.HistogramVec
@@ -289,6 +295,22 @@ func (m *MyAudioMetrics) initMetrics() error {
 		[]string{"source", "reason"}, // reason: full_buffer, write_failure, retry_exhausted
 	)
 
+	m.analysisOverlapGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "myaudio_analysis_overlap_seconds",
+			Help: "Overlap between analyzed chunks currently in effect",
+		},
+		[]string{"source"},
+	)
+
+	m.analysisOverlapAdjustmentsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "myaudio_analysis_overlap_adjustments_total",
+			Help: "Total number of overlap adjustments made under CPU pressure",
+		},
+		[]string{"source", "direction"}, // direction: lowered, restored
+	)
+
 	// Capture buffer specific metrics
 	m.captureBufferSegmentReadsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -340,6 +362,23 @@ func (m *MyAudioMetrics) initMetrics() error {
 		[]string{"source", "corruption_type"},
 	)
 
+	// Microphone health metrics
+	m.micHealthIssueGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "myaudio_mic_health_issue",
+			Help: "Whether a microphone health issue is active (1) or not (0)",
+		},
+		[]string{"source", "issue"}, // issue: silence, clipping, dc_offset, hum, rolloff, channel_imbalance
+	)
+
+	m.micHealthMeasurementGauge = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "myaudio_mic_health_measurement",
+			Help: "Latest microphone health measurement",
+		},
+		[]string{"source", "measurement"}, // measurement: rms_db, peak_db, clipping_ratio, dc_offset, hum_ratio, rolloff_hz, channel_imbalance_db
+	)
+
 	// File operation metrics
 	m.fileOperationsTotal = prometheus.NewCounterVec(
 		prometheus.CounterOpts{
@@ -499,12 +538,16 @@ func (m *MyAudioMetrics) initMetrics() error {
 		m.analysisBufferProcessingDuration,
 		m.analysisBufferPollTotal,
 		m.analysisBufferDataDropsTotal,
+		m.analysisOverlapGauge,
+		m.analysisOverlapAdjustmentsTotal,
 		m.captureBufferSegmentReadsTotal,
 		m.captureBufferSegmentReadDuration,
 		m.captureBufferTimestampErrorsTotal,
 		m.audioDataValidationErrors,
 		m.audioSilenceDetections,
 		m.audioDataCorruptionTotal,
+		m.micHealthIssueGauge,
+		m.micHealthMeasurementGauge,
 		m.fileOperationsTotal,
 		m.fileOperationDuration,
 		m.fileOperationErrors,
@@ -675,6 +718,12 @@ func (m *MyAudioMetrics) RecordAnalysisBufferDataDrop(source, reason string) {
 	m.analysisBufferDataDropsTotal.WithLabelValues(source, reason).Inc()
 }
 
+// RecordAnalysisOverlapAdjustment records an overlap change of a source
+func (m *MyAudioMetrics) RecordAnalysisOverlapAdjustment(source, direction string, overlap float64) {
+	m.analysisOverlapAdjustmentsTotal.WithLabelValues(source, direction).Inc()
+	m.analysisOverlapGauge.WithLabelValues(source).Set(overlap)
+}
+
 // Capture buffer specific recording methods
 
 // RecordCaptureBufferSegmentRead records a capture buffer segment read
@@ -709,6 +758,22 @@ func (m *MyAudioMetrics) RecordAudioDataCorruption(source, corruptionType string
 	m.audioDataCorruptionTotal.WithLabelValues(source, corruptionType).Inc()
 }
 
+// Microphone health recording methods
+
+// UpdateMicHealthIssue sets whether a microphone health issue is active for a source
+func (m *MyAudioMetrics) UpdateMicHealthIssue(source, issue string, active bool) {
+	value := 0.0
+	if active {
+		value = 1.0
+	}
+	m.micHealthIssueGauge.WithLabelValues(source, issue).Set(value)
+}
+
+// UpdateMicHealthMeasurement records the latest value of a microphone health measurement
+func (m *MyAudioMetrics) UpdateMicHealthMeasurement(source, measurement string, value float64) {
+	m.micHealthMeasurementGauge.WithLabelValues(source, measurement).Set(value)
+}
+
 // File operation recording methods
 
 // RecordFileOperation records a file operation
@@ -170,3 +170,21 @@ func TestRecordBufferAllocationAttempt(t *testing.T) {
 		assert.InDelta(t, float64(3), blockedCount, 0.01, "Should have 3 blocked repeated allocations")
 	})
 }
+
+func TestUpdateMicHealth(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := NewMyAudioMetrics(registry)
+	require.NoError(t, err)
+
+	m.UpdateMicHealthIssue("rtsp_001", "hum", true)
+	m.UpdateMicHealthIssue("rtsp_001", "clipping", false)
+	m.UpdateMicHealthMeasurement("rtsp_001", "hum_ratio", 0.8)
+
+	assert.InDelta(t, 1.0, testutil.ToFloat64(m.micHealthIssueGauge.WithLabelValues("rtsp_001", "hum")), 0.001)
+	assert.InDelta(t, 0.0, testutil.ToFloat64(m.micHealthIssueGauge.WithLabelValues("rtsp_001", "clipping")), 0.001)
+	assert.InDelta(t, 0.8, testutil.ToFloat64(m.micHealthMeasurementGauge.WithLabelValues("rtsp_001", "hum_ratio")), 0.001)
+
+	// Clearing an issue resets the gauge
+	m.UpdateMicHealthIssue("rtsp_001", "hum", false)
+	assert.InDelta(t, 0.0, testutil.ToFloat64(m.micHealthIssueGauge.WithLabelValues("rtsp_001", "hum")), 0.001)
+}
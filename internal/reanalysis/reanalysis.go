@@ -0,0 +1,155 @@
+// Package reanalysis runs inference again over the stored clips of detections,
+// with a chosen model and sensitivity. A job stores the new top predictions of
+// every detection in detection_predictions under its own ID and flags the
+// detections it disagrees with for review, so history can be audited against
+// model updates without touching the original detections.
+//
+// Jobs run one at a time in the background at low priority: the model uses a
+// single thread and the runner rests as long after each clip as it took to
+// analyze it. A job interrupted by a shutdown resumes after the last
+// detection it processed.
+package reanalysis
+
+import (
+	"cmp"
+	"context"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/tphakala/birdnet-go/internal/birdnet"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/detection"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// maxPredictions is how many of the best predictions a job stores per detection
+const maxPredictions = 10
+
+// Store is the part of the v2 datastore jobs read detections from and write
+// predictions to.
+type Store interface {
+	SearchNotesAdvanced(filters *datastore.AdvancedSearchFilters) ([]datastore.Note, int64, error)
+	ReanalysisModelID(ctx context.Context, info detection.ModelInfo) (uint, error)
+	SaveReanalysisPredictions(ctx context.Context, jobID, modelID, detectionID uint, results []datastore.Results) error
+}
+
+// Analyzer runs the model of a job on 3 second chunks of audio.
+type Analyzer interface {
+	PredictWithContext(ctx context.Context, sample [][]float32) ([]datastore.Results, error)
+	Delete()
+}
+
+// AnalyzerFactory loads the model of a job and describes it for the model registry.
+type AnalyzerFactory func(job *entities.ReanalysisJob) (Analyzer, detection.ModelInfo, error)
+
+// BirdNETAnalyzer returns a factory loading BirdNET with the model and
+// sensitivity of a job and the remaining settings of settings.
+func BirdNETAnalyzer(settings *conf.Settings) AnalyzerFactory {
+	return func(job *entities.ReanalysisJob) (Analyzer, detection.ModelInfo, error) {
+		jobSettings := *settings
+		if job.ModelPath != "" {
+			jobSettings.BirdNET.ModelPath = job.ModelPath
+			jobSettings.BirdNET.LabelPath = job.LabelPath
+		}
+		jobSettings.BirdNET.Sensitivity = job.Sensitivity
+		// One thread and no batching or workers keep the job out of the way of realtime analysis
+		jobSettings.BirdNET.Threads = 1
+		jobSettings.BirdNET.BatchSize = 0
+		jobSettings.BirdNET.Remote = conf.RemoteInferenceSettings{}
+
+		bn, err := birdnet.NewBirdNET(&jobSettings)
+		if err != nil {
+			return nil, detection.ModelInfo{}, err
+		}
+		return bn, modelInfo(job), nil
+	}
+}
+
+// modelInfo describes the model of a job for the model registry. The
+// configured model shares the entry of the original detections.
+func modelInfo(job *entities.ReanalysisJob) detection.ModelInfo {
+	if job.ModelPath == "" {
+		return detection.DefaultModelInfo()
+	}
+	path := job.ModelPath
+	variant := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	if len(variant) > 100 {
+		variant = variant[:100]
+	}
+	return detection.ModelInfo{
+		Name:           detection.DefaultModelName,
+		Version:        detection.DefaultModelVersion,
+		Variant:        variant,
+		ClassifierPath: &path,
+	}
+}
+
+// mergeChunkResults keeps the highest confidence of every species over the
+// chunks of a clip and returns the best predictions, best first.
+func mergeChunkResults(chunks [][]datastore.Results) []datastore.Results {
+	best := make(map[string]float32)
+	for _, results := range chunks {
+		for _, r := range results {
+			if r.Confidence > best[r.Species] {
+				best[r.Species] = r.Confidence
+			}
+		}
+	}
+	merged := make([]datastore.Results, 0, len(best))
+	for species, confidence := range best {
+		merged = append(merged, datastore.Results{Species: species, Confidence: confidence})
+	}
+	slices.SortFunc(merged, func(a, b datastore.Results) int {
+		if c := cmp.Compare(b.Confidence, a.Confidence); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Species, b.Species)
+	})
+	if len(merged) > maxPredictions {
+		merged = merged[:maxPredictions]
+	}
+	return merged
+}
+
+// compare checks the predictions of a job against a detection. It returns a
+// flag if the job disagrees, nil if it agrees.
+func compare(job *entities.ReanalysisJob, note *datastore.Note, results []datastore.Results) *entities.ReanalysisFlag {
+	flag := &entities.ReanalysisFlag{
+		JobID:          job.ID,
+		DetectionID:    note.ID,
+		ScientificName: note.ScientificName,
+		Confidence:     note.Confidence,
+	}
+	for _, r := range results {
+		if strings.EqualFold(detection.ParseSpeciesString(r.Species).ScientificName, note.ScientificName) {
+			flag.NewConfidence = float64(r.Confidence)
+			break
+		}
+	}
+
+	var top datastore.Results
+	if len(results) > 0 {
+		top = results[0]
+		flag.TopScientificName = detection.ParseSpeciesString(top.Species).ScientificName
+		flag.TopConfidence = float64(top.Confidence)
+	}
+
+	switch {
+	case flag.TopScientificName != "" && !strings.EqualFold(flag.TopScientificName, note.ScientificName) &&
+		flag.TopConfidence >= job.Threshold:
+		flag.Reason = entities.ReanalysisLabelChanged
+	case flag.NewConfidence < job.Threshold:
+		flag.Reason = entities.ReanalysisBelowThreshold
+	default:
+		return nil
+	}
+	return flag
+}
+
+// GetLogger returns the reanalysis logger.
+func GetLogger() logger.Logger {
+	return logger.Global().Module("reanalysis")
+}
@@ -0,0 +1,144 @@
+package reanalysis
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/detection"
+)
+
+func TestMergeChunkResults(t *testing.T) {
+	t.Parallel()
+
+	merged := mergeChunkResults([][]datastore.Results{
+		{{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.6}, {Species: "Parus major_Great Tit", Confidence: 0.3}},
+		{{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.8}, {Species: "Erithacus rubecula_European Robin", Confidence: 0.3}},
+	})
+
+	require.Len(t, merged, 3)
+	assert.Equal(t, datastore.Results{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.8}, merged[0],
+		"a species keeps its best confidence over the chunks")
+	assert.Equal(t, "Erithacus rubecula_European Robin", merged[1].Species, "ties are ordered by name")
+	assert.Equal(t, "Parus major_Great Tit", merged[2].Species)
+
+	many := make([]datastore.Results, 0, maxPredictions+5)
+	for i := range maxPredictions + 5 {
+		many = append(many, datastore.Results{Species: string(rune('a' + i)), Confidence: float32(i) / 100})
+	}
+	assert.Len(t, mergeChunkResults([][]datastore.Results{many}), maxPredictions)
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+
+	job := &entities.ReanalysisJob{ID: 3, Threshold: 0.7}
+	note := &datastore.Note{ID: 11, ScientificName: "Turdus merula", Confidence: 0.85}
+
+	tests := []struct {
+		name    string
+		results []datastore.Results
+		reason  entities.ReanalysisReason // empty when the job agrees
+	}{
+		{
+			name:    "same species above threshold",
+			results: []datastore.Results{{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.9}},
+		},
+		{
+			name: "another species is confident",
+			results: []datastore.Results{
+				{Species: "Turdus philomelos_Song Thrush", Confidence: 0.8},
+				{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.75},
+			},
+			reason: entities.ReanalysisLabelChanged,
+		},
+		{
+			name: "another species leads below threshold",
+			results: []datastore.Results{
+				{Species: "Turdus philomelos_Song Thrush", Confidence: 0.6},
+				{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.5},
+			},
+			reason: entities.ReanalysisBelowThreshold,
+		},
+		{
+			name:   "nothing predicted",
+			reason: entities.ReanalysisBelowThreshold,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			flag := compare(job, note, tt.results)
+			if tt.reason == "" {
+				assert.Nil(t, flag)
+				return
+			}
+			require.NotNil(t, flag)
+			assert.Equal(t, tt.reason, flag.Reason)
+			assert.Equal(t, job.ID, flag.JobID)
+			assert.Equal(t, note.ID, flag.DetectionID)
+			assert.Equal(t, note.ScientificName, flag.ScientificName)
+		})
+	}
+}
+
+func TestModelInfo(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, detection.DefaultModelInfo(), modelInfo(&entities.ReanalysisJob{}),
+		"the configured model shares the registry entry of the detections")
+
+	info := modelInfo(&entities.ReanalysisJob{ModelPath: "/models/BirdNET_GLOBAL_6K_V2.4_Model_INT8.tflite"})
+	assert.Equal(t, "BirdNET_GLOBAL_6K_V2.4_Model_INT8", info.Variant)
+	require.NotNil(t, info.ClassifierPath)
+	assert.Equal(t, "/models/BirdNET_GLOBAL_6K_V2.4_Model_INT8.tflite", *info.ClassifierPath)
+}
+
+func TestValidateJob(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	model := filepath.Join(dir, "model.tflite")
+	labels := filepath.Join(dir, "labels.txt")
+	require.NoError(t, os.WriteFile(model, []byte("model"), 0o600))
+	require.NoError(t, os.WriteFile(labels, []byte("labels"), 0o600))
+
+	valid := func() *entities.ReanalysisJob {
+		return &entities.ReanalysisJob{Sensitivity: 1, Threshold: 0.8, StartDate: "2026-01-01", EndDate: "2026-02-01"}
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*entities.ReanalysisJob)
+		valid  bool
+	}{
+		{name: "configured model", modify: func(*entities.ReanalysisJob) {}, valid: true},
+		{name: "custom model", modify: func(j *entities.ReanalysisJob) { j.ModelPath, j.LabelPath = model, labels }, valid: true},
+		{name: "missing model file", modify: func(j *entities.ReanalysisJob) { j.ModelPath, j.LabelPath = filepath.Join(dir, "nope"), labels }},
+		{name: "custom model without labels", modify: func(j *entities.ReanalysisJob) { j.ModelPath = model }},
+		{name: "labels without model", modify: func(j *entities.ReanalysisJob) { j.LabelPath = labels }},
+		{name: "sensitivity too high", modify: func(j *entities.ReanalysisJob) { j.Sensitivity = 2 }},
+		{name: "threshold above one", modify: func(j *entities.ReanalysisJob) { j.Threshold = 1.5 }},
+		{name: "malformed date", modify: func(j *entities.ReanalysisJob) { j.StartDate = "01/02/2026" }},
+		{name: "end before start", modify: func(j *entities.ReanalysisJob) { j.EndDate = "2025-12-31" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			job := valid()
+			tt.modify(job)
+			err := validateJob(job)
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,415 @@
+package reanalysis
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+)
+
+const (
+	// pageSize is how many detections a job reads at a time
+	pageSize = 100
+	// clipOverlap is the overlap in seconds of the chunks a clip is analyzed in
+	clipOverlap = 1.5
+	// progressInterval is how often a running job stores its progress
+	progressInterval = 5 * time.Second
+	// pollInterval is how often the runner looks for pending jobs without being woken
+	pollInterval = time.Minute
+	// maxRest bounds the rest after a clip
+	maxRest = 5 * time.Second
+)
+
+// Runner runs re-analysis jobs one at a time.
+type Runner struct {
+	store       Store
+	jobs        repository.ReanalysisRepository
+	newAnalyzer AnalyzerFactory
+	clipsDir    string
+	defaults    conf.BirdNETConfig
+
+	wake chan struct{}
+
+	mu        sync.Mutex
+	runningID uint
+	cancelJob context.CancelFunc
+}
+
+// NewRunner creates a runner reading clips from the clip export path of
+// settings. Jobs without a sensitivity or threshold use the configured ones.
+func NewRunner(settings *conf.Settings, store Store, jobs repository.ReanalysisRepository, newAnalyzer AnalyzerFactory) *Runner {
+	return &Runner{
+		store:       store,
+		jobs:        jobs,
+		newAnalyzer: newAnalyzer,
+		clipsDir:    settings.Realtime.Audio.Export.Path,
+		defaults:    settings.BirdNET,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Submit validates a job and queues it.
+func (r *Runner) Submit(ctx context.Context, job *entities.ReanalysisJob) error {
+	if job.Sensitivity == 0 {
+		job.Sensitivity = r.defaults.Sensitivity
+	}
+	if job.Threshold == 0 {
+		job.Threshold = r.defaults.Threshold
+	}
+	if err := validateJob(job); err != nil {
+		return err
+	}
+
+	*job = entities.ReanalysisJob{
+		Status:      entities.ReanalysisPending,
+		ModelPath:   job.ModelPath,
+		LabelPath:   job.LabelPath,
+		Sensitivity: job.Sensitivity,
+		Threshold:   job.Threshold,
+		StartDate:   job.StartDate,
+		EndDate:     job.EndDate,
+		Species:     job.Species,
+		Source:      job.Source,
+	}
+	if err := r.jobs.CreateJob(ctx, job); err != nil {
+		return err
+	}
+
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Cancel stops a pending or running job. It reports false if the job had already ended.
+func (r *Runner) Cancel(ctx context.Context, id uint) (bool, error) {
+	cancelled, err := r.jobs.CancelJob(ctx, id, time.Now())
+	if err != nil || !cancelled {
+		return cancelled, err
+	}
+	r.mu.Lock()
+	if r.runningID == id && r.cancelJob != nil {
+		r.cancelJob()
+	}
+	r.mu.Unlock()
+	return true, nil
+}
+
+// Run processes queued jobs until ctx is done. Jobs a previous shutdown left
+// running are queued again first.
+func (r *Runner) Run(ctx context.Context) {
+	log := GetLogger()
+	if n, err := r.jobs.RequeueRunningJobs(ctx); err != nil {
+		log.Warn("failed to requeue interrupted reanalysis jobs", logger.Error(err))
+	} else if n > 0 {
+		log.Info("resuming interrupted reanalysis jobs", logger.Int64("jobs", n))
+	}
+
+	for {
+		job, err := r.jobs.NextPendingJob(ctx)
+		switch {
+		case err == nil:
+			r.runJob(ctx, job)
+			continue
+		case !stderrors.Is(err, repository.ErrReanalysisJobNotFound) && ctx.Err() == nil:
+			log.Warn("failed to get next reanalysis job", logger.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-r.wake:
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// runJob runs a job to its end, until it is cancelled or until ctx is done.
+func (r *Runner) runJob(ctx context.Context, job *entities.ReanalysisJob) {
+	log := GetLogger().With(logger.Uint64("job_id", uint64(job.ID)))
+	started, err := r.jobs.StartJob(ctx, job.ID, time.Now())
+	if err != nil || !started {
+		if err != nil {
+			log.Warn("failed to start reanalysis job", logger.Error(err))
+		}
+		return
+	}
+
+	jobCtx, cancel := context.WithCancel(ctx)
+	r.mu.Lock()
+	r.runningID, r.cancelJob = job.ID, cancel
+	r.mu.Unlock()
+	defer func() {
+		r.mu.Lock()
+		r.runningID, r.cancelJob = 0, nil
+		r.mu.Unlock()
+		cancel()
+	}()
+
+	log.Info("reanalysis job started",
+		logger.String("model", cmpOr(job.ModelPath, "configured")),
+		logger.Float64("sensitivity", job.Sensitivity),
+		logger.Uint64("resume_after", uint64(job.LastDetectionID)))
+
+	err = r.process(jobCtx, job)
+	if progressErr := r.jobs.UpdateJobProgress(context.WithoutCancel(ctx), job); progressErr != nil {
+		log.Warn("failed to store reanalysis progress", logger.Error(progressErr))
+	}
+
+	switch {
+	case ctx.Err() != nil:
+		// Shutdown: the job stays running and is requeued on the next start
+		log.Info("reanalysis job interrupted by shutdown", logger.Int64("processed", job.Processed))
+		return
+	case jobCtx.Err() != nil:
+		log.Info("reanalysis job cancelled", logger.Int64("processed", job.Processed))
+		return
+	case err != nil:
+		log.Error("reanalysis job failed", logger.Error(err))
+		if finishErr := r.jobs.FinishJob(ctx, job.ID, entities.ReanalysisFailed, err.Error(), time.Now()); finishErr != nil {
+			log.Warn("failed to store reanalysis job status", logger.Error(finishErr))
+		}
+		return
+	}
+
+	log.Info("reanalysis job completed",
+		logger.Int64("processed", job.Processed),
+		logger.Int64("skipped", job.Skipped),
+		logger.Int64("disagreements", job.Disagreements))
+	if err := r.jobs.FinishJob(ctx, job.ID, entities.ReanalysisCompleted, "", time.Now()); err != nil {
+		log.Warn("failed to store reanalysis job status", logger.Error(err))
+	}
+}
+
+// process analyzes the clips of the detections a job covers.
+func (r *Runner) process(ctx context.Context, job *entities.ReanalysisJob) error {
+	analyzer, info, err := r.newAnalyzer(job)
+	if err != nil {
+		return fmt.Errorf("failed to load model: %w", err)
+	}
+	defer analyzer.Delete()
+
+	modelID, err := r.store.ReanalysisModelID(ctx, info)
+	if err != nil {
+		return err
+	}
+	job.ModelID = &modelID
+
+	filters := jobFilters(job)
+	lastProgress := time.Now()
+	for first := true; ; first = false {
+		filters.MinID = job.LastDetectionID
+		notes, remaining, err := r.store.SearchNotesAdvanced(filters)
+		if err != nil {
+			return err
+		}
+		if first {
+			job.Total = job.Processed + job.Skipped + remaining
+		}
+		if len(notes) == 0 {
+			return nil
+		}
+
+		for i := range notes {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			start := time.Now()
+			if err := r.processNote(ctx, job, analyzer, &notes[i]); err != nil {
+				return err
+			}
+			job.LastDetectionID = notes[i].ID
+
+			if time.Since(lastProgress) >= progressInterval {
+				if err := r.jobs.UpdateJobProgress(ctx, job); err != nil {
+					return err
+				}
+				lastProgress = time.Now()
+			}
+			rest(ctx, time.Since(start))
+		}
+	}
+}
+
+// processNote analyzes the clip of one detection and stores the result.
+func (r *Runner) processNote(ctx context.Context, job *entities.ReanalysisJob, analyzer Analyzer, note *datastore.Note) error {
+	clipPath, ok := r.resolveClip(note.ClipName)
+	if !ok {
+		job.Skipped++
+		return nil
+	}
+	chunks, err := readClip(clipPath)
+	if err != nil || len(chunks) == 0 {
+		GetLogger().Debug("skipping unreadable clip",
+			logger.String("clip", note.ClipName),
+			logger.Error(err))
+		job.Skipped++
+		return nil
+	}
+
+	chunkResults := make([][]datastore.Results, 0, len(chunks))
+	for _, chunk := range chunks {
+		results, err := analyzer.PredictWithContext(ctx, [][]float32{chunk})
+		if err != nil {
+			return fmt.Errorf("inference failed on detection %d: %w", note.ID, err)
+		}
+		chunkResults = append(chunkResults, results)
+	}
+	results := mergeChunkResults(chunkResults)
+
+	if err := r.store.SaveReanalysisPredictions(ctx, job.ID, *job.ModelID, note.ID, results); err != nil {
+		return err
+	}
+	if flag := compare(job, note, results); flag != nil {
+		if err := r.jobs.SaveFlag(ctx, flag); err != nil {
+			return err
+		}
+		job.Disagreements++
+	}
+	job.Processed++
+	return nil
+}
+
+// resolveClip returns where the clip of a detection is stored. Clip names are
+// relative to the clip export path; older versions stored them with it.
+func (r *Runner) resolveClip(clipName string) (string, bool) {
+	if clipName == "" {
+		return "", false
+	}
+	candidates := []string{filepath.Join(r.clipsDir, filepath.FromSlash(clipName))}
+	if filepath.IsAbs(clipName) || r.clipsDir == "" {
+		candidates = []string{filepath.FromSlash(clipName)}
+	} else {
+		candidates = append(candidates, filepath.FromSlash(clipName))
+	}
+	for _, candidate := range candidates {
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate, true
+		}
+	}
+	return "", false
+}
+
+// readClip decodes a clip into overlapping 3 second chunks.
+func readClip(path string) ([][]float32, error) {
+	settings := &conf.Settings{}
+	settings.Input.Path = path
+	settings.BirdNET.Overlap = clipOverlap
+
+	var chunks [][]float32
+	err := myaudio.ReadAudioFileBuffered(settings, func(chunk []float32, _ bool) error {
+		if len(chunk) > 0 {
+			chunks = append(chunks, append([]float32(nil), chunk...))
+		}
+		return nil
+	})
+	return chunks, err
+}
+
+// jobFilters selects the detections of a job in ID order.
+func jobFilters(job *entities.ReanalysisJob) *datastore.AdvancedSearchFilters {
+	filters := &datastore.AdvancedSearchFilters{
+		CursorPagination: true,
+		SortAscending:    true,
+		Limit:            pageSize,
+	}
+	if job.Species != "" {
+		filters.Species = []string{job.Species}
+	}
+	if job.Source != "" {
+		filters.Location = []string{job.Source}
+	}
+	if job.StartDate != "" || job.EndDate != "" {
+		dr := &datastore.DateRange{}
+		if job.StartDate != "" {
+			dr.Start, _ = time.ParseInLocation(time.DateOnly, job.StartDate, time.Local)
+		}
+		if job.EndDate != "" {
+			dr.End, _ = time.ParseInLocation(time.DateOnly, job.EndDate, time.Local)
+		} else {
+			dr.End = time.Now()
+		}
+		filters.DateRange = dr
+	}
+	return filters
+}
+
+// rest pauses as long as the last clip took to analyze, so a job uses at
+// most half of a CPU core.
+func rest(ctx context.Context, worked time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(min(worked, maxRest)):
+	}
+}
+
+// validateJob checks the model, sensitivity, threshold and filters of a job.
+func validateJob(job *entities.ReanalysisJob) error {
+	if job.ModelPath != "" {
+		if _, err := os.Stat(job.ModelPath); err != nil {
+			return validationError(fmt.Sprintf("model file %q does not exist", job.ModelPath))
+		}
+		if job.LabelPath == "" {
+			return validationError("a custom model needs its label file")
+		}
+	}
+	if job.LabelPath != "" {
+		if job.ModelPath == "" {
+			return validationError("a label file is only used with a custom model")
+		}
+		if _, err := os.Stat(job.LabelPath); err != nil {
+			return validationError(fmt.Sprintf("label file %q does not exist", job.LabelPath))
+		}
+	}
+	if job.Sensitivity < conf.SensitivityMin || job.Sensitivity > conf.SensitivityMax {
+		return validationError(fmt.Sprintf("sensitivity must be between %g and %g", conf.SensitivityMin, conf.SensitivityMax))
+	}
+	if job.Threshold <= 0 || job.Threshold > 1 {
+		return validationError("threshold must be above 0 and at most 1")
+	}
+
+	var start, end time.Time
+	for _, d := range []struct {
+		value string
+		out   *time.Time
+	}{{job.StartDate, &start}, {job.EndDate, &end}} {
+		if d.value == "" {
+			continue
+		}
+		t, err := time.Parse(time.DateOnly, d.value)
+		if err != nil {
+			return validationError(fmt.Sprintf("date %q must be YYYY-MM-DD", d.value))
+		}
+		*d.out = t
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return validationError("end date is before start date")
+	}
+	return nil
+}
+
+func validationError(msg string) error {
+	return errors.Newf("%s", msg).
+		Component("reanalysis").
+		Category(errors.CategoryValidation).
+		Build()
+}
+
+func cmpOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,230 @@
+package reanalysis
+
+import (
+	"context"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+	"github.com/tphakala/birdnet-go/internal/datastore"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/entities"
+	"github.com/tphakala/birdnet-go/internal/datastore/v2/repository"
+	"github.com/tphakala/birdnet-go/internal/detection"
+	"github.com/tphakala/birdnet-go/internal/myaudio"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	gorm_logger "gorm.io/gorm/logger"
+)
+
+// memoryStore serves notes in ID order and records the predictions of jobs.
+type memoryStore struct {
+	mu          sync.Mutex
+	notes       []datastore.Note
+	predictions map[uint][]datastore.Results // by detection ID
+}
+
+func (m *memoryStore) SearchNotesAdvanced(filters *datastore.AdvancedSearchFilters) ([]datastore.Note, int64, error) {
+	var matching []datastore.Note
+	for i := range m.notes {
+		if m.notes[i].ID > filters.MinID {
+			matching = append(matching, m.notes[i])
+		}
+	}
+	total := int64(len(matching))
+	if len(matching) > filters.Limit {
+		matching = matching[:filters.Limit]
+	}
+	return matching, total, nil
+}
+
+func (m *memoryStore) ReanalysisModelID(_ context.Context, _ detection.ModelInfo) (uint, error) {
+	return 1, nil
+}
+
+func (m *memoryStore) SaveReanalysisPredictions(_ context.Context, _, _, detectionID uint, results []datastore.Results) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.predictions[detectionID] = results
+	return nil
+}
+
+func (m *memoryStore) saved(detectionID uint) []datastore.Results {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.predictions[detectionID]
+}
+
+// fixedAnalyzer predicts the same results for every chunk.
+type fixedAnalyzer struct {
+	results []datastore.Results
+}
+
+func (a *fixedAnalyzer) PredictWithContext(_ context.Context, _ [][]float32) ([]datastore.Results, error) {
+	return a.results, nil
+}
+
+func (a *fixedAnalyzer) Delete() {}
+
+func setupRunner(t *testing.T, notes []datastore.Note) (*Runner, *memoryStore, repository.ReanalysisRepository) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{
+		Logger: gorm_logger.Default.LogMode(gorm_logger.Silent),
+	})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+	require.NoError(t, db.AutoMigrate(&entities.ReanalysisJob{}, &entities.ReanalysisFlag{}))
+	jobs := repository.NewReanalysisRepository(db)
+
+	settings := &conf.Settings{}
+	settings.Realtime.Audio.Export.Path = t.TempDir()
+	settings.BirdNET.Sensitivity = 1
+	settings.BirdNET.Threshold = 0.8
+
+	// Six seconds of silence; the analyzer does not look at the audio
+	for _, note := range notes {
+		if note.ClipName == "" {
+			continue
+		}
+		clip := filepath.Join(settings.Realtime.Audio.Export.Path, note.ClipName)
+		require.NoError(t, myaudio.SavePCMDataToWAV(clip, make([]byte, 6*conf.SampleRate*2)))
+	}
+
+	store := &memoryStore{notes: notes, predictions: make(map[uint][]datastore.Results)}
+	analyzer := &fixedAnalyzer{results: []datastore.Results{
+		{Species: "Turdus merula_Eurasian Blackbird", Confidence: 0.9},
+		{Species: "Turdus philomelos_Song Thrush", Confidence: 0.4},
+	}}
+	newAnalyzer := func(*entities.ReanalysisJob) (Analyzer, detection.ModelInfo, error) {
+		return analyzer, detection.DefaultModelInfo(), nil
+	}
+	return NewRunner(settings, store, jobs, newAnalyzer), store, jobs
+}
+
+func waitForStatus(t *testing.T, jobs repository.ReanalysisRepository, id uint, status entities.ReanalysisStatus) *entities.ReanalysisJob {
+	t.Helper()
+	var job *entities.ReanalysisJob
+	require.Eventually(t, func() bool {
+		var err error
+		job, err = jobs.GetJob(context.Background(), id)
+		return err == nil && job.Status == status
+	}, 10*time.Second, 10*time.Millisecond, "job did not reach status %s", status)
+	return job
+}
+
+func TestRunner_RunsJobAndFlagsDisagreements(t *testing.T) {
+	notes := []datastore.Note{
+		{ID: 1, ScientificName: "Turdus merula", Confidence: 0.85, ClipName: "blackbird.wav"},
+		{ID: 2, ScientificName: "Parus major", Confidence: 0.9, ClipName: "tit.wav"},
+		{ID: 3, ScientificName: "Turdus merula", Confidence: 0.8, ClipName: "missing.wav"},
+		{ID: 4, ScientificName: "Turdus merula", Confidence: 0.8},
+	}
+	runner, store, jobs := setupRunner(t, notes[:2])
+	store.notes = notes
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		runner.Run(ctx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	job := &entities.ReanalysisJob{StartDate: "2026-01-01"}
+	require.NoError(t, runner.Submit(ctx, job))
+	assert.InDelta(t, 1.0, job.Sensitivity, 0.0001, "an unset sensitivity uses the configured one")
+	assert.InDelta(t, 0.8, job.Threshold, 0.0001, "an unset threshold uses the configured one")
+
+	finished := waitForStatus(t, jobs, job.ID, entities.ReanalysisCompleted)
+	assert.Equal(t, int64(4), finished.Total)
+	assert.Equal(t, int64(2), finished.Processed)
+	assert.Equal(t, int64(2), finished.Skipped, "detections without a readable clip are skipped")
+	assert.Equal(t, int64(1), finished.Disagreements)
+	assert.Equal(t, uint(4), finished.LastDetectionID)
+	require.NotNil(t, finished.ModelID)
+
+	require.Len(t, store.saved(1), 2)
+	assert.Equal(t, "Turdus merula_Eurasian Blackbird", store.saved(1)[0].Species)
+	assert.Empty(t, store.saved(3))
+
+	flags, total, err := jobs.ListFlags(context.Background(), job.ID, 10, 0)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	assert.Equal(t, uint(2), flags[0].DetectionID)
+	assert.Equal(t, entities.ReanalysisLabelChanged, flags[0].Reason)
+	assert.Equal(t, "Turdus merula", flags[0].TopScientificName)
+}
+
+func TestRunner_ResumesInterruptedJob(t *testing.T) {
+	notes := []datastore.Note{
+		{ID: 1, ScientificName: "Turdus merula", Confidence: 0.85, ClipName: "one.wav"},
+		{ID: 2, ScientificName: "Turdus merula", Confidence: 0.85, ClipName: "two.wav"},
+	}
+	runner, store, jobs := setupRunner(t, notes)
+	ctx := context.Background()
+
+	// A shutdown left the job running after its first detection
+	job := &entities.ReanalysisJob{Status: entities.ReanalysisPending, Sensitivity: 1, Threshold: 0.8}
+	require.NoError(t, jobs.CreateJob(ctx, job))
+	_, err := jobs.StartJob(ctx, job.ID, time.Now())
+	require.NoError(t, err)
+	job.Processed, job.LastDetectionID = 1, 1
+	require.NoError(t, jobs.UpdateJobProgress(ctx, job))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		runner.Run(runCtx)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	finished := waitForStatus(t, jobs, job.ID, entities.ReanalysisCompleted)
+	assert.Equal(t, int64(2), finished.Processed)
+	assert.Equal(t, int64(2), finished.Total)
+	assert.Empty(t, store.saved(1), "detections processed before the shutdown are not analyzed again")
+	assert.NotEmpty(t, store.saved(2))
+}
+
+func TestRunner_CancelPendingJob(t *testing.T) {
+	runner, _, jobs := setupRunner(t, nil)
+	ctx := context.Background()
+
+	job := &entities.ReanalysisJob{}
+	require.NoError(t, runner.Submit(ctx, job))
+
+	cancelled, err := runner.Cancel(ctx, job.ID)
+	require.NoError(t, err)
+	assert.True(t, cancelled)
+
+	cancelled, err = runner.Cancel(ctx, job.ID)
+	require.NoError(t, err)
+	assert.False(t, cancelled, "an ended job cannot be cancelled again")
+
+	got, err := jobs.GetJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entities.ReanalysisCancelled, got.Status)
+}
+
+func TestRunner_SubmitRejectsInvalidJob(t *testing.T) {
+	runner, _, jobs := setupRunner(t, nil)
+	ctx := context.Background()
+
+	require.Error(t, runner.Submit(ctx, &entities.ReanalysisJob{Threshold: 2}))
+
+	list, err := jobs.ListJobs(ctx, 10)
+	require.NoError(t, err)
+	assert.Empty(t, list, "an invalid job is not queued")
+}
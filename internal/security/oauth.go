@@ -687,10 +687,14 @@ func (s *OAuth2Server) IsAuthenticationEnabled(ip string) bool {
 	enabledOAuthProviders := s.Settings.GetEnabledOAuthProviders()
 	oauthEnabled := len(enabledOAuthProviders) > 0
 
-	if basicEnabled || oauthEnabled {
+	// Trusted proxy auth delegates login to the proxy but still requires an identity
+	proxyEnabled := s.Settings.Security.TrustedProxyAuth.Enabled
+
+	if basicEnabled || oauthEnabled || proxyEnabled {
 		authLog.Info("Authentication required: at least one provider enabled and IP not in allowed subnet",
 			logger.Bool("basic_enabled", basicEnabled),
 			logger.Any("oauth_providers", enabledOAuthProviders),
+			logger.Bool("trusted_proxy_enabled", proxyEnabled),
 		)
 		return true
 	}
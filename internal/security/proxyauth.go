@@ -0,0 +1,194 @@
+package security
+
+import (
+	"net"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/tphakala/birdnet-go/internal/errors"
+	"github.com/tphakala/birdnet-go/internal/logger"
+)
+
+// maxProxyHeaderValueLength caps identity header values to keep hostile or
+// misconfigured proxies from pushing arbitrarily large values into logs and sessions.
+const maxProxyHeaderValueLength = 512
+
+// ProxyAccessLevel describes what a proxy-authenticated user may do.
+type ProxyAccessLevel int
+
+const (
+	ProxyAccessNone     ProxyAccessLevel = iota // No access granted
+	ProxyAccessReadOnly                         // Safe (read-only) HTTP methods only
+	ProxyAccessFull                             // Full access, equivalent to a logged-in administrator
+)
+
+// ProxyIdentity is the identity asserted by a trusted reverse proxy.
+type ProxyIdentity struct {
+	Username string
+	Groups   []string
+	Access   ProxyAccessLevel
+}
+
+// Errors returned by AuthenticateProxyRequest.
+var (
+	// ErrProxyAuthNotApplicable means proxy auth is disabled, or the request carries
+	// no proxy identity and other authentication methods should be tried.
+	ErrProxyAuthNotApplicable = errors.NewStd("trusted proxy authentication not applicable")
+	// ErrUntrustedProxy means RequireProxy is set and the request did not arrive via a trusted proxy.
+	ErrUntrustedProxy = errors.NewStd("request did not arrive via a trusted proxy")
+	// ErrProxyUserNotAllowed means the asserted user is not in the allowed users list.
+	ErrProxyUserNotAllowed = errors.NewStd("proxy-authenticated user is not allowed")
+	// ErrProxyAccessDenied means none of the user's groups map to an access level.
+	ErrProxyAccessDenied = errors.NewStd("proxy-authenticated user has no group granting access")
+)
+
+// IsTrustedProxy reports whether remoteAddr (host or host:port) is within the
+// configured trusted proxy ranges. It must be given the TCP peer address, never
+// a value derived from X-Forwarded-For or similar client-controlled headers.
+func (s *OAuth2Server) IsTrustedProxy(remoteAddr string) bool {
+	cfg := s.Settings.Security.TrustedProxyAuth
+	if !cfg.Enabled {
+		return false
+	}
+
+	host := remoteAddr
+	if h, _, err := net.SplitHostPort(remoteAddr); err == nil {
+		host = h
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for cidr := range strings.SplitSeq(cfg.TrustedProxies, ",") {
+		trimmed := strings.TrimSpace(cidr)
+		if trimmed == "" {
+			continue
+		}
+		_, subnet, err := net.ParseCIDR(trimmed)
+		if err != nil {
+			GetLogger().Warn("Failed to parse trusted proxy CIDR", logger.String("cidr", trimmed), logger.Error(err))
+			continue
+		}
+		if subnet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// AuthenticateProxyRequest resolves the identity asserted by a trusted reverse proxy.
+// The identity header is honoured only when the TCP peer is a trusted proxy; the
+// same header from any other peer is ignored and logged as a possible spoofing attempt.
+func (s *OAuth2Server) AuthenticateProxyRequest(r *http.Request) (*ProxyIdentity, error) {
+	cfg := s.Settings.Security.TrustedProxyAuth
+	if !cfg.Enabled {
+		return nil, ErrProxyAuthNotApplicable
+	}
+
+	proxyLog := GetLogger().With(logger.String("remote_addr", r.RemoteAddr))
+	username := sanitizeProxyHeaderValue(r.Header.Get(cfg.UserHeader))
+
+	if !s.IsTrustedProxy(r.RemoteAddr) {
+		if username != "" {
+			proxyLog.Warn("Ignoring proxy identity header from untrusted peer",
+				logger.String("header", cfg.UserHeader))
+		}
+		if cfg.RequireProxy {
+			return nil, ErrUntrustedProxy
+		}
+		return nil, ErrProxyAuthNotApplicable
+	}
+
+	if username == "" {
+		proxyLog.Debug("Trusted proxy request without identity header",
+			logger.String("header", cfg.UserHeader))
+		return nil, ErrProxyAuthNotApplicable
+	}
+
+	if cfg.AllowedUsers != "" && !listContainsFold(cfg.AllowedUsers, username) {
+		proxyLog.Warn("Proxy-authenticated user not in allowed users list", logger.Username(username))
+		return nil, ErrProxyUserNotAllowed
+	}
+
+	var groups []string
+	if cfg.GroupsHeader != "" {
+		groups = parseProxyGroups(r.Header.Get(cfg.GroupsHeader))
+	}
+
+	access := resolveProxyAccess(cfg.AdminGroups, cfg.ViewerGroups, groups)
+	if access == ProxyAccessNone {
+		proxyLog.Warn("Proxy-authenticated user has no group granting access",
+			logger.Username(username),
+			logger.Int("group_count", len(groups)))
+		return nil, ErrProxyAccessDenied
+	}
+
+	proxyLog.Debug("Proxy authentication successful",
+		logger.Username(username),
+		logger.Bool("read_only", access == ProxyAccessReadOnly))
+
+	return &ProxyIdentity{
+		Username: username,
+		Groups:   groups,
+		Access:   access,
+	}, nil
+}
+
+// resolveProxyAccess maps group membership to an access level. Without any
+// group mappings every authenticated user gets full access; otherwise admin
+// groups take precedence over viewer groups.
+func resolveProxyAccess(adminGroups, viewerGroups string, groups []string) ProxyAccessLevel {
+	if strings.TrimSpace(adminGroups) == "" && strings.TrimSpace(viewerGroups) == "" {
+		return ProxyAccessFull
+	}
+
+	access := ProxyAccessNone
+	for _, group := range groups {
+		if listContainsFold(adminGroups, group) {
+			return ProxyAccessFull
+		}
+		if listContainsFold(viewerGroups, group) {
+			access = ProxyAccessReadOnly
+		}
+	}
+	return access
+}
+
+// parseProxyGroups splits a comma-separated groups header into sanitized group names.
+func parseProxyGroups(header string) []string {
+	if header == "" {
+		return nil
+	}
+	var groups []string
+	for part := range strings.SplitSeq(header, ",") {
+		if group := sanitizeProxyHeaderValue(part); group != "" {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// sanitizeProxyHeaderValue trims a header value and rejects values that are too
+// long or contain control characters.
+func sanitizeProxyHeaderValue(value string) string {
+	value = strings.TrimSpace(value)
+	if len(value) > maxProxyHeaderValueLength {
+		return ""
+	}
+	if strings.ContainsFunc(value, unicode.IsControl) {
+		return ""
+	}
+	return value
+}
+
+// listContainsFold reports whether the comma-separated list contains value, ignoring case.
+func listContainsFold(list, value string) bool {
+	for item := range strings.SplitSeq(list, ",") {
+		if strings.EqualFold(strings.TrimSpace(item), value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,185 @@
+package security
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tphakala/birdnet-go/internal/conf"
+)
+
+// newProxyAuthTestServer creates an OAuth2Server with trusted proxy auth configured.
+func newProxyAuthTestServer(cfg conf.TrustedProxyAuth) *OAuth2Server {
+	return NewOAuth2ServerForTesting(&conf.Settings{
+		Security: conf.Security{TrustedProxyAuth: cfg},
+	})
+}
+
+// TestIsTrustedProxy tests peer address matching against the trusted proxy list
+func TestIsTrustedProxy(t *testing.T) {
+	t.Parallel()
+
+	server := newProxyAuthTestServer(conf.TrustedProxyAuth{
+		Enabled:        true,
+		TrustedProxies: "172.18.0.0/16, 2001:db8::/32",
+	})
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		expected   bool
+	}{
+		{"IPv4 with port in range", "172.18.0.5:43210", true},
+		{"IPv4 without port in range", "172.18.0.5", true},
+		{"IPv4 out of range", "192.168.1.10:5555", false},
+		{"IPv6 in range", "[2001:db8::1]:8080", true},
+		{"Garbage address", "not-an-ip", false},
+		{"Empty address", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.expected, server.IsTrustedProxy(tt.remoteAddr))
+		})
+	}
+
+	disabled := newProxyAuthTestServer(conf.TrustedProxyAuth{TrustedProxies: "172.18.0.0/16"})
+	assert.False(t, disabled.IsTrustedProxy("172.18.0.5:1234"), "disabled proxy auth must trust nobody")
+}
+
+// TestAuthenticateProxyRequest tests identity extraction, allow lists, group mapping and strict mode
+func TestAuthenticateProxyRequest(t *testing.T) {
+	t.Parallel()
+
+	base := conf.TrustedProxyAuth{
+		Enabled:        true,
+		TrustedProxies: "10.0.0.0/8",
+		UserHeader:     "Remote-User",
+		GroupsHeader:   "Remote-Groups",
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(cfg *conf.TrustedProxyAuth)
+		remoteAddr string
+		headers    map[string]string
+		wantErr    error
+		wantUser   string
+		wantAccess ProxyAccessLevel
+	}{
+		{
+			name:       "trusted proxy without group mapping grants full access",
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"Remote-User": "alice"},
+			wantUser:   "alice",
+			wantAccess: ProxyAccessFull,
+		},
+		{
+			name:       "disabled returns not applicable",
+			modify:     func(cfg *conf.TrustedProxyAuth) { cfg.Enabled = false },
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"Remote-User": "alice"},
+			wantErr:    ErrProxyAuthNotApplicable,
+		},
+		{
+			name:       "untrusted peer header is ignored",
+			remoteAddr: "192.168.1.50:5000",
+			headers:    map[string]string{"Remote-User": "admin"},
+			wantErr:    ErrProxyAuthNotApplicable,
+		},
+		{
+			name:       "untrusted peer rejected in strict mode",
+			modify:     func(cfg *conf.TrustedProxyAuth) { cfg.RequireProxy = true },
+			remoteAddr: "192.168.1.50:5000",
+			wantErr:    ErrUntrustedProxy,
+		},
+		{
+			name:       "trusted proxy without identity falls through",
+			remoteAddr: "10.1.2.3:5000",
+			wantErr:    ErrProxyAuthNotApplicable,
+		},
+		{
+			name:       "user not in allow list",
+			modify:     func(cfg *conf.TrustedProxyAuth) { cfg.AllowedUsers = "alice@example.com, bob@example.com" },
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"Remote-User": "mallory@example.com"},
+			wantErr:    ErrProxyUserNotAllowed,
+		},
+		{
+			name:       "allow list match is case-insensitive",
+			modify:     func(cfg *conf.TrustedProxyAuth) { cfg.AllowedUsers = "Alice@Example.com" },
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"Remote-User": "alice@example.com"},
+			wantUser:   "alice@example.com",
+			wantAccess: ProxyAccessFull,
+		},
+		{
+			name: "admin group grants full access",
+			modify: func(cfg *conf.TrustedProxyAuth) {
+				cfg.AdminGroups = "birdnet-admins"
+				cfg.ViewerGroups = "family"
+			},
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"Remote-User": "alice", "Remote-Groups": "family, birdnet-admins"},
+			wantUser:   "alice",
+			wantAccess: ProxyAccessFull,
+		},
+		{
+			name: "viewer group grants read-only access",
+			modify: func(cfg *conf.TrustedProxyAuth) {
+				cfg.AdminGroups = "birdnet-admins"
+				cfg.ViewerGroups = "family"
+			},
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"Remote-User": "kid", "Remote-Groups": "family"},
+			wantUser:   "kid",
+			wantAccess: ProxyAccessReadOnly,
+		},
+		{
+			name:       "no matching group denies access",
+			modify:     func(cfg *conf.TrustedProxyAuth) { cfg.AdminGroups = "birdnet-admins" },
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"Remote-User": "guest", "Remote-Groups": "guests"},
+			wantErr:    ErrProxyAccessDenied,
+		},
+		{
+			name:       "control characters in identity are rejected",
+			remoteAddr: "10.1.2.3:5000",
+			headers:    map[string]string{"Remote-User": "alice\x00admin"},
+			wantErr:    ErrProxyAuthNotApplicable,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := base
+			if tt.modify != nil {
+				tt.modify(&cfg)
+			}
+			server := newProxyAuthTestServer(cfg)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v2/settings", http.NoBody)
+			req.RemoteAddr = tt.remoteAddr
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+
+			identity, err := server.AuthenticateProxyRequest(req)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				assert.Nil(t, identity)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NotNil(t, identity)
+			assert.Equal(t, tt.wantUser, identity.Username)
+			assert.Equal(t, tt.wantAccess, identity.Access)
+		})
+	}
+}
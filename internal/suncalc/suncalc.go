@@ -231,3 +231,35 @@ func (sc *SunCalc) GetSunsetTime(date time.Time) (time.Time, error) {
 	}
 	return sunEventTimes.Sunset, nil
 }
+
+// ResolveTime returns the wall-clock time of a clock or sun-relative boundary
+// (as parsed by conf.ParseQuietHoursTime) on the given day, in the day's location.
+// Clock-time boundaries do not use the receiver, so a nil SunCalc is valid for them.
+func (sc *SunCalc) ResolveTime(t conf.QuietHoursTime, day time.Time) (time.Time, error) {
+	if !t.IsSunRelative() {
+		return time.Date(day.Year(), day.Month(), day.Day(), t.Hour, t.Minute, 0, 0, day.Location()), nil
+	}
+
+	events, err := sc.GetSunEventTimes(day)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var base time.Time
+	switch t.SunEvent {
+	case conf.SunEventSunrise:
+		base = events.Sunrise
+	case conf.SunEventSunset:
+		base = events.Sunset
+	case conf.SunEventDawn:
+		base = events.CivilDawn
+	case conf.SunEventDusk:
+		base = events.CivilDusk
+	default:
+		return time.Time{}, errors.Newf("unknown sun event %q", t.SunEvent).
+			Component("suncalc").
+			Category(errors.CategoryValidation).
+			Context("operation", "resolve_time").
+			Build()
+	}
+	return base.In(day.Location()).Add(t.Offset), nil
+}